@@ -63,6 +63,9 @@ const (
 	AssetTypeADR         AssetType = "ADRC"
 	AssetTypeFRED        AssetType = "FRED"
 	AssetTypeSynthetic   AssetType = "SYNTH"
+	AssetTypeFuture      AssetType = "FUT"
+	AssetTypeOption      AssetType = "OPT"
+	AssetTypeCrypto      AssetType = "CRYPTO"
 )
 
 // Exchange identifies the primary listing exchange for an asset.
@@ -73,6 +76,11 @@ const (
 	ExchangeNASDAQ Exchange = "NASDAQ"
 	ExchangeBATS   Exchange = "BATS"
 	ExchangeFRED   Exchange = "FRED"
+	ExchangeCrypto Exchange = "CRYPTO"
+	ExchangeLSE    Exchange = "LSE"
+	ExchangeTSX    Exchange = "TSX"
+	ExchangeTSE    Exchange = "TSE"
+	ExchangeXetra  Exchange = "XETRA"
 )
 
 // NormalizeExchange maps raw exchange strings (e.g. MIC codes or alternative
@@ -87,6 +95,16 @@ func NormalizeExchange(raw string) Exchange {
 		return ExchangeBATS
 	case "FRED":
 		return ExchangeFRED
+	case "CRYPTO":
+		return ExchangeCrypto
+	case "LSE", "XLON":
+		return ExchangeLSE
+	case "TSX", "XTSE":
+		return ExchangeTSX
+	case "TSE", "XTKS", "JPX":
+		return ExchangeTSE
+	case "XETRA", "XETR":
+		return ExchangeXetra
 	default:
 		return Exchange(raw)
 	}
@@ -268,6 +286,8 @@ type Asset struct {
 	Name            string
 	AssetType       AssetType
 	PrimaryExchange Exchange
+	Currency        Currency
+	Contract        Contract
 	Sector          Sector
 	Industry        Industry
 	SICCode         int
@@ -276,6 +296,12 @@ type Asset struct {
 	Delisted        time.Time
 }
 
+// IsCrypto reports whether the asset is a cryptocurrency. Crypto assets
+// trade around the clock and in fractional quantities.
+func (a Asset) IsCrypto() bool {
+	return a.AssetType == AssetTypeCrypto
+}
+
 // EconomicIndicator is a sentinel asset for metrics not tied to a specific instrument.
 var EconomicIndicator = Asset{Ticker: "$ECONOMIC_INDICATOR"}
 
@@ -39,11 +39,45 @@ var _ = Describe("NormalizeExchange", func() {
 		Entry("NMFQS", "NMFQS", asset.ExchangeNASDAQ),
 		Entry("BATS", "BATS", asset.ExchangeBATS),
 		Entry("FRED", "FRED", asset.ExchangeFRED),
+		Entry("XLON", "XLON", asset.ExchangeLSE),
+		Entry("XTSE", "XTSE", asset.ExchangeTSX),
+		Entry("XTKS", "XTKS", asset.ExchangeTSE),
+		Entry("XETR", "XETR", asset.ExchangeXetra),
 		Entry("unknown passes through", "MYSTERY", asset.Exchange("MYSTERY")),
 		Entry("empty string passes through", "", asset.Exchange("")),
 	)
 })
 
+var _ = Describe("Currencies", func() {
+	DescribeTable("derives the quote currency from the exchange",
+		func(ex asset.Exchange, expected asset.Currency) {
+			Expect(asset.ExchangeCurrency(ex)).To(Equal(expected))
+		},
+		Entry("LSE quotes in pence", asset.ExchangeLSE, asset.CurrencyGBX),
+		Entry("MIC codes are normalized", asset.Exchange("XTSE"), asset.CurrencyCAD),
+		Entry("Tokyo", asset.ExchangeTSE, asset.CurrencyJPY),
+		Entry("Xetra", asset.ExchangeXetra, asset.CurrencyEUR),
+		Entry("US exchanges are the base currency", asset.ExchangeNYSE, asset.Currency("")),
+		Entry("unknown exchanges", asset.Exchange("MYSTERY"), asset.Currency("")),
+	)
+
+	It("relates pence to pounds", func() {
+		major, units := asset.CurrencyGBX.Major()
+		Expect(major).To(Equal(asset.CurrencyGBP))
+		Expect(units).To(Equal(100.0))
+
+		major, units = asset.CurrencyEUR.Major()
+		Expect(major).To(Equal(asset.CurrencyEUR))
+		Expect(units).To(Equal(1.0))
+	})
+
+	It("parses the GBp spelling of pence", func() {
+		Expect(asset.ParseCurrency("GBp")).To(Equal(asset.CurrencyGBX))
+		Expect(asset.ParseCurrency(" gbp ")).To(Equal(asset.CurrencyGBP))
+		Expect(asset.ParseCurrency("")).To(Equal(asset.Currency("")))
+	})
+})
+
 var _ = Describe("FRED ticker helpers", func() {
 	DescribeTable("IsFREDTicker recognizes the namespace prefix",
 		func(ticker string, expected bool) {
@@ -0,0 +1,243 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asset
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// OptionRight identifies an option as a call or a put.
+type OptionRight string
+
+const (
+	Call OptionRight = "C"
+	Put  OptionRight = "P"
+)
+
+// DefaultOptionMultiplier is the number of shares a listed US equity
+// option controls.
+const DefaultOptionMultiplier = 100
+
+// Contract holds the specification of a derivative contract. It is the
+// zero value for cash instruments. Margin amounts are per contract and in
+// the asset's quote currency.
+type Contract struct {
+	// Root is the product symbol shared by every expiry, for example
+	// "ES" for the E-mini S&P 500 or "SPY" for SPY options.
+	Root string
+
+	// Underlying and UnderlyingFigi identify an option's underlying
+	// asset by ticker and CompositeFigi.
+	Underlying     string
+	UnderlyingFigi string
+
+	// Strike is an option's exercise price.
+	Strike float64
+
+	// Right is Call or Put for options and empty otherwise.
+	Right OptionRight
+
+	// Multiplier is the number of units of the underlying one contract
+	// controls: a one-point move changes a contract's value by this
+	// many units of currency.
+	Multiplier float64
+
+	// TickSize is the minimum price increment. Zero means any price.
+	TickSize float64
+
+	// Expiry is the last trading day of the contract.
+	Expiry time.Time
+
+	// InitialMargin is the margin required to open a contract.
+	InitialMargin float64
+
+	// MaintenanceMargin is the margin required to keep a contract open.
+	// Zero means the same as InitialMargin.
+	MaintenanceMargin float64
+	// CashSettled marks an option that settles in cash at its intrinsic
+	// value rather than by delivering the underlying, as index options
+	// do.
+	CashSettled bool
+}
+
+// NewFuture constructs a futures contract asset. The ticker doubles as
+// the CompositeFigi, since futures do not carry FIGIs in pvdb.
+func NewFuture(ticker string, spec Contract) Asset {
+	return Asset{
+		Ticker:        ticker,
+		CompositeFigi: ticker,
+		AssetType:     AssetTypeFuture,
+		Contract:      spec,
+	}
+}
+
+// NewOption constructs a listed option on underlying. The ticker and
+// CompositeFigi are the OCC option symbol without padding, for example
+// SPY240621C00500000. The multiplier defaults to DefaultOptionMultiplier
+// when multiplier is zero.
+func NewOption(underlying Asset, right OptionRight, strike float64, expiry time.Time, multiplier float64) Asset {
+	if multiplier == 0 {
+		multiplier = DefaultOptionMultiplier
+	}
+
+	ticker := OCCSymbol(underlying.Ticker, right, strike, expiry)
+
+	return Asset{
+		Ticker:        ticker,
+		CompositeFigi: ticker,
+		AssetType:     AssetTypeOption,
+		Currency:      underlying.Currency,
+		Contract: Contract{
+			Root:           underlying.Ticker,
+			Underlying:     underlying.Ticker,
+			UnderlyingFigi: underlying.CompositeFigi,
+			Strike:         strike,
+			Right:          right,
+			Multiplier:     multiplier,
+			Expiry:         time.Date(expiry.Year(), expiry.Month(), expiry.Day(), 0, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+// NewIndexOption constructs a cash-settled option on an index. It is
+// otherwise the same as NewOption.
+func NewIndexOption(index Asset, right OptionRight, strike float64, expiry time.Time, multiplier float64) Asset {
+	option := NewOption(index, right, strike, expiry, multiplier)
+	option.Contract.CashSettled = true
+
+	return option
+}
+
+// OCCSymbol formats an option symbol in the OCC convention: root,
+// expiry as YYMMDD, right, and the strike in thousandths padded to
+// eight digits.
+func OCCSymbol(root string, right OptionRight, strike float64, expiry time.Time) string {
+	return fmt.Sprintf("%s%s%s%08d", strings.ToUpper(root), expiry.Format("060102"), right,
+		int64(math.Round(strike*1000)))
+}
+
+// ParseOCCSymbol parses an OCC option symbol, with or without the space
+// padding after the root, into an option asset with the default
+// multiplier. The underlying is identified by ticker only. ok is false
+// when symbol is not an option symbol.
+func ParseOCCSymbol(symbol string) (option Asset, ok bool) {
+	const suffixLen = 15 // YYMMDD, right, and eight strike digits
+
+	if len(symbol) <= suffixLen {
+		return Asset{}, false
+	}
+
+	root := strings.TrimSpace(symbol[:len(symbol)-suffixLen])
+	suffix := symbol[len(symbol)-suffixLen:]
+
+	expiry, err := time.Parse("060102", suffix[:6])
+	if err != nil || root == "" || strings.ContainsAny(root, " ") {
+		return Asset{}, false
+	}
+
+	right := OptionRight(suffix[6:7])
+	if right != Call && right != Put {
+		return Asset{}, false
+	}
+
+	var strike int64
+	for _, digit := range suffix[7:] {
+		if digit < '0' || digit > '9' {
+			return Asset{}, false
+		}
+
+		strike = strike*10 + int64(digit-'0')
+	}
+
+	return NewOption(Asset{Ticker: root}, right, float64(strike)/1000, expiry, 0), true
+}
+
+// IsOption reports whether the asset is an option contract.
+func (a Asset) IsOption() bool {
+	return a.AssetType == AssetTypeOption
+}
+
+// UnderlyingAsset returns the asset an option is written on, identified
+// by ticker and CompositeFigi only.
+func (c Contract) UnderlyingAsset() Asset {
+	return Asset{Ticker: c.Underlying, CompositeFigi: c.UnderlyingFigi}
+}
+
+// Intrinsic returns an option's exercise value per unit of the
+// underlying at underlyingPrice: the amount by which a call's strike is
+// below the price or a put's strike is above it, and zero otherwise.
+func (c Contract) Intrinsic(underlyingPrice float64) float64 {
+	switch c.Right {
+	case Call:
+		return math.Max(underlyingPrice-c.Strike, 0)
+	case Put:
+		return math.Max(c.Strike-underlyingPrice, 0)
+	default:
+		return 0
+	}
+}
+
+// IsFuture reports whether the asset is a futures contract.
+func (a Asset) IsFuture() bool {
+	return a.AssetType == AssetTypeFuture
+}
+
+// ContractMultiplier returns the asset's contract multiplier, or 1 for
+// assets without one.
+func (a Asset) ContractMultiplier() float64 {
+	if a.Contract.Multiplier == 0 {
+		return 1
+	}
+
+	return a.Contract.Multiplier
+}
+
+// ExpiresBy reports whether the contract's last trading day is on or
+// before the calendar day of date. Contracts without an expiry never
+// expire.
+func (c Contract) ExpiresBy(date time.Time) bool {
+	if c.Expiry.IsZero() {
+		return false
+	}
+
+	expiryYear, expiryMonth, expiryDay := c.Expiry.Date()
+	year, month, day := date.Date()
+
+	return !time.Date(year, month, day, 0, 0, 0, 0, time.UTC).
+		Before(time.Date(expiryYear, expiryMonth, expiryDay, 0, 0, 0, 0, time.UTC))
+}
+
+// MaintenanceRequirement returns the maintenance margin per contract.
+func (c Contract) MaintenanceRequirement() float64 {
+	if c.MaintenanceMargin == 0 {
+		return c.InitialMargin
+	}
+
+	return c.MaintenanceMargin
+}
+
+// RoundToTick rounds price to the nearest multiple of TickSize. Prices
+// are returned unchanged when TickSize is zero.
+func (c Contract) RoundToTick(price float64) float64 {
+	if c.TickSize <= 0 {
+		return price
+	}
+
+	return math.Round(price/c.TickSize) * c.TickSize
+}
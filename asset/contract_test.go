@@ -0,0 +1,74 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asset_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+)
+
+var _ = Describe("Option contracts", func() {
+	spy := asset.Asset{CompositeFigi: "BBG000BDTBL9", Ticker: "SPY"}
+	expiry := time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC)
+
+	It("names options by their OCC symbol", func() {
+		call := asset.NewOption(spy, asset.Call, 500, expiry, 0)
+
+		Expect(call.Ticker).To(Equal("SPY240621C00500000"))
+		Expect(call.CompositeFigi).To(Equal(call.Ticker))
+		Expect(call.IsOption()).To(BeTrue())
+		Expect(call.ContractMultiplier()).To(Equal(100.0))
+		Expect(call.Contract.UnderlyingAsset()).To(Equal(spy))
+
+		put := asset.NewOption(spy, asset.Put, 432.5, expiry, 10)
+		Expect(put.Ticker).To(Equal("SPY240621P00432500"))
+		Expect(put.ContractMultiplier()).To(Equal(10.0))
+	})
+
+	DescribeTable("parses OCC symbols",
+		func(symbol string, ok bool, root string, right asset.OptionRight, strike float64) {
+			option, parsed := asset.ParseOCCSymbol(symbol)
+			Expect(parsed).To(Equal(ok))
+
+			if ok {
+				Expect(option.Contract.Underlying).To(Equal(root))
+				Expect(option.Contract.Right).To(Equal(right))
+				Expect(option.Contract.Strike).To(Equal(strike))
+				Expect(option.Contract.Expiry).To(Equal(expiry))
+			}
+		},
+		Entry("compact", "SPY240621C00500000", true, "SPY", asset.Call, 500.0),
+		Entry("padded", "SPY   240621P00432500", true, "SPY", asset.Put, 432.5),
+		Entry("equity ticker", "SPY", false, "", asset.OptionRight(""), 0.0),
+		Entry("bad right", "SPY240621X00500000", false, "", asset.OptionRight(""), 0.0),
+		Entry("bad strike", "SPY240621C0050000A", false, "", asset.OptionRight(""), 0.0),
+	)
+
+	DescribeTable("computes intrinsic value",
+		func(right asset.OptionRight, spot, expected float64) {
+			spec := asset.Contract{Right: right, Strike: 100}
+			Expect(spec.Intrinsic(spot)).To(Equal(expected))
+		},
+		Entry("call in the money", asset.Call, 110.0, 10.0),
+		Entry("call out of the money", asset.Call, 90.0, 0.0),
+		Entry("put in the money", asset.Put, 90.0, 10.0),
+		Entry("put out of the money", asset.Put, 110.0, 0.0),
+	)
+})
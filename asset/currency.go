@@ -0,0 +1,102 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asset
+
+import "strings"
+
+// Currency is an ISO 4217 currency code such as "USD" or "EUR".
+type Currency string
+
+const (
+	CurrencyUSD Currency = "USD"
+	CurrencyEUR Currency = "EUR"
+	CurrencyGBP Currency = "GBP"
+	CurrencyJPY Currency = "JPY"
+	CurrencyCAD Currency = "CAD"
+	CurrencyCHF Currency = "CHF"
+	CurrencyAUD Currency = "AUD"
+	CurrencyNZD Currency = "NZD"
+	CurrencyCNY Currency = "CNY"
+	CurrencyHKD Currency = "HKD"
+	CurrencySEK Currency = "SEK"
+	CurrencyMXN Currency = "MXN"
+
+	// CurrencyGBX is pence sterling, one hundredth of a pound. London
+	// Stock Exchange listings are quoted in it.
+	CurrencyGBX Currency = "GBX"
+)
+
+// minorUnits maps currencies quoted as a fraction of another currency to
+// that currency and the number of minor units in one of it.
+var minorUnits = map[Currency]struct {
+	major Currency
+	units float64
+}{
+	CurrencyGBX: {major: CurrencyGBP, units: 100},
+}
+
+// exchangeCurrencies maps exchanges outside the US to the currency their
+// listings are quoted in.
+var exchangeCurrencies = map[Exchange]Currency{
+	ExchangeLSE:   CurrencyGBX,
+	ExchangeTSX:   CurrencyCAD,
+	ExchangeTSE:   CurrencyJPY,
+	ExchangeXetra: CurrencyEUR,
+}
+
+// BaseCurrency is the currency portfolios are valued in. Cash deposits,
+// withdrawals, and the equity curve are all in the base currency.
+const BaseCurrency = CurrencyUSD
+
+// QuoteCurrency returns the currency the asset's prices are quoted in.
+// Assets without a Currency are quoted in BaseCurrency.
+func (a Asset) QuoteCurrency() Currency {
+	if a.Currency == "" {
+		return BaseCurrency
+	}
+
+	return a.Currency
+}
+
+// Major returns the currency c is a fraction of and the number of units
+// of c in one unit of it: GBP and 100 for GBX. Every other currency is its
+// own major unit.
+func (c Currency) Major() (Currency, float64) {
+	if minor, ok := minorUnits[c]; ok {
+		return minor.major, minor.units
+	}
+
+	return c, 1
+}
+
+// ParseCurrency maps a currency code from provider metadata to a
+// Currency. Codes are case-insensitive except for the "GBp" spelling of
+// pence sterling, which is read as GBX rather than GBP.
+func ParseCurrency(raw string) Currency {
+	raw = strings.TrimSpace(raw)
+	if raw == "GBp" {
+		return CurrencyGBX
+	}
+
+	return Currency(strings.ToUpper(raw))
+}
+
+// ExchangeCurrency returns the currency listings on ex are quoted in. It
+// is empty for US exchanges and exchanges it does not know, matching the
+// convention that assets quoted in BaseCurrency leave Currency empty.
+func ExchangeCurrency(ex Exchange) Currency {
+	return exchangeCurrencies[NormalizeExchange(string(ex))]
+}
@@ -24,10 +24,31 @@
 // Strategies use the metadata to filter assets -- for example, excluding
 // financial-sector stocks or limiting to common stock only.
 //
+// Currency is the [Currency] the asset's prices are quoted in. It is empty
+// for assets quoted in [BaseCurrency] (USD); [Asset.QuoteCurrency] returns
+// the effective currency either way. Portfolios convert foreign-currency
+// prices to the base currency when valuing holdings. Providers fill
+// Currency from their metadata or, failing that, from the primary
+// exchange ([ExchangeCurrency]). London listings are quoted in pence
+// ([CurrencyGBX]), which [Currency.Major] relates to pounds.
+//
+// Futures contracts have AssetType [AssetTypeFuture] and carry their
+// [Contract] specification: root symbol, multiplier, tick size, expiry,
+// and per-contract initial and maintenance margin. [NewFuture] builds one.
+// Listed options have AssetType [AssetTypeOption]; their Contract adds the
+// underlying, strike, and [OptionRight]. [NewOption] builds one named by
+// its OCC symbol. Cash instruments leave Contract at its zero value, and
+// [Asset.ContractMultiplier] returns 1 for them.
+//
+// Cryptocurrencies have AssetType [AssetTypeCrypto] and usually list on
+// [ExchangeCrypto]. [Asset.IsCrypto] identifies them; the simulated
+// broker fills their dollar-amount orders in fractional units.
+//
 // The AssetType, Exchange, Sector, and Industry fields are string-typed
 // enums with named constants (e.g. [AssetTypeETF], [ExchangeNYSE],
 // [SectorTechnology], [IndustryBiotechnology]). Raw exchange codes from
-// the database are normalized via [NormalizeExchange].
+// the database are normalized via [NormalizeExchange]. The engine picks an
+// asset's trading calendar from its Exchange.
 //
 // # Economic Indicators
 //
@@ -45,8 +45,17 @@ const (
 	// InterestTransaction records interest earned or charged.
 	InterestTransaction
 
-	// JournalTransaction records an internal transfer between accounts.
+	// JournalTransaction records an internal transfer between accounts or a
+	// bookkeeping adjustment, such as a broker reconciliation correction.
 	JournalTransaction
+
+	// FXTransaction records one leg of a currency conversion or the cash
+	// settlement of a currency hedge.
+	FXTransaction
+
+	// VariationMarginTransaction records the daily cash settlement of a
+	// futures position's gain or loss since its previous settlement.
+	VariationMarginTransaction
 )
 
 // String returns a human-readable name for the transaction type.
@@ -70,6 +79,10 @@ func (tt TransactionType) String() string {
 		return "Interest"
 	case JournalTransaction:
 		return "Journal"
+	case FXTransaction:
+		return "FX"
+	case VariationMarginTransaction:
+		return "VariationMargin"
 	default:
 		return fmt.Sprintf("TransactionType(%d)", int(tt))
 	}
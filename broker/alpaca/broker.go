@@ -32,16 +32,23 @@ const (
 	productionWSURL   = "wss://api.alpaca.markets/stream"
 	paperWSURL        = "wss://paper-api.alpaca.markets/stream"
 	fillChannelSize   = 1024
+
+	// fillReplayLookback bounds how far before the replay cutoff FillsSince
+	// looks for submitted orders. Alpaca filters its order listing on
+	// submission time, so an order placed before the cutoff and filled
+	// after it is only found if the query window reaches back far enough.
+	fillReplayLookback = 7 * 24 * time.Hour
 )
 
-// AlpacaBroker implements broker.Broker and broker.GroupSubmitter for the
-// Alpaca brokerage.
+// AlpacaBroker implements broker.Broker, broker.GroupSubmitter and
+// broker.FillReplayer for the Alpaca brokerage.
 type AlpacaBroker struct {
 	client          *apiClient
 	streamer        *fillStreamer
 	fills           chan broker.Fill
 	paper           bool
 	fractional      bool
+	getenv          func(string) string
 	submittedOrders map[string]broker.Order
 	mu              sync.Mutex
 }
@@ -68,6 +75,7 @@ func WithFractionalShares() Option {
 func New(opts ...Option) *AlpacaBroker {
 	alpacaBroker := &AlpacaBroker{
 		fills:           make(chan broker.Fill, fillChannelSize),
+		getenv:          os.Getenv,
 		submittedOrders: make(map[string]broker.Order),
 	}
 
@@ -81,8 +89,8 @@ func New(opts ...Option) *AlpacaBroker {
 // Connect establishes a session with Alpaca by reading credentials from
 // environment variables and validating the account.
 func (alpacaBroker *AlpacaBroker) Connect(ctx context.Context) error {
-	apiKey := os.Getenv("ALPACA_API_KEY")
-	apiSecret := os.Getenv("ALPACA_API_SECRET")
+	apiKey := alpacaBroker.getenv("ALPACA_API_KEY")
+	apiSecret := alpacaBroker.getenv("ALPACA_API_SECRET")
 
 	if apiKey == "" || apiSecret == "" {
 		return broker.ErrMissingCredentials
@@ -150,6 +158,11 @@ func (alpacaBroker *AlpacaBroker) Fills() <-chan broker.Fill {
 // are converted to share quantities or sent as notional when fractional
 // shares are enabled.
 func (alpacaBroker *AlpacaBroker) Submit(ctx context.Context, order broker.Order) error {
+	// Alpaca supports trailing stops but not trailing stop-limits.
+	if order.OrderType == broker.TrailingStopLimit {
+		return fmt.Errorf("alpaca: trailing stop-limit: %w", broker.ErrUnsupportedOrder)
+	}
+
 	if order.Qty == 0 && order.Amount > 0 {
 		if alpacaBroker.fractional {
 			alpacaOrder := toAlpacaOrder(order, true)
@@ -316,12 +329,48 @@ func (alpacaBroker *AlpacaBroker) Transactions(_ context.Context, _ time.Time) (
 	return nil, nil
 }
 
+// FillsSince reports orders with fills executed after since. Each Fill
+// carries the order's cumulative filled quantity and average price.
+func (alpacaBroker *AlpacaBroker) FillsSince(ctx context.Context, since time.Time) ([]broker.Fill, error) {
+	responses, err := alpacaBroker.client.getOrdersSince(ctx, since.Add(-fillReplayLookback))
+	if err != nil {
+		return nil, fmt.Errorf("alpaca: fills since: %w", err)
+	}
+
+	var fills []broker.Fill
+
+	for _, resp := range responses {
+		filledQty := parseFloat(resp.FilledQty)
+		if filledQty <= 0 {
+			continue
+		}
+
+		filledAt, parseErr := time.Parse(time.RFC3339, resp.FilledAt)
+		if parseErr != nil || filledAt.Before(since) {
+			continue
+		}
+
+		fills = append(fills, broker.Fill{
+			OrderID:  resp.ID,
+			Price:    parseFloat(resp.FilledAvgPrice),
+			Qty:      filledQty,
+			FilledAt: filledAt,
+		})
+	}
+
+	return fills, nil
+}
+
 // SubmitGroup submits a group of orders as a native Alpaca bracket or OCO order.
 func (alpacaBroker *AlpacaBroker) SubmitGroup(ctx context.Context, orders []broker.Order, groupType broker.GroupType) error {
 	if len(orders) == 0 {
 		return broker.ErrEmptyOrderGroup
 	}
 
+	if err := broker.CheckGroupLegs(orders); err != nil {
+		return fmt.Errorf("alpaca: %w", err)
+	}
+
 	switch groupType {
 	case broker.GroupBracket:
 		return alpacaBroker.submitBracket(ctx, orders)
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"sync/atomic"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -19,6 +20,7 @@ import (
 // Compile-time interface checks.
 var _ broker.Broker = (*alpaca.AlpacaBroker)(nil)
 var _ broker.GroupSubmitter = (*alpaca.AlpacaBroker)(nil)
+var _ broker.FillReplayer = (*alpaca.AlpacaBroker)(nil)
 
 var _ = Describe("AlpacaBroker", func() {
 	var (
@@ -137,6 +139,38 @@ var _ = Describe("AlpacaBroker", func() {
 			err := alpacaBroker.Connect(ctx)
 			Expect(err).To(MatchError(alpaca.ErrAccountNotActive))
 		})
+
+		It("reads credentials from the config section when env vars are not set", func() {
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /v2/account", func(writer http.ResponseWriter, req *http.Request) {
+				writer.Header().Set("Content-Type", "application/json")
+				sonic.ConfigDefault.NewEncoder(writer).Encode(map[string]any{
+					"id":     "acct-001",
+					"status": "SUSPENDED",
+					"cash":   "0",
+					"equity": "0",
+				})
+			})
+
+			server := httptest.NewServer(mux)
+			DeferCleanup(server.Close)
+
+			GinkgoT().Setenv("ALPACA_API_KEY", "")
+			GinkgoT().Setenv("ALPACA_API_SECRET", "")
+
+			created, err := alpaca.NewFromConfig(broker.Config{
+				Paper:    true,
+				Settings: map[string]string{"api_key": "config-key", "api_secret": "config-secret"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			alpacaBroker := created.(*alpaca.AlpacaBroker)
+			alpaca.SetClientForTest(alpacaBroker, server.URL, "config-key", "config-secret")
+
+			// Getting past the credential check to the account status
+			// check shows the config-supplied keys were used.
+			Expect(alpacaBroker.Connect(ctx)).To(MatchError(alpaca.ErrAccountNotActive))
+		})
 	})
 
 	Describe("Submit", Label("orders"), func() {
@@ -514,6 +548,57 @@ var _ = Describe("AlpacaBroker", func() {
 		})
 	})
 
+	Describe("FillsSince", func() {
+		It("reports orders filled after the cutoff with cumulative quantity", func() {
+			cutoff := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+
+			var afterParam string
+
+			alpacaBroker := authenticatedBroker(func(mux *http.ServeMux) {
+				mux.HandleFunc("GET /v2/orders", func(writer http.ResponseWriter, req *http.Request) {
+					afterParam = req.URL.Query().Get("after")
+
+					writer.Header().Set("Content-Type", "application/json")
+					sonic.ConfigDefault.NewEncoder(writer).Encode([]map[string]any{
+						{
+							"id":               "ORD-LATE",
+							"status":           "filled",
+							"symbol":           "AAPL",
+							"filled_qty":       "10",
+							"filled_avg_price": "187.25",
+							"filled_at":        "2026-03-10T15:30:00Z",
+						},
+						{
+							"id":               "ORD-EARLY",
+							"status":           "filled",
+							"symbol":           "MSFT",
+							"filled_qty":       "5",
+							"filled_avg_price": "410",
+							"filled_at":        "2026-03-10T13:00:00Z",
+						},
+						{
+							"id":         "ORD-OPEN",
+							"status":     "new",
+							"symbol":     "GOOG",
+							"filled_qty": "0",
+						},
+					})
+				})
+			})
+
+			fills, err := alpacaBroker.FillsSince(ctx, cutoff)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fills).To(HaveLen(1))
+			Expect(fills[0].OrderID).To(Equal("ORD-LATE"))
+			Expect(fills[0].Qty).To(Equal(10.0))
+			Expect(fills[0].Price).To(Equal(187.25))
+
+			queriedAfter, parseErr := time.Parse(time.RFC3339, afterParam)
+			Expect(parseErr).ToNot(HaveOccurred())
+			Expect(queriedAfter.Before(cutoff)).To(BeTrue())
+		})
+	})
+
 	Describe("SubmitGroup", Label("orders"), func() {
 		It("submits a bracket order with correct order_class and legs", func() {
 			var receivedBody map[string]any
@@ -582,6 +667,15 @@ var _ = Describe("AlpacaBroker", func() {
 			Expect(err).To(MatchError(alpaca.ErrEmptyOrderGroup))
 		})
 
+		It("rejects a trailing bracket exit with ErrUnsupportedOrder", func() {
+			alpacaBroker := authenticatedBroker(nil)
+			err := alpacaBroker.SubmitGroup(ctx, []broker.Order{
+				{Asset: asset.Asset{Ticker: "AAPL"}, Side: broker.Sell, Qty: 10, OrderType: broker.TrailingStop, TrailAmount: 2.0, StopPrice: 148.0, TimeInForce: broker.GTC, GroupRole: broker.RoleStopLoss},
+				{Asset: asset.Asset{Ticker: "AAPL"}, Side: broker.Sell, Qty: 10, OrderType: broker.Limit, LimitPrice: 160.0, TimeInForce: broker.GTC, GroupRole: broker.RoleTakeProfit},
+			}, broker.GroupOCO)
+			Expect(err).To(MatchError(broker.ErrUnsupportedOrder))
+		})
+
 		It("returns ErrNoEntryOrder when bracket has no entry", func() {
 			alpacaBroker := authenticatedBroker(nil)
 			err := alpacaBroker.SubmitGroup(ctx, []broker.Order{
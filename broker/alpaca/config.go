@@ -0,0 +1,49 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alpaca
+
+import "github.com/penny-vault/pvbt/broker"
+
+func init() {
+	broker.Register("alpaca", NewFromConfig)
+}
+
+// NewFromConfig creates an AlpacaBroker from a `[broker.alpaca]` config
+// section. Recognized settings are api_key and api_secret (overriding
+// ALPACA_API_KEY and ALPACA_API_SECRET) and fractional. Paper selects
+// the paper-trading environment. Alpaca keys are issued per account, so
+// Account is not used.
+func NewFromConfig(cfg broker.Config) (broker.Broker, error) {
+	fractional, err := cfg.Bool("fractional")
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []Option
+
+	if cfg.Paper {
+		opts = append(opts, WithPaper())
+	}
+
+	if fractional {
+		opts = append(opts, WithFractionalShares())
+	}
+
+	alpacaBroker := New(opts...)
+	alpacaBroker.getenv = cfg.Getenv
+
+	return alpacaBroker, nil
+}
@@ -35,6 +35,8 @@ type orderRequest struct {
 	TimeInForce   string             `json:"time_in_force"`
 	LimitPrice    string             `json:"limit_price,omitempty"`
 	StopPrice     string             `json:"stop_price,omitempty"`
+	TrailPrice    string             `json:"trail_price,omitempty"`
+	TrailPercent  string             `json:"trail_percent,omitempty"`
 	ExpireTime    string             `json:"expire_time,omitempty"`
 	ClientOrderID string             `json:"client_order_id"`
 	OrderClass    string             `json:"order_class,omitempty"`
@@ -69,8 +71,11 @@ type orderResponse struct {
 	Qty            string          `json:"qty"`
 	FilledQty      string          `json:"filled_qty"`
 	FilledAvgPrice string          `json:"filled_avg_price"`
+	FilledAt       string          `json:"filled_at"`
 	LimitPrice     string          `json:"limit_price"`
 	StopPrice      string          `json:"stop_price"`
+	TrailPrice     string          `json:"trail_price"`
+	TrailPercent   string          `json:"trail_percent"`
 	TimeInForce    string          `json:"time_in_force"`
 	OrderClass     string          `json:"order_class"`
 	Legs           []orderResponse `json:"legs"`
@@ -177,6 +182,15 @@ func toAlpacaOrder(order broker.Order, fractional bool) orderRequest {
 		request.StopPrice = formatFloat(order.StopPrice)
 	}
 
+	// Trailing stops carry the trail instead of a stop price.
+	if order.OrderType == broker.TrailingStop {
+		if order.TrailPercent > 0 {
+			request.TrailPercent = formatFloat(order.TrailPercent)
+		} else {
+			request.TrailPrice = formatFloat(order.TrailAmount)
+		}
+	}
+
 	// Set expire time for GTD orders. Convert to UTC first: formatting a
 	// local wall-clock time with a literal 'Z' would mislabel it as UTC.
 	if order.TimeInForce == broker.GTD {
@@ -195,14 +209,16 @@ func toAlpacaOrder(order broker.Order, fractional bool) orderRequest {
 
 func toBrokerOrder(resp orderResponse) broker.Order {
 	return broker.Order{
-		ID:         resp.ID,
-		Asset:      asset.Asset{Ticker: resp.Symbol},
-		Side:       mapAlpacaSide(resp.Side),
-		Status:     mapAlpacaStatus(resp.Status),
-		Qty:        parseFloat(resp.Qty),
-		OrderType:  mapAlpacaOrderType(resp.Type),
-		LimitPrice: parseFloat(resp.LimitPrice),
-		StopPrice:  parseFloat(resp.StopPrice),
+		ID:           resp.ID,
+		Asset:        asset.Asset{Ticker: resp.Symbol},
+		Side:         mapAlpacaSide(resp.Side),
+		Status:       mapAlpacaStatus(resp.Status),
+		Qty:          parseFloat(resp.Qty),
+		OrderType:    mapAlpacaOrderType(resp.Type),
+		LimitPrice:   parseFloat(resp.LimitPrice),
+		StopPrice:    parseFloat(resp.StopPrice),
+		TrailAmount:  parseFloat(resp.TrailPrice),
+		TrailPercent: parseFloat(resp.TrailPercent),
 	}
 }
 
@@ -248,6 +264,8 @@ func mapOrderType(orderType broker.OrderType) string {
 		return "stop"
 	case broker.StopLimit:
 		return "stop_limit"
+	case broker.TrailingStop:
+		return "trailing_stop"
 	default:
 		return "market"
 	}
@@ -301,6 +319,8 @@ func mapAlpacaOrderType(orderType string) broker.OrderType {
 		return broker.Stop
 	case "stop_limit":
 		return broker.StopLimit
+	case "trailing_stop":
+		return broker.TrailingStop
 	default:
 		return broker.Market
 	}
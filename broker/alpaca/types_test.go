@@ -89,6 +89,41 @@ var _ = Describe("Types", Label("translation"), func() {
 			Expect(result.LimitPrice).To(Equal("155"))
 		})
 
+		It("translates a trailing stop by amount", func() {
+			order := broker.Order{
+				Asset:       asset.Asset{Ticker: "AAPL"},
+				Side:        broker.Sell,
+				Qty:         10,
+				OrderType:   broker.TrailingStop,
+				TrailAmount: 2.5,
+				TimeInForce: broker.GTC,
+			}
+
+			result := toAlpacaOrder(order, false)
+
+			Expect(result.Type).To(Equal("trailing_stop"))
+			Expect(result.TrailPrice).To(Equal("2.5"))
+			Expect(result.TrailPercent).To(BeEmpty())
+			Expect(result.StopPrice).To(BeEmpty())
+		})
+
+		It("translates a trailing stop by percent", func() {
+			order := broker.Order{
+				Asset:        asset.Asset{Ticker: "AAPL"},
+				Side:         broker.Sell,
+				Qty:          10,
+				OrderType:    broker.TrailingStop,
+				TrailPercent: 5,
+				TimeInForce:  broker.GTC,
+			}
+
+			result := toAlpacaOrder(order, false)
+
+			Expect(result.Type).To(Equal("trailing_stop"))
+			Expect(result.TrailPercent).To(Equal("5"))
+			Expect(result.TrailPrice).To(BeEmpty())
+		})
+
 		It("sets expire_time for GTD orders", func() {
 			expiry := time.Date(2026, 4, 15, 16, 0, 0, 0, time.UTC)
 			order := broker.Order{
@@ -282,6 +317,7 @@ var _ = Describe("Types", Label("translation"), func() {
 				{"limit", broker.Limit},
 				{"stop", broker.Stop},
 				{"stop_limit", broker.StopLimit},
+				{"trailing_stop", broker.TrailingStop},
 			} {
 				resp := orderResponse{Type: testCase.alpacaType}
 				result := toBrokerOrder(resp)
@@ -17,6 +17,7 @@ package broker
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/penny-vault/pvbt/asset"
@@ -116,6 +117,28 @@ type Order struct {
 	// Zero means the order did not originate from a portfolio.Batch
 	// (e.g., broker-internal housekeeping orders).
 	BatchID int
+	// TrailAmount and TrailPercent set how far a TrailingStop or
+	// TrailingStopLimit order's stop follows the best price since
+	// submission: a dollar amount, or a percent of price (5 means 5%).
+	// Exactly one is set. StopPrice holds the current stop once known.
+	TrailAmount  float64
+	TrailPercent float64
+	// LimitOffset places a TrailingStopLimit order's limit price this many
+	// dollars beyond its stop: below the stop for sells, above for buys.
+	LimitOffset float64
+	// Fractional asks the broker to fill a dollar-amount order with
+	// fractional shares so the whole Amount is invested. Brokers that
+	// trade only whole shares, or that configure fractional trading per
+	// account, ignore it.
+	Fractional bool
+	// ExecAlgo works the order as a series of child orders instead of
+	// sending it whole. ExecTWAP and ExecVWAP spread it across
+	// ExecSlices slices over ExecDuration; ExecPOV trades
+	// ExecParticipation percent of each minute's volume until filled.
+	ExecAlgo          ExecAlgo
+	ExecDuration      time.Duration
+	ExecSlices        int
+	ExecParticipation float64
 }
 
 // OrderType identifies the price behavior of an order.
@@ -126,8 +149,54 @@ const (
 	Limit
 	Stop
 	StopLimit
+	TrailingStop
+	TrailingStopLimit
 )
 
+// IsTrailing reports whether the order type's stop follows the price.
+func (ot OrderType) IsTrailing() bool {
+	return ot == TrailingStop || ot == TrailingStopLimit
+}
+
+// TrailStop returns the stop price that trails reference by the order's
+// TrailAmount or TrailPercent: below reference for sells and above it for
+// buys.
+func TrailStop(order Order, reference float64) float64 {
+	distance := order.TrailAmount
+	if order.TrailPercent != 0 {
+		distance = reference * order.TrailPercent / 100
+	}
+
+	if order.Side == Sell {
+		return reference - distance
+	}
+
+	return reference + distance
+}
+
+// TrailLimit returns the limit price of a TrailingStopLimit order whose
+// stop is at stop.
+func TrailLimit(order Order, stop float64) float64 {
+	if order.Side == Sell {
+		return stop - order.LimitOffset
+	}
+
+	return stop + order.LimitOffset
+}
+
+// CheckGroupLegs returns ErrUnsupportedOrder if any leg of an order group
+// trails. Contingent groups carry fixed stop prices; a trailing leg, such
+// as the stop of a TrailingStopLoss bracket, has no group form.
+func CheckGroupLegs(orders []Order) error {
+	for _, order := range orders {
+		if order.OrderType.IsTrailing() {
+			return fmt.Errorf("trailing stop in order group: %w", ErrUnsupportedOrder)
+		}
+	}
+
+	return nil
+}
+
 // TimeInForce controls how long an order remains active.
 type TimeInForce int
 
@@ -146,12 +215,16 @@ const (
 // priced, was rejected by a risk or margin check, or its limit was not
 // touched. Such a Fill carries Qty 0 and a zero Price; Err describes why the
 // order failed so the originating strategy can react to it.
+//
+// Fees lists commissions and regulatory fees charged for the fill. The
+// account records each one as a FeeTransaction against the order.
 type Fill struct {
 	OrderID  string
 	Price    float64
 	Qty      float64
 	FilledAt time.Time
 	Err      error
+	Fees     []Fee
 }
 
 // Position represents a holding in the account.
@@ -239,6 +312,16 @@ type GroupSubmitter interface {
 	SubmitGroup(ctx context.Context, orders []Order, groupType GroupType) error
 }
 
+// FillReplayer is implemented by brokers that can report fills executed
+// after a given time. The engine uses it when a live session resumes from
+// a checkpoint, to recover fills that happened while it was not running.
+// Each returned Fill carries the order's cumulative filled quantity and
+// average price; the portfolio applies only the part beyond what it has
+// already recorded for the order.
+type FillReplayer interface {
+	FillsSince(ctx context.Context, since time.Time) ([]Fill, error)
+}
+
 // PriceProvider supplies current market prices. The engine implements
 // this interface; the simulated broker uses it to determine fill prices
 // and convert dollar-amount orders to share quantities.
@@ -0,0 +1,295 @@
+package broker
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Fee is a single charge assessed on a fill, such as a commission or a
+// regulatory fee. Amount is the positive dollar value charged.
+type Fee struct {
+	Amount      float64
+	Description string
+}
+
+// CommissionModel computes the fees charged for a fill of an order. The
+// simulated broker attaches the result to Fill.Fees and the account
+// records each fee as a FeeTransaction linked to the order.
+type CommissionModel interface {
+	Fees(order Order, fill Fill) []Fee
+}
+
+// perShareCommission charges a rate per share with optional bounds.
+type perShareCommission struct {
+	rate       float64
+	minimum    float64
+	maximumPct float64
+}
+
+// PerShareCommission returns a CommissionModel that charges rate dollars
+// per share, at least minimum per fill, and at most maximumPct of the
+// fill's notional value. A zero minimum or maximumPct disables that
+// bound.
+func PerShareCommission(rate, minimum, maximumPct float64) CommissionModel {
+	return &perShareCommission{rate: rate, minimum: minimum, maximumPct: maximumPct}
+}
+
+func (pc *perShareCommission) Fees(order Order, fill Fill) []Fee {
+	amount := boundCommission(math.Abs(fill.Qty)*pc.rate, pc.minimum, pc.maximumPct, order, fill)
+
+	return nonZeroFee(amount, fmt.Sprintf("commission: %g shares at $%g/share", math.Abs(fill.Qty), pc.rate))
+}
+
+// flatCommission charges a fixed amount per fill.
+type flatCommission struct {
+	amount float64
+}
+
+// FlatCommission returns a CommissionModel that charges a fixed dollar
+// amount per fill regardless of size.
+func FlatCommission(amount float64) CommissionModel {
+	return &flatCommission{amount: amount}
+}
+
+func (fc *flatCommission) Fees(_ Order, _ Fill) []Fee {
+	return nonZeroFee(fc.amount, fmt.Sprintf("commission: flat $%.2f", fc.amount))
+}
+
+// bpsCommission charges basis points of notional.
+type bpsCommission struct {
+	bps float64
+}
+
+// BpsCommission returns a CommissionModel that charges bps basis points
+// (hundredths of a percent) of the fill's notional value.
+func BpsCommission(bps float64) CommissionModel {
+	return &bpsCommission{bps: bps}
+}
+
+func (bc *bpsCommission) Fees(order Order, fill Fill) []Fee {
+	amount := notional(order, fill) * bc.bps / 10_000
+
+	return nonZeroFee(amount, fmt.Sprintf("commission: %g bps of $%.2f", bc.bps, notional(order, fill)))
+}
+
+// CommissionTier is one step of a volume-tiered schedule. Rate applies
+// to shares traded in the calendar month up to and including UpToShares.
+// The last tier should use math.Inf(1).
+type CommissionTier struct {
+	UpToShares float64
+	Rate       float64
+}
+
+// tieredCommission charges a per-share rate that falls as monthly
+// volume grows.
+type tieredCommission struct {
+	tiers      []CommissionTier
+	minimum    float64
+	maximumPct float64
+
+	mu     sync.Mutex
+	month  time.Time
+	volume float64
+}
+
+// TieredCommission returns a CommissionModel that charges per share
+// according to a monthly-volume schedule, in the style of Interactive
+// Brokers' tiered pricing. The model tracks shares traded in the current
+// calendar month (by fill date) and prices each share at the rate of the
+// tier it falls in, so a fill that crosses a tier boundary is charged at
+// both rates. minimum and maximumPct bound each fill as in
+// PerShareCommission.
+func TieredCommission(tiers []CommissionTier, minimum, maximumPct float64) CommissionModel {
+	return &tieredCommission{tiers: tiers, minimum: minimum, maximumPct: maximumPct}
+}
+
+func (tc *tieredCommission) Fees(order Order, fill Fill) []Fee {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	month := time.Date(fill.FilledAt.Year(), fill.FilledAt.Month(), 1, 0, 0, 0, 0, fill.FilledAt.Location())
+	if !month.Equal(tc.month) {
+		tc.month = month
+		tc.volume = 0
+	}
+
+	remaining := math.Abs(fill.Qty)
+	amount := 0.0
+
+	for _, tier := range tc.tiers {
+		if remaining <= 0 {
+			break
+		}
+
+		capacity := tier.UpToShares - tc.volume
+		if capacity <= 0 {
+			continue
+		}
+
+		shares := math.Min(remaining, capacity)
+		amount += shares * tier.Rate
+		tc.volume += shares
+		remaining -= shares
+	}
+
+	// Volume beyond the last tier is charged at the last tier's rate.
+	if remaining > 0 && len(tc.tiers) > 0 {
+		amount += remaining * tc.tiers[len(tc.tiers)-1].Rate
+		tc.volume += remaining
+	}
+
+	amount = boundCommission(amount, tc.minimum, tc.maximumPct, order, fill)
+
+	return nonZeroFee(amount, fmt.Sprintf("commission: %g shares tiered (%.0f shares this month)", math.Abs(fill.Qty), tc.volume))
+}
+
+// IBKRFixed returns Interactive Brokers' fixed US stock pricing: $0.005
+// per share, $1.00 minimum, capped at 1% of trade value.
+func IBKRFixed() CommissionModel {
+	return PerShareCommission(0.005, 1.00, 0.01)
+}
+
+// IBKRTiered returns Interactive Brokers' tiered US stock commission
+// schedule: $0.0035 per share up to 300,000 shares a month, falling to
+// $0.0005 above 100 million, with a $0.35 minimum and a 1% of trade
+// value cap. Exchange, clearing and pass-through fees are not included.
+func IBKRTiered() CommissionModel {
+	return TieredCommission([]CommissionTier{
+		{UpToShares: 300_000, Rate: 0.0035},
+		{UpToShares: 3_000_000, Rate: 0.002},
+		{UpToShares: 20_000_000, Rate: 0.0015},
+		{UpToShares: 100_000_000, Rate: 0.001},
+		{UpToShares: math.Inf(1), Rate: 0.0005},
+	}, 0.35, 0.01)
+}
+
+// Default regulatory fee rates. Both are set by the regulator and change
+// periodically; pass current values to SECFee and FINRATAF when they do.
+const (
+	// DefaultSECFeePerMillion is the Section 31 fee in dollars per
+	// million dollars of sale proceeds.
+	DefaultSECFeePerMillion = 27.80
+
+	// DefaultTAFPerShare is the FINRA Trading Activity Fee per share sold.
+	DefaultTAFPerShare = 0.000166
+
+	// DefaultTAFMaximum is the FINRA Trading Activity Fee cap per trade.
+	DefaultTAFMaximum = 8.30
+)
+
+// secFee charges the SEC Section 31 fee on sells.
+type secFee struct {
+	perMillion float64
+}
+
+// SECFee returns a CommissionModel that charges the SEC Section 31 fee of
+// perMillion dollars per million dollars of proceeds on sell fills,
+// rounded up to the next cent. Option proceeds include the contract
+// multiplier. Buys and futures, which the fee does not cover, are not
+// charged.
+func SECFee(perMillion float64) CommissionModel {
+	return &secFee{perMillion: perMillion}
+}
+
+func (sf *secFee) Fees(order Order, fill Fill) []Fee {
+	if order.Side != Sell || order.Asset.IsFuture() {
+		return nil
+	}
+
+	amount := roundUpToCent(notional(order, fill) * sf.perMillion / 1_000_000)
+
+	return nonZeroFee(amount, fmt.Sprintf("SEC fee: $%g per million on $%.2f", sf.perMillion, notional(order, fill)))
+}
+
+// finraTAF charges the FINRA Trading Activity Fee on sells.
+type finraTAF struct {
+	perShare float64
+	maximum  float64
+}
+
+// FINRATAF returns a CommissionModel that charges the FINRA Trading
+// Activity Fee of perShare dollars per share or option contract sold,
+// capped at maximum per fill and rounded up to the next cent. Buys and
+// futures, which the fee does not cover, are not charged.
+func FINRATAF(perShare, maximum float64) CommissionModel {
+	return &finraTAF{perShare: perShare, maximum: maximum}
+}
+
+func (ft *finraTAF) Fees(order Order, fill Fill) []Fee {
+	if order.Side != Sell || order.Asset.IsFuture() {
+		return nil
+	}
+
+	amount := math.Abs(fill.Qty) * ft.perShare
+	if ft.maximum > 0 {
+		amount = math.Min(amount, ft.maximum)
+	}
+
+	amount = roundUpToCent(amount)
+
+	return nonZeroFee(amount, fmt.Sprintf("FINRA TAF: %g shares at $%g/share", math.Abs(fill.Qty), ft.perShare))
+}
+
+// RegulatoryFees returns the SEC Section 31 fee and FINRA TAF at the
+// default rates, charged on sells only.
+func RegulatoryFees() CommissionModel {
+	return Commissions(SECFee(DefaultSECFeePerMillion), FINRATAF(DefaultTAFPerShare, DefaultTAFMaximum))
+}
+
+// combinedCommission sums the fees of several models.
+type combinedCommission struct {
+	models []CommissionModel
+}
+
+// Commissions combines several models into one. Each model's fees are
+// reported separately, so a broker commission and the regulatory fees on
+// the same fill become distinct FeeTransactions.
+func Commissions(models ...CommissionModel) CommissionModel {
+	return &combinedCommission{models: models}
+}
+
+func (cc *combinedCommission) Fees(order Order, fill Fill) []Fee {
+	var fees []Fee
+
+	for _, model := range cc.models {
+		fees = append(fees, model.Fees(order, fill)...)
+	}
+
+	return fees
+}
+
+// notional returns the absolute dollar value of a fill, scaled by the
+// contract multiplier for options and futures.
+func notional(order Order, fill Fill) float64 {
+	return math.Abs(fill.Qty * fill.Price * order.Asset.ContractMultiplier())
+}
+
+// boundCommission applies a per-fill minimum and a cap expressed as a
+// fraction of notional. The cap wins when the two conflict.
+func boundCommission(amount, minimum, maximumPct float64, order Order, fill Fill) float64 {
+	if minimum > 0 {
+		amount = math.Max(amount, minimum)
+	}
+
+	if maximumPct > 0 {
+		amount = math.Min(amount, maximumPct*notional(order, fill))
+	}
+
+	return amount
+}
+
+// roundUpToCent rounds a positive dollar amount up to the next cent.
+func roundUpToCent(amount float64) float64 {
+	return math.Ceil(math.Round(amount*1e6)/1e4) / 100
+}
+
+// nonZeroFee wraps a positive amount in a single-element slice.
+func nonZeroFee(amount float64, description string) []Fee {
+	if amount <= 0 || math.IsNaN(amount) {
+		return nil
+	}
+
+	return []Fee{{Amount: amount, Description: description}}
+}
@@ -0,0 +1,168 @@
+package broker_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+)
+
+var _ = Describe("CommissionModel", func() {
+	var (
+		aapl asset.Asset
+		date time.Time
+		buy  broker.Order
+		sell broker.Order
+	)
+
+	fill := func(qty, price float64, at time.Time) broker.Fill {
+		return broker.Fill{OrderID: "ord-1", Price: price, Qty: qty, FilledAt: at}
+	}
+
+	total := func(fees []broker.Fee) float64 {
+		sum := 0.0
+		for _, fee := range fees {
+			sum += fee.Amount
+		}
+
+		return sum
+	}
+
+	BeforeEach(func() {
+		aapl = asset.Asset{CompositeFigi: "FIGI-AAPL", Ticker: "AAPL"}
+		date = time.Date(2025, 6, 16, 16, 0, 0, 0, time.UTC)
+		buy = broker.Order{ID: "ord-1", Asset: aapl, Side: broker.Buy}
+		sell = broker.Order{ID: "ord-1", Asset: aapl, Side: broker.Sell}
+	})
+
+	Context("PerShareCommission", func() {
+		It("charges the rate per share", func() {
+			fees := broker.PerShareCommission(0.005, 0, 0).Fees(buy, fill(1000, 50, date))
+			Expect(fees).To(HaveLen(1))
+			Expect(fees[0].Amount).To(BeNumerically("~", 5.0, 1e-9))
+			Expect(fees[0].Description).To(ContainSubstring("commission"))
+		})
+
+		It("applies the minimum to small fills", func() {
+			fees := broker.PerShareCommission(0.005, 1.00, 0).Fees(buy, fill(10, 50, date))
+			Expect(total(fees)).To(BeNumerically("~", 1.00, 1e-9))
+		})
+
+		It("caps the charge at a percentage of notional", func() {
+			fees := broker.IBKRFixed().Fees(buy, fill(1000, 0.10, date))
+			Expect(total(fees)).To(BeNumerically("~", 1.00, 1e-9))
+		})
+	})
+
+	Context("FlatCommission", func() {
+		It("charges the same amount for every fill", func() {
+			model := broker.FlatCommission(4.95)
+			Expect(total(model.Fees(buy, fill(1, 10, date)))).To(BeNumerically("~", 4.95, 1e-9))
+			Expect(total(model.Fees(sell, fill(10_000, 10, date)))).To(BeNumerically("~", 4.95, 1e-9))
+		})
+
+		It("reports nothing for a zero amount", func() {
+			Expect(broker.FlatCommission(0).Fees(buy, fill(1, 10, date))).To(BeEmpty())
+		})
+	})
+
+	Context("BpsCommission", func() {
+		It("charges basis points of notional", func() {
+			fees := broker.BpsCommission(5).Fees(sell, fill(-200, 50, date))
+			Expect(total(fees)).To(BeNumerically("~", 5.0, 1e-9))
+		})
+	})
+
+	Context("notional of contracts", func() {
+		It("scales basis points by the contract multiplier", func() {
+			future := asset.NewFuture("ESZ5", asset.Contract{Multiplier: 50})
+			futureBuy := broker.Order{ID: "ord-1", Asset: future, Side: broker.Buy}
+
+			// 2 contracts * $6,000 * 50 = $600,000 * 1 bp = $60
+			fees := broker.BpsCommission(1).Fees(futureBuy, fill(2, 6000, date))
+			Expect(total(fees)).To(BeNumerically("~", 60.0, 1e-9))
+		})
+	})
+
+	Context("TieredCommission", func() {
+		var model broker.CommissionModel
+
+		BeforeEach(func() {
+			model = broker.TieredCommission([]broker.CommissionTier{
+				{UpToShares: 1000, Rate: 0.01},
+				{UpToShares: 2000, Rate: 0.005},
+			}, 0, 0)
+		})
+
+		It("charges each share at the rate of the tier it falls in", func() {
+			Expect(total(model.Fees(buy, fill(800, 100, date)))).To(BeNumerically("~", 8.0, 1e-9))
+
+			// 200 shares at 0.01 finish the first tier, 300 at 0.005.
+			Expect(total(model.Fees(buy, fill(500, 100, date)))).To(BeNumerically("~", 3.5, 1e-9))
+		})
+
+		It("charges volume beyond the last tier at the last rate", func() {
+			Expect(total(model.Fees(buy, fill(3000, 100, date)))).To(BeNumerically("~", 10+5+5, 1e-9))
+		})
+
+		It("resets volume at the start of each month", func() {
+			model.Fees(buy, fill(1000, 100, date))
+
+			nextMonth := time.Date(2025, 7, 1, 16, 0, 0, 0, time.UTC)
+			Expect(total(model.Fees(buy, fill(100, 100, nextMonth)))).To(BeNumerically("~", 1.0, 1e-9))
+		})
+	})
+
+	Context("regulatory fees", func() {
+		It("does not charge buys", func() {
+			Expect(broker.RegulatoryFees().Fees(buy, fill(1000, 100, date))).To(BeEmpty())
+		})
+
+		It("charges the SEC fee on sale proceeds rounded up to the cent", func() {
+			// $100,000 * 27.80 / 1,000,000 = $2.78
+			fees := broker.SECFee(broker.DefaultSECFeePerMillion).Fees(sell, fill(-1000, 100, date))
+			Expect(total(fees)).To(BeNumerically("~", 2.78, 1e-9))
+
+			// $1,000 * 27.80 / 1,000,000 = $0.0278 -> $0.03
+			fees = broker.SECFee(broker.DefaultSECFeePerMillion).Fees(sell, fill(-10, 100, date))
+			Expect(total(fees)).To(BeNumerically("~", 0.03, 1e-9))
+		})
+
+		It("charges the SEC fee on option proceeds including the multiplier", func() {
+			option := asset.NewOption(aapl, asset.Call, 200, date.AddDate(0, 1, 0), 100)
+			optionSell := broker.Order{ID: "ord-1", Asset: option, Side: broker.Sell}
+
+			// 10 contracts * $5 * 100 = $5,000 * 27.80 / 1,000,000 = $0.139 -> $0.14
+			fees := broker.SECFee(broker.DefaultSECFeePerMillion).Fees(optionSell, fill(-10, 5, date))
+			Expect(total(fees)).To(BeNumerically("~", 0.14, 1e-9))
+		})
+
+		It("does not charge futures", func() {
+			future := asset.NewFuture("ESZ5", asset.Contract{Multiplier: 50})
+			futureSell := broker.Order{ID: "ord-1", Asset: future, Side: broker.Sell}
+
+			Expect(broker.RegulatoryFees().Fees(futureSell, fill(-10, 6000, date))).To(BeEmpty())
+		})
+
+		It("caps the FINRA TAF per trade", func() {
+			taf := broker.FINRATAF(broker.DefaultTAFPerShare, broker.DefaultTAFMaximum)
+
+			// 1,000 * 0.000166 = 0.166 -> 0.17
+			Expect(total(taf.Fees(sell, fill(-1000, 10, date)))).To(BeNumerically("~", 0.17, 1e-9))
+			Expect(total(taf.Fees(sell, fill(-1_000_000, 10, date)))).To(BeNumerically("~", 8.30, 1e-9))
+		})
+	})
+
+	Context("Commissions", func() {
+		It("reports each model's fees separately", func() {
+			model := broker.Commissions(broker.FlatCommission(1), broker.RegulatoryFees())
+
+			fees := model.Fees(sell, fill(-1000, 100, date))
+			Expect(fees).To(HaveLen(3))
+			Expect(total(fees)).To(BeNumerically("~", 1+2.78+0.17, 1e-9))
+		})
+	})
+})
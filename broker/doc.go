@@ -49,7 +49,11 @@
 //   - TimeInForce: how long the order remains active (see Time in Force
 //     below).
 //   - LimitPrice, StopPrice: trigger and limit prices for Limit, Stop,
-//     and StopLimit orders.
+//     and StopLimit orders. Trailing orders keep their current stop in
+//     StopPrice.
+//   - TrailAmount, TrailPercent, LimitOffset: the trail distance of
+//     TrailingStop and TrailingStopLimit orders and the limit's offset
+//     from the stop.
 //   - GTDDate: expiry date for GTD time-in-force orders.
 //
 // OrderStatus tracks an order through its lifecycle:
@@ -67,6 +71,11 @@
 //   - Stop: becomes a market order when the price reaches StopPrice.
 //   - StopLimit: becomes a limit order at LimitPrice when the price reaches
 //     StopPrice.
+//   - TrailingStop: a stop that follows the price by TrailAmount or
+//     TrailPercent as it moves in the order's favor and becomes a market
+//     order when the price reverses by the trail.
+//   - TrailingStopLimit: a trailing stop that becomes a limit order
+//     LimitOffset beyond the stop when triggered.
 //
 // # Time in Force
 //
@@ -136,7 +145,9 @@
 // # SimulatedBroker
 //
 // SimulatedBroker lives in the engine package and fills all orders at the
-// closing price for backtesting. It supports dollar-amount orders by
+// closing price for backtesting. A fill pipeline built from a BaseModel
+// and Adjusters changes the price; the NextOpen and OHLCPath base models
+// hold each order and fill it against the following bar. It supports dollar-amount orders by
 // dividing the requested amount by the close price. Cancel is supported for
 // managing pending bracket/OCO orders. Replace is not supported and returns
 // an error if called.
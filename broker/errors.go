@@ -18,6 +18,8 @@ var (
 	ErrMultipleEntryOrders = errors.New("broker: multiple entry orders in group")
 	ErrOrderRejected       = errors.New("broker: order rejected")
 	ErrRateLimited         = errors.New("broker: rate limited")
+	ErrPriceNotReached     = errors.New("broker: limit or stop price not reached")
+	ErrUnsupportedOrder    = errors.New("broker: order type not supported")
 )
 
 // HTTPError represents an HTTP response with a non-2xx status code.
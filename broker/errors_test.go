@@ -24,6 +24,7 @@ var _ = Describe("Errors", func() {
 			Expect(broker.ErrNoEntryOrder).To(MatchError("broker: no entry order in group"))
 			Expect(broker.ErrMultipleEntryOrders).To(MatchError("broker: multiple entry orders in group"))
 			Expect(broker.ErrOrderRejected).To(MatchError("broker: order rejected"))
+			Expect(broker.ErrUnsupportedOrder).To(MatchError("broker: order type not supported"))
 		})
 	})
 
@@ -18,14 +18,16 @@ const (
 
 // EtradeBroker implements broker.Broker for the E*TRADE brokerage.
 type EtradeBroker struct {
-	client      *apiClient
-	auth        *tokenManager
-	poller      *orderPoller
-	fills       chan broker.Fill
-	mu          sync.Mutex
-	sandbox     bool
-	tokenFile   string
-	callbackURL string
+	client       *apiClient
+	auth         *tokenManager
+	poller       *orderPoller
+	fills        chan broker.Fill
+	mu           sync.Mutex
+	sandbox      bool
+	tokenFile    string
+	callbackURL  string
+	accountIDKey string
+	getenv       func(string) string
 }
 
 // Option configures an EtradeBroker.
@@ -52,9 +54,17 @@ func WithCallbackURL(callbackURL string) Option {
 	}
 }
 
+// WithAccountIDKey selects the account to trade by its account ID key
+// (from the List Accounts API), overriding ETRADE_ACCOUNT_ID_KEY.
+func WithAccountIDKey(accountIDKey string) Option {
+	return func(eb *EtradeBroker) {
+		eb.accountIDKey = accountIDKey
+	}
+}
+
 // New creates a new EtradeBroker with the given options.
 func New(opts ...Option) *EtradeBroker {
-	eb := &EtradeBroker{fills: make(chan broker.Fill, 1024)}
+	eb := &EtradeBroker{fills: make(chan broker.Fill, 1024), getenv: os.Getenv}
 	for _, opt := range opts {
 		opt(eb)
 	}
@@ -71,9 +81,12 @@ func (eb *EtradeBroker) Fills() <-chan broker.Fill {
 // credentials from environment variables, loads any existing tokens, attempts
 // renewal, and falls back to the interactive auth flow when necessary.
 func (eb *EtradeBroker) Connect(ctx context.Context) error {
-	consumerKey := os.Getenv("ETRADE_CONSUMER_KEY")
-	consumerSecret := os.Getenv("ETRADE_CONSUMER_SECRET")
-	accountIDKey := os.Getenv("ETRADE_ACCOUNT_ID_KEY")
+	consumerKey := eb.getenv("ETRADE_CONSUMER_KEY")
+	consumerSecret := eb.getenv("ETRADE_CONSUMER_SECRET")
+	accountIDKey := eb.accountIDKey
+	if accountIDKey == "" {
+		accountIDKey = eb.getenv("ETRADE_ACCOUNT_ID_KEY")
+	}
 
 	if consumerKey == "" || consumerSecret == "" || accountIDKey == "" {
 		return fmt.Errorf("etrade: connect: %w", broker.ErrMissingCredentials)
@@ -81,12 +94,12 @@ func (eb *EtradeBroker) Connect(ctx context.Context) error {
 
 	callbackURL := eb.callbackURL
 	if callbackURL == "" {
-		callbackURL = os.Getenv("ETRADE_CALLBACK_URL")
+		callbackURL = eb.getenv("ETRADE_CALLBACK_URL")
 	}
 
 	tokenFile := eb.tokenFile
 	if tokenFile == "" {
-		tokenFile = os.Getenv("ETRADE_TOKEN_FILE")
+		tokenFile = eb.getenv("ETRADE_TOKEN_FILE")
 	}
 
 	eb.auth = newTokenManager(consumerKey, consumerSecret, callbackURL, tokenFile)
@@ -144,6 +157,10 @@ func (eb *EtradeBroker) Close() error {
 // The order action (BUY/SELL/SELL_SHORT/BUY_TO_COVER) is determined by
 // comparing the requested side against existing positions.
 func (eb *EtradeBroker) Submit(ctx context.Context, order broker.Order) error {
+	if order.OrderType.IsTrailing() {
+		return fmt.Errorf("etrade: trailing stop: %w", broker.ErrUnsupportedOrder)
+	}
+
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
 
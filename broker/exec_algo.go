@@ -0,0 +1,93 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
+)
+
+// ExecAlgo selects how an order is worked over time.
+type ExecAlgo int
+
+const (
+	// ExecImmediate sends the order to the broker whole. It is the zero
+	// value.
+	ExecImmediate ExecAlgo = iota
+	// ExecTWAP splits the order into equal slices spaced evenly over a
+	// duration.
+	ExecTWAP
+	// ExecVWAP splits the order into slices sized by traded volume over a
+	// duration.
+	ExecVWAP
+	// ExecPOV trades a fixed percentage of each minute's volume until the
+	// order is filled.
+	ExecPOV
+)
+
+// String returns the algorithm's usual abbreviation.
+func (algo ExecAlgo) String() string {
+	switch algo {
+	case ExecTWAP:
+		return "TWAP"
+	case ExecVWAP:
+		return "VWAP"
+	case ExecPOV:
+		return "POV"
+	default:
+		return "immediate"
+	}
+}
+
+// ExecutionReport summarizes how an algorithmic order was worked. The
+// arrival price is the market price when the parent order was submitted.
+type ExecutionReport struct {
+	OrderID      string
+	Asset        asset.Asset
+	Side         Side
+	Algo         ExecAlgo
+	Ordered      float64
+	Filled       float64
+	AvgPrice     float64
+	ArrivalPrice float64
+	Slices       int
+	Start        time.Time
+	End          time.Time
+}
+
+// ImplementationShortfall returns the cost of working the order relative
+// to its arrival price as a fraction of that price: positive when buys
+// paid more, or sells received less, than the arrival price. It is zero
+// when nothing filled or the arrival price is unknown.
+func (r ExecutionReport) ImplementationShortfall() float64 {
+	if r.Filled == 0 || r.ArrivalPrice == 0 {
+		return 0
+	}
+
+	shortfall := (r.AvgPrice - r.ArrivalPrice) / r.ArrivalPrice
+	if r.Side == Sell {
+		return -shortfall
+	}
+
+	return shortfall
+}
+
+// ExecutionReporter is implemented by brokers that work algorithmic
+// orders and report how each one was executed.
+type ExecutionReporter interface {
+	ExecutionReports() []ExecutionReport
+}
@@ -20,6 +20,13 @@ type BaseModel interface {
 	Fill(ctx context.Context, order Order, bar *data.DataFrame) (FillResult, error)
 }
 
+// NextBarModel is implemented by base models that must not fill on the bar
+// an order was submitted on. The simulated broker holds such orders and
+// fills them against the following bar.
+type NextBarModel interface {
+	FillsOnNextBar() bool
+}
+
 // Adjuster modifies a FillResult produced by a BaseModel or prior Adjuster.
 type Adjuster interface {
 	Adjust(ctx context.Context, order Order, bar *data.DataFrame, current FillResult) (FillResult, error)
@@ -77,6 +84,14 @@ func (pp *Pipeline) Adjust(ctx context.Context, order Order, bar *data.DataFrame
 	return result, nil
 }
 
+// FillsOnNextBar reports whether the base model fills orders against the
+// bar after the one they were submitted on.
+func (pp *Pipeline) FillsOnNextBar() bool {
+	nextBar, ok := pp.base.(NextBarModel)
+
+	return ok && nextBar.FillsOnNextBar()
+}
+
 // SetDataFetcher propagates the fetcher to any base model or adjuster that implements DataFetcherAware.
 func (pp *Pipeline) SetDataFetcher(fetcher DataFetcher) {
 	if aware, ok := pp.base.(DataFetcherAware); ok {
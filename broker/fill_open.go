@@ -0,0 +1,38 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/penny-vault/pvbt/data"
+)
+
+// nextOpenFill fills orders at the open of the bar after submission.
+type nextOpenFill struct{}
+
+// NextOpen returns a BaseModel that fills at the next bar's open price.
+// A strategy that computes on today's close cannot trade at that same
+// close, so the simulated broker holds the order and fills it against
+// the following bar. Like FillAtClose, the order type is not examined;
+// use OHLCPath for limit and stop orders.
+func NextOpen() BaseModel {
+	return &nextOpenFill{}
+}
+
+// FillsOnNextBar reports that orders wait for the following bar.
+func (nf *nextOpenFill) FillsOnNextBar() bool {
+	return true
+}
+
+func (nf *nextOpenFill) Fill(_ context.Context, order Order, bar *data.DataFrame) (FillResult, error) {
+	price := bar.Value(order.Asset, data.MetricOpen)
+	if math.IsNaN(price) || price == 0 {
+		return FillResult{}, fmt.Errorf("next open fill: no open price for %s", order.Asset.Ticker)
+	}
+
+	return FillResult{
+		Price:    price,
+		Quantity: order.Qty,
+	}, nil
+}
@@ -0,0 +1,59 @@
+package broker_test
+
+import (
+	"context"
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/data"
+)
+
+var _ = Describe("NextOpen", func() {
+	var (
+		aapl asset.Asset
+		date time.Time
+	)
+
+	BeforeEach(func() {
+		aapl = asset.Asset{CompositeFigi: "FIGI-AAPL", Ticker: "AAPL"}
+		date = time.Date(2025, 6, 16, 16, 0, 0, 0, time.UTC)
+	})
+
+	It("fills at the open price", func() {
+		bar := buildBar(date, aapl, map[data.Metric]float64{data.MetricOpen: 148.0, data.MetricClose: 150.0})
+
+		result, err := broker.NextOpen().Fill(context.Background(), broker.Order{Asset: aapl, Qty: 100}, bar)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Price).To(Equal(148.0))
+		Expect(result.Quantity).To(Equal(100.0))
+	})
+
+	It("returns an error when the open price is missing", func() {
+		bar := buildBar(date, aapl, map[data.Metric]float64{data.MetricOpen: math.NaN(), data.MetricClose: 150.0})
+
+		_, err := broker.NextOpen().Fill(context.Background(), broker.Order{Asset: aapl, Qty: 100}, bar)
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("marks its pipeline as filling on the next bar", func() {
+		Expect(broker.NewPipeline(broker.NextOpen(), nil).FillsOnNextBar()).To(BeTrue())
+		Expect(broker.NewPipeline(broker.FillAtClose(), nil).FillsOnNextBar()).To(BeFalse())
+	})
+
+	It("composes with slippage", func() {
+		bar := buildBar(date, aapl, map[data.Metric]float64{data.MetricOpen: 100.0, data.MetricClose: 150.0})
+		pipeline := broker.NewPipeline(broker.NextOpen(), []broker.Adjuster{broker.Slippage(broker.Percent(0.01))})
+
+		result, err := pipeline.Fill(context.Background(), broker.Order{Asset: aapl, Side: broker.Buy, Qty: 10}, bar)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Price).To(BeNumerically("~", 101.0, 1e-9))
+	})
+})
@@ -0,0 +1,156 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/penny-vault/pvbt/data"
+)
+
+// PathOrder is the assumed order in which a bar visits its high and low.
+type PathOrder int
+
+const (
+	// OpenHighLowClose assumes the bar trades up to its high before it
+	// trades down to its low.
+	OpenHighLowClose PathOrder = iota
+
+	// OpenLowHighClose assumes the bar trades down to its low before it
+	// trades up to its high.
+	OpenLowHighClose
+)
+
+// String returns the path as it is usually written, e.g. "O-H-L-C".
+func (po PathOrder) String() string {
+	if po == OpenLowHighClose {
+		return "O-L-H-C"
+	}
+
+	return "O-H-L-C"
+}
+
+// ohlcPathFill fills orders by walking an assumed intrabar price path.
+type ohlcPathFill struct {
+	path PathOrder
+}
+
+// OHLCPath returns a BaseModel that walks the bar after submission along
+// an assumed open, high, low, close path (or open, low, high, close) and
+// fills each order at the first point the path reaches its price:
+//
+//   - Market orders fill at the open.
+//   - Limit orders fill at the open when the bar gaps through the limit,
+//     otherwise at the limit price when the path touches it.
+//   - Stop orders fill at the open when the bar gaps through the stop,
+//     otherwise at the stop price when the path touches it.
+//   - Stop-limit orders trigger at the stop as above and then fill as a
+//     limit order on the remainder of the path.
+//
+// When the path never reaches the order's price the model returns
+// ErrPriceNotReached. Missing high or low values are treated as the
+// larger and smaller of open and close.
+func OHLCPath(path PathOrder) BaseModel {
+	return &ohlcPathFill{path: path}
+}
+
+// FillsOnNextBar reports that orders wait for the following bar.
+func (pf *ohlcPathFill) FillsOnNextBar() bool {
+	return true
+}
+
+func (pf *ohlcPathFill) Fill(_ context.Context, order Order, bar *data.DataFrame) (FillResult, error) {
+	prices, err := pf.pricePath(order, bar)
+	if err != nil {
+		return FillResult{}, err
+	}
+
+	var (
+		price   float64
+		reached bool
+	)
+
+	switch order.OrderType {
+	case Market:
+		price, reached = prices[0], true
+	case Limit:
+		price, _, reached = reachPrice(prices, order.LimitPrice, order.Side == Sell)
+	case Stop:
+		price, _, reached = reachPrice(prices, order.StopPrice, order.Side == Buy)
+	case StopLimit:
+		var remaining []float64
+
+		_, remaining, reached = reachPrice(prices, order.StopPrice, order.Side == Buy)
+		if reached {
+			price, _, reached = reachPrice(remaining, order.LimitPrice, order.Side == Sell)
+		}
+	default:
+		return FillResult{}, fmt.Errorf("ohlc path fill: unsupported order type %d for %s", order.OrderType, order.Asset.Ticker)
+	}
+
+	if !reached {
+		return FillResult{}, fmt.Errorf("ohlc path fill: %s along %s: %w",
+			order.Asset.Ticker, pf.path, ErrPriceNotReached)
+	}
+
+	return FillResult{
+		Price:    price,
+		Quantity: order.Qty,
+	}, nil
+}
+
+// pricePath returns the bar's open, high, low and close in path order.
+func (pf *ohlcPathFill) pricePath(order Order, bar *data.DataFrame) ([]float64, error) {
+	open := bar.Value(order.Asset, data.MetricOpen)
+	closePrice := bar.Value(order.Asset, data.MetricClose)
+
+	if math.IsNaN(open) || open == 0 || math.IsNaN(closePrice) || closePrice == 0 {
+		return nil, fmt.Errorf("ohlc path fill: missing open or close price for %s", order.Asset.Ticker)
+	}
+
+	high := bar.Value(order.Asset, data.MetricHigh)
+	if math.IsNaN(high) || high == 0 {
+		high = math.Max(open, closePrice)
+	}
+
+	low := bar.Value(order.Asset, data.MetricLow)
+	if math.IsNaN(low) || low == 0 {
+		low = math.Min(open, closePrice)
+	}
+
+	if pf.path == OpenLowHighClose {
+		return []float64{open, low, high, closePrice}, nil
+	}
+
+	return []float64{open, high, low, closePrice}, nil
+}
+
+// reachPrice walks prices, treating the movement between consecutive
+// points as continuous, and returns the first price at which the path is
+// at or above level (when above is true) or at or below it. A first
+// point already beyond level is a gap and fills there; otherwise the path
+// crosses level and fills at level. The remaining path starts at the fill
+// price.
+func reachPrice(prices []float64, level float64, above bool) (float64, []float64, bool) {
+	beyond := func(price float64) bool {
+		if above {
+			return price >= level
+		}
+
+		return price <= level
+	}
+
+	if beyond(prices[0]) {
+		return prices[0], prices, true
+	}
+
+	for idx := 1; idx < len(prices); idx++ {
+		if beyond(prices[idx]) {
+			remaining := append([]float64{level}, prices[idx:]...)
+
+			return level, remaining, true
+		}
+	}
+
+	return 0, nil, false
+}
@@ -0,0 +1,126 @@
+package broker_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/data"
+)
+
+var _ = Describe("OHLCPath", func() {
+	var (
+		aapl asset.Asset
+		date time.Time
+		bar  *data.DataFrame
+	)
+
+	fill := func(path broker.PathOrder, order broker.Order) (broker.FillResult, error) {
+		order.Asset = aapl
+		order.Qty = 10
+
+		return broker.OHLCPath(path).Fill(context.Background(), order, bar)
+	}
+
+	BeforeEach(func() {
+		aapl = asset.Asset{CompositeFigi: "FIGI-AAPL", Ticker: "AAPL"}
+		date = time.Date(2025, 6, 16, 16, 0, 0, 0, time.UTC)
+
+		// Open 100, high 110, low 90, close 105.
+		bar = buildBar(date, aapl, map[data.Metric]float64{
+			data.MetricOpen:  100.0,
+			data.MetricHigh:  110.0,
+			data.MetricLow:   90.0,
+			data.MetricClose: 105.0,
+		})
+	})
+
+	It("fills market orders at the open", func() {
+		result, err := fill(broker.OpenHighLowClose, broker.Order{Side: broker.Buy, OrderType: broker.Market})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Price).To(Equal(100.0))
+		Expect(result.Quantity).To(Equal(10.0))
+	})
+
+	Context("limit orders", func() {
+		It("fills at the limit price when the path touches it", func() {
+			result, err := fill(broker.OpenHighLowClose, broker.Order{Side: broker.Buy, OrderType: broker.Limit, LimitPrice: 95})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Price).To(Equal(95.0))
+
+			result, err = fill(broker.OpenHighLowClose, broker.Order{Side: broker.Sell, OrderType: broker.Limit, LimitPrice: 108})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Price).To(Equal(108.0))
+		})
+
+		It("fills at the open when the bar gaps through the limit", func() {
+			result, err := fill(broker.OpenHighLowClose, broker.Order{Side: broker.Buy, OrderType: broker.Limit, LimitPrice: 102})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Price).To(Equal(100.0))
+		})
+
+		It("reports ErrPriceNotReached when the path never touches the limit", func() {
+			_, err := fill(broker.OpenHighLowClose, broker.Order{Side: broker.Buy, OrderType: broker.Limit, LimitPrice: 85})
+			Expect(err).To(MatchError(broker.ErrPriceNotReached))
+		})
+	})
+
+	Context("stop orders", func() {
+		It("fills at the stop price when the path touches it", func() {
+			result, err := fill(broker.OpenHighLowClose, broker.Order{Side: broker.Sell, OrderType: broker.Stop, StopPrice: 92})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Price).To(Equal(92.0))
+		})
+
+		It("fills at the open when the bar gaps through the stop", func() {
+			result, err := fill(broker.OpenHighLowClose, broker.Order{Side: broker.Sell, OrderType: broker.Stop, StopPrice: 101})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Price).To(Equal(100.0))
+		})
+
+		It("reports ErrPriceNotReached when the stop is not triggered", func() {
+			_, err := fill(broker.OpenHighLowClose, broker.Order{Side: broker.Buy, OrderType: broker.Stop, StopPrice: 111})
+			Expect(err).To(MatchError(broker.ErrPriceNotReached))
+		})
+	})
+
+	Context("stop-limit orders", func() {
+		It("fills as a limit on the path remaining after the trigger", func() {
+			// O-H-L-C: triggers at 108 on the way up, then the path
+			// falls to 90 and touches the 104 limit.
+			result, err := fill(broker.OpenHighLowClose, broker.Order{
+				Side: broker.Buy, OrderType: broker.StopLimit, StopPrice: 108, LimitPrice: 104,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Price).To(Equal(104.0))
+		})
+
+		It("depends on the order the path visits high and low", func() {
+			// O-L-H-C: the low is visited before the trigger at 108, and
+			// the path only rises to 110 and falls to 105 afterward.
+			_, err := fill(broker.OpenLowHighClose, broker.Order{
+				Side: broker.Buy, OrderType: broker.StopLimit, StopPrice: 108, LimitPrice: 104,
+			})
+			Expect(err).To(MatchError(broker.ErrPriceNotReached))
+		})
+	})
+
+	It("marks its pipeline as filling on the next bar", func() {
+		Expect(broker.NewPipeline(broker.OHLCPath(broker.OpenLowHighClose), nil).FillsOnNextBar()).To(BeTrue())
+	})
+
+	It("composes with slippage", func() {
+		pipeline := broker.NewPipeline(broker.OHLCPath(broker.OpenHighLowClose),
+			[]broker.Adjuster{broker.Slippage(broker.Fixed(0.5))})
+
+		result, err := pipeline.Fill(context.Background(), broker.Order{
+			Asset: aapl, Side: broker.Sell, Qty: 10, OrderType: broker.Limit, LimitPrice: 108,
+		}, bar)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Price).To(Equal(107.5))
+	})
+})
@@ -37,6 +37,7 @@ type IBBroker struct {
 	fills           chan broker.Fill
 	streamer        *orderStreamer
 	accountID       string
+	desiredAccount  string
 	conidCache      map[string]int64
 	cancelKeepalive context.CancelFunc
 }
@@ -63,6 +64,14 @@ func WithOAuth(baseURL, consumerKey, keyFile string) Option {
 	}
 }
 
+// WithAccountID selects the account to trade when the session has access
+// to more than one. Without it the first account is used.
+func WithAccountID(accountID string) Option {
+	return func(ib *IBBroker) {
+		ib.desiredAccount = accountID
+	}
+}
+
 // New creates a new IBBroker with the given options.
 func New(opts ...Option) *IBBroker {
 	ib := &IBBroker{
@@ -90,7 +99,7 @@ func (ib *IBBroker) Connect(ctx context.Context) error {
 		}
 	}
 
-	accountID, resolveErr := ib.client.resolveAccount(ctx)
+	accountID, resolveErr := ib.client.resolveAccount(ctx, ib.desiredAccount)
 	if resolveErr != nil {
 		return resolveErr
 	}
@@ -108,8 +108,10 @@ func decodeBody(resp *resty.Response, target any) error {
 	return nil
 }
 
-// resolveAccount returns the first account ID associated with the session.
-func (ac *apiClient) resolveAccount(ctx context.Context) (string, error) {
+// resolveAccount returns the desired account ID if the session has access
+// to it, or the first account ID associated with the session when desired
+// is empty.
+func (ac *apiClient) resolveAccount(ctx context.Context, desired string) (string, error) {
 	if waitErr := ac.limiter.Wait(ctx); waitErr != nil {
 		return "", fmt.Errorf("resolve account rate limit: %w", waitErr)
 	}
@@ -137,7 +139,17 @@ func (ac *apiClient) resolveAccount(ctx context.Context) (string, error) {
 		return "", fmt.Errorf("resolve account: %w", broker.ErrAccountNotFound)
 	}
 
-	return result.Accounts[0], nil
+	if desired == "" {
+		return result.Accounts[0], nil
+	}
+
+	for _, accountID := range result.Accounts {
+		if accountID == desired {
+			return accountID, nil
+		}
+	}
+
+	return "", fmt.Errorf("resolve account %s: %w", desired, broker.ErrAccountNotFound)
 }
 
 // submitOrder posts an array of orders and returns the reply objects.
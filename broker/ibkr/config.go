@@ -0,0 +1,79 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ibkr
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/penny-vault/pvbt/broker"
+)
+
+const (
+	defaultGatewayURL = "https://localhost:5000"
+	defaultOAuthURL   = "https://api.ibkr.com/v1/api"
+)
+
+func init() {
+	broker.Register("ibkr", NewFromConfig)
+}
+
+// NewFromConfig creates an IBBroker from a `[broker.ibkr]` config
+// section. When consumer_key and signing_key_file are set (or
+// IBKR_CONSUMER_KEY and IBKR_SIGNING_KEY_FILE) the broker uses OAuth
+// against oauth_url; otherwise it uses the Client Portal Gateway at
+// gateway_url. Account selects among the accounts the session can
+// trade. IB paper trading is chosen by logging in with the paper
+// account, so Paper is an error rather than a silent no-op.
+func NewFromConfig(cfg broker.Config) (broker.Broker, error) {
+	if cfg.Paper {
+		return nil, fmt.Errorf("ibkr: %w: log the gateway in with the paper account instead", broker.ErrPaperUnsupported)
+	}
+
+	var opts []Option
+
+	consumerKey := cfg.Getenv("IBKR_CONSUMER_KEY")
+	keyFile := cfg.Getenv("IBKR_SIGNING_KEY_FILE")
+
+	switch {
+	case consumerKey != "" && keyFile != "":
+		if _, err := os.Stat(keyFile); err != nil {
+			return nil, fmt.Errorf("ibkr: signing key file: %w", err)
+		}
+
+		oauthURL := cfg.Getenv("IBKR_OAUTH_URL")
+		if oauthURL == "" {
+			oauthURL = defaultOAuthURL
+		}
+
+		opts = append(opts, WithOAuth(oauthURL, consumerKey, keyFile))
+	case consumerKey != "" || keyFile != "":
+		return nil, fmt.Errorf("ibkr: OAuth needs both consumer_key and signing_key_file: %w", broker.ErrMissingCredentials)
+	default:
+		gatewayURL := cfg.Getenv("IBKR_GATEWAY_URL")
+		if gatewayURL == "" {
+			gatewayURL = defaultGatewayURL
+		}
+
+		opts = append(opts, WithGateway(gatewayURL))
+	}
+
+	if cfg.Account != "" {
+		opts = append(opts, WithAccountID(cfg.Account))
+	}
+
+	return New(opts...), nil
+}
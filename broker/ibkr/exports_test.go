@@ -63,7 +63,7 @@ func SetAccountIDForTest(ib *IBBroker, accountID string) {
 // Method wrappers to expose unexported client methods to tests.
 
 func (ac *apiClient) ResolveAccount(ctx context.Context) (string, error) {
-	return ac.resolveAccount(ctx)
+	return ac.resolveAccount(ctx, "")
 }
 
 func (ac *apiClient) SubmitOrder(ctx context.Context, accountID string, orders []ibOrderRequest) ([]ibOrderReply, error) {
@@ -32,6 +32,8 @@ type ibOrderRequest struct {
 	Quantity   float64 `json:"quantity"`
 	Price      float64 `json:"price,omitempty"`
 	AuxPrice   float64 `json:"auxPrice,omitempty"`
+	TrailAmt   float64 `json:"trailingAmt,omitempty"`
+	TrailType  string  `json:"trailingType,omitempty"`
 	COID       string  `json:"cOID,omitempty"`
 	ParentId   string  `json:"parentId,omitempty"`
 	OcaGroup   string  `json:"ocaGroup,omitempty"`
@@ -117,6 +119,24 @@ func toIBOrder(order broker.Order, conid int64) (ibOrderRequest, error) {
 		req.AuxPrice = order.StopPrice
 	}
 
+	// Trailing orders carry the trail; price is the initial stop and,
+	// for TRAILLMT, auxPrice is the limit offset from the stop.
+	if order.OrderType.IsTrailing() {
+		if order.TrailPercent > 0 {
+			req.TrailAmt = order.TrailPercent
+			req.TrailType = "%"
+		} else {
+			req.TrailAmt = order.TrailAmount
+			req.TrailType = "amt"
+		}
+
+		req.Price = order.StopPrice
+
+		if order.OrderType == broker.TrailingStopLimit {
+			req.AuxPrice = order.LimitOffset
+		}
+	}
+
 	return req, nil
 }
 
@@ -172,6 +192,10 @@ func mapOrderType(orderType broker.OrderType) string {
 		return "STP"
 	case broker.StopLimit:
 		return "STP_LIMIT"
+	case broker.TrailingStop:
+		return "TRAIL"
+	case broker.TrailingStopLimit:
+		return "TRAILLMT"
 	default:
 		return "MKT"
 	}
@@ -227,6 +251,10 @@ func mapIBOrderType(orderType string) broker.OrderType {
 		return broker.Stop
 	case "STP_LIMIT":
 		return broker.StopLimit
+	case "TRAIL":
+		return broker.TrailingStop
+	case "TRAILLMT":
+		return broker.TrailingStopLimit
 	default:
 		return broker.Market
 	}
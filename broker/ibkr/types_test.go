@@ -64,6 +64,26 @@ var _ = Describe("Types", func() {
 			Expect(result.AuxPrice).To(BeNumerically("==", 150.00))
 		})
 
+		It("translates a trailing stop-limit order with its trail and limit offset", func() {
+			order := broker.Order{
+				ID:           "test-4",
+				Side:         broker.Sell,
+				Qty:          25,
+				OrderType:    broker.TrailingStopLimit,
+				StopPrice:    142.50,
+				TrailPercent: 5,
+				LimitOffset:  0.25,
+				TimeInForce:  broker.GTC,
+			}
+			result, translateErr := ibkr.ToIBOrder(order, 265598)
+			Expect(translateErr).ToNot(HaveOccurred())
+			Expect(result.OrderType).To(Equal("TRAILLMT"))
+			Expect(result.TrailAmt).To(BeNumerically("==", 5))
+			Expect(result.TrailType).To(Equal("%"))
+			Expect(result.Price).To(BeNumerically("==", 142.50))
+			Expect(result.AuxPrice).To(BeNumerically("==", 0.25))
+		})
+
 		It("maps all supported time-in-force values", func() {
 			for _, tc := range []struct {
 				input    broker.TimeInForce
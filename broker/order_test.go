@@ -0,0 +1,43 @@
+package broker_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/broker"
+)
+
+var _ = Describe("Trailing stops", func() {
+	It("trails a sell stop below the reference by a dollar amount", func() {
+		order := broker.Order{Side: broker.Sell, OrderType: broker.TrailingStop, TrailAmount: 2.5}
+		Expect(broker.TrailStop(order, 100)).To(Equal(97.5))
+	})
+
+	It("trails a buy stop above the reference by a percentage", func() {
+		order := broker.Order{Side: broker.Buy, OrderType: broker.TrailingStop, TrailPercent: 10}
+		Expect(broker.TrailStop(order, 50)).To(BeNumerically("~", 55.0, 1e-9))
+	})
+
+	It("offsets the limit beyond the stop in the direction of the trade", func() {
+		sell := broker.Order{Side: broker.Sell, OrderType: broker.TrailingStopLimit, LimitOffset: 0.5}
+		buy := broker.Order{Side: broker.Buy, OrderType: broker.TrailingStopLimit, LimitOffset: 0.5}
+
+		Expect(broker.TrailLimit(sell, 95)).To(Equal(94.5))
+		Expect(broker.TrailLimit(buy, 105)).To(Equal(105.5))
+	})
+
+	It("reports which order types trail", func() {
+		Expect(broker.TrailingStop.IsTrailing()).To(BeTrue())
+		Expect(broker.TrailingStopLimit.IsTrailing()).To(BeTrue())
+		Expect(broker.Stop.IsTrailing()).To(BeFalse())
+	})
+
+	It("rejects order groups with a trailing leg", func() {
+		entry := broker.Order{Side: broker.Buy, OrderType: broker.Market}
+		stop := broker.Order{Side: broker.Sell, OrderType: broker.Stop, StopPrice: 95}
+		trailing := broker.Order{Side: broker.Sell, OrderType: broker.TrailingStop, TrailAmount: 5}
+
+		Expect(broker.CheckGroupLegs([]broker.Order{entry, stop})).To(Succeed())
+		Expect(broker.CheckGroupLegs([]broker.Order{entry, trailing})).To(MatchError(broker.ErrUnsupportedOrder))
+	})
+})
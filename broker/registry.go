@@ -0,0 +1,150 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrUnknownBroker is returned by Create when no adapter is
+	// registered under the requested name.
+	ErrUnknownBroker = errors.New("broker: unknown broker")
+
+	// ErrPaperUnsupported is returned by adapters that have no paper or
+	// sandbox environment when Config.Paper is set.
+	ErrPaperUnsupported = errors.New("broker: paper trading not supported")
+)
+
+// Config carries the settings used to construct a broker adapter by
+// name. It is normally built from the `[broker.<name>]` section of
+// pvbt.toml together with the --account and --paper flags.
+type Config struct {
+	// Account selects the brokerage account to trade. Empty lets the
+	// adapter fall back to its environment variable or its default.
+	Account string
+
+	// Paper selects the adapter's paper-trading or sandbox environment.
+	Paper bool
+
+	// Settings holds the remaining keys of the config section. Keys are
+	// lower case and named after the adapter's environment variables
+	// without the broker prefix, e.g. api_key for ALPACA_API_KEY.
+	Settings map[string]string
+}
+
+// Getenv returns the setting that corresponds to the environment
+// variable envVar, falling back to the environment itself. The setting
+// name is envVar with everything up to and including the first
+// underscore removed, lower-cased: ALPACA_API_KEY reads api_key.
+func (cfg Config) Getenv(envVar string) string {
+	if value, ok := cfg.Settings[settingName(envVar)]; ok && value != "" {
+		return value
+	}
+
+	return os.Getenv(envVar)
+}
+
+// Bool parses the named setting as a boolean. Missing settings are false.
+func (cfg Config) Bool(key string) (bool, error) {
+	value, ok := cfg.Settings[key]
+	if !ok || value == "" {
+		return false, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("broker: setting %s: %w", key, err)
+	}
+
+	return parsed, nil
+}
+
+// settingName maps an environment variable name to its config key.
+func settingName(envVar string) string {
+	if _, rest, found := strings.Cut(envVar, "_"); found {
+		envVar = rest
+	}
+
+	return strings.ToLower(envVar)
+}
+
+// Factory constructs a broker adapter from its configuration. The
+// returned broker is not yet connected.
+type Factory func(cfg Config) (Broker, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a broker adapter available by name. Adapters call it
+// from an init function. Registering the same name twice panics.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic("broker: Register factory is nil for " + name)
+	}
+
+	if _, dup := registry[name]; dup {
+		panic("broker: Register called twice for " + name)
+	}
+
+	registry[name] = factory
+}
+
+// Create constructs the adapter registered under name. The adapter's
+// package must have been imported for it to be registered.
+func Create(name string, cfg Config) (Broker, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w %q (registered: %s)", ErrUnknownBroker, name, strings.Join(Registered(), ", "))
+	}
+
+	b, err := factory(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("broker: create %s: %w", name, err)
+	}
+
+	return b, nil
+}
+
+// Registered returns the names of all registered adapters in sorted
+// order.
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,61 @@
+package broker_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/broker"
+)
+
+var _ = Describe("Registry", func() {
+	Describe("Config", func() {
+		It("prefers settings over the environment and strips the broker prefix", func() {
+			GinkgoT().Setenv("EXAMPLE_API_KEY", "from-env")
+			GinkgoT().Setenv("EXAMPLE_API_SECRET", "secret-from-env")
+
+			cfg := broker.Config{Settings: map[string]string{"api_key": "from-config"}}
+			Expect(cfg.Getenv("EXAMPLE_API_KEY")).To(Equal("from-config"))
+			Expect(cfg.Getenv("EXAMPLE_API_SECRET")).To(Equal("secret-from-env"))
+			Expect(cfg.Getenv("EXAMPLE_ACCOUNT_ID_KEY")).To(BeEmpty())
+		})
+
+		It("parses boolean settings", func() {
+			cfg := broker.Config{Settings: map[string]string{"fractional": "true", "bad": "maybe"}}
+
+			enabled, err := cfg.Bool("fractional")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(enabled).To(BeTrue())
+
+			missing, err := cfg.Bool("missing")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(missing).To(BeFalse())
+
+			_, err = cfg.Bool("bad")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	It("creates registered adapters by name and passes the config through", func() {
+		var seen broker.Config
+
+		broker.Register("registry-test", func(cfg broker.Config) (broker.Broker, error) {
+			seen = cfg
+			return nil, nil
+		})
+
+		_, err := broker.Create("registry-test", broker.Config{Account: "A1", Paper: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(seen.Account).To(Equal("A1"))
+		Expect(seen.Paper).To(BeTrue())
+		Expect(broker.Registered()).To(ContainElement("registry-test"))
+
+		Expect(func() {
+			broker.Register("registry-test", func(broker.Config) (broker.Broker, error) { return nil, nil })
+		}).To(Panic())
+	})
+
+	It("reports unknown names", func() {
+		_, err := broker.Create("no-such-broker", broker.Config{})
+		Expect(err).To(MatchError(broker.ErrUnknownBroker))
+	})
+})
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/penny-vault/pvbt/asset"
 	"github.com/penny-vault/pvbt/broker"
 )
 
@@ -25,6 +26,8 @@ type SchwabBroker struct {
 	accountHash string
 	tokenFile   string
 	callbackURL string
+	account     string
+	getenv      func(string) string
 	mu          sync.Mutex
 }
 
@@ -45,10 +48,18 @@ func WithCallbackURL(callbackURL string) Option {
 	}
 }
 
+// WithAccountNumber selects the account to trade by its account number.
+func WithAccountNumber(accountNumber string) Option {
+	return func(schwabBroker *SchwabBroker) {
+		schwabBroker.account = accountNumber
+	}
+}
+
 // New creates a new SchwabBroker with the given options.
 func New(opts ...Option) *SchwabBroker {
 	schwabBroker := &SchwabBroker{
-		fills: make(chan broker.Fill, fillChannelSize),
+		fills:  make(chan broker.Fill, fillChannelSize),
+		getenv: os.Getenv,
 	}
 
 	for _, opt := range opts {
@@ -65,8 +76,8 @@ func (schwabBroker *SchwabBroker) Fills() <-chan broker.Fill {
 
 // Connect authenticates with Schwab and starts the activity streamer.
 func (schwabBroker *SchwabBroker) Connect(ctx context.Context) error {
-	clientID := os.Getenv("SCHWAB_CLIENT_ID")
-	clientSecret := os.Getenv("SCHWAB_CLIENT_SECRET")
+	clientID := schwabBroker.getenv("SCHWAB_CLIENT_ID")
+	clientSecret := schwabBroker.getenv("SCHWAB_CLIENT_SECRET")
 
 	if clientID == "" || clientSecret == "" {
 		return broker.ErrMissingCredentials
@@ -74,12 +85,12 @@ func (schwabBroker *SchwabBroker) Connect(ctx context.Context) error {
 
 	callbackURL := schwabBroker.callbackURL
 	if callbackURL == "" {
-		callbackURL = os.Getenv("SCHWAB_CALLBACK_URL")
+		callbackURL = schwabBroker.getenv("SCHWAB_CALLBACK_URL")
 	}
 
 	tokenFile := schwabBroker.tokenFile
 	if tokenFile == "" {
-		tokenFile = os.Getenv("SCHWAB_TOKEN_FILE")
+		tokenFile = schwabBroker.getenv("SCHWAB_TOKEN_FILE")
 	}
 
 	schwabBroker.auth = newTokenManager(clientID, clientSecret, callbackURL, tokenFile)
@@ -106,7 +117,10 @@ func (schwabBroker *SchwabBroker) Connect(ctx context.Context) error {
 	}
 
 	// Resolve the account hash.
-	desiredAccount := os.Getenv("SCHWAB_ACCOUNT_NUMBER")
+	desiredAccount := schwabBroker.account
+	if desiredAccount == "" {
+		desiredAccount = schwabBroker.getenv("SCHWAB_ACCOUNT_NUMBER")
+	}
 
 	accountHash, resolveErr := schwabBroker.client.resolveAccount(ctx, desiredAccount)
 	if resolveErr != nil {
@@ -167,17 +181,21 @@ func (schwabBroker *SchwabBroker) Close() error {
 
 // Submit places a single order. If Qty is zero and Amount is set, the share
 // quantity is derived from the current quote price using math.Floor.
+// Option orders are sized in contracts and open or close according to
+// the account's current position.
 func (schwabBroker *SchwabBroker) Submit(ctx context.Context, order broker.Order) error {
 	schwabBroker.mu.Lock()
 	defer schwabBroker.mu.Unlock()
 
 	qty := order.Qty
 	if qty == 0 && order.Amount > 0 {
-		price, quoteErr := schwabBroker.client.getQuote(ctx, order.Asset.Ticker)
+		price, quoteErr := schwabBroker.client.getQuote(ctx, toSchwabInstrument(order.Asset).Symbol)
 		if quoteErr != nil {
 			return fmt.Errorf("schwab: fetching quote for %s: %w", order.Asset.Ticker, quoteErr)
 		}
 
+		price *= order.Asset.ContractMultiplier()
+
 		qty = math.Floor(order.Amount / price)
 		if qty == 0 {
 			return fmt.Errorf("schwab: order for %s: amount %.2f is less than the share price %.2f",
@@ -187,7 +205,12 @@ func (schwabBroker *SchwabBroker) Submit(ctx context.Context, order broker.Order
 
 	order.Qty = qty
 
-	schwabOrder, tifErr := toSchwabOrder(order)
+	heldQty, heldErr := schwabBroker.heldQty(ctx, order.Asset)
+	if heldErr != nil {
+		return heldErr
+	}
+
+	schwabOrder, tifErr := toSchwabOrder(order, heldQty)
 	if tifErr != nil {
 		return tifErr
 	}
@@ -207,7 +230,12 @@ func (schwabBroker *SchwabBroker) Cancel(ctx context.Context, orderID string) er
 
 // Replace cancels an existing order and submits a replacement.
 func (schwabBroker *SchwabBroker) Replace(ctx context.Context, orderID string, order broker.Order) error {
-	schwabOrder, tifErr := toSchwabOrder(order)
+	heldQty, heldErr := schwabBroker.heldQty(ctx, order.Asset)
+	if heldErr != nil {
+		return heldErr
+	}
+
+	schwabOrder, tifErr := toSchwabOrder(order, heldQty)
 	if tifErr != nil {
 		return tifErr
 	}
@@ -282,7 +310,12 @@ func (schwabBroker *SchwabBroker) SubmitGroup(ctx context.Context, orders []brok
 }
 
 func (schwabBroker *SchwabBroker) submitOCO(ctx context.Context, orders []broker.Order) error {
-	ocoOrder, buildErr := buildOCOOrder(orders)
+	heldQty, heldErr := schwabBroker.heldQty(ctx, orders[0].Asset)
+	if heldErr != nil {
+		return heldErr
+	}
+
+	ocoOrder, buildErr := buildOCOOrder(orders, heldQty)
 	if buildErr != nil {
 		return buildErr
 	}
@@ -296,7 +329,12 @@ func (schwabBroker *SchwabBroker) submitOCO(ctx context.Context, orders []broker
 }
 
 func (schwabBroker *SchwabBroker) submitBracket(ctx context.Context, orders []broker.Order) error {
-	bracketOrder, buildErr := buildBracketOrder(orders)
+	heldQty, heldErr := schwabBroker.heldQty(ctx, orders[0].Asset)
+	if heldErr != nil {
+		return heldErr
+	}
+
+	bracketOrder, buildErr := buildBracketOrder(orders, heldQty)
 	if buildErr != nil {
 		return buildErr
 	}
@@ -308,3 +346,27 @@ func (schwabBroker *SchwabBroker) submitBracket(ctx context.Context, orders []br
 
 	return nil
 }
+
+// heldQty returns the account's position in an option, long positive and
+// short negative, which decides whether an option order opens or
+// closes. Other assets need no position and return zero without a
+// request.
+func (schwabBroker *SchwabBroker) heldQty(ctx context.Context, held asset.Asset) (float64, error) {
+	if !held.IsOption() {
+		return 0, nil
+	}
+
+	responses, getErr := schwabBroker.client.getPositions(ctx)
+	if getErr != nil {
+		return 0, fmt.Errorf("schwab: get positions: %w", getErr)
+	}
+
+	for _, resp := range responses {
+		position := toBrokerPosition(resp)
+		if position.Asset.Ticker == held.Ticker {
+			return position.Qty, nil
+		}
+	}
+
+	return 0, nil
+}
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"sync/atomic"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -163,6 +164,50 @@ var _ = Describe("SchwabBroker", func() {
 			Expect(submittedQty).To(Equal(50.0)) // floor(5000 / 100) = 50
 		})
 
+		It("closes a held option position with SELL_TO_CLOSE", func() {
+			var receivedBody map[string]any
+
+			schwabBroker := authenticatedBroker(func(mux *http.ServeMux) {
+				mux.HandleFunc("GET /trader/v1/accounts/HASH-TEST", func(writer http.ResponseWriter, req *http.Request) {
+					writer.Header().Set("Content-Type", "application/json")
+					sonic.ConfigDefault.NewEncoder(writer).Encode(map[string]any{
+						"securitiesAccount": map[string]any{
+							"positions": []map[string]any{
+								{
+									"instrument":   map[string]any{"symbol": "SPY   261218C00450000", "assetType": "OPTION"},
+									"longQuantity": 3.0,
+									"marketValue":  1500.0,
+								},
+							},
+						},
+					})
+				})
+
+				mux.HandleFunc("POST /trader/v1/accounts/HASH-TEST/orders", func(writer http.ResponseWriter, req *http.Request) {
+					sonic.ConfigDefault.NewDecoder(req.Body).Decode(&receivedBody)
+					writer.Header().Set("Location", "/v1/accounts/HASH-TEST/orders/ORD-OPT-1")
+					writer.WriteHeader(http.StatusCreated)
+				})
+			})
+
+			submitErr := schwabBroker.Submit(ctx, broker.Order{
+				Asset: asset.NewOption(asset.Asset{Ticker: "SPY"}, asset.Call, 450,
+					time.Date(2026, 12, 18, 0, 0, 0, 0, time.UTC), 0),
+				Side:        broker.Sell,
+				Qty:         3,
+				OrderType:   broker.Market,
+				TimeInForce: broker.Day,
+			})
+			Expect(submitErr).ToNot(HaveOccurred())
+
+			legs := receivedBody["orderLegCollection"].([]any)
+			firstLeg := legs[0].(map[string]any)
+			inst := firstLeg["instrument"].(map[string]any)
+			Expect(inst["symbol"]).To(Equal("SPY   261218C00450000"))
+			Expect(inst["assetType"]).To(Equal("OPTION"))
+			Expect(firstLeg["instruction"]).To(Equal("SELL_TO_CLOSE"))
+		})
+
 		It("returns an error without submitting when dollar amount yields zero shares", func() {
 			var submitCalled atomic.Int32
 
@@ -0,0 +1,48 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schwab
+
+import (
+	"fmt"
+
+	"github.com/penny-vault/pvbt/broker"
+)
+
+func init() {
+	broker.Register("schwab", NewFromConfig)
+}
+
+// NewFromConfig creates a SchwabBroker from a `[broker.schwab]` config
+// section. Recognized settings are client_id, client_secret,
+// callback_url, token_file and account_number, each overriding the
+// matching SCHWAB_* environment variable. Account selects the account
+// number. Schwab has no paper-trading environment, so Paper is an error.
+func NewFromConfig(cfg broker.Config) (broker.Broker, error) {
+	if cfg.Paper {
+		return nil, fmt.Errorf("schwab: %w", broker.ErrPaperUnsupported)
+	}
+
+	var opts []Option
+
+	if cfg.Account != "" {
+		opts = append(opts, WithAccountNumber(cfg.Account))
+	}
+
+	schwabBroker := New(opts...)
+	schwabBroker.getenv = cfg.Getenv
+
+	return schwabBroker, nil
+}
@@ -16,6 +16,12 @@ type schwabOrderRequest struct {
 	OrderStrategyType    string                `json:"orderStrategyType"`
 	Price                float64               `json:"price,omitempty"`
 	StopPrice            float64               `json:"stopPrice,omitempty"`
+	StopPriceLinkBasis   string                `json:"stopPriceLinkBasis,omitempty"`
+	StopPriceLinkType    string                `json:"stopPriceLinkType,omitempty"`
+	StopPriceOffset      float64               `json:"stopPriceOffset,omitempty"`
+	PriceLinkBasis       string                `json:"priceLinkBasis,omitempty"`
+	PriceLinkType        string                `json:"priceLinkType,omitempty"`
+	PriceOffset          float64               `json:"priceOffset,omitempty"`
 	TaxLotMethod         string                `json:"taxLotMethod,omitempty"`
 	OrderLegCollection   []schwabOrderLegEntry `json:"orderLegCollection"`
 	ChildOrderStrategies []schwabOrderRequest  `json:"childOrderStrategies,omitempty"`
@@ -38,6 +44,9 @@ type schwabOrderResponse struct {
 	OrderType               string                `json:"orderType"`
 	Price                   float64               `json:"price"`
 	StopPrice               float64               `json:"stopPrice"`
+	StopPriceLinkType       string                `json:"stopPriceLinkType"`
+	StopPriceOffset         float64               `json:"stopPriceOffset"`
+	PriceOffset             float64               `json:"priceOffset"`
 	Duration                string                `json:"duration"`
 	OrderStrategyType       string                `json:"orderStrategyType"`
 	OrderLegCollection      []schwabOrderLeg      `json:"orderLegCollection"`
@@ -124,13 +133,22 @@ type schwabTokenResponse struct {
 
 // --- Translation functions ---
 
-func toSchwabOrder(order broker.Order) (schwabOrderRequest, error) {
+// toSchwabOrder translates an order into a Schwab order request.
+// heldQty is the position in the order's asset before the order, long
+// positive and short negative; it picks the open or close instruction
+// for options and is ignored for other assets.
+func toSchwabOrder(order broker.Order, heldQty float64) (schwabOrderRequest, error) {
+	instruction := mapSide(order.Side)
+	if order.Asset.IsOption() {
+		instruction = optionInstruction(order.Side, heldQty)
+	}
+
 	duration, tifErr := mapTimeInForce(order.TimeInForce)
 	if tifErr != nil {
 		return schwabOrderRequest{}, tifErr
 	}
 
-	return schwabOrderRequest{
+	request := schwabOrderRequest{
 		OrderType:         mapOrderType(order.OrderType),
 		Session:           "NORMAL",
 		Duration:          duration,
@@ -140,15 +158,42 @@ func toSchwabOrder(order broker.Order) (schwabOrderRequest, error) {
 		TaxLotMethod:      mapLotSelection(order.LotSelection),
 		OrderLegCollection: []schwabOrderLegEntry{
 			{
-				Instruction: mapSide(order.Side),
+				Instruction: instruction,
 				Quantity:    order.Qty,
-				Instrument: schwabInstrument{
-					Symbol:    order.Asset.Ticker,
-					AssetType: "EQUITY",
-				},
+				Instrument:  toSchwabInstrument(order.Asset),
 			},
 		},
-	}, nil
+	}
+
+	if order.OrderType.IsTrailing() {
+		setTrailingFields(&request, order)
+	}
+
+	return request, nil
+}
+
+// setTrailingFields replaces the fixed stop and limit prices with
+// Schwab's price links: the stop trails the last trade by the order's
+// amount or percent, and a trailing stop-limit's limit is offset from
+// the triggered stop.
+func setTrailingFields(request *schwabOrderRequest, order broker.Order) {
+	request.StopPrice = 0
+	request.Price = 0
+	request.StopPriceLinkBasis = "LAST"
+
+	if order.TrailPercent > 0 {
+		request.StopPriceLinkType = "PERCENT"
+		request.StopPriceOffset = order.TrailPercent
+	} else {
+		request.StopPriceLinkType = "VALUE"
+		request.StopPriceOffset = order.TrailAmount
+	}
+
+	if order.OrderType == broker.TrailingStopLimit {
+		request.PriceLinkBasis = "TRIGGER"
+		request.PriceLinkType = "VALUE"
+		request.PriceOffset = order.LimitOffset
+	}
 }
 
 func toBrokerOrder(resp schwabOrderResponse) broker.Order {
@@ -160,9 +205,19 @@ func toBrokerOrder(resp schwabOrderResponse) broker.Order {
 		StopPrice:  resp.StopPrice,
 	}
 
+	if order.OrderType.IsTrailing() {
+		if resp.StopPriceLinkType == "PERCENT" {
+			order.TrailPercent = resp.StopPriceOffset
+		} else {
+			order.TrailAmount = resp.StopPriceOffset
+		}
+
+		order.LimitOffset = resp.PriceOffset
+	}
+
 	if len(resp.OrderLegCollection) > 0 {
 		leg := resp.OrderLegCollection[0]
-		order.Asset = asset.Asset{Ticker: leg.Instrument.Symbol}
+		order.Asset = assetFromSchwabInstrument(leg.Instrument)
 		order.Qty = leg.Quantity
 		order.Side = mapSchwabSide(leg.Instruction)
 	}
@@ -176,13 +231,17 @@ func toBrokerPosition(resp schwabPositionEntry) broker.Position {
 		qty = -resp.ShortQuantity
 	}
 
+	held := assetFromSchwabInstrument(resp.Instrument)
+
+	// Market value is in dollars; an option's mark is per share of
+	// underlying, like its average price.
 	markPrice := 0.0
 	if qty != 0 {
-		markPrice = resp.MarketValue / qty
+		markPrice = resp.MarketValue / qty / held.ContractMultiplier()
 	}
 
 	return broker.Position{
-		Asset:         asset.Asset{Ticker: resp.Instrument.Symbol},
+		Asset:         held,
 		Qty:           qty,
 		AvgOpenPrice:  resp.AveragePrice,
 		MarkPrice:     markPrice,
@@ -203,6 +262,59 @@ func toBrokerBalance(resp schwabAccountResponse) broker.Balance {
 
 // --- Mapping helpers ---
 
+// occSuffixLen is the length of an OCC option symbol after the root:
+// the expiry, the right, and eight strike digits.
+const occSuffixLen = 15
+
+// toSchwabInstrument returns the Schwab instrument for an asset. Options
+// use the OCC symbol with the root space-padded to six characters.
+func toSchwabInstrument(held asset.Asset) schwabInstrument {
+	if !held.IsOption() {
+		return schwabInstrument{Symbol: held.Ticker, AssetType: "EQUITY"}
+	}
+
+	symbol := held.Ticker
+	if len(symbol) > occSuffixLen {
+		split := len(symbol) - occSuffixLen
+		symbol = fmt.Sprintf("%-6s%s", symbol[:split], symbol[split:])
+	}
+
+	return schwabInstrument{Symbol: symbol, AssetType: "OPTION"}
+}
+
+// assetFromSchwabInstrument creates an asset from a Schwab instrument.
+// Options become option assets identified by their unpadded OCC symbol.
+func assetFromSchwabInstrument(instrument schwabInstrument) asset.Asset {
+	if instrument.AssetType == "OPTION" {
+		if option, ok := asset.ParseOCCSymbol(instrument.Symbol); ok {
+			return option
+		}
+	}
+
+	return asset.Asset{Ticker: instrument.Symbol}
+}
+
+// optionInstruction returns the Schwab instruction for an option order
+// given the contracts held before it: a buy covers a short position with
+// BUY_TO_CLOSE and otherwise opens with BUY_TO_OPEN; a sell closes a
+// long position with SELL_TO_CLOSE and otherwise opens a short with
+// SELL_TO_OPEN.
+func optionInstruction(side broker.Side, heldQty float64) string {
+	if side == broker.Sell {
+		if heldQty > 0 {
+			return "SELL_TO_CLOSE"
+		}
+
+		return "SELL_TO_OPEN"
+	}
+
+	if heldQty < 0 {
+		return "BUY_TO_CLOSE"
+	}
+
+	return "BUY_TO_OPEN"
+}
+
 func mapOrderType(orderType broker.OrderType) string {
 	switch orderType {
 	case broker.Market:
@@ -213,6 +325,10 @@ func mapOrderType(orderType broker.OrderType) string {
 		return "STOP"
 	case broker.StopLimit:
 		return "STOP_LIMIT"
+	case broker.TrailingStop:
+		return "TRAILING_STOP"
+	case broker.TrailingStopLimit:
+		return "TRAILING_STOP_LIMIT"
 	default:
 		return "MARKET"
 	}
@@ -228,6 +344,10 @@ func mapSchwabOrderType(orderType string) broker.OrderType {
 		return broker.Stop
 	case "STOP_LIMIT":
 		return broker.StopLimit
+	case "TRAILING_STOP":
+		return broker.TrailingStop
+	case "TRAILING_STOP_LIMIT":
+		return broker.TrailingStopLimit
 	default:
 		return broker.Market
 	}
@@ -246,9 +366,9 @@ func mapSide(side broker.Side) string {
 
 func mapSchwabSide(instruction string) broker.Side {
 	switch instruction {
-	case "BUY", "BUY_TO_COVER":
+	case "BUY", "BUY_TO_COVER", "BUY_TO_OPEN", "BUY_TO_CLOSE":
 		return broker.Buy
-	case "SELL", "SELL_SHORT":
+	case "SELL", "SELL_SHORT", "SELL_TO_OPEN", "SELL_TO_CLOSE":
 		return broker.Sell
 	default:
 		return broker.Buy
@@ -311,13 +431,35 @@ func mapLotSelection(lotSelection int) string {
 
 // --- Bracket/OCO order builders ---
 
-func buildBracketOrder(orders []broker.Order) (schwabOrderRequest, error) {
+// buildBracketOrder nests the exits of a bracket under its entry.
+// heldQty is the position before the entry; the exits are translated
+// against the position the entry leaves, so option exits close it.
+func buildBracketOrder(orders []broker.Order, heldQty float64) (schwabOrderRequest, error) {
 	var entryOrder *schwabOrderRequest
 
 	var contingentOrders []schwabOrderRequest
 
+	afterEntry := heldQty
+
+	for _, order := range orders {
+		if order.GroupRole != broker.RoleEntry {
+			continue
+		}
+
+		if order.Side == broker.Sell {
+			afterEntry -= order.Qty
+		} else {
+			afterEntry += order.Qty
+		}
+	}
+
 	for _, order := range orders {
-		schwabOrd, tifErr := toSchwabOrder(order)
+		legHeld := afterEntry
+		if order.GroupRole == broker.RoleEntry {
+			legHeld = heldQty
+		}
+
+		schwabOrd, tifErr := toSchwabOrder(order, legHeld)
 		if tifErr != nil {
 			return schwabOrderRequest{}, tifErr
 		}
@@ -348,11 +490,13 @@ func buildBracketOrder(orders []broker.Order) (schwabOrderRequest, error) {
 	return *entryOrder, nil
 }
 
-func buildOCOOrder(orders []broker.Order) (schwabOrderRequest, error) {
+// buildOCOOrder groups orders as one-cancels-other children. heldQty is
+// the position in their asset.
+func buildOCOOrder(orders []broker.Order, heldQty float64) (schwabOrderRequest, error) {
 	children := make([]schwabOrderRequest, len(orders))
 
 	for idx, order := range orders {
-		schwabOrd, tifErr := toSchwabOrder(order)
+		schwabOrd, tifErr := toSchwabOrder(order, heldQty)
 		if tifErr != nil {
 			return schwabOrderRequest{}, tifErr
 		}
@@ -1,6 +1,8 @@
 package schwab
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -20,7 +22,7 @@ var _ = Describe("Types", Label("translation"), func() {
 				TimeInForce: broker.Day,
 			}
 
-			result, translateErr := toSchwabOrder(order)
+			result, translateErr := toSchwabOrder(order, 0)
 			Expect(translateErr).ToNot(HaveOccurred())
 
 			Expect(result.OrderType).To(Equal("MARKET"))
@@ -46,7 +48,7 @@ var _ = Describe("Types", Label("translation"), func() {
 				TimeInForce: broker.GTC,
 			}
 
-			result, translateErr := toSchwabOrder(order)
+			result, translateErr := toSchwabOrder(order, 0)
 			Expect(translateErr).ToNot(HaveOccurred())
 
 			Expect(result.OrderType).To(Equal("LIMIT"))
@@ -65,7 +67,7 @@ var _ = Describe("Types", Label("translation"), func() {
 				TimeInForce: broker.Day,
 			}
 
-			result, translateErr := toSchwabOrder(order)
+			result, translateErr := toSchwabOrder(order, 0)
 			Expect(translateErr).ToNot(HaveOccurred())
 
 			Expect(result.OrderType).To(Equal("STOP"))
@@ -84,7 +86,7 @@ var _ = Describe("Types", Label("translation"), func() {
 				TimeInForce: broker.Day,
 			}
 
-			result, translateErr := toSchwabOrder(order)
+			result, translateErr := toSchwabOrder(order, 0)
 			Expect(translateErr).ToNot(HaveOccurred())
 
 			Expect(result.OrderType).To(Equal("STOP_LIMIT"))
@@ -92,6 +94,30 @@ var _ = Describe("Types", Label("translation"), func() {
 			Expect(result.Price).To(Equal(155.0))
 		})
 
+		It("translates a trailing stop-limit order into price links", func() {
+			order := broker.Order{
+				Asset:        asset.Asset{Ticker: "MSFT"},
+				Side:         broker.Sell,
+				Qty:          20,
+				OrderType:    broker.TrailingStopLimit,
+				StopPrice:    400.0,
+				TrailPercent: 3,
+				LimitOffset:  0.5,
+				TimeInForce:  broker.GTC,
+			}
+
+			result, translateErr := toSchwabOrder(order, 0)
+			Expect(translateErr).ToNot(HaveOccurred())
+
+			Expect(result.OrderType).To(Equal("TRAILING_STOP_LIMIT"))
+			Expect(result.StopPrice).To(BeZero())
+			Expect(result.StopPriceLinkBasis).To(Equal("LAST"))
+			Expect(result.StopPriceLinkType).To(Equal("PERCENT"))
+			Expect(result.StopPriceOffset).To(Equal(3.0))
+			Expect(result.PriceLinkBasis).To(Equal("TRIGGER"))
+			Expect(result.PriceOffset).To(Equal(0.5))
+		})
+
 		It("maps LotSelection to taxLotMethod", func() {
 			for _, testCase := range []struct {
 				lotSelection int
@@ -110,7 +136,7 @@ var _ = Describe("Types", Label("translation"), func() {
 					TimeInForce:  broker.Day,
 					LotSelection: testCase.lotSelection,
 				}
-				result, translateErr := toSchwabOrder(order)
+				result, translateErr := toSchwabOrder(order, 0)
 				Expect(translateErr).ToNot(HaveOccurred())
 				Expect(result.TaxLotMethod).To(Equal(testCase.expected), "for LotSelection %d", testCase.lotSelection)
 			}
@@ -133,7 +159,7 @@ var _ = Describe("Types", Label("translation"), func() {
 					OrderType:   broker.Market,
 					TimeInForce: testCase.tif,
 				}
-				result, translateErr := toSchwabOrder(order)
+				result, translateErr := toSchwabOrder(order, 0)
 				Expect(translateErr).ToNot(HaveOccurred())
 				Expect(result.Duration).To(Equal(testCase.expect), "for TIF %d", testCase.tif)
 			}
@@ -148,10 +174,43 @@ var _ = Describe("Types", Label("translation"), func() {
 					OrderType:   broker.Market,
 					TimeInForce: tif,
 				}
-				_, translateErr := toSchwabOrder(order)
+				_, translateErr := toSchwabOrder(order, 0)
 				Expect(translateErr).To(HaveOccurred(), "for TIF %d", tif)
 			}
 		})
+
+		It("translates option orders with the padded OCC symbol and an open or close instruction", func() {
+			order := broker.Order{
+				Asset: asset.NewOption(asset.Asset{Ticker: "SPY"}, asset.Call, 450,
+					time.Date(2026, 12, 18, 0, 0, 0, 0, time.UTC), 0),
+				Side:        broker.Buy,
+				Qty:         2,
+				OrderType:   broker.Limit,
+				LimitPrice:  3.25,
+				TimeInForce: broker.Day,
+			}
+
+			result, translateErr := toSchwabOrder(order, 0)
+			Expect(translateErr).ToNot(HaveOccurred())
+			Expect(result.OrderLegCollection).To(HaveLen(1))
+			Expect(result.OrderLegCollection[0].Instrument).To(Equal(schwabInstrument{
+				Symbol: "SPY   261218C00450000", AssetType: "OPTION",
+			}))
+			Expect(result.OrderLegCollection[0].Instruction).To(Equal("BUY_TO_OPEN"))
+			Expect(result.OrderLegCollection[0].Quantity).To(Equal(2.0))
+			Expect(result.Price).To(Equal(3.25))
+
+			result, _ = toSchwabOrder(order, -2)
+			Expect(result.OrderLegCollection[0].Instruction).To(Equal("BUY_TO_CLOSE"))
+
+			order.Side = broker.Sell
+
+			result, _ = toSchwabOrder(order, 2)
+			Expect(result.OrderLegCollection[0].Instruction).To(Equal("SELL_TO_CLOSE"))
+
+			result, _ = toSchwabOrder(order, 0)
+			Expect(result.OrderLegCollection[0].Instruction).To(Equal("SELL_TO_OPEN"))
+		})
 	})
 
 	Describe("toBrokerOrder", func() {
@@ -259,6 +318,25 @@ var _ = Describe("Types", Label("translation"), func() {
 			Expect(result.Qty).To(Equal(-50.0))
 			Expect(result.MarkPrice).To(Equal(-190.0))
 		})
+
+		It("reads an option position back as an option asset marked per share", func() {
+			resp := schwabPositionEntry{
+				Instrument: schwabInstrument{
+					Symbol:    "SPY   261218C00450000",
+					AssetType: "OPTION",
+				},
+				LongQuantity: 3,
+				AveragePrice: 4.10,
+				MarketValue:  1500.0,
+			}
+
+			result := toBrokerPosition(resp)
+
+			Expect(result.Asset.Ticker).To(Equal("SPY261218C00450000"))
+			Expect(result.Asset.IsOption()).To(BeTrue())
+			Expect(result.Qty).To(Equal(3.0))
+			Expect(result.MarkPrice).To(BeNumerically("~", 5.0, 1e-9))
+		})
 	})
 
 	Describe("toBrokerBalance", func() {
@@ -291,6 +369,8 @@ var _ = Describe("Types", Label("translation"), func() {
 			Expect(mapOrderType(broker.Limit)).To(Equal("LIMIT"))
 			Expect(mapOrderType(broker.Stop)).To(Equal("STOP"))
 			Expect(mapOrderType(broker.StopLimit)).To(Equal("STOP_LIMIT"))
+			Expect(mapOrderType(broker.TrailingStop)).To(Equal("TRAILING_STOP"))
+			Expect(mapOrderType(broker.TrailingStopLimit)).To(Equal("TRAILING_STOP_LIMIT"))
 		})
 	})
 
@@ -300,6 +380,7 @@ var _ = Describe("Types", Label("translation"), func() {
 			Expect(mapSchwabOrderType("LIMIT")).To(Equal(broker.Limit))
 			Expect(mapSchwabOrderType("STOP")).To(Equal(broker.Stop))
 			Expect(mapSchwabOrderType("STOP_LIMIT")).To(Equal(broker.StopLimit))
+			Expect(mapSchwabOrderType("TRAILING_STOP")).To(Equal(broker.TrailingStop))
 			Expect(mapSchwabOrderType("UNKNOWN")).To(Equal(broker.Market))
 		})
 	})
@@ -398,7 +479,7 @@ var _ = Describe("Types", Label("translation"), func() {
 				{Asset: asset.Asset{Ticker: "SPY"}, Side: broker.Sell, Qty: 5, OrderType: broker.Stop, StopPrice: 430.0, TimeInForce: broker.GTC, GroupRole: broker.RoleStopLoss},
 			}
 
-			result, buildErr := buildBracketOrder(orders)
+			result, buildErr := buildBracketOrder(orders, 0)
 
 			Expect(buildErr).ToNot(HaveOccurred())
 			Expect(result.OrderStrategyType).To(Equal("TRIGGER"))
@@ -417,17 +498,39 @@ var _ = Describe("Types", Label("translation"), func() {
 				{Asset: asset.Asset{Ticker: "SPY"}, Side: broker.Sell, Qty: 5, OrderType: broker.Stop, StopPrice: 430.0, TimeInForce: broker.GTC, GroupRole: broker.RoleStopLoss},
 			}
 
-			_, buildErr := buildBracketOrder(orders)
+			_, buildErr := buildBracketOrder(orders, 0)
 			Expect(buildErr).To(MatchError(broker.ErrNoEntryOrder))
 		})
 
+		It("closes the position an option entry opens", func() {
+			call := asset.NewOption(asset.Asset{Ticker: "SPY"}, asset.Call, 450,
+				time.Date(2026, 12, 18, 0, 0, 0, 0, time.UTC), 0)
+			orders := []broker.Order{
+				{Asset: call, Side: broker.Buy, Qty: 2, OrderType: broker.Market, TimeInForce: broker.Day, GroupRole: broker.RoleEntry},
+				{Asset: call, Side: broker.Sell, Qty: 2, OrderType: broker.Limit, LimitPrice: 6.0, TimeInForce: broker.GTC, GroupRole: broker.RoleTakeProfit},
+				{Asset: call, Side: broker.Sell, Qty: 2, OrderType: broker.Stop, StopPrice: 2.0, TimeInForce: broker.GTC, GroupRole: broker.RoleStopLoss},
+			}
+
+			result, buildErr := buildBracketOrder(orders, 0)
+			Expect(buildErr).ToNot(HaveOccurred())
+			Expect(result.OrderLegCollection[0].Instruction).To(Equal("BUY_TO_OPEN"))
+
+			exits := result.ChildOrderStrategies[0].ChildOrderStrategies
+			Expect(exits).To(HaveLen(2))
+
+			for _, exit := range exits {
+				Expect(exit.OrderLegCollection[0].Instruction).To(Equal("SELL_TO_CLOSE"))
+				Expect(exit.OrderLegCollection[0].Instrument.AssetType).To(Equal("OPTION"))
+			}
+		})
+
 		It("returns ErrMultipleEntryOrders when two entries are present", func() {
 			orders := []broker.Order{
 				{Asset: asset.Asset{Ticker: "SPY"}, Side: broker.Buy, Qty: 5, OrderType: broker.Market, TimeInForce: broker.Day, GroupRole: broker.RoleEntry},
 				{Asset: asset.Asset{Ticker: "SPY"}, Side: broker.Buy, Qty: 5, OrderType: broker.Market, TimeInForce: broker.Day, GroupRole: broker.RoleEntry},
 			}
 
-			_, buildErr := buildBracketOrder(orders)
+			_, buildErr := buildBracketOrder(orders, 0)
 			Expect(buildErr).To(MatchError(broker.ErrMultipleEntryOrders))
 		})
 	})
@@ -439,7 +542,7 @@ var _ = Describe("Types", Label("translation"), func() {
 				{Asset: asset.Asset{Ticker: "AAPL"}, Side: broker.Sell, Qty: 10, OrderType: broker.Limit, LimitPrice: 160.0, TimeInForce: broker.GTC},
 			}
 
-			result, buildErr := buildOCOOrder(orders)
+			result, buildErr := buildOCOOrder(orders, 0)
 			Expect(buildErr).ToNot(HaveOccurred())
 
 			Expect(result.OrderStrategyType).To(Equal("OCO"))
@@ -25,6 +25,8 @@ type TastytradeBroker struct {
 	streamer        *fillStreamer
 	fills           chan broker.Fill
 	sandbox         bool
+	account         string
+	getenv          func(string) string
 	complexOrderIDs map[string]string // maps child order ID -> complex order ID
 	mu              sync.Mutex
 }
@@ -39,10 +41,19 @@ func WithSandbox() Option {
 	}
 }
 
+// WithAccountNumber selects the account to trade. Without it the first
+// account on the login is used.
+func WithAccountNumber(accountNumber string) Option {
+	return func(ttBroker *TastytradeBroker) {
+		ttBroker.account = accountNumber
+	}
+}
+
 // New creates a new TastytradeBroker with the given options.
 func New(opts ...Option) *TastytradeBroker {
 	ttBroker := &TastytradeBroker{
 		fills:           make(chan broker.Fill, fillChannelSize),
+		getenv:          os.Getenv,
 		complexOrderIDs: make(map[string]string),
 	}
 
@@ -61,13 +72,15 @@ func New(opts ...Option) *TastytradeBroker {
 }
 
 func (ttBroker *TastytradeBroker) Connect(ctx context.Context) error {
-	username := os.Getenv("TASTYTRADE_USERNAME")
+	username := ttBroker.getenv("TASTYTRADE_USERNAME")
 
-	password := os.Getenv("TASTYTRADE_PASSWORD")
+	password := ttBroker.getenv("TASTYTRADE_PASSWORD")
 	if username == "" || password == "" {
 		return ErrMissingCredentials
 	}
 
+	ttBroker.client.desiredAccount = ttBroker.account
+
 	if err := ttBroker.client.authenticate(ctx, username, password); err != nil {
 		return fmt.Errorf("tastytrade: connect: %w", err)
 	}
@@ -103,16 +116,22 @@ func (ttBroker *TastytradeBroker) Fills() <-chan broker.Fill {
 }
 
 func (ttBroker *TastytradeBroker) Submit(ctx context.Context, order broker.Order) error {
+	if order.OrderType.IsTrailing() {
+		return fmt.Errorf("tastytrade: trailing stop: %w", broker.ErrUnsupportedOrder)
+	}
+
 	ttBroker.mu.Lock()
 	defer ttBroker.mu.Unlock()
 
 	qty := order.Qty
 	if qty == 0 && order.Amount > 0 {
-		price, err := ttBroker.client.getQuote(ctx, order.Asset.Ticker)
+		price, err := ttBroker.client.getQuote(ctx, toTTSymbol(order.Asset))
 		if err != nil {
 			return fmt.Errorf("tastytrade: fetching quote for %s: %w", order.Asset.Ticker, err)
 		}
 
+		price *= order.Asset.ContractMultiplier()
+
 		qty = math.Floor(order.Amount / price)
 		if qty == 0 {
 			return fmt.Errorf("tastytrade: order for %s: amount %.2f is less than the share price %.2f",
@@ -215,6 +234,10 @@ func (ttBroker *TastytradeBroker) SubmitGroup(ctx context.Context, orders []brok
 		return ErrEmptyOrderGroup
 	}
 
+	if err := broker.CheckGroupLegs(orders); err != nil {
+		return fmt.Errorf("tastytrade: %w", err)
+	}
+
 	ttBroker.mu.Lock()
 	defer ttBroker.mu.Unlock()
 
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"sync/atomic"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -255,6 +256,72 @@ var _ = Describe("TastytradeBroker", func() {
 			Expect(err.Error()).To(ContainSubstring("less than the share price"))
 			Expect(submitCalled.Load()).To(Equal(int32(0)))
 		})
+
+		It("submits option orders as equity options sized by the contract multiplier", func() {
+			var (
+				quoteQuery   string
+				receivedBody map[string]any
+			)
+
+			ttBroker := authenticatedBroker(func(mux *http.ServeMux) {
+				mux.HandleFunc("GET /accounts/ACCT-001/positions", func(writer http.ResponseWriter, req *http.Request) {
+					writer.Header().Set("Content-Type", "application/json")
+					sonic.ConfigDefault.NewEncoder(writer).Encode(map[string]any{
+						"data": map[string]any{
+							"items": []map[string]any{
+								{"symbol": "SPY   261218C00450000", "quantity": 5.0},
+							},
+						},
+					})
+				})
+
+				mux.HandleFunc("GET /market-data/by-type", func(writer http.ResponseWriter, req *http.Request) {
+					quoteQuery = req.URL.Query().Get("equity-option")
+					writer.Header().Set("Content-Type", "application/json")
+					sonic.ConfigDefault.NewEncoder(writer).Encode(map[string]any{
+						"data": map[string]any{
+							"items": []map[string]any{
+								{"symbol": "SPY   261218C00450000", "last": 2.5},
+							},
+						},
+					})
+				})
+
+				mux.HandleFunc("POST /accounts/ACCT-001/orders", func(writer http.ResponseWriter, req *http.Request) {
+					sonic.ConfigDefault.NewDecoder(req.Body).Decode(&receivedBody)
+					writer.Header().Set("Content-Type", "application/json")
+					sonic.ConfigDefault.NewEncoder(writer).Encode(map[string]any{
+						"data": map[string]any{
+							"order": map[string]any{
+								"id":     "ORD-OPT-1",
+								"status": "Received",
+							},
+						},
+					})
+				})
+			})
+
+			call := asset.NewOption(asset.Asset{Ticker: "SPY"}, asset.Call, 450,
+				time.Date(2026, 12, 18, 0, 0, 0, 0, time.UTC), 0)
+
+			err := ttBroker.Submit(ctx, broker.Order{
+				Asset:       call,
+				Side:        broker.Sell,
+				Amount:      800,
+				OrderType:   broker.Market,
+				TimeInForce: broker.Day,
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(quoteQuery).To(Equal("SPY   261218C00450000"))
+
+			legs := receivedBody["legs"].([]any)
+			firstLeg := legs[0].(map[string]any)
+			Expect(firstLeg["instrument-type"]).To(Equal("Equity Option"))
+			Expect(firstLeg["symbol"]).To(Equal("SPY   261218C00450000"))
+			Expect(firstLeg["action"]).To(Equal("Sell to Close"))
+			Expect(firstLeg["quantity"]).To(BeNumerically("==", 3)) // floor(800 / (2.5 * 100))
+		})
 	})
 
 	Describe("Cancel", Label("orders"), func() {
@@ -496,6 +563,15 @@ var _ = Describe("TastytradeBroker", func() {
 			Expect(err).To(MatchError(tastytrade.ErrEmptyOrderGroup))
 		})
 
+		It("rejects a trailing bracket exit with ErrUnsupportedOrder", func() {
+			ttBroker := authenticatedBroker(nil)
+			err := ttBroker.SubmitGroup(ctx, []broker.Order{
+				{Asset: asset.Asset{Ticker: "AAPL"}, Side: broker.Sell, Qty: 10, OrderType: broker.TrailingStop, TrailAmount: 2.0, StopPrice: 148.0, TimeInForce: broker.GTC, GroupRole: broker.RoleStopLoss},
+				{Asset: asset.Asset{Ticker: "AAPL"}, Side: broker.Sell, Qty: 10, OrderType: broker.Limit, LimitPrice: 160.0, TimeInForce: broker.GTC, GroupRole: broker.RoleTakeProfit},
+			}, broker.GroupOCO)
+			Expect(err).To(MatchError(broker.ErrUnsupportedOrder))
+		})
+
 		It("returns ErrNoEntryOrder when OTOCO has no entry", func() {
 			ttBroker := authenticatedBroker(nil)
 			err := ttBroker.SubmitGroup(ctx, []broker.Order{
@@ -544,6 +620,29 @@ var _ = Describe("TastytradeBroker", func() {
 			Expect(positions[0].AvgOpenPrice).To(Equal(450.0))
 			Expect(positions[0].MarkPrice).To(Equal(475.0))
 		})
+
+		It("reads option positions back as option assets", func() {
+			ttBroker := authenticatedBroker(func(mux *http.ServeMux) {
+				mux.HandleFunc("GET /accounts/ACCT-001/positions", func(writer http.ResponseWriter, req *http.Request) {
+					writer.Header().Set("Content-Type", "application/json")
+					sonic.ConfigDefault.NewEncoder(writer).Encode(map[string]any{
+						"data": map[string]any{
+							"items": []map[string]any{
+								{"symbol": "SPY   261218P00400000", "quantity": -2.0},
+							},
+						},
+					})
+				})
+			})
+
+			positions, err := ttBroker.Positions(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(positions).To(HaveLen(1))
+			Expect(positions[0].Asset.IsOption()).To(BeTrue())
+			Expect(positions[0].Asset.Ticker).To(Equal("SPY261218P00400000"))
+			Expect(positions[0].Asset.Contract.Strike).To(Equal(400.0))
+			Expect(positions[0].Qty).To(Equal(-2.0))
+		})
 	})
 
 	Describe("Balance", func() {
@@ -9,14 +9,16 @@ import (
 	"time"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/penny-vault/pvbt/asset"
 )
 
 type apiClient struct {
-	resty     *resty.Client
-	accountID string
-	username  string
-	password  string
-	mu        sync.Mutex // protects re-authentication
+	resty          *resty.Client
+	accountID      string
+	desiredAccount string // account number to select; empty selects the first
+	username       string
+	password       string
+	mu             sync.Mutex // protects re-authentication
 }
 
 // newAPIClient creates a new apiClient configured with retry and default headers.
@@ -87,9 +89,21 @@ func (client *apiClient) authenticate(ctx context.Context, username, password st
 		return ErrAccountNotFound
 	}
 
-	client.accountID = accounts.Data.Items[0].Account.AccountNumber
+	if client.desiredAccount == "" {
+		client.accountID = accounts.Data.Items[0].Account.AccountNumber
 
-	return nil
+		return nil
+	}
+
+	for _, item := range accounts.Data.Items {
+		if item.Account.AccountNumber == client.desiredAccount {
+			client.accountID = item.Account.AccountNumber
+
+			return nil
+		}
+	}
+
+	return ErrAccountNotFound
 }
 
 // submitOrder sends an order and returns the order ID.
@@ -272,9 +286,15 @@ func (client *apiClient) account() string {
 	return client.accountID
 }
 
-// getQuote retrieves the last price for a symbol.
+// getQuote retrieves the last price for a symbol. OCC option symbols are
+// quoted as equity options.
 func (client *apiClient) getQuote(ctx context.Context, symbol string) (float64, error) {
-	endpoint := "/market-data/by-type?equity=" + url.QueryEscape(symbol)
+	instrument := "equity"
+	if _, isOption := asset.ParseOCCSymbol(symbol); isOption {
+		instrument = "equity-option"
+	}
+
+	endpoint := "/market-data/by-type?" + instrument + "=" + url.QueryEscape(symbol)
 
 	var result quoteResponse
 
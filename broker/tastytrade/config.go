@@ -0,0 +1,43 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tastytrade
+
+import "github.com/penny-vault/pvbt/broker"
+
+func init() {
+	broker.Register("tastytrade", NewFromConfig)
+}
+
+// NewFromConfig creates a TastytradeBroker from a `[broker.tastytrade]`
+// config section. Recognized settings are username and password,
+// overriding TASTYTRADE_USERNAME and TASTYTRADE_PASSWORD. Account
+// selects the account number and Paper selects the sandbox.
+func NewFromConfig(cfg broker.Config) (broker.Broker, error) {
+	var opts []Option
+
+	if cfg.Paper {
+		opts = append(opts, WithSandbox())
+	}
+
+	if cfg.Account != "" {
+		opts = append(opts, WithAccountNumber(cfg.Account))
+	}
+
+	ttBroker := New(opts...)
+	ttBroker.getenv = cfg.Getenv
+
+	return ttBroker, nil
+}
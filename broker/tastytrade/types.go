@@ -2,7 +2,9 @@ package tastytrade
 
 import (
 	"encoding/json"
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/penny-vault/pvbt/asset"
 	"github.com/penny-vault/pvbt/broker"
@@ -162,8 +164,8 @@ func toTastytradeOrder(order broker.Order, action string) orderRequest {
 		AutomatedSource: true,
 		Legs: []orderLeg{
 			{
-				InstrumentType: "Equity",
-				Symbol:         order.Asset.Ticker,
+				InstrumentType: instrumentType(order.Asset),
+				Symbol:         toTTSymbol(order.Asset),
 				Action:         action,
 				Quantity:       order.Qty,
 			},
@@ -182,7 +184,7 @@ func toBrokerOrder(resp orderResponse) broker.Order {
 
 	if len(resp.Legs) > 0 {
 		leg := resp.Legs[0]
-		order.Asset = asset.Asset{Ticker: leg.Symbol}
+		order.Asset = assetFromTTSymbol(leg.Symbol)
 		order.Qty = leg.Quantity
 		order.Side = mapTTSide(leg.Action)
 	}
@@ -192,7 +194,7 @@ func toBrokerOrder(resp orderResponse) broker.Order {
 
 func toBrokerPosition(resp positionResponse) broker.Position {
 	return broker.Position{
-		Asset:         asset.Asset{Ticker: resp.Symbol},
+		Asset:         assetFromTTSymbol(resp.Symbol),
 		Qty:           resp.Quantity,
 		AvgOpenPrice:  resp.AveragePrice,
 		MarkPrice:     resp.MarkPrice,
@@ -220,6 +222,41 @@ func parseLegFillQuantity(quantity string) float64 {
 
 // --- Mapping helpers ---
 
+// occSuffixLen is the length of an OCC option symbol after the root:
+// the expiry, the right, and eight strike digits.
+const occSuffixLen = 15
+
+// instrumentType returns the tastytrade instrument type for an asset.
+func instrumentType(held asset.Asset) string {
+	if held.IsOption() {
+		return "Equity Option"
+	}
+
+	return "Equity"
+}
+
+// toTTSymbol returns the tastytrade symbol for an asset. Options use the
+// OCC symbol with the root space-padded to six characters.
+func toTTSymbol(held asset.Asset) string {
+	if !held.IsOption() || len(held.Ticker) <= occSuffixLen {
+		return held.Ticker
+	}
+
+	split := len(held.Ticker) - occSuffixLen
+
+	return fmt.Sprintf("%-6s%s", held.Ticker[:split], held.Ticker[split:])
+}
+
+// assetFromTTSymbol creates an asset from a tastytrade symbol. OCC option
+// symbols become option assets identified by their unpadded symbol.
+func assetFromTTSymbol(symbol string) asset.Asset {
+	if option, ok := asset.ParseOCCSymbol(symbol); ok {
+		return option
+	}
+
+	return asset.Asset{Ticker: symbol}
+}
+
 func mapPriceEffect(side broker.Side, orderType broker.OrderType) string {
 	if orderType == broker.Market {
 		return ""
@@ -238,12 +275,13 @@ func mapPriceEffect(side broker.Side, orderType broker.OrderType) string {
 // detectAction returns the tastytrade order action for the requested side,
 // accounting for the current position: an existing short is covered with
 // "Buy to Close" and a new short is opened with "Sell to Open"; long
-// positions use "Buy to Open" and "Sell to Close".
+// positions use "Buy to Open" and "Sell to Close". Position symbols are
+// compared without the space padding tastytrade uses in option symbols.
 func detectAction(side broker.Side, ticker string, positions []positionResponse) string {
 	var currentQty float64
 
 	for _, pos := range positions {
-		if pos.Symbol == ticker {
+		if strings.ReplaceAll(pos.Symbol, " ", "") == ticker {
 			currentQty = pos.Quantity
 			break
 		}
@@ -31,6 +31,7 @@ type TradeStationBroker struct {
 	callbackURL      string
 	sandbox          bool
 	desiredAccountID string
+	getenv           func(string) string
 	mu               sync.Mutex
 }
 
@@ -68,7 +69,8 @@ func WithAccountID(accountID string) Option {
 // New creates a new TradeStationBroker with the given options.
 func New(opts ...Option) *TradeStationBroker {
 	tsBroker := &TradeStationBroker{
-		fills: make(chan broker.Fill, fillChannelSize),
+		fills:  make(chan broker.Fill, fillChannelSize),
+		getenv: os.Getenv,
 	}
 
 	for _, opt := range opts {
@@ -85,8 +87,8 @@ func (tsBroker *TradeStationBroker) Fills() <-chan broker.Fill {
 
 // Connect authenticates with TradeStation and starts the order streamer.
 func (tsBroker *TradeStationBroker) Connect(ctx context.Context) error {
-	clientID := os.Getenv("TRADESTATION_CLIENT_ID")
-	clientSecret := os.Getenv("TRADESTATION_CLIENT_SECRET")
+	clientID := tsBroker.getenv("TRADESTATION_CLIENT_ID")
+	clientSecret := tsBroker.getenv("TRADESTATION_CLIENT_SECRET")
 
 	if clientID == "" || clientSecret == "" {
 		return broker.ErrMissingCredentials
@@ -94,12 +96,12 @@ func (tsBroker *TradeStationBroker) Connect(ctx context.Context) error {
 
 	callbackURL := tsBroker.callbackURL
 	if callbackURL == "" {
-		callbackURL = os.Getenv("TRADESTATION_CALLBACK_URL")
+		callbackURL = tsBroker.getenv("TRADESTATION_CALLBACK_URL")
 	}
 
 	tokenFile := tsBroker.tokenFile
 	if tokenFile == "" {
-		tokenFile = os.Getenv("TRADESTATION_TOKEN_FILE")
+		tokenFile = tsBroker.getenv("TRADESTATION_TOKEN_FILE")
 	}
 
 	tsBroker.auth = newTokenManager(clientID, clientSecret, callbackURL, tokenFile)
@@ -132,7 +134,7 @@ func (tsBroker *TradeStationBroker) Connect(ctx context.Context) error {
 	// Resolve the account ID.
 	desiredAccount := tsBroker.desiredAccountID
 	if desiredAccount == "" {
-		desiredAccount = os.Getenv("TRADESTATION_ACCOUNT_ID")
+		desiredAccount = tsBroker.getenv("TRADESTATION_ACCOUNT_ID")
 	}
 
 	accountID, resolveErr := tsBroker.client.resolveAccount(ctx, desiredAccount)
@@ -0,0 +1,45 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tradestation
+
+import "github.com/penny-vault/pvbt/broker"
+
+func init() {
+	broker.Register("tradestation", NewFromConfig)
+}
+
+// NewFromConfig creates a TradeStationBroker from a
+// `[broker.tradestation]` config section. Recognized settings are
+// client_id, client_secret, callback_url, token_file and account_id,
+// each overriding the matching TRADESTATION_* environment variable.
+// Account selects the account and Paper selects the simulation
+// environment.
+func NewFromConfig(cfg broker.Config) (broker.Broker, error) {
+	var opts []Option
+
+	if cfg.Paper {
+		opts = append(opts, WithSandbox())
+	}
+
+	if cfg.Account != "" {
+		opts = append(opts, WithAccountID(cfg.Account))
+	}
+
+	tsBroker := New(opts...)
+	tsBroker.getenv = cfg.Getenv
+
+	return tsBroker, nil
+}
@@ -21,6 +21,17 @@ type tsOrderRequest struct {
 	Route       string        `json:"Route"`
 	LimitPrice  string        `json:"LimitPrice,omitempty"`
 	StopPrice   string        `json:"StopPrice,omitempty"`
+
+	AdvancedOptions *tsAdvancedOptions `json:"AdvancedOptions,omitempty"`
+}
+
+type tsAdvancedOptions struct {
+	TrailingStop *tsTrailingStop `json:"TrailingStop,omitempty"`
+}
+
+type tsTrailingStop struct {
+	Amount  string `json:"Amount,omitempty"`
+	Percent string `json:"Percent,omitempty"`
 }
 
 type tsTimeInForce struct {
@@ -118,6 +129,10 @@ type tsStreamOrderEvent struct {
 // --- Translation functions ---
 
 func toTSOrder(order broker.Order, accountID string) (tsOrderRequest, error) {
+	if order.OrderType == broker.TrailingStopLimit {
+		return tsOrderRequest{}, fmt.Errorf("tradestation: trailing stop-limit: %w", broker.ErrUnsupportedOrder)
+	}
+
 	tif := mapTimeInForce(order.TimeInForce)
 
 	tsOrder := tsOrderRequest{
@@ -134,7 +149,18 @@ func toTSOrder(order broker.Order, accountID string) (tsOrderRequest, error) {
 		tsOrder.LimitPrice = fmt.Sprintf("%.2f", order.LimitPrice)
 	}
 
-	if order.StopPrice != 0 {
+	// TradeStation trails a StopMarket order through its advanced
+	// options; the stop price is computed by the exchange.
+	if order.OrderType == broker.TrailingStop {
+		trail := &tsTrailingStop{}
+		if order.TrailPercent > 0 {
+			trail.Percent = fmt.Sprintf("%g", order.TrailPercent)
+		} else {
+			trail.Amount = fmt.Sprintf("%.2f", order.TrailAmount)
+		}
+
+		tsOrder.AdvancedOptions = &tsAdvancedOptions{TrailingStop: trail}
+	} else if order.StopPrice != 0 {
 		tsOrder.StopPrice = fmt.Sprintf("%.2f", order.StopPrice)
 	}
 
@@ -191,7 +217,7 @@ func mapOrderType(orderType broker.OrderType) string {
 		return "Market"
 	case broker.Limit:
 		return "Limit"
-	case broker.Stop:
+	case broker.Stop, broker.TrailingStop:
 		return "StopMarket"
 	case broker.StopLimit:
 		return "StopLimit"
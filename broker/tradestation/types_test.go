@@ -92,6 +92,40 @@ var _ = Describe("Types", Label("translation"), func() {
 			Expect(result.LimitPrice).To(Equal("155.00"))
 		})
 
+		It("translates a trailing stop into a trailing StopMarket order", func() {
+			order := broker.Order{
+				Asset:       asset.Asset{Ticker: "AAPL"},
+				Side:        broker.Sell,
+				Qty:         10,
+				OrderType:   broker.TrailingStop,
+				StopPrice:   145.0,
+				TrailAmount: 5.0,
+				TimeInForce: broker.GTC,
+			}
+
+			result, translateErr := toTSOrder(order, "ACCT-123")
+			Expect(translateErr).ToNot(HaveOccurred())
+
+			Expect(result.OrderType).To(Equal("StopMarket"))
+			Expect(result.StopPrice).To(BeEmpty())
+			Expect(result.AdvancedOptions).ToNot(BeNil())
+			Expect(result.AdvancedOptions.TrailingStop.Amount).To(Equal("5.00"))
+		})
+
+		It("rejects trailing stop-limit orders", func() {
+			order := broker.Order{
+				Asset:       asset.Asset{Ticker: "AAPL"},
+				Side:        broker.Sell,
+				Qty:         10,
+				OrderType:   broker.TrailingStopLimit,
+				TrailAmount: 5.0,
+				TimeInForce: broker.GTC,
+			}
+
+			_, translateErr := toTSOrder(order, "ACCT-123")
+			Expect(translateErr).To(MatchError(broker.ErrUnsupportedOrder))
+		})
+
 		It("maps all supported time-in-force values", func() {
 			for _, testCase := range []struct {
 				tif    broker.TimeInForce
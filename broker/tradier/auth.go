@@ -62,14 +62,15 @@ type tokenManager struct {
 	stopOnce     sync.Once
 }
 
-// detectAuthMode inspects environment variables to determine how the broker
-// should authenticate. TRADIER_ACCESS_TOKEN takes priority over OAuth env vars.
-func detectAuthMode() (authMode, error) {
-	if os.Getenv("TRADIER_ACCESS_TOKEN") != "" {
+// detectAuthMode inspects environment variables, read through getenv, to
+// determine how the broker should authenticate. TRADIER_ACCESS_TOKEN takes
+// priority over OAuth env vars.
+func detectAuthMode(getenv func(string) string) (authMode, error) {
+	if getenv("TRADIER_ACCESS_TOKEN") != "" {
 		return authModeStatic, nil
 	}
 
-	if os.Getenv("TRADIER_CLIENT_ID") != "" && os.Getenv("TRADIER_CLIENT_SECRET") != "" {
+	if getenv("TRADIER_CLIENT_ID") != "" && getenv("TRADIER_CLIENT_SECRET") != "" {
 		return authModeOAuth, nil
 	}
 
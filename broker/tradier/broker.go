@@ -23,6 +23,8 @@ type TradierBroker struct {
 	sandbox     bool
 	tokenFile   string
 	callbackURL string
+	accountID   string
+	getenv      func(string) string
 }
 
 // Option configures a TradierBroker.
@@ -49,10 +51,19 @@ func WithCallbackURL(callbackURL string) Option {
 	}
 }
 
+// WithAccountID selects the account to trade, overriding
+// TRADIER_ACCOUNT_ID.
+func WithAccountID(accountID string) Option {
+	return func(tb *TradierBroker) {
+		tb.accountID = accountID
+	}
+}
+
 // New creates a new TradierBroker.
 func New(opts ...Option) *TradierBroker {
 	tb := &TradierBroker{
-		fills: make(chan broker.Fill, 1024),
+		fills:  make(chan broker.Fill, 1024),
+		getenv: os.Getenv,
 	}
 	for _, opt := range opts {
 		opt(tb)
@@ -68,30 +79,37 @@ func (tb *TradierBroker) Fills() <-chan broker.Fill {
 
 // Connect establishes an authenticated session with Tradier.
 func (tb *TradierBroker) Connect(ctx context.Context) error {
-	mode, modeErr := detectAuthMode()
+	mode, modeErr := detectAuthMode(tb.getenv)
 	if modeErr != nil {
 		return fmt.Errorf("tradier: connect: %w", modeErr)
 	}
 
-	accountID := os.Getenv("TRADIER_ACCOUNT_ID")
+	accountID := tb.accountID
+	if accountID == "" {
+		accountID = tb.getenv("TRADIER_ACCOUNT_ID")
+	}
+
 	if accountID == "" {
 		return fmt.Errorf("tradier: connect: %w", ErrMissingCredentials)
 	}
 
 	callbackURL := tb.callbackURL
 	if callbackURL == "" {
-		callbackURL = os.Getenv("TRADIER_CALLBACK_URL")
+		callbackURL = tb.getenv("TRADIER_CALLBACK_URL")
 	}
 
 	tokenFile := tb.tokenFile
 	if tokenFile == "" {
-		tokenFile = os.Getenv("TRADIER_TOKEN_FILE")
+		tokenFile = tb.getenv("TRADIER_TOKEN_FILE")
 	}
 
-	clientID := os.Getenv("TRADIER_CLIENT_ID")
-	clientSecret := os.Getenv("TRADIER_CLIENT_SECRET")
+	clientID := tb.getenv("TRADIER_CLIENT_ID")
+	clientSecret := tb.getenv("TRADIER_CLIENT_SECRET")
 
 	tb.auth = newTokenManager(mode, clientID, clientSecret, callbackURL, tokenFile)
+	if mode == authModeStatic {
+		tb.auth.staticToken = tb.getenv("TRADIER_ACCESS_TOKEN")
+	}
 
 	// Attempt to load existing OAuth tokens (ignored in static mode).
 	if mode == authModeOAuth {
@@ -193,6 +211,10 @@ func (tb *TradierBroker) Submit(ctx context.Context, order broker.Order) error {
 // submitLocked is the internal implementation of Submit. It must be called
 // with tb.mu already held.
 func (tb *TradierBroker) submitLocked(ctx context.Context, order broker.Order) error {
+	if order.OrderType.IsTrailing() {
+		return fmt.Errorf("tradier: trailing stop: %w", broker.ErrUnsupportedOrder)
+	}
+
 	// Handle dollar-amount orders.
 	if order.Qty == 0 && order.Amount > 0 {
 		price, quoteErr := tb.client.getQuote(ctx, order.Asset.Ticker)
@@ -200,6 +222,8 @@ func (tb *TradierBroker) submitLocked(ctx context.Context, order broker.Order) e
 			return fmt.Errorf("tradier: submit: get quote for dollar-amount order: %w", quoteErr)
 		}
 
+		price *= order.Asset.ContractMultiplier()
+
 		qty := math.Floor(order.Amount / price)
 		if qty == 0 {
 			return fmt.Errorf("tradier: submit: dollar-amount order for %s results in zero shares at price %.4f", order.Asset.Ticker, price)
@@ -220,6 +244,10 @@ func (tb *TradierBroker) submitLocked(ctx context.Context, order broker.Order) e
 	}
 
 	adjustedSide := detectSide(order.Side, order.Asset.Ticker, positions)
+	if order.Asset.IsOption() {
+		adjustedSide = detectOptionSide(order.Side, order.Asset.Ticker, positions)
+	}
+
 	params.Set("side", adjustedSide)
 
 	orderID, submitErr := tb.client.submitOrder(ctx, params)
@@ -276,6 +304,33 @@ func detectSide(side broker.Side, ticker string, positions []tradierPositionResp
 	}
 }
 
+// detectOptionSide returns the Tradier option side string: an order opens
+// a position unless it reduces one already held in the same contract.
+func detectOptionSide(side broker.Side, optionSymbol string, positions []tradierPositionResponse) string {
+	var currentQty float64
+
+	for _, pos := range positions {
+		if pos.Symbol == optionSymbol {
+			currentQty = pos.Quantity
+			break
+		}
+	}
+
+	if side == broker.Sell {
+		if currentQty > 0 {
+			return "sell_to_close"
+		}
+
+		return "sell_to_open"
+	}
+
+	if currentQty < 0 {
+		return "buy_to_close"
+	}
+
+	return "buy_to_open"
+}
+
 // Cancel requests cancellation of an open order.
 func (tb *TradierBroker) Cancel(ctx context.Context, orderID string) error {
 	tb.mu.Lock()
@@ -401,6 +456,10 @@ func (tb *TradierBroker) SubmitGroup(ctx context.Context, orders []broker.Order,
 		return ErrEmptyOrderGroup
 	}
 
+	if err := broker.CheckGroupLegs(orders); err != nil {
+		return fmt.Errorf("tradier: %w", err)
+	}
+
 	switch groupType {
 	case broker.GroupOCO:
 		return tb.submitOCO(ctx, orders)
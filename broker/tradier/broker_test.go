@@ -706,6 +706,15 @@ var _ = Describe("TradierBroker", func() {
 			Expect(err).To(MatchError(tradier.ErrEmptyOrderGroup))
 		})
 
+		It("rejects a trailing bracket exit with ErrUnsupportedOrder", func() {
+			tb := authenticatedBroker(nil)
+			err := tb.SubmitGroup(ctx, []broker.Order{
+				{Asset: asset.Asset{Ticker: "AAPL"}, Side: broker.Sell, Qty: 10, OrderType: broker.TrailingStop, TrailAmount: 2.0, StopPrice: 148.0, TimeInForce: broker.GTC, GroupRole: broker.RoleStopLoss},
+				{Asset: asset.Asset{Ticker: "AAPL"}, Side: broker.Sell, Qty: 10, OrderType: broker.Limit, LimitPrice: 160.0, TimeInForce: broker.GTC, GroupRole: broker.RoleTakeProfit},
+			}, broker.GroupOCO)
+			Expect(err).To(MatchError(broker.ErrUnsupportedOrder))
+		})
+
 		It("returns ErrNoEntryOrder when bracket has no entry", func() {
 			tb := authenticatedBroker(nil)
 			err := tb.SubmitGroup(ctx, []broker.Order{
@@ -0,0 +1,44 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tradier
+
+import "github.com/penny-vault/pvbt/broker"
+
+func init() {
+	broker.Register("tradier", NewFromConfig)
+}
+
+// NewFromConfig creates a TradierBroker from a `[broker.tradier]` config
+// section. Recognized settings are access_token, client_id,
+// client_secret, callback_url, token_file and account_id, each
+// overriding the matching TRADIER_* environment variable. Account
+// selects the account and Paper selects the sandbox.
+func NewFromConfig(cfg broker.Config) (broker.Broker, error) {
+	var opts []Option
+
+	if cfg.Paper {
+		opts = append(opts, WithSandbox())
+	}
+
+	if cfg.Account != "" {
+		opts = append(opts, WithAccountID(cfg.Account))
+	}
+
+	tb := New(opts...)
+	tb.getenv = cfg.Getenv
+
+	return tb, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/url"
+	"os"
 
 	"github.com/penny-vault/pvbt/broker"
 )
@@ -137,7 +138,7 @@ type TokenStore = tokenStore
 
 // DetectAuthMode exposes detectAuthMode for testing.
 func DetectAuthMode() (authMode, error) {
-	return detectAuthMode()
+	return detectAuthMode(os.Getenv)
 }
 
 // SaveTokens exposes saveTokens for testing.
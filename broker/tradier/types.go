@@ -19,6 +19,7 @@ type tradierOrderResponse struct {
 	ID                int64   `json:"id"`
 	Type              string  `json:"type"`
 	Symbol            string  `json:"symbol"`
+	OptionSymbol      string  `json:"option_symbol"`
 	Side              string  `json:"side"`
 	Quantity          float64 `json:"quantity"`
 	Status            string  `json:"status"`
@@ -160,8 +161,14 @@ func toTradierOrderParams(order broker.Order) (url.Values, error) {
 	}
 
 	params := url.Values{}
-	params.Set("class", "equity")
-	params.Set("symbol", order.Asset.Ticker)
+	if order.Asset.IsOption() {
+		params.Set("class", "option")
+		params.Set("symbol", order.Asset.Contract.Underlying)
+		params.Set("option_symbol", order.Asset.Ticker)
+	} else {
+		params.Set("class", "equity")
+		params.Set("symbol", order.Asset.Ticker)
+	}
 	params.Set("side", mapSide(order.Side))
 	params.Set("quantity", strconv.FormatFloat(order.Qty, 'f', -1, 64))
 	params.Set("type", mapOrderType(order.OrderType))
@@ -182,11 +189,17 @@ func toTradierOrderParams(order broker.Order) (url.Values, error) {
 	return params, nil
 }
 
-// toBrokerOrder maps a tradierOrderResponse to a broker.Order.
+// toBrokerOrder maps a tradierOrderResponse to a broker.Order. Option
+// orders are identified by their OCC option symbol.
 func toBrokerOrder(resp tradierOrderResponse) broker.Order {
+	symbol := resp.Symbol
+	if resp.OptionSymbol != "" {
+		symbol = resp.OptionSymbol
+	}
+
 	return broker.Order{
 		ID:          fmt.Sprintf("%d", resp.ID),
-		Asset:       assetFromSymbol(resp.Symbol),
+		Asset:       assetFromSymbol(symbol),
 		Side:        mapTradierSide(resp.Side),
 		Qty:         resp.Quantity,
 		Status:      mapTradierStatus(resp.Status),
@@ -282,9 +295,9 @@ func mapSide(side broker.Side) string {
 
 func mapTradierSide(side string) broker.Side {
 	switch side {
-	case "buy", "buy_to_cover":
+	case "buy", "buy_to_cover", "buy_to_open", "buy_to_close":
 		return broker.Buy
-	case "sell", "sell_short":
+	case "sell", "sell_short", "sell_to_open", "sell_to_close":
 		return broker.Sell
 	default:
 		return broker.Buy
@@ -340,7 +353,12 @@ func mapTradierStatus(status string) broker.OrderStatus {
 	}
 }
 
-// assetFromSymbol creates an asset.Asset from a ticker symbol.
+// assetFromSymbol creates an asset.Asset from a ticker symbol. OCC option
+// symbols become option assets.
 func assetFromSymbol(symbol string) asset.Asset {
+	if option, ok := asset.ParseOCCSymbol(symbol); ok {
+		return option
+	}
+
 	return asset.Asset{Ticker: symbol}
 }
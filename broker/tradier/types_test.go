@@ -2,6 +2,7 @@ package tradier_test
 
 import (
 	"encoding/json"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -72,6 +73,23 @@ var _ = Describe("Types", Label("translation"), func() {
 			Expect(params.Get("stop")).To(BeEmpty())
 		})
 
+		It("sends option orders with the option class and OCC symbol", func() {
+			spy := asset.Asset{CompositeFigi: "BBG000BDTBL9", Ticker: "SPY"}
+			order := broker.Order{
+				Asset:       asset.NewOption(spy, asset.Call, 500, time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC), 0),
+				Side:        broker.Sell,
+				Qty:         1,
+				OrderType:   broker.Market,
+				TimeInForce: broker.Day,
+			}
+
+			params, err := tradier.ToTradierOrderParams(order)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(params.Get("class")).To(Equal("option"))
+			Expect(params.Get("symbol")).To(Equal("SPY"))
+			Expect(params.Get("option_symbol")).To(Equal("SPY240621C00500000"))
+		})
+
 		It("translates a Limit order and includes price param", func() {
 			order := broker.Order{
 				Asset:       asset.Asset{Ticker: "MSFT"},
@@ -73,14 +73,15 @@ func (hs *hmacSigner) Sign(req *http.Request) error {
 	return nil
 }
 
-// detectAuthMode inspects environment variables to determine how the broker
-// should authenticate. WEBULL_APP_KEY takes priority over OAuth env vars.
-func detectAuthMode() (authMode, error) {
-	if os.Getenv("WEBULL_APP_KEY") != "" && os.Getenv("WEBULL_APP_SECRET") != "" {
+// detectAuthMode inspects environment variables, read through getenv, to
+// determine how the broker should authenticate. WEBULL_APP_KEY takes
+// priority over OAuth env vars.
+func detectAuthMode(getenv func(string) string) (authMode, error) {
+	if getenv("WEBULL_APP_KEY") != "" && getenv("WEBULL_APP_SECRET") != "" {
 		return authModeDirect, nil
 	}
 
-	if os.Getenv("WEBULL_CLIENT_ID") != "" && os.Getenv("WEBULL_CLIENT_SECRET") != "" {
+	if getenv("WEBULL_CLIENT_ID") != "" && getenv("WEBULL_CLIENT_SECRET") != "" {
 		return authModeOAuth, nil
 	}
 
@@ -43,6 +43,7 @@ type WebullBroker struct {
 	uat             bool
 	tokenFile       string
 	callbackURL     string
+	getenv          func(string) string
 	streamer        *fillStreamer
 	submittedOrders map[string]broker.Order
 	mu              sync.Mutex
@@ -72,6 +73,7 @@ func WithAccountID(id string) Option { return func(wb *WebullBroker) { wb.accoun
 func New(opts ...Option) *WebullBroker {
 	wb := &WebullBroker{
 		fills:           make(chan broker.Fill, fillChannelSize),
+		getenv:          os.Getenv,
 		submittedOrders: make(map[string]broker.Order),
 	}
 
@@ -89,7 +91,7 @@ func (wb *WebullBroker) Fills() <-chan broker.Fill {
 
 // Connect establishes an authenticated session with Webull.
 func (wb *WebullBroker) Connect(ctx context.Context) error {
-	mode, modeErr := detectAuthMode()
+	mode, modeErr := detectAuthMode(wb.getenv)
 	if modeErr != nil {
 		return fmt.Errorf("webull: connect: %w", modeErr)
 	}
@@ -104,8 +106,8 @@ func (wb *WebullBroker) Connect(ctx context.Context) error {
 	switch mode {
 	case authModeDirect:
 		sign = &hmacSigner{
-			appKey:    os.Getenv("WEBULL_APP_KEY"),
-			appSecret: os.Getenv("WEBULL_APP_SECRET"),
+			appKey:    wb.getenv("WEBULL_APP_KEY"),
+			appSecret: wb.getenv("WEBULL_APP_SECRET"),
 		}
 	case authModeOAuth:
 		authURL := productionAuthURL
@@ -115,18 +117,18 @@ func (wb *WebullBroker) Connect(ctx context.Context) error {
 
 		callbackURL := wb.callbackURL
 		if callbackURL == "" {
-			callbackURL = os.Getenv("WEBULL_CALLBACK_URL")
+			callbackURL = wb.getenv("WEBULL_CALLBACK_URL")
 		}
 
 		tokenFile := wb.tokenFile
 		if tokenFile == "" {
-			tokenFile = os.Getenv("WEBULL_TOKEN_FILE")
+			tokenFile = wb.getenv("WEBULL_TOKEN_FILE")
 		}
 
 		mgr := newTokenManager(
 			authModeOAuth,
-			os.Getenv("WEBULL_CLIENT_ID"),
-			os.Getenv("WEBULL_CLIENT_SECRET"),
+			wb.getenv("WEBULL_CLIENT_ID"),
+			wb.getenv("WEBULL_CLIENT_SECRET"),
 			callbackURL,
 			tokenFile,
 			authURL,
@@ -341,6 +343,10 @@ func (wb *WebullBroker) validateOrder(order broker.Order) error {
 		return ErrUnsupportedTimeInForce
 	}
 
+	if order.OrderType.IsTrailing() {
+		return fmt.Errorf("webull: trailing stop: %w", broker.ErrUnsupportedOrder)
+	}
+
 	// Dollar-amount orders require fractional shares to be enabled.
 	if order.Qty == 0 && order.Amount > 0 {
 		if !wb.fractional {
@@ -0,0 +1,53 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webull
+
+import "github.com/penny-vault/pvbt/broker"
+
+func init() {
+	broker.Register("webull", NewFromConfig)
+}
+
+// NewFromConfig creates a WebullBroker from a `[broker.webull]` config
+// section. Recognized settings are app_key, app_secret, client_id,
+// client_secret, callback_url and token_file, each overriding the
+// matching WEBULL_* environment variable, and fractional. Account
+// selects the account and Paper selects the UAT environment.
+func NewFromConfig(cfg broker.Config) (broker.Broker, error) {
+	fractional, err := cfg.Bool("fractional")
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []Option
+
+	if cfg.Paper {
+		opts = append(opts, WithUAT())
+	}
+
+	if fractional {
+		opts = append(opts, WithFractionalShares())
+	}
+
+	if cfg.Account != "" {
+		opts = append(opts, WithAccountID(cfg.Account))
+	}
+
+	wb := New(opts...)
+	wb.getenv = cfg.Getenv
+
+	return wb, nil
+}
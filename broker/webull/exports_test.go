@@ -3,6 +3,7 @@ package webull
 import (
 	"context"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/penny-vault/pvbt/broker"
@@ -32,7 +33,7 @@ func ExtractSignatureHeaders(req *http.Request) (appKey, timestamp, signature, a
 
 // DetectAuthModeExport exposes detectAuthMode for testing.
 func DetectAuthModeExport() (AuthModeExport, error) {
-	mode, err := detectAuthMode()
+	mode, err := detectAuthMode(os.Getenv)
 	return AuthModeExport(mode), err
 }
 
@@ -39,6 +39,8 @@ func newBacktestCmd(strategy engine.Strategy) *cobra.Command {
 	cmd.Flags().String("output", "", "Output file path (default: auto-generated)")
 	cmd.Flags().Bool("no-progress", false, "Disable the interactive progress bar (logs go straight to stderr)")
 	cmd.Flags().Bool("json", false, "Output JSON Lines to stdout (for programmatic consumers)")
+	cmd.Flags().String("data-dir", "", "Read data from a directory of per-ticker CSV/Parquet files instead of pv-data")
+	cmd.Flags().String("data-quality", "off", "Audit prices and warn or fail when the strategy reads flagged data (off, warn, fail)")
 
 	registerStrategyFlags(cmd, strategy)
 	cmd.Flags().String("preset", "", "Apply a named parameter preset")
@@ -46,6 +48,8 @@ func newBacktestCmd(strategy engine.Strategy) *cobra.Command {
 	cmd.Flags().String("risk-profile", "", "Risk profile (conservative, moderate, aggressive, none)")
 	cmd.Flags().Bool("tax", false, "Enable tax optimization")
 	registerMarginFlags(cmd)
+	registerCommissionFlags(cmd)
+	registerDiskCacheFlags(cmd)
 
 	return cmd
 }
@@ -64,6 +68,38 @@ func defaultOutputPath(strategyName string, start, end time.Time, shortID string
 	)
 }
 
+// backtestDataProvider is the provider surface a backtest needs: bulk data
+// and asset metadata from the same source.
+type backtestDataProvider interface {
+	data.BatchProvider
+	data.AssetProvider
+}
+
+// newBacktestDataProvider returns a FileProvider when --data-dir is set and
+// the pv-data provider otherwise.
+func newBacktestDataProvider(cmd *cobra.Command) (backtestDataProvider, error) {
+	dataDir, err := cmd.Flags().GetString("data-dir")
+	if err != nil {
+		return nil, err
+	}
+
+	if dataDir != "" {
+		fileProvider, err := data.NewFileProvider(dataDir)
+		if err != nil {
+			return nil, err
+		}
+
+		return fileProvider, nil
+	}
+
+	pvProvider, err := data.NewPVDataProvider(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return pvProvider, nil
+}
+
 func runBacktest(cmd *cobra.Command, strategy engine.Strategy) error {
 	nyc, err := time.LoadLocation("America/New_York")
 	if err != nil {
@@ -158,7 +194,7 @@ func runBacktest(cmd *cobra.Command, strategy engine.Strategy) error {
 		return err
 	}
 
-	provider, err := data.NewPVDataProvider(nil)
+	provider, err := newBacktestDataProvider(cmd)
 	if err != nil {
 		return fmt.Errorf("create data provider: %w", err)
 	}
@@ -168,6 +204,26 @@ func runBacktest(cmd *cobra.Command, strategy engine.Strategy) error {
 		return err
 	}
 
+	commissionOpts, err := resolveCommissionOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	cacheOpts, err := resolveDiskCacheOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	dataQualityStr, err := cmd.Flags().GetString("data-quality")
+	if err != nil {
+		return err
+	}
+
+	dataQuality, err := engine.ParseDataQualityPolicy(dataQualityStr)
+	if err != nil {
+		return err
+	}
+
 	acct := portfolio.New(
 		portfolio.WithCash(cash, start),
 		portfolio.WithAllMetrics(),
@@ -181,6 +237,12 @@ func runBacktest(cmd *cobra.Command, strategy engine.Strategy) error {
 	}
 
 	engineOpts = append(engineOpts, marginOpts...)
+	engineOpts = append(engineOpts, commissionOpts...)
+	engineOpts = append(engineOpts, cacheOpts...)
+
+	if dataQuality != engine.DataQualityOff {
+		engineOpts = append(engineOpts, engine.WithDataQualityPolicy(dataQuality, data.AuditConfig{}))
+	}
 
 	if cfg.HasMiddleware() {
 		engineOpts = append(engineOpts, engine.WithMiddlewareConfig(*cfg))
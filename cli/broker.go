@@ -0,0 +1,247 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/engine"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	// Register the shipped broker adapters with the broker registry.
+	_ "github.com/penny-vault/pvbt/broker/alpaca"
+	_ "github.com/penny-vault/pvbt/broker/etrade"
+	_ "github.com/penny-vault/pvbt/broker/ibkr"
+	_ "github.com/penny-vault/pvbt/broker/schwab"
+	_ "github.com/penny-vault/pvbt/broker/tastytrade"
+	_ "github.com/penny-vault/pvbt/broker/tradestation"
+	_ "github.com/penny-vault/pvbt/broker/tradier"
+	_ "github.com/penny-vault/pvbt/broker/webull"
+)
+
+// registerBrokerFlags adds --broker, --account and --paper to the given
+// command.
+func registerBrokerFlags(cmd *cobra.Command) {
+	cmd.Flags().String("broker", "",
+		fmt.Sprintf("Broker to trade through (%s)", strings.Join(broker.Registered(), ", ")))
+	cmd.Flags().String("account", "", "Brokerage account to trade (overrides the config file)")
+	cmd.Flags().Bool("paper", false, "Use the broker's paper-trading or sandbox environment")
+}
+
+// loadBrokerConfig reads the `[broker.<name>]` section of the config file
+// at configPath (or the default search path when empty). The account and
+// paper keys fill Config.Account and Config.Paper; every other key is
+// kept in Config.Settings. String values have environment variables
+// expanded, so secrets can be written as "${ALPACA_API_SECRET}". A
+// missing file or section yields an empty Config.
+func loadBrokerConfig(configPath, name string) (broker.Config, error) {
+	cfg := broker.Config{Settings: make(map[string]string)}
+
+	path := configFilePath(configPath)
+	if path == "" {
+		if configPath != "" {
+			return cfg, fmt.Errorf("load broker config: %s: %w", configPath, os.ErrNotExist)
+		}
+
+		return cfg, nil
+	}
+
+	vp := viper.New()
+	vp.SetConfigType("toml")
+	vp.SetConfigFile(path)
+
+	if err := vp.ReadInConfig(); err != nil {
+		return cfg, fmt.Errorf("load broker config: read file: %w", err)
+	}
+
+	section := vp.Sub("broker." + name)
+	if section == nil {
+		return cfg, nil
+	}
+
+	for key, value := range section.AllSettings() {
+		switch key {
+		case "account":
+			cfg.Account = os.ExpandEnv(fmt.Sprint(value))
+		case "paper":
+			paper, ok := value.(bool)
+			if !ok {
+				return cfg, fmt.Errorf("load broker config: broker.%s.paper must be true or false", name)
+			}
+
+			cfg.Paper = paper
+		default:
+			cfg.Settings[key] = os.ExpandEnv(fmt.Sprint(value))
+		}
+	}
+
+	return cfg, nil
+}
+
+// resolveBroker builds the broker selected by --broker, applying the
+// config file section and the --account and --paper overrides. It
+// returns nil when --broker is not set.
+func resolveBroker(cmd *cobra.Command) (broker.Broker, error) {
+	name, err := cmd.Flags().GetString("broker")
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		if cmd.Flags().Changed("account") || cmd.Flags().Changed("paper") {
+			return nil, errors.New("--account and --paper require --broker")
+		}
+
+		return nil, nil
+	}
+
+	configPath, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := loadBrokerConfig(configPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.Flags().Changed("account") {
+		if cfg.Account, err = cmd.Flags().GetString("account"); err != nil {
+			return nil, err
+		}
+	}
+
+	if cmd.Flags().Changed("paper") {
+		if cfg.Paper, err = cmd.Flags().GetBool("paper"); err != nil {
+			return nil, err
+		}
+	}
+
+	return broker.Create(name, cfg)
+}
+
+// resolveBrokerOptions returns the engine option that installs the broker
+// selected by --broker, or no options when none is selected.
+func resolveBrokerOptions(cmd *cobra.Command) ([]engine.Option, error) {
+	selected, err := resolveBroker(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if selected == nil {
+		return nil, nil
+	}
+
+	return []engine.Option{engine.WithBroker(selected)}, nil
+}
+
+func newBrokerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "broker",
+		Short: "Inspect broker connections",
+	}
+
+	testCmd := &cobra.Command{
+		Use:   "test",
+		Short: "Connect to a broker and print its positions and balance",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBrokerTest(cmd)
+		},
+	}
+
+	registerBrokerFlags(testCmd)
+
+	cmd.AddCommand(testCmd)
+
+	return cmd
+}
+
+func runBrokerTest(cmd *cobra.Command) error {
+	ctx := context.Background()
+
+	selected, err := resolveBroker(cmd)
+	if err != nil {
+		return fmt.Errorf("broker test: %w", err)
+	}
+
+	if selected == nil {
+		return errors.New("broker test: --broker is required")
+	}
+
+	if err := selected.Connect(ctx); err != nil {
+		return fmt.Errorf("broker test: connect: %w", err)
+	}
+
+	defer selected.Close()
+
+	positions, err := selected.Positions(ctx)
+	if err != nil {
+		return fmt.Errorf("broker test: positions: %w", err)
+	}
+
+	balance, err := selected.Balance(ctx)
+	if err != nil {
+		return fmt.Errorf("broker test: balance: %w", err)
+	}
+
+	renderBrokerTest(cmd, positions, balance)
+
+	return nil
+}
+
+func renderBrokerTest(cmd *cobra.Command, positions []broker.Position, balance broker.Balance) {
+	out := cmd.OutOrStdout()
+
+	fmt.Fprintln(out, "Connected.")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Balance:")
+
+	tw := tabwriter.NewWriter(out, 2, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "  cash\t%.2f\n", balance.CashBalance)
+	fmt.Fprintf(tw, "  net liquidating value\t%.2f\n", balance.NetLiquidatingValue)
+	fmt.Fprintf(tw, "  equity buying power\t%.2f\n", balance.EquityBuyingPower)
+	fmt.Fprintf(tw, "  maintenance requirement\t%.2f\n", balance.MaintenanceReq)
+	tw.Flush()
+
+	fmt.Fprintln(out)
+
+	if len(positions) == 0 {
+		fmt.Fprintln(out, "Positions: none")
+		return
+	}
+
+	sort.Slice(positions, func(i, j int) bool { return positions[i].Asset.Ticker < positions[j].Asset.Ticker })
+
+	fmt.Fprintln(out, "Positions:")
+
+	tw = tabwriter.NewWriter(out, 2, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  TICKER\tQTY\tAVG OPEN\tMARK")
+
+	for _, position := range positions {
+		fmt.Fprintf(tw, "  %s\t%g\t%.2f\t%.2f\n",
+			position.Asset.Ticker, position.Qty, position.AvgOpenPrice, position.MarkPrice)
+	}
+
+	tw.Flush()
+}
@@ -0,0 +1,134 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/engine"
+)
+
+// capturedBrokerConfig records the Config passed to the test factory.
+var capturedBrokerConfig broker.Config
+
+func init() {
+	broker.Register("clitest", func(cfg broker.Config) (broker.Broker, error) {
+		capturedBrokerConfig = cfg
+		return engine.NewSimulatedBroker(), nil
+	})
+}
+
+var _ = Describe("Broker selection", func() {
+	const brokerTOML = `[risk]
+profile = "none"
+
+[broker.clitest]
+account = "ACCT-1"
+paper = true
+api_key = "key-from-file"
+api_secret = "${PVBT_CLI_TEST_SECRET}"
+fractional = true
+`
+
+	var configPath string
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("config", "", "")
+		registerBrokerFlags(cmd)
+
+		return cmd
+	}
+
+	BeforeEach(func() {
+		capturedBrokerConfig = broker.Config{}
+		configPath = filepath.Join(GinkgoT().TempDir(), "pvbt.toml")
+		Expect(os.WriteFile(configPath, []byte(brokerTOML), 0o644)).To(Succeed())
+		GinkgoT().Setenv("PVBT_CLI_TEST_SECRET", "secret-from-env")
+	})
+
+	It("registers every shipped adapter", func() {
+		Expect(broker.Registered()).To(ContainElements(
+			"alpaca", "etrade", "ibkr", "schwab", "tastytrade", "tradestation", "tradier", "webull"))
+	})
+
+	It("reads the [broker.<name>] section and expands environment variables", func() {
+		cfg, err := loadBrokerConfig(configPath, "clitest")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Account).To(Equal("ACCT-1"))
+		Expect(cfg.Paper).To(BeTrue())
+		Expect(cfg.Settings).To(HaveKeyWithValue("api_key", "key-from-file"))
+		Expect(cfg.Settings).To(HaveKeyWithValue("api_secret", "secret-from-env"))
+		Expect(cfg.Settings).To(HaveKeyWithValue("fractional", "true"))
+		Expect(cfg.Getenv("CLITEST_API_KEY")).To(Equal("key-from-file"))
+	})
+
+	It("returns an empty config when the section is missing", func() {
+		cfg, err := loadBrokerConfig(configPath, "alpaca")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Account).To(BeEmpty())
+		Expect(cfg.Settings).To(BeEmpty())
+	})
+
+	It("lets --account and --paper override the config file", func() {
+		cmd := newCmd()
+		Expect(cmd.Flags().Set("config", configPath)).To(Succeed())
+		Expect(cmd.Flags().Set("broker", "clitest")).To(Succeed())
+		Expect(cmd.Flags().Set("account", "ACCT-2")).To(Succeed())
+		Expect(cmd.Flags().Set("paper", "false")).To(Succeed())
+
+		opts, err := resolveBrokerOptions(cmd)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(opts).To(HaveLen(1))
+		Expect(capturedBrokerConfig.Account).To(Equal("ACCT-2"))
+		Expect(capturedBrokerConfig.Paper).To(BeFalse())
+		Expect(capturedBrokerConfig.Settings).To(HaveKeyWithValue("api_key", "key-from-file"))
+	})
+
+	It("adds no engine option when --broker is not set", func() {
+		opts, err := resolveBrokerOptions(newCmd())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(opts).To(BeEmpty())
+	})
+
+	It("rejects --paper without --broker", func() {
+		cmd := newCmd()
+		Expect(cmd.Flags().Set("paper", "true")).To(Succeed())
+
+		_, err := resolveBrokerOptions(cmd)
+		Expect(err).To(MatchError(ContainSubstring("require --broker")))
+	})
+
+	It("rejects unknown broker names", func() {
+		cmd := newCmd()
+		Expect(cmd.Flags().Set("broker", "nosuchbroker")).To(Succeed())
+
+		_, err := resolveBrokerOptions(cmd)
+		Expect(err).To(MatchError(broker.ErrUnknownBroker))
+	})
+
+	It("leaves middleware config loading unaffected by broker sections", func() {
+		cfg, err := loadMiddlewareConfig(configPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Risk.Profile).To(Equal("none"))
+	})
+})
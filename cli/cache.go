@@ -0,0 +1,66 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"time"
+
+	"github.com/penny-vault/pvbt/engine"
+	"github.com/spf13/cobra"
+)
+
+// registerDiskCacheFlags adds --cache-dir, --cache-size-mb and --cache-ttl
+// to the given command. The disk cache is off unless --cache-dir is set.
+func registerDiskCacheFlags(cmd *cobra.Command) {
+	cmd.Flags().String("cache-dir", "", "Directory for a persistent data cache shared across runs (disabled when empty)")
+	cmd.Flags().Int64("cache-size-mb", 4096, "Maximum size of the persistent data cache in MB")
+	cmd.Flags().Duration("cache-ttl", 12*time.Hour, "How long cached data for the current year stays valid")
+}
+
+// resolveDiskCacheOptions reads the disk cache flags and returns the engine
+// option that installs the cache, or no options when --cache-dir is unset.
+// Study commands pass the returned options to every run, so all workers
+// share one cache.
+func resolveDiskCacheOptions(cmd *cobra.Command) ([]engine.Option, error) {
+	dir, err := cmd.Flags().GetString("cache-dir")
+	if err != nil {
+		return nil, err
+	}
+
+	if dir == "" {
+		return nil, nil
+	}
+
+	sizeMB, err := cmd.Flags().GetInt64("cache-size-mb")
+	if err != nil {
+		return nil, err
+	}
+
+	ttl, err := cmd.Flags().GetDuration("cache-ttl")
+	if err != nil {
+		return nil, err
+	}
+
+	cache, err := engine.NewDiskCache(dir, engine.DiskCacheConfig{
+		MaxBytes: sizeMB * 1024 * 1024,
+		TTL:      ttl,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []engine.Option{engine.WithDiskCache(cache)}, nil
+}
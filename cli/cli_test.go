@@ -28,6 +28,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
 	"github.com/penny-vault/pvbt/engine"
 	"github.com/penny-vault/pvbt/portfolio"
 	"github.com/penny-vault/pvbt/universe"
@@ -118,6 +119,47 @@ var _ = Describe("defaultOutputPath", func() {
 	})
 })
 
+var _ = Describe("newBacktestDataProvider", func() {
+	It("reads from a file directory when --data-dir is set", func() {
+		dir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "SPY.csv"), []byte("date,close\n2024-01-02,474\n"), 0o600)).To(Succeed())
+
+		cmd := newBacktestCmd(&testStrategy{})
+		Expect(cmd.Flags().Set("data-dir", dir)).To(Succeed())
+
+		provider, err := newBacktestDataProvider(cmd)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(provider).To(BeAssignableToTypeOf(&data.FileProvider{}))
+
+		spy, err := provider.LookupAsset(context.Background(), "SPY")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spy.Ticker).To(Equal("SPY"))
+	})
+})
+
+var _ = Describe("resolveDiskCacheOptions", func() {
+	It("returns no options when --cache-dir is unset", func() {
+		cmd := newBacktestCmd(&testStrategy{})
+
+		opts, err := resolveDiskCacheOptions(cmd)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(opts).To(BeEmpty())
+	})
+
+	It("opens a disk cache in the directory given by --cache-dir", func() {
+		dir := filepath.Join(GinkgoT().TempDir(), "cache")
+
+		cmd := newBacktestCmd(&testStrategy{})
+		Expect(cmd.Flags().Set("cache-dir", dir)).To(Succeed())
+		Expect(cmd.Flags().Set("cache-size-mb", "16")).To(Succeed())
+
+		opts, err := resolveDiskCacheOptions(cmd)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(opts).To(HaveLen(1))
+		Expect(dir).To(BeADirectory())
+	})
+})
+
 var _ = Describe("registerStrategyFlags", func() {
 	It("registers flags from struct tags with correct defaults", func() {
 		cmd := &cobra.Command{Use: "test"}
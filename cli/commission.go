@@ -0,0 +1,127 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/engine"
+	"github.com/spf13/cobra"
+)
+
+// registerCommissionFlags adds --commission and --regulatory-fees to the
+// given command. The default charges nothing, matching earlier releases.
+func registerCommissionFlags(cmd *cobra.Command) {
+	cmd.Flags().String("commission", "none",
+		"Commission model: 'none', 'ibkr-fixed', 'ibkr-tiered', 'per-share:RATE[:MIN[:MAXPCT]]', 'flat:AMOUNT', or 'bps:N'")
+	cmd.Flags().Bool("regulatory-fees", false,
+		"Charge the SEC Section 31 fee and FINRA TAF on sells")
+}
+
+// resolveCommissionOptions reads the commission flags and returns the
+// engine option that installs the requested model, or no options when
+// nothing is charged.
+func resolveCommissionOptions(cmd *cobra.Command) ([]engine.Option, error) {
+	spec, err := cmd.Flags().GetString("commission")
+	if err != nil {
+		return nil, err
+	}
+
+	model, err := parseCommissionModel(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	regulatory, err := cmd.Flags().GetBool("regulatory-fees")
+	if err != nil {
+		return nil, err
+	}
+
+	if regulatory {
+		if model == nil {
+			model = broker.RegulatoryFees()
+		} else {
+			model = broker.Commissions(model, broker.RegulatoryFees())
+		}
+	}
+
+	if model == nil {
+		return nil, nil
+	}
+
+	return []engine.Option{engine.WithCommissionModel(model)}, nil
+}
+
+// parseCommissionModel parses a --commission value. It returns nil for
+// "none".
+func parseCommissionModel(spec string) (broker.CommissionModel, error) {
+	name, rest, _ := strings.Cut(strings.ToLower(strings.TrimSpace(spec)), ":")
+
+	var args []float64
+
+	if rest != "" {
+		for _, field := range strings.Split(rest, ":") {
+			value, err := strconv.ParseFloat(field, 64)
+			if err != nil || value < 0 {
+				return nil, fmt.Errorf("invalid --commission %q: %q is not a non-negative number", spec, field)
+			}
+
+			args = append(args, value)
+		}
+	}
+
+	arity := func(minArgs, maxArgs int) error {
+		if len(args) < minArgs || len(args) > maxArgs {
+			return fmt.Errorf("invalid --commission %q: %s takes %d to %d values", spec, name, minArgs, maxArgs)
+		}
+
+		return nil
+	}
+
+	switch name {
+	case "none", "":
+		return nil, arity(0, 0)
+	case "ibkr-fixed":
+		return broker.IBKRFixed(), arity(0, 0)
+	case "ibkr-tiered":
+		return broker.IBKRTiered(), arity(0, 0)
+	case "per-share":
+		if err := arity(1, 3); err != nil {
+			return nil, err
+		}
+
+		args = append(args, 0, 0)
+
+		return broker.PerShareCommission(args[0], args[1], args[2]), nil
+	case "flat":
+		if err := arity(1, 1); err != nil {
+			return nil, err
+		}
+
+		return broker.FlatCommission(args[0]), nil
+	case "bps":
+		if err := arity(1, 1); err != nil {
+			return nil, err
+		}
+
+		return broker.BpsCommission(args[0]), nil
+	default:
+		return nil, fmt.Errorf("unknown --commission %q (expected none, ibkr-fixed, ibkr-tiered, per-share, flat, or bps)", spec)
+	}
+}
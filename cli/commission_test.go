@@ -0,0 +1,93 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	"github.com/penny-vault/pvbt/broker"
+)
+
+var _ = Describe("Commission flags", func() {
+	sell := broker.Order{Side: broker.Sell}
+	fill := broker.Fill{Price: 100, Qty: -1000, FilledAt: time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC)}
+
+	total := func(model broker.CommissionModel) float64 {
+		sum := 0.0
+		for _, fee := range model.Fees(sell, fill) {
+			sum += fee.Amount
+		}
+
+		return sum
+	}
+
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		registerCommissionFlags(cmd)
+		Expect(cmd.Flags().Parse(args)).To(Succeed())
+
+		return cmd
+	}
+
+	DescribeTable("parses --commission",
+		func(spec string, expected float64) {
+			model, err := parseCommissionModel(spec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(total(model)).To(BeNumerically("~", expected, 1e-9))
+		},
+		Entry("ibkr-fixed", "ibkr-fixed", 5.0),
+		Entry("ibkr-tiered", "ibkr-tiered", 3.5),
+		Entry("per-share with minimum", "per-share:0.001:2", 2.0),
+		Entry("per-share with cap", "per-share:0.5:0:0.001", 100.0),
+		Entry("flat", "flat:4.95", 4.95),
+		Entry("bps", "BPS:2", 20.0),
+	)
+
+	DescribeTable("rejects malformed values",
+		func(spec string) {
+			_, err := parseCommissionModel(spec)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("unknown model", "robinhood"),
+		Entry("missing rate", "per-share"),
+		Entry("too many values", "flat:1:2"),
+		Entry("negative value", "bps:-1"),
+		Entry("not a number", "flat:abc"),
+		Entry("arguments to a preset", "ibkr-fixed:1"),
+	)
+
+	It("adds no engine option by default", func() {
+		opts, err := resolveCommissionOptions(newCmd())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(opts).To(BeEmpty())
+	})
+
+	It("installs regulatory fees on their own", func() {
+		opts, err := resolveCommissionOptions(newCmd("--regulatory-fees"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(opts).To(HaveLen(1))
+	})
+
+	It("installs a commission model", func() {
+		opts, err := resolveCommissionOptions(newCmd("--commission", "flat:1", "--regulatory-fees"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(opts).To(HaveLen(1))
+	})
+})
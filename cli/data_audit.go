@@ -0,0 +1,323 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/tradecron"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func newDataCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "data",
+		Short: "Inspect market data",
+	}
+
+	auditCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Check price data for zero prices, spikes, split and dividend errors, stale values, and gaps",
+		Long: `Audit fetches daily prices for the given tickers and reports suspect data:
+zero or negative prices, large day-over-day spikes, moves that look like
+splits without a split factor, split factors the price did not follow,
+adjusted closes that disagree with dividends and splits, bad dividends,
+stale repeated prices, and trading days with no price.
+
+  data audit --tickers SPY,TLT --start 2010-01-01 --end 2024-12-31
+  data audit --tickers SPY --start 2020-01-01 --end 2020-12-31 --output audit.csv`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDataAudit(cmd)
+		},
+	}
+
+	auditCmd.Flags().StringSlice("tickers", nil, "Tickers to audit (comma separated)")
+	auditCmd.Flags().String("start", "", "First date to audit (YYYY-MM-DD)")
+	auditCmd.Flags().String("end", time.Now().Format("2006-01-02"), "Last date to audit (YYYY-MM-DD)")
+	auditCmd.Flags().String("data-dir", "", "Read data from a directory of per-ticker CSV/Parquet files instead of pv-data")
+	auditCmd.Flags().String("calendar", "XNYS", "Exchange calendar gaps are measured against (MIC or exchange name)")
+	auditCmd.Flags().Float64("spike-ratio", 4, "Day-over-day price ratio flagged as a spike")
+	auditCmd.Flags().Int("stale-days", 5, "Consecutive trading days with an unchanged price flagged as stale")
+	auditCmd.Flags().String("output", "", "Also write the report to this file (.json or .csv)")
+
+	cmd.AddCommand(auditCmd)
+
+	return cmd
+}
+
+func runDataAudit(cmd *cobra.Command) error {
+	ctx := log.Logger.WithContext(context.Background())
+
+	nyc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return fmt.Errorf("load America/New_York timezone: %w", err)
+	}
+
+	tickers, err := cmd.Flags().GetStringSlice("tickers")
+	if err != nil {
+		return err
+	}
+
+	startStr, err := cmd.Flags().GetString("start")
+	if err != nil {
+		return err
+	}
+
+	if startStr == "" {
+		return errors.New("data audit: --start is required")
+	}
+
+	start, err := time.ParseInLocation("2006-01-02", startStr, nyc)
+	if err != nil {
+		return fmt.Errorf("invalid start date: %w", err)
+	}
+
+	endStr, err := cmd.Flags().GetString("end")
+	if err != nil {
+		return err
+	}
+
+	end, err := time.ParseInLocation("2006-01-02", endStr, nyc)
+	if err != nil {
+		return fmt.Errorf("invalid end date: %w", err)
+	}
+
+	end = end.Add(24*time.Hour - time.Nanosecond)
+
+	if end.Before(start) {
+		return fmt.Errorf("data audit: end date %s is before start date %s", endStr, startStr)
+	}
+
+	cfg, err := resolveAuditConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	outputPath, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+
+	provider, err := newBacktestDataProvider(cmd)
+	if err != nil {
+		return fmt.Errorf("data audit: create data provider: %w", err)
+	}
+
+	defer provider.Close()
+
+	assets := make([]asset.Asset, 0, len(tickers))
+
+	for _, ticker := range tickers {
+		ticker = strings.ToUpper(strings.TrimSpace(ticker))
+		if ticker == "" {
+			continue
+		}
+
+		resolved, lookupErr := provider.LookupAsset(ctx, ticker)
+		if lookupErr != nil {
+			return fmt.Errorf("data audit: look up %s: %w", ticker, lookupErr)
+		}
+
+		assets = append(assets, resolved)
+	}
+
+	if len(assets) == 0 {
+		return errors.New("data audit: no tickers given")
+	}
+
+	auditor := data.NewAuditingProvider(provider, cfg)
+
+	if _, err := auditor.Fetch(ctx, data.DataRequest{
+		Assets:    assets,
+		Metrics:   []data.Metric{data.MetricClose},
+		Start:     start,
+		End:       end,
+		Frequency: data.Daily,
+	}); err != nil {
+		return fmt.Errorf("data audit: fetch prices: %w", err)
+	}
+
+	anomalies := auditor.Anomalies()
+
+	writeAuditReport(cmd.OutOrStdout(), anomalies, len(assets), start, end)
+
+	if outputPath != "" {
+		if err := exportAuditReport(outputPath, anomalies); err != nil {
+			return fmt.Errorf("data audit: %w", err)
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Report written to %s\n", outputPath)
+	}
+
+	return nil
+}
+
+// resolveAuditConfig builds the audit configuration from the command's
+// flags.
+func resolveAuditConfig(cmd *cobra.Command) (data.AuditConfig, error) {
+	calendarName, err := cmd.Flags().GetString("calendar")
+	if err != nil {
+		return data.AuditConfig{}, err
+	}
+
+	calendar, ok := tradecron.Lookup(calendarName)
+	if !ok {
+		return data.AuditConfig{}, fmt.Errorf("data audit: unknown calendar %q", calendarName)
+	}
+
+	spikeRatio, err := cmd.Flags().GetFloat64("spike-ratio")
+	if err != nil {
+		return data.AuditConfig{}, err
+	}
+
+	staleDays, err := cmd.Flags().GetInt("stale-days")
+	if err != nil {
+		return data.AuditConfig{}, err
+	}
+
+	return data.AuditConfig{Calendar: calendar, SpikeRatio: spikeRatio, StaleDays: staleDays}, nil
+}
+
+// writeAuditReport prints one row per anomaly followed by counts by kind.
+func writeAuditReport(out io.Writer, anomalies []data.Anomaly, numAssets int, start, end time.Time) {
+	period := fmt.Sprintf("%s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+
+	if len(anomalies) == 0 {
+		fmt.Fprintf(out, "No anomalies in %d tickers from %s.\n", numAssets, period)
+		return
+	}
+
+	tw := tabwriter.NewWriter(out, 2, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TICKER\tMETRIC\tKIND\tDATE\tEND\tVALUE\tDETAIL")
+
+	for _, anomaly := range anomalies {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			anomaly.Asset.Ticker, anomaly.Metric, anomaly.Kind,
+			anomaly.Date.Format("2006-01-02"), anomaly.End.Format("2006-01-02"),
+			formatAuditValue(anomaly.Value), anomaly.Detail)
+	}
+
+	tw.Flush()
+
+	counts := make(map[data.AnomalyKind]int)
+	for _, anomaly := range anomalies {
+		counts[anomaly.Kind]++
+	}
+
+	kinds := make([]string, 0, len(counts))
+	for kind, count := range counts {
+		kinds = append(kinds, fmt.Sprintf("%s %d", kind, count))
+	}
+
+	sort.Strings(kinds)
+
+	fmt.Fprintf(out, "\n%d anomalies in %d tickers from %s (%s).\n", len(anomalies), numAssets, period, strings.Join(kinds, ", "))
+}
+
+func formatAuditValue(value float64) string {
+	if math.IsNaN(value) {
+		return ""
+	}
+
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// auditReportRow is one anomaly in an exported report.
+type auditReportRow struct {
+	Ticker        string   `json:"ticker"`
+	CompositeFigi string   `json:"composite_figi"`
+	Metric        string   `json:"metric"`
+	Kind          string   `json:"kind"`
+	Date          string   `json:"date"`
+	End           string   `json:"end"`
+	Value         *float64 `json:"value"`
+	Detail        string   `json:"detail"`
+}
+
+// exportAuditReport writes anomalies to path as JSON or CSV, chosen by
+// the file extension.
+func exportAuditReport(path string, anomalies []data.Anomaly) error {
+	rows := make([]auditReportRow, len(anomalies))
+
+	for idx, anomaly := range anomalies {
+		rows[idx] = auditReportRow{
+			Ticker:        anomaly.Asset.Ticker,
+			CompositeFigi: anomaly.Asset.CompositeFigi,
+			Metric:        string(anomaly.Metric),
+			Kind:          string(anomaly.Kind),
+			Date:          anomaly.Date.Format("2006-01-02"),
+			End:           anomaly.End.Format("2006-01-02"),
+			Detail:        anomaly.Detail,
+		}
+
+		if !math.IsNaN(anomaly.Value) {
+			value := anomaly.Value
+			rows[idx].Value = &value
+		}
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		encoded, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode report: %w", err)
+		}
+
+		return os.WriteFile(path, append(encoded, '\n'), 0o644)
+	case ".csv":
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("create report: %w", err)
+		}
+
+		writer := csv.NewWriter(file)
+		_ = writer.Write([]string{"ticker", "composite_figi", "metric", "kind", "date", "end", "value", "detail"})
+
+		for idx, row := range rows {
+			_ = writer.Write([]string{
+				row.Ticker, row.CompositeFigi, row.Metric, row.Kind, row.Date, row.End,
+				formatAuditValue(anomalies[idx].Value), row.Detail,
+			})
+		}
+
+		writer.Flush()
+
+		if err := errors.Join(writer.Error(), file.Close()); err != nil {
+			return fmt.Errorf("write report: %w", err)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unsupported report format %q (want .json or .csv)", ext)
+	}
+}
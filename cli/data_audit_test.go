@@ -0,0 +1,65 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("data audit", func() {
+	It("prints and exports the anomalies in a file directory", func() {
+		dir := GinkgoT().TempDir()
+		csv := "date,close\n" +
+			"2024-01-02,100\n" +
+			"2024-01-03,101\n" +
+			"2024-01-04,0\n" +
+			"2024-01-05,102\n" +
+			"2024-01-09,103\n"
+		Expect(os.WriteFile(filepath.Join(dir, "SPY.csv"), []byte(csv), 0o600)).To(Succeed())
+
+		reportPath := filepath.Join(GinkgoT().TempDir(), "audit.json")
+
+		cmd := newDataCmd()
+
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"audit",
+			"--tickers", "spy", "--start", "2024-01-01", "--end", "2024-01-31",
+			"--data-dir", dir, "--output", reportPath,
+		})
+		Expect(cmd.Execute()).To(Succeed())
+
+		Expect(out.String()).To(MatchRegexp(`SPY\s+Close\s+zero-price\s+2024-01-04`))
+		Expect(out.String()).To(MatchRegexp(`SPY\s+Close\s+gap\s+2024-01-08`))
+		Expect(out.String()).To(ContainSubstring("2 anomalies in 1 tickers"))
+
+		raw, err := os.ReadFile(reportPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		var rows []auditReportRow
+		Expect(json.Unmarshal(raw, &rows)).To(Succeed())
+		Expect(rows).To(HaveLen(2))
+		Expect(rows[0].Kind).To(Equal("zero-price"))
+		Expect(rows[1].Kind).To(Equal("gap"))
+		Expect(rows[1].Value).To(BeNil())
+	})
+})
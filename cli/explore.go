@@ -44,6 +44,8 @@ func RunPVBT() {
 
 	rootCmd.AddCommand(newExploreCmd())
 	rootCmd.AddCommand(newLibraryCmd())
+	rootCmd.AddCommand(newHolidaysCmd())
+	rootCmd.AddCommand(newDataCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		// Check if the first arg is an installed strategy short-code.
@@ -0,0 +1,150 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/tradecron"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func newHolidaysCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "holidays",
+		Short: "Inspect market holiday calendars",
+	}
+
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare the built-in NYSE holiday rules with the PVDataProvider holidays",
+		Long: `Diff generates NYSE holidays and early closes from the built-in rules
+and lists every day on which they disagree with the market_holidays table.
+The year range defaults to the years covered by the provider's data.
+
+  holidays diff
+  holidays diff --start-year 2000 --end-year 2025`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHolidaysDiff(cmd)
+		},
+	}
+
+	diffCmd.Flags().Int("start-year", 0, "First year to compare (default: first year of provider data)")
+	diffCmd.Flags().Int("end-year", 0, "Last year to compare (default: last year of provider data)")
+
+	cmd.AddCommand(diffCmd)
+
+	return cmd
+}
+
+func runHolidaysDiff(cmd *cobra.Command) error {
+	ctx := log.Logger.WithContext(context.Background())
+
+	startYear, err := cmd.Flags().GetInt("start-year")
+	if err != nil {
+		return err
+	}
+
+	endYear, err := cmd.Flags().GetInt("end-year")
+	if err != nil {
+		return err
+	}
+
+	provider, err := data.NewPVDataProvider(nil)
+	if err != nil {
+		return fmt.Errorf("holidays diff: create data provider: %w", err)
+	}
+
+	defer provider.Close()
+
+	provided, err := provider.FetchMarketHolidays(ctx)
+	if err != nil {
+		return fmt.Errorf("holidays diff: %w", err)
+	}
+
+	if len(provided) > 0 {
+		if startYear == 0 {
+			startYear = provided[0].Date.Year()
+		}
+
+		if endYear == 0 {
+			endYear = provided[len(provided)-1].Date.Year()
+		}
+	}
+
+	if startYear == 0 || endYear == 0 {
+		return errors.New("holidays diff: provider has no holidays; pass --start-year and --end-year")
+	}
+
+	if endYear < startYear {
+		return fmt.Errorf("holidays diff: end year %d is before start year %d", endYear, startYear)
+	}
+
+	var generated []tradecron.MarketHoliday
+	for year := startYear; year <= endYear; year++ {
+		generated = append(generated, tradecron.NYSEHolidays(year)...)
+	}
+
+	var inRange []tradecron.MarketHoliday
+	for _, holiday := range provided {
+		if year := holiday.Date.Year(); year >= startYear && year <= endYear {
+			inRange = append(inRange, holiday)
+		}
+	}
+
+	writeHolidayDiff(cmd.OutOrStdout(), tradecron.DiffHolidays(generated, inRange), startYear, endYear)
+
+	return nil
+}
+
+// writeHolidayDiff prints one row per disagreeing day followed by a count.
+func writeHolidayDiff(out io.Writer, diffs []tradecron.HolidayDiff, startYear, endYear int) {
+	if len(diffs) == 0 {
+		fmt.Fprintf(out, "No differences between %d and %d.\n", startYear, endYear)
+		return
+	}
+
+	tw := tabwriter.NewWriter(out, 2, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DATE\tRULES\tPROVIDER")
+
+	for _, diff := range diffs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n",
+			diff.Date.Format("2006-01-02"), describeHoliday(diff.Generated), describeHoliday(diff.Provided))
+	}
+
+	tw.Flush()
+
+	fmt.Fprintf(out, "\n%d differences between %d and %d.\n", len(diffs), startYear, endYear)
+}
+
+// describeHoliday renders a holiday entry for the diff table.
+func describeHoliday(holiday *tradecron.MarketHoliday) string {
+	switch {
+	case holiday == nil:
+		return "open"
+	case holiday.EarlyClose:
+		return fmt.Sprintf("closes %02d:%02d", holiday.CloseTime/100, holiday.CloseTime%100)
+	default:
+		return "closed"
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/tradecron"
+)
+
+var _ = Describe("holidays diff", func() {
+	It("lists each disagreeing day with both sides", func() {
+		provided := []tradecron.MarketHoliday{
+			{Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{Date: time.Date(2024, 11, 29, 0, 0, 0, 0, time.UTC), EarlyClose: true, CloseTime: 1400},
+		}
+
+		var out bytes.Buffer
+		writeHolidayDiff(&out, tradecron.DiffHolidays(tradecron.NYSEHolidays(2024), provided), 2024, 2024)
+
+		Expect(out.String()).To(ContainSubstring("DATE"))
+		Expect(out.String()).To(MatchRegexp(`2024-03-29\s+closed\s+open`))
+		Expect(out.String()).To(MatchRegexp(`2024-11-29\s+closes 13:00\s+closes 14:00`))
+		Expect(out.String()).NotTo(ContainSubstring("2024-01-01"))
+		Expect(out.String()).To(ContainSubstring("12 differences between 2024 and 2024."))
+	})
+
+	It("reports when the calendars agree", func() {
+		var out bytes.Buffer
+		writeHolidayDiff(&out, nil, 2020, 2024)
+
+		Expect(out.String()).To(Equal("No differences between 2020 and 2024.\n"))
+	})
+})
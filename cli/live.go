@@ -29,6 +29,12 @@ func newLiveCmd(strategy engine.Strategy) *cobra.Command {
 	cmd.Flags().String("benchmark", "", "Benchmark ticker for performance comparison")
 	cmd.Flags().String("risk-profile", "", "Risk profile (conservative, moderate, aggressive, none)")
 	cmd.Flags().Bool("tax", false, "Enable tax optimization")
+	cmd.Flags().String("state-dir", "", "Directory for the per-step checkpoint used to resume after a restart")
+	cmd.Flags().String("resume", "", "Resume from a checkpoint file written by a previous --state-dir session")
+	cmd.Flags().String("reconcile", "", "Reconcile the account against the broker at startup and before each step (warn, halt, trust-broker)")
+	cmd.Flags().Float64("reconcile-qty-tolerance", 1e-6, "Share quantity difference ignored by reconciliation")
+	cmd.Flags().Float64("reconcile-cash-tolerance", 0.01, "Cash difference ignored by reconciliation")
+	registerBrokerFlags(cmd)
 	registerMarginFlags(cmd)
 
 	return cmd
@@ -58,13 +64,51 @@ func runLive(cmd *cobra.Command, strategy engine.Strategy) error {
 		return fmt.Errorf("create data provider: %w", err)
 	}
 
+	stateDir, err := cmd.Flags().GetString("state-dir")
+	if err != nil {
+		return err
+	}
+
+	resumePath, err := cmd.Flags().GetString("resume")
+	if err != nil {
+		return err
+	}
+
 	engineOpts := []engine.Option{
 		engine.WithDataProvider(provider),
 		engine.WithAssetProvider(provider),
-		engine.WithInitialDeposit(cash),
 		engine.WithUserParams(appliedFlags...),
 	}
 
+	if resumePath != "" {
+		engineOpts = append(engineOpts, engine.WithResumeCheckpoint(resumePath))
+	} else {
+		engineOpts = append(engineOpts, engine.WithInitialDeposit(cash))
+	}
+
+	if stateDir != "" {
+		engineOpts = append(engineOpts, engine.WithLiveStateDir(stateDir))
+	}
+
+	reconcileOpts, err := resolveReconcileOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	engineOpts = append(engineOpts, reconcileOpts...)
+
+	brokerName, err := cmd.Flags().GetString("broker")
+	if err != nil {
+		return err
+	}
+
+	brokerOpts, err := resolveBrokerOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	engineOpts = append(engineOpts, brokerOpts...)
+
 	if cfg.HasMiddleware() {
 		engineOpts = append(engineOpts, engine.WithMiddlewareConfig(*cfg))
 	}
@@ -91,6 +135,9 @@ func runLive(cmd *cobra.Command, strategy engine.Strategy) error {
 	log.Info().
 		Str("strategy", strategy.Name()).
 		Float64("cash", cash).
+		Str("state_dir", stateDir).
+		Str("resume", resumePath).
+		Str("broker", brokerName).
 		Msg("starting live mode")
 
 	ch, err := eng.RunLive(ctx)
@@ -99,7 +146,10 @@ func runLive(cmd *cobra.Command, strategy engine.Strategy) error {
 	}
 
 	for p := range ch {
-		p.SetMetadata(portfolio.MetaRunInitialCash, fmt.Sprintf("%.2f", cash))
+		// A resumed account carries the original session's initial cash.
+		if resumePath == "" {
+			p.SetMetadata(portfolio.MetaRunInitialCash, fmt.Sprintf("%.2f", cash))
+		}
 
 		reportable, ok := p.(summary.ReportablePortfolio)
 		if !ok {
@@ -114,3 +164,37 @@ func runLive(cmd *cobra.Command, strategy engine.Strategy) error {
 
 	return nil
 }
+
+// resolveReconcileOptions builds the broker reconciliation option from the
+// --reconcile flags. No option is returned when --reconcile is empty.
+func resolveReconcileOptions(cmd *cobra.Command) ([]engine.Option, error) {
+	policyName, err := cmd.Flags().GetString("reconcile")
+	if err != nil {
+		return nil, err
+	}
+
+	if policyName == "" {
+		return nil, nil
+	}
+
+	policy, err := engine.ParseDriftPolicy(policyName)
+	if err != nil {
+		return nil, err
+	}
+
+	qtyTolerance, err := cmd.Flags().GetFloat64("reconcile-qty-tolerance")
+	if err != nil {
+		return nil, err
+	}
+
+	cashTolerance, err := cmd.Flags().GetFloat64("reconcile-cash-tolerance")
+	if err != nil {
+		return nil, err
+	}
+
+	return []engine.Option{engine.WithBrokerReconciliation(engine.ReconcileConfig{
+		Policy:        policy,
+		QtyTolerance:  qtyTolerance,
+		CashTolerance: cashTolerance,
+	})}, nil
+}
@@ -125,6 +125,7 @@ func newRootCmd(strategy engine.Strategy) (*cobra.Command, func()) {
 	rootCmd.AddCommand(newDescribeCmd(strategy))
 	rootCmd.AddCommand(newStudyCmd(strategy))
 	rootCmd.AddCommand(newConfigCmd())
+	rootCmd.AddCommand(newBrokerCmd())
 
 	return rootCmd, cleanup
 }
@@ -61,6 +61,7 @@ func newStressTestCmd(strategy engine.Strategy) *cobra.Command {
 	cmd.Flags().Int("workers", runtime.GOMAXPROCS(0), "Number of concurrent workers")
 	cmd.Flags().String("format", "html", "Output format (text, json, html)")
 	cmd.Flags().Float64("cash", 100000, "Initial cash balance per scenario run")
+	registerDiskCacheFlags(cmd)
 
 	registerStrategyFlags(cmd, strategy)
 
@@ -98,6 +99,13 @@ func runStressTest(cmd *cobra.Command, strategy engine.Strategy, args []string)
 		engine.WithUserParams(strategyFlagFieldNames(cmd, strategy)...),
 	}
 
+	cacheOpts, err := resolveDiskCacheOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	opts = append(opts, cacheOpts...)
+
 	workers, err := cmd.Flags().GetInt("workers")
 	if err != nil {
 		return err
@@ -299,6 +307,7 @@ func newOptimizeCmd(strategy engine.Strategy) *cobra.Command {
 	cmd.Flags().String("scenarios", "", "Comma-separated scenario names for scenario validation")
 	cmd.Flags().Int("holdout", 1, "Number of scenarios to hold out per split (scenario validation)")
 	cmd.Flags().Float64("cash", 100000, "Initial cash balance per parameter combination run")
+	registerDiskCacheFlags(cmd)
 
 	registerStrategyFlagsForSweep(cmd, strategy)
 
@@ -390,6 +399,13 @@ func runOptimize(cmd *cobra.Command, strategy engine.Strategy) error {
 		engine.WithInitialDeposit(cash),
 	}
 
+	cacheOpts, err := resolveDiskCacheOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	opts = append(opts, cacheOpts...)
+
 	workers, err := cmd.Flags().GetInt("workers")
 	if err != nil {
 		return fmt.Errorf("get --workers: %w", err)
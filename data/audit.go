@@ -0,0 +1,683 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/tradecron"
+)
+
+// AnomalyKind classifies a data quality problem found by Audit.
+type AnomalyKind string
+
+const (
+	// AnomalyZeroPrice is a price at or below zero.
+	AnomalyZeroPrice AnomalyKind = "zero-price"
+
+	// AnomalySpike is a day-over-day move of at least SpikeRatio in either
+	// direction that no split factor explains.
+	AnomalySpike AnomalyKind = "spike"
+
+	// AnomalyMissingSplit is a close that moved by a whole split ratio
+	// (2:1, 1:3, ...) on a day with no split factor recorded.
+	AnomalyMissingSplit AnomalyKind = "missing-split"
+
+	// AnomalySplitMismatch is a recorded split factor the close did not
+	// move by.
+	AnomalySplitMismatch AnomalyKind = "split-mismatch"
+
+	// AnomalyAdjustmentMismatch is a change in the adjusted-close to close
+	// ratio that the day's dividend and split factor do not account for.
+	AnomalyAdjustmentMismatch AnomalyKind = "adjustment-mismatch"
+
+	// AnomalyBadDividend is a negative dividend or one at least as large
+	// as the prior close.
+	AnomalyBadDividend AnomalyKind = "bad-dividend"
+
+	// AnomalyStale is a price repeated unchanged on StaleDays or more
+	// consecutive trading days.
+	AnomalyStale AnomalyKind = "stale"
+
+	// AnomalyGap is one or more consecutive trading days with no price.
+	AnomalyGap AnomalyKind = "gap"
+)
+
+// Anomaly is one suspect cell, or run of cells, in a price series.
+type Anomaly struct {
+	Asset  asset.Asset
+	Metric Metric
+	Kind   AnomalyKind
+
+	// Date is the first affected timestamp and End the last; they are
+	// equal for single-cell anomalies. Gaps and stale runs span several
+	// trading days.
+	Date time.Time
+	End  time.Time
+
+	// Value is the offending value, or NaN when there is none (gaps).
+	Value  float64
+	Detail string
+}
+
+// Overlaps reports whether the anomaly affects any timestamp in
+// [start, end].
+func (a Anomaly) Overlaps(start, end time.Time) bool {
+	return !a.Date.After(end) && !a.End.Before(start)
+}
+
+// AnomalyReporter is implemented by providers that flag suspect data,
+// such as AuditingProvider. The engine consults every registered reporter
+// when a data quality policy is set.
+type AnomalyReporter interface {
+	// AnomaliesFor returns the anomalies recorded for a that overlap
+	// [start, end].
+	AnomaliesFor(a asset.Asset, start, end time.Time) []Anomaly
+}
+
+// CacheAuditor is implemented by providers that audit the data they
+// serve, such as AuditingProvider. Data the engine's disk cache serves in
+// place of a fetch is handed to AuditCached, so it is audited as if it
+// had been fetched.
+type CacheAuditor interface {
+	// AuditMetrics returns metrics widened with the audit inputs the
+	// provider serves. The engine reads these extra columns from the disk
+	// cache so every check can run.
+	AuditMetrics(metrics []Metric) []Metric
+
+	// AuditCached audits a daily frame read from the disk cache.
+	AuditCached(df *DataFrame)
+}
+
+const (
+	defaultSpikeRatio      = 4.0
+	defaultStaleDays       = 5
+	defaultSplitTolerance  = 0.03
+	defaultAdjustTolerance = 0.01
+
+	// maxSplitRatio is the largest whole split ratio a missing split is
+	// matched against.
+	maxSplitRatio = 50
+)
+
+// AuditConfig tunes Audit. Zero values select the defaults.
+type AuditConfig struct {
+	// Calendar supplies the trading days gaps are measured against.
+	// Defaults to tradecron.XNYS.
+	Calendar tradecron.Calendar
+
+	// SpikeRatio is the day-over-day price ratio, up or down, flagged as
+	// a spike. Defaults to 4 (a 300% gain or a 75% loss).
+	SpikeRatio float64
+
+	// StaleDays is the number of consecutive trading days with an
+	// unchanged price flagged as stale. Defaults to 5.
+	StaleDays int
+
+	// SplitTolerance is the relative distance from a whole split ratio
+	// within which an unexplained move is reported as a missing split
+	// rather than a spike. Defaults to 3%.
+	SplitTolerance float64
+
+	// AdjustmentTolerance is the relative error allowed between the
+	// observed and expected change in the adjusted-close factor.
+	// Defaults to 1%.
+	AdjustmentTolerance float64
+}
+
+func (cfg AuditConfig) withDefaults() AuditConfig {
+	if cfg.Calendar == nil {
+		cfg.Calendar = tradecron.XNYS
+	}
+
+	if cfg.SpikeRatio <= 1 {
+		cfg.SpikeRatio = defaultSpikeRatio
+	}
+
+	if cfg.StaleDays <= 1 {
+		cfg.StaleDays = defaultStaleDays
+	}
+
+	if cfg.SplitTolerance <= 0 {
+		cfg.SplitTolerance = defaultSplitTolerance
+	}
+
+	if cfg.AdjustmentTolerance <= 0 {
+		cfg.AdjustmentTolerance = defaultAdjustTolerance
+	}
+
+	return cfg
+}
+
+// auditMetrics are the metrics Audit reads. AuditingProvider adds the
+// ones its provider serves to every request for any of them.
+var auditMetrics = []Metric{
+	MetricOpen, MetricHigh, MetricLow, MetricClose, AdjClose, Dividend, SplitFactor,
+}
+
+// Audit checks the daily price series in df and returns the anomalies it
+// finds, ordered by asset, date, and kind. Each check runs only when the
+// metrics it needs are present:
+//
+//   - zero-price on Open, High, Low, Close, and AdjClose;
+//   - spike on Close and AdjClose, with Close moves explained by the
+//     day's SplitFactor, and missing-split and split-mismatch on Close;
+//   - adjustment-mismatch when Close and AdjClose are both present;
+//   - bad-dividend on Dividend;
+//   - stale and gap on Close, or on AdjClose when Close is absent. Gaps
+//     are trading days of cfg.Calendar between the series' first and last
+//     price with no price.
+func Audit(df *DataFrame, cfg AuditConfig) []Anomaly {
+	if df == nil || df.Len() == 0 {
+		return nil
+	}
+
+	cfg = cfg.withDefaults()
+
+	times := df.Times()
+	metrics := df.MetricList()
+
+	var found []Anomaly
+
+	for _, assetItem := range df.AssetList() {
+		column := func(metric Metric) []float64 {
+			if !slices.Contains(metrics, metric) {
+				return nil
+			}
+
+			return df.Column(assetItem, metric)
+		}
+
+		report := func(metric Metric, kind AnomalyKind, idx int, value float64, detail string) {
+			found = append(found, Anomaly{
+				Asset: assetItem, Metric: metric, Kind: kind,
+				Date: times[idx], End: times[idx], Value: value, Detail: detail,
+			})
+		}
+
+		closes := column(MetricClose)
+		adjCloses := column(AdjClose)
+		splits := column(SplitFactor)
+		dividends := column(Dividend)
+
+		for _, metric := range []Metric{MetricOpen, MetricHigh, MetricLow, MetricClose, AdjClose} {
+			for idx, value := range column(metric) {
+				if value <= 0 {
+					report(metric, AnomalyZeroPrice, idx, value, fmt.Sprintf("%s is %g", metric, value))
+				}
+			}
+		}
+
+		if closes != nil {
+			auditCloseMoves(closes, splits, cfg, func(idx int, kind AnomalyKind, detail string) {
+				report(MetricClose, kind, idx, closes[idx], detail)
+			})
+		}
+
+		if adjCloses != nil {
+			auditCloseMoves(adjCloses, nil, cfg, func(idx int, kind AnomalyKind, detail string) {
+				report(AdjClose, kind, idx, adjCloses[idx], detail)
+			})
+		}
+
+		if closes != nil && adjCloses != nil {
+			auditAdjustments(closes, adjCloses, splits, dividends, cfg, func(idx int, detail string) {
+				report(AdjClose, AnomalyAdjustmentMismatch, idx, adjCloses[idx], detail)
+			})
+		}
+
+		prevClose := math.NaN()
+
+		for idx, dividend := range dividends {
+			switch {
+			case dividend < 0:
+				report(Dividend, AnomalyBadDividend, idx, dividend, fmt.Sprintf("negative dividend %g", dividend))
+			case dividend > 0 && dividend >= prevClose:
+				report(Dividend, AnomalyBadDividend, idx, dividend,
+					fmt.Sprintf("dividend %g is at least the prior close %g", dividend, prevClose))
+			}
+
+			if closes != nil && !math.IsNaN(closes[idx]) {
+				prevClose = closes[idx]
+			}
+		}
+
+		primary, primaryMetric := closes, MetricClose
+		if primary == nil {
+			primary, primaryMetric = adjCloses, AdjClose
+		}
+
+		if primary != nil {
+			found = append(found, auditStale(assetItem, primaryMetric, times, primary, cfg)...)
+			found = append(found, auditGaps(assetItem, primaryMetric, times, primary, cfg)...)
+		}
+	}
+
+	sortAnomalies(found)
+
+	return found
+}
+
+// auditCloseMoves flags day-over-day moves in prices. When splits is
+// non-nil a move is first divided by the day's split factor, and moves
+// by a whole split ratio on days without one are reported as missing
+// splits.
+func auditCloseMoves(prices, splits []float64, cfg AuditConfig, flag func(idx int, kind AnomalyKind, detail string)) {
+	spikeLimit := math.Log(cfg.SpikeRatio)
+	prev := -1
+
+	for idx, price := range prices {
+		if math.IsNaN(price) || price <= 0 {
+			continue
+		}
+
+		if prev < 0 {
+			prev = idx
+			continue
+		}
+
+		ratio := price / prices[prev]
+		prev = idx
+
+		split := 1.0
+		if splits != nil && !math.IsNaN(splits[idx]) && splits[idx] > 0 {
+			split = splits[idx]
+		}
+
+		adjusted := ratio * split
+
+		switch {
+		case split != 1 && math.Abs(math.Log(adjusted)) > math.Log(1.5):
+			flag(idx, AnomalySplitMismatch, fmt.Sprintf("split factor %g but price moved %.4gx", split, ratio))
+		case split == 1 && splits != nil && isSplitRatio(ratio, cfg.SplitTolerance):
+			flag(idx, AnomalyMissingSplit, fmt.Sprintf("price moved %.4gx with no split factor", ratio))
+		case math.Abs(math.Log(adjusted)) >= spikeLimit:
+			flag(idx, AnomalySpike, fmt.Sprintf("price moved %.4gx", adjusted))
+		}
+	}
+}
+
+// isSplitRatio reports whether ratio is within tolerance of n or 1/n for
+// a whole n from 2 to maxSplitRatio.
+func isSplitRatio(ratio, tolerance float64) bool {
+	move := ratio
+	if move < 1 {
+		move = 1 / move
+	}
+
+	nearest := math.Round(move)
+	if nearest < 2 || nearest > maxSplitRatio {
+		return false
+	}
+
+	return math.Abs(move-nearest)/nearest <= tolerance
+}
+
+// auditAdjustments compares the change in the adjusted-close factor
+// (AdjClose / Close) between consecutive rows with the change the day's
+// dividend and split imply: (1 - dividend / prior close) / split.
+func auditAdjustments(closes, adjCloses, splits, dividends []float64, cfg AuditConfig, flag func(idx int, detail string)) {
+	prev := -1
+
+	for idx := range closes {
+		if math.IsNaN(closes[idx]) || math.IsNaN(adjCloses[idx]) || closes[idx] <= 0 || adjCloses[idx] <= 0 {
+			continue
+		}
+
+		if prev < 0 {
+			prev = idx
+			continue
+		}
+
+		split := 1.0
+		if splits != nil && !math.IsNaN(splits[idx]) && splits[idx] > 0 {
+			split = splits[idx]
+		}
+
+		dividend := 0.0
+		if dividends != nil && !math.IsNaN(dividends[idx]) && dividends[idx] > 0 {
+			dividend = dividends[idx]
+		}
+
+		observed := (adjCloses[prev] / closes[prev]) / (adjCloses[idx] / closes[idx])
+		expected := (1 - dividend/closes[prev]) / split
+		prev = idx
+
+		if expected <= 0 || math.Abs(observed/expected-1) <= cfg.AdjustmentTolerance {
+			continue
+		}
+
+		flag(idx, fmt.Sprintf("adjustment factor changed %.4gx; dividend %g and split %g imply %.4gx",
+			observed, dividend, split, expected))
+	}
+}
+
+// auditStale reports each run of StaleDays or more consecutive rows
+// holding the same price.
+func auditStale(assetItem asset.Asset, metric Metric, times []time.Time, prices []float64, cfg AuditConfig) []Anomaly {
+	var found []Anomaly
+
+	flush := func(first, last, count int) {
+		if count < cfg.StaleDays {
+			return
+		}
+
+		found = append(found, Anomaly{
+			Asset: assetItem, Metric: metric, Kind: AnomalyStale,
+			Date: times[first], End: times[last], Value: prices[first],
+			Detail: fmt.Sprintf("unchanged for %d trading days", count),
+		})
+	}
+
+	first, last, count := -1, -1, 0
+
+	for idx, price := range prices {
+		if math.IsNaN(price) {
+			continue
+		}
+
+		if first >= 0 && price == prices[first] {
+			last = idx
+			count++
+
+			continue
+		}
+
+		if first >= 0 {
+			flush(first, last, count)
+		}
+
+		first, last, count = idx, idx, 1
+	}
+
+	if first >= 0 {
+		flush(first, last, count)
+	}
+
+	return found
+}
+
+// auditGaps reports runs of consecutive trading days between the first
+// and last price on which the series has no price. Missing days are
+// stamped with the time of day of the price before them.
+func auditGaps(assetItem asset.Asset, metric Metric, times []time.Time, prices []float64, cfg AuditConfig) []Anomaly {
+	loc := cfg.Calendar.Location()
+	observed := make(map[string]bool)
+
+	var first, last time.Time
+
+	for idx, price := range prices {
+		if math.IsNaN(price) {
+			continue
+		}
+
+		at := times[idx].In(loc)
+		observed[at.Format(time.DateOnly)] = true
+
+		if first.IsZero() {
+			first = at
+		}
+
+		last = at
+	}
+
+	if first.IsZero() {
+		return nil
+	}
+
+	var (
+		found   []Anomaly
+		current *Anomaly
+		stamp   = first
+	)
+
+	for day := first.AddDate(0, 0, 1); !day.After(last); day = day.AddDate(0, 0, 1) {
+		if !cfg.Calendar.IsTradingDay(day) {
+			continue
+		}
+
+		if observed[day.Format(time.DateOnly)] {
+			stamp = day
+			current = nil
+
+			continue
+		}
+
+		at := time.Date(day.Year(), day.Month(), day.Day(), stamp.Hour(), stamp.Minute(), stamp.Second(), 0, loc)
+
+		if current != nil {
+			current.End = at
+			current.Detail = fmt.Sprintf("no price on %d trading days", daysInGap(current, cfg.Calendar))
+
+			continue
+		}
+
+		found = append(found, Anomaly{
+			Asset: assetItem, Metric: metric, Kind: AnomalyGap,
+			Date: at, End: at, Value: math.NaN(), Detail: "no price on 1 trading day",
+		})
+		current = &found[len(found)-1]
+	}
+
+	return found
+}
+
+// daysInGap counts the trading days spanned by a gap anomaly.
+func daysInGap(gap *Anomaly, cal tradecron.Calendar) int {
+	count := 0
+
+	for day := gap.Date; !day.After(gap.End); day = day.AddDate(0, 0, 1) {
+		if cal.IsTradingDay(day) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// sortAnomalies orders anomalies by ticker, date, metric, and kind.
+func sortAnomalies(anomalies []Anomaly) {
+	sort.SliceStable(anomalies, func(i, j int) bool {
+		left, right := anomalies[i], anomalies[j]
+
+		switch {
+		case left.Asset.Ticker != right.Asset.Ticker:
+			return left.Asset.Ticker < right.Asset.Ticker
+		case !left.Date.Equal(right.Date):
+			return left.Date.Before(right.Date)
+		case left.Metric != right.Metric:
+			return left.Metric < right.Metric
+		default:
+			return left.Kind < right.Kind
+		}
+	})
+}
+
+// anomalyKey identifies an anomaly so repeated fetches of the same data
+// record it once.
+type anomalyKey struct {
+	figi   string
+	metric Metric
+	kind   AnomalyKind
+	date   int64
+}
+
+// AuditingProvider wraps a BatchProvider and audits every daily frame it
+// returns with Audit. Requests for any price metric are widened to the
+// audit inputs the wrapped provider serves (open, high, low, close,
+// adjusted close, dividend, and split factor) so that every check can
+// run; the caller still receives only the metrics it asked for. Frames
+// pass through unchanged.
+//
+// The anomalies found are kept for the life of the provider and are
+// available from Anomalies and AnomaliesFor. When the wrapped provider
+// implements CacheKeyed, the engine's disk cache stores the audited data
+// under the wrapped key marked as audited and passes every chunk it
+// serves back to AuditCached.
+type AuditingProvider struct {
+	inner  BatchProvider
+	config AuditConfig
+
+	mu        sync.Mutex
+	anomalies map[anomalyKey]Anomaly
+}
+
+var (
+	_ BatchProvider   = (*AuditingProvider)(nil)
+	_ AnomalyReporter = (*AuditingProvider)(nil)
+	_ CacheKeyed      = (*AuditingProvider)(nil)
+	_ CacheAuditor    = (*AuditingProvider)(nil)
+)
+
+// NewAuditingProvider wraps inner with an audit using cfg.
+func NewAuditingProvider(inner BatchProvider, cfg AuditConfig) *AuditingProvider {
+	return &AuditingProvider{
+		inner:     inner,
+		config:    cfg.withDefaults(),
+		anomalies: make(map[anomalyKey]Anomaly),
+	}
+}
+
+// Provides returns the wrapped provider's metrics.
+func (p *AuditingProvider) Provides() []Metric { return p.inner.Provides() }
+
+// Close closes the wrapped provider.
+func (p *AuditingProvider) Close() error { return p.inner.Close() }
+
+// CacheKey returns the wrapped provider's cache key marked as audited, or
+// an empty key when the wrapped provider is not cached.
+func (p *AuditingProvider) CacheKey() string {
+	keyed, ok := p.inner.(CacheKeyed)
+	if !ok {
+		return ""
+	}
+
+	key := keyed.CacheKey()
+	if key == "" {
+		return ""
+	}
+
+	return key + "|audited"
+}
+
+// AuditMetrics returns metrics widened with the audit inputs the wrapped
+// provider serves. Metrics without a price metric are returned unchanged.
+func (p *AuditingProvider) AuditMetrics(metrics []Metric) []Metric {
+	if !slices.ContainsFunc(metrics, isAuditMetric) {
+		return metrics
+	}
+
+	widened := slices.Clone(metrics)
+
+	provided := p.inner.Provides()
+	for _, metric := range auditMetrics {
+		if slices.Contains(provided, metric) && !slices.Contains(widened, metric) {
+			widened = append(widened, metric)
+		}
+	}
+
+	return widened
+}
+
+// AuditCached audits a daily frame the engine's disk cache served in
+// place of a fetch.
+func (p *AuditingProvider) AuditCached(df *DataFrame) {
+	p.record(Audit(df, p.config))
+}
+
+// Fetch fetches from the wrapped provider and audits the result.
+func (p *AuditingProvider) Fetch(ctx context.Context, req DataRequest) (*DataFrame, error) {
+	if req.Frequency != Daily || !slices.ContainsFunc(req.Metrics, isAuditMetric) {
+		return p.inner.Fetch(ctx, req)
+	}
+
+	widened := req
+	widened.Metrics = p.AuditMetrics(req.Metrics)
+
+	df, err := p.inner.Fetch(ctx, widened)
+	if err != nil {
+		return nil, err
+	}
+
+	p.record(Audit(df, p.config))
+
+	if len(widened.Metrics) == len(req.Metrics) {
+		return df, nil
+	}
+
+	return df.Metrics(req.Metrics...), nil
+}
+
+func isAuditMetric(metric Metric) bool {
+	return slices.Contains(auditMetrics, metric)
+}
+
+func (p *AuditingProvider) record(found []Anomaly) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, anomaly := range found {
+		key := anomalyKey{
+			figi:   anomaly.Asset.CompositeFigi,
+			metric: anomaly.Metric,
+			kind:   anomaly.Kind,
+			date:   anomaly.Date.Unix(),
+		}
+		p.anomalies[key] = anomaly
+	}
+}
+
+// Anomalies returns every anomaly found so far, ordered by asset, date,
+// and kind.
+func (p *AuditingProvider) Anomalies() []Anomaly {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	found := make([]Anomaly, 0, len(p.anomalies))
+	for _, anomaly := range p.anomalies {
+		found = append(found, anomaly)
+	}
+
+	sortAnomalies(found)
+
+	return found
+}
+
+// AnomaliesFor returns the anomalies found for a that overlap
+// [start, end], ordered by date and kind.
+func (p *AuditingProvider) AnomaliesFor(a asset.Asset, start, end time.Time) []Anomaly {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var found []Anomaly
+
+	for _, anomaly := range p.anomalies {
+		if anomaly.Asset.CompositeFigi == a.CompositeFigi && anomaly.Overlaps(start, end) {
+			found = append(found, anomaly)
+		}
+	}
+
+	sortAnomalies(found)
+
+	return found
+}
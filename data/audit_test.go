@@ -0,0 +1,291 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data_test
+
+import (
+	"context"
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
+)
+
+var _ = Describe("Audit", func() {
+	var (
+		spy asset.Asset
+		nyc *time.Location
+	)
+
+	BeforeEach(func() {
+		spy = asset.Asset{CompositeFigi: "FIGI-SPY", Ticker: "SPY"}
+
+		var err error
+		nyc, err = time.LoadLocation("America/New_York")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	// tradingDays returns n consecutive NYSE trading days at the close
+	// starting on 2024-01-02.
+	tradingDays := func(n int) []time.Time {
+		holidays := map[string]bool{"2024-01-15": true, "2024-02-19": true}
+
+		var days []time.Time
+
+		for day := time.Date(2024, 1, 2, 16, 0, 0, 0, nyc); len(days) < n; day = day.AddDate(0, 0, 1) {
+			if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday || holidays[day.Format(time.DateOnly)] {
+				continue
+			}
+
+			days = append(days, day)
+		}
+
+		return days
+	}
+
+	// rising returns n closes starting at 100 and gaining 1% a day.
+	rising := func(n int) []float64 {
+		closes := make([]float64, n)
+		for idx := range closes {
+			closes[idx] = 100 * math.Pow(1.01, float64(idx))
+		}
+
+		return closes
+	}
+
+	frame := func(times []time.Time, metrics []data.Metric, columns ...[]float64) *data.DataFrame {
+		df, err := data.NewDataFrame(times, []asset.Asset{spy}, metrics, data.Daily, columns)
+		Expect(err).NotTo(HaveOccurred())
+
+		return df
+	}
+
+	kinds := func(found []data.Anomaly) []data.AnomalyKind {
+		result := make([]data.AnomalyKind, len(found))
+		for idx, anomaly := range found {
+			result[idx] = anomaly.Kind
+		}
+
+		return result
+	}
+
+	It("finds nothing in a clean series", func() {
+		times := tradingDays(30)
+		found := data.Audit(frame(times, []data.Metric{data.MetricClose}, rising(30)), data.AuditConfig{})
+		Expect(found).To(BeEmpty())
+	})
+
+	It("flags zero prices", func() {
+		closes := rising(10)
+		closes[4] = 0
+
+		found := data.Audit(frame(tradingDays(10), []data.Metric{data.MetricClose}, closes), data.AuditConfig{})
+		Expect(kinds(found)).To(ContainElement(data.AnomalyZeroPrice))
+	})
+
+	It("flags a 100x spike", func() {
+		times := tradingDays(10)
+		closes := rising(10)
+		closes[5] *= 100
+
+		found := data.Audit(frame(times, []data.Metric{data.MetricClose}, closes), data.AuditConfig{})
+		Expect(found).To(HaveLen(2))
+		Expect(found[0].Kind).To(Equal(data.AnomalySpike))
+		Expect(found[0].Date).To(Equal(times[5]))
+		Expect(found[1].Date).To(Equal(times[6]))
+	})
+
+	It("accepts a move explained by the split factor", func() {
+		closes := rising(10)
+		splits := make([]float64, 10)
+
+		for idx := range closes {
+			splits[idx] = 1
+			if idx >= 5 {
+				closes[idx] /= 4
+			}
+		}
+
+		splits[5] = 4
+
+		found := data.Audit(frame(tradingDays(10), []data.Metric{data.MetricClose, data.SplitFactor}, closes, splits), data.AuditConfig{})
+		Expect(found).To(BeEmpty())
+	})
+
+	It("flags a split-sized move with no split factor", func() {
+		times := tradingDays(10)
+		closes := rising(10)
+		splits := make([]float64, 10)
+
+		for idx := range closes {
+			splits[idx] = 1
+			if idx >= 5 {
+				closes[idx] /= 2
+			}
+		}
+
+		found := data.Audit(frame(times, []data.Metric{data.MetricClose, data.SplitFactor}, closes, splits), data.AuditConfig{})
+		Expect(kinds(found)).To(Equal([]data.AnomalyKind{data.AnomalyMissingSplit}))
+		Expect(found[0].Date).To(Equal(times[5]))
+	})
+
+	It("flags a split factor the price did not follow", func() {
+		splits := make([]float64, 10)
+		for idx := range splits {
+			splits[idx] = 1
+		}
+
+		splits[5] = 3
+
+		found := data.Audit(frame(tradingDays(10), []data.Metric{data.MetricClose, data.SplitFactor}, rising(10), splits), data.AuditConfig{})
+		Expect(kinds(found)).To(Equal([]data.AnomalyKind{data.AnomalySplitMismatch}))
+	})
+
+	It("reports a run of unchanged prices once", func() {
+		times := tradingDays(20)
+		closes := rising(20)
+
+		for idx := 8; idx < 14; idx++ {
+			closes[idx] = closes[7]
+		}
+
+		found := data.Audit(frame(times, []data.Metric{data.MetricClose}, closes), data.AuditConfig{})
+		Expect(kinds(found)).To(Equal([]data.AnomalyKind{data.AnomalyStale}))
+		Expect(found[0].Date).To(Equal(times[7]))
+		Expect(found[0].End).To(Equal(times[13]))
+		Expect(found[0].Detail).To(ContainSubstring("7 trading days"))
+	})
+
+	It("flags missing trading days but not holidays or weekends", func() {
+		all := tradingDays(20)
+
+		// Drop Jan 9 and Jan 10; the series already skips the Jan 15 holiday.
+		var times []time.Time
+
+		for _, day := range all {
+			if day.Day() == 9 || day.Day() == 10 {
+				continue
+			}
+
+			times = append(times, day)
+		}
+
+		found := data.Audit(frame(times, []data.Metric{data.MetricClose}, rising(len(times))), data.AuditConfig{})
+		Expect(kinds(found)).To(Equal([]data.AnomalyKind{data.AnomalyGap}))
+		Expect(found[0].Date).To(Equal(time.Date(2024, 1, 9, 16, 0, 0, 0, nyc)))
+		Expect(found[0].End).To(Equal(time.Date(2024, 1, 10, 16, 0, 0, 0, nyc)))
+		Expect(found[0].Detail).To(ContainSubstring("2 trading days"))
+	})
+
+	It("flags NaN prices on trading days as gaps", func() {
+		closes := rising(10)
+		closes[3] = math.NaN()
+
+		found := data.Audit(frame(tradingDays(10), []data.Metric{data.MetricClose}, closes), data.AuditConfig{})
+		Expect(kinds(found)).To(Equal([]data.AnomalyKind{data.AnomalyGap}))
+	})
+
+	It("flags negative and oversized dividends", func() {
+		dividends := make([]float64, 10)
+		dividends[3] = -0.5
+		dividends[7] = 500
+
+		found := data.Audit(frame(tradingDays(10), []data.Metric{data.MetricClose, data.Dividend}, rising(10), dividends), data.AuditConfig{})
+		Expect(kinds(found)).To(Equal([]data.AnomalyKind{data.AnomalyBadDividend, data.AnomalyBadDividend}))
+	})
+
+	It("checks adjusted closes against dividends", func() {
+		closes := rising(10)
+		adjusted := make([]float64, 10)
+		dividends := make([]float64, 10)
+
+		// A 2% dividend on day 5: adjusted closes before it are scaled down.
+		dividends[5] = closes[4] * 0.02
+		for idx := range closes {
+			adjusted[idx] = closes[idx]
+			if idx < 5 {
+				adjusted[idx] *= 0.98
+			}
+		}
+
+		metrics := []data.Metric{data.MetricClose, data.AdjClose, data.Dividend}
+		Expect(data.Audit(frame(tradingDays(10), metrics, closes, adjusted, dividends), data.AuditConfig{})).To(BeEmpty())
+
+		dividends[5] = 0
+		found := data.Audit(frame(tradingDays(10), metrics, closes, adjusted, dividends), data.AuditConfig{})
+		Expect(kinds(found)).To(Equal([]data.AnomalyKind{data.AnomalyAdjustmentMismatch}))
+	})
+
+	Describe("AuditingProvider", func() {
+		It("audits the widened request and returns only the requested metrics", func() {
+			times := tradingDays(10)
+			closes := rising(10)
+			closes[5] = 0
+			volumes := make([]float64, 10)
+
+			inner := data.NewTestProvider(
+				[]data.Metric{data.MetricClose, data.Volume},
+				frame(times, []data.Metric{data.MetricClose, data.Volume}, closes, volumes),
+			)
+			provider := data.NewAuditingProvider(inner, data.AuditConfig{})
+
+			df, err := provider.Fetch(context.Background(), data.DataRequest{
+				Assets:    []asset.Asset{spy},
+				Metrics:   []data.Metric{data.Volume},
+				Start:     times[0],
+				End:       times[9],
+				Frequency: data.Daily,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(df.MetricList()).To(Equal([]data.Metric{data.Volume}))
+			Expect(provider.Anomalies()).To(BeEmpty())
+
+			df, err = provider.Fetch(context.Background(), data.DataRequest{
+				Assets:    []asset.Asset{spy},
+				Metrics:   []data.Metric{data.MetricClose},
+				Start:     times[0],
+				End:       times[9],
+				Frequency: data.Daily,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(df.MetricList()).To(Equal([]data.Metric{data.MetricClose}))
+
+			Expect(kinds(provider.Anomalies())).To(ContainElement(data.AnomalyZeroPrice))
+			Expect(provider.AnomaliesFor(spy, times[5], times[5])).NotTo(BeEmpty())
+			Expect(provider.AnomaliesFor(spy, times[0], times[3])).To(BeEmpty())
+		})
+
+		It("marks the wrapped provider's cache key as audited", func() {
+			inner := data.NewTestProvider([]data.Metric{data.MetricClose},
+				frame(tradingDays(1), []data.Metric{data.MetricClose}, rising(1)))
+
+			Expect(data.NewAuditingProvider(inner, data.AuditConfig{}).CacheKey()).To(BeEmpty())
+
+			keyed := data.NewAuditingProvider(keyedTestProvider{inner}, data.AuditConfig{})
+			Expect(keyed.CacheKey()).To(Equal("vendor-db|audited"))
+		})
+	})
+})
+
+// keyedTestProvider is a TestProvider the disk cache may store.
+type keyedTestProvider struct {
+	*data.TestProvider
+}
+
+func (keyedTestProvider) CacheKey() string { return "vendor-db" }
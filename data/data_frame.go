@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/tradecron"
 	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/stat"
 )
@@ -71,6 +72,10 @@ type DataFrame struct {
 	// risk-free return. Shared by pointer across same-time-axis transformations.
 	riskFreeRates []float64
 
+	// calendar is the market calendar the data was fetched on. It sets
+	// the number of daily periods in a year for annualized rates.
+	calendar tradecron.Calendar
+
 	// source is the DataSource that populated this DataFrame. Weighting
 	// functions and other consumers use it to fetch additional data on demand.
 	source DataSource
@@ -107,6 +112,14 @@ func (df *DataFrame) SetRiskFreeRates(rates []float64) error {
 	return nil
 }
 
+// SetCalendar records the market calendar the DataFrame's data trades on.
+// RiskAdjustedPct uses it to convert annualized yields to daily rates.
+func (df *DataFrame) SetCalendar(cal tradecron.Calendar) { df.calendar = cal }
+
+// Calendar returns the market calendar set by SetCalendar, or nil when
+// none has been set; callers should then assume NYSE.
+func (df *DataFrame) Calendar() tradecron.Calendar { return df.calendar }
+
 // RiskFreeRates returns the attached cumulative risk-free rate values, or nil
 // if none have been set.
 func (df *DataFrame) RiskFreeRates() []float64 {
@@ -117,6 +130,7 @@ func (df *DataFrame) RiskFreeRates() []float64 {
 // receiver to the target DataFrame. Returns target for chaining.
 func (df *DataFrame) propagateAux(target *DataFrame) *DataFrame {
 	target.riskFreeRates = df.riskFreeRates
+	target.calendar = df.calendar
 	target.dateKeys = df.dateKeys
 
 	return target
@@ -435,6 +449,8 @@ func (df *DataFrame) At(t time.Time) *DataFrame {
 		result.riskFreeRates = []float64{df.riskFreeRates[tIdx]}
 	}
 
+	result.calendar = df.calendar
+
 	return result
 }
 
@@ -481,6 +497,8 @@ func (df *DataFrame) Copy() *DataFrame {
 		result.riskFreeRates = rfCopy
 	}
 
+	result.calendar = df.calendar
+
 	if df.dateKeys != nil {
 		dkCopy := make([]int32, len(df.dateKeys))
 		copy(dkCopy, df.dateKeys)
@@ -677,6 +695,8 @@ func (df *DataFrame) sliceByTimeIndices(startIdx, endIdx int) *DataFrame {
 		result.riskFreeRates = df.riskFreeRates[startIdx:endIdx:endIdx]
 	}
 
+	result.calendar = df.calendar
+
 	if df.dateKeys != nil {
 		result.dateKeys = df.dateKeys[startIdx:endIdx:endIdx]
 	}
@@ -761,6 +781,8 @@ func (df *DataFrame) sliceByIndices(indices []int) *DataFrame {
 		result.riskFreeRates = rfSlice
 	}
 
+	result.calendar = df.calendar
+
 	if df.dateKeys != nil {
 		dkSlice := make([]int32, newTimeLen)
 		for newIdx, oldIdx := range indices {
@@ -1752,7 +1774,7 @@ func (df *DataFrame) RiskAdjustedPct(periods ...int) *DataFrame {
 	}
 
 	rf := df.riskFreeRates
-	periodsPerYear := df.freq.PeriodsPerYear()
+	periodsPerYear := df.freq.PeriodsPerYearOn(df.calendar)
 
 	return df.Apply(func(col []float64) []float64 {
 		out := make([]float64, len(col))
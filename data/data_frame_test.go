@@ -26,6 +26,7 @@ import (
 
 	"github.com/penny-vault/pvbt/asset"
 	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/tradecron"
 )
 
 type mockFrameDataSource struct{}
@@ -1725,6 +1726,18 @@ var _ = Describe("DataFrame", func() {
 				Expect(col[1]).To(BeNumerically("~", expected, 1e-10))
 			})
 
+			It("uses the trading days per year of the frame's calendar", func() {
+				Expect(df.SetRiskFreeRates([]float64{4.5, 4.5, 4.5, 4.5, 4.5})).To(Succeed())
+				df.SetCalendar(tradecron.Crypto)
+
+				Expect(df.Pct().Calendar()).To(Equal(tradecron.Crypto))
+
+				col := df.RiskAdjustedPct().Column(aapl, data.Price)
+				// rf = 4.5 / 365 / 100 on a 24/7 calendar
+				expected := 0.01 - 4.5/365.0/100.0
+				Expect(col[1]).To(BeNumerically("~", expected, 1e-10))
+			})
+
 			It("does not modify original", func() {
 				rates := []float64{4.5, 4.5, 4.5, 4.5, 4.5}
 				Expect(df.SetRiskFreeRates(rates)).To(Succeed())
@@ -81,6 +81,15 @@ type HolidayProvider interface {
 	FetchMarketHolidays(ctx context.Context) ([]tradecron.MarketHoliday, error)
 }
 
+// CacheKeyed is implemented by providers whose data may be cached across
+// runs. CacheKey returns a stable identifier for the provider's underlying
+// source (database, snapshot file, data directory) so a persistent cache
+// keeps entries from different sources apart. Providers that do not
+// implement it, or return an empty key, are never cached on disk.
+type CacheKeyed interface {
+	CacheKey() string
+}
+
 // FundamentalsByDateKeyProvider is implemented by providers that can
 // return fundamentals filtered to a specific reporting period (date_key).
 // The engine type-asserts on this interface from FetchFundamentalsByDateKey.
@@ -0,0 +1,296 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
+)
+
+// Derived fundamentals are computed from a company's quarterly filings
+// instead of being read from a provider column. A derived metric is an
+// ordinary Metric whose name records the operation and its operand, e.g.
+// "TTM(Revenue)", so it can be requested anywhere a metric is accepted.
+// Derived metrics nest: PerShare(TTM(FreeCashFlow)) is trailing-twelve-
+// month free cash flow per share.
+
+// derivedOp names an operation on a quarterly fundamental series.
+type derivedOp string
+
+const (
+	derivedTTM       derivedOp = "TTM"
+	derivedYoYGrowth derivedOp = "YoYGrowth"
+	derivedPerShare  derivedOp = "PerShare"
+)
+
+// derivedFilingLag is how many quarter ends before the latest one on or
+// before the as-of date are searched for the most recent filing. Annual
+// reports can be filed up to 90 days after the fiscal year ends, so the
+// latest two quarters are often not yet public.
+const derivedFilingLag = 2
+
+// TTM returns the derived metric summing metric over the four most
+// recent fiscal quarters. The result is NaN unless all four quarters
+// have been filed.
+func TTM(metric Metric) Metric {
+	return deriveMetric(derivedTTM, metric)
+}
+
+// YoYGrowth returns the derived metric measuring the change in metric
+// from the same fiscal quarter a year earlier, as a fraction of the
+// absolute year-ago value. 0.1 means 10% growth.
+func YoYGrowth(metric Metric) Metric {
+	return deriveMetric(derivedYoYGrowth, metric)
+}
+
+// PerShare returns the derived metric dividing metric by
+// WeightedAverageShares from the same filing.
+func PerShare(metric Metric) Metric {
+	return deriveMetric(derivedPerShare, metric)
+}
+
+func deriveMetric(op derivedOp, metric Metric) Metric {
+	return Metric(string(op) + "(" + string(metric) + ")")
+}
+
+// parseDerived splits a derived metric into its operation and operand.
+func parseDerived(metric Metric) (derivedOp, Metric, bool) {
+	name := string(metric)
+
+	open := strings.IndexByte(name, '(')
+	if open <= 0 || !strings.HasSuffix(name, ")") {
+		return "", "", false
+	}
+
+	op := derivedOp(name[:open])
+	switch op {
+	case derivedTTM, derivedYoYGrowth, derivedPerShare:
+		return op, Metric(name[open+1 : len(name)-1]), true
+	default:
+		return "", "", false
+	}
+}
+
+// IsDerived reports whether metric was built by TTM, YoYGrowth, or
+// PerShare.
+func IsDerived(metric Metric) bool {
+	_, _, ok := parseDerived(metric)
+	return ok
+}
+
+// DerivedInputs returns the stored fundamental metrics a derived metric
+// is computed from. The operand's underlying fundamental is first. It
+// returns an error if metric is not derived or its operand is not a
+// fundamental metric.
+func DerivedInputs(metric Metric) ([]Metric, error) {
+	if !IsDerived(metric) {
+		return nil, fmt.Errorf("%q is not a derived metric", metric)
+	}
+
+	perShare := false
+	current := metric
+
+	for {
+		op, operand, ok := parseDerived(current)
+		if !ok {
+			break
+		}
+
+		perShare = perShare || op == derivedPerShare
+		current = operand
+	}
+
+	if !IsFundamental(current) || current == FundamentalsDateKey || current == FundamentalsReportPeriod {
+		return nil, fmt.Errorf("derived metric %q: %q is not a fundamental metric", metric, current)
+	}
+
+	inputs := []Metric{current}
+	if perShare && current != WeightedAverageShares {
+		inputs = append(inputs, WeightedAverageShares)
+	}
+
+	return inputs, nil
+}
+
+// derivedQuarters returns how many consecutive quarters, ending with the
+// one being evaluated, metric reads.
+func derivedQuarters(metric Metric) int {
+	op, operand, ok := parseDerived(metric)
+	if !ok {
+		return 1
+	}
+
+	switch op {
+	case derivedTTM:
+		return derivedQuarters(operand) + 3
+	case derivedYoYGrowth:
+		return derivedQuarters(operand) + 4
+	default:
+		return derivedQuarters(operand)
+	}
+}
+
+// derivedSeries evaluates metric over quarterly input series ordered
+// oldest first.
+func derivedSeries(metric Metric, inputs map[Metric][]float64, numQuarters int) []float64 {
+	op, operand, ok := parseDerived(metric)
+	if !ok {
+		return inputs[metric]
+	}
+
+	src := derivedSeries(operand, inputs, numQuarters)
+	out := make([]float64, numQuarters)
+
+	for idx := range out {
+		out[idx] = math.NaN()
+	}
+
+	switch op {
+	case derivedTTM:
+		for idx := 3; idx < numQuarters; idx++ {
+			out[idx] = src[idx] + src[idx-1] + src[idx-2] + src[idx-3]
+		}
+	case derivedYoYGrowth:
+		for idx := 4; idx < numQuarters; idx++ {
+			if prior := src[idx-4]; prior != 0 {
+				out[idx] = (src[idx] - prior) / math.Abs(prior)
+			}
+		}
+	case derivedPerShare:
+		shares := inputs[WeightedAverageShares]
+		for idx := range out {
+			if shares[idx] != 0 {
+				out[idx] = src[idx] / shares[idx]
+			}
+		}
+	}
+
+	return out
+}
+
+// FundamentalsAsOf returns one row per asset of fundamentals for the
+// reporting period dateKey, using only filings with event_date <=
+// maxEventDate. It has the shape of
+// FundamentalsByDateKeyProvider.FetchFundamentalsByDateKey with the
+// dimension already chosen.
+type FundamentalsAsOf func(ctx context.Context, assets []asset.Asset, metrics []Metric,
+	dateKey, maxEventDate time.Time) (*DataFrame, error)
+
+// EvaluateDerived computes derived metrics for each asset from the
+// filings public at asOf and returns a single-row DataFrame at asOf.
+// Each value is anchored on the asset's most recent filed quarter; if
+// that quarter or any earlier one the metric needs is missing, the
+// value is NaN rather than taken from an older quarter. fetch should
+// query a quarterly dimension.
+func EvaluateDerived(ctx context.Context, fetch FundamentalsAsOf, assets []asset.Asset, metrics []Metric, asOf time.Time) (*DataFrame, error) {
+	var (
+		inputs      []Metric
+		numQuarters int
+	)
+
+	bases := make([]Metric, len(metrics))
+
+	for mIdx, metric := range metrics {
+		metricInputs, err := DerivedInputs(metric)
+		if err != nil {
+			return nil, err
+		}
+
+		bases[mIdx] = metricInputs[0]
+
+		for _, input := range metricInputs {
+			if !slices.Contains(inputs, input) {
+				inputs = append(inputs, input)
+			}
+		}
+
+		numQuarters = max(numQuarters, derivedQuarters(metric))
+	}
+
+	numQuarters += derivedFilingLag
+
+	dateKeys := make([]time.Time, numQuarters)
+	dateKeys[numQuarters-1] = quarterEndOnOrBefore(asOf)
+
+	for idx := numQuarters - 2; idx >= 0; idx-- {
+		next := dateKeys[idx+1]
+		dateKeys[idx] = time.Date(next.Year(), next.Month()-2, 0, 0, 0, 0, 0, time.UTC)
+	}
+
+	// series[asset][input] holds one value per date key, oldest first.
+	series := make([]map[Metric][]float64, len(assets))
+	for aIdx := range assets {
+		series[aIdx] = make(map[Metric][]float64, len(inputs))
+		for _, input := range inputs {
+			series[aIdx][input] = make([]float64, numQuarters)
+		}
+	}
+
+	for qIdx, dateKey := range dateKeys {
+		df, err := fetch(ctx, assets, inputs, dateKey, asOf)
+		if err != nil {
+			return nil, fmt.Errorf("derived fundamentals for %s: %w", dateKey.Format(time.DateOnly), err)
+		}
+
+		for aIdx, assetItem := range assets {
+			for _, input := range inputs {
+				series[aIdx][input][qIdx] = df.Value(assetItem, input)
+			}
+		}
+	}
+
+	columns := make([][]float64, len(assets)*len(metrics))
+
+	for aIdx := range assets {
+		for mIdx, metric := range metrics {
+			value := math.NaN()
+
+			base := series[aIdx][bases[mIdx]]
+			for qIdx := numQuarters - 1; qIdx >= 0; qIdx-- {
+				if !math.IsNaN(base[qIdx]) {
+					value = derivedSeries(metric, series[aIdx], numQuarters)[qIdx]
+					break
+				}
+			}
+
+			columns[aIdx*len(metrics)+mIdx] = []float64{value}
+		}
+	}
+
+	return NewDataFrame([]time.Time{asOf}, assets, metrics, Daily, columns)
+}
+
+// quarterEndOnOrBefore returns the calendar quarter end, as a UTC date,
+// on or before t's calendar date.
+func quarterEndOnOrBefore(t time.Time) time.Time {
+	year, month, day := t.Date()
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	endMonth := ((month-1)/3)*3 + 3
+	end := time.Date(year, endMonth+1, 0, 0, 0, 0, 0, time.UTC)
+
+	if end.After(date) {
+		end = time.Date(end.Year(), end.Month()-2, 0, 0, 0, 0, 0, time.UTC)
+	}
+
+	return end
+}
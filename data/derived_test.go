@@ -0,0 +1,144 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data_test
+
+import (
+	"context"
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
+)
+
+var _ = Describe("Derived fundamentals", func() {
+	type filing struct {
+		figi    string
+		dateKey time.Time
+		filed   time.Time
+		values  map[data.Metric]float64
+	}
+
+	var (
+		aapl    asset.Asset
+		msft    asset.Asset
+		filings []filing
+		fetch   data.FundamentalsAsOf
+	)
+
+	quarter := func(year int, month time.Month) time.Time {
+		return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+	}
+
+	BeforeEach(func() {
+		aapl = asset.Asset{CompositeFigi: "FIGI-AAPL", Ticker: "AAPL"}
+		msft = asset.Asset{CompositeFigi: "FIGI-MSFT", Ticker: "MSFT"}
+
+		// Five quarters of revenue, each filed 40 days after quarter end.
+		// MSFT skipped its 2023 Q2 filing.
+		filings = nil
+
+		for idx, dateKey := range []time.Time{
+			quarter(2023, time.March), quarter(2023, time.June), quarter(2023, time.September),
+			quarter(2023, time.December), quarter(2024, time.March),
+		} {
+			revenue := []float64{100, 110, 120, 130, 150}[idx]
+			for _, figi := range []string{aapl.CompositeFigi, msft.CompositeFigi} {
+				if figi == msft.CompositeFigi && idx == 1 {
+					continue
+				}
+
+				filings = append(filings, filing{
+					figi:    figi,
+					dateKey: dateKey,
+					filed:   dateKey.AddDate(0, 0, 40),
+					values:  map[data.Metric]float64{data.Revenue: revenue, data.WeightedAverageShares: 10},
+				})
+			}
+		}
+
+		fetch = func(_ context.Context, assets []asset.Asset, metrics []data.Metric, dateKey, maxEventDate time.Time) (*data.DataFrame, error) {
+			columns := make([][]float64, len(assets)*len(metrics))
+
+			for aIdx, assetItem := range assets {
+				for mIdx, metric := range metrics {
+					value := math.NaN()
+
+					for _, row := range filings {
+						if row.figi == assetItem.CompositeFigi && row.dateKey.Equal(dateKey) && !row.filed.After(maxEventDate) {
+							value = row.values[metric]
+						}
+					}
+
+					columns[aIdx*len(metrics)+mIdx] = []float64{value}
+				}
+			}
+
+			return data.NewDataFrame([]time.Time{dateKey}, assets, metrics, data.Daily, columns)
+		}
+	})
+
+	evaluate := func(asOf time.Time, metrics ...data.Metric) *data.DataFrame {
+		df, err := data.EvaluateDerived(context.Background(), fetch, []asset.Asset{aapl, msft}, metrics, asOf)
+		Expect(err).NotTo(HaveOccurred())
+
+		return df
+	}
+
+	It("names derived metrics after the operation and operand", func() {
+		Expect(data.TTM(data.Revenue)).To(Equal(data.Metric("TTM(Revenue)")))
+		Expect(data.PerShare(data.TTM(data.FreeCashFlow))).To(Equal(data.Metric("PerShare(TTM(FreeCashFlow))")))
+		Expect(data.IsDerived(data.YoYGrowth(data.EarningsPerShare))).To(BeTrue())
+		Expect(data.IsDerived(data.Revenue)).To(BeFalse())
+
+		inputs, err := data.DerivedInputs(data.PerShare(data.TTM(data.FreeCashFlow)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inputs).To(Equal([]data.Metric{data.FreeCashFlow, data.WeightedAverageShares}))
+
+		_, err = data.DerivedInputs(data.TTM(data.MetricClose))
+		Expect(err).To(MatchError(ContainSubstring("not a fundamental metric")))
+	})
+
+	It("uses only filings public at the as-of date", func() {
+		// 2024 Q1 is filed on May 10, 2024.
+		before := evaluate(time.Date(2024, 4, 15, 16, 0, 0, 0, time.UTC), data.TTM(data.Revenue))
+		Expect(before.Value(aapl, data.TTM(data.Revenue))).To(Equal(460.0))
+
+		after := evaluate(time.Date(2024, 6, 3, 16, 0, 0, 0, time.UTC), data.TTM(data.Revenue))
+		Expect(after.Value(aapl, data.TTM(data.Revenue))).To(Equal(510.0))
+	})
+
+	It("computes year-over-year growth and per-share values", func() {
+		asOf := time.Date(2024, 6, 3, 16, 0, 0, 0, time.UTC)
+		df := evaluate(asOf, data.YoYGrowth(data.Revenue), data.PerShare(data.TTM(data.Revenue)), data.PerShare(data.Revenue))
+
+		Expect(df.Times()).To(Equal([]time.Time{asOf}))
+		Expect(df.Value(aapl, data.YoYGrowth(data.Revenue))).To(BeNumerically("~", 0.5, 1e-12))
+		Expect(df.Value(aapl, data.PerShare(data.TTM(data.Revenue)))).To(Equal(51.0))
+		Expect(df.Value(aapl, data.PerShare(data.Revenue))).To(Equal(15.0))
+	})
+
+	It("returns NaN when a needed quarter is missing instead of using stale data", func() {
+		df := evaluate(time.Date(2024, 3, 1, 16, 0, 0, 0, time.UTC), data.TTM(data.Revenue), data.YoYGrowth(data.Revenue))
+
+		Expect(df.Value(aapl, data.TTM(data.Revenue))).To(Equal(460.0))
+		Expect(math.IsNaN(df.Value(msft, data.TTM(data.Revenue)))).To(BeTrue())
+		Expect(math.IsNaN(df.Value(aapl, data.YoYGrowth(data.Revenue)))).To(BeTrue())
+	})
+})
@@ -83,6 +83,9 @@
 // and an [IndexConstituent] slice (which includes weight data) for the members
 // that belonged to the index at a given point in time.
 //
+// [OptionChainProvider] supplies the option contracts listed on an
+// underlying at a point in time, each with an end-of-day [OptionQuote].
+//
 // [DataRequest] describes a batch of data to fetch. It specifies the assets,
 // metrics, time range, and [Frequency].
 //
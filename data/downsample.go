@@ -96,6 +96,8 @@ func (d *DownsampledDataFrame) aggregate(reducer func([]float64) float64) *DataF
 		result.riskFreeRates = rfRates
 	}
 
+	result.calendar = d.df.calendar
+
 	return result
 }
 
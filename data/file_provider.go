@@ -0,0 +1,929 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/tradecron"
+)
+
+// Compile-time interface checks.
+var (
+	_ BatchProvider   = (*FileProvider)(nil)
+	_ AssetProvider   = (*FileProvider)(nil)
+	_ HolidayProvider = (*FileProvider)(nil)
+	_ CacheKeyed      = (*FileProvider)(nil)
+)
+
+// DefaultAssetsManifest is the manifest file name FileProvider looks for in
+// its data directory when no manifest path is configured.
+const DefaultAssetsManifest = "assets.csv"
+
+// defaultFileColumns maps normalized column names (lower case with spaces,
+// dashes and underscores removed) to metrics.
+var defaultFileColumns = map[string]Metric{
+	"open":        MetricOpen,
+	"high":        MetricHigh,
+	"low":         MetricLow,
+	"close":       MetricClose,
+	"adjopen":     AdjOpen,
+	"adjhigh":     AdjHigh,
+	"adjlow":      AdjLow,
+	"adjclose":    AdjClose,
+	"volume":      Volume,
+	"adjvolume":   AdjVolume,
+	"dividend":    Dividend,
+	"splitfactor": SplitFactor,
+}
+
+// defaultDateColumns are tried, in order, when no date column is configured.
+var defaultDateColumns = []string{"date", "event_date", "timestamp", "time"}
+
+// fileDateLayouts are tried, in order, when no date layout is configured.
+var fileDateLayouts = []string{
+	"2006-01-02",
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"01/02/2006",
+	"20060102",
+}
+
+// FileProviderOption configures a FileProvider.
+type FileProviderOption func(*fileProviderOptions)
+
+type fileProviderOptions struct {
+	columns    map[string]Metric
+	dateColumn string
+	dateLayout string
+	manifest   string
+}
+
+// WithColumnMap maps file column names to metrics. Entries are matched
+// case-insensitively and take precedence over the built-in OHLCV names
+// (open, high, low, close, adj_close, volume, dividend, split_factor).
+func WithColumnMap(columns map[string]Metric) FileProviderOption {
+	return func(o *fileProviderOptions) {
+		for name, metric := range columns {
+			o.columns[name] = metric
+		}
+	}
+}
+
+// WithDateColumn names the column holding each row's date. By default the
+// first of date, event_date, timestamp or time present in the file is used.
+func WithDateColumn(name string) FileProviderOption {
+	return func(o *fileProviderOptions) { o.dateColumn = name }
+}
+
+// WithDateLayout sets the time.Parse layout for text date columns. By
+// default ISO dates, RFC 3339 timestamps, MM/DD/YYYY and YYYYMMDD are
+// accepted.
+func WithDateLayout(layout string) FileProviderOption {
+	return func(o *fileProviderOptions) { o.dateLayout = layout }
+}
+
+// WithAssetsManifest overrides the assets manifest path. Relative paths
+// are resolved against the data directory.
+func WithAssetsManifest(path string) FileProviderOption {
+	return func(o *fileProviderOptions) { o.manifest = path }
+}
+
+// FileProvider serves daily data from a directory of per-ticker CSV or
+// Parquet files (TICKER.csv, TICKER.parquet). Each file holds one row per
+// trading day with a date column and any number of metric columns; columns
+// that do not map to a metric are ignored.
+//
+// Asset metadata comes from an optional manifest CSV (assets.csv by
+// default) with a ticker column and any of composite_figi, name,
+// asset_type, primary_exchange, currency, sector, industry, sic_code, cik,
+// listed, delisted and file. When a manifest is present only the assets it
+// lists are served; file overrides the data file name for that asset, and
+// currency, when empty, is derived from primary_exchange. Without a
+// manifest every data file becomes an asset named after the file. Assets
+// with no composite_figi use the ticker as their FIGI.
+//
+// Files are parsed on first use and kept in memory for the lifetime of the
+// provider. Date-only values are placed at 4pm Eastern to match the
+// timestamps produced by PVDataProvider.
+type FileProvider struct {
+	dir        string
+	columns    map[string]Metric
+	dateColumn string
+	dateLayout string
+	manifest   string // assets manifest path; empty when there is none
+
+	assets   []asset.Asset
+	byTicker map[string]asset.Asset
+	byFigi   map[string]asset.Asset
+	files    map[string]string // composite figi -> data file path
+	provides []Metric
+
+	mu     sync.Mutex
+	series map[string]*fileSeries // composite figi -> parsed file
+}
+
+// fileSeries is the parsed contents of one data file, sorted by time.
+type fileSeries struct {
+	times  []time.Time
+	values map[Metric][]float64
+}
+
+// NewFileProvider indexes the data directory at dir and reads the header of
+// every data file to determine which metrics are available.
+func NewFileProvider(dir string, opts ...FileProviderOption) (*FileProvider, error) {
+	options := fileProviderOptions{columns: make(map[string]Metric)}
+	for _, fn := range opts {
+		fn(&options)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("file provider: read directory: %w", err)
+	}
+
+	columns := make(map[string]Metric, len(options.columns))
+	for name, metric := range options.columns {
+		columns[strings.ToLower(name)] = metric
+	}
+
+	provider := &FileProvider{
+		dir:        dir,
+		columns:    columns,
+		dateColumn: options.dateColumn,
+		dateLayout: options.dateLayout,
+		byTicker:   make(map[string]asset.Asset),
+		byFigi:     make(map[string]asset.Asset),
+		files:      make(map[string]string),
+		series:     make(map[string]*fileSeries),
+	}
+
+	// Index data files by upper-case ticker.
+	dataFiles := make(map[string]string)
+
+	manifestPath := options.manifest
+	if manifestPath == "" {
+		manifestPath = DefaultAssetsManifest
+	}
+
+	if !filepath.IsAbs(manifestPath) {
+		manifestPath = filepath.Join(dir, manifestPath)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !isDataFile(entry.Name()) {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if path == manifestPath {
+			continue
+		}
+
+		ticker := strings.ToUpper(strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())))
+		if existing, ok := dataFiles[ticker]; ok {
+			return nil, fmt.Errorf("file provider: %s and %s both hold ticker %s",
+				filepath.Base(existing), entry.Name(), ticker)
+		}
+
+		dataFiles[ticker] = path
+	}
+
+	if _, statErr := os.Stat(manifestPath); statErr == nil {
+		provider.manifest = manifestPath
+
+		if err := provider.loadManifest(manifestPath, dataFiles); err != nil {
+			return nil, err
+		}
+	} else if options.manifest != "" {
+		return nil, fmt.Errorf("file provider: assets manifest: %w", statErr)
+	} else {
+		for ticker, path := range dataFiles {
+			provider.addAsset(asset.Asset{CompositeFigi: ticker, Ticker: ticker}, path)
+		}
+	}
+
+	sort.Slice(provider.assets, func(i, j int) bool {
+		return provider.assets[i].Ticker < provider.assets[j].Ticker
+	})
+
+	if err := provider.indexMetrics(); err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+// isDataFile reports whether name has a CSV or Parquet extension.
+func isDataFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv", ".parquet", ".pq":
+		return true
+	}
+
+	return false
+}
+
+func (p *FileProvider) addAsset(aa asset.Asset, path string) {
+	p.assets = append(p.assets, aa)
+	p.byTicker[aa.Ticker] = aa
+	p.byFigi[aa.CompositeFigi] = aa
+
+	if path != "" {
+		p.files[aa.CompositeFigi] = path
+	}
+}
+
+// loadManifest reads the assets manifest and pairs each asset with its
+// data file.
+func (p *FileProvider) loadManifest(path string, dataFiles map[string]string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("file provider: open assets manifest: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("file provider: read assets manifest header: %w", err)
+	}
+
+	col := make(map[string]int, len(header))
+	for idx, name := range header {
+		col[strings.ToLower(strings.TrimSpace(name))] = idx
+	}
+
+	if _, ok := col["ticker"]; !ok {
+		return errors.New("file provider: assets manifest has no ticker column")
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("file provider: read assets manifest: %w", err)
+		}
+
+		field := func(name string) string {
+			idx, ok := col[name]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+
+			return strings.TrimSpace(record[idx])
+		}
+
+		aa := asset.Asset{
+			CompositeFigi:   field("composite_figi"),
+			Ticker:          field("ticker"),
+			Name:            field("name"),
+			AssetType:       asset.AssetType(field("asset_type")),
+			PrimaryExchange: asset.Exchange(field("primary_exchange")),
+			Sector:          asset.Sector(field("sector")),
+			Industry:        asset.Industry(field("industry")),
+			CIK:             field("cik"),
+		}
+
+		// Assets quoted in the base currency leave Currency empty.
+		currency := asset.ParseCurrency(field("currency"))
+		if currency == "" {
+			currency = asset.ExchangeCurrency(aa.PrimaryExchange)
+		}
+
+		if currency != asset.BaseCurrency {
+			aa.Currency = currency
+		}
+
+		if aa.Ticker == "" {
+			return fmt.Errorf("file provider: assets manifest line %d has no ticker", len(p.assets)+2)
+		}
+
+		if aa.CompositeFigi == "" {
+			aa.CompositeFigi = aa.Ticker
+		}
+
+		if _, dup := p.byTicker[aa.Ticker]; dup {
+			return fmt.Errorf("file provider: assets manifest lists %s twice", aa.Ticker)
+		}
+
+		if sic := field("sic_code"); sic != "" {
+			if aa.SICCode, err = strconv.Atoi(sic); err != nil {
+				return fmt.Errorf("file provider: parse sic_code %q for %s: %w", sic, aa.Ticker, err)
+			}
+		}
+
+		for name, dest := range map[string]*time.Time{"listed": &aa.Listed, "delisted": &aa.Delisted} {
+			if raw := field(name); raw != "" {
+				if *dest, err = time.Parse("2006-01-02", raw); err != nil {
+					return fmt.Errorf("file provider: parse %s date %q for %s: %w", name, raw, aa.Ticker, err)
+				}
+			}
+		}
+
+		dataPath := dataFiles[strings.ToUpper(aa.Ticker)]
+		if name := field("file"); name != "" {
+			dataPath = name
+			if !filepath.IsAbs(dataPath) {
+				dataPath = filepath.Join(p.dir, dataPath)
+			}
+		}
+
+		p.addAsset(aa, dataPath)
+	}
+}
+
+// indexMetrics reads each data file's header and records the union of
+// mapped metrics.
+func (p *FileProvider) indexMetrics() error {
+	seen := make(map[Metric]bool)
+
+	for _, aa := range p.assets {
+		path, ok := p.files[aa.CompositeFigi]
+		if !ok {
+			continue
+		}
+
+		names, err := readFileHeader(path)
+		if err != nil {
+			return fmt.Errorf("file provider: read header of %s: %w", path, err)
+		}
+
+		if _, err := p.resolveDateColumn(names); err != nil {
+			return fmt.Errorf("file provider: %s: %w", path, err)
+		}
+
+		for _, name := range names {
+			if metric, ok := p.metricFor(name); ok {
+				seen[metric] = true
+			}
+		}
+	}
+
+	for metric := range seen {
+		p.provides = append(p.provides, metric)
+	}
+
+	slices.Sort(p.provides)
+
+	return nil
+}
+
+// readFileHeader returns the column names of a CSV or Parquet file.
+func readFileHeader(path string) ([]string, error) {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		header, err := csv.NewReader(file).Read()
+		if err != nil {
+			return nil, err
+		}
+
+		for idx := range header {
+			header[idx] = strings.TrimSpace(header[idx])
+		}
+
+		return header, nil
+	}
+
+	return readParquetSchema(path)
+}
+
+// metricFor maps a file column name to a metric: configured columns first,
+// then the built-in OHLCV names, then registered metric names.
+func (p *FileProvider) metricFor(name string) (Metric, bool) {
+	if metric, ok := p.columns[strings.ToLower(name)]; ok {
+		return metric, true
+	}
+
+	normalized := strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(name))
+	if metric, ok := defaultFileColumns[normalized]; ok {
+		return metric, true
+	}
+
+	return MetricByName(name)
+}
+
+// resolveDateColumn returns the index of the date column in names.
+func (p *FileProvider) resolveDateColumn(names []string) (int, error) {
+	candidates := defaultDateColumns
+	if p.dateColumn != "" {
+		candidates = []string{p.dateColumn}
+	}
+
+	for _, candidate := range candidates {
+		for idx, name := range names {
+			if strings.EqualFold(name, candidate) {
+				return idx, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("no date column (looked for %s)", strings.Join(candidates, ", "))
+}
+
+// parseFileDate parses a text date. Values without a time of day are
+// placed at 4pm Eastern.
+func (p *FileProvider) parseFileDate(raw string) (time.Time, error) {
+	layouts := fileDateLayouts
+	if p.dateLayout != "" {
+		layouts = []string{p.dateLayout}
+	}
+
+	for _, layout := range layouts {
+		parsed, err := time.ParseInLocation(layout, raw, snapshotLocation)
+		if err == nil {
+			return marketCloseIfDateOnly(parsed), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized date %q", raw)
+}
+
+// marketCloseIfDateOnly moves timestamps that fall on midnight (in either
+// Eastern or UTC, since Parquet DATE columns decode to UTC midnight) to 4pm
+// Eastern on the same calendar day.
+func marketCloseIfDateOnly(tt time.Time) time.Time {
+	for _, loc := range []*time.Location{snapshotLocation, time.UTC} {
+		local := tt.In(loc)
+		if local.Hour() == 0 && local.Minute() == 0 && local.Second() == 0 && local.Nanosecond() == 0 {
+			return time.Date(local.Year(), local.Month(), local.Day(), 16, 0, 0, 0, snapshotLocation)
+		}
+	}
+
+	return tt.In(snapshotLocation)
+}
+
+// load returns the parsed series for an asset, reading its file on first
+// use. It returns nil when the asset has no data file.
+func (p *FileProvider) load(figi string) (*fileSeries, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if series, ok := p.series[figi]; ok {
+		return series, nil
+	}
+
+	path, ok := p.files[figi]
+	if !ok {
+		return nil, nil
+	}
+
+	var (
+		series *fileSeries
+		err    error
+	)
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		series, err = p.readCSV(path)
+	} else {
+		series, err = p.readParquet(path)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("file provider: %s: %w", path, err)
+	}
+
+	p.series[figi] = series
+
+	return series, nil
+}
+
+func (p *FileProvider) readCSV(path string) (*fileSeries, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	for idx := range header {
+		header[idx] = strings.TrimSpace(header[idx])
+	}
+
+	dateIdx, err := p.resolveDateColumn(header)
+	if err != nil {
+		return nil, err
+	}
+
+	metricCols := make(map[int]Metric)
+
+	for idx, name := range header {
+		if metric, ok := p.metricFor(name); ok && idx != dateIdx {
+			metricCols[idx] = metric
+		}
+	}
+
+	builder := newFileSeriesBuilder(metricCols)
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		tt, err := p.parseFileDate(strings.TrimSpace(record[dateIdx]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		row := make(map[int]float64, len(metricCols))
+
+		for idx := range metricCols {
+			val, err := parseFileValue(record[idx])
+			if err != nil {
+				return nil, fmt.Errorf("line %d column %s: %w", line, header[idx], err)
+			}
+
+			row[idx] = val
+		}
+
+		builder.add(tt, row)
+	}
+
+	return builder.build(), nil
+}
+
+// parseFileValue parses a numeric cell. Blank cells and the usual
+// missing-value markers yield NaN.
+func parseFileValue(raw string) (float64, error) {
+	raw = strings.TrimSpace(raw)
+
+	switch strings.ToLower(raw) {
+	case "", "na", "n/a", "nan", "null", "none", "-":
+		return math.NaN(), nil
+	}
+
+	return strconv.ParseFloat(raw, 64)
+}
+
+func (p *FileProvider) readParquet(path string) (*fileSeries, error) {
+	table, err := readParquetFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dateIdx, err := p.resolveDateColumn(table.names)
+	if err != nil {
+		return nil, err
+	}
+
+	metricCols := make(map[int]Metric)
+
+	for idx, name := range table.names {
+		if metric, ok := p.metricFor(name); ok && idx != dateIdx {
+			metricCols[idx] = metric
+		}
+	}
+
+	builder := newFileSeriesBuilder(metricCols)
+
+	for rowIdx, raw := range table.columns[dateIdx] {
+		var tt time.Time
+
+		switch val := raw.(type) {
+		case time.Time:
+			tt = marketCloseIfDateOnly(val)
+		case string:
+			if tt, err = p.parseFileDate(val); err != nil {
+				return nil, fmt.Errorf("row %d: %w", rowIdx, err)
+			}
+		default:
+			return nil, fmt.Errorf("row %d: date column %q holds %T, want a date, timestamp or string",
+				rowIdx, table.names[dateIdx], raw)
+		}
+
+		row := make(map[int]float64, len(metricCols))
+
+		for idx := range metricCols {
+			switch val := table.columns[idx][rowIdx].(type) {
+			case float64:
+				row[idx] = val
+			case string:
+				if row[idx], err = parseFileValue(val); err != nil {
+					return nil, fmt.Errorf("row %d column %s: %w", rowIdx, table.names[idx], err)
+				}
+			default:
+				row[idx] = math.NaN()
+			}
+		}
+
+		builder.add(tt, row)
+	}
+
+	return builder.build(), nil
+}
+
+// fileSeriesBuilder collects rows in file order and produces a series
+// sorted by time. When a date appears more than once the last row wins.
+type fileSeriesBuilder struct {
+	metricCols map[int]Metric
+	rows       map[int64]map[int]float64
+	times      map[int64]time.Time
+}
+
+func newFileSeriesBuilder(metricCols map[int]Metric) *fileSeriesBuilder {
+	return &fileSeriesBuilder{
+		metricCols: metricCols,
+		rows:       make(map[int64]map[int]float64),
+		times:      make(map[int64]time.Time),
+	}
+}
+
+func (b *fileSeriesBuilder) add(tt time.Time, row map[int]float64) {
+	key := tt.UnixNano()
+	b.rows[key] = row
+	b.times[key] = tt
+}
+
+func (b *fileSeriesBuilder) build() *fileSeries {
+	keys := make([]int64, 0, len(b.rows))
+	for key := range b.rows {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	series := &fileSeries{
+		times:  make([]time.Time, len(keys)),
+		values: make(map[Metric][]float64, len(b.metricCols)),
+	}
+
+	for _, metric := range b.metricCols {
+		col := make([]float64, len(keys))
+		for idx := range col {
+			col[idx] = math.NaN()
+		}
+
+		series.values[metric] = col
+	}
+
+	for rowIdx, key := range keys {
+		series.times[rowIdx] = b.times[key]
+
+		for colIdx, val := range b.rows[key] {
+			series.values[b.metricCols[colIdx]][rowIdx] = val
+		}
+	}
+
+	return series
+}
+
+// CacheKey identifies the data directory the provider reads from. It
+// also digests the column mapping, the date column and layout, and the
+// size and modification time of every data file and the assets manifest,
+// so editing a file or changing the options starts a fresh cache.
+func (p *FileProvider) CacheKey() string {
+	dir := p.dir
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+
+	digest := sha256.New()
+	fmt.Fprintf(digest, "date %q %q\n", p.dateColumn, p.dateLayout)
+
+	for _, name := range slices.Sorted(maps.Keys(p.columns)) {
+		fmt.Fprintf(digest, "column %q %q\n", name, p.columns[name])
+	}
+
+	paths := slices.Sorted(maps.Values(p.files))
+	if p.manifest != "" {
+		paths = append(paths, p.manifest)
+	}
+
+	for _, path := range paths {
+		writeFileStamp(digest, path)
+	}
+
+	return "files:" + dir + ":" + hex.EncodeToString(digest.Sum(nil))
+}
+
+// writeFileStamp writes path with its size and modification time to w. A
+// file that cannot be read is recorded as missing.
+func writeFileStamp(w io.Writer, path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		fmt.Fprintf(w, "%s missing\n", path)
+		return
+	}
+
+	fmt.Fprintf(w, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+}
+
+// Close releases the parsed series.
+func (p *FileProvider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.series = make(map[string]*fileSeries)
+
+	return nil
+}
+
+// FetchMarketHolidays derives full-day closures from the observed trading
+// days: every weekday between the first and last date present in any data
+// file on which no file has a row is reported as a holiday. Early closes
+// cannot be inferred from daily rows and are not reported.
+func (p *FileProvider) FetchMarketHolidays(_ context.Context) ([]tradecron.MarketHoliday, error) {
+	observed := make(map[string]bool)
+
+	var first, last time.Time
+
+	for _, aa := range p.assets {
+		series, err := p.load(aa.CompositeFigi)
+		if err != nil {
+			return nil, err
+		}
+
+		if series == nil {
+			continue
+		}
+
+		for _, tt := range series.times {
+			local := tt.In(snapshotLocation)
+			observed[local.Format(snapshotDateFormat)] = true
+
+			day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, snapshotLocation)
+			if first.IsZero() || day.Before(first) {
+				first = day
+			}
+
+			if day.After(last) {
+				last = day
+			}
+		}
+	}
+
+	var holidays []tradecron.MarketHoliday
+
+	for day := first; !first.IsZero() && !day.After(last); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+
+		if !observed[day.Format(snapshotDateFormat)] {
+			holidays = append(holidays, tradecron.MarketHoliday{Date: day})
+		}
+	}
+
+	return holidays, nil
+}
+
+// -- AssetProvider --
+
+func (p *FileProvider) Assets(_ context.Context) ([]asset.Asset, error) {
+	return slices.Clone(p.assets), nil
+}
+
+func (p *FileProvider) LookupAsset(_ context.Context, ticker string) (asset.Asset, error) {
+	aa, ok := p.byTicker[ticker]
+	if !ok {
+		return asset.Asset{}, fmt.Errorf("file provider: lookup asset %q: not found", ticker)
+	}
+
+	return aa, nil
+}
+
+// -- BatchProvider --
+
+func (p *FileProvider) Provides() []Metric {
+	return slices.Clone(p.provides)
+}
+
+func (p *FileProvider) Fetch(_ context.Context, req DataRequest) (*DataFrame, error) {
+	startStr := req.Start.Format(snapshotDateFormat)
+	endStr := req.End.Format(snapshotDateFormat)
+
+	inRange := func(tt time.Time) bool {
+		day := tt.In(snapshotLocation).Format(snapshotDateFormat)
+		return day >= startStr && day <= endStr
+	}
+
+	loaded := make([]*fileSeries, len(req.Assets))
+	timeSet := make(map[int64]time.Time)
+
+	for idx, aa := range req.Assets {
+		figi := aa.CompositeFigi
+		if _, ok := p.files[figi]; !ok {
+			if known, ok := p.byTicker[aa.Ticker]; ok {
+				figi = known.CompositeFigi
+			}
+		}
+
+		series, err := p.load(figi)
+		if err != nil {
+			return nil, err
+		}
+
+		loaded[idx] = series
+
+		if series == nil {
+			continue
+		}
+
+		for _, tt := range series.times {
+			if inRange(tt) {
+				timeSet[tt.Unix()] = tt
+			}
+		}
+	}
+
+	if len(timeSet) == 0 {
+		return NewDataFrame(nil, nil, nil, req.Frequency, nil)
+	}
+
+	times := make([]time.Time, 0, len(timeSet))
+	for _, tt := range timeSet {
+		times = append(times, tt)
+	}
+
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	timeIdx := make(map[int64]int, len(times))
+	for idx, tt := range times {
+		timeIdx[tt.Unix()] = idx
+	}
+
+	numTimes := len(times)
+	numMetrics := len(req.Metrics)
+
+	slab := make([]float64, numTimes*len(req.Assets)*numMetrics)
+	for idx := range slab {
+		slab[idx] = math.NaN()
+	}
+
+	for ai, series := range loaded {
+		if series == nil {
+			continue
+		}
+
+		for mi, metric := range req.Metrics {
+			vals, ok := series.values[metric]
+			if !ok {
+				continue
+			}
+
+			colStart := (ai*numMetrics + mi) * numTimes
+
+			for row, tt := range series.times {
+				if ti, ok := timeIdx[tt.Unix()]; ok {
+					slab[colStart+ti] = vals[row]
+				}
+			}
+		}
+	}
+
+	return NewDataFrame(times, req.Assets, req.Metrics, req.Frequency,
+		SlabToColumns(slab, len(req.Assets)*numMetrics, numTimes))
+}
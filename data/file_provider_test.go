@@ -0,0 +1,615 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
+)
+
+var _ = Describe("FileProvider", func() {
+	var (
+		ctx context.Context
+		dir string
+		nyc *time.Location
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		dir = GinkgoT().TempDir()
+
+		var err error
+		nyc, err = time.LoadLocation("America/New_York")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	writeFile := func(name, contents string) {
+		Expect(os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600)).To(Succeed())
+	}
+
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 16, 0, 0, 0, nyc) }
+
+	Describe("CSV files", func() {
+		BeforeEach(func() {
+			// Vendor exports are often newest-first.
+			writeFile("SPY.csv", "Date,Open,High,Low,Close,Adj Close,Volume\n"+
+				"2024-01-04,471,472,469,470,469.5,1200\n"+
+				"2024-01-03,473,474,471,472,471.5,1100\n"+
+				"2024-01-02,475,476,473,474,473.5,1000\n")
+			writeFile("tlt.csv", "date,close\n"+
+				"2024-01-03,98\n"+
+				"2024-01-05,97\n")
+		})
+
+		It("serves every file as an asset named after the file", func() {
+			provider, err := data.NewFileProvider(dir)
+			Expect(err).NotTo(HaveOccurred())
+			defer provider.Close()
+
+			assets, err := provider.Assets(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(assets).To(Equal([]asset.Asset{
+				{CompositeFigi: "SPY", Ticker: "SPY"},
+				{CompositeFigi: "TLT", Ticker: "TLT"},
+			}))
+
+			Expect(provider.Provides()).To(ConsistOf(
+				data.MetricOpen, data.MetricHigh, data.MetricLow, data.MetricClose,
+				data.AdjClose, data.Volume,
+			))
+		})
+
+		It("returns rows sorted by date at market close on the union time axis", func() {
+			provider, err := data.NewFileProvider(dir)
+			Expect(err).NotTo(HaveOccurred())
+			defer provider.Close()
+
+			spy, err := provider.LookupAsset(ctx, "SPY")
+			Expect(err).NotTo(HaveOccurred())
+			tlt, err := provider.LookupAsset(ctx, "TLT")
+			Expect(err).NotTo(HaveOccurred())
+
+			df, err := provider.Fetch(ctx, data.DataRequest{
+				Assets:    []asset.Asset{spy, tlt},
+				Metrics:   []data.Metric{data.MetricClose, data.AdjClose},
+				Start:     day(3),
+				End:       day(5),
+				Frequency: data.Daily,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(df.Times()).To(Equal([]time.Time{day(3), day(4), day(5)}))
+
+			Expect(df.Column(spy, data.MetricClose)[:2]).To(Equal([]float64{472, 470}))
+			Expect(math.IsNaN(df.Column(spy, data.MetricClose)[2])).To(BeTrue())
+			Expect(df.Column(spy, data.AdjClose)[0]).To(Equal(471.5))
+
+			tltClose := df.Column(tlt, data.MetricClose)
+			Expect(tltClose[0]).To(Equal(98.0))
+			Expect(math.IsNaN(tltClose[1])).To(BeTrue())
+			Expect(tltClose[2]).To(Equal(97.0))
+			Expect(math.IsNaN(df.Column(tlt, data.AdjClose)[0])).To(BeTrue())
+		})
+
+		It("derives holidays from weekdays with no observed rows", func() {
+			writeFile("TLT.csv", "date,close\n2024-01-08,97\n")
+
+			Expect(os.Remove(filepath.Join(dir, "tlt.csv"))).To(Succeed())
+
+			provider, err := data.NewFileProvider(dir)
+			Expect(err).NotTo(HaveOccurred())
+			defer provider.Close()
+
+			holidays, err := provider.FetchMarketHolidays(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(holidays).To(HaveLen(1))
+			Expect(holidays[0].Date.Format("2006-01-02")).To(Equal("2024-01-05"))
+			Expect(holidays[0].EarlyClose).To(BeFalse())
+		})
+
+		It("maps custom columns and a custom date column", func() {
+			Expect(os.Remove(filepath.Join(dir, "SPY.csv"))).To(Succeed())
+			Expect(os.Remove(filepath.Join(dir, "tlt.csv"))).To(Succeed())
+			writeFile("IWM.csv", "as_of,px_last,mkt_cap,comment\n"+
+				"01/02/2024,200.5,1000,first\n")
+
+			provider, err := data.NewFileProvider(dir,
+				data.WithDateColumn("as_of"),
+				data.WithDateLayout("01/02/2006"),
+				data.WithColumnMap(map[string]data.Metric{"PX_LAST": data.MetricClose, "mkt_cap": data.MarketCap}),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			defer provider.Close()
+
+			Expect(provider.Provides()).To(ConsistOf(data.MetricClose, data.MarketCap))
+
+			iwm := asset.Asset{CompositeFigi: "IWM", Ticker: "IWM"}
+			df, err := provider.Fetch(ctx, data.DataRequest{
+				Assets:  []asset.Asset{iwm},
+				Metrics: []data.Metric{data.MetricClose, data.MarketCap},
+				Start:   day(1),
+				End:     day(31),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(df.Times()).To(Equal([]time.Time{day(2)}))
+			Expect(df.Value(iwm, data.MetricClose)).To(Equal(200.5))
+			Expect(df.Value(iwm, data.MarketCap)).To(Equal(1000.0))
+		})
+
+		It("changes its cache key when a file or the column mapping changes", func() {
+			provider, err := data.NewFileProvider(dir)
+			Expect(err).NotTo(HaveOccurred())
+			defer provider.Close()
+
+			before := provider.CacheKey()
+			Expect(provider.CacheKey()).To(Equal(before))
+
+			writeFile("tlt.csv", "date,close\n"+
+				"2024-01-03,99\n"+
+				"2024-01-05,97\n")
+
+			later := time.Now().Add(time.Minute)
+			Expect(os.Chtimes(filepath.Join(dir, "tlt.csv"), later, later)).To(Succeed())
+			Expect(provider.CacheKey()).NotTo(Equal(before))
+
+			remapped, err := data.NewFileProvider(dir,
+				data.WithColumnMap(map[string]data.Metric{"adj close": data.MetricClose}))
+			Expect(err).NotTo(HaveOccurred())
+			defer remapped.Close()
+
+			Expect(remapped.CacheKey()).NotTo(Equal(provider.CacheKey()))
+		})
+
+		It("rejects files without a date column", func() {
+			writeFile("BAD.csv", "close\n1\n")
+
+			_, err := data.NewFileProvider(dir)
+			Expect(err).To(MatchError(ContainSubstring("no date column")))
+		})
+	})
+
+	Describe("assets manifest", func() {
+		BeforeEach(func() {
+			writeFile("SPY.csv", "date,close\n2024-01-02,474\n")
+			writeFile("brk-b.csv", "date,close\n2024-01-02,363\n")
+			writeFile("QQQ.csv", "date,close\n2024-01-02,409\n")
+			writeFile("assets.csv", "ticker,composite_figi,name,asset_type,primary_exchange,sic_code,listed,file\n"+
+				"SPY,BBG000BDTBL9,SPDR S&P 500 ETF Trust,ETF,NYSE,6726,1993-01-22,\n"+
+				"BRK.B,BBG000DWG505,Berkshire Hathaway Inc,Common Stock,NYSE,,,brk-b.csv\n")
+		})
+
+		It("serves only manifest assets with their metadata", func() {
+			provider, err := data.NewFileProvider(dir)
+			Expect(err).NotTo(HaveOccurred())
+			defer provider.Close()
+
+			assets, err := provider.Assets(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(assets).To(HaveLen(2))
+			Expect(assets[0].Ticker).To(Equal("BRK.B"))
+			Expect(assets[1].Ticker).To(Equal("SPY"))
+
+			spy, err := provider.LookupAsset(ctx, "SPY")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spy.CompositeFigi).To(Equal("BBG000BDTBL9"))
+			Expect(spy.Name).To(Equal("SPDR S&P 500 ETF Trust"))
+			Expect(spy.AssetType).To(Equal(asset.AssetTypeETF))
+			Expect(spy.PrimaryExchange).To(Equal(asset.ExchangeNYSE))
+			Expect(spy.SICCode).To(Equal(6726))
+			Expect(spy.Listed.Year()).To(Equal(1993))
+
+			_, err = provider.LookupAsset(ctx, "QQQ")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("reads the currency column and derives it from the exchange when empty", func() {
+			writeFile("VOD.csv", "date,close\n2024-01-02,69.5\n")
+			writeFile("SAP.csv", "date,close\n2024-01-02,140\n")
+			writeFile("assets.csv", "ticker,primary_exchange,currency\n"+
+				"SPY,NYSE,USD\n"+
+				"VOD,XLON,\n"+
+				"SAP,NYSE,eur\n")
+
+			provider, err := data.NewFileProvider(dir)
+			Expect(err).NotTo(HaveOccurred())
+			defer provider.Close()
+
+			spy, err := provider.LookupAsset(ctx, "SPY")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spy.Currency).To(BeEmpty())
+
+			vod, err := provider.LookupAsset(ctx, "VOD")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vod.Currency).To(Equal(asset.CurrencyGBX))
+
+			sap, err := provider.LookupAsset(ctx, "SAP")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sap.Currency).To(Equal(asset.CurrencyEUR))
+		})
+
+		It("reads data from the file named in the manifest", func() {
+			provider, err := data.NewFileProvider(dir)
+			Expect(err).NotTo(HaveOccurred())
+			defer provider.Close()
+
+			brk, err := provider.LookupAsset(ctx, "BRK.B")
+			Expect(err).NotTo(HaveOccurred())
+
+			df, err := provider.Fetch(ctx, data.DataRequest{
+				Assets:  []asset.Asset{brk},
+				Metrics: []data.Metric{data.MetricClose},
+				Start:   day(2),
+				End:     day(2),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(df.Value(brk, data.MetricClose)).To(Equal(363.0))
+		})
+	})
+
+	Describe("Parquet files", func() {
+		It("reads required PLAIN columns with a DATE column", func() {
+			writeTestParquet(filepath.Join(dir, "SPY.parquet"), 0,
+				testParquetColumn{name: "date", physical: 1, converted: 6, values: []any{int64(19724), int64(19725)}},
+				testParquetColumn{name: "close", physical: 5, converted: -1, values: []any{474.0, 472.0}},
+				testParquetColumn{name: "volume", physical: 2, converted: -1, values: []any{int64(1000), int64(1100)}},
+			)
+
+			provider, err := data.NewFileProvider(dir)
+			Expect(err).NotTo(HaveOccurred())
+			defer provider.Close()
+
+			Expect(provider.Provides()).To(ConsistOf(data.MetricClose, data.Volume))
+
+			spy := asset.Asset{CompositeFigi: "SPY", Ticker: "SPY"}
+			df, err := provider.Fetch(ctx, data.DataRequest{
+				Assets:  []asset.Asset{spy},
+				Metrics: []data.Metric{data.MetricClose, data.Volume},
+				Start:   day(1),
+				End:     day(31),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(df.Times()).To(Equal([]time.Time{day(2), day(3)}))
+			Expect(df.Column(spy, data.MetricClose)).To(Equal([]float64{474, 472}))
+			Expect(df.Column(spy, data.Volume)).To(Equal([]float64{1000, 1100}))
+		})
+
+		It("reads snappy-compressed dictionary columns with nulls", func() {
+			writeTestParquet(filepath.Join(dir, "TLT.parquet"), 1,
+				testParquetColumn{name: "Date", physical: 6, converted: 0, values: []any{"2024-01-02", "2024-01-03", "2024-01-04"}},
+				testParquetColumn{name: "Close", physical: 5, converted: -1, optional: true, dictionary: true,
+					values: []any{98.0, nil, 98.0}},
+			)
+
+			provider, err := data.NewFileProvider(dir)
+			Expect(err).NotTo(HaveOccurred())
+			defer provider.Close()
+
+			tlt := asset.Asset{CompositeFigi: "TLT", Ticker: "TLT"}
+			df, err := provider.Fetch(ctx, data.DataRequest{
+				Assets:  []asset.Asset{tlt},
+				Metrics: []data.Metric{data.MetricClose},
+				Start:   day(1),
+				End:     day(31),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			closes := df.Column(tlt, data.MetricClose)
+			Expect(closes).To(HaveLen(3))
+			Expect(closes[0]).To(Equal(98.0))
+			Expect(math.IsNaN(closes[1])).To(BeTrue())
+			Expect(closes[2]).To(Equal(98.0))
+		})
+	})
+})
+
+// testParquetColumn describes one column of a single-row-group test file.
+// physical is the Parquet physical type (1 INT32, 2 INT64, 5 DOUBLE,
+// 6 BYTE_ARRAY) and converted the converted type, or -1 for none. Values
+// are int64, float64 or string, with nil for nulls in optional columns.
+type testParquetColumn struct {
+	name       string
+	physical   int64
+	converted  int64
+	optional   bool
+	dictionary bool
+	values     []any
+}
+
+// writeTestParquet writes a minimal Parquet file with one row group and
+// one v1 data page per column. codec is 0 (uncompressed) or 1 (snappy).
+func writeTestParquet(path string, codec int64, cols ...testParquetColumn) {
+	GinkgoHelper()
+
+	var (
+		file   bytes.Buffer
+		chunks [][]byte
+	)
+
+	file.WriteString("PAR1")
+
+	compress := func(body []byte) []byte {
+		if codec == 1 {
+			return snappy.Encode(nil, body)
+		}
+
+		return body
+	}
+
+	writePage := func(header func(uncompressed, compressed int) []byte, body []byte) int {
+		offset := file.Len()
+		compressed := compress(body)
+		file.Write(header(len(body), len(compressed)))
+		file.Write(compressed)
+
+		return offset
+	}
+
+	schema := [][]byte{thriftStructBytes(
+		thriftField{4, 8, "schema"},
+		thriftField{5, 5, int64(len(cols))},
+	)}
+
+	numRows := 0
+
+	for _, col := range cols {
+		numRows = len(col.values)
+
+		element := []thriftField{{1, 5, col.physical}, {3, 5, int64(0)}, {4, 8, col.name}}
+		if col.optional {
+			element[1].value = int64(1)
+		}
+
+		if col.converted >= 0 {
+			element = append(element, thriftField{6, 5, col.converted})
+		}
+
+		schema = append(schema, thriftStructBytes(element...))
+
+		var present []any
+
+		for _, val := range col.values {
+			if val != nil {
+				present = append(present, val)
+			}
+		}
+
+		var body bytes.Buffer
+
+		if col.optional {
+			defs := make([]int, len(col.values))
+			for idx, val := range col.values {
+				if val != nil {
+					defs[idx] = 1
+				}
+			}
+
+			levels := bitPacked(defs, 1)
+			_ = binary.Write(&body, binary.LittleEndian, uint32(len(levels)))
+			body.Write(levels)
+		}
+
+		start := file.Len()
+		dictOffset := int64(-1)
+		encoding := int64(0)
+
+		if col.dictionary {
+			var (
+				dict    []any
+				indices []int
+			)
+
+			for _, val := range present {
+				idx := -1
+
+				for pos, existing := range dict {
+					if existing == val {
+						idx = pos
+					}
+				}
+
+				if idx < 0 {
+					idx = len(dict)
+					dict = append(dict, val)
+				}
+
+				indices = append(indices, idx)
+			}
+
+			dictOffset = int64(writePage(func(uncompressed, compressed int) []byte {
+				return thriftStructBytes(
+					thriftField{1, 5, int64(2)},
+					thriftField{2, 5, int64(uncompressed)},
+					thriftField{3, 5, int64(compressed)},
+					thriftField{7, 12, thriftStructBytes(
+						thriftField{1, 5, int64(len(dict))},
+						thriftField{2, 5, int64(0)},
+					)},
+				)
+			}, plainValues(col.physical, dict)))
+
+			body.WriteByte(1)
+			body.Write(bitPacked(indices, 1))
+
+			encoding = 8
+		} else {
+			body.Write(plainValues(col.physical, present))
+		}
+
+		dataOffset := writePage(func(uncompressed, compressed int) []byte {
+			return thriftStructBytes(
+				thriftField{1, 5, int64(0)},
+				thriftField{2, 5, int64(uncompressed)},
+				thriftField{3, 5, int64(compressed)},
+				thriftField{5, 12, thriftStructBytes(
+					thriftField{1, 5, int64(len(col.values))},
+					thriftField{2, 5, encoding},
+					thriftField{3, 5, int64(3)},
+					thriftField{4, 5, int64(3)},
+				)},
+			)
+		}, body.Bytes())
+
+		size := int64(file.Len() - start)
+		meta := []thriftField{
+			{1, 5, col.physical},
+			{2, 9, thriftList{5, [][]byte{zigzag(encoding)}}},
+			{3, 9, thriftList{8, [][]byte{thriftBinaryBytes(col.name)}}},
+			{4, 5, codec},
+			{5, 6, int64(len(col.values))},
+			{6, 6, size},
+			{7, 6, size},
+			{9, 6, int64(dataOffset)},
+		}
+
+		if dictOffset >= 0 {
+			meta = append(meta, thriftField{11, 6, dictOffset})
+		}
+
+		chunks = append(chunks, thriftStructBytes(
+			thriftField{2, 6, int64(start)},
+			thriftField{3, 12, thriftStructBytes(meta...)},
+		))
+	}
+
+	footer := thriftStructBytes(
+		thriftField{1, 5, int64(1)},
+		thriftField{2, 9, thriftList{12, schema}},
+		thriftField{3, 6, int64(numRows)},
+		thriftField{4, 9, thriftList{12, [][]byte{thriftStructBytes(
+			thriftField{1, 9, thriftList{12, chunks}},
+			thriftField{2, 6, int64(file.Len())},
+			thriftField{3, 6, int64(numRows)},
+		)}}},
+	)
+
+	file.Write(footer)
+	_ = binary.Write(&file, binary.LittleEndian, uint32(len(footer)))
+	file.WriteString("PAR1")
+
+	Expect(os.WriteFile(path, file.Bytes(), 0o600)).To(Succeed())
+}
+
+// plainValues PLAIN-encodes values of the given physical type.
+func plainValues(physical int64, values []any) []byte {
+	var buf bytes.Buffer
+
+	for _, val := range values {
+		switch physical {
+		case 1:
+			_ = binary.Write(&buf, binary.LittleEndian, int32(val.(int64)))
+		case 2:
+			_ = binary.Write(&buf, binary.LittleEndian, val.(int64))
+		case 5:
+			_ = binary.Write(&buf, binary.LittleEndian, val.(float64))
+		case 6:
+			_ = binary.Write(&buf, binary.LittleEndian, uint32(len(val.(string))))
+			buf.WriteString(val.(string))
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// bitPacked encodes values as a single bit-packed run of the RLE hybrid
+// encoding.
+func bitPacked(values []int, bitWidth int) []byte {
+	groups := (len(values) + 7) / 8
+	out := binary.AppendUvarint(nil, uint64(groups<<1|1))
+	packed := make([]byte, groups*bitWidth)
+
+	for idx, val := range values {
+		for bit := range bitWidth {
+			if val>>bit&1 == 1 {
+				abs := idx*bitWidth + bit
+				packed[abs/8] |= 1 << (abs % 8)
+			}
+		}
+	}
+
+	return append(out, packed...)
+}
+
+// thriftField is one field of a Thrift compact-protocol struct. value is
+// int64 for integer types, string for binary, []byte for an encoded struct
+// and thriftList for lists.
+type thriftField struct {
+	id    int16
+	kind  byte
+	value any
+}
+
+type thriftList struct {
+	elemKind byte
+	elems    [][]byte
+}
+
+func zigzag(val int64) []byte {
+	return binary.AppendUvarint(nil, uint64((val<<1)^(val>>63)))
+}
+
+func thriftBinaryBytes(val string) []byte {
+	return append(binary.AppendUvarint(nil, uint64(len(val))), val...)
+}
+
+func thriftStructBytes(fields ...thriftField) []byte {
+	var (
+		out  []byte
+		last int16
+	)
+
+	for _, field := range fields {
+		if delta := field.id - last; delta > 0 && delta <= 15 {
+			out = append(out, byte(delta)<<4|field.kind)
+		} else {
+			out = append(out, field.kind)
+			out = append(out, zigzag(int64(field.id))...)
+		}
+
+		last = field.id
+
+		switch val := field.value.(type) {
+		case int64:
+			out = append(out, zigzag(val)...)
+		case string:
+			out = append(out, thriftBinaryBytes(val)...)
+		case []byte:
+			out = append(out, val...)
+		case thriftList:
+			if len(val.elems) < 15 {
+				out = append(out, byte(len(val.elems))<<4|val.elemKind)
+			} else {
+				out = append(out, 0xf0|val.elemKind)
+				out = binary.AppendUvarint(out, uint64(len(val.elems)))
+			}
+
+			for _, elem := range val.elems {
+				out = append(out, elem...)
+			}
+		}
+	}
+
+	return append(out, 0)
+}
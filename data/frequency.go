@@ -15,7 +15,11 @@
 
 package data
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/penny-vault/pvbt/tradecron"
+)
 
 // Frequency represents data publication frequency.
 type Frequency int
@@ -49,11 +53,23 @@ func (f Frequency) String() string {
 }
 
 // PeriodsPerYear returns the approximate number of periods per year for this
-// frequency. Used to convert annualized rates to per-period rates.
+// frequency on the NYSE calendar. Used to convert annualized rates to
+// per-period rates.
 func (f Frequency) PeriodsPerYear() float64 {
+	return f.PeriodsPerYearOn(nil)
+}
+
+// PeriodsPerYearOn is PeriodsPerYear for the given market calendar: a
+// daily period is one of the calendar's trading days, so a year holds 365
+// of them on a 24/7 calendar. A nil calendar means NYSE.
+func (f Frequency) PeriodsPerYearOn(cal tradecron.Calendar) float64 {
+	if cal == nil {
+		cal = tradecron.NYSE
+	}
+
 	switch f {
 	case Daily:
-		return 252
+		return cal.TradingDaysPerYear()
 	case Weekly:
 		return 52
 	case Monthly:
@@ -63,7 +79,7 @@ func (f Frequency) PeriodsPerYear() float64 {
 	case Yearly:
 		return 1
 	default:
-		return 252 // default to daily
+		return cal.TradingDaysPerYear() // default to daily
 	}
 }
 
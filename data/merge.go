@@ -153,6 +153,8 @@ func MergeTimes(frames ...*DataFrame) (*DataFrame, error) {
 		return nil, err
 	}
 
+	result.calendar = sorted[0].calendar
+
 	// Concatenate risk-free rates if all frames have them.
 	allHaveRF := true
 
@@ -0,0 +1,62 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
+)
+
+// OptionQuote is the end-of-day quote for one option contract. Fields a
+// provider does not supply are NaN.
+type OptionQuote struct {
+	// Contract is the option, built with asset.NewOption or carrying an
+	// equivalent asset.Contract specification.
+	Contract asset.Asset
+
+	Bid          float64
+	Ask          float64
+	Last         float64
+	Volume       float64
+	OpenInterest float64
+
+	// ImpliedVolatility is the provider's annualized implied volatility
+	// as a decimal (0.2 for 20%).
+	ImpliedVolatility float64
+}
+
+// Mid returns the midpoint of the bid and ask, falling back to the last
+// trade when either side is missing.
+func (q OptionQuote) Mid() float64 {
+	if math.IsNaN(q.Bid) || math.IsNaN(q.Ask) || q.Bid <= 0 || q.Ask <= 0 {
+		return q.Last
+	}
+
+	return (q.Bid + q.Ask) / 2
+}
+
+// OptionChainProvider supplies historical option chains. A provider that
+// implements it should also answer BatchProvider requests for the option
+// contracts it lists, with MetricClose holding the contract's settlement
+// or mid price, so the engine can value and fill option positions.
+type OptionChainProvider interface {
+	// OptionChain returns a quote for every contract on underlying that
+	// was listed on t and has not expired, as of the close of t.
+	OptionChain(ctx context.Context, underlying asset.Asset, t time.Time) ([]OptionQuote, error)
+}
@@ -0,0 +1,1010 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// This file implements the subset of Apache Parquet that FileProvider
+// needs to read vendor and research exports: flat schemas, PLAIN and
+// dictionary encodings, data page v1 and v2, and the uncompressed, snappy,
+// gzip, brotli and zstd codecs. Nested columns and the DELTA_* encodings
+// are rejected with an error rather than decoded incorrectly.
+
+const parquetMagic = "PAR1"
+
+// Parquet physical types.
+const (
+	parquetBoolean           = 0
+	parquetInt32             = 1
+	parquetInt64             = 2
+	parquetInt96             = 3
+	parquetFloat             = 4
+	parquetDouble            = 5
+	parquetByteArray         = 6
+	parquetFixedLenByteArray = 7
+)
+
+// Parquet encodings.
+const (
+	parquetEncodingPlain           = 0
+	parquetEncodingPlainDictionary = 2
+	parquetEncodingRLEDictionary   = 8
+)
+
+// Parquet page types.
+const (
+	parquetDataPage       = 0
+	parquetDictionaryPage = 2
+	parquetDataPageV2     = 3
+)
+
+// Parquet converted types (the legacy logical type annotation).
+const (
+	parquetConvertedDecimal         = 5
+	parquetConvertedDate            = 6
+	parquetConvertedTimestampMillis = 9
+	parquetConvertedTimestampMicros = 10
+)
+
+// julianUnixEpoch is the Julian day number of 1970-01-01, used to decode
+// legacy INT96 timestamps.
+const julianUnixEpoch = 2440588
+
+// parquetTable is a fully decoded Parquet file. Each column holds one value
+// per row: float64 for numeric columns, string for byte arrays, time.Time
+// for DATE and TIMESTAMP columns, and nil for nulls.
+type parquetTable struct {
+	names   []string
+	columns [][]any
+	numRows int
+}
+
+// column returns the values of the named column.
+func (t *parquetTable) column(name string) ([]any, bool) {
+	for idx, colName := range t.names {
+		if colName == name {
+			return t.columns[idx], true
+		}
+	}
+
+	return nil, false
+}
+
+// parquetColumnSchema describes one leaf column of a flat schema.
+type parquetColumnSchema struct {
+	name          string
+	physical      int64
+	typeLength    int
+	optional      bool
+	convertedType int64
+	scale         int
+	logical       thriftStruct
+}
+
+// readParquetSchema returns the column names of the Parquet file at path
+// without decoding any data pages.
+func readParquetSchema(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	_, cols, err := readParquetFooter(file)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(cols))
+	for idx, col := range cols {
+		names[idx] = col.name
+	}
+
+	return names, nil
+}
+
+// readParquetFile decodes every column of the Parquet file at path.
+func readParquetFile(path string) (*parquetTable, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	meta, cols, err := readParquetFooter(file)
+	if err != nil {
+		return nil, err
+	}
+
+	numRows, _ := meta.int(3)
+
+	table := &parquetTable{
+		names:   make([]string, len(cols)),
+		columns: make([][]any, len(cols)),
+		numRows: int(numRows),
+	}
+
+	for idx, col := range cols {
+		table.names[idx] = col.name
+		table.columns[idx] = make([]any, 0, numRows)
+	}
+
+	for _, rawGroup := range meta.list(4) {
+		group, ok := rawGroup.(thriftStruct)
+		if !ok {
+			return nil, errors.New("parquet: malformed row group")
+		}
+
+		chunks := group.list(1)
+		if len(chunks) != len(cols) {
+			return nil, fmt.Errorf("parquet: row group has %d column chunks, schema has %d columns", len(chunks), len(cols))
+		}
+
+		for idx, rawChunk := range chunks {
+			chunk, ok := rawChunk.(thriftStruct)
+			if !ok {
+				return nil, errors.New("parquet: malformed column chunk")
+			}
+
+			values, err := readParquetColumnChunk(file, &cols[idx], chunk.child(3))
+			if err != nil {
+				return nil, fmt.Errorf("parquet: column %q: %w", cols[idx].name, err)
+			}
+
+			table.columns[idx] = append(table.columns[idx], values...)
+		}
+	}
+
+	return table, nil
+}
+
+// readParquetFooter reads and decodes the file metadata and flat schema.
+func readParquetFooter(file *os.File) (thriftStruct, []parquetColumnSchema, error) {
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	size := stat.Size()
+	if size < 12 {
+		return nil, nil, errors.New("parquet: file too small")
+	}
+
+	tail := make([]byte, 8)
+	if _, err := file.ReadAt(tail, size-8); err != nil {
+		return nil, nil, fmt.Errorf("parquet: read footer: %w", err)
+	}
+
+	if string(tail[4:]) != parquetMagic {
+		return nil, nil, errors.New("parquet: missing PAR1 magic")
+	}
+
+	footerLen := int64(binary.LittleEndian.Uint32(tail[:4]))
+	if footerLen > size-12 {
+		return nil, nil, errors.New("parquet: footer length exceeds file size")
+	}
+
+	footer := make([]byte, footerLen)
+	if _, err := file.ReadAt(footer, size-8-footerLen); err != nil {
+		return nil, nil, fmt.Errorf("parquet: read footer: %w", err)
+	}
+
+	reader := thriftReader{buf: footer}
+
+	meta, err := reader.readStruct()
+	if err != nil {
+		return nil, nil, fmt.Errorf("parquet: decode file metadata: %w", err)
+	}
+
+	cols, err := parseParquetSchema(meta.list(2))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return meta, cols, nil
+}
+
+// parseParquetSchema flattens the schema element list. Only a root group
+// followed by primitive leaves is supported.
+func parseParquetSchema(elements []any) ([]parquetColumnSchema, error) {
+	if len(elements) == 0 {
+		return nil, errors.New("parquet: empty schema")
+	}
+
+	cols := make([]parquetColumnSchema, 0, len(elements)-1)
+
+	for _, raw := range elements[1:] {
+		elem, ok := raw.(thriftStruct)
+		if !ok {
+			return nil, errors.New("parquet: malformed schema element")
+		}
+
+		name := elem.str(4)
+
+		if children, ok := elem.int(5); ok && children > 0 {
+			return nil, fmt.Errorf("parquet: nested column %q is not supported", name)
+		}
+
+		repetition, _ := elem.int(3)
+		if repetition == 2 {
+			return nil, fmt.Errorf("parquet: repeated column %q is not supported", name)
+		}
+
+		physical, ok := elem.int(1)
+		if !ok {
+			return nil, fmt.Errorf("parquet: column %q has no physical type", name)
+		}
+
+		typeLength, _ := elem.int(2)
+		scale, _ := elem.int(7)
+
+		converted, ok := elem.int(6)
+		if !ok {
+			converted = -1
+		}
+
+		cols = append(cols, parquetColumnSchema{
+			name:          name,
+			physical:      physical,
+			typeLength:    int(typeLength),
+			optional:      repetition == 1,
+			convertedType: converted,
+			scale:         int(scale),
+			logical:       elem.child(10),
+		})
+	}
+
+	return cols, nil
+}
+
+// readParquetColumnChunk decodes every page of a column chunk.
+func readParquetColumnChunk(file io.ReaderAt, col *parquetColumnSchema, meta thriftStruct) ([]any, error) {
+	if meta == nil {
+		return nil, errors.New("column chunk has no metadata")
+	}
+
+	codec, _ := meta.int(4)
+	numValues, _ := meta.int(5)
+	compressedSize, _ := meta.int(7)
+	offset, _ := meta.int(9)
+
+	if dictOffset, ok := meta.int(11); ok && dictOffset > 0 && dictOffset < offset {
+		offset = dictOffset
+	}
+
+	chunk := make([]byte, compressedSize)
+	if _, err := file.ReadAt(chunk, offset); err != nil {
+		return nil, fmt.Errorf("read column chunk: %w", err)
+	}
+
+	var (
+		dictionary []any
+		values     = make([]any, 0, numValues)
+		reader     = thriftReader{buf: chunk}
+	)
+
+	for int64(len(values)) < numValues && reader.pos < len(chunk) {
+		header, err := reader.readStruct()
+		if err != nil {
+			return nil, fmt.Errorf("decode page header: %w", err)
+		}
+
+		pageType, _ := header.int(1)
+		uncompressedSize, _ := header.int(2)
+		pageSize, _ := header.int(3)
+
+		if pageSize < 0 || reader.pos+int(pageSize) > len(chunk) {
+			return nil, errors.New("page extends past column chunk")
+		}
+
+		page := chunk[reader.pos : reader.pos+int(pageSize)]
+		reader.pos += int(pageSize)
+
+		switch pageType {
+		case parquetDictionaryPage:
+			body, err := parquetDecompress(codec, page, int(uncompressedSize))
+			if err != nil {
+				return nil, err
+			}
+
+			dictHeader := header.child(7)
+			count, _ := dictHeader.int(1)
+
+			raw, err := decodeParquetPlain(col, body, int(count))
+			if err != nil {
+				return nil, fmt.Errorf("decode dictionary: %w", err)
+			}
+
+			dictionary = make([]any, len(raw))
+			for idx, val := range raw {
+				dictionary[idx] = col.convert(val)
+			}
+
+		case parquetDataPage:
+			body, err := parquetDecompress(codec, page, int(uncompressedSize))
+			if err != nil {
+				return nil, err
+			}
+
+			dataHeader := header.child(5)
+			count, _ := dataHeader.int(1)
+			encoding, _ := dataHeader.int(2)
+
+			var defs []int
+
+			if col.optional {
+				if len(body) < 4 {
+					return nil, errors.New("truncated definition levels")
+				}
+
+				levelLen := int(binary.LittleEndian.Uint32(body[:4]))
+				if 4+levelLen > len(body) {
+					return nil, errors.New("truncated definition levels")
+				}
+
+				defs, err = decodeRLEHybrid(body[4:4+levelLen], 1, int(count))
+				if err != nil {
+					return nil, fmt.Errorf("decode definition levels: %w", err)
+				}
+
+				body = body[4+levelLen:]
+			}
+
+			values, err = appendParquetPage(values, col, encoding, body, int(count), defs, dictionary)
+			if err != nil {
+				return nil, err
+			}
+
+		case parquetDataPageV2:
+			dataHeader := header.child(8)
+			count, _ := dataHeader.int(1)
+			encoding, _ := dataHeader.int(4)
+			defLen, _ := dataHeader.int(5)
+			repLen, _ := dataHeader.int(6)
+			compressed := dataHeader.boolean(7, true)
+
+			if int(repLen+defLen) > len(page) {
+				return nil, errors.New("truncated levels")
+			}
+
+			var defs []int
+
+			if col.optional {
+				defs, err = decodeRLEHybrid(page[repLen:repLen+defLen], 1, int(count))
+				if err != nil {
+					return nil, fmt.Errorf("decode definition levels: %w", err)
+				}
+			}
+
+			body := page[repLen+defLen:]
+			if compressed {
+				body, err = parquetDecompress(codec, body, int(uncompressedSize-repLen-defLen))
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			values, err = appendParquetPage(values, col, encoding, body, int(count), defs, dictionary)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// appendParquetPage decodes count values from a data page body and appends
+// them to values, inserting nil wherever the definition level marks a null.
+func appendParquetPage(values []any, col *parquetColumnSchema, encoding int64, body []byte, count int, defs []int, dictionary []any) ([]any, error) {
+	present := count
+
+	if defs != nil {
+		present = 0
+
+		for _, def := range defs {
+			if def == 1 {
+				present++
+			}
+		}
+	}
+
+	var decoded []any
+
+	switch encoding {
+	case parquetEncodingPlain:
+		raw, err := decodeParquetPlain(col, body, present)
+		if err != nil {
+			return nil, err
+		}
+
+		decoded = make([]any, len(raw))
+		for idx, val := range raw {
+			decoded[idx] = col.convert(val)
+		}
+
+	case parquetEncodingPlainDictionary, parquetEncodingRLEDictionary:
+		if dictionary == nil {
+			return nil, errors.New("dictionary-encoded page without a dictionary")
+		}
+
+		if len(body) == 0 {
+			if present > 0 {
+				return nil, errors.New("truncated dictionary indices")
+			}
+
+			break
+		}
+
+		indices, err := decodeRLEHybrid(body[1:], int(body[0]), present)
+		if err != nil {
+			return nil, fmt.Errorf("decode dictionary indices: %w", err)
+		}
+
+		decoded = make([]any, len(indices))
+		for idx, dictIdx := range indices {
+			if dictIdx >= len(dictionary) {
+				return nil, errors.New("dictionary index out of range")
+			}
+
+			decoded[idx] = dictionary[dictIdx]
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported encoding %d", encoding)
+	}
+
+	if defs == nil {
+		return append(values, decoded...), nil
+	}
+
+	next := 0
+
+	for _, def := range defs {
+		if def == 1 {
+			values = append(values, decoded[next])
+			next++
+		} else {
+			values = append(values, nil)
+		}
+	}
+
+	return values, nil
+}
+
+// decodeParquetPlain decodes count PLAIN-encoded values of the column's
+// physical type. Integers are returned as int64, floats as float64, and
+// byte arrays as []byte.
+func decodeParquetPlain(col *parquetColumnSchema, buf []byte, count int) ([]any, error) {
+	out := make([]any, 0, count)
+	pos := 0
+
+	need := func(n int) error {
+		if pos+n > len(buf) {
+			return errors.New("truncated PLAIN values")
+		}
+
+		return nil
+	}
+
+	for range count {
+		switch col.physical {
+		case parquetBoolean:
+			idx := len(out)
+			if idx/8 >= len(buf) {
+				return nil, errors.New("truncated PLAIN values")
+			}
+
+			out = append(out, buf[idx/8]>>(idx%8)&1 == 1)
+
+		case parquetInt32:
+			if err := need(4); err != nil {
+				return nil, err
+			}
+
+			out = append(out, int64(int32(binary.LittleEndian.Uint32(buf[pos:]))))
+			pos += 4
+
+		case parquetInt64:
+			if err := need(8); err != nil {
+				return nil, err
+			}
+
+			out = append(out, int64(binary.LittleEndian.Uint64(buf[pos:])))
+			pos += 8
+
+		case parquetInt96:
+			if err := need(12); err != nil {
+				return nil, err
+			}
+
+			nanos := int64(binary.LittleEndian.Uint64(buf[pos:]))
+			julian := int64(binary.LittleEndian.Uint32(buf[pos+8:]))
+			out = append(out, time.Unix((julian-julianUnixEpoch)*86400, nanos).UTC())
+			pos += 12
+
+		case parquetFloat:
+			if err := need(4); err != nil {
+				return nil, err
+			}
+
+			out = append(out, float64(math.Float32frombits(binary.LittleEndian.Uint32(buf[pos:]))))
+			pos += 4
+
+		case parquetDouble:
+			if err := need(8); err != nil {
+				return nil, err
+			}
+
+			out = append(out, math.Float64frombits(binary.LittleEndian.Uint64(buf[pos:])))
+			pos += 8
+
+		case parquetByteArray:
+			if err := need(4); err != nil {
+				return nil, err
+			}
+
+			length := int(binary.LittleEndian.Uint32(buf[pos:]))
+			pos += 4
+
+			if err := need(length); err != nil {
+				return nil, err
+			}
+
+			out = append(out, buf[pos:pos+length])
+			pos += length
+
+		case parquetFixedLenByteArray:
+			if err := need(col.typeLength); err != nil {
+				return nil, err
+			}
+
+			out = append(out, buf[pos:pos+col.typeLength])
+			pos += col.typeLength
+
+		default:
+			return nil, fmt.Errorf("unsupported physical type %d", col.physical)
+		}
+	}
+
+	return out, nil
+}
+
+// convert applies the column's logical type to a raw PLAIN value.
+func (c *parquetColumnSchema) convert(raw any) any {
+	switch val := raw.(type) {
+	case bool:
+		if val {
+			return 1.0
+		}
+
+		return 0.0
+
+	case int64:
+		switch {
+		case c.isDate():
+			return time.Unix(val*86400, 0).UTC()
+		case c.timestampUnit() != 0:
+			return time.Unix(0, val*int64(c.timestampUnit())).UTC()
+		case c.isDecimal():
+			return float64(val) / math.Pow10(c.scale)
+		}
+
+		return float64(val)
+
+	case []byte:
+		if c.isDecimal() {
+			unscaled := new(big.Int).SetBytes(val)
+			if len(val) > 0 && val[0]&0x80 != 0 {
+				unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(len(val)*8)))
+			}
+
+			f, _ := new(big.Float).SetInt(unscaled).Float64()
+
+			return f / math.Pow10(c.scale)
+		}
+
+		return string(val)
+	}
+
+	return raw
+}
+
+func (c *parquetColumnSchema) isDate() bool {
+	return c.convertedType == parquetConvertedDate || c.logical.child(6) != nil
+}
+
+func (c *parquetColumnSchema) isDecimal() bool {
+	return c.convertedType == parquetConvertedDecimal || c.logical.child(5) != nil
+}
+
+// timestampUnit returns the duration of one tick of a TIMESTAMP column, or
+// zero when the column is not a timestamp.
+func (c *parquetColumnSchema) timestampUnit() time.Duration {
+	switch c.convertedType {
+	case parquetConvertedTimestampMillis:
+		return time.Millisecond
+	case parquetConvertedTimestampMicros:
+		return time.Microsecond
+	}
+
+	unit := c.logical.child(8).child(2)
+
+	switch {
+	case unit.child(1) != nil:
+		return time.Millisecond
+	case unit.child(2) != nil:
+		return time.Microsecond
+	case unit.child(3) != nil:
+		return time.Nanosecond
+	}
+
+	return 0
+}
+
+// decodeRLEHybrid decodes count values of the RLE / bit-packing hybrid
+// encoding used for definition levels and dictionary indices.
+func decodeRLEHybrid(buf []byte, bitWidth, count int) ([]int, error) {
+	out := make([]int, 0, count)
+	byteWidth := (bitWidth + 7) / 8
+	pos := 0
+
+	for len(out) < count {
+		header, n := binary.Uvarint(buf[pos:])
+		if n <= 0 {
+			return nil, errors.New("truncated RLE header")
+		}
+
+		pos += n
+
+		if header&1 == 0 {
+			run := int(header >> 1)
+			if pos+byteWidth > len(buf) {
+				return nil, errors.New("truncated RLE run")
+			}
+
+			value := 0
+			for idx := range byteWidth {
+				value |= int(buf[pos+idx]) << (8 * idx)
+			}
+
+			pos += byteWidth
+
+			for idx := 0; idx < run && len(out) < count; idx++ {
+				out = append(out, value)
+			}
+
+			continue
+		}
+
+		groups := int(header >> 1)
+		packedBytes := groups * bitWidth
+
+		if pos+packedBytes > len(buf) {
+			return nil, errors.New("truncated bit-packed run")
+		}
+
+		for idx := 0; idx < groups*8 && len(out) < count; idx++ {
+			value := 0
+
+			for bit := range bitWidth {
+				abs := idx*bitWidth + bit
+				if buf[pos+abs/8]>>(abs%8)&1 == 1 {
+					value |= 1 << bit
+				}
+			}
+
+			out = append(out, value)
+		}
+
+		pos += packedBytes
+	}
+
+	return out, nil
+}
+
+var (
+	zstdDecoderOnce sync.Once
+	zstdDecoder     *zstd.Decoder
+	zstdDecoderErr  error
+)
+
+// parquetDecompress inflates a page body with the column chunk's codec.
+func parquetDecompress(codec int64, src []byte, size int) ([]byte, error) {
+	switch codec {
+	case 0:
+		return src, nil
+
+	case 1:
+		out, err := snappy.Decode(make([]byte, 0, size), src)
+		if err != nil {
+			return nil, fmt.Errorf("snappy: %w", err)
+		}
+
+		return out, nil
+
+	case 2:
+		reader, err := gzip.NewReader(bytes.NewReader(src))
+		if err != nil {
+			return nil, fmt.Errorf("gzip: %w", err)
+		}
+
+		return io.ReadAll(reader)
+
+	case 4:
+		return io.ReadAll(brotli.NewReader(bytes.NewReader(src)))
+
+	case 6:
+		zstdDecoderOnce.Do(func() {
+			zstdDecoder, zstdDecoderErr = zstd.NewReader(nil)
+		})
+
+		if zstdDecoderErr != nil {
+			return nil, fmt.Errorf("zstd: %w", zstdDecoderErr)
+		}
+
+		return zstdDecoder.DecodeAll(src, make([]byte, 0, size))
+	}
+
+	return nil, fmt.Errorf("unsupported compression codec %d", codec)
+}
+
+// thriftStruct is a decoded Thrift compact-protocol struct keyed by field
+// id. Integer fields of every width decode to int64.
+type thriftStruct map[int16]any
+
+func (s thriftStruct) int(id int16) (int64, bool) {
+	val, ok := s[id].(int64)
+	return val, ok
+}
+
+func (s thriftStruct) str(id int16) string {
+	val, _ := s[id].([]byte)
+	return string(val)
+}
+
+func (s thriftStruct) boolean(id int16, fallback bool) bool {
+	val, ok := s[id].(bool)
+	if !ok {
+		return fallback
+	}
+
+	return val
+}
+
+func (s thriftStruct) list(id int16) []any {
+	val, _ := s[id].([]any)
+	return val
+}
+
+func (s thriftStruct) child(id int16) thriftStruct {
+	val, _ := s[id].(thriftStruct)
+	return val
+}
+
+// Thrift compact-protocol type ids.
+const (
+	thriftTrue   = 1
+	thriftFalse  = 2
+	thriftByte   = 3
+	thriftI16    = 4
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftDouble = 7
+	thriftBinary = 8
+	thriftList   = 9
+	thriftSet    = 10
+	thriftMap    = 11
+	thriftStrct  = 12
+)
+
+// thriftReader decodes the Thrift compact protocol used by Parquet
+// metadata.
+type thriftReader struct {
+	buf []byte
+	pos int
+}
+
+func (r *thriftReader) readByte() (byte, error) {
+	if r.pos >= len(r.buf) {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	b := r.buf[r.pos]
+	r.pos++
+
+	return b, nil
+}
+
+func (r *thriftReader) readUvarint() (uint64, error) {
+	val, n := binary.Uvarint(r.buf[r.pos:])
+	if n <= 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	r.pos += n
+
+	return val, nil
+}
+
+func (r *thriftReader) readVarint() (int64, error) {
+	val, err := r.readUvarint()
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(val>>1) ^ -int64(val&1), nil
+}
+
+func (r *thriftReader) readStruct() (thriftStruct, error) {
+	out := thriftStruct{}
+
+	var lastID int16
+
+	for {
+		header, err := r.readByte()
+		if err != nil {
+			return nil, err
+		}
+
+		if header == 0 {
+			return out, nil
+		}
+
+		fieldType := header & 0x0f
+
+		id := lastID + int16(header>>4)
+		if header>>4 == 0 {
+			raw, err := r.readVarint()
+			if err != nil {
+				return nil, err
+			}
+
+			id = int16(raw)
+		}
+
+		lastID = id
+
+		switch fieldType {
+		case thriftTrue:
+			out[id] = true
+		case thriftFalse:
+			out[id] = false
+		default:
+			val, err := r.readValue(fieldType)
+			if err != nil {
+				return nil, err
+			}
+
+			out[id] = val
+		}
+	}
+}
+
+func (r *thriftReader) readValue(fieldType byte) (any, error) {
+	switch fieldType {
+	case thriftTrue, thriftFalse:
+		b, err := r.readByte()
+		return b == thriftTrue, err
+
+	case thriftByte:
+		b, err := r.readByte()
+		return int64(int8(b)), err
+
+	case thriftI16, thriftI32, thriftI64:
+		return r.readVarint()
+
+	case thriftDouble:
+		if r.pos+8 > len(r.buf) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		val := math.Float64frombits(binary.LittleEndian.Uint64(r.buf[r.pos:]))
+		r.pos += 8
+
+		return val, nil
+
+	case thriftBinary:
+		length, err := r.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+
+		if length > uint64(len(r.buf)-r.pos) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		val := r.buf[r.pos : r.pos+int(length)]
+		r.pos += int(length)
+
+		return val, nil
+
+	case thriftList, thriftSet:
+		header, err := r.readByte()
+		if err != nil {
+			return nil, err
+		}
+
+		size := uint64(header >> 4)
+		if size == 15 {
+			if size, err = r.readUvarint(); err != nil {
+				return nil, err
+			}
+		}
+
+		if size > uint64(len(r.buf)-r.pos) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		elemType := header & 0x0f
+		out := make([]any, 0, size)
+
+		for range size {
+			val, err := r.readValue(elemType)
+			if err != nil {
+				return nil, err
+			}
+
+			out = append(out, val)
+		}
+
+		return out, nil
+
+	case thriftMap:
+		size, err := r.readUvarint()
+		if err != nil || size == 0 {
+			return nil, err
+		}
+
+		kinds, err := r.readByte()
+		if err != nil {
+			return nil, err
+		}
+
+		for range size {
+			if _, err := r.readValue(kinds >> 4); err != nil {
+				return nil, err
+			}
+
+			if _, err := r.readValue(kinds & 0x0f); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, nil
+
+	case thriftStrct:
+		return r.readStruct()
+	}
+
+	return nil, fmt.Errorf("unknown thrift type %d", fieldType)
+}
@@ -45,6 +45,7 @@ var _ RatingProvider = (*PVDataProvider)(nil)
 var _ IndexProvider = (*PVDataProvider)(nil)
 var _ interface{ Dimension() string } = (*PVDataProvider)(nil)
 var _ FundamentalsByDateKeyProvider = (*PVDataProvider)(nil)
+var _ CacheKeyed = (*PVDataProvider)(nil)
 
 // scanPgxAsset scans a full asset row from a pgx result set. All metadata
 // columns are nullable in the view, so we scan into pointers and fall back
@@ -77,6 +78,7 @@ func scanPgxAsset(scanner interface{ Scan(dest ...any) error }) (asset.Asset, er
 
 	if exchange != nil {
 		aa.PrimaryExchange = asset.NormalizeExchange(*exchange)
+		aa.Currency = asset.ExchangeCurrency(aa.PrimaryExchange)
 	}
 
 	if sector != nil {
@@ -184,6 +186,13 @@ func (p *PVDataProvider) Dimension() string {
 	return p.dimension
 }
 
+// CacheKey identifies the database the provider reads from.
+func (p *PVDataProvider) CacheKey() string {
+	conn := p.pool.Config().ConnConfig
+
+	return fmt.Sprintf("pvdata:%s:%d/%s", conn.Host, conn.Port, conn.Database)
+}
+
 // NewPVDataProvider creates a provider that reads from a pv-data database.
 // If pool is nil the provider reads ~/.pvdata.toml (or the path set via
 // WithConfigFile) for the connection URL and creates its own pool.
@@ -2,10 +2,13 @@ package data
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
 	"github.com/bytedance/sonic"
 	"math"
+	"path/filepath"
 	"slices"
 	"sort"
 	"strings"
@@ -25,11 +28,13 @@ var (
 	_ RatingProvider                = (*SnapshotProvider)(nil)
 	_ HolidayProvider               = (*SnapshotProvider)(nil)
 	_ FundamentalsByDateKeyProvider = (*SnapshotProvider)(nil)
+	_ CacheKeyed                    = (*SnapshotProvider)(nil)
 )
 
 // SnapshotProvider replays data from a snapshot SQLite database.
 type SnapshotProvider struct {
 	db        *sql.DB
+	path      string
 	dimension string
 }
 
@@ -59,6 +64,7 @@ func scanAssetRow(scanner interface{ Scan(dest ...any) error }, extra ...any) (a
 	aa.Name = name
 	aa.AssetType = asset.AssetType(assetType)
 	aa.PrimaryExchange = asset.Exchange(exchange)
+	aa.Currency = asset.ExchangeCurrency(aa.PrimaryExchange)
 	aa.Sector = asset.Sector(sector)
 	aa.Industry = asset.Industry(industry)
 	aa.SICCode = sicCode
@@ -99,7 +105,22 @@ func NewSnapshotProvider(path string) (*SnapshotProvider, error) {
 		return nil, fmt.Errorf("snapshot provider: set read-only: %w", err)
 	}
 
-	return &SnapshotProvider{db: db, dimension: "ARQ"}, nil
+	return &SnapshotProvider{db: db, path: path, dimension: "ARQ"}, nil
+}
+
+// CacheKey identifies the snapshot file the provider replays by its path,
+// size and modification time, so a rewritten snapshot does not share
+// chunks with the one it replaced.
+func (p *SnapshotProvider) CacheKey() string {
+	path := p.path
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	digest := sha256.New()
+	writeFileStamp(digest, path)
+
+	return "snapshot:" + path + ":" + hex.EncodeToString(digest.Sum(nil))
 }
 
 // Close closes the database connection.
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"math"
+	"os"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -40,6 +41,30 @@ var _ = Describe("SnapshotProvider", func() {
 		db.Close()
 	}
 
+	Describe("CacheKey", func() {
+		It("changes when the snapshot file is rewritten", func() {
+			seedDB()
+
+			snap, err := data.NewSnapshotProvider(dbPath)
+			Expect(err).NotTo(HaveOccurred())
+			defer snap.Close()
+
+			before := snap.CacheKey()
+			Expect(snap.CacheKey()).To(Equal(before))
+
+			db, err := sql.Open("sqlite", dbPath)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = db.Exec(`DELETE FROM assets WHERE ticker = 'TLT'`)
+			Expect(err).NotTo(HaveOccurred())
+			db.Close()
+
+			later := time.Now().Add(time.Minute)
+			Expect(os.Chtimes(dbPath, later, later)).To(Succeed())
+
+			Expect(snap.CacheKey()).NotTo(Equal(before))
+		})
+	})
+
 	Describe("Assets", func() {
 		It("returns all assets with metadata from the snapshot", func() {
 			seedDB()
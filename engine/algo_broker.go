@@ -0,0 +1,502 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/rs/zerolog"
+)
+
+// liveAlgo tracks a parent order an algoBroker is working.
+type liveAlgo struct {
+	algoOrder
+
+	ctx   context.Context
+	quit  chan struct{}
+	count int
+
+	// open maps each child order still at the broker to its unfilled
+	// quantity; sent is the quantity of children submitted and not
+	// rejected.
+	open      map[string]float64
+	sent      float64
+	scheduled bool
+	finished  bool
+}
+
+// algoBroker works orders that carry an execution algorithm against a live
+// broker. It submits each parent's child orders on the algorithm's
+// schedule and reports their fills under the parent's ID, so the account
+// and the strategy see the order that was placed. Other orders pass
+// straight through to the wrapped broker.
+//
+// TWAP sends equal slices evenly over the duration. VWAP sizes its slices
+// by the previous day's volume over the same minutes when an
+// IntradayProvider is registered, and sends equal slices otherwise. POV
+// polls the minute volume and sends its share of each minute's volume
+// until the order is filled. Fills replayed after a restart are reported
+// by the wrapped broker under the child IDs and are not mapped back.
+type algoBroker struct {
+	broker.Broker
+
+	intraday     IntradayProvider
+	prices       func() broker.PriceProvider
+	pollInterval time.Duration
+
+	fills      chan broker.Fill
+	forwarding sync.Once
+	done       chan struct{}
+	closing    sync.Once
+
+	mu       sync.Mutex
+	parents  map[string]*liveAlgo
+	children map[string]string // child order ID -> parent order ID
+	reports  []broker.ExecutionReport
+	outbox   []broker.Fill // fills queued under ab.mu, sent by flush
+}
+
+// algoGroupBroker is an algoBroker whose wrapped broker submits contingent
+// order groups natively.
+type algoGroupBroker struct {
+	*algoBroker
+}
+
+// SubmitGroup passes the group to the wrapped broker.
+func (ab algoGroupBroker) SubmitGroup(ctx context.Context, orders []broker.Order, groupType broker.GroupType) error {
+	return ab.Broker.(broker.GroupSubmitter).SubmitGroup(ctx, orders, groupType)
+}
+
+// newAlgoBroker wraps inner so it works algorithmic orders. intraday may
+// be nil, in which case POV orders fail and VWAP falls back to equal
+// slices.
+func newAlgoBroker(inner broker.Broker, intraday IntradayProvider, prices func() broker.PriceProvider) *algoBroker {
+	return &algoBroker{
+		Broker:       inner,
+		intraday:     intraday,
+		prices:       prices,
+		pollInterval: time.Minute,
+		fills:        make(chan broker.Fill, fillChannelSize),
+		done:         make(chan struct{}),
+		parents:      make(map[string]*liveAlgo),
+		children:     make(map[string]string),
+	}
+}
+
+// asBroker returns ab as the broker the account submits to, keeping the
+// wrapped broker's native group support visible.
+func (ab *algoBroker) asBroker() broker.Broker {
+	if _, ok := ab.Broker.(broker.GroupSubmitter); ok {
+		return algoGroupBroker{ab}
+	}
+
+	return ab
+}
+
+// Fills returns the channel carrying the wrapped broker's fills, with child
+// fills reported under their parent's ID.
+func (ab *algoBroker) Fills() <-chan broker.Fill {
+	ab.forwarding.Do(func() { go ab.forward() })
+
+	return ab.fills
+}
+
+// Close stops working orders. The engine closes the wrapped broker itself.
+func (ab *algoBroker) Close() error {
+	ab.closing.Do(func() { close(ab.done) })
+
+	return nil
+}
+
+// ExecutionReports returns a report for each algorithmic order that has
+// finished.
+func (ab *algoBroker) ExecutionReports() []broker.ExecutionReport {
+	ab.mu.Lock()
+	defer ab.mu.Unlock()
+
+	return append([]broker.ExecutionReport(nil), ab.reports...)
+}
+
+// Submit starts working an algorithmic order or passes any other order to
+// the wrapped broker.
+func (ab *algoBroker) Submit(ctx context.Context, order broker.Order) error {
+	if order.ExecAlgo == broker.ExecImmediate {
+		return ab.Broker.Submit(ctx, order)
+	}
+
+	if err := validateExecAlgo(order); err != nil {
+		ab.send(broker.Fill{OrderID: order.ID, FilledAt: time.Now(), Err: err})
+
+		return nil
+	}
+
+	if order.ExecAlgo == broker.ExecPOV && ab.intraday == nil {
+		ab.send(broker.Fill{OrderID: order.ID, FilledAt: time.Now(), Err: fmt.Errorf(
+			"%s order for %s needs an IntradayProvider for minute volume", order.ExecAlgo, order.Asset.Ticker)})
+
+		return nil
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	now := time.Now()
+
+	state := &liveAlgo{
+		algoOrder: algoOrder{order: order, submitted: now, arrival: ab.arrivalPrice(ctx, order.Asset), start: now},
+		ctx:       ctx,
+		quit:      make(chan struct{}),
+		open:      make(map[string]float64),
+	}
+
+	var quantities []float64
+	if order.ExecAlgo != broker.ExecPOV {
+		quantities = ab.sliceQuantities(ctx, order, now)
+	}
+
+	ab.mu.Lock()
+	ab.parents[order.ID] = state
+	ab.mu.Unlock()
+
+	if order.ExecAlgo == broker.ExecPOV {
+		go ab.workPOV(state)
+	} else {
+		go ab.workSlices(state, quantities)
+	}
+
+	return nil
+}
+
+// Cancel stops working an algorithmic order and cancels its open child
+// orders, or passes any other cancellation to the wrapped broker.
+func (ab *algoBroker) Cancel(ctx context.Context, orderID string) error {
+	ab.mu.Lock()
+
+	state, ok := ab.parents[orderID]
+	if !ok {
+		ab.mu.Unlock()
+
+		return ab.Broker.Cancel(ctx, orderID)
+	}
+
+	state.stopped = true
+
+	open := make([]string, 0, len(state.open))
+	for childID := range state.open {
+		open = append(open, childID)
+	}
+
+	ab.finish(state)
+	ab.mu.Unlock()
+	ab.flush()
+
+	for _, childID := range open {
+		if err := ab.Broker.Cancel(ctx, childID); err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Str("order", childID).Msg("cancel child order failed")
+		}
+	}
+
+	return nil
+}
+
+// arrivalPrice returns ast's current close, or zero when it is unknown.
+func (ab *algoBroker) arrivalPrice(ctx context.Context, ast asset.Asset) float64 {
+	if ab.prices == nil {
+		return 0
+	}
+
+	df, err := ab.prices().Prices(ctx, ast)
+	if err != nil {
+		return 0
+	}
+
+	closePrice := df.Value(ast, data.MetricClose)
+	if math.IsNaN(closePrice) {
+		return 0
+	}
+
+	return closePrice
+}
+
+// sliceQuantities splits a TWAP or VWAP order into the quantity of each
+// slice. VWAP weights the slices by the volume profile of the same
+// minutes over the prior sessions.
+func (ab *algoBroker) sliceQuantities(ctx context.Context, order broker.Order, now time.Time) []float64 {
+	var weights []float64
+
+	if order.ExecAlgo == broker.ExecVWAP {
+		weights = volumeProfile(ctx, ab.intraday, order.Asset, now, order.ExecDuration, order.ExecSlices)
+	}
+
+	targets := sliceTargets(order.Qty, weights, order.ExecSlices, wholeShares(order))
+	quantities := make([]float64, len(targets))
+
+	var prev float64
+
+	for idx, target := range targets {
+		quantities[idx] = target - prev
+		prev = target
+	}
+
+	return quantities
+}
+
+// workSlices sends one child order per slice, spaced evenly over the
+// order's duration.
+func (ab *algoBroker) workSlices(state *liveAlgo, quantities []float64) {
+	step := state.order.ExecDuration / time.Duration(len(quantities))
+
+	for idx, qty := range quantities {
+		if idx > 0 {
+			select {
+			case <-time.After(step):
+			case <-state.quit:
+				return
+			case <-ab.done:
+				return
+			}
+		}
+
+		if qty > 0 {
+			ab.submitChild(state, qty)
+		}
+	}
+
+	ab.scheduleDone(state)
+}
+
+// workPOV sends a child order for the order's share of each polling
+// interval's volume until the whole order has been sent.
+func (ab *algoBroker) workPOV(state *liveAlgo) {
+	order := state.order
+
+	ticker := time.NewTicker(ab.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-state.quit:
+			return
+		case <-ab.done:
+			return
+		case now := <-ticker.C:
+			bars, err := ab.intraday.IntradayFetch(state.ctx, []asset.Asset{order.Asset}, []data.Metric{data.Volume},
+				now.Add(-ab.pollInterval), now, nil)
+			if err != nil {
+				zerolog.Ctx(state.ctx).Warn().Err(err).Str("asset", order.Asset.Ticker).Msg("execution algorithm: fetch minute volume failed")
+				continue
+			}
+
+			var volume float64
+
+			for _, barVolume := range bars.Column(order.Asset, data.Volume) {
+				if !math.IsNaN(barVolume) {
+					volume += barVolume
+				}
+			}
+
+			ab.mu.Lock()
+			unsent := order.Qty - state.sent
+			ab.mu.Unlock()
+
+			qty := math.Min(unsent, volume*order.ExecParticipation/100)
+			if wholeShares(order) {
+				qty = math.Floor(qty)
+			}
+
+			if qty > 0 {
+				ab.submitChild(state, qty)
+			}
+
+			if unsent-qty <= 1e-9 {
+				ab.scheduleDone(state)
+
+				return
+			}
+		}
+	}
+}
+
+// submitChild sends a child order of qty shares to the wrapped broker.
+func (ab *algoBroker) submitChild(state *liveAlgo, qty float64) {
+	ab.mu.Lock()
+
+	if state.finished {
+		ab.mu.Unlock()
+
+		return
+	}
+
+	state.count++
+
+	child := state.order
+	child.ID = fmt.Sprintf("%s-%d", state.order.ID, state.count)
+	child.Qty = qty
+	child.ExecAlgo = broker.ExecImmediate
+
+	ab.children[child.ID] = state.order.ID
+	state.open[child.ID] = qty
+	state.sent += qty
+
+	ab.mu.Unlock()
+
+	if err := ab.Broker.Submit(state.ctx, child); err != nil {
+		zerolog.Ctx(state.ctx).Warn().Err(err).Str("order", child.ID).Msg("execution algorithm: submit child order failed")
+
+		ab.mu.Lock()
+		ab.release(state, child.ID)
+		ab.mu.Unlock()
+		ab.flush()
+	}
+}
+
+// scheduleDone records that every child order has been sent.
+func (ab *algoBroker) scheduleDone(state *liveAlgo) {
+	ab.mu.Lock()
+	state.scheduled = true
+	ab.maybeFinish(state)
+	ab.mu.Unlock()
+	ab.flush()
+}
+
+// release forgets an open child order that will not fill. Callers hold
+// ab.mu.
+func (ab *algoBroker) release(state *liveAlgo, childID string) {
+	state.sent -= state.open[childID]
+	delete(state.open, childID)
+	ab.maybeFinish(state)
+}
+
+// forward relays the wrapped broker's fills until the broker is closed.
+func (ab *algoBroker) forward() {
+	inner := ab.Broker.Fills()
+
+	for {
+		select {
+		case <-ab.done:
+			return
+		case fill, ok := <-inner:
+			if !ok {
+				return
+			}
+
+			ab.route(fill)
+		}
+	}
+}
+
+// route reports a child fill under its parent's ID and updates the
+// parent's totals. A rejected child is logged rather than failing the
+// parent; the shares it carried count as unfilled.
+func (ab *algoBroker) route(fill broker.Fill) {
+	ab.mu.Lock()
+
+	defer ab.flush()
+	defer ab.mu.Unlock()
+
+	parentID, ok := ab.children[fill.OrderID]
+	if !ok {
+		ab.outbox = append(ab.outbox, fill)
+
+		return
+	}
+
+	state := ab.parents[parentID]
+
+	if fill.Err != nil {
+		if state != nil {
+			zerolog.Ctx(state.ctx).Warn().Err(fill.Err).Str("order", fill.OrderID).Msg("execution algorithm: child order failed")
+			ab.release(state, fill.OrderID)
+		}
+
+		return
+	}
+
+	childID := fill.OrderID
+	fill.OrderID = parentID
+	ab.outbox = append(ab.outbox, fill)
+
+	if state != nil {
+		state.record(fill)
+		state.lastBar = fill.FilledAt
+
+		if state.open[childID] -= fill.Qty; state.open[childID] <= 1e-9 {
+			delete(state.open, childID)
+		}
+
+		ab.maybeFinish(state)
+	}
+}
+
+// maybeFinish finishes the parent once it is filled, or once every child
+// has been sent and resolved. Callers hold ab.mu.
+func (ab *algoBroker) maybeFinish(state *liveAlgo) {
+	if state.remaining() <= 1e-9 || (state.scheduled && len(state.open) == 0) {
+		ab.finish(state)
+	}
+}
+
+// finish stops working the parent, fails any unfilled remainder so the
+// account releases it, and records its execution report. Callers hold
+// ab.mu.
+func (ab *algoBroker) finish(state *liveAlgo) {
+	if state.finished {
+		return
+	}
+
+	state.finished = true
+	close(state.quit)
+	delete(ab.parents, state.order.ID)
+
+	if !state.stopped && state.remaining() > 1e-9 {
+		ab.outbox = append(ab.outbox, broker.Fill{OrderID: state.order.ID, FilledAt: time.Now(), Err: fmt.Errorf(
+			"%s order for %s ended with %g of %g shares unfilled",
+			state.order.ExecAlgo, state.order.Asset.Ticker, state.remaining(), state.order.Qty)})
+	}
+
+	report := state.report()
+	ab.reports = append(ab.reports, report)
+
+	logExecutionReport(state.ctx, report)
+}
+
+// flush sends the queued fills. The fill channel is drained by the engine
+// goroutine, which may be waiting on ab.mu, so fills are never sent while
+// holding it.
+func (ab *algoBroker) flush() {
+	ab.mu.Lock()
+	queued := ab.outbox
+	ab.outbox = nil
+	ab.mu.Unlock()
+
+	for _, fill := range queued {
+		ab.send(fill)
+	}
+}
+
+// send delivers fill on the account's fill channel unless the broker has
+// been closed.
+func (ab *algoBroker) send(fill broker.Fill) {
+	select {
+	case ab.fills <- fill:
+	case <-ab.done:
+	}
+}
@@ -34,19 +34,10 @@ import (
 // configured strategy and data providers. It returns the portfolio
 // after running every scheduled trading date.
 func (e *Engine) Backtest(ctx context.Context, start, end time.Time) (portfolio.Portfolio, error) {
-	// The daily equity schedule stamps each trading day at the 16:00 ET
-	// close, so date enumeration must treat end as inclusive of the end
-	// date's own close. A date-only end (e.g. the --end flag) parses to
-	// midnight, which would otherwise drop that day's 16:00 close and stop
-	// the backtest on the prior trading day. Extend the enumeration boundary
-	// to the final instant of the end date's New York trading day; the
-	// caller's requested end is preserved separately for reporting.
+	// The caller's requested end is reported as-is; end itself is extended
+	// to the end date's close once the market calendar is known (step 4a).
 	requestedEnd := end
 
-	endLocal := end.In(nyc)
-	end = time.Date(endLocal.Year(), endLocal.Month(), endLocal.Day(),
-		23, 59, 59, int(time.Second-time.Nanosecond), nyc)
-
 	// PHASE 1: INITIALIZATION
 
 	// 1. Load asset registry from assetProvider.
@@ -94,12 +85,28 @@ func (e *Engine) Backtest(ctx context.Context, start, end time.Time) (portfolio.
 	// 4. Call strategy.Setup.
 	e.strategy.Setup(e)
 
+	// 4a. Choose the market calendar from the strategy's listing exchange.
+	e.resolveCalendar(ctx)
+
+	// The daily equity schedule stamps each trading day at the calendar's
+	// close (16:00 ET on NYSE), so date enumeration must treat end as
+	// inclusive of the end date's own close. A date-only end (e.g. the
+	// --end flag) parses to midnight, which would otherwise drop that day's
+	// close and stop the backtest on the prior trading day. Extend the
+	// enumeration boundary to the final instant of the end date's trading
+	// day in the calendar's time zone; the caller's requested end is
+	// preserved separately for reporting.
+	marketTZ := e.marketCalendar().Location()
+	endLocal := end.In(marketTZ)
+	end = time.Date(endLocal.Year(), endLocal.Month(), endLocal.Day(),
+		23, 59, 59, int(time.Second-time.Nanosecond), marketTZ)
+
 	// 4b. If Setup did not set schedule/benchmark, try Describe().
 	if desc, ok := e.strategy.(Descriptor); ok {
 		description := desc.Describe()
 
 		if e.schedule == nil && description.Schedule != "" {
-			tc, tcErr := tradecron.New(description.Schedule, tradecron.RegularHours)
+			tc, tcErr := e.newSchedule(description.Schedule)
 			if tcErr != nil {
 				return nil, fmt.Errorf("engine: parsing schedule from Describe(): %w", tcErr)
 			}
@@ -148,7 +155,7 @@ func (e *Engine) Backtest(ctx context.Context, start, end time.Time) (portfolio.
 		if desc, ok := child.strategy.(Descriptor); ok {
 			description := desc.Describe()
 			if description.Schedule != "" {
-				tc, tcErr := tradecron.New(description.Schedule, tradecron.RegularHours)
+				tc, tcErr := e.newSchedule(description.Schedule)
 				if tcErr != nil {
 					return nil, fmt.Errorf("engine: child %q schedule: %w", child.name, tcErr)
 				}
@@ -216,6 +223,7 @@ func (e *Engine) Backtest(ctx context.Context, start, end time.Time) (portfolio.
 	}
 
 	applyMarginConfig(e, acct)
+	e.watchEvents(acct)
 
 	// 6b. Apply config-driven middleware if provided.
 	if e.middlewareConfig != nil {
@@ -277,6 +285,16 @@ func (e *Engine) Backtest(ctx context.Context, start, end time.Time) (portfolio.
 
 	e.riskFreeCumulative = 0
 
+	// 7b. Resolve scheduled deposits and withdrawals to trading dates.
+	if err := e.planCashFlows(ctx, start, end); err != nil {
+		return nil, err
+	}
+
+	// 7c. Resolve exchange-rate series for foreign-currency assets.
+	if err := e.resolveFXSeries(ctx); err != nil {
+		return nil, err
+	}
+
 	// 8. Store start/end on engine.
 	e.start = start
 	e.end = reportedEnd
@@ -286,7 +304,9 @@ func (e *Engine) Backtest(ctx context.Context, start, end time.Time) (portfolio.
 	if sb, ok := e.broker.(*SimulatedBroker); ok {
 		sb.SetPortfolio(acct)
 		sb.SetBorrowRate(acct.BorrowRate())
+		sb.SetTradingDaysPerYear(e.marketCalendar().TradingDaysPerYear())
 		sb.SetMaxLeverage(acct.MaxLeverage())
+		sb.SetIntradayProvider(e.findIntradayProvider(), e.Now)
 	}
 
 	// Connect the broker (no-op for SimulatedBroker, authenticates for live brokers).
@@ -297,7 +317,7 @@ func (e *Engine) Backtest(ctx context.Context, start, end time.Time) (portfolio.
 	// PHASE 2: DATE ENUMERATION
 
 	// 9. Create a daily schedule for equity recording on every trading day.
-	dailySchedule, dailyErr := tradecron.New("@close * * *", tradecron.RegularHours)
+	dailySchedule, dailyErr := e.newSchedule("@close * * *")
 	if dailyErr != nil {
 		return nil, fmt.Errorf("engine: creating daily equity schedule: %w", dailyErr)
 	}
@@ -372,6 +392,8 @@ func (e *Engine) Backtest(ctx context.Context, start, end time.Time) (portfolio.
 
 	// PHASE 3: STEP LOOP
 
+	execute := e.backtestExecutor(acct)
+
 	for stepIdx, step := range steps {
 		// 10. Check context cancellation.
 		if err := ctx.Err(); err != nil {
@@ -411,6 +433,17 @@ func (e *Engine) Backtest(ctx context.Context, start, end time.Time) (portfolio.
 			return nil, fmt.Errorf("engine: prefetch housekeeping prices on %v: %w", date, err)
 		}
 
+		// Mark exchange rates before any fills, dividends, or cash flows
+		// in foreign currencies are recorded.
+		fxAccounts := []portfolio.PortfolioManager{acct}
+		for _, child := range e.children {
+			fxAccounts = append(fxAccounts, child.account)
+		}
+
+		if err := e.markFXRates(stepCtx, date, fxAccounts...); err != nil {
+			return nil, err
+		}
+
 		// 13-14b. Housekeep parent account (dividends + fill draining).
 		if err := e.housekeepAccount(stepCtx, acct, e.broker, date); err != nil {
 			return nil, err
@@ -429,6 +462,18 @@ func (e *Engine) Backtest(ctx context.Context, start, end time.Time) (portfolio.
 			return nil, fmt.Errorf("engine: margin call on %v: %w", date, err)
 		}
 
+		// Post scheduled deposits and withdrawals before the strategy runs
+		// so it can invest contributions or raise cash for withdrawals.
+		if err := e.applyCashFlows(stepCtx, acct, date); err != nil {
+			return nil, err
+		}
+
+		// Deliver the fills, dividends, and splits applied during
+		// housekeeping to the strategy's event callbacks.
+		if err := e.dispatchEvents(stepCtx, acct, date, execute); err != nil {
+			return nil, err
+		}
+
 		// Run scheduled child strategies (children before parent). Each
 		// child fires once per scheduled timestamp on this date.
 		for childName, childFirings := range step.childFirings {
@@ -503,11 +548,66 @@ func (e *Engine) Backtest(ctx context.Context, start, end time.Time) (portfolio.
 				if err := e.reconcileBatch(stepCtx, e.strategy, acct, batch); err != nil {
 					return nil, fmt.Errorf("engine: reconcile batch on %v: %w", fireTime, err)
 				}
+
+				if err := e.dispatchEvents(stepCtx, acct, fireTime, execute); err != nil {
+					return nil, err
+				}
 			}
 
 			e.currentTime = date
 		}
 
+		// 16b. Check data-driven triggers against the close and run the
+		// strategy again when any of them fires.
+		if len(e.triggers) > 0 {
+			if err := e.setMarginPrices(stepCtx, acct, date); err != nil {
+				return nil, err
+			}
+
+			fired, err := e.firedTriggers(stepCtx, acct, date)
+			if err != nil {
+				return nil, fmt.Errorf("engine: triggers on %v: %w", date, err)
+			}
+
+			if len(fired) > 0 {
+				if sb, ok := e.broker.(*SimulatedBroker); ok {
+					sb.SetPriceProvider(e, date)
+				}
+
+				if err := acct.CancelOpenOrders(stepCtx); err != nil {
+					return nil, fmt.Errorf("engine: cancel open orders on %v: %w", date, err)
+				}
+
+				batch := acct.NewBatch(date)
+				if err := e.computeTriggered(stepCtx, acct, batch, fired); err != nil {
+					return nil, fmt.Errorf("engine: strategy %q trigger on %v: %w",
+						e.strategy.Name(), date, err)
+				}
+
+				if err := e.prefetchBrokerPrices(stepCtx, batch.Orders); err != nil {
+					return nil, fmt.Errorf("engine: prefetch broker prices on %v: %w", date, err)
+				}
+
+				if err := acct.ExecuteBatch(stepCtx, batch); err != nil {
+					return nil, fmt.Errorf("engine: execute trigger batch on %v: %w", date, err)
+				}
+
+				if err := e.reconcileBatch(stepCtx, e.strategy, acct, batch); err != nil {
+					return nil, fmt.Errorf("engine: reconcile trigger batch on %v: %w", date, err)
+				}
+			}
+		}
+
+		// 16c. Run the strategy's OnBar at the close of every trading day,
+		// then deliver fills from the trigger and bar batches.
+		if err := e.runBar(stepCtx, acct, date, execute); err != nil {
+			return nil, err
+		}
+
+		if err := e.dispatchEvents(stepCtx, acct, date, execute); err != nil {
+			return nil, err
+		}
+
 		// 17-18. Update parent account prices once per date (end-of-day
 		// equity recording is independent of intra-day firing cadence).
 		if err := e.updateAccountPrices(stepCtx, acct, date, e.benchmark); err != nil {
@@ -657,6 +757,30 @@ func (eng *Engine) housekeepAccount(ctx context.Context, acct portfolio.Portfoli
 	return nil
 }
 
+// withOptionUnderlyings appends the underlying of every option in assets
+// that is not already among them, so expiring options can be settled
+// against the underlying's close.
+func withOptionUnderlyings(assets []asset.Asset) []asset.Asset {
+	present := make(map[string]bool, len(assets))
+	for _, ast := range assets {
+		present[ast.CompositeFigi] = true
+	}
+
+	for _, ast := range assets {
+		if !ast.IsOption() {
+			continue
+		}
+
+		underlying := ast.Contract.UnderlyingAsset()
+		if !present[underlying.CompositeFigi] {
+			present[underlying.CompositeFigi] = true
+			assets = append(assets, underlying)
+		}
+	}
+
+	return assets
+}
+
 // updateAccountPrices fetches current prices and updates equity for the given
 // account on date. benchmark controls whether the benchmark asset is included
 // in the price fetch; pass asset.Asset{} for child accounts. The risk-free
@@ -672,6 +796,8 @@ func (eng *Engine) updateAccountPrices(ctx context.Context, acct portfolio.Portf
 		priceAssets = append(priceAssets, ast)
 	}
 
+	priceAssets = withOptionUnderlyings(priceAssets)
+
 	if benchmark != (asset.Asset{}) {
 		priceAssets = append(priceAssets, benchmark)
 	}
@@ -682,7 +808,7 @@ func (eng *Engine) updateAccountPrices(ctx context.Context, acct portfolio.Portf
 		if rfFetchErr == nil {
 			yield := rfDF.Value(eng.riskFreeAssetDGS, data.MetricClose)
 			if !math.IsNaN(yield) && yield > 0 {
-				eng.riskFreeCumulative = portfolio.YieldToCumulative(yield, eng.riskFreeCumulative)
+				eng.riskFreeCumulative = portfolio.YieldToCumulative(yield, eng.riskFreeCumulative, eng.marketCalendar().TradingDaysPerYear())
 			} else if eng.riskFreeCumulative == 0 {
 				eng.riskFreeCumulative = 100.0
 			}
@@ -697,6 +823,12 @@ func (eng *Engine) updateAccountPrices(ctx context.Context, acct portfolio.Portf
 			return fmt.Errorf("engine: price fetch on %v: %w", date, fetchErr)
 		}
 
+		// Settle futures and expire options at the close before
+		// recording the day's equity.
+		acct.SetPrices(priceDF)
+		acct.SettleFutures(date)
+		acct.ExpireOptions(date)
+
 		acct.UpdatePrices(priceDF)
 		acct.UpdateExcursions(priceDF)
 	} else {
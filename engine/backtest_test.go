@@ -31,6 +31,7 @@ import (
 	"github.com/penny-vault/pvbt/engine"
 	"github.com/penny-vault/pvbt/engine/middleware/risk"
 	"github.com/penny-vault/pvbt/portfolio"
+	"github.com/penny-vault/pvbt/tradecron"
 )
 
 // mockAssetProvider implements data.AssetProvider for tests.
@@ -146,6 +147,24 @@ func (s *monthlyStrategy) Compute(ctx context.Context, eng *engine.Engine, fund
 }
 
 // noScheduleStrategy omits calling e.Schedule in Setup.
+// exchangeStrategy declares a single asset so the engine can choose its
+// calendar from the asset's listing exchange.
+type exchangeStrategy struct {
+	Stock asset.Asset
+}
+
+func (s *exchangeStrategy) Name() string { return "exchangeStrategy" }
+
+func (s *exchangeStrategy) Setup(_ *engine.Engine) {}
+
+func (s *exchangeStrategy) Describe() engine.StrategyDescription {
+	return engine.StrategyDescription{Schedule: "@close @monthend"}
+}
+
+func (s *exchangeStrategy) Compute(_ context.Context, _ *engine.Engine, _ portfolio.Portfolio, _ *portfolio.Batch) error {
+	return nil
+}
+
 type noScheduleStrategy struct{}
 
 func (s *noScheduleStrategy) Name() string           { return "noSchedule" }
@@ -686,6 +705,60 @@ var _ = Describe("Backtest", func() {
 			Expect(len(equityCol)).To(BeNumerically(">=", 30),
 				"expected daily equity data, got %d points", len(equityCol))
 		})
+
+		It("records equity every calendar day on the crypto calendar", func() {
+			dataStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			df := makeDailyTestData(dataStart, 400, testAssets, metrics)
+			provider := data.NewTestProvider(metrics, df)
+
+			strategy := &monthlyStrategy{assets: testAssets}
+			eng := engine.New(strategy,
+				engine.WithDataProvider(provider),
+				engine.WithAssetProvider(assetProvider),
+				engine.WithInitialDeposit(100_000.0),
+				engine.WithCalendar(tradecron.Crypto),
+			)
+
+			start := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+			end := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+
+			fund, err := eng.Backtest(context.Background(), start, end)
+			Expect(err).NotTo(HaveOccurred())
+
+			times := fund.PerfData().Times()
+			Expect(times).To(HaveLen(29))
+			Expect(times[2].Weekday()).To(Equal(time.Saturday))
+		})
+
+		It("schedules on the calendar of the strategy assets' exchange", func() {
+			vod := asset.Asset{CompositeFigi: "FIGI-VOD", Ticker: "VOD", PrimaryExchange: asset.ExchangeLSE}
+			londonAssets := []asset.Asset{vod}
+
+			dataStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			df := makeDailyTestData(dataStart, 400, londonAssets, metrics)
+			provider := data.NewTestProvider(metrics, df)
+
+			strategy := &exchangeStrategy{Stock: vod}
+			eng := engine.New(strategy,
+				engine.WithDataProvider(provider),
+				engine.WithAssetProvider(&mockAssetProvider{assets: londonAssets}),
+				engine.WithInitialDeposit(100_000.0),
+			)
+
+			start := time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC)
+			end := time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC)
+
+			fund, err := eng.Backtest(context.Background(), start, end)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Good Friday and Easter Monday are LSE holidays; the close is 16:30 London.
+			var days []string
+			for _, stamp := range fund.PerfData().Times() {
+				days = append(days, stamp.In(tradecron.XLON.Location()).Format("01-02 15:04"))
+			}
+
+			Expect(days).To(Equal([]string{"03-25 16:30", "03-26 16:30", "03-27 16:30", "03-28 16:30", "04-02 16:30", "04-03 16:30"}))
+		})
 	})
 
 	Context("validation", func() {
@@ -1499,6 +1572,64 @@ var _ = Describe("Backtest", func() {
 				"position should be 200 shares after 2-for-1 split")
 		})
 
+		It("reinvests dividends when WithDividendReinvestment is set", func() {
+			testStock := asset.Asset{CompositeFigi: "FIGI-DRIP1", Ticker: "DRIP1"}
+			dripAssets := []asset.Asset{testStock}
+
+			dripMetrics := []data.Metric{
+				data.MetricClose, data.AdjClose, data.Dividend,
+				data.MetricHigh, data.MetricLow, data.SplitFactor, data.Volume,
+			}
+			nDays := 10
+			dataStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			times := make([]time.Time, nDays)
+			for idx := range times {
+				day := dataStart.AddDate(0, 0, idx)
+				times[idx] = time.Date(day.Year(), day.Month(), day.Day(), 16, 0, 0, 0, time.UTC)
+			}
+
+			nMetrics := len(dripMetrics)
+			vals := make([]float64, nDays*nMetrics)
+			for dayIdx := 0; dayIdx < nDays; dayIdx++ {
+				vals[0*nDays+dayIdx] = 100.0       // Close
+				vals[1*nDays+dayIdx] = 100.0       // AdjClose
+				vals[3*nDays+dayIdx] = 102.0       // High
+				vals[4*nDays+dayIdx] = 98.0        // Low
+				vals[5*nDays+dayIdx] = 1.0         // SplitFactor
+				vals[6*nDays+dayIdx] = 1_000_000.0 // Volume
+			}
+			// $1.50 dividend on day 4 (Jan 5, Friday).
+			vals[2*nDays+4] = 1.50
+
+			dripDF, dfErr := data.NewDataFrame(times, dripAssets, dripMetrics, data.Daily,
+				data.SlabToColumns(vals, nMetrics, nDays))
+			Expect(dfErr).NotTo(HaveOccurred())
+
+			eng := engine.New(&buyOnceStrategy{target: testStock, qty: 100},
+				engine.WithDataProvider(data.NewTestProvider(dripMetrics, dripDF)),
+				engine.WithAssetProvider(&mockAssetProvider{assets: dripAssets}),
+				engine.WithInitialDeposit(50_000),
+				engine.WithDividendReinvestment(portfolio.DividendReinvestment{Fractional: true}),
+			)
+
+			fund, err := eng.Backtest(context.Background(),
+				time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC))
+			Expect(err).NotTo(HaveOccurred())
+
+			var reinvested []portfolio.Transaction
+			for _, tx := range fund.Transactions() {
+				if tx.Type == asset.BuyTransaction && tx.Justification == portfolio.DividendReinvestmentJustification {
+					reinvested = append(reinvested, tx)
+				}
+			}
+
+			Expect(reinvested).To(HaveLen(1))
+			Expect(reinvested[0].Qty).To(BeNumerically("~", 1.5, 1e-9))
+			Expect(reinvested[0].Amount).To(BeNumerically("~", -150.0, 1e-9))
+			Expect(fund.Position(testStock)).To(BeNumerically("~", 101.5, 1e-9))
+			Expect(fund.Cash()).To(BeNumerically("~", 40_000.0, 1e-6))
+		})
+
 		It("liquidates delisted positions", func() {
 			testStock := asset.Asset{CompositeFigi: "FIGI-SYNC2", Ticker: "SYNC2"}
 			delistAssets := []asset.Asset{testStock}
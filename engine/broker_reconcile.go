@@ -0,0 +1,306 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/portfolio"
+	"github.com/rs/zerolog"
+)
+
+// ErrBrokerDrift is returned by RunLive (at startup) and ends the live
+// loop (before a step) when the account and the broker disagree beyond
+// tolerance and the reconciliation policy is DriftHalt.
+var ErrBrokerDrift = errors.New("engine: account and broker positions have drifted")
+
+// DriftPolicy selects how RunLive responds when the account's holdings
+// or cash disagree with what the broker reports.
+type DriftPolicy int
+
+const (
+	// DriftWarn logs the drift report and continues unchanged.
+	DriftWarn DriftPolicy = iota
+
+	// DriftHalt stops the live session with ErrBrokerDrift.
+	DriftHalt
+
+	// DriftTrustBroker treats the broker as authoritative and records
+	// adjusting JournalTransactions so the account matches it.
+	DriftTrustBroker
+)
+
+// String returns the policy name as accepted by ParseDriftPolicy.
+func (p DriftPolicy) String() string {
+	switch p {
+	case DriftWarn:
+		return "warn"
+	case DriftHalt:
+		return "halt"
+	case DriftTrustBroker:
+		return "trust-broker"
+	default:
+		return fmt.Sprintf("DriftPolicy(%d)", int(p))
+	}
+}
+
+// ParseDriftPolicy converts "warn", "halt", or "trust-broker" to a
+// DriftPolicy.
+func ParseDriftPolicy(name string) (DriftPolicy, error) {
+	switch name {
+	case "warn":
+		return DriftWarn, nil
+	case "halt":
+		return DriftHalt, nil
+	case "trust-broker":
+		return DriftTrustBroker, nil
+	default:
+		return DriftWarn, fmt.Errorf("engine: unknown drift policy %q (want warn, halt, or trust-broker)", name)
+	}
+}
+
+// ReconcileConfig configures broker position reconciliation in RunLive.
+// Quantity differences at or below QtyTolerance and cash differences at
+// or below CashTolerance are not reported as drift.
+type ReconcileConfig struct {
+	Policy        DriftPolicy
+	QtyTolerance  float64
+	CashTolerance float64
+
+	// OnDrift, when set, receives every report that contains drift,
+	// before the policy is applied.
+	OnDrift func(DriftReport)
+}
+
+// PositionDrift describes one asset whose quantity differs between the
+// account and the broker.
+type PositionDrift struct {
+	Asset      asset.Asset
+	AccountQty float64
+	BrokerQty  float64
+
+	// Delta is BrokerQty - AccountQty.
+	Delta float64
+
+	// Price is the per-share value used to book an adjustment: the
+	// broker's average open price, falling back to its mark.
+	Price float64
+}
+
+// DriftReport is the structured result of comparing the account with the
+// broker at a point in time.
+type DriftReport struct {
+	Time        time.Time
+	Positions   []PositionDrift
+	AccountCash float64
+	BrokerCash  float64
+
+	// CashDelta is BrokerCash - AccountCash, or zero when within tolerance.
+	CashDelta float64
+}
+
+// HasDrift reports whether any position or the cash balance is out of
+// tolerance.
+func (r DriftReport) HasDrift() bool {
+	return len(r.Positions) > 0 || r.CashDelta != 0
+}
+
+// DiffBroker compares account holdings and cash with broker positions
+// and balance. Broker positions are matched to account holdings by
+// CompositeFigi when the broker supplies one, otherwise by ticker.
+// Positions are returned sorted by ticker.
+func DiffBroker(
+	holdings map[asset.Asset]float64,
+	cash float64,
+	positions []broker.Position,
+	balance broker.Balance,
+	cfg ReconcileConfig,
+	at time.Time,
+) DriftReport {
+	report := DriftReport{
+		Time:        at,
+		AccountCash: cash,
+		BrokerCash:  balance.CashBalance,
+	}
+
+	byFigi := make(map[string]asset.Asset, len(holdings))
+	byTicker := make(map[string]asset.Asset, len(holdings))
+
+	for held := range holdings {
+		if held.CompositeFigi != "" {
+			byFigi[held.CompositeFigi] = held
+		}
+
+		byTicker[held.Ticker] = held
+	}
+
+	matched := make(map[asset.Asset]bool, len(holdings))
+
+	for _, position := range positions {
+		held, ok := byFigi[position.Asset.CompositeFigi]
+		if !ok || position.Asset.CompositeFigi == "" {
+			held, ok = byTicker[position.Asset.Ticker]
+		}
+
+		key := position.Asset
+
+		accountQty := 0.0
+		if ok {
+			key = held
+			accountQty = holdings[held]
+			matched[held] = true
+		}
+
+		price := position.AvgOpenPrice
+		if price <= 0 {
+			price = position.MarkPrice
+		}
+
+		if drift := position.Qty - accountQty; math.Abs(drift) > cfg.QtyTolerance {
+			report.Positions = append(report.Positions, PositionDrift{
+				Asset:      key,
+				AccountQty: accountQty,
+				BrokerQty:  position.Qty,
+				Delta:      drift,
+				Price:      price,
+			})
+		}
+	}
+
+	for held, qty := range holdings {
+		if matched[held] || math.Abs(qty) <= cfg.QtyTolerance {
+			continue
+		}
+
+		report.Positions = append(report.Positions, PositionDrift{
+			Asset:      held,
+			AccountQty: qty,
+			Delta:      -qty,
+		})
+	}
+
+	sort.Slice(report.Positions, func(i, j int) bool {
+		return report.Positions[i].Asset.Ticker < report.Positions[j].Asset.Ticker
+	})
+
+	if cashDrift := balance.CashBalance - cash; math.Abs(cashDrift) > cfg.CashTolerance {
+		report.CashDelta = cashDrift
+	}
+
+	return report
+}
+
+// reconcileWithBroker fetches broker positions and balance, diffs them
+// against the account, logs the drift report, and applies the configured
+// policy. It is a no-op when reconciliation is not configured or the
+// broker is a SimulatedBroker, which keeps no positions or balance of
+// its own to compare.
+func (e *Engine) reconcileWithBroker(ctx context.Context, acct portfolio.PortfolioManager, at time.Time) error {
+	if e.reconcile == nil {
+		return nil
+	}
+
+	if _, simulated := e.broker.(*SimulatedBroker); simulated {
+		return nil
+	}
+
+	positions, err := e.broker.Positions(ctx)
+	if err != nil {
+		return fmt.Errorf("engine: reconcile: broker positions: %w", err)
+	}
+
+	balance, err := e.broker.Balance(ctx)
+	if err != nil {
+		return fmt.Errorf("engine: reconcile: broker balance: %w", err)
+	}
+
+	report := DiffBroker(acct.Holdings(), acct.Cash(), positions, balance, *e.reconcile, at)
+	if !report.HasDrift() {
+		return nil
+	}
+
+	logDriftReport(ctx, report, e.reconcile.Policy)
+
+	if e.reconcile.OnDrift != nil {
+		e.reconcile.OnDrift(report)
+	}
+
+	switch e.reconcile.Policy {
+	case DriftHalt:
+		return fmt.Errorf("%w: %d position(s), cash delta %.2f", ErrBrokerDrift, len(report.Positions), report.CashDelta)
+	case DriftTrustBroker:
+		applyDriftJournals(acct, report)
+	}
+
+	return nil
+}
+
+// applyDriftJournals records JournalTransactions that move the account
+// to the broker's quantities and cash. Position journals carry no cash
+// amount; the cash journal absorbs the whole cash difference.
+func applyDriftJournals(acct portfolio.PortfolioManager, report DriftReport) {
+	for _, drift := range report.Positions {
+		acct.Record(portfolio.Transaction{
+			Date:          report.Time,
+			Asset:         drift.Asset,
+			Type:          asset.JournalTransaction,
+			Qty:           drift.Delta,
+			Price:         drift.Price,
+			Justification: fmt.Sprintf("reconcile: broker reports %g shares, account held %g", drift.BrokerQty, drift.AccountQty),
+		})
+	}
+
+	if report.CashDelta != 0 {
+		acct.Record(portfolio.Transaction{
+			Date:          report.Time,
+			Type:          asset.JournalTransaction,
+			Amount:        report.CashDelta,
+			Justification: fmt.Sprintf("reconcile: broker reports cash %.2f, account held %.2f", report.BrokerCash, report.AccountCash),
+		})
+	}
+}
+
+// logDriftReport writes one structured log entry per drifting position
+// plus a summary entry.
+func logDriftReport(ctx context.Context, report DriftReport, policy DriftPolicy) {
+	logger := zerolog.Ctx(ctx)
+
+	for _, drift := range report.Positions {
+		logger.Warn().
+			Str("ticker", drift.Asset.Ticker).
+			Str("figi", drift.Asset.CompositeFigi).
+			Float64("account_qty", drift.AccountQty).
+			Float64("broker_qty", drift.BrokerQty).
+			Float64("delta", drift.Delta).
+			Msg("position drift")
+	}
+
+	logger.Warn().
+		Time("at", report.Time).
+		Int("positions", len(report.Positions)).
+		Float64("account_cash", report.AccountCash).
+		Float64("broker_cash", report.BrokerCash).
+		Float64("cash_delta", report.CashDelta).
+		Stringer("policy", policy).
+		Msg("account drifted from broker")
+}
@@ -0,0 +1,225 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/engine"
+	"github.com/penny-vault/pvbt/portfolio"
+)
+
+// positionBroker is a mockLifecycleBroker that reports fixed positions
+// and balance.
+type positionBroker struct {
+	mockLifecycleBroker
+	positions []broker.Position
+	balance   broker.Balance
+}
+
+func (pb *positionBroker) Positions(_ context.Context) ([]broker.Position, error) {
+	return pb.positions, nil
+}
+
+func (pb *positionBroker) Balance(_ context.Context) (broker.Balance, error) {
+	return pb.balance, nil
+}
+
+var _ = Describe("broker reconciliation", func() {
+	var (
+		spy  asset.Asset
+		tlt  asset.Asset
+		gld  asset.Asset
+		at   time.Time
+		acct *portfolio.Account
+	)
+
+	BeforeEach(func() {
+		spy = asset.Asset{Ticker: "SPY", CompositeFigi: "FIGI-SPY"}
+		tlt = asset.Asset{Ticker: "TLT", CompositeFigi: "FIGI-TLT"}
+		gld = asset.Asset{Ticker: "GLD", CompositeFigi: "FIGI-GLD"}
+		at = time.Date(2026, 1, 7, 14, 30, 0, 0, time.UTC)
+
+		acct = portfolio.New(portfolio.WithCash(10_000, time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)))
+		acct.Record(portfolio.Transaction{
+			Date: time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC), Asset: spy,
+			Type: asset.BuyTransaction, Qty: 10, Price: 500, Amount: -5_000,
+		})
+		acct.Record(portfolio.Transaction{
+			Date: time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC), Asset: tlt,
+			Type: asset.BuyTransaction, Qty: 20, Price: 90, Amount: -1_800,
+		})
+	})
+
+	Describe("ParseDriftPolicy", func() {
+		It("round-trips every policy name", func() {
+			for _, policy := range []engine.DriftPolicy{engine.DriftWarn, engine.DriftHalt, engine.DriftTrustBroker} {
+				parsed, err := engine.ParseDriftPolicy(policy.String())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parsed).To(Equal(policy))
+			}
+		})
+
+		It("rejects unknown names", func() {
+			_, err := engine.ParseDriftPolicy("ignore")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("DiffBroker", func() {
+		It("reports no drift when quantities and cash agree within tolerance", func() {
+			report := engine.DiffBroker(acct.Holdings(), acct.Cash(),
+				[]broker.Position{
+					{Asset: spy, Qty: 10},
+					{Asset: asset.Asset{Ticker: "TLT"}, Qty: 20.0000001},
+				},
+				broker.Balance{CashBalance: 3_200.005},
+				engine.ReconcileConfig{QtyTolerance: 1e-6, CashTolerance: 0.01}, at)
+
+			Expect(report.HasDrift()).To(BeFalse())
+		})
+
+		It("reports quantity mismatches, broker-only and account-only positions, and cash", func() {
+			report := engine.DiffBroker(acct.Holdings(), acct.Cash(),
+				[]broker.Position{
+					{Asset: spy, Qty: 12, AvgOpenPrice: 505},
+					{Asset: gld, Qty: 5, MarkPrice: 190},
+				},
+				broker.Balance{CashBalance: 3_000},
+				engine.ReconcileConfig{QtyTolerance: 1e-6, CashTolerance: 0.01}, at)
+
+			Expect(report.HasDrift()).To(BeTrue())
+			Expect(report.Time).To(Equal(at))
+			Expect(report.Positions).To(HaveLen(3))
+
+			Expect(report.Positions[0].Asset).To(Equal(gld))
+			Expect(report.Positions[0].AccountQty).To(Equal(0.0))
+			Expect(report.Positions[0].Delta).To(Equal(5.0))
+			Expect(report.Positions[0].Price).To(Equal(190.0))
+
+			Expect(report.Positions[1].Asset).To(Equal(spy))
+			Expect(report.Positions[1].Delta).To(Equal(2.0))
+			Expect(report.Positions[1].Price).To(Equal(505.0))
+
+			Expect(report.Positions[2].Asset).To(Equal(tlt))
+			Expect(report.Positions[2].BrokerQty).To(Equal(0.0))
+			Expect(report.Positions[2].Delta).To(Equal(-20.0))
+
+			Expect(report.CashDelta).To(BeNumerically("~", -200, 1e-9))
+		})
+	})
+
+	Describe("policies", func() {
+		var (
+			pb  *positionBroker
+			eng *engine.Engine
+		)
+
+		newEngine := func(cfg engine.ReconcileConfig) {
+			eng = engine.New(&liveStrategy{}, engine.WithBrokerReconciliation(cfg))
+			engine.SetBrokerForTest(eng, pb)
+		}
+
+		BeforeEach(func() {
+			pb = &positionBroker{
+				positions: []broker.Position{
+					{Asset: spy, Qty: 12, AvgOpenPrice: 500},
+					{Asset: tlt, Qty: 20},
+				},
+				balance: broker.Balance{CashBalance: 2_500},
+			}
+		})
+
+		It("halts with ErrBrokerDrift and leaves the account unchanged", func() {
+			var reports []engine.DriftReport
+
+			newEngine(engine.ReconcileConfig{
+				Policy:       engine.DriftHalt,
+				QtyTolerance: 1e-6,
+				OnDrift:      func(report engine.DriftReport) { reports = append(reports, report) },
+			})
+
+			err := engine.ReconcileWithBrokerForTest(eng, context.Background(), acct, at)
+			Expect(err).To(MatchError(engine.ErrBrokerDrift))
+			Expect(reports).To(HaveLen(1))
+			Expect(acct.Position(spy)).To(Equal(10.0))
+			Expect(acct.Cash()).To(Equal(3_200.0))
+		})
+
+		It("warns without changing the account", func() {
+			var reports []engine.DriftReport
+
+			newEngine(engine.ReconcileConfig{
+				Policy:       engine.DriftWarn,
+				QtyTolerance: 1e-6,
+				OnDrift:      func(report engine.DriftReport) { reports = append(reports, report) },
+			})
+
+			Expect(engine.ReconcileWithBrokerForTest(eng, context.Background(), acct, at)).To(Succeed())
+			Expect(reports).To(HaveLen(1))
+			Expect(acct.Position(spy)).To(Equal(10.0))
+			Expect(acct.Cash()).To(Equal(3_200.0))
+		})
+
+		It("journals the account to the broker's positions and cash when trusting the broker", func() {
+			newEngine(engine.ReconcileConfig{Policy: engine.DriftTrustBroker, QtyTolerance: 1e-6, CashTolerance: 0.01})
+
+			Expect(engine.ReconcileWithBrokerForTest(eng, context.Background(), acct, at)).To(Succeed())
+			Expect(acct.Position(spy)).To(Equal(12.0))
+			Expect(acct.Position(tlt)).To(Equal(20.0))
+			Expect(acct.Cash()).To(Equal(2_500.0))
+
+			var journals int
+
+			for _, txn := range acct.Transactions() {
+				if txn.Type == asset.JournalTransaction {
+					journals++
+				}
+			}
+
+			Expect(journals).To(Equal(2))
+
+			report := engine.DiffBroker(acct.Holdings(), acct.Cash(), pb.positions, pb.balance,
+				engine.ReconcileConfig{QtyTolerance: 1e-6, CashTolerance: 0.01}, at)
+			Expect(report.HasDrift()).To(BeFalse())
+		})
+
+		It("skips reconciliation against a simulated broker", func() {
+			newEngine(engine.ReconcileConfig{Policy: engine.DriftTrustBroker, QtyTolerance: 1e-6, CashTolerance: 0.01})
+
+			// The simulated broker reports no positions; trusting it
+			// would journal the account flat.
+			engine.SetBrokerForTest(eng, engine.NewSimulatedBroker())
+
+			Expect(engine.ReconcileWithBrokerForTest(eng, context.Background(), acct, at)).To(Succeed())
+			Expect(acct.Position(spy)).To(Equal(10.0))
+		})
+
+		It("does nothing when reconciliation is not configured", func() {
+			eng = engine.New(&liveStrategy{})
+			engine.SetBrokerForTest(eng, pb)
+
+			Expect(engine.ReconcileWithBrokerForTest(eng, context.Background(), acct, at)).To(Succeed())
+			Expect(acct.Position(spy)).To(Equal(10.0))
+		})
+	})
+})
@@ -0,0 +1,287 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/portfolio"
+	"github.com/rs/zerolog"
+)
+
+// DefaultCPISeries is the price index used by InflationIndexedWithdrawal
+// when no series is given: the FRED CPI for all urban consumers.
+const DefaultCPISeries = "FRED:CPIAUCSL"
+
+// CashFlow is an external deposit or withdrawal that the engine posts to
+// the account during a backtest, either on every date of a tradecron
+// schedule or once. Build one with Contribution, Withdrawal,
+// InflationIndexedWithdrawal, PercentOfValueWithdrawal, OneTimeDeposit,
+// or OneTimeWithdrawal and pass it to WithCashFlows.
+type CashFlow struct {
+	schedule  string    // tradecron spec; empty for a one-off flow
+	date      time.Time // date of a one-off flow
+	amount    float64   // dollars per flow
+	fraction  float64   // fraction of portfolio value per flow; used when percent is set
+	percent   bool      // withdraw fraction of portfolio value rather than amount
+	withdraw  bool      // withdrawal rather than deposit
+	cpiTicker string    // price index that scales amount; empty when not indexed
+	label     string    // recorded as the transaction's Justification
+}
+
+// Contribution deposits amount dollars on every date of schedule, for
+// example Contribution("@monthbegin", 500) for a fixed monthly
+// contribution.
+func Contribution(schedule string, amount float64) CashFlow {
+	return CashFlow{schedule: schedule, amount: amount, label: "scheduled contribution"}
+}
+
+// Withdrawal withdraws amount dollars on every date of schedule.
+func Withdrawal(schedule string, amount float64) CashFlow {
+	return CashFlow{schedule: schedule, amount: amount, withdraw: true, label: "scheduled withdrawal"}
+}
+
+// InflationIndexedWithdrawal withdraws amount dollars, expressed in
+// backtest-start dollars, on every date of schedule. Each withdrawal is
+// scaled by the ratio of the price index cpiTicker on the withdrawal date
+// to its value at the start of the backtest, using the most recent
+// observation on or before each date. An empty cpiTicker uses
+// DefaultCPISeries.
+func InflationIndexedWithdrawal(schedule string, amount float64, cpiTicker string) CashFlow {
+	if cpiTicker == "" {
+		cpiTicker = DefaultCPISeries
+	}
+
+	return CashFlow{
+		schedule:  schedule,
+		amount:    amount,
+		withdraw:  true,
+		cpiTicker: cpiTicker,
+		label:     "inflation-indexed withdrawal",
+	}
+}
+
+// PercentOfValueWithdrawal withdraws fraction of the portfolio's value on
+// every date of schedule. PercentOfValueWithdrawal("@monthbegin", 0.04/12)
+// withdraws roughly 4% a year.
+func PercentOfValueWithdrawal(schedule string, fraction float64) CashFlow {
+	return CashFlow{schedule: schedule, fraction: fraction, percent: true, withdraw: true, label: "percent-of-value withdrawal"}
+}
+
+// OneTimeDeposit deposits amount dollars once, on the first trading day
+// on or after date.
+func OneTimeDeposit(date time.Time, amount float64) CashFlow {
+	return CashFlow{date: date, amount: amount, label: "one-time deposit"}
+}
+
+// OneTimeWithdrawal withdraws amount dollars once, on the first trading
+// day on or after date.
+func OneTimeWithdrawal(date time.Time, amount float64) CashFlow {
+	return CashFlow{date: date, amount: amount, withdraw: true, label: "one-time withdrawal"}
+}
+
+// validate reports a CashFlow whose amount or fraction cannot be applied.
+func (cf CashFlow) validate() error {
+	switch {
+	case cf.percent:
+		if !(cf.fraction > 0 && cf.fraction <= 1) {
+			return fmt.Errorf("%s fraction %g must be greater than 0 and at most 1", cf.label, cf.fraction)
+		}
+	case !(cf.amount > 0) || math.IsInf(cf.amount, 1):
+		return fmt.Errorf("%s amount %g must be a positive number of dollars", cf.label, cf.amount)
+	}
+
+	if cf.schedule == "" && cf.date.IsZero() {
+		return fmt.Errorf("%s has no date", cf.label)
+	}
+
+	return nil
+}
+
+// indexSeries is a price index used to scale inflation-indexed flows.
+type indexSeries struct {
+	times  []time.Time
+	values []float64
+	base   float64 // value at the start of the backtest
+}
+
+// valueAt returns the most recent observation on or before date, or NaN
+// when the series starts after date.
+func (is *indexSeries) valueAt(date time.Time) float64 {
+	idx := sort.Search(len(is.times), func(ii int) bool { return is.times[ii].After(date) })
+	for idx > 0 {
+		idx--
+		if !math.IsNaN(is.values[idx]) {
+			return is.values[idx]
+		}
+	}
+
+	return math.NaN()
+}
+
+// cashFlowPlan is the resolved form of the engine's cash flows for one
+// backtest.
+type cashFlowPlan struct {
+	due     map[string][]CashFlow   // trading date (2006-01-02) -> flows posted that day
+	indexes map[string]*indexSeries // price index ticker -> observations
+}
+
+// planCashFlows expands each configured cash flow into the trading dates
+// it is posted on between start and end and loads the price indexes the
+// inflation-indexed flows need.
+func (e *Engine) planCashFlows(ctx context.Context, start, end time.Time) error {
+	e.cashFlowPlan = nil
+
+	if len(e.cashFlows) == 0 {
+		return nil
+	}
+
+	plan := &cashFlowPlan{
+		due:     make(map[string][]CashFlow),
+		indexes: make(map[string]*indexSeries),
+	}
+
+	tradingDays, err := e.newSchedule("@close * * *")
+	if err != nil {
+		return fmt.Errorf("engine: cash flows: creating trading-day schedule: %w", err)
+	}
+
+	for _, flow := range e.cashFlows {
+		if err := flow.validate(); err != nil {
+			return fmt.Errorf("engine: cash flows: %w", err)
+		}
+
+		if flow.schedule == "" {
+			postOn := tradingDays.Next(flow.date.Add(-time.Nanosecond))
+			if postOn.Before(start) || postOn.After(end) {
+				continue
+			}
+
+			key := postOn.Format("2006-01-02")
+			plan.due[key] = append(plan.due[key], flow)
+
+			continue
+		}
+
+		schedule, err := e.newSchedule(flow.schedule)
+		if err != nil {
+			return fmt.Errorf("engine: cash flows: %s schedule %q: %w", flow.label, flow.schedule, err)
+		}
+
+		for cur := schedule.Next(start.Add(-time.Nanosecond)); !cur.After(end); cur = schedule.Next(cur.Add(time.Nanosecond)) {
+			key := cur.Format("2006-01-02")
+			plan.due[key] = append(plan.due[key], flow)
+		}
+
+		if flow.cpiTicker != "" && plan.indexes[flow.cpiTicker] == nil {
+			series, err := e.loadIndexSeries(ctx, flow.cpiTicker, start, end)
+			if err != nil {
+				return fmt.Errorf("engine: cash flows: %w", err)
+			}
+
+			plan.indexes[flow.cpiTicker] = series
+		}
+	}
+
+	e.cashFlowPlan = plan
+
+	return nil
+}
+
+// loadIndexSeries fetches the price index ticker from a year before start
+// through end, so the base value is available even for a monthly series.
+func (e *Engine) loadIndexSeries(ctx context.Context, ticker string, start, end time.Time) (*indexSeries, error) {
+	indexAsset, err := e.assetProvider.LookupAsset(ctx, ticker)
+	if err != nil {
+		return nil, fmt.Errorf("look up price index %s: %w", ticker, err)
+	}
+
+	df, err := e.fetchRange(ctx, []asset.Asset{indexAsset}, []data.Metric{data.MetricClose}, start.AddDate(-1, 0, 0), end)
+	if err != nil {
+		return nil, fmt.Errorf("fetch price index %s: %w", ticker, err)
+	}
+
+	series := &indexSeries{
+		times:  append([]time.Time(nil), df.Times()...),
+		values: append([]float64(nil), df.Column(indexAsset, data.MetricClose)...),
+	}
+
+	series.base = series.valueAt(start)
+	if math.IsNaN(series.base) || series.base <= 0 {
+		return nil, fmt.Errorf("price index %s has no observation on or before %s", ticker, start.Format("2006-01-02"))
+	}
+
+	return series, nil
+}
+
+// applyCashFlows posts the cash flows due on date to acct as deposit and
+// withdrawal transactions. Withdrawals are capped at the portfolio's
+// value; when cash is short the balance goes negative until the strategy
+// sells to cover it.
+func (e *Engine) applyCashFlows(ctx context.Context, acct portfolio.PortfolioManager, date time.Time) error {
+	if e.cashFlowPlan == nil {
+		return nil
+	}
+
+	for _, flow := range e.cashFlowPlan.due[date.Format("2006-01-02")] {
+		amount := flow.amount
+
+		switch {
+		case flow.percent:
+			amount = flow.fraction * acct.Value()
+		case flow.cpiTicker != "":
+			series := e.cashFlowPlan.indexes[flow.cpiTicker]
+
+			level := series.valueAt(date)
+			if math.IsNaN(level) {
+				return fmt.Errorf("engine: cash flows: price index %s has no value on %v", flow.cpiTicker, date)
+			}
+
+			amount *= level / series.base
+		}
+
+		txType := asset.DepositTransaction
+
+		if flow.withdraw {
+			txType = asset.WithdrawalTransaction
+			amount = -math.Min(amount, math.Max(acct.Value(), 0))
+		}
+
+		if amount == 0 {
+			continue
+		}
+
+		acct.Record(portfolio.Transaction{
+			Date:          date,
+			Type:          txType,
+			Amount:        amount,
+			Justification: flow.label,
+		})
+
+		zerolog.Ctx(ctx).Debug().
+			Str("flow", flow.label).
+			Float64("amount", amount).
+			Msg("posted cash flow")
+	}
+
+	return nil
+}
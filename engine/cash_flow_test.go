@@ -0,0 +1,204 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/engine"
+	"github.com/penny-vault/pvbt/portfolio"
+)
+
+// holdCashStrategy never trades, so the account's value changes only
+// through external cash flows.
+type holdCashStrategy struct{}
+
+func (s *holdCashStrategy) Name() string           { return "hold-cash" }
+func (s *holdCashStrategy) Setup(_ *engine.Engine) {}
+func (s *holdCashStrategy) Describe() engine.StrategyDescription {
+	return engine.StrategyDescription{Schedule: "@monthend"}
+}
+
+func (s *holdCashStrategy) Compute(_ context.Context, _ *engine.Engine, _ portfolio.Portfolio, _ *portfolio.Batch) error {
+	return nil
+}
+
+// externalFlows returns the deposits and withdrawals recorded after the
+// initial deposit.
+func externalFlows(fund portfolio.Portfolio) []portfolio.Transaction {
+	var flows []portfolio.Transaction
+
+	for _, txn := range fund.Transactions() {
+		if txn.Justification == "" {
+			continue
+		}
+
+		if txn.Type == asset.DepositTransaction || txn.Type == asset.WithdrawalTransaction {
+			flows = append(flows, txn)
+		}
+	}
+
+	return flows
+}
+
+var _ = Describe("Cash flows", func() {
+	var (
+		aapl          asset.Asset
+		msft          asset.Asset
+		cpi           asset.Asset
+		assetProvider *mockAssetProvider
+		metrics       []data.Metric
+		df            *data.DataFrame
+		start         time.Time
+		end           time.Time
+	)
+
+	BeforeEach(func() {
+		aapl = asset.Asset{CompositeFigi: "FIGI-AAPL", Ticker: "AAPL"}
+		msft = asset.Asset{CompositeFigi: "FIGI-MSFT", Ticker: "MSFT"}
+		cpi = asset.NewFREDAsset(engine.DefaultCPISeries)
+		assetProvider = &mockAssetProvider{assets: []asset.Asset{aapl, msft, cpi}}
+		metrics = []data.Metric{data.MetricClose, data.AdjClose, data.Dividend, data.MetricHigh, data.MetricLow, data.SplitFactor, data.Volume}
+		df = makeDailyTestData(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 400, []asset.Asset{aapl, msft, cpi}, metrics)
+		start = time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+		end = time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC)
+	})
+
+	runBacktest := func(strategy engine.Strategy, flows ...engine.CashFlow) (portfolio.Portfolio, error) {
+		eng := engine.New(strategy,
+			engine.WithDataProvider(data.NewTestProvider(metrics, df)),
+			engine.WithAssetProvider(assetProvider),
+			engine.WithInitialDeposit(100_000),
+			engine.WithCashFlows(flows...),
+		)
+
+		return eng.Backtest(context.Background(), start, end)
+	}
+
+	It("posts a fixed contribution on every scheduled date", func() {
+		fund, err := runBacktest(&holdCashStrategy{}, engine.Contribution("@monthbegin", 1_000))
+		Expect(err).NotTo(HaveOccurred())
+
+		flows := externalFlows(fund)
+		Expect(flows).To(HaveLen(3))
+
+		for _, flow := range flows {
+			Expect(flow.Type).To(Equal(asset.DepositTransaction))
+			Expect(flow.Amount).To(Equal(1_000.0))
+			Expect(flow.Justification).To(Equal("scheduled contribution"))
+		}
+
+		Expect(fund.Cash()).To(Equal(103_000.0))
+	})
+
+	It("excludes contributions from the time- and money-weighted returns", func() {
+		fund, err := runBacktest(&holdCashStrategy{}, engine.Contribution("@monthbegin", 10_000))
+		Expect(err).NotTo(HaveOccurred())
+
+		twrr, err := fund.PerformanceMetric(portfolio.TWRR).Value()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(twrr).To(BeNumerically("~", 0, 1e-9))
+
+		mwrr, err := fund.PerformanceMetric(portfolio.MWRR).Value()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mwrr).To(BeNumerically("~", 0, 1e-6))
+	})
+
+	It("withdraws a fraction of portfolio value", func() {
+		fund, err := runBacktest(&holdCashStrategy{}, engine.PercentOfValueWithdrawal("@monthbegin", 0.01))
+		Expect(err).NotTo(HaveOccurred())
+
+		flows := externalFlows(fund)
+		Expect(flows).To(HaveLen(3))
+		Expect(flows[0].Type).To(Equal(asset.WithdrawalTransaction))
+		Expect(flows[0].Amount).To(BeNumerically("~", -1_000, 1e-9))
+		Expect(flows[1].Amount).To(BeNumerically("~", -990, 1e-9))
+		Expect(flows[2].Amount).To(BeNumerically("~", -980.1, 1e-9))
+	})
+
+	It("indexes withdrawals to the CPI series", func() {
+		fund, err := runBacktest(&holdCashStrategy{},
+			engine.InflationIndexedWithdrawal("@monthbegin", 1_000, ""))
+		Expect(err).NotTo(HaveOccurred())
+
+		cpiAt := func(date time.Time) float64 {
+			level := 0.0
+
+			for idx, ts := range df.Times() {
+				if ts.After(date) {
+					break
+				}
+
+				level = df.Column(cpi, data.MetricClose)[idx]
+			}
+
+			return level
+		}
+
+		flows := externalFlows(fund)
+		Expect(flows).To(HaveLen(3))
+
+		for _, flow := range flows {
+			Expect(flow.Amount).To(BeNumerically("~", -1_000*cpiAt(flow.Date)/cpiAt(start), 1e-6))
+		}
+
+		Expect(flows[2].Amount).To(BeNumerically("<", flows[0].Amount))
+	})
+
+	It("posts one-off flows on the next trading day", func() {
+		// 2024-03-16 is a Saturday.
+		saturday := time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC)
+
+		fund, err := runBacktest(&holdCashStrategy{},
+			engine.OneTimeDeposit(saturday, 2_500),
+			engine.OneTimeWithdrawal(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), 500))
+		Expect(err).NotTo(HaveOccurred())
+
+		flows := externalFlows(fund)
+		Expect(flows).To(HaveLen(2))
+		Expect(flows[0].Date.Format("2006-01-02")).To(Equal("2024-03-18"))
+		Expect(flows[0].Amount).To(Equal(2_500.0))
+		Expect(flows[1].Type).To(Equal(asset.WithdrawalTransaction))
+		Expect(flows[1].Amount).To(Equal(-500.0))
+		Expect(fund.Cash()).To(Equal(102_000.0))
+	})
+
+	It("never withdraws more than the portfolio is worth", func() {
+		fund, err := runBacktest(&holdCashStrategy{}, engine.Withdrawal("@monthbegin", 60_000))
+		Expect(err).NotTo(HaveOccurred())
+
+		flows := externalFlows(fund)
+		Expect(flows).To(HaveLen(2))
+		Expect(flows[1].Amount).To(Equal(-40_000.0))
+		Expect(fund.Cash()).To(Equal(0.0))
+	})
+
+	It("rejects a flow with a non-positive amount", func() {
+		_, err := runBacktest(&holdCashStrategy{}, engine.Withdrawal("@monthbegin", -100))
+		Expect(err).To(MatchError(ContainSubstring("must be a positive number of dollars")))
+	})
+
+	It("rejects an invalid schedule", func() {
+		_, err := runBacktest(&holdCashStrategy{}, engine.Contribution("not a schedule", 100))
+		Expect(err).To(MatchError(ContainSubstring("scheduled contribution schedule")))
+	})
+})
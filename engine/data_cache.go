@@ -46,6 +46,20 @@ type colCacheKey struct {
 type colCacheEntry struct {
 	times  []time.Time
 	values []float64
+
+	// sources holds, per cell, the index into the metric's provider chain
+	// of the provider that supplied it. Nil means every cell came from
+	// the first provider in the chain.
+	sources []uint8
+}
+
+// source returns the provider chain index of cell idx.
+func (c *colCacheEntry) source(idx int) int {
+	if c.sources == nil {
+		return 0
+	}
+
+	return int(c.sources[idx])
 }
 
 type dataCache struct {
@@ -117,5 +131,5 @@ func estimateEntryBytes(e *colCacheEntry) int64 {
 		return 0
 	}
 
-	return int64(len(e.values)*8 + len(e.times)*24)
+	return int64(len(e.values)*8 + len(e.times)*24 + len(e.sources))
 }
@@ -0,0 +1,202 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/penny-vault/pvbt/data"
+	"github.com/rs/zerolog"
+)
+
+// ErrFlaggedData is returned by Fetch and FetchAt when the data quality
+// policy is DataQualityFail and the returned frame includes cells a
+// provider audit flagged.
+var ErrFlaggedData = errors.New("engine: strategy consumed flagged data")
+
+// DataQualityPolicy selects how Fetch and FetchAt respond when the data
+// they return to a strategy includes cells flagged by a data audit.
+type DataQualityPolicy int
+
+const (
+	// DataQualityOff does not audit provider data.
+	DataQualityOff DataQualityPolicy = iota
+
+	// DataQualityWarn logs each flagged anomaly the first time a strategy
+	// reads it and returns the data unchanged.
+	DataQualityWarn
+
+	// DataQualityFail returns ErrFlaggedData instead of the data.
+	DataQualityFail
+)
+
+// String returns the policy name as accepted by ParseDataQualityPolicy.
+func (p DataQualityPolicy) String() string {
+	switch p {
+	case DataQualityOff:
+		return "off"
+	case DataQualityWarn:
+		return "warn"
+	case DataQualityFail:
+		return "fail"
+	default:
+		return fmt.Sprintf("DataQualityPolicy(%d)", int(p))
+	}
+}
+
+// ParseDataQualityPolicy converts "off", "warn", or "fail" to a
+// DataQualityPolicy.
+func ParseDataQualityPolicy(name string) (DataQualityPolicy, error) {
+	switch name {
+	case "off":
+		return DataQualityOff, nil
+	case "warn":
+		return DataQualityWarn, nil
+	case "fail":
+		return DataQualityFail, nil
+	default:
+		return DataQualityOff, fmt.Errorf("engine: unknown data quality policy %q (want off, warn, or fail)", name)
+	}
+}
+
+// auditRouting wraps every provider in the routing table that does not
+// already report anomalies in a data.AuditingProvider. Only the routing
+// table is changed, so optional provider interfaces found on e.providers
+// keep working.
+func (e *Engine) auditRouting() {
+	if e.dataQuality == DataQualityOff {
+		return
+	}
+
+	wrapped := make(map[data.BatchProvider]data.BatchProvider)
+	e.auditedProviders = make(map[data.BatchProvider]data.BatchProvider)
+
+	for metric, chain := range e.metricProvider {
+		audited := make([]data.BatchProvider, len(chain))
+
+		for idx, provider := range chain {
+			if _, ok := provider.(data.AnomalyReporter); ok {
+				audited[idx] = provider
+				continue
+			}
+
+			auditor, ok := wrapped[provider]
+			if !ok {
+				auditor = data.NewAuditingProvider(provider, e.auditConfig)
+				wrapped[provider] = auditor
+				e.auditedProviders[auditor] = provider
+			}
+
+			audited[idx] = auditor
+		}
+
+		e.metricProvider[metric] = audited
+	}
+}
+
+// anomalyID identifies an anomaly for warn-once bookkeeping.
+type anomalyID struct {
+	figi   string
+	metric data.Metric
+	kind   data.AnomalyKind
+	date   int64
+}
+
+// checkDataQuality applies the data quality policy to a frame about to
+// be returned to the strategy. An anomaly counts when it overlaps the
+// frame, concerns one of its metrics, and the provider that reported it
+// supplied the cell rather than a higher-precedence provider.
+func (e *Engine) checkDataQuality(ctx context.Context, df *data.DataFrame) error {
+	if e.dataQuality == DataQualityOff || df.Len() == 0 {
+		return nil
+	}
+
+	start, end := df.Start(), df.End()
+	metrics := df.MetricList()
+
+	var flagged []data.Anomaly
+
+	for _, metric := range metrics {
+		for _, provider := range e.metricProvider[metric] {
+			reporter, ok := provider.(data.AnomalyReporter)
+			if !ok {
+				continue
+			}
+
+			for _, assetItem := range df.AssetList() {
+				for _, anomaly := range reporter.AnomaliesFor(assetItem, start, end) {
+					if anomaly.Metric != metric {
+						continue
+					}
+
+					if source := e.provenance(assetItem, metric, anomaly.Date); source != nil && source != provider {
+						continue
+					}
+
+					flagged = append(flagged, anomaly)
+				}
+			}
+		}
+	}
+
+	if len(flagged) == 0 {
+		return nil
+	}
+
+	if e.dataQuality == DataQualityFail {
+		if len(flagged) == 1 {
+			return fmt.Errorf("%w: %s", ErrFlaggedData, describeAnomaly(flagged[0]))
+		}
+
+		return fmt.Errorf("%w: %s and %d more", ErrFlaggedData, describeAnomaly(flagged[0]), len(flagged)-1)
+	}
+
+	if e.warnedAnomalies == nil {
+		e.warnedAnomalies = make(map[anomalyID]struct{})
+	}
+
+	logger := zerolog.Ctx(ctx)
+
+	for _, anomaly := range flagged {
+		id := anomalyID{figi: anomaly.Asset.CompositeFigi, metric: anomaly.Metric, kind: anomaly.Kind, date: anomaly.Date.Unix()}
+		if _, seen := e.warnedAnomalies[id]; seen {
+			continue
+		}
+
+		e.warnedAnomalies[id] = struct{}{}
+
+		logger.Warn().
+			Str("ticker", anomaly.Asset.Ticker).
+			Str("figi", anomaly.Asset.CompositeFigi).
+			Str("metric", string(anomaly.Metric)).
+			Str("kind", string(anomaly.Kind)).
+			Time("date", anomaly.Date).
+			Time("end", anomaly.End).
+			Str("detail", anomaly.Detail).
+			Msg("strategy consumed flagged data")
+	}
+
+	return nil
+}
+
+// describeAnomaly renders an anomaly for an error message.
+func describeAnomaly(anomaly data.Anomaly) string {
+	return fmt.Sprintf("%s %s %s on %s (%s)", anomaly.Asset.Ticker, anomaly.Metric, anomaly.Kind,
+		anomaly.Date.Format(time.DateOnly), anomaly.Detail)
+}
@@ -0,0 +1,176 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/engine"
+	"github.com/penny-vault/pvbt/portfolio"
+)
+
+var _ = Describe("Data quality policy", func() {
+	var (
+		aapl     asset.Asset
+		metrics  []data.Metric
+		vendorDF *data.DataFrame
+		vendor   *data.TestProvider
+		spikeAt  time.Time
+	)
+
+	day := func(d int) time.Time {
+		return time.Date(2024, 1, d, 16, 0, 0, 0, time.UTC)
+	}
+
+	BeforeEach(func() {
+		aapl = asset.Asset{CompositeFigi: "FIGI-AAPL", Ticker: "AAPL"}
+		metrics = []data.Metric{data.MetricClose}
+
+		// Daily closes from Jan 1 to Jan 31 with a 100x bad print on Jan 10.
+		times := make([]time.Time, 31)
+		closes := make([]float64, 31)
+
+		for idx := range times {
+			times[idx] = day(idx + 1)
+			closes[idx] = 100 + float64(idx)
+		}
+
+		closes[9] *= 100
+		spikeAt = day(10)
+
+		var err error
+
+		vendorDF, err = data.NewDataFrame(times, []asset.Asset{aapl}, metrics, data.Daily, [][]float64{closes})
+		Expect(err).NotTo(HaveOccurred())
+
+		vendor = data.NewTestProvider(metrics, vendorDF)
+	})
+
+	run := func(lookback portfolio.Period, opts ...engine.Option) (*engine.Engine, *fetchStrategy, error) {
+		strategy := &fetchStrategy{
+			lookback:  lookback,
+			metrics:   metrics,
+			assets:    []asset.Asset{aapl},
+			firstOnly: true,
+		}
+
+		opts = append(opts,
+			engine.WithAssetProvider(&mockAssetProvider{assets: []asset.Asset{aapl}}),
+			engine.WithInitialDeposit(100_000.0),
+		)
+
+		eng := engine.New(strategy, opts...)
+
+		_, err := eng.Backtest(context.Background(),
+			time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 19, 23, 0, 0, 0, time.UTC))
+
+		return eng, strategy, err
+	}
+
+	It("fails a fetch that includes flagged data under DataQualityFail", func() {
+		_, strategy, err := run(portfolio.Days(15),
+			engine.WithDataProvider(vendor),
+			engine.WithDataQualityPolicy(engine.DataQualityFail, data.AuditConfig{}),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strategy.fetchErr).To(MatchError(engine.ErrFlaggedData))
+		Expect(strategy.fetchErr.Error()).To(ContainSubstring("AAPL Close spike on 2024-01-10"))
+	})
+
+	It("allows a fetch whose window avoids the flagged cells", func() {
+		_, strategy, err := run(portfolio.Days(5),
+			engine.WithDataProvider(vendor),
+			engine.WithDataQualityPolicy(engine.DataQualityFail, data.AuditConfig{}),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strategy.fetchErr).NotTo(HaveOccurred())
+	})
+
+	It("returns the data under DataQualityWarn", func() {
+		_, strategy, err := run(portfolio.Days(15),
+			engine.WithDataProvider(vendor),
+			engine.WithDataQualityPolicy(engine.DataQualityWarn, data.AuditConfig{}),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strategy.fetchErr).NotTo(HaveOccurred())
+		Expect(strategy.fetched.Len()).To(BeNumerically(">", 0))
+	})
+
+	It("ignores flagged cells a higher-precedence provider replaced", func() {
+		patchDF, err := data.NewDataFrame([]time.Time{spikeAt, day(11)}, []asset.Asset{aapl}, metrics, data.Daily,
+			[][]float64{{109, 110}})
+		Expect(err).NotTo(HaveOccurred())
+
+		patch := data.NewTestProvider(metrics, patchDF)
+
+		eng, strategy, err := run(portfolio.Days(15),
+			engine.WithProviderPrecedence(patch, vendor),
+			engine.WithDataQualityPolicy(engine.DataQualityFail, data.AuditConfig{}),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strategy.fetchErr).NotTo(HaveOccurred())
+
+		Expect(eng.Provenance(aapl, data.MetricClose, spikeAt)).To(BeIdenticalTo(patch))
+		Expect(eng.Provenance(aapl, data.MetricClose, day(12))).To(BeIdenticalTo(vendor))
+	})
+
+	It("audits data the disk cache serves in place of a fetch", func() {
+		dir := GinkgoT().TempDir()
+
+		runCached := func() (*keyedCountingProvider, *fetchStrategy) {
+			// A fresh cache on the same directory stands in for a new process.
+			cache, err := engine.NewDiskCache(dir, engine.DiskCacheConfig{})
+			Expect(err).NotTo(HaveOccurred())
+
+			keyed := newKeyedCountingProvider("vendor-db", metrics, vendorDF)
+
+			_, strategy, err := run(portfolio.Days(15),
+				engine.WithDataProvider(keyed),
+				engine.WithDiskCache(cache),
+				engine.WithDataQualityPolicy(engine.DataQualityFail, data.AuditConfig{}),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			return keyed, strategy
+		}
+
+		first, strategy := runCached()
+		Expect(first.fetches.Load()).To(BeNumerically(">", 0))
+		Expect(strategy.fetchErr).To(MatchError(engine.ErrFlaggedData))
+
+		second, strategy := runCached()
+		Expect(second.fetches.Load()).To(BeZero())
+		Expect(strategy.fetchErr).To(MatchError(engine.ErrFlaggedData))
+	})
+
+	It("parses policy names", func() {
+		for _, policy := range []engine.DataQualityPolicy{engine.DataQualityOff, engine.DataQualityWarn, engine.DataQualityFail} {
+			parsed, err := engine.ParseDataQualityPolicy(policy.String())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed).To(Equal(policy))
+		}
+
+		_, err := engine.ParseDataQualityPolicy("strict")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,228 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
+)
+
+// fetchDerived serves a request that includes derived fundamentals
+// (data.TTM, data.YoYGrowth, data.PerShare). The stored inputs are
+// fetched alongside the plain metrics so the result has the same time
+// axis as a request for the underlying fundamentals. Derived values are
+// then evaluated from quarterly filings with event_date <= each
+// timestamp. A value can only change when a quarterly filing becomes
+// public. Under a quarterly dimension that is exactly when the asset's
+// forward-filled inputs or FundamentalsDateKey change, so only those
+// rows are evaluated and the rest are filled forward. Annual and
+// trailing dimensions do not see every quarterly filing, so rows that
+// pass a quarter's filing deadline are evaluated as well.
+func (e *Engine) fetchDerived(ctx context.Context, assets []asset.Asset, metrics []data.Metric, rangeStart, rangeEnd time.Time) (*data.DataFrame, error) {
+	var derived, plain, inputs []data.Metric
+
+	for _, metric := range metrics {
+		if !data.IsDerived(metric) {
+			if !slices.Contains(plain, metric) {
+				plain = append(plain, metric)
+			}
+
+			continue
+		}
+
+		metricInputs, err := data.DerivedInputs(metric)
+		if err != nil {
+			return nil, fmt.Errorf("engine: %w", err)
+		}
+
+		if !slices.Contains(derived, metric) {
+			derived = append(derived, metric)
+		}
+
+		for _, input := range metricInputs {
+			if !slices.Contains(inputs, input) {
+				inputs = append(inputs, input)
+			}
+		}
+	}
+
+	// The filing's date key marks a new quarter even when the input
+	// values happen to repeat.
+	watched := slices.Clone(inputs)
+	if _, ok := e.metricProvider[data.FundamentalsDateKey]; ok {
+		watched = append(watched, data.FundamentalsDateKey)
+	}
+
+	fetchMetrics := slices.Clone(plain)
+	for _, metric := range watched {
+		if !slices.Contains(fetchMetrics, metric) {
+			fetchMetrics = append(fetchMetrics, metric)
+		}
+	}
+
+	assembled, err := e.fetchRange(ctx, assets, fetchMetrics, rangeStart, rangeEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	times := assembled.Times()
+	if len(times) == 0 {
+		return assembled.Metrics(metrics...), nil
+	}
+
+	deadlines := e.filingDeadlineRows(times)
+
+	// changed[row] lists the assets whose filings changed at that row.
+	changed := make(map[int][]asset.Asset)
+
+	for _, assetItem := range assets {
+		changed[0] = append(changed[0], assetItem)
+
+		for row := 1; row < len(times); row++ {
+			if deadlines[row] {
+				changed[row] = append(changed[row], assetItem)
+				continue
+			}
+
+			for _, metric := range watched {
+				col := assembled.Column(assetItem, metric)
+				if col != nil && !sameValue(col[row-1], col[row]) {
+					changed[row] = append(changed[row], assetItem)
+					break
+				}
+			}
+		}
+	}
+
+	fetch := func(ctx context.Context, assets []asset.Asset, metrics []data.Metric, dateKey, maxEventDate time.Time) (*data.DataFrame, error) {
+		return e.fundamentalsByDateKey(ctx, assets, metrics, dateKey, e.quarterlyDimension(), maxEventDate)
+	}
+
+	columns := make(map[string][][]float64, len(assets))
+	for _, assetItem := range assets {
+		cols := make([][]float64, len(derived))
+		for mIdx := range cols {
+			cols[mIdx] = make([]float64, len(times))
+		}
+
+		columns[assetItem.CompositeFigi] = cols
+	}
+
+	// latest holds each asset's most recent evaluation while rows are
+	// filled in order.
+	latest := make(map[string][]float64, len(assets))
+
+	for row, asOf := range times {
+		if rowAssets := changed[row]; len(rowAssets) > 0 {
+			values, evalErr := data.EvaluateDerived(ctx, fetch, rowAssets, derived, asOf)
+			if evalErr != nil {
+				return nil, fmt.Errorf("engine: %w", evalErr)
+			}
+
+			for _, assetItem := range rowAssets {
+				current := make([]float64, len(derived))
+				for mIdx, metric := range derived {
+					current[mIdx] = values.Value(assetItem, metric)
+				}
+
+				latest[assetItem.CompositeFigi] = current
+			}
+		}
+
+		for _, assetItem := range assets {
+			current := latest[assetItem.CompositeFigi]
+			for mIdx := range derived {
+				columns[assetItem.CompositeFigi][mIdx][row] = current[mIdx]
+			}
+		}
+	}
+
+	for _, assetItem := range assets {
+		for mIdx, metric := range derived {
+			if err := assembled.Insert(assetItem, metric, columns[assetItem.CompositeFigi][mIdx]); err != nil {
+				return nil, fmt.Errorf("engine: insert derived %s: %w", metric, err)
+			}
+		}
+	}
+
+	return assembled.Metrics(metrics...), nil
+}
+
+// quarterlyFilingDeadlines are the days after a fiscal quarter end by
+// which its filing is public: 45 for a 10-Q and 90 for a 10-K.
+var quarterlyFilingDeadlines = [...]int{45, 90}
+
+// filingDeadlineRows marks the rows of times that are the first on or
+// after a quarter end plus one of quarterlyFilingDeadlines. It returns
+// nil under a quarterly dimension, whose date keys already change with
+// every quarterly filing.
+func (e *Engine) filingDeadlineRows(times []time.Time) map[int]bool {
+	if e.fundamentalDimension == "" || e.fundamentalDimension == e.quarterlyDimension() {
+		return nil
+	}
+
+	dateOf := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	first, last := dateOf(times[0]), dateOf(times[len(times)-1])
+
+	// Start from the quarter end whose latest deadline could still fall
+	// inside the range.
+	earliest := first.AddDate(0, 0, -quarterlyFilingDeadlines[len(quarterlyFilingDeadlines)-1])
+	quarterEnd := time.Date(earliest.Year(), ((earliest.Month()-1)/3)*3+1, 0, 0, 0, 0, 0, time.UTC)
+
+	rows := make(map[int]bool)
+
+	for ; !quarterEnd.After(last); quarterEnd = time.Date(quarterEnd.Year(), quarterEnd.Month()+4, 0, 0, 0, 0, 0, time.UTC) {
+		for _, days := range quarterlyFilingDeadlines {
+			deadline := quarterEnd.AddDate(0, 0, days)
+
+			row := sort.Search(len(times), func(idx int) bool { return !dateOf(times[idx]).Before(deadline) })
+			if row > 0 && row < len(times) {
+				rows[row] = true
+			}
+		}
+	}
+
+	return rows
+}
+
+// quarterlyDimension returns the quarterly fundamental dimension from
+// the same family (as reported or most recent) as the strategy's
+// configured dimension. Derived fundamentals always build on quarterly
+// filings.
+func (e *Engine) quarterlyDimension() string {
+	if strings.HasPrefix(e.fundamentalDimension, "MR") {
+		return "MRQ"
+	}
+
+	return "ARQ"
+}
+
+// sameValue reports whether a and b are equal, treating two NaNs as
+// equal.
+func sameValue(a, b float64) bool {
+	return a == b || (math.IsNaN(a) && math.IsNaN(b))
+}
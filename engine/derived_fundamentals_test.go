@@ -0,0 +1,254 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine_test
+
+import (
+	"context"
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/engine"
+	"github.com/penny-vault/pvbt/portfolio"
+)
+
+// quarterlyFiling is one quarter's fundamentals and the day they became
+// public.
+type quarterlyFiling struct {
+	dateKey time.Time
+	filed   time.Time
+	revenue float64
+}
+
+// filingProvider serves Revenue forward-filled from filing dates and
+// answers FetchFundamentalsByDateKey honoring maxEventDate.
+type filingProvider struct {
+	*data.TestProvider
+	figi       string
+	filings    []quarterlyFiling
+	dimensions []string
+}
+
+func newFilingProvider(aa asset.Asset, filings []quarterlyFiling) *filingProvider {
+	metrics := []data.Metric{data.Revenue, data.FundamentalsDateKey}
+	times := make([]time.Time, len(filings))
+	revenue := make([]float64, len(filings))
+	dateKeys := make([]float64, len(filings))
+
+	for idx, row := range filings {
+		times[idx] = time.Date(row.filed.Year(), row.filed.Month(), row.filed.Day(), 16, 0, 0, 0, time.UTC)
+		revenue[idx] = row.revenue
+		dateKeys[idx] = float64(row.dateKey.Unix())
+	}
+
+	df, err := data.NewDataFrame(times, []asset.Asset{aa}, metrics, data.Daily, [][]float64{revenue, dateKeys})
+	Expect(err).NotTo(HaveOccurred())
+
+	return &filingProvider{TestProvider: data.NewTestProvider(metrics, df), figi: aa.CompositeFigi, filings: filings}
+}
+
+func (p *filingProvider) FetchFundamentalsByDateKey(
+	_ context.Context,
+	assets []asset.Asset,
+	metrics []data.Metric,
+	dateKey time.Time,
+	dimension string,
+	maxEventDate time.Time,
+) (*data.DataFrame, error) {
+	p.dimensions = append(p.dimensions, dimension)
+	columns := make([][]float64, len(assets)*len(metrics))
+
+	for aIdx, aa := range assets {
+		for mIdx, mm := range metrics {
+			value := math.NaN()
+
+			for _, row := range p.filings {
+				if aa.CompositeFigi == p.figi && mm == data.Revenue && row.dateKey.Equal(dateKey) && !row.filed.After(maxEventDate) {
+					value = row.revenue
+				}
+			}
+
+			columns[aIdx*len(metrics)+mIdx] = []float64{value}
+		}
+	}
+
+	return data.NewDataFrame([]time.Time{dateKey}, assets, metrics, data.Daily, columns)
+}
+
+var _ = Describe("Derived fundamentals", func() {
+	var (
+		aapl     asset.Asset
+		closes   *data.TestProvider
+		filings  *filingProvider
+		filedQ1  time.Time
+		simStart time.Time
+		simEnd   time.Time
+	)
+
+	BeforeEach(func() {
+		aapl = asset.Asset{CompositeFigi: "FIGI-AAPL", Ticker: "AAPL"}
+
+		closeDF := makeDailyDF(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), 600, []asset.Asset{aapl}, []data.Metric{data.MetricClose})
+		closes = data.NewTestProvider([]data.Metric{data.MetricClose}, closeDF)
+
+		// Five quarters of revenue, each filed 40 days after quarter end.
+		// 2024 Q1 becomes public on May 10, 2024.
+		var rows []quarterlyFiling
+
+		for idx, dateKey := range []time.Time{
+			time.Date(2023, 3, 31, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, 9, 30, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		} {
+			rows = append(rows, quarterlyFiling{
+				dateKey: dateKey,
+				filed:   dateKey.AddDate(0, 0, 40),
+				revenue: []float64{100, 110, 120, 130, 150}[idx],
+			})
+		}
+
+		filedQ1 = time.Date(2024, 5, 10, 16, 0, 0, 0, time.UTC)
+		filings = newFilingProvider(aapl, rows)
+
+		// Monday, June 3, 2024.
+		simStart = time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+		simEnd = time.Date(2024, 6, 3, 23, 0, 0, 0, time.UTC)
+	})
+
+	It("changes a Fetch series only once the filing is public", func() {
+		strategy := &fetchStrategy{
+			lookback:  portfolio.Days(60),
+			metrics:   []data.Metric{data.MetricClose, data.TTM(data.Revenue)},
+			assets:    []asset.Asset{aapl},
+			firstOnly: true,
+		}
+
+		eng := engine.New(strategy,
+			engine.WithDataProvider(closes, filings),
+			engine.WithAssetProvider(&mockAssetProvider{assets: []asset.Asset{aapl}}),
+			engine.WithInitialDeposit(100_000.0),
+		)
+
+		_, err := eng.Backtest(context.Background(), simStart, simEnd)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strategy.fetchErr).NotTo(HaveOccurred())
+
+		Expect(strategy.fetched.MetricList()).To(Equal([]data.Metric{data.MetricClose, data.TTM(data.Revenue)}))
+
+		ttm := strategy.fetched.Column(aapl, data.TTM(data.Revenue))
+		Expect(ttm).To(HaveLen(strategy.fetched.Len()))
+		Expect(strategy.fetched.Start().Before(filedQ1)).To(BeTrue())
+
+		for idx, timestamp := range strategy.fetched.Times() {
+			if timestamp.Before(filedQ1) {
+				Expect(ttm[idx]).To(Equal(460.0), "TTM on %s", timestamp)
+			} else {
+				Expect(ttm[idx]).To(Equal(510.0), "TTM on %s", timestamp)
+			}
+		}
+
+		Expect(filings.dimensions).To(HaveEach("ARQ"))
+	})
+
+	It("picks up quarterly filings under an annual dimension by their filing deadline", func() {
+		// The annual dimension's series changes only with the 10-K, filed
+		// on Feb 9, 2024; the 2024 Q1 filing never appears in it.
+		annualDF, err := data.NewDataFrame(
+			[]time.Time{time.Date(2024, 2, 9, 16, 0, 0, 0, time.UTC)},
+			[]asset.Asset{aapl}, []data.Metric{data.Revenue, data.FundamentalsDateKey}, data.Daily,
+			[][]float64{{490}, {float64(time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC).Unix())}})
+		Expect(err).NotTo(HaveOccurred())
+
+		filings.TestProvider = data.NewTestProvider([]data.Metric{data.Revenue, data.FundamentalsDateKey}, annualDF)
+
+		strategy := &fetchStrategy{
+			lookback:  portfolio.Days(60),
+			metrics:   []data.Metric{data.MetricClose, data.TTM(data.Revenue)},
+			assets:    []asset.Asset{aapl},
+			firstOnly: true,
+		}
+
+		eng := engine.New(strategy,
+			engine.WithDataProvider(closes, filings),
+			engine.WithAssetProvider(&mockAssetProvider{assets: []asset.Asset{aapl}}),
+			engine.WithInitialDeposit(100_000.0),
+		)
+		eng.SetFundamentalDimension("ARY")
+
+		_, err = eng.Backtest(context.Background(), simStart, simEnd)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strategy.fetchErr).NotTo(HaveOccurred())
+
+		// The 10-Q deadline for 2024 Q1 is May 15.
+		deadline := time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC)
+		ttm := strategy.fetched.Column(aapl, data.TTM(data.Revenue))
+
+		for idx, timestamp := range strategy.fetched.Times() {
+			if timestamp.Before(filedQ1) {
+				Expect(ttm[idx]).To(Equal(460.0), "TTM on %s", timestamp)
+			} else if !timestamp.Before(deadline) {
+				Expect(ttm[idx]).To(Equal(510.0), "TTM on %s", timestamp)
+			}
+		}
+
+		Expect(filings.dimensions).To(HaveEach("ARQ"))
+	})
+
+	It("serves derived metrics from FetchAt with the matching quarterly dimension", func() {
+		strategy := &fetchAtStrategy{
+			metrics: []data.Metric{data.YoYGrowth(data.Revenue), data.TTM(data.Revenue)},
+			assets:  []asset.Asset{aapl},
+		}
+
+		eng := engine.New(strategy,
+			engine.WithDataProvider(closes, filings),
+			engine.WithAssetProvider(&mockAssetProvider{assets: []asset.Asset{aapl}}),
+			engine.WithInitialDeposit(100_000.0),
+		)
+		eng.SetFundamentalDimension("MRT")
+
+		_, err := eng.Backtest(context.Background(), simStart, simEnd)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strategy.fetchErr).NotTo(HaveOccurred())
+
+		Expect(strategy.fetched.Value(aapl, data.YoYGrowth(data.Revenue))).To(BeNumerically("~", 0.5, 1e-12))
+		Expect(strategy.fetched.Value(aapl, data.TTM(data.Revenue))).To(Equal(510.0))
+		Expect(filings.dimensions).To(HaveEach("MRQ"))
+	})
+
+	It("rejects derived metrics over non-fundamental operands", func() {
+		strategy := &fetchAtStrategy{
+			metrics: []data.Metric{data.TTM(data.MetricClose)},
+			assets:  []asset.Asset{aapl},
+		}
+
+		eng := engine.New(strategy,
+			engine.WithDataProvider(closes, filings),
+			engine.WithAssetProvider(&mockAssetProvider{assets: []asset.Asset{aapl}}),
+			engine.WithInitialDeposit(100_000.0),
+		)
+
+		_, err := eng.Backtest(context.Background(), simStart, simEnd)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strategy.fetchErr).To(MatchError(ContainSubstring("not a fundamental metric")))
+	})
+})
@@ -0,0 +1,484 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/penny-vault/pvbt/data"
+)
+
+const (
+	defaultDiskCacheMaxBytes int64 = 4 * 1024 * 1024 * 1024 // 4 GB
+	defaultDiskCacheTTL            = 12 * time.Hour
+
+	// diskCacheSettle is how long after a year ends its chunk keeps
+	// following the TTL. Vendors revise the last days of a year (late
+	// prints, split adjustments) for a while after it closes.
+	diskCacheSettle = 7 * 24 * time.Hour
+
+	// diskCacheLowWater is the fraction of MaxBytes eviction trims down to,
+	// so a cache at its limit does not evict on every write.
+	diskCacheLowWater = 0.9
+
+	diskCacheMagic   = "PVBC"
+	diskCacheVersion = 1
+	diskCacheExt     = ".col"
+)
+
+// DiskCacheConfig configures a DiskCache.
+type DiskCacheConfig struct {
+	// MaxBytes caps the total size of the cache directory. When a write
+	// pushes the cache over the limit the least recently used chunks are
+	// removed. Zero means 4 GB.
+	MaxBytes int64
+
+	// TTL is how long a chunk fetched before its year had settled stays
+	// valid. Chunks for years that ended more than a week before they were
+	// fetched never expire. Zero means 12 hours.
+	TTL time.Duration
+}
+
+// DiskCacheStats counts disk cache activity since the cache was opened.
+type DiskCacheStats struct {
+	Hits      int64
+	Misses    int64
+	Writes    int64
+	Evictions int64
+}
+
+// DiskCache is a persistent tier below the engine's in-memory column
+// cache. It stores one file per column chunk, keyed by provider, asset,
+// metric, fundamental dimension and calendar year, so repeated backtests
+// and study workers read history from disk instead of the provider.
+//
+// Only providers implementing data.CacheKeyed are cached; their CacheKey
+// keeps chunks from different data sources apart. A single DiskCache may
+// be shared by any number of engines, including concurrent study workers:
+// engines that miss the same provider and year wait for the first to
+// fetch it and then read its chunks from disk. Separate processes may
+// share a directory too; files are written atomically, so the worst case
+// is a duplicate fetch.
+type DiskCache struct {
+	dir      string
+	maxBytes int64
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	curBytes int64
+	groups   map[string]*sync.Mutex
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	writes    atomic.Int64
+	evictions atomic.Int64
+}
+
+// NewDiskCache opens (creating if needed) a disk cache rooted at dir.
+func NewDiskCache(dir string, cfg DiskCacheConfig) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("disk cache: create %s: %w", dir, err)
+	}
+
+	cache := &DiskCache{
+		dir:      dir,
+		maxBytes: cfg.MaxBytes,
+		ttl:      cfg.TTL,
+		now:      time.Now,
+		groups:   make(map[string]*sync.Mutex),
+	}
+
+	if cache.maxBytes <= 0 {
+		cache.maxBytes = defaultDiskCacheMaxBytes
+	}
+
+	if cache.ttl <= 0 {
+		cache.ttl = defaultDiskCacheTTL
+	}
+
+	files, err := cache.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		cache.curBytes += file.size
+	}
+
+	return cache, nil
+}
+
+// Dir returns the cache directory.
+func (c *DiskCache) Dir() string { return c.dir }
+
+// Size returns the cache's estimate of its directory size in bytes.
+func (c *DiskCache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.curBytes
+}
+
+// Stats returns hit, miss, write and eviction counts.
+func (c *DiskCache) Stats() DiskCacheStats {
+	return DiskCacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Writes:    c.writes.Load(),
+		Evictions: c.evictions.Load(),
+	}
+}
+
+// diskCacheKey identifies one column chunk on disk.
+type diskCacheKey struct {
+	provider   string
+	dimension  string
+	figi       string
+	metric     data.Metric
+	chunkStart int64
+}
+
+func (k diskCacheKey) String() string {
+	return fmt.Sprintf("%s|%s|%s|%s|%d", k.provider, k.dimension, k.figi, k.metric, k.chunkStart)
+}
+
+func (c *DiskCache) path(key diskCacheKey) string {
+	sum := sha256.Sum256([]byte(key.String()))
+	name := hex.EncodeToString(sum[:])
+
+	return filepath.Join(c.dir, name[:2], name[2:]+diskCacheExt)
+}
+
+// lockGroup serializes fetches of one provider's chunk year across every
+// engine sharing the cache. It returns the unlock function.
+func (c *DiskCache) lockGroup(provider string, chunkStart int64) func() {
+	name := fmt.Sprintf("%s|%d", provider, chunkStart)
+
+	c.mu.Lock()
+
+	group, ok := c.groups[name]
+	if !ok {
+		group = &sync.Mutex{}
+		c.groups[name] = group
+	}
+
+	c.mu.Unlock()
+
+	group.Lock()
+
+	return group.Unlock
+}
+
+// load reads a chunk from disk. Corrupt, mismatched and expired files are
+// removed and reported as misses.
+func (c *DiskCache) load(key diskCacheKey) (*colCacheEntry, bool) {
+	path := c.path(key)
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	entry, fetchedAt, err := decodeDiskChunk(raw, key.String())
+	if err != nil || c.expired(key, fetchedAt) {
+		c.remove(path, int64(len(raw)))
+		c.misses.Add(1)
+
+		return nil, false
+	}
+
+	// The modification time doubles as the LRU access time.
+	now := c.now()
+	_ = os.Chtimes(path, now, now)
+
+	c.hits.Add(1)
+
+	return entry, true
+}
+
+// expired reports whether a chunk fetched at fetchedAt has outlived the
+// TTL. Chunks fetched after their year settled never expire.
+func (c *DiskCache) expired(key diskCacheKey, fetchedAt time.Time) bool {
+	chunkStart := time.Unix(key.chunkStart, 0).In(nyc)
+	chunkEnd := time.Date(chunkStart.Year()+1, 1, 1, 0, 0, 0, 0, nyc)
+
+	if !fetchedAt.Before(chunkEnd.Add(diskCacheSettle)) {
+		return false
+	}
+
+	return c.now().Sub(fetchedAt) > c.ttl
+}
+
+// store writes a chunk to disk atomically and evicts least recently used
+// chunks if the cache is over its size limit.
+func (c *DiskCache) store(key diskCacheKey, entry *colCacheEntry) error {
+	path := c.path(key)
+	raw := encodeDiskChunk(entry, key.String(), c.now())
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("disk cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("disk cache: %w", err)
+	}
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+
+		return fmt.Errorf("disk cache: write %s: %w", path, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("disk cache: write %s: %w", path, err)
+	}
+
+	var replaced int64
+	if info, statErr := os.Stat(path); statErr == nil {
+		replaced = info.Size()
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("disk cache: write %s: %w", path, err)
+	}
+
+	c.writes.Add(1)
+
+	c.mu.Lock()
+	c.curBytes += int64(len(raw)) - replaced
+	over := c.curBytes > c.maxBytes
+	c.mu.Unlock()
+
+	if over {
+		return c.evict()
+	}
+
+	return nil
+}
+
+func (c *DiskCache) remove(path string, size int64) {
+	if os.Remove(path) != nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.curBytes -= size
+	c.mu.Unlock()
+}
+
+// diskCacheFile is one chunk file found while scanning the cache.
+type diskCacheFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// scan lists every chunk file in the cache directory.
+func (c *DiskCache) scan() ([]diskCacheFile, error) {
+	var files []diskCacheFile
+
+	err := filepath.WalkDir(c.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Another process may evict files mid-walk.
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if entry.IsDir() || !strings.HasSuffix(path, diskCacheExt) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+
+		files = append(files, diskCacheFile{path: path, size: info.Size(), modTime: info.ModTime()})
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("disk cache: scan %s: %w", c.dir, err)
+	}
+
+	return files, nil
+}
+
+// evict removes least recently used chunks until the cache is below its
+// low-water mark. The directory is rescanned so that files written by
+// other processes are accounted for.
+func (c *DiskCache) evict() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	files, err := c.scan()
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+	var total int64
+	for _, file := range files {
+		total += file.size
+	}
+
+	target := int64(float64(c.maxBytes) * diskCacheLowWater)
+
+	for _, file := range files {
+		if total <= target {
+			break
+		}
+
+		if os.Remove(file.path) == nil {
+			total -= file.size
+			c.evictions.Add(1)
+		}
+	}
+
+	c.curBytes = total
+
+	return nil
+}
+
+// encodeDiskChunk serializes a column chunk: magic, version, key, fetch
+// time, time zone, length, timestamps and values, followed by a CRC-32 of
+// everything before it.
+func encodeDiskChunk(entry *colCacheEntry, key string, fetchedAt time.Time) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString(diskCacheMagic)
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(diskCacheVersion))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(len(key)))
+	buf.WriteString(key)
+	_ = binary.Write(&buf, binary.LittleEndian, fetchedAt.UnixNano())
+
+	location := ""
+	if len(entry.times) > 0 {
+		location = entry.times[0].Location().String()
+	}
+
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(len(location)))
+	buf.WriteString(location)
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(entry.times)))
+
+	for _, tt := range entry.times {
+		_ = binary.Write(&buf, binary.LittleEndian, tt.UnixNano())
+	}
+
+	for _, val := range entry.values {
+		_ = binary.Write(&buf, binary.LittleEndian, math.Float64bits(val))
+	}
+
+	_ = binary.Write(&buf, binary.LittleEndian, crc32.ChecksumIEEE(buf.Bytes()))
+
+	return buf.Bytes()
+}
+
+var errDiskChunkInvalid = errors.New("disk cache: invalid chunk")
+
+// decodeDiskChunk parses and validates a chunk written by encodeDiskChunk.
+func decodeDiskChunk(raw []byte, key string) (*colCacheEntry, time.Time, error) {
+	const headerLen = len(diskCacheMagic) + 2 + 2
+
+	if len(raw) < headerLen+4 {
+		return nil, time.Time{}, errDiskChunkInvalid
+	}
+
+	body, sum := raw[:len(raw)-4], binary.LittleEndian.Uint32(raw[len(raw)-4:])
+	if crc32.ChecksumIEEE(body) != sum || string(body[:len(diskCacheMagic)]) != diskCacheMagic {
+		return nil, time.Time{}, errDiskChunkInvalid
+	}
+
+	pos := len(diskCacheMagic)
+
+	if binary.LittleEndian.Uint16(body[pos:]) != diskCacheVersion {
+		return nil, time.Time{}, errDiskChunkInvalid
+	}
+
+	keyLen := int(binary.LittleEndian.Uint16(body[pos+2:]))
+	pos = headerLen
+
+	if len(body) < pos+keyLen+10 || string(body[pos:pos+keyLen]) != key {
+		return nil, time.Time{}, errDiskChunkInvalid
+	}
+
+	pos += keyLen
+	fetchedAt := time.Unix(0, int64(binary.LittleEndian.Uint64(body[pos:])))
+	locLen := int(binary.LittleEndian.Uint16(body[pos+8:]))
+	pos += 10
+
+	if len(body) < pos+locLen+4 {
+		return nil, time.Time{}, errDiskChunkInvalid
+	}
+
+	location, err := time.LoadLocation(string(body[pos : pos+locLen]))
+	if err != nil {
+		return nil, time.Time{}, errDiskChunkInvalid
+	}
+
+	pos += locLen
+	count := int(binary.LittleEndian.Uint32(body[pos:]))
+	pos += 4
+
+	if len(body) != pos+count*16 {
+		return nil, time.Time{}, errDiskChunkInvalid
+	}
+
+	entry := &colCacheEntry{}
+	if count == 0 {
+		return entry, fetchedAt, nil
+	}
+
+	entry.times = make([]time.Time, count)
+	entry.values = make([]float64, count)
+
+	for idx := range count {
+		entry.times[idx] = time.Unix(0, int64(binary.LittleEndian.Uint64(body[pos+idx*8:]))).In(location)
+	}
+
+	pos += count * 8
+
+	for idx := range count {
+		entry.values[idx] = math.Float64frombits(binary.LittleEndian.Uint64(body[pos+idx*8:]))
+	}
+
+	return entry, fetchedAt, nil
+}
@@ -0,0 +1,231 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine_test
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/engine"
+	"github.com/penny-vault/pvbt/portfolio"
+)
+
+// keyedCountingProvider is a disk-cacheable TestProvider that counts
+// Fetch calls from any goroutine.
+type keyedCountingProvider struct {
+	data.DataProvider
+	inner   *data.TestProvider
+	key     string
+	fetches atomic.Int64
+}
+
+func newKeyedCountingProvider(key string, metrics []data.Metric, df *data.DataFrame) *keyedCountingProvider {
+	inner := data.NewTestProvider(metrics, df)
+	return &keyedCountingProvider{DataProvider: inner, inner: inner, key: key}
+}
+
+func (p *keyedCountingProvider) CacheKey() string { return p.key }
+
+func (p *keyedCountingProvider) Fetch(ctx context.Context, req data.DataRequest) (*data.DataFrame, error) {
+	p.fetches.Add(1)
+	return p.inner.Fetch(ctx, req)
+}
+
+var _ = Describe("DiskCache", func() {
+	var (
+		aapl    asset.Asset
+		metrics []data.Metric
+		df      *data.DataFrame
+		dir     string
+		start   time.Time
+		end     time.Time
+	)
+
+	BeforeEach(func() {
+		aapl = asset.Asset{CompositeFigi: "FIGI-AAPL", Ticker: "AAPL"}
+		metrics = []data.Metric{data.MetricClose}
+		df = makeDailyDF(time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), 150, []asset.Asset{aapl}, metrics)
+		dir = GinkgoT().TempDir()
+		start = time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+		end = time.Date(2024, 2, 9, 23, 0, 0, 0, time.UTC)
+	})
+
+	// runBacktest runs a lookback-fetching strategy and returns the close
+	// prices it saw.
+	runBacktest := func(provider data.BatchProvider, cache *engine.DiskCache) []float64 {
+		strategy := &fetchStrategy{
+			lookback:  portfolio.Days(60),
+			metrics:   metrics,
+			assets:    []asset.Asset{aapl},
+			firstOnly: true,
+		}
+
+		eng := engine.New(strategy,
+			engine.WithDataProvider(provider),
+			engine.WithAssetProvider(&mockAssetProvider{assets: []asset.Asset{aapl}}),
+			engine.WithInitialDeposit(100_000.0),
+			engine.WithDiskCache(cache),
+		)
+
+		_, err := eng.Backtest(context.Background(), start, end)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strategy.fetchErr).NotTo(HaveOccurred())
+
+		return strategy.fetched.Column(aapl, data.MetricClose)
+	}
+
+	It("serves a second run from disk without calling the provider", func() {
+		cache, err := engine.NewDiskCache(dir, engine.DiskCacheConfig{})
+		Expect(err).NotTo(HaveOccurred())
+
+		first := newKeyedCountingProvider("test-db", metrics, df)
+		firstCloses := runBacktest(first, cache)
+		Expect(first.fetches.Load()).To(BeNumerically(">", 0))
+		Expect(cache.Stats().Writes).To(BeNumerically(">", 0))
+
+		// A fresh cache on the same directory stands in for a new process.
+		reopened, err := engine.NewDiskCache(dir, engine.DiskCacheConfig{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reopened.Size()).To(Equal(cache.Size()))
+
+		second := newKeyedCountingProvider("test-db", metrics, df)
+		Expect(runBacktest(second, reopened)).To(Equal(firstCloses))
+		Expect(second.fetches.Load()).To(BeZero())
+		Expect(reopened.Stats().Hits).To(BeNumerically(">", 0))
+	})
+
+	It("keeps chunks from different provider keys apart", func() {
+		cache, err := engine.NewDiskCache(dir, engine.DiskCacheConfig{})
+		Expect(err).NotTo(HaveOccurred())
+
+		runBacktest(newKeyedCountingProvider("db-one", metrics, df), cache)
+
+		other := newKeyedCountingProvider("db-two", metrics, df)
+		runBacktest(other, cache)
+		Expect(other.fetches.Load()).To(BeNumerically(">", 0))
+	})
+
+	It("does not cache providers without a cache key", func() {
+		cache, err := engine.NewDiskCache(dir, engine.DiskCacheConfig{})
+		Expect(err).NotTo(HaveOccurred())
+
+		runBacktest(newCountingProvider(metrics, df), cache)
+		Expect(cache.Stats().Writes).To(BeZero())
+		Expect(cache.Size()).To(BeZero())
+	})
+
+	It("fetches each chunk once across concurrent engines", func() {
+		cache, err := engine.NewDiskCache(dir, engine.DiskCacheConfig{})
+		Expect(err).NotTo(HaveOccurred())
+
+		solo := newKeyedCountingProvider("solo-db", metrics, df)
+		runBacktest(solo, cache)
+
+		shared := newKeyedCountingProvider("shared-db", metrics, df)
+
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Add(1)
+
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				runBacktest(shared, cache)
+			}()
+		}
+
+		wg.Wait()
+
+		Expect(shared.fetches.Load()).To(Equal(solo.fetches.Load()))
+	})
+
+	It("discards corrupt chunks and refetches them", func() {
+		cache, err := engine.NewDiskCache(dir, engine.DiskCacheConfig{})
+		Expect(err).NotTo(HaveOccurred())
+
+		closes := runBacktest(newKeyedCountingProvider("test-db", metrics, df), cache)
+
+		Expect(filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+
+			return os.WriteFile(path, []byte("garbage"), 0o600)
+		})).To(Succeed())
+
+		again := newKeyedCountingProvider("test-db", metrics, df)
+		Expect(runBacktest(again, cache)).To(Equal(closes))
+		Expect(again.fetches.Load()).To(BeNumerically(">", 0))
+	})
+
+	It("expires chunks of an unsettled year after the TTL", func() {
+		cache, err := engine.NewDiskCache(dir, engine.DiskCacheConfig{TTL: time.Hour})
+		Expect(err).NotTo(HaveOccurred())
+
+		// Pretend the backtest runs while 2024 is still in progress.
+		now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		engine.SetDiskCacheClockForTest(cache, func() time.Time { return now })
+
+		runBacktest(newKeyedCountingProvider("test-db", metrics, df), cache)
+
+		fresh := newKeyedCountingProvider("test-db", metrics, df)
+		runBacktest(fresh, cache)
+		Expect(fresh.fetches.Load()).To(BeZero())
+
+		now = now.Add(2 * time.Hour)
+
+		stale := newKeyedCountingProvider("test-db", metrics, df)
+		runBacktest(stale, cache)
+		Expect(stale.fetches.Load()).To(BeNumerically(">", 0))
+	})
+
+	It("evicts least recently used chunks beyond the size limit", func() {
+		cache, err := engine.NewDiskCache(dir, engine.DiskCacheConfig{MaxBytes: 2048})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, key := range []string{"db-a", "db-b", "db-c", "db-d"} {
+			runBacktest(newKeyedCountingProvider(key, metrics, df), cache)
+		}
+
+		Expect(cache.Stats().Evictions).To(BeNumerically(">", 0))
+		Expect(cache.Size()).To(BeNumerically("<=", 2048))
+
+		var total int64
+
+		Expect(filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+
+			info, err := entry.Info()
+			total += info.Size()
+
+			return err
+		})).To(Succeed())
+		Expect(total).To(Equal(cache.Size()))
+	})
+})
@@ -150,14 +150,22 @@
 //     wins (pessimistic assumption). Triggered fills are queued for
 //     processing in the next step.
 //  4. Updates the simulated broker with the current price provider and date
-//     so orders can fill.
+//     so orders can fill, and posts any deposits and withdrawals scheduled
+//     with WithCashFlows.
 //  5. Calls Compute. The strategy fetches data, computes signals, and tells
 //     the portfolio to rebalance.
-//  6. Fetches post-Compute prices for all held assets (including newly
+//  6. Checks the triggers registered with AddTrigger. When one becomes true
+//     the engine runs the strategy again with a batch annotated with the
+//     trigger's name, calling OnTrigger if the strategy implements
+//     TriggerHandler and Compute otherwise.
+//     Strategies implementing BarHandler then get OnBar. Fills, dividends,
+//     and splits are delivered to FillHandler and CorporateActionHandler
+//     strategies after housekeeping and after each of these batches.
+//  7. Fetches post-Compute prices for all held assets (including newly
 //     acquired positions) and updates the equity curve.
-//  7. Computes all registered performance metrics across standard windows
+//  8. Computes all registered performance metrics across standard windows
 //     (5yr, 3yr, 1yr, YTD, MTD, WTD, and since-inception).
-//  8. Evicts stale cache entries.
+//  9. Evicts stale cache entries.
 //
 // Phase 4: Return. After the final step, the portfolio contains the full
 // transaction log and can compute performance metrics. It provides access to
@@ -19,6 +19,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"math"
 	"slices"
 	"sort"
@@ -43,6 +44,18 @@ type Engine struct {
 	schedule      *tradecron.TradeCron
 	benchmark     asset.Asset
 
+	// Explicit provider precedence (WithProviderPrecedence,
+	// WithMetricPrecedence); highest precedence first.
+	providerPrecedence []data.DataProvider
+	metricPrecedence   map[data.Metric][]data.DataProvider
+
+	// Data quality audit (WithDataQualityPolicy). auditedProviders maps
+	// the audit wrappers in the routing table to the providers they wrap.
+	dataQuality      DataQualityPolicy
+	auditConfig      data.AuditConfig
+	auditedProviders map[data.BatchProvider]data.BatchProvider
+	warnedAnomalies  map[anomalyID]struct{}
+
 	// Risk-free rate (DGS3MO) state.
 	riskFreeResolved   bool
 	riskFreeAssetDGS   asset.Asset
@@ -53,6 +66,7 @@ type Engine struct {
 
 	// configuration (set via options, used during init)
 	cacheMaxBytes            int64
+	diskCache                *DiskCache
 	initialDeposit           float64
 	broker                   broker.Broker
 	snapshot                 portfolio.PortfolioSnapshot
@@ -64,9 +78,20 @@ type Engine struct {
 	marginModel              *portfolio.RegT
 	fillBaseModel            broker.BaseModel
 	fillAdjusters            []broker.Adjuster
+	commissionModel          broker.CommissionModel
 	middlewareConfig         *MiddlewareConfig
 	fundamentalDimension     string
 	progressCallback         ProgressCallback
+	liveStateDir             string
+	resumePath               string
+	reconcile                *ReconcileConfig
+	cashFlows                []CashFlow
+	dividendReinvestment     *portfolio.DividendReinvestment
+	fxSeries                 map[asset.Currency]FXSeries
+	fxSources                map[asset.Currency]fxSource
+	currencyHedge            *portfolio.CurrencyHedge
+	calendar                 tradecron.Calendar
+	triggers                 []*registeredTrigger
 
 	// userParams names strategy fields the caller has explicitly set
 	// (via CLI flags, presets, ApplyParams). hydrateFields skips applying
@@ -84,12 +109,21 @@ type Engine struct {
 	currentTime           time.Time // intra-day firing timestamp; equals currentDate (end-of-day) outside of intra-day Compute calls
 	start                 time.Time
 	end                   time.Time
-	metricProvider        map[data.Metric]data.BatchProvider
+	metricProvider        map[data.Metric][]data.BatchProvider // provider chain per metric, highest precedence first
 	predicting            bool
 	measurementsEvaluated int
 
 	children       []*childEntry
 	childrenByName map[string]*childEntry
+
+	cashFlowPlan *cashFlowPlan // cash flows resolved to trading dates for the current backtest
+
+	stream       *streamManager // live-mode StreamProvider subscription; nil when no provider streams
+	streamMaxAge time.Duration  // age after which a streamed quote is treated as missing
+
+	events *eventQueue // fills and corporate actions awaiting the strategy's callbacks; nil when it has none
+
+	algoBroker *algoBroker // live-mode wrapper working algorithmic orders; nil in backtests
 }
 
 // New creates a new engine for the given strategy.
@@ -154,6 +188,8 @@ func (e *Engine) createAccount(start time.Time) (portfolio.PortfolioManager, err
 			sb.SetFillPipeline(broker.NewPipeline(e.fillBaseModel, e.fillAdjusters))
 		}
 
+		sb.SetCommissionModel(e.commissionModel)
+
 		e.broker = sb
 	}
 
@@ -167,6 +203,14 @@ func (e *Engine) createAccount(start time.Time) (portfolio.PortfolioManager, err
 			e.account.SetBroker(e.broker)
 		}
 
+		if e.dividendReinvestment != nil {
+			e.account.SetDividendReinvestment(*e.dividendReinvestment)
+		}
+
+		if e.currencyHedge != nil {
+			e.account.SetCurrencyHedge(*e.currencyHedge)
+		}
+
 		return e.account, nil
 	}
 
@@ -183,6 +227,14 @@ func (e *Engine) createAccount(start time.Time) (portfolio.PortfolioManager, err
 
 	opts = append(opts, portfolio.WithBroker(e.broker))
 
+	if e.dividendReinvestment != nil {
+		opts = append(opts, portfolio.WithDividendReinvestment(*e.dividendReinvestment))
+	}
+
+	if e.currencyHedge != nil {
+		opts = append(opts, portfolio.WithCurrencyHedge(*e.currencyHedge))
+	}
+
 	return portfolio.New(opts...), nil
 }
 
@@ -206,6 +258,56 @@ func (e *Engine) SetBenchmark(a asset.Asset) {
 	e.benchmark = a
 }
 
+// marketCalendar returns the calendar the engine schedules against: the
+// one set by WithCalendar or chosen by resolveCalendar, or NYSE.
+func (e *Engine) marketCalendar() tradecron.Calendar {
+	if e.calendar == nil {
+		return tradecron.NYSE
+	}
+
+	return e.calendar
+}
+
+// resolveCalendar picks the market calendar from the listing exchange of
+// the strategy's statically declared assets when WithCalendar was not
+// used. Assets whose exchange has no known calendar are ignored; when the
+// rest list on more than one calendar, the engine stays on NYSE.
+func (e *Engine) resolveCalendar(ctx context.Context) {
+	if e.calendar != nil {
+		return
+	}
+
+	var chosen tradecron.Calendar
+
+	for _, held := range collectStrategyAssets(e.strategy, asset.Asset{}) {
+		cal, ok := tradecron.Lookup(string(held.PrimaryExchange))
+		if !ok {
+			continue
+		}
+
+		if chosen != nil && chosen != cal {
+			zerolog.Ctx(ctx).Warn().
+				Str("first", chosen.Name()).
+				Str("second", cal.Name()).
+				Msg("strategy assets list on more than one exchange calendar; scheduling on NYSE")
+
+			return
+		}
+
+		chosen = cal
+	}
+
+	e.calendar = chosen
+}
+
+// newSchedule parses a tradecron spec against the engine's market
+// calendar and that calendar's regular session.
+func (e *Engine) newSchedule(spec string) (*tradecron.TradeCron, error) {
+	cal := e.marketCalendar()
+
+	return tradecron.New(spec, cal.Session(), tradecron.WithCalendar(cal))
+}
+
 // Asset looks up an asset by ticker from the pre-loaded registry.
 // Panics if the ticker cannot be resolved.
 func (e *Engine) Asset(ticker string) asset.Asset {
@@ -294,6 +396,38 @@ func (e *Engine) SpliceUniverse(primary string, fallbacks ...universe.SplicePeri
 	return u
 }
 
+// ContinuousFutures creates a single-asset universe that follows a chain
+// of futures contracts, rolling to the next contract rollDays weekdays
+// before each expiry. Window returns the contracts' histories stitched
+// into one series with the roll gaps removed as adjustment selects; call
+// Roll from Compute to move held contracts into the active one.
+func (e *Engine) ContinuousFutures(adjustment universe.FuturesAdjustment, rollDays int, tickers ...string) *universe.ContinuousFutures {
+	u := universe.NewContinuousFutures(adjustment, rollDays, tickers...)
+	u.Resolve(e.Asset)
+	u.SetDataSource(e)
+
+	return u
+}
+
+// OptionChain returns the option chain on underlying as of the current
+// simulation date from the first registered provider that implements
+// data.OptionChainProvider.
+func (e *Engine) OptionChain(ctx context.Context, underlying asset.Asset) ([]data.OptionQuote, error) {
+	for _, p := range e.providers {
+		if op, ok := p.(data.OptionChainProvider); ok {
+			chain, err := op.OptionChain(ctx, underlying, e.currentDate)
+			if err != nil {
+				return nil, fmt.Errorf("engine: option chain for %s on %s: %w",
+					underlying.Ticker, e.currentDate.Format(time.DateOnly), err)
+			}
+
+			return chain, nil
+		}
+	}
+
+	return nil, fmt.Errorf("engine: no provider implements OptionChainProvider (needed for %s options)", underlying.Ticker)
+}
+
 // CurrentDate returns the current simulation date (calendar date, with
 // the time-of-day component set to the trading-day boundary used by the
 // engine for end-of-day operations like dividend posting and equity
@@ -320,22 +454,131 @@ func periodToTime(base time.Time, period portfolio.Period) time.Time {
 	return period.Before(base)
 }
 
-// buildProviderRouting populates e.metricProvider by iterating e.providers
-// and mapping each metric to the BatchProvider that serves it.
-// Returns an error if a provider does not implement BatchProvider.
+// maxProviderChain is the longest provider chain a metric may have;
+// per-cell provenance records the chain index in a byte.
+const maxProviderChain = 256
+
+// buildProviderRouting populates e.metricProvider with the chain of
+// BatchProviders serving each metric, highest precedence first. A metric
+// named by WithMetricPrecedence gets exactly the providers listed there.
+// Otherwise its chain holds every provider serving it: those named by
+// WithProviderPrecedence in that order, then the rest with the most
+// recently registered first. Providers that only implement StreamProvider
+// are skipped; RunLive subscribes to them directly. Returns an error if
+// any other provider does not implement BatchProvider.
 func (e *Engine) buildProviderRouting() error {
-	e.metricProvider = make(map[data.Metric]data.BatchProvider)
-	for _, provider := range e.providers {
+	serving := make(map[data.Metric][]data.BatchProvider)
+
+	for idx := len(e.providers) - 1; idx >= 0; idx-- {
+		provider := e.providers[idx]
+
 		batchProvider, ok := provider.(data.BatchProvider)
 		if !ok {
+			if _, isStream := provider.(data.StreamProvider); isStream {
+				continue
+			}
+
 			return fmt.Errorf("engine: provider %T does not implement BatchProvider", provider)
 		}
 
 		for _, metric := range provider.Provides() {
-			e.metricProvider[metric] = batchProvider
+			if !slices.Contains(serving[metric], batchProvider) {
+				serving[metric] = append(serving[metric], batchProvider)
+			}
 		}
 	}
 
+	e.metricProvider = make(map[data.Metric][]data.BatchProvider, len(serving)+len(e.metricPrecedence))
+
+	for metric, candidates := range serving {
+		chain := make([]data.BatchProvider, 0, len(candidates))
+
+		for _, provider := range e.providerPrecedence {
+			if bp, ok := provider.(data.BatchProvider); ok && slices.Contains(candidates, bp) && !slices.Contains(chain, bp) {
+				chain = append(chain, bp)
+			}
+		}
+
+		for _, bp := range candidates {
+			if !slices.Contains(chain, bp) {
+				chain = append(chain, bp)
+			}
+		}
+
+		e.metricProvider[metric] = chain
+	}
+
+	for metric, providers := range e.metricPrecedence {
+		chain := make([]data.BatchProvider, 0, len(providers))
+
+		for _, provider := range providers {
+			bp, ok := provider.(data.BatchProvider)
+			if !ok {
+				return fmt.Errorf("engine: precedence for metric %q: provider %T does not implement BatchProvider", metric, provider)
+			}
+
+			if !slices.Contains(chain, bp) {
+				chain = append(chain, bp)
+			}
+		}
+
+		if len(chain) == 0 {
+			delete(e.metricProvider, metric)
+			continue
+		}
+
+		e.metricProvider[metric] = chain
+	}
+
+	for metric, chain := range e.metricProvider {
+		if len(chain) > maxProviderChain {
+			return fmt.Errorf("engine: metric %q has %d providers; at most %d are supported", metric, len(chain), maxProviderChain)
+		}
+	}
+
+	e.auditRouting()
+
+	return nil
+}
+
+// Provenance reports which provider supplied the value of metric for a
+// at date: the most recent cell at or before date in the engine's data
+// cache, which is the cell Fetch and FetchAt return for that date. It
+// returns nil when the column has not been fetched or holds no cell on
+// or before date. Provenance is meant for debugging provider chains.
+func (e *Engine) Provenance(a asset.Asset, metric data.Metric, date time.Time) data.BatchProvider {
+	source := e.provenance(a, metric, date)
+	if original, ok := e.auditedProviders[source]; ok {
+		return original
+	}
+
+	return source
+}
+
+// provenance is Provenance without unwrapping the audit wrappers
+// installed by the data quality policy.
+func (e *Engine) provenance(a asset.Asset, metric data.Metric, date time.Time) data.BatchProvider {
+	chain := e.metricProvider[metric]
+	if e.cache == nil || len(chain) == 0 {
+		return nil
+	}
+
+	year := time.Date(date.In(nyc).Year(), 1, 1, 0, 0, 0, 0, nyc)
+
+	for _, chunk := range []time.Time{year, year.AddDate(-1, 0, 0)} {
+		entry, ok := e.cache.get(colCacheKey{figi: a.CompositeFigi, metric: metric, chunkStart: chunk.Unix()})
+		if !ok {
+			continue
+		}
+
+		idx := sort.Search(len(entry.times), func(i int) bool { return entry.times[i].After(date) }) - 1
+		if idx < 0 {
+			continue
+		}
+
+		return chain[entry.source(idx)]
+	}
+
 	return nil
 }
 
@@ -399,12 +642,33 @@ func (e *Engine) Fetch(ctx context.Context, assets []asset.Asset, lookback portf
 	}
 
 	assembled.SetSource(e)
+	assembled.SetCalendar(e.marketCalendar())
+
+	if err := e.checkDataQuality(ctx, assembled); err != nil {
+		return nil, err
+	}
 
 	return assembled, nil
 }
 
 // FetchAt implements data.DataSource.
 func (e *Engine) FetchAt(ctx context.Context, assets []asset.Asset, timestamp time.Time, metrics []data.Metric) (*data.DataFrame, error) {
+	result, err := e.fetchAt(ctx, assets, timestamp, metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.checkDataQuality(ctx, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// fetchAt is FetchAt without the data quality check. The engine's own
+// reads (fill prices, housekeeping, risk-free marks) use it so that the
+// policy applies only to data the strategy consumes.
+func (e *Engine) fetchAt(ctx context.Context, assets []asset.Asset, timestamp time.Time, metrics []data.Metric) (*data.DataFrame, error) {
 	if !e.predicting && !e.currentDate.IsZero() && timestamp.After(e.currentDate) {
 		return nil, fmt.Errorf("FetchAt: requested future date %s (current simulation date is %s)",
 			timestamp.Format("2006-01-02"), e.currentDate.Format("2006-01-02"))
@@ -440,6 +704,7 @@ func (e *Engine) FetchAt(ctx context.Context, assets []asset.Asset, timestamp ti
 	}
 
 	result.SetSource(e)
+	result.SetCalendar(e.marketCalendar())
 
 	return result, nil
 }
@@ -518,20 +783,33 @@ func (e *Engine) FetchFundamentalsByDateKey(
 		dimension = "ARQ"
 	}
 
-	for _, provider := range e.providers {
-		if dp, ok := provider.(data.FundamentalsByDateKeyProvider); ok {
-			df, err := dp.FetchFundamentalsByDateKey(ctx, assets, metrics, dateKey, dimension, maxEventDate)
-			if err != nil {
-				return nil, fmt.Errorf("FetchFundamentalsByDateKey: %w", err)
-			}
+	df, err := e.fundamentalsByDateKey(ctx, assets, metrics, dateKey, dimension, maxEventDate)
+	if err != nil {
+		return nil, fmt.Errorf("FetchFundamentalsByDateKey: %w", err)
+	}
 
-			df.SetSource(e)
+	df.SetSource(e)
 
-			return df, nil
+	return df, nil
+}
+
+// fundamentalsByDateKey queries the first registered provider that
+// implements data.FundamentalsByDateKeyProvider.
+func (e *Engine) fundamentalsByDateKey(
+	ctx context.Context,
+	assets []asset.Asset,
+	metrics []data.Metric,
+	dateKey time.Time,
+	dimension string,
+	maxEventDate time.Time,
+) (*data.DataFrame, error) {
+	for _, provider := range e.providers {
+		if dp, ok := provider.(data.FundamentalsByDateKeyProvider); ok {
+			return dp.FetchFundamentalsByDateKey(ctx, assets, metrics, dateKey, dimension, maxEventDate)
 		}
 	}
 
-	return nil, fmt.Errorf("FetchFundamentalsByDateKey: no provider supports FundamentalsByDateKeyProvider")
+	return nil, errors.New("no provider supports FundamentalsByDateKeyProvider")
 }
 
 // sliceRiskFree returns the cumulative risk-free values for the given
@@ -568,202 +846,696 @@ func (e *Engine) sliceRiskFree(timestamps []time.Time) []float64 {
 	return result
 }
 
-// fetchRange is the shared implementation for Fetch and FetchAt.
-// It checks the per-column cache, bulk-fetches misses grouped by
-// calendar-year chunk, and assembles the result.
-func (e *Engine) fetchRange(ctx context.Context, assets []asset.Asset, metrics []data.Metric, rangeStart, rangeEnd time.Time) (*data.DataFrame, error) {
+// providerFetch is one provider's share of a chunk-year cache miss.
+type providerFetch struct {
+	provider data.BatchProvider
+	assets   []asset.Asset
+	metrics  []data.Metric
+
+	// start and end bound the request. partial is set when they cover
+	// less than the whole chunk; a partial result is never written to
+	// the disk cache because it would read back as the full year.
+	start, end time.Time
+	partial    bool
+
+	// diskKey and dimension identify the provider's chunks in the disk
+	// cache; diskKey is empty when the provider is not cached on disk.
+	diskKey   string
+	dimension string
+}
+
+// diskCacheKey returns the disk cache key for one of pf's columns.
+// Fundamental metrics include the dimension; all others share a key
+// across dimensions.
+func (pf *providerFetch) diskCacheKey(figi string, metric data.Metric, year int64) diskCacheKey {
+	key := diskCacheKey{provider: pf.diskKey, figi: figi, metric: metric, chunkStart: year}
+	if data.IsFundamental(metric) {
+		key.dimension = pf.dimension
+	}
+
+	return key
+}
+
+// providerColumnKey identifies one column a single provider returned for
+// the chunk being fetched, before provider chains are merged.
+type providerColumnKey struct {
+	provider data.BatchProvider
+	figi     string
+	metric   data.Metric
+}
+
+// fetchChunk fills the column cache for the given assets and metrics over
+// one calendar-year chunk, reading the disk cache first when one is
+// configured and writing back whatever the providers return. A metric's
+// chain is fetched one precedence level at a time: the first provider is
+// asked for every asset, and each later provider only for the assets and
+// dates the providers before it left missing or NaN. The columns are then
+// merged so that those cells are filled from the next provider.
+func (e *Engine) fetchChunk(ctx context.Context, year int64, missAssets []asset.Asset, missMetrics []data.Metric) error {
 	log := zerolog.Ctx(ctx)
 
-	years := chunkYears(rangeStart, rangeEnd)
+	chunkStart := time.Unix(year, 0).In(nyc)
+	chunkEnd := time.Date(chunkStart.Year()+1, 1, 1, 0, 0, 0, 0, nyc).Add(-time.Nanosecond)
 
-	// Identify cache misses grouped by chunk year.
-	type chunkMiss struct {
-		assets  map[string]asset.Asset // figi -> asset
-		metrics map[data.Metric]bool
+	log.Debug().
+		Int("missAssets", len(missAssets)).
+		Int("missMetrics", len(missMetrics)).
+		Time("chunkStart", chunkStart).
+		Time("chunkEnd", chunkEnd).
+		Msg("engine.fetchRange cache miss")
+
+	depth := 0
+
+	for _, metric := range missMetrics {
+		chain, ok := e.metricProvider[metric]
+		if !ok {
+			return fmt.Errorf("engine: no provider registered for metric %q", metric)
+		}
+
+		depth = max(depth, len(chain))
 	}
 
-	misses := make(map[int64]*chunkMiss)
+	fetched := make(map[providerColumnKey]*colCacheEntry)
+	now := time.Now()
 
-	for _, year := range years {
-		for _, assetItem := range assets {
-			for _, metric := range metrics {
-				key := colCacheKey{figi: assetItem.CompositeFigi, metric: metric, chunkStart: year}
-				if _, ok := e.cache.get(key); !ok {
-					cacheMiss, exists := misses[year]
-					if !exists {
-						cacheMiss = &chunkMiss{
-							assets:  make(map[string]asset.Asset),
-							metrics: make(map[data.Metric]bool),
-						}
-						misses[year] = cacheMiss
+	for level := range depth {
+		// Group this level's columns by provider so each provider is
+		// fetched once, over the hull of the dates its columns need.
+		type levelFetch struct {
+			assets     map[string]asset.Asset
+			metrics    map[data.Metric]bool
+			start, end time.Time
+		}
+
+		levels := make(map[data.BatchProvider]*levelFetch)
+
+		var order []data.BatchProvider
+
+		for _, metric := range missMetrics {
+			chain := e.metricProvider[metric]
+			if level >= len(chain) {
+				continue
+			}
+
+			for _, assetItem := range missAssets {
+				start, end := chunkStart, chunkEnd
+
+				if level > 0 {
+					cols := make([]*colCacheEntry, level)
+					for idx, bp := range chain[:level] {
+						cols[idx] = fetched[providerColumnKey{provider: bp, figi: assetItem.CompositeFigi, metric: metric}]
 					}
 
-					cacheMiss.assets[assetItem.CompositeFigi] = assetItem
-					cacheMiss.metrics[metric] = true
+					var gap bool
+
+					start, end, gap = chainGap(mergeChainColumns(cols), chunkStart, chunkEnd, now)
+					if !gap {
+						continue
+					}
+				}
+
+				lf, exists := levels[chain[level]]
+				if !exists {
+					lf = &levelFetch{
+						assets:  make(map[string]asset.Asset),
+						metrics: make(map[data.Metric]bool),
+						start:   start,
+						end:     end,
+					}
+					levels[chain[level]] = lf
+					order = append(order, chain[level])
+				}
+
+				lf.assets[assetItem.CompositeFigi] = assetItem
+				lf.metrics[metric] = true
+				if start.Before(lf.start) {
+					lf.start = start
+				}
+
+				if end.After(lf.end) {
+					lf.end = end
 				}
 			}
 		}
-	}
 
-	// Fetch misses: one bulk call per provider per chunk year. Each provider
-	// is fetched independently so that providers with different time axes
-	// (e.g. sparse fundamental filing dates vs. dense daily close prices)
-	// do not need to share a common timestamp grid at fetch time. The slab
-	// assembly below builds the union time axis from cached column entries.
-	for year, cacheMiss := range misses {
-		missAssets := make([]asset.Asset, 0, len(cacheMiss.assets))
-		for _, assetItem := range cacheMiss.assets {
-			missAssets = append(missAssets, assetItem)
+		providerMap := make(map[data.BatchProvider]*providerFetch, len(levels))
+
+		for _, bp := range order {
+			lf := levels[bp]
+			pf := &providerFetch{
+				provider: bp,
+				start:    lf.start,
+				end:      lf.end,
+				partial:  lf.start.After(chunkStart) || lf.end.Before(chunkEnd),
+			}
+
+			for _, assetItem := range missAssets {
+				if _, ok := lf.assets[assetItem.CompositeFigi]; ok {
+					pf.assets = append(pf.assets, assetItem)
+				}
+			}
+
+			for _, metric := range missMetrics {
+				if lf.metrics[metric] {
+					pf.metrics = append(pf.metrics, metric)
+				}
+			}
+
+			providerMap[bp] = pf
 		}
 
-		missMetrics := make([]data.Metric, 0, len(cacheMiss.metrics))
-		for metric := range cacheMiss.metrics {
-			missMetrics = append(missMetrics, metric)
+		if err := e.fetchLevel(ctx, year, providerMap, fetched); err != nil {
+			return err
 		}
+	}
 
-		chunkStart := time.Unix(year, 0).In(nyc)
-		chunkEnd := time.Date(chunkStart.Year()+1, 1, 1, 0, 0, 0, 0, nyc).Add(-time.Nanosecond)
+	// Merge each provider chain into one column per (asset, metric).
 
-		log.Debug().
-			Int("missAssets", len(missAssets)).
-			Int("missMetrics", len(missMetrics)).
-			Time("chunkStart", chunkStart).
-			Time("chunkEnd", chunkEnd).
-			Msg("engine.fetchRange cache miss")
+	// Merge each column across its provider chain into the column cache.
+	// Columns no provider returned are cached as empty so we record them
+	// as checked and don't re-fetch them on subsequent calls.
+	filled := 0
 
-		// Group miss metrics by provider so each provider is fetched once.
-		type providerFetch struct {
-			provider data.BatchProvider
-			metrics  []data.Metric
-		}
+	for _, metric := range missMetrics {
+		chain := e.metricProvider[metric]
+		cols := make([]*colCacheEntry, len(chain))
 
-		providerMap := make(map[data.BatchProvider]*providerFetch)
+		for _, assetItem := range missAssets {
+			for idx, bp := range chain {
+				cols[idx] = fetched[providerColumnKey{provider: bp, figi: assetItem.CompositeFigi, metric: metric}]
+			}
 
-		for _, metric := range missMetrics {
-			bp, ok := e.metricProvider[metric]
-			if !ok {
-				return nil, fmt.Errorf("engine: no provider registered for metric %q", metric)
+			merged := mergeChainColumns(cols)
+
+			key := colCacheKey{figi: assetItem.CompositeFigi, metric: metric, chunkStart: year}
+			if len(merged.times) == 0 {
+				if _, already := e.cache.get(key); already {
+					continue
+				}
 			}
 
-			pf, exists := providerMap[bp]
-			if !exists {
-				pf = &providerFetch{provider: bp}
-				providerMap[bp] = pf
+			for idx := range merged.sources {
+				if merged.sources[idx] != 0 {
+					filled++
+				}
 			}
 
-			pf.metrics = append(pf.metrics, metric)
+			e.cache.put(key, merged)
 		}
+	}
+
+	if filled > 0 {
+		log.Debug().
+			Int("cells", filled).
+			Time("chunkStart", chunkStart).
+			Msg("engine.fetchRange filled cells from fallback providers")
+	}
 
-		// Cache metrics that could not be fetched (not in any miss) as empty
-		// so we record them as checked and don't re-fetch on subsequent calls.
-		cachedMetrics := make(map[data.Metric]bool, len(missMetrics))
+	return nil
+}
 
-		// Fetch from all providers concurrently. Each provider uses its
-		// own DB connection so there is no contention. Results are
-		// collected and then decomposed into the cache sequentially.
-		type fetchResult struct {
-			provFetch *providerFetch
-			frame     *data.DataFrame
-			err       error
-		}
+// fetchLevel fetches one precedence level of a chunk miss: it reads
+// what it can from the disk cache, fetches the rest from the providers
+// concurrently, decomposes the results into per-provider columns in
+// fetched, and writes full-chunk results back to the disk cache. Disk
+// locks are held only for the duration of the level.
+func (e *Engine) fetchLevel(ctx context.Context, year int64, providerMap map[data.BatchProvider]*providerFetch, fetched map[providerColumnKey]*colCacheEntry) error {
+	if len(providerMap) == 0 {
+		return nil
+	}
+
+	if e.diskCache != nil {
+		release := e.readDiskChunks(ctx, year, providerMap, fetched)
+		defer release()
+	}
+
+	// Fetch from all providers concurrently. Each provider uses its
+	// own DB connection so there is no contention. Results are
+	// collected and then decomposed into columns sequentially.
+	type fetchResult struct {
+		provFetch *providerFetch
+		frame     *data.DataFrame
+		err       error
+	}
+
+	results := make([]fetchResult, 0, len(providerMap))
 
-		results := make([]fetchResult, 0, len(providerMap))
+	if len(providerMap) == 1 {
+		// Common case: skip goroutine overhead when only one provider.
+		for _, pf := range providerMap {
+			req := data.DataRequest{
+				Assets:    pf.assets,
+				Metrics:   pf.metrics,
+				Start:     pf.start,
+				End:       pf.end,
+				Frequency: data.Daily,
+			}
 
-		if len(providerMap) == 1 {
-			// Common case: skip goroutine overhead when only one provider.
-			for _, pf := range providerMap {
+			df, fetchErr := pf.provider.Fetch(ctx, req)
+			results = append(results, fetchResult{provFetch: pf, frame: df, err: fetchErr})
+		}
+	} else {
+		ch := make(chan fetchResult, len(providerMap))
+
+		for _, pf := range providerMap {
+			go func(pf *providerFetch) {
 				req := data.DataRequest{
-					Assets:    missAssets,
+					Assets:    pf.assets,
 					Metrics:   pf.metrics,
-					Start:     chunkStart,
-					End:       chunkEnd,
+					Start:     pf.start,
+					End:       pf.end,
 					Frequency: data.Daily,
 				}
 
 				df, fetchErr := pf.provider.Fetch(ctx, req)
-				results = append(results, fetchResult{provFetch: pf, frame: df, err: fetchErr})
+				ch <- fetchResult{provFetch: pf, frame: df, err: fetchErr}
+			}(pf)
+		}
+
+		for range len(providerMap) {
+			results = append(results, <-ch)
+		}
+	}
+
+	// Decompose fetched DataFrames into per-provider columns.
+	for _, fr := range results {
+		if fr.err != nil {
+			return fmt.Errorf("engine: provider fetch: %w", fr.err)
+		}
+
+		dfTimes := fr.frame.Times()
+		if len(dfTimes) == 0 {
+			continue
+		}
+
+		timesCopy := make([]time.Time, len(dfTimes))
+		copy(timesCopy, dfTimes)
+
+		for _, assetItem := range fr.frame.AssetList() {
+			for _, metric := range fr.frame.MetricList() {
+				col := fr.frame.Column(assetItem, metric)
+				if col == nil {
+					continue
+				}
+
+				colCopy := make([]float64, len(col))
+				copy(colCopy, col)
+
+				key := providerColumnKey{provider: fr.provFetch.provider, figi: assetItem.CompositeFigi, metric: metric}
+				fetched[key] = &colCacheEntry{times: timesCopy, values: colCopy}
 			}
-		} else {
-			ch := make(chan fetchResult, len(providerMap))
-
-			for _, pf := range providerMap {
-				go func(pf *providerFetch) {
-					req := data.DataRequest{
-						Assets:    missAssets,
-						Metrics:   pf.metrics,
-						Start:     chunkStart,
-						End:       chunkEnd,
-						Frequency: data.Daily,
-					}
+		}
+	}
+
+	if e.diskCache != nil {
+		e.writeDiskChunks(ctx, year, providerMap, fetched)
+	}
+
+	return nil
+}
+
+// fallbackGapDays is the longest run of calendar days without a row that
+// a merged column may have before the next provider in the chain is asked
+// to fill it. It spans a long weekend plus a holiday.
+const fallbackGapDays = 5
+
+// chainGap reports the date range, within one chunk, that the next
+// provider in a chain must cover for col, the merge of the providers
+// before it: the hull of every NaN cell and every run of more than
+// fallbackGapDays without a row, including runs at either end of the
+// chunk. Dates after now are never counted missing. It reports false
+// when col leaves nothing to fill.
+func chainGap(col *colCacheEntry, chunkStart, chunkEnd, now time.Time) (time.Time, time.Time, bool) {
+	limit := chunkEnd
+	if now.Before(limit) {
+		limit = now
+	}
+
+	if len(col.times) == 0 {
+		return chunkStart, chunkEnd, true
+	}
+
+	var start, end time.Time
+
+	gap := false
+
+	extend := func(from, to time.Time) {
+		if !gap || from.Before(start) {
+			start = from
+		}
+
+		if !gap || to.After(end) {
+			end = to
+		}
+
+		gap = true
+	}
+
+	maxRun := fallbackGapDays * 24 * time.Hour
+
+	if col.times[0].Sub(chunkStart) > maxRun {
+		extend(chunkStart, col.times[0])
+	}
+
+	for idx, at := range col.times {
+		if math.IsNaN(col.values[idx]) {
+			extend(at, at)
+		}
+
+		if idx > 0 && at.Sub(col.times[idx-1]) > maxRun {
+			extend(col.times[idx-1], at)
+		}
+	}
+
+	if last := col.times[len(col.times)-1]; limit.Sub(last) > maxRun {
+		extend(last, chunkEnd)
+	}
+
+	return start, end, gap
+}
 
-					df, fetchErr := pf.provider.Fetch(ctx, req)
-					ch <- fetchResult{provFetch: pf, frame: df, err: fetchErr}
-				}(pf)
+// mergeChainColumns merges one column from each provider of a chain,
+// highest precedence first; nil entries are providers that returned
+// nothing. Each timestamp takes the value of the first provider with a
+// non-NaN cell there, and the result records that provider's chain index.
+func mergeChainColumns(cols []*colCacheEntry) *colCacheEntry {
+	var only *colCacheEntry
+
+	present := 0
+
+	for idx, col := range cols {
+		if col == nil || len(col.times) == 0 {
+			continue
+		}
+
+		present++
+
+		if idx == 0 {
+			only = col
+		}
+	}
+
+	switch {
+	case present == 0:
+		return &colCacheEntry{}
+	case present == 1 && only != nil:
+		return only
+	}
+
+	type cell struct {
+		at     time.Time
+		value  float64
+		source uint8
+	}
+
+	cells := make(map[int64]*cell)
+
+	for idx, col := range cols {
+		if col == nil {
+			continue
+		}
+
+		for ii, at := range col.times {
+			value := col.values[ii]
+
+			existing, ok := cells[at.Unix()]
+			if !ok {
+				cells[at.Unix()] = &cell{at: at, value: value, source: uint8(idx)}
+				continue
 			}
 
-			for range len(providerMap) {
-				results = append(results, <-ch)
+			if math.IsNaN(existing.value) && !math.IsNaN(value) {
+				existing.value = value
+				existing.source = uint8(idx)
 			}
 		}
+	}
+
+	ordered := make([]*cell, 0, len(cells))
+	for _, c := range cells {
+		ordered = append(ordered, c)
+	}
+
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].at.Before(ordered[j].at) })
+
+	merged := &colCacheEntry{
+		times:   make([]time.Time, len(ordered)),
+		values:  make([]float64, len(ordered)),
+		sources: make([]uint8, len(ordered)),
+	}
+
+	fromFallback := false
+
+	for idx, c := range ordered {
+		merged.times[idx] = c.at
+		merged.values[idx] = c.value
+		merged.sources[idx] = c.source
+
+		if c.source != 0 {
+			fromFallback = true
+		}
+	}
+
+	if !fromFallback {
+		merged.sources = nil
+	}
+
+	return merged
+}
 
-		// Decompose fetched DataFrames into the column cache.
-		for _, fr := range results {
-			if fr.err != nil {
-				return nil, fmt.Errorf("engine: provider fetch: %w", fr.err)
+// readDiskChunks locks each disk-cached provider's chunk year, moves every
+// column found on disk into fetched, and narrows each provider's fetch to
+// the assets and metrics still missing. Providers with nothing left to
+// fetch are removed from providerMap. Columns read for a data.CacheAuditor
+// are widened to its audit inputs and audited. The returned function
+// releases the locks once the fetched columns have been written back.
+func (e *Engine) readDiskChunks(ctx context.Context, year int64, providerMap map[data.BatchProvider]*providerFetch, fetched map[providerColumnKey]*colCacheEntry) func() {
+	cached := make([]*providerFetch, 0, len(providerMap))
+
+	for _, pf := range providerMap {
+		keyed, ok := pf.provider.(data.CacheKeyed)
+		if !ok {
+			continue
+		}
+
+		pf.diskKey = keyed.CacheKey()
+		if pf.diskKey == "" {
+			continue
+		}
+
+		if auditor, ok := pf.provider.(data.CacheAuditor); ok {
+			pf.metrics = auditor.AuditMetrics(pf.metrics)
+		}
+
+		pf.dimension = e.fundamentalDimension
+		if dp, ok := pf.provider.(interface{ Dimension() string }); ok {
+			pf.dimension = dp.Dimension()
+		}
+
+		cached = append(cached, pf)
+	}
+
+	// Lock in a fixed order so two engines never hold each other's locks.
+	sort.Slice(cached, func(i, j int) bool { return cached[i].diskKey < cached[j].diskKey })
+
+	unlocks := make([]func(), 0, len(cached))
+
+	for _, pf := range cached {
+		unlocks = append(unlocks, e.diskCache.lockGroup(pf.diskKey, year))
+
+		assets := make(map[string]asset.Asset)
+		metrics := make(map[data.Metric]bool)
+
+		loaded := false
+
+		for _, assetItem := range pf.assets {
+			for _, metric := range pf.metrics {
+				entry, ok := e.diskCache.load(pf.diskCacheKey(assetItem.CompositeFigi, metric, year))
+				if ok {
+					fetched[providerColumnKey{provider: pf.provider, figi: assetItem.CompositeFigi, metric: metric}] = entry
+					loaded = true
+
+					continue
+				}
+
+				assets[assetItem.CompositeFigi] = assetItem
+				metrics[metric] = true
 			}
+		}
 
-			dfTimes := fr.frame.Times()
-			if len(dfTimes) == 0 {
-				empty := &colCacheEntry{}
+		if auditor, ok := pf.provider.(data.CacheAuditor); ok && loaded {
+			if err := auditDiskChunks(auditor, pf, fetched); err != nil {
+				zerolog.Ctx(ctx).Warn().Err(err).Msg("engine: audit disk cache")
+			}
+		}
 
-				for _, assetItem := range missAssets {
-					for _, metric := range fr.provFetch.metrics {
-						key := colCacheKey{figi: assetItem.CompositeFigi, metric: metric, chunkStart: year}
-						e.cache.put(key, empty)
+		if len(assets) == 0 {
+			delete(providerMap, pf.provider)
+			continue
+		}
 
-						cachedMetrics[metric] = true
-					}
+		pf.assets = pf.assets[:0:0]
+		for _, assetItem := range assets {
+			pf.assets = append(pf.assets, assetItem)
+		}
+
+		pf.metrics = pf.metrics[:0:0]
+		for metric := range metrics {
+			pf.metrics = append(pf.metrics, metric)
+		}
+	}
+
+	return func() {
+		for idx := len(unlocks) - 1; idx >= 0; idx-- {
+			unlocks[idx]()
+		}
+	}
+}
+
+// auditDiskChunks assembles the columns read from disk for pf into a
+// daily frame and hands it to the provider's audit.
+func auditDiskChunks(auditor data.CacheAuditor, pf *providerFetch, fetched map[providerColumnKey]*colCacheEntry) error {
+	timeSet := make(map[int64]time.Time)
+
+	for _, assetItem := range pf.assets {
+		for _, metric := range pf.metrics {
+			if entry, ok := fetched[providerColumnKey{provider: pf.provider, figi: assetItem.CompositeFigi, metric: metric}]; ok {
+				for _, t := range entry.times {
+					timeSet[t.Unix()] = t
 				}
-			} else {
-				timesCopy := make([]time.Time, len(dfTimes))
-				copy(timesCopy, dfTimes)
-
-				for _, assetItem := range fr.frame.AssetList() {
-					for _, metric := range fr.frame.MetricList() {
-						col := fr.frame.Column(assetItem, metric)
-						if col == nil {
-							continue
-						}
+			}
+		}
+	}
 
-						colCopy := make([]float64, len(col))
-						copy(colCopy, col)
+	if len(timeSet) == 0 {
+		return nil
+	}
 
-						key := colCacheKey{figi: assetItem.CompositeFigi, metric: metric, chunkStart: year}
-						e.cache.put(key, &colCacheEntry{times: timesCopy, values: colCopy})
+	times := slices.SortedFunc(maps.Values(timeSet), func(left, right time.Time) int { return left.Compare(right) })
 
-						cachedMetrics[metric] = true
-					}
+	timeIdx := make(map[int64]int, len(times))
+	for idx, t := range times {
+		timeIdx[t.Unix()] = idx
+	}
+
+	columns := make([][]float64, 0, len(pf.assets)*len(pf.metrics))
+
+	for _, assetItem := range pf.assets {
+		for _, metric := range pf.metrics {
+			col := make([]float64, len(times))
+			for idx := range col {
+				col[idx] = math.NaN()
+			}
+
+			if entry, ok := fetched[providerColumnKey{provider: pf.provider, figi: assetItem.CompositeFigi, metric: metric}]; ok {
+				for idx, t := range entry.times {
+					col[timeIdx[t.Unix()]] = entry.values[idx]
 				}
 			}
+
+			columns = append(columns, col)
 		}
+	}
 
-		// Any miss metric not placed into the cache (because its provider
-		// returned an empty result without listing it in MetricList) needs an
-		// empty sentinel so we don't re-fetch it on the next call.
-		for _, assetItem := range missAssets {
-			for _, metric := range missMetrics {
-				if cachedMetrics[metric] {
-					continue
+	df, err := data.NewDataFrame(times, pf.assets, pf.metrics, data.Daily, columns)
+	if err != nil {
+		return err
+	}
+
+	auditor.AuditCached(df)
+
+	return nil
+}
+
+// writeDiskChunks stores the columns each disk-cached provider just
+// fetched, with an empty column for anything the provider did not return.
+// Write failures are logged and otherwise ignored: the disk cache is an
+// optimization and the data is already in memory.
+func (e *Engine) writeDiskChunks(ctx context.Context, year int64, providerMap map[data.BatchProvider]*providerFetch, fetched map[providerColumnKey]*colCacheEntry) {
+	for _, pf := range providerMap {
+		if pf.diskKey == "" || pf.partial {
+			continue
+		}
+
+		for _, assetItem := range pf.assets {
+			for _, metric := range pf.metrics {
+				entry, ok := fetched[providerColumnKey{provider: pf.provider, figi: assetItem.CompositeFigi, metric: metric}]
+				if !ok {
+					entry = &colCacheEntry{}
+				}
+
+				if err := e.diskCache.store(pf.diskCacheKey(assetItem.CompositeFigi, metric, year), entry); err != nil {
+					zerolog.Ctx(ctx).Warn().Err(err).Msg("engine: write disk cache")
+					return
 				}
+			}
+		}
+	}
+}
+
+// fetchRange is the shared implementation for Fetch and FetchAt.
+// It checks the per-column cache, bulk-fetches misses grouped by
+// calendar-year chunk, and assembles the result. Requests that include
+// derived fundamentals are handed to fetchDerived.
+func (e *Engine) fetchRange(ctx context.Context, assets []asset.Asset, metrics []data.Metric, rangeStart, rangeEnd time.Time) (*data.DataFrame, error) {
+	if slices.ContainsFunc(metrics, data.IsDerived) {
+		return e.fetchDerived(ctx, assets, metrics, rangeStart, rangeEnd)
+	}
+
+	log := zerolog.Ctx(ctx)
 
+	years := chunkYears(rangeStart, rangeEnd)
+
+	// Identify cache misses grouped by chunk year.
+	type chunkMiss struct {
+		assets  map[string]asset.Asset // figi -> asset
+		metrics map[data.Metric]bool
+	}
+
+	misses := make(map[int64]*chunkMiss)
+
+	for _, year := range years {
+		for _, assetItem := range assets {
+			for _, metric := range metrics {
 				key := colCacheKey{figi: assetItem.CompositeFigi, metric: metric, chunkStart: year}
-				if _, already := e.cache.get(key); !already {
-					e.cache.put(key, &colCacheEntry{})
+				if _, ok := e.cache.get(key); !ok {
+					cacheMiss, exists := misses[year]
+					if !exists {
+						cacheMiss = &chunkMiss{
+							assets:  make(map[string]asset.Asset),
+							metrics: make(map[data.Metric]bool),
+						}
+						misses[year] = cacheMiss
+					}
+
+					cacheMiss.assets[assetItem.CompositeFigi] = assetItem
+					cacheMiss.metrics[metric] = true
 				}
 			}
 		}
 	}
 
+	// Fetch misses: one bulk call per provider per chunk year. Each provider
+	// is fetched independently so that providers with different time axes
+	// (e.g. sparse fundamental filing dates vs. dense daily close prices)
+	// do not need to share a common timestamp grid at fetch time. The slab
+	// assembly below builds the union time axis from cached column entries.
+	for year, cacheMiss := range misses {
+		missAssets := make([]asset.Asset, 0, len(cacheMiss.assets))
+		for _, assetItem := range cacheMiss.assets {
+			missAssets = append(missAssets, assetItem)
+		}
+
+		missMetrics := make([]data.Metric, 0, len(cacheMiss.metrics))
+		for metric := range cacheMiss.metrics {
+			missMetrics = append(missMetrics, metric)
+		}
+
+		if err := e.fetchChunk(ctx, year, missAssets, missMetrics); err != nil {
+			return nil, err
+		}
+	}
+
 	// Fast path: point-in-time queries (rangeStart == rangeEnd) skip the
 	// union-time-axis rebuild and binary-search cached year chunks directly.
 	if rangeStart.Equal(rangeEnd) {
@@ -988,6 +1760,15 @@ func (e *Engine) assemblePointInTime(assets []asset.Asset, metrics []data.Metric
 func (e *Engine) Close() error {
 	var firstErr error
 
+	// Tear down any live stream subscription before closing providers.
+	if e.stream != nil {
+		e.stream.stop()
+	}
+
+	if e.algoBroker != nil {
+		_ = e.algoBroker.Close()
+	}
+
 	// Close the broker.
 	if e.broker != nil {
 		if err := e.broker.Close(); err != nil {
@@ -1004,11 +1785,12 @@ func (e *Engine) Close() error {
 	return firstErr
 }
 
-// Prices implements broker.PriceProvider. It returns close, high, low,
-// and volume prices plus dividend/split data for the requested assets at
-// the engine's current simulation date. High and low are needed by
-// EvaluatePending for intrabar bracket order evaluation. Volume is needed
-// by the MarketImpact fill adjuster.
+// Prices implements broker.PriceProvider. It returns open, close, high,
+// low, and volume prices plus dividend/split data for the requested assets
+// at the engine's current simulation date. High and low are needed by
+// EvaluatePending for intrabar bracket order evaluation. Open is needed
+// by the NextOpen and OHLCPath fill models. Volume is needed by the
+// MarketImpact fill adjuster.
 //
 // When the engine is mid intra-day firing (currentTime carries an
 // hour/minute component beyond the day boundary), Prices returns the
@@ -1020,10 +1802,21 @@ func (e *Engine) Prices(ctx context.Context, assets ...asset.Asset) (*data.DataF
 		return e.nextMinuteBar(ctx, assets)
 	}
 
-	return e.FetchAt(ctx, assets, e.currentDate, []data.Metric{
+	return e.fetchAt(ctx, assets, e.currentDate, e.withOpen(
 		data.MetricClose, data.MetricHigh, data.MetricLow,
 		data.Volume, data.Dividend, data.SplitFactor,
-	})
+	))
+}
+
+// withOpen returns metrics with MetricOpen added when a provider serves
+// it. Only the next-bar fill models need the open, so engines wired to
+// providers without it keep filling at the close.
+func (e *Engine) withOpen(metrics ...data.Metric) []data.Metric {
+	if _, ok := e.metricProvider[data.MetricOpen]; ok {
+		return append([]data.Metric{data.MetricOpen}, metrics...)
+	}
+
+	return metrics
 }
 
 // capDailyRangeEnd caps the end of a daily data request during an
@@ -1117,7 +1910,7 @@ func (e *Engine) nextMinuteBar(ctx context.Context, assets []asset.Asset) (*data
 	// every call after the first from a per-firing cache so a remote source
 	// sees one round-trip instead of N+1.
 	window, err := e.intradayFillWindow(ctx, provider, assets,
-		[]data.Metric{data.MetricClose, data.MetricHigh, data.MetricLow, data.Volume},
+		[]data.Metric{data.MetricOpen, data.MetricClose, data.MetricHigh, data.MetricLow, data.Volume},
 		start, end)
 	if err != nil {
 		return nil, err
@@ -1310,10 +2103,10 @@ func (e *Engine) prefetchHousekeepingPrices(ctx context.Context, acct portfolio.
 		assets = append(assets, benchmark)
 	}
 
-	_, err := e.FetchAt(ctx, assets, date, []data.Metric{
+	_, err := e.fetchAt(ctx, assets, date, e.withOpen(
 		data.MetricClose, data.AdjClose, data.MetricHigh, data.MetricLow,
 		data.Volume, data.Dividend, data.SplitFactor,
-	})
+	))
 	if err != nil {
 		return fmt.Errorf("prefetch housekeeping prices: %w", err)
 	}
@@ -0,0 +1,222 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/portfolio"
+	"github.com/rs/zerolog"
+)
+
+// FillHandler is an optional interface strategies may implement to react to
+// executions. The engine calls OnFill once for every fill drained from the
+// broker, including failed fills (fill.Err is set), with the order the fill
+// belongs to and a fresh batch for follow-up orders. Fills are delivered
+// after the batch that produced them has executed, never while it is still
+// being submitted.
+type FillHandler interface {
+	OnFill(ctx context.Context, eng *Engine, port portfolio.Portfolio, batch *portfolio.Batch, order broker.Order, fill broker.Fill) error
+}
+
+// BarHandler is an optional interface strategies may implement to run on
+// every bar, not only on scheduled dates. Backtests call OnBar at the close
+// of every trading day; live sessions call it on every firing, including the
+// daily close firing on days the strategy is not scheduled.
+type BarHandler interface {
+	OnBar(ctx context.Context, eng *Engine, port portfolio.Portfolio, batch *portfolio.Batch) error
+}
+
+// CorporateActionHandler is an optional interface strategies may implement
+// to react to dividends and splits on their holdings. The engine calls
+// OnCorporateAction after the action has been applied to the portfolio,
+// with a fresh batch for follow-up orders. For a split, action.Price holds
+// the split factor.
+type CorporateActionHandler interface {
+	OnCorporateAction(ctx context.Context, eng *Engine, port portfolio.Portfolio, batch *portfolio.Batch, action broker.Transaction) error
+}
+
+// maxEventPasses bounds how many rounds of callbacks dispatchEvents runs
+// for one step. Orders placed from OnFill produce fills of their own, so a
+// strategy that always answers a fill with another order would otherwise
+// loop forever.
+const maxEventPasses = 16
+
+// eventQueue collects the fills and corporate actions the account reports
+// until the engine delivers them to the strategy.
+type eventQueue struct {
+	fills   []queuedFill
+	actions []broker.Transaction
+}
+
+type queuedFill struct {
+	order broker.Order
+	fill  broker.Fill
+}
+
+func (q *eventQueue) OrderFilled(order broker.Order, fill broker.Fill) {
+	q.fills = append(q.fills, queuedFill{order: order, fill: fill})
+}
+
+func (q *eventQueue) CorporateActionApplied(action broker.Transaction) {
+	q.actions = append(q.actions, action)
+}
+
+// batchExecutor submits a batch created for a strategy callback.
+type batchExecutor func(ctx context.Context, batch *portfolio.Batch) error
+
+// watchEvents installs an event queue on acct when the strategy implements
+// FillHandler or CorporateActionHandler.
+func (e *Engine) watchEvents(acct portfolio.PortfolioManager) {
+	e.events = nil
+
+	_, onFill := e.strategy.(FillHandler)
+	_, onAction := e.strategy.(CorporateActionHandler)
+
+	if !onFill && !onAction {
+		return
+	}
+
+	e.events = &eventQueue{}
+	acct.SetObserver(e.events)
+}
+
+// dispatchEvents delivers queued corporate actions and then fills to the
+// strategy, one callback and batch per event. Batches with orders are
+// executed at once, and any fills they produce are delivered in the next
+// pass.
+func (e *Engine) dispatchEvents(ctx context.Context, acct portfolio.PortfolioManager, at time.Time, execute batchExecutor) error {
+	if e.events == nil {
+		return nil
+	}
+
+	fillHandler, _ := e.strategy.(FillHandler)
+	actionHandler, _ := e.strategy.(CorporateActionHandler)
+
+	for range maxEventPasses {
+		actions, fills := e.events.actions, e.events.fills
+		e.events.actions, e.events.fills = nil, nil
+
+		if len(actions) == 0 && len(fills) == 0 {
+			return nil
+		}
+
+		if actionHandler != nil {
+			for _, action := range actions {
+				if err := e.runCallback(ctx, acct, at, execute, func(batch *portfolio.Batch) error {
+					return actionHandler.OnCorporateAction(ctx, e, acct, batch, action)
+				}); err != nil {
+					return fmt.Errorf("engine: strategy %q corporate action %s on %s: %w",
+						e.strategy.Name(), action.Type, action.Asset.Ticker, err)
+				}
+			}
+		}
+
+		if fillHandler != nil {
+			for _, queued := range fills {
+				if err := e.runCallback(ctx, acct, at, execute, func(batch *portfolio.Batch) error {
+					return fillHandler.OnFill(ctx, e, acct, batch, queued.order, queued.fill)
+				}); err != nil {
+					return fmt.Errorf("engine: strategy %q fill for order %s: %w",
+						e.strategy.Name(), queued.order.ID, err)
+				}
+			}
+		}
+	}
+
+	return fmt.Errorf("engine: strategy %q events did not settle after %d passes", e.strategy.Name(), maxEventPasses)
+}
+
+// runBar calls the strategy's OnBar, when it implements BarHandler, and
+// executes the resulting batch.
+func (e *Engine) runBar(ctx context.Context, acct portfolio.PortfolioManager, at time.Time, execute batchExecutor) error {
+	handler, ok := e.strategy.(BarHandler)
+	if !ok {
+		return nil
+	}
+
+	if err := e.runCallback(ctx, acct, at, execute, func(batch *portfolio.Batch) error {
+		return handler.OnBar(ctx, e, acct, batch)
+	}); err != nil {
+		return fmt.Errorf("engine: strategy %q bar on %v: %w", e.strategy.Name(), at, err)
+	}
+
+	return nil
+}
+
+// runCallback creates a batch at the given time, passes it to callback,
+// and executes it unless the callback left it empty. Open orders are not
+// canceled: callbacks add to whatever the strategy already has working.
+func (e *Engine) runCallback(ctx context.Context, acct portfolio.PortfolioManager, at time.Time, execute batchExecutor, callback func(batch *portfolio.Batch) error) error {
+	batch := acct.NewBatch(at)
+	if err := callback(batch); err != nil {
+		return err
+	}
+
+	if len(batch.Orders) == 0 && len(batch.Annotations) == 0 {
+		return nil
+	}
+
+	return execute(ctx, batch)
+}
+
+// backtestExecutor executes callback batches during a backtest: it warms
+// the broker's price cache, executes the batch, and runs the strategy's
+// Reconciler.
+func (e *Engine) backtestExecutor(acct portfolio.PortfolioManager) batchExecutor {
+	return func(ctx context.Context, batch *portfolio.Batch) error {
+		if err := e.prefetchBrokerPrices(ctx, batch.Orders); err != nil {
+			return fmt.Errorf("prefetch broker prices: %w", err)
+		}
+
+		if err := acct.ExecuteBatch(ctx, batch); err != nil {
+			return fmt.Errorf("execute batch: %w", err)
+		}
+
+		return e.reconcileBatch(ctx, e.strategy, acct, batch)
+	}
+}
+
+// dispatchLiveEvents delivers queued events during a live firing. Failures
+// are logged so one bad callback does not end the session.
+func (e *Engine) dispatchLiveEvents(ctx context.Context, acct portfolio.PortfolioManager) {
+	if err := e.dispatchEvents(ctx, acct, e.currentDate, acct.ExecuteBatch); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("strategy event callback failed")
+	}
+}
+
+// runLiveBar runs the strategy's OnBar during a live firing and delivers
+// the fills it produces. Failures are logged.
+func (e *Engine) runLiveBar(ctx context.Context, acct portfolio.PortfolioManager) {
+	if _, ok := e.strategy.(BarHandler); !ok {
+		return
+	}
+
+	if sb, ok := e.broker.(*SimulatedBroker); ok {
+		sb.SetPriceProvider(e.livePriceProvider(), e.currentDate)
+	}
+
+	if err := e.runBar(ctx, acct, e.currentDate, acct.ExecuteBatch); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("strategy bar failed")
+		return
+	}
+
+	e.dispatchLiveEvents(ctx, acct)
+	e.syncLiveStream(ctx, acct)
+}
@@ -0,0 +1,187 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/engine"
+	"github.com/penny-vault/pvbt/portfolio"
+)
+
+// eventStrategy buys a fixed quantity on its first Compute and records the
+// callbacks it receives. On the first fill it sells a third of the
+// position from the OnFill batch.
+type eventStrategy struct {
+	target       asset.Asset
+	computeCount int
+	fills        []broker.Fill
+	fillOrders   []broker.Order
+	bars         []time.Time
+	actions      []broker.Transaction
+	actionHolds  []float64
+}
+
+func (s *eventStrategy) Name() string           { return "events" }
+func (s *eventStrategy) Setup(_ *engine.Engine) {}
+func (s *eventStrategy) Describe() engine.StrategyDescription {
+	return engine.StrategyDescription{Schedule: "@weekbegin"}
+}
+
+func (s *eventStrategy) Compute(ctx context.Context, _ *engine.Engine, _ portfolio.Portfolio, batch *portfolio.Batch) error {
+	s.computeCount++
+	if s.computeCount == 1 {
+		batch.Order(ctx, s.target, portfolio.Buy, 30)
+	}
+
+	return nil
+}
+
+func (s *eventStrategy) OnFill(ctx context.Context, _ *engine.Engine, _ portfolio.Portfolio, batch *portfolio.Batch, order broker.Order, fill broker.Fill) error {
+	s.fills = append(s.fills, fill)
+	s.fillOrders = append(s.fillOrders, order)
+
+	if len(s.fills) == 1 {
+		batch.Order(ctx, s.target, portfolio.Sell, 10, portfolio.WithJustification("trim after fill"))
+	}
+
+	return nil
+}
+
+func (s *eventStrategy) OnBar(_ context.Context, eng *engine.Engine, _ portfolio.Portfolio, _ *portfolio.Batch) error {
+	s.bars = append(s.bars, eng.CurrentDate())
+
+	return nil
+}
+
+func (s *eventStrategy) OnCorporateAction(_ context.Context, _ *engine.Engine, port portfolio.Portfolio, _ *portfolio.Batch, action broker.Transaction) error {
+	s.actions = append(s.actions, action)
+	s.actionHolds = append(s.actionHolds, port.Position(s.target))
+
+	return nil
+}
+
+var _ = Describe("Event callbacks", func() {
+	var (
+		testStock     asset.Asset
+		assetProvider *mockAssetProvider
+		allMetrics    []data.Metric
+		strategy      *eventStrategy
+		fund          portfolio.Portfolio
+	)
+
+	BeforeEach(func() {
+		testStock = asset.Asset{CompositeFigi: "FIGI-EVNT", Ticker: "EVNT"}
+		assetProvider = &mockAssetProvider{assets: []asset.Asset{testStock}}
+		allMetrics = []data.Metric{data.MetricClose, data.AdjClose, data.Dividend, data.MetricHigh, data.MetricLow, data.SplitFactor, data.Volume}
+
+		// Daily bars from January 1 to February 9, 2024 at $50, with a
+		// $0.25 dividend on January 10 and a 2-for-1 split on January 17.
+		dataStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		nDays := 40
+
+		times := make([]time.Time, nDays)
+		vals := make([]float64, nDays*len(allMetrics))
+
+		for dayIdx := range times {
+			day := dataStart.AddDate(0, 0, dayIdx)
+			times[dayIdx] = time.Date(day.Year(), day.Month(), day.Day(), 16, 0, 0, 0, time.UTC)
+
+			price := 50.0
+			if !day.Before(time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC)) {
+				price = 25.0
+			}
+
+			dividend := 0.0
+			if day.Equal(time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)) {
+				dividend = 0.25
+			}
+
+			split := 1.0
+			if day.Equal(time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC)) {
+				split = 2.0
+			}
+
+			vals[0*nDays+dayIdx] = price
+			vals[1*nDays+dayIdx] = price
+			vals[2*nDays+dayIdx] = dividend
+			vals[3*nDays+dayIdx] = price
+			vals[4*nDays+dayIdx] = price
+			vals[5*nDays+dayIdx] = split
+			vals[6*nDays+dayIdx] = 1_000_000.0
+		}
+
+		testDF, err := data.NewDataFrame(times, []asset.Asset{testStock}, allMetrics, data.Daily, data.SlabToColumns(vals, len(allMetrics), nDays))
+		Expect(err).NotTo(HaveOccurred())
+
+		strategy = &eventStrategy{target: testStock}
+		eng := engine.New(strategy,
+			engine.WithDataProvider(data.NewTestProvider(allMetrics, testDF)),
+			engine.WithAssetProvider(assetProvider),
+			engine.WithAccount(portfolio.New(portfolio.WithCash(10_000, time.Time{}))),
+		)
+
+		fund, err = eng.Backtest(context.Background(),
+			time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("calls OnBar at the close of every trading day", func() {
+		// The backtest ends at midnight UTC on January 31, which is the
+		// evening of January 30 in New York: 21 weekdays.
+		Expect(strategy.bars).To(HaveLen(21))
+		Expect(strategy.bars[0].Format("2006-01-02")).To(Equal("2024-01-02"))
+		Expect(strategy.bars[20].Format("2006-01-02")).To(Equal("2024-01-30"))
+	})
+
+	It("calls OnFill for each fill and executes its follow-up orders", func() {
+		Expect(strategy.fills).To(HaveLen(2))
+		Expect(strategy.fillOrders[0].Side).To(Equal(broker.Buy))
+		Expect(strategy.fills[0].Qty).To(Equal(30.0))
+		Expect(strategy.fillOrders[1].Side).To(Equal(broker.Sell))
+		Expect(strategy.fills[1].Qty).To(Equal(10.0))
+
+		var trims int
+
+		for _, txn := range fund.Transactions() {
+			if txn.Type == asset.SellTransaction && txn.Justification == "trim after fill" {
+				trims++
+			}
+		}
+
+		Expect(trims).To(Equal(1))
+	})
+
+	It("calls OnCorporateAction after each dividend and split is applied", func() {
+		Expect(strategy.actions).To(HaveLen(2))
+
+		Expect(strategy.actions[0].Type).To(Equal(asset.DividendTransaction))
+		Expect(strategy.actions[0].Amount).To(BeNumerically("~", 5.0, 1e-9))
+		Expect(strategy.actionHolds[0]).To(Equal(20.0))
+
+		Expect(strategy.actions[1].Type).To(Equal(asset.SplitTransaction))
+		Expect(strategy.actions[1].Price).To(Equal(2.0))
+		Expect(strategy.actionHolds[1]).To(Equal(40.0))
+	})
+})
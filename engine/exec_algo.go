@@ -0,0 +1,516 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/rs/zerolog"
+)
+
+// algoBarMetrics are the minute-bar metrics algorithmic orders are worked
+// against.
+var algoBarMetrics = []data.Metric{data.MetricOpen, data.MetricClose, data.MetricHigh, data.MetricLow, data.Volume}
+
+// vwapProfileSessions is the number of prior sessions whose minute volumes
+// are averaged into a VWAP order's volume profile.
+const vwapProfileSessions = 20
+
+// vwapProfileLookback is the calendar span searched for those sessions.
+const vwapProfileLookback = 45 * 24 * time.Hour
+
+// algoOrder tracks a parent order the simulated broker is working as child
+// orders over minute bars. Child fills carry the parent's ID, so the
+// account sees them as partial fills of the order the strategy placed.
+type algoOrder struct {
+	order     broker.Order
+	submitted time.Time
+	arrival   float64
+	seq       int
+
+	// start is the first minute bar after submission, zero until it
+	// prints. TWAP and VWAP windows run from it for ExecDuration.
+	start     time.Time
+	nextSlice int
+	// profile weights VWAP slices; nil sizes them equally.
+	profile []float64
+	// cursor is the last minute bar a POV order traded against.
+	cursor time.Time
+
+	filled   float64
+	notional float64
+	children int
+	lastBar  time.Time
+	stopped  bool
+}
+
+// remaining returns the shares still to fill.
+func (algo *algoOrder) remaining() float64 {
+	return algo.order.Qty - algo.filled
+}
+
+// record adds a child fill to the parent's running totals.
+func (algo *algoOrder) record(fill broker.Fill) {
+	algo.filled += fill.Qty
+	algo.notional += fill.Qty * fill.Price
+	algo.children++
+}
+
+// report summarizes the parent order's execution so far.
+func (algo *algoOrder) report() broker.ExecutionReport {
+	report := broker.ExecutionReport{
+		OrderID:      algo.order.ID,
+		Asset:        algo.order.Asset,
+		Side:         algo.order.Side,
+		Algo:         algo.order.ExecAlgo,
+		Ordered:      algo.order.Qty,
+		Filled:       algo.filled,
+		ArrivalPrice: algo.arrival,
+		Slices:       algo.children,
+		Start:        algo.start,
+		End:          algo.lastBar,
+	}
+
+	if algo.filled > 0 {
+		report.AvgPrice = algo.notional / algo.filled
+	}
+
+	return report
+}
+
+// validateExecAlgo checks an algorithmic order's parameters. Orders are
+// worked in share slices, so they must be sized in shares.
+func validateExecAlgo(order broker.Order) error {
+	switch order.ExecAlgo {
+	case broker.ExecTWAP, broker.ExecVWAP:
+		if order.ExecDuration <= 0 || order.ExecSlices < 1 {
+			return fmt.Errorf("%s order for %s needs a positive duration and at least one slice",
+				order.ExecAlgo, order.Asset.Ticker)
+		}
+	case broker.ExecPOV:
+		if order.ExecParticipation <= 0 || order.ExecParticipation > 100 {
+			return fmt.Errorf("%s order for %s needs a participation between 0 and 100 percent",
+				order.ExecAlgo, order.Asset.Ticker)
+		}
+	}
+
+	if order.Qty <= 0 {
+		return fmt.Errorf("%s order for %s must be sized in shares", order.ExecAlgo, order.Asset.Ticker)
+	}
+
+	return nil
+}
+
+// wholeShares reports whether order trades in whole shares.
+func wholeShares(order broker.Order) bool {
+	return !order.Fractional && !order.Asset.IsCrypto()
+}
+
+// sliceTargets returns the cumulative quantity an order of total shares
+// should have filled by the end of each slice, splitting it in proportion
+// to weights. Equal weights are used when weights is nil or sums to zero.
+// The last target is always total.
+func sliceTargets(total float64, weights []float64, slices int, whole bool) []float64 {
+	var sum float64
+	for _, weight := range weights {
+		sum += weight
+	}
+
+	targets := make([]float64, slices)
+
+	var cumulative float64
+
+	for idx := range targets {
+		if sum > 0 {
+			cumulative += weights[idx] / sum
+		} else {
+			cumulative = float64(idx+1) / float64(slices)
+		}
+
+		targets[idx] = total * cumulative
+		if whole {
+			targets[idx] = math.Floor(targets[idx] + 1e-9)
+		}
+	}
+
+	targets[slices-1] = total
+
+	return targets
+}
+
+// sliceIndex returns which of slices equal parts of the window starting at
+// start and lasting duration contains t.
+func sliceIndex(start, t time.Time, duration time.Duration, slices int) int {
+	return int(int64(t.Sub(start)) * int64(slices) / int64(duration))
+}
+
+// sliceEnd returns the end of slice idx of the window.
+func sliceEnd(start time.Time, duration time.Duration, slices, idx int) time.Time {
+	return start.Add(time.Duration(int64(duration) * int64(idx+1) / int64(slices)))
+}
+
+// volumeProfile returns the average volume traded in each of count equal
+// parts of a window lasting duration, placed at the time of day of start,
+// over the vwapProfileSessions sessions before start's day. Only earlier
+// sessions are read, so sizing a VWAP order never looks ahead. It returns
+// nil when no volume traded in those windows.
+func volumeProfile(ctx context.Context, provider IntradayProvider, ast asset.Asset, start time.Time,
+	duration time.Duration, count int,
+) []float64 {
+	if provider == nil {
+		return nil
+	}
+
+	loc := start.Location()
+	dayStart := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	offset := start.Sub(dayStart)
+
+	bars, err := provider.IntradayFetch(ctx, []asset.Asset{ast}, []data.Metric{data.Volume},
+		dayStart.Add(-vwapProfileLookback), dayStart, nil)
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().
+			Err(err).
+			Str("asset", ast.Ticker).
+			Msg("execution algorithm: fetch volume profile failed")
+
+		return nil
+	}
+
+	volumes := bars.Column(ast, data.Volume)
+	sessions := make(map[time.Time][]float64)
+
+	for idx, barTime := range bars.Times() {
+		if idx >= len(volumes) || math.IsNaN(volumes[idx]) {
+			continue
+		}
+
+		local := barTime.In(loc)
+		day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+		windowStart := day.Add(offset)
+		if local.Before(windowStart) || !local.Before(windowStart.Add(duration)) {
+			continue
+		}
+
+		if sessions[day] == nil {
+			sessions[day] = make([]float64, count)
+		}
+
+		sessions[day][sliceIndex(windowStart, local, duration, count)] += volumes[idx]
+	}
+
+	days := slices.SortedFunc(maps.Keys(sessions), time.Time.Compare)
+	if len(days) > vwapProfileSessions {
+		days = days[len(days)-vwapProfileSessions:]
+	}
+
+	if len(days) == 0 {
+		return nil
+	}
+
+	profile := make([]float64, count)
+
+	for _, day := range days {
+		for idx, volume := range sessions[day] {
+			profile[idx] += volume / float64(len(days))
+		}
+	}
+
+	return profile
+}
+
+// tradedBars returns the times of the minute bars in bars where ast has a
+// close.
+func tradedBars(bars *data.DataFrame, ast asset.Asset) []time.Time {
+	closes := bars.Column(ast, data.MetricClose)
+	times := bars.Times()
+
+	traded := make([]time.Time, 0, len(times))
+
+	for idx, closePrice := range closes {
+		if !math.IsNaN(closePrice) && closePrice > 0 {
+			traded = append(traded, times[idx])
+		}
+	}
+
+	return traded
+}
+
+// SetIntradayProvider supplies the minute bars that orders with an
+// execution algorithm are worked against, and the clock that stamps when
+// they were submitted.
+func (b *SimulatedBroker) SetIntradayProvider(provider IntradayProvider, now func() time.Time) {
+	b.intraday = provider
+	b.clock = now
+}
+
+// ExecutionReports returns a report for each algorithmic order the broker
+// finished working.
+func (b *SimulatedBroker) ExecutionReports() []broker.ExecutionReport {
+	return b.reports
+}
+
+// submitAlgo accepts an order with an execution algorithm. Its arrival
+// price is the current price; its child orders are worked from the first
+// minute bar after submission as EvaluatePending runs on later bars.
+func (b *SimulatedBroker) submitAlgo(ctx context.Context, order broker.Order) error {
+	if err := validateExecAlgo(order); err != nil {
+		b.failOrder(order, err)
+
+		return nil
+	}
+
+	if b.intraday == nil {
+		b.failOrder(order, fmt.Errorf("%s order for %s needs an IntradayProvider for minute bars",
+			order.ExecAlgo, order.Asset.Ticker))
+
+		return nil
+	}
+
+	var arrival float64
+
+	if df, err := b.prices.Prices(ctx, order.Asset); err == nil {
+		if closePrice := df.Value(order.Asset, data.MetricClose); !math.IsNaN(closePrice) {
+			arrival = closePrice
+		}
+	}
+
+	submitted := b.date
+	if b.clock != nil {
+		submitted = b.clock()
+	}
+
+	b.workingSeq++
+	b.algos[order.ID] = &algoOrder{order: order, submitted: submitted, arrival: arrival, seq: b.workingSeq}
+
+	return nil
+}
+
+// evaluateAlgoOrders works each algorithmic order against the minute bars
+// that have printed since it was submitted, filling the child orders that
+// are due. An order finishes when it is filled, its window closes, or a
+// child fails.
+func (b *SimulatedBroker) evaluateAlgoOrders() {
+	if b.prices == nil || len(b.algos) == 0 {
+		return
+	}
+
+	ctx := context.Background()
+
+	algos := make([]*algoOrder, 0, len(b.algos))
+	for _, algo := range b.algos {
+		algos = append(algos, algo)
+	}
+
+	sort.Slice(algos, func(i, j int) bool { return algos[i].seq < algos[j].seq })
+
+	for _, algo := range algos {
+		bars, err := b.intraday.IntradayFetch(ctx, []asset.Asset{algo.order.Asset}, algoBarMetrics,
+			algo.submitted.Add(time.Minute), b.date, nil)
+		if err != nil {
+			zerolog.Ctx(ctx).Warn().
+				Err(err).
+				Str("asset", algo.order.Asset.Ticker).
+				Msg("execution algorithm: fetch minute bars failed")
+
+			continue
+		}
+
+		var done bool
+
+		if algo.order.ExecAlgo == broker.ExecPOV {
+			done = b.workPOV(ctx, algo, bars)
+		} else {
+			done = b.workSlices(ctx, algo, bars)
+		}
+
+		if done || algo.stopped || algo.remaining() <= 1e-9 {
+			b.completeAlgo(ctx, algo)
+		}
+	}
+}
+
+// workSlices fills the TWAP or VWAP slices whose first minute bar has
+// printed, each at that bar. A slice with no trades carries its quantity
+// to the next one. VWAP sizes slices by the volume profile of the prior
+// sessions, taken when the window opens. It reports whether the window is
+// done.
+func (b *SimulatedBroker) workSlices(ctx context.Context, algo *algoOrder, bars *data.DataFrame) bool {
+	order := algo.order
+
+	traded := tradedBars(bars, order.Asset)
+	if len(traded) == 0 {
+		return false
+	}
+
+	if algo.start.IsZero() {
+		algo.start = traded[0]
+
+		if order.ExecAlgo == broker.ExecVWAP {
+			algo.profile = volumeProfile(ctx, b.intraday, order.Asset, algo.start, order.ExecDuration, order.ExecSlices)
+		}
+	}
+
+	end := algo.start.Add(order.ExecDuration)
+	firstBars := make([]time.Time, order.ExecSlices)
+
+	for _, barTime := range traded {
+		if barTime.Before(algo.start) || !barTime.Before(end) {
+			continue
+		}
+
+		idx := sliceIndex(algo.start, barTime, order.ExecDuration, order.ExecSlices)
+		if firstBars[idx].IsZero() {
+			firstBars[idx] = barTime
+		}
+	}
+
+	targets := sliceTargets(order.Qty, algo.profile, order.ExecSlices, wholeShares(order))
+
+	for ; algo.nextSlice < order.ExecSlices; algo.nextSlice++ {
+		idx := algo.nextSlice
+
+		if firstBars[idx].IsZero() {
+			if b.date.Before(sliceEnd(algo.start, order.ExecDuration, order.ExecSlices, idx)) {
+				return false
+			}
+
+			continue
+		}
+
+		if qty := targets[idx] - algo.filled; qty > 1e-9 {
+			b.fillChild(ctx, algo, qty, bars.At(firstBars[idx]))
+		}
+
+		if algo.stopped {
+			return true
+		}
+	}
+
+	return true
+}
+
+// workPOV trades ExecParticipation percent of each new minute bar's
+// volume until the order is filled. It reports whether the order is done.
+func (b *SimulatedBroker) workPOV(ctx context.Context, algo *algoOrder, bars *data.DataFrame) bool {
+	order := algo.order
+
+	for _, barTime := range tradedBars(bars, order.Asset) {
+		if !barTime.After(algo.cursor) {
+			continue
+		}
+
+		algo.cursor = barTime
+		if algo.start.IsZero() {
+			algo.start = barTime
+		}
+
+		volume := bars.ValueAt(order.Asset, data.Volume, barTime)
+		if math.IsNaN(volume) || volume <= 0 {
+			continue
+		}
+
+		qty := math.Min(algo.remaining(), volume*order.ExecParticipation/100)
+		if wholeShares(order) {
+			qty = math.Floor(qty)
+		}
+
+		if qty > 0 {
+			b.fillChild(ctx, algo, qty, bars.At(barTime))
+		}
+
+		if algo.stopped || algo.remaining() <= 1e-9 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fillChild fills a child order of qty shares against a single minute bar
+// through the fill pipeline. A limit or stop price the bar does not reach
+// leaves the quantity for a later slice.
+func (b *SimulatedBroker) fillChild(ctx context.Context, algo *algoOrder, qty float64, bar *data.DataFrame) {
+	child := algo.order
+	child.Qty = qty
+	child.ExecAlgo = broker.ExecImmediate
+
+	filled := algo.filled
+
+	if err := b.fillOrder(ctx, child, bar); err != nil {
+		return
+	}
+
+	if algo.filled > filled {
+		algo.lastBar = bar.Times()[0]
+	}
+}
+
+// completeAlgo stops working an algorithmic order, fails any unfilled
+// remainder so the account releases it, and records its execution report.
+func (b *SimulatedBroker) completeAlgo(ctx context.Context, algo *algoOrder) {
+	delete(b.algos, algo.order.ID)
+
+	if !algo.stopped && algo.remaining() > 1e-9 {
+		b.failOrder(algo.order, fmt.Errorf("%s order for %s ended with %g of %g shares unfilled",
+			algo.order.ExecAlgo, algo.order.Asset.Ticker, algo.remaining(), algo.order.Qty))
+	}
+
+	report := algo.report()
+	b.reports = append(b.reports, report)
+
+	logExecutionReport(ctx, report)
+}
+
+// logExecutionReport logs a finished algorithmic order and its
+// implementation shortfall.
+func logExecutionReport(ctx context.Context, report broker.ExecutionReport) {
+	zerolog.Ctx(ctx).Info().
+		Str("order", report.OrderID).
+		Str("asset", report.Asset.Ticker).
+		Stringer("algo", report.Algo).
+		Float64("ordered", report.Ordered).
+		Float64("filled", report.Filled).
+		Float64("avg_price", report.AvgPrice).
+		Float64("arrival_price", report.ArrivalPrice).
+		Float64("shortfall_bps", report.ImplementationShortfall()*10000).
+		Msg("execution algorithm finished")
+}
+
+// ExecutionReports returns a report for each order the engine's broker
+// worked with an execution algorithm (see portfolio.ExecTWAP,
+// portfolio.ExecVWAP and portfolio.ExecPOV), including its implementation
+// shortfall against the arrival price.
+func (e *Engine) ExecutionReports() []broker.ExecutionReport {
+	if e.algoBroker != nil {
+		return e.algoBroker.ExecutionReports()
+	}
+
+	if reporter, ok := e.broker.(broker.ExecutionReporter); ok {
+		return reporter.ExecutionReports()
+	}
+
+	return nil
+}
@@ -0,0 +1,278 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/engine"
+	"github.com/penny-vault/pvbt/portfolio"
+)
+
+// algoOrderStrategy buys 100 shares with an execution algorithm on its
+// first firing.
+type algoOrderStrategy struct {
+	target   asset.Asset
+	modifier portfolio.OrderModifier
+	fired    bool
+}
+
+func (s *algoOrderStrategy) Name() string           { return "algoOrder" }
+func (s *algoOrderStrategy) Setup(_ *engine.Engine) {}
+func (s *algoOrderStrategy) Describe() engine.StrategyDescription {
+	return engine.StrategyDescription{Schedule: "0 16 * * 1-5"}
+}
+
+func (s *algoOrderStrategy) Compute(ctx context.Context, _ *engine.Engine, _ portfolio.Portfolio, batch *portfolio.Batch) error {
+	if s.fired {
+		return nil
+	}
+
+	s.fired = true
+
+	return batch.Order(ctx, s.target, portfolio.Buy, 100, s.modifier)
+}
+
+// childFillingBroker fills every order it receives at a price one dollar
+// higher than the last.
+type childFillingBroker struct {
+	mockLifecycleBroker
+	mu        sync.Mutex
+	fills     chan broker.Fill
+	submitted []broker.Order
+}
+
+func (fb *childFillingBroker) Submit(_ context.Context, order broker.Order) error {
+	fb.mu.Lock()
+	fb.submitted = append(fb.submitted, order)
+	price := 10.0 + float64(len(fb.submitted))
+	fb.mu.Unlock()
+
+	fb.fills <- broker.Fill{OrderID: order.ID, Price: price, Qty: order.Qty, FilledAt: time.Now()}
+
+	return nil
+}
+
+func (fb *childFillingBroker) Fills() <-chan broker.Fill { return fb.fills }
+
+func (fb *childFillingBroker) submittedOrders() []broker.Order {
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+
+	return append([]broker.Order(nil), fb.submitted...)
+}
+
+var _ = Describe("Execution algorithms", func() {
+	var (
+		nyc       *time.Location
+		testStock asset.Asset
+	)
+
+	BeforeEach(func() {
+		var err error
+		nyc, err = time.LoadLocation("America/New_York")
+		Expect(err).NotTo(HaveOccurred())
+
+		testStock = asset.Asset{CompositeFigi: "FIGI-ALGO", Ticker: "ALGO"}
+	})
+
+	Context("in a backtest", func() {
+		// run buys 100 shares at the January 2, 2024 close ($10) with
+		// modifier. The order is worked against four minute bars on
+		// January 3 closing at $10, $11, $12 and $13 on volumes of 100,
+		// 100, 200 and 600. The same minutes on January 2 traded 300,
+		// 300, 100 and 100 shares.
+		run := func(modifier portfolio.OrderModifier) (*engine.Engine, portfolio.Portfolio) {
+			dailyMetrics := []data.Metric{data.MetricClose, data.AdjClose, data.MetricHigh, data.MetricLow, data.Volume, data.Dividend, data.SplitFactor}
+			dailyTimes := []time.Time{
+				time.Date(2024, 1, 2, 16, 0, 0, 0, nyc),
+				time.Date(2024, 1, 3, 16, 0, 0, 0, nyc),
+				time.Date(2024, 1, 4, 16, 0, 0, 0, nyc),
+			}
+
+			dailyDF, err := data.NewDataFrame(dailyTimes, []asset.Asset{testStock}, dailyMetrics, data.Daily, [][]float64{
+				{10, 13, 13}, {10, 13, 13}, {10, 13, 13}, {10, 10, 13}, {1000, 1000, 1000}, {0, 0, 0}, {1, 1, 1},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			minuteMetrics := []data.Metric{data.MetricOpen, data.MetricClose, data.MetricHigh, data.MetricLow, data.Volume}
+			minuteTimes := []time.Time{
+				time.Date(2024, 1, 2, 10, 0, 0, 0, nyc),
+				time.Date(2024, 1, 2, 10, 1, 0, 0, nyc),
+				time.Date(2024, 1, 2, 10, 2, 0, 0, nyc),
+				time.Date(2024, 1, 2, 10, 3, 0, 0, nyc),
+				time.Date(2024, 1, 3, 10, 0, 0, 0, nyc),
+				time.Date(2024, 1, 3, 10, 1, 0, 0, nyc),
+				time.Date(2024, 1, 3, 10, 2, 0, 0, nyc),
+				time.Date(2024, 1, 3, 10, 3, 0, 0, nyc),
+			}
+
+			minuteDF, err := data.NewDataFrame(minuteTimes, []asset.Asset{testStock}, minuteMetrics, data.Tick, [][]float64{
+				{10, 10, 10, 10, 10, 11, 12, 13}, {10, 10, 10, 10, 10, 11, 12, 13},
+				{10, 10, 10, 10, 10, 11, 12, 13}, {10, 10, 10, 10, 10, 11, 12, 13},
+				{300, 300, 100, 100, 100, 100, 200, 600},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			eng := engine.New(&algoOrderStrategy{target: testStock, modifier: modifier},
+				engine.WithDataProvider(data.NewTestProvider(dailyMetrics, dailyDF)),
+				engine.WithDataProvider(data.NewIntradayTestProvider(minuteDF)),
+				engine.WithAssetProvider(&mockAssetProvider{assets: []asset.Asset{testStock}}),
+				engine.WithInitialDeposit(10_000),
+			)
+
+			fund, err := eng.Backtest(context.Background(),
+				time.Date(2024, 1, 2, 0, 0, 0, 0, nyc), time.Date(2024, 1, 4, 23, 59, 0, 0, nyc))
+			Expect(err).NotTo(HaveOccurred())
+
+			return eng, fund
+		}
+
+		buys := func(fund portfolio.Portfolio) [][2]float64 {
+			var got [][2]float64
+
+			for _, txn := range fund.Transactions() {
+				if txn.Type == asset.BuyTransaction {
+					got = append(got, [2]float64{txn.Qty, txn.Price})
+				}
+			}
+
+			return got
+		}
+
+		It("works a TWAP order in equal slices and reports its shortfall", func() {
+			eng, fund := run(portfolio.ExecTWAP(4*time.Minute, 4))
+
+			Expect(buys(fund)).To(Equal([][2]float64{{25, 10}, {25, 11}, {25, 12}, {25, 13}}))
+			Expect(fund.Position(testStock)).To(Equal(100.0))
+
+			reports := eng.ExecutionReports()
+			Expect(reports).To(HaveLen(1))
+			Expect(reports[0].Algo).To(Equal(broker.ExecTWAP))
+			Expect(reports[0].Filled).To(Equal(100.0))
+			Expect(reports[0].Slices).To(Equal(4))
+			Expect(reports[0].AvgPrice).To(BeNumerically("~", 11.5, 1e-9))
+			Expect(reports[0].ArrivalPrice).To(Equal(10.0))
+			Expect(reports[0].ImplementationShortfall()).To(BeNumerically("~", 0.15, 1e-9))
+			Expect(reports[0].Start).To(Equal(time.Date(2024, 1, 3, 10, 0, 0, 0, nyc)))
+			Expect(reports[0].End).To(Equal(time.Date(2024, 1, 3, 10, 3, 0, 0, nyc)))
+		})
+
+		It("sizes VWAP slices by the prior sessions' volume profile", func() {
+			_, fund := run(portfolio.ExecVWAP(4*time.Minute, 2))
+
+			// January 2 traded 600 shares in the first two minutes and
+			// 200 in the last two; January 3's own volumes are not known
+			// when the slices are sized.
+			Expect(buys(fund)).To(Equal([][2]float64{{75, 10}, {25, 12}}))
+		})
+
+		It("takes a share of each minute's volume with POV", func() {
+			_, fund := run(portfolio.ExecPOV(50))
+
+			Expect(buys(fund)).To(Equal([][2]float64{{50, 10}, {50, 11}}))
+		})
+
+		It("carries empty slices forward and releases what the window leaves unfilled", func() {
+			eng, fund := run(portfolio.ExecTWAP(10*time.Minute, 5))
+
+			Expect(buys(fund)).To(Equal([][2]float64{{20, 10}, {20, 12}}))
+			Expect(fund.Position(testStock)).To(Equal(40.0))
+
+			reports := eng.ExecutionReports()
+			Expect(reports).To(HaveLen(1))
+			Expect(reports[0].Ordered).To(Equal(100.0))
+			Expect(reports[0].Filled).To(Equal(40.0))
+		})
+	})
+
+	Context("against a live broker", func() {
+		var (
+			inner  *childFillingBroker
+			algo   *engine.AlgoBrokerForTest
+			prices *mockVolumePriceProvider
+		)
+
+		BeforeEach(func() {
+			inner = &childFillingBroker{fills: make(chan broker.Fill, 16)}
+			prices = &mockVolumePriceProvider{
+				close:  map[asset.Asset]float64{testStock: 10},
+				volume: map[asset.Asset]float64{testStock: 1000},
+				date:   time.Now(),
+			}
+			algo = engine.NewAlgoBrokerForTest(inner, nil, prices)
+		})
+
+		AfterEach(func() {
+			Expect(algo.Close()).To(Succeed())
+		})
+
+		It("sends child orders on the schedule and reports their fills under the parent", func() {
+			parent := broker.Order{
+				ID: "parent", Asset: testStock, Side: broker.Buy, Qty: 100,
+				ExecAlgo: broker.ExecTWAP, ExecDuration: 60 * time.Millisecond, ExecSlices: 4,
+			}
+			Expect(algo.Submit(context.Background(), parent)).To(Succeed())
+
+			var fills []broker.Fill
+			for range 4 {
+				var fill broker.Fill
+				Eventually(algo.Fills()).Should(Receive(&fill))
+				fills = append(fills, fill)
+			}
+
+			for _, fill := range fills {
+				Expect(fill.OrderID).To(Equal("parent"))
+				Expect(fill.Qty).To(Equal(25.0))
+			}
+
+			children := inner.submittedOrders()
+			Expect(children).To(HaveLen(4))
+			Expect(children[0].ID).To(Equal("parent-1"))
+			Expect(children[0].ExecAlgo).To(Equal(broker.ExecImmediate))
+
+			Eventually(algo.ExecutionReports).Should(HaveLen(1))
+			report := algo.ExecutionReports()[0]
+			Expect(report.Filled).To(Equal(100.0))
+			Expect(report.AvgPrice).To(BeNumerically("~", 12.5, 1e-9))
+			Expect(report.ImplementationShortfall()).To(BeNumerically("~", 0.25, 1e-9))
+		})
+
+		It("stops sending child orders when the parent is cancelled", func() {
+			parent := broker.Order{
+				ID: "parent", Asset: testStock, Side: broker.Sell, Qty: 100,
+				ExecAlgo: broker.ExecTWAP, ExecDuration: time.Hour, ExecSlices: 4,
+			}
+			Expect(algo.Submit(context.Background(), parent)).To(Succeed())
+
+			Eventually(algo.Fills()).Should(Receive())
+			Expect(algo.Cancel(context.Background(), "parent")).To(Succeed())
+
+			Expect(inner.submittedOrders()).To(HaveLen(1))
+			Expect(algo.ExecutionReports()).To(HaveLen(1))
+			Expect(algo.ExecutionReports()[0].Filled).To(Equal(25.0))
+			Expect(algo.ExecutionReports()[0].ImplementationShortfall()).To(BeNumerically("~", -0.1, 1e-9))
+		})
+	})
+})
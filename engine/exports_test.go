@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
 	"github.com/penny-vault/pvbt/data"
 	"github.com/penny-vault/pvbt/portfolio"
 )
@@ -189,3 +190,74 @@ func SetAccountForTest(eng *Engine, acct portfolio.PortfolioManager) {
 func ComputeMetricsForTest(stats portfolio.PortfolioStats, date time.Time, metrics []portfolio.PerformanceMetric, appendMetric func(portfolio.MetricRow)) int {
 	return computeMetrics(stats, date, metrics, appendMetric)
 }
+
+// StreamManagerForTest is a type alias for streamManager.
+type StreamManagerForTest = streamManager
+
+// NewStreamManagerForTest exposes newStreamManager with a short retry
+// delay so resubscription tests run quickly.
+func NewStreamManagerForTest(provider data.StreamProvider, maxAge time.Duration) *streamManager {
+	sm := newStreamManager(provider, maxAge)
+	sm.retryDelay = time.Millisecond
+
+	return sm
+}
+
+// StreamSyncForTest exposes streamManager.sync.
+func StreamSyncForTest(sm *streamManager, ctx context.Context, assets []asset.Asset) error {
+	return sm.sync(ctx, assets)
+}
+
+// StreamCoversForTest exposes streamManager.covers.
+func StreamCoversForTest(sm *streamManager, assets []asset.Asset) bool {
+	return sm.covers(assets)
+}
+
+// StreamFrameForTest exposes streamManager.frame.
+func StreamFrameForTest(sm *streamManager, assets []asset.Asset, at time.Time) (*data.DataFrame, error) {
+	return sm.frame(assets, at)
+}
+
+// StreamStopForTest exposes streamManager.stop.
+func StreamStopForTest(sm *streamManager) {
+	sm.stop()
+}
+
+// WriteCheckpointForTest exposes writeCheckpoint.
+func WriteCheckpointForTest(eng *Engine, acct portfolio.PortfolioManager, lastSync time.Time) error {
+	return eng.writeCheckpoint(acct, lastSync)
+}
+
+// LoadCheckpointForTest exposes loadCheckpoint and returns the restored
+// account along with the recovered checkpoint and last-sync times.
+func LoadCheckpointForTest(eng *Engine) (portfolio.PortfolioManager, time.Time, time.Time, error) {
+	resume, err := eng.loadCheckpoint()
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+
+	return eng.account, resume.checkpointAt, resume.lastSync, nil
+}
+
+// ReconcileWithBrokerForTest exposes reconcileWithBroker.
+func ReconcileWithBrokerForTest(eng *Engine, ctx context.Context, acct portfolio.PortfolioManager, at time.Time) error {
+	return eng.reconcileWithBroker(ctx, acct, at)
+}
+
+// SetBrokerForTest installs a broker on the engine without running init.
+func SetBrokerForTest(eng *Engine, b broker.Broker) {
+	eng.broker = b
+}
+
+// AlgoBrokerForTest is a type alias for algoBroker.
+type AlgoBrokerForTest = algoBroker
+
+// NewAlgoBrokerForTest exposes newAlgoBroker with a fixed price source.
+func NewAlgoBrokerForTest(inner broker.Broker, intraday IntradayProvider, prices broker.PriceProvider) *algoBroker {
+	return newAlgoBroker(inner, intraday, func() broker.PriceProvider { return prices })
+}
+
+// SetDiskCacheClockForTest replaces the disk cache's clock.
+func SetDiskCacheClockForTest(cache *DiskCache, now func() time.Time) {
+	cache.now = now
+}
@@ -0,0 +1,157 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/portfolio"
+)
+
+// fxLookback is how far before each step the engine looks for the most
+// recent exchange rate, so weekends and holidays in the rate series are
+// covered.
+const fxLookback = 14 * 24 * time.Hour
+
+// FXSeries names the data series that supplies the exchange rate for a
+// currency. The series is fetched through the engine's provider routing
+// like any other asset, using its Close metric.
+type FXSeries struct {
+	// Ticker is the series ticker, for example "FRED:DEXUSEU".
+	Ticker string
+
+	// PerUSD is true when the series is quoted in units of the currency
+	// per US dollar (FRED's DEXJPUS), and false when it is quoted in US
+	// dollars per unit of the currency (FRED's DEXUSEU).
+	PerUSD bool
+}
+
+// defaultFXSeries are the FRED H.10 daily noon buying rates used for
+// currencies without a series configured through WithFXSeries.
+var defaultFXSeries = map[asset.Currency]FXSeries{
+	asset.CurrencyEUR: {Ticker: "FRED:DEXUSEU"},
+	asset.CurrencyGBP: {Ticker: "FRED:DEXUSUK"},
+	asset.CurrencyAUD: {Ticker: "FRED:DEXUSAL"},
+	asset.CurrencyNZD: {Ticker: "FRED:DEXUSNZ"},
+	asset.CurrencyJPY: {Ticker: "FRED:DEXJPUS", PerUSD: true},
+	asset.CurrencyCAD: {Ticker: "FRED:DEXCAUS", PerUSD: true},
+	asset.CurrencyCHF: {Ticker: "FRED:DEXSZUS", PerUSD: true},
+	asset.CurrencyCNY: {Ticker: "FRED:DEXCHUS", PerUSD: true},
+	asset.CurrencyHKD: {Ticker: "FRED:DEXHKUS", PerUSD: true},
+	asset.CurrencySEK: {Ticker: "FRED:DEXSDUS", PerUSD: true},
+	asset.CurrencyMXN: {Ticker: "FRED:DEXMXUS", PerUSD: true},
+}
+
+// fxSource is a resolved exchange-rate series.
+type fxSource struct {
+	asset  asset.Asset
+	perUSD bool
+}
+
+// resolveFXSeries looks up the exchange-rate series for every foreign
+// currency that an asset in the registry is quoted in. It is an error
+// for such a currency to have no series.
+func (e *Engine) resolveFXSeries(ctx context.Context) error {
+	e.fxSources = nil
+
+	var currencies []asset.Currency
+
+	for _, registered := range e.assets {
+		// Minor units such as GBX are converted through their major
+		// currency's rate.
+		cur, _ := registered.QuoteCurrency().Major()
+		if cur != asset.BaseCurrency && !slices.Contains(currencies, cur) {
+			currencies = append(currencies, cur)
+		}
+	}
+
+	for cur := range e.fxSeries {
+		if !slices.Contains(currencies, cur) {
+			currencies = append(currencies, cur)
+		}
+	}
+
+	for _, cur := range currencies {
+		series, ok := e.fxSeries[cur]
+		if !ok {
+			series, ok = defaultFXSeries[cur]
+		}
+
+		if !ok {
+			return fmt.Errorf("engine: no exchange-rate series for %s; configure one with WithFXSeries", cur)
+		}
+
+		rateAsset, err := e.assetProvider.LookupAsset(ctx, series.Ticker)
+		if err != nil {
+			return fmt.Errorf("engine: look up exchange-rate series %s for %s: %w", series.Ticker, cur, err)
+		}
+
+		if e.fxSources == nil {
+			e.fxSources = make(map[asset.Currency]fxSource)
+		}
+
+		e.fxSources[cur] = fxSource{asset: rateAsset, perUSD: series.PerUSD}
+	}
+
+	return nil
+}
+
+// markFXRates sets the exchange rates of each account to the most recent
+// observation of each series on or before date. A currency without an
+// observation in the lookback window keeps its previous rate.
+func (e *Engine) markFXRates(ctx context.Context, date time.Time, accts ...portfolio.PortfolioManager) error {
+	if len(e.fxSources) == 0 {
+		return nil
+	}
+
+	rates := make(map[asset.Currency]float64, len(e.fxSources))
+
+	for cur, source := range e.fxSources {
+		df, err := e.fetchRange(ctx, []asset.Asset{source.asset}, []data.Metric{data.MetricClose}, date.Add(-fxLookback), date)
+		if err != nil {
+			return fmt.Errorf("engine: fetch exchange rate for %s: %w", cur, err)
+		}
+
+		rate := math.NaN()
+		times := df.Times()
+		values := df.Column(source.asset, data.MetricClose)
+
+		for idx := min(len(times), len(values)) - 1; idx >= 0; idx-- {
+			if !times[idx].After(date) && !math.IsNaN(values[idx]) {
+				rate = values[idx]
+				break
+			}
+		}
+
+		if source.perUSD {
+			rate = 1 / rate
+		}
+
+		rates[cur] = rate
+	}
+
+	for _, acct := range accts {
+		acct.SetFXRates(date, rates)
+	}
+
+	return nil
+}
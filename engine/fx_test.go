@@ -0,0 +1,113 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/engine"
+	"github.com/penny-vault/pvbt/portfolio"
+)
+
+var _ = Describe("Exchange rates", func() {
+	var (
+		sap     asset.Asset
+		eurusd  asset.Asset
+		usdjpy  asset.Asset
+		metrics []data.Metric
+		df      *data.DataFrame
+		start   time.Time
+		end     time.Time
+	)
+
+	BeforeEach(func() {
+		sap = asset.Asset{CompositeFigi: "FIGI-SAP", Ticker: "SAP", Currency: asset.CurrencyEUR}
+		eurusd = asset.NewFREDAsset("DEXUSEU")
+		usdjpy = asset.NewFREDAsset("DEXJPUS")
+		metrics = []data.Metric{data.MetricClose, data.AdjClose, data.Dividend, data.MetricHigh, data.MetricLow, data.SplitFactor, data.Volume}
+		df = makeDailyTestData(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 200, []asset.Asset{sap, eurusd, usdjpy}, metrics)
+		start = time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+		end = time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC)
+	})
+
+	// closeAt returns the last close of ast on or before date.
+	closeAt := func(ast asset.Asset, date time.Time) float64 {
+		level := 0.0
+
+		for idx, ts := range df.Times() {
+			if ts.After(date) {
+				break
+			}
+
+			level = df.Column(ast, data.MetricClose)[idx]
+		}
+
+		return level
+	}
+
+	runBacktest := func(assets []asset.Asset, opts ...engine.Option) (portfolio.Portfolio, error) {
+		opts = append([]engine.Option{
+			engine.WithDataProvider(data.NewTestProvider(metrics, df)),
+			engine.WithAssetProvider(&mockAssetProvider{assets: assets}),
+			engine.WithInitialDeposit(100_000),
+		}, opts...)
+
+		return engine.New(&holdCashStrategy{}, opts...).Backtest(context.Background(), start, end)
+	}
+
+	It("marks each foreign currency with its default series", func() {
+		fund, err := runBacktest([]asset.Asset{sap, eurusd})
+		Expect(err).NotTo(HaveOccurred())
+
+		last := fund.PerfData().Times()
+		Expect(fund.FXRate(asset.CurrencyEUR)).To(Equal(closeAt(eurusd, last[len(last)-1])))
+	})
+
+	It("inverts series quoted per US dollar", func() {
+		fund, err := runBacktest([]asset.Asset{sap, eurusd, usdjpy},
+			engine.WithFXSeries(asset.CurrencyJPY, engine.FXSeries{Ticker: "FRED:DEXJPUS", PerUSD: true}))
+		Expect(err).NotTo(HaveOccurred())
+
+		last := fund.PerfData().Times()
+		Expect(fund.FXRate(asset.CurrencyJPY)).To(BeNumerically("~", 1/closeAt(usdjpy, last[len(last)-1]), 1e-12))
+	})
+
+	It("marks pence through the pound's series", func() {
+		vod := asset.Asset{CompositeFigi: "FIGI-VOD", Ticker: "VOD", Currency: asset.CurrencyGBX}
+		gbpusd := asset.NewFREDAsset("DEXUSUK")
+		df = makeDailyTestData(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 200, []asset.Asset{vod, gbpusd}, metrics)
+
+		fund, err := runBacktest([]asset.Asset{vod, gbpusd})
+		Expect(err).NotTo(HaveOccurred())
+
+		last := fund.PerfData().Times()
+		Expect(fund.FXRate(asset.CurrencyGBP)).To(Equal(closeAt(gbpusd, last[len(last)-1])))
+		Expect(fund.FXRate(asset.CurrencyGBX)).To(BeNumerically("~", closeAt(gbpusd, last[len(last)-1])/100, 1e-12))
+	})
+
+	It("fails when a currency has no exchange-rate series", func() {
+		petr := asset.Asset{CompositeFigi: "FIGI-PETR", Ticker: "PETR4", Currency: "BRL"}
+
+		_, err := runBacktest([]asset.Asset{sap, eurusd, petr})
+		Expect(err).To(MatchError(ContainSubstring("no exchange-rate series for BRL")))
+	})
+})
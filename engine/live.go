@@ -17,6 +17,7 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"time"
@@ -24,7 +25,6 @@ import (
 	"github.com/penny-vault/pvbt/asset"
 	"github.com/penny-vault/pvbt/data"
 	"github.com/penny-vault/pvbt/portfolio"
-	"github.com/penny-vault/pvbt/tradecron"
 	"github.com/rs/zerolog"
 )
 
@@ -64,12 +64,15 @@ func (e *Engine) RunLive(ctx context.Context) (<-chan portfolio.PortfolioManager
 	// 4. Call strategy.Setup.
 	e.strategy.Setup(e)
 
+	// 4a. Choose the market calendar from the strategy's listing exchange.
+	e.resolveCalendar(ctx)
+
 	// 4b. If Setup did not set schedule/benchmark, try Describe().
 	if desc, ok := e.strategy.(Descriptor); ok {
 		description := desc.Describe()
 
 		if e.schedule == nil && description.Schedule != "" {
-			tc, tcErr := tradecron.New(description.Schedule, tradecron.RegularHours)
+			tc, tcErr := e.newSchedule(description.Schedule)
 			if tcErr != nil {
 				return nil, fmt.Errorf("engine: parsing schedule from Describe(): %w", tcErr)
 			}
@@ -111,7 +114,24 @@ func (e *Engine) RunLive(ctx context.Context) (<-chan portfolio.PortfolioManager
 		return nil, fmt.Errorf("engine: strategy %q did not set a schedule during Setup", e.strategy.Name())
 	}
 
-	// 6. Create and configure account.
+	// 6. Create and configure account. When resuming, the checkpoint
+	// supplies the account instead of a fresh deposit.
+	if err := e.prepareLiveStateDir(); err != nil {
+		return nil, err
+	}
+
+	var resume liveResume
+
+	if e.resumePath != "" {
+		var resumeErr error
+		if resume, resumeErr = e.loadCheckpoint(); resumeErr != nil {
+			return nil, resumeErr
+		}
+
+		e.initialDeposit = 0
+		e.snapshot = nil
+	}
+
 	acct, acctErr := e.createAccount(time.Now())
 	if acctErr != nil {
 		return nil, acctErr
@@ -123,6 +143,14 @@ func (e *Engine) RunLive(ctx context.Context) (<-chan portfolio.PortfolioManager
 	}
 
 	applyMarginConfig(e, acct)
+	e.watchEvents(acct)
+
+	// Live brokers receive orders with an execution algorithm as child
+	// orders sent on the algorithm's schedule.
+	if _, ok := e.broker.(*SimulatedBroker); !ok {
+		e.algoBroker = newAlgoBroker(e.broker, e.findIntradayProvider(), e.livePriceProvider)
+		acct.SetBroker(e.algoBroker.asBroker())
+	}
 
 	// 6b. Apply config-driven middleware if provided.
 	if e.middlewareConfig != nil {
@@ -188,10 +216,43 @@ func (e *Engine) RunLive(ctx context.Context) (<-chan portfolio.PortfolioManager
 
 	e.riskFreeCumulative = 0
 
+	// Resolve exchange-rate series for foreign-currency assets.
+	if err := e.resolveFXSeries(ctx); err != nil {
+		return nil, err
+	}
+
+	// Give the simulated broker the account for its margin checks, as
+	// Backtest does.
+	if sb, ok := e.broker.(*SimulatedBroker); ok {
+		sb.SetPortfolio(acct)
+	}
+
 	if err := e.broker.Connect(ctx); err != nil {
 		return nil, fmt.Errorf("engine: broker connect: %w", err)
 	}
 
+	// Recover fills the broker executed while the session was down.
+	if e.resumePath != "" && !resume.checkpointAt.IsZero() {
+		if err := e.replayMissedFills(ctx, acct, resume.checkpointAt); err != nil {
+			return nil, err
+		}
+	}
+
+	// Compare the account with the broker before trading starts.
+	if err := e.reconcileWithBroker(ctx, acct, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// Open the real-time stream when a registered provider offers one.
+	// The initial subscription covers restored holdings, the benchmark,
+	// and universe members; it is refreshed on every firing.
+	if streamProvider := e.findStreamProvider(); streamProvider != nil {
+		e.stream = newStreamManager(streamProvider, e.streamMaxAge)
+		if err := e.stream.sync(ctx, e.liveStreamAssets(acct.Holdings(), time.Now())); err != nil {
+			return nil, err
+		}
+	}
+
 	// PHASE 2: GOROUTINE
 
 	portfolioCh := make(chan portfolio.PortfolioManager, 1)
@@ -199,7 +260,11 @@ func (e *Engine) RunLive(ctx context.Context) (<-chan portfolio.PortfolioManager
 	go func() {
 		defer close(portfolioCh)
 
-		dailySchedule, dailyErr := tradecron.New("@close * * *", tradecron.RegularHours)
+		if e.stream != nil {
+			defer e.stream.stop()
+		}
+
+		dailySchedule, dailyErr := e.newSchedule("@close * * *")
 		if dailyErr != nil {
 			zerolog.Ctx(ctx).Error().Err(dailyErr).Msg("failed to create daily equity schedule")
 			return
@@ -208,6 +273,9 @@ func (e *Engine) RunLive(ctx context.Context) (<-chan portfolio.PortfolioManager
 		priceMetrics := []data.Metric{data.MetricClose, data.AdjClose}
 		step := 0
 		lastSyncTime := time.Now().Add(-24 * time.Hour)
+		if !resume.lastSync.IsZero() {
+			lastSyncTime = resume.lastSync
+		}
 
 		for {
 			// a. Compute next fire time for both schedules.
@@ -257,6 +325,12 @@ func (e *Engine) RunLive(ctx context.Context) (<-chan portfolio.PortfolioManager
 				Logger()
 			stepCtx := stepLogger.WithContext(ctx)
 
+			// Mark exchange rates before fills and broker transactions in
+			// foreign currencies are recorded.
+			if err := e.markFXRates(stepCtx, e.currentDate, acct); err != nil {
+				zerolog.Ctx(stepCtx).Error().Err(err).Msg("exchange rate fetch failed")
+			}
+
 			// e. Drain fills from previous step (before syncing transactions).
 			if acct.HasBroker() {
 				if err := acct.DrainFills(stepCtx); err != nil {
@@ -277,17 +351,33 @@ func (e *Engine) RunLive(ctx context.Context) (<-chan portfolio.PortfolioManager
 				lastSyncTime = e.currentDate
 			}
 
+			// Refresh the stream subscription for the current holdings
+			// and universe membership.
+			e.syncLiveStream(stepCtx, acct)
+
 			// Set prices for margin computation and check for margin calls.
-			if marginErr := e.setMarginPrices(stepCtx, acct, e.currentDate); marginErr != nil {
+			if marginErr := e.setLiveMarks(stepCtx, acct); marginErr != nil {
 				zerolog.Ctx(stepCtx).Error().Err(marginErr).Msg("margin price fetch failed")
 			} else if marginErr := e.checkAndHandleMarginCall(stepCtx, acct, e.currentDate); marginErr != nil {
 				zerolog.Ctx(stepCtx).Error().Err(marginErr).Msg("margin call handling failed")
 			}
 
+			e.dispatchLiveEvents(stepCtx, acct)
+
 			// g-h. Run strategy only on strategy-schedule days.
 			if isStrategy {
+				// Reconcile against the broker before the strategy sees
+				// the account. A halt policy ends the session.
+				if err := e.reconcileWithBroker(stepCtx, acct, e.currentDate); err != nil {
+					zerolog.Ctx(stepCtx).Error().Err(err).Msg("broker reconciliation failed")
+
+					if errors.Is(err, ErrBrokerDrift) {
+						return
+					}
+				}
+
 				if sb, ok := e.broker.(*SimulatedBroker); ok {
-					sb.SetPriceProvider(e, e.currentDate)
+					sb.SetPriceProvider(e.livePriceProvider(), e.currentDate)
 				}
 
 				// Cancel open orders from previous frame.
@@ -308,8 +398,22 @@ func (e *Engine) RunLive(ctx context.Context) (<-chan portfolio.PortfolioManager
 					zerolog.Ctx(stepCtx).Error().Err(err).Msg("execute batch failed")
 					continue
 				}
+
+				e.dispatchLiveEvents(stepCtx, acct)
+
+				// Holdings may have changed; pick up new positions.
+				e.syncLiveStream(stepCtx, acct)
+			}
+
+			// Check data-driven triggers on every firing and run the
+			// strategy again when any of them fires.
+			if len(e.triggers) > 0 {
+				e.runLiveTriggers(stepCtx, acct)
 			}
 
+			// Every firing is a bar for the strategy's OnBar.
+			e.runLiveBar(stepCtx, acct)
+
 			// i. Mark-to-market: fetch prices and record equity.
 			// Retry up to 18 times with 1-hour waits for delayed prices
 			// (mutual fund NAVs may not be available until 1-3 AM next day).
@@ -320,6 +424,8 @@ func (e *Engine) RunLive(ctx context.Context) (<-chan portfolio.PortfolioManager
 				priceAssets = append(priceAssets, ast)
 			}
 
+			priceAssets = withOptionUnderlyings(priceAssets)
+
 			if e.benchmark != (asset.Asset{}) {
 				priceAssets = append(priceAssets, e.benchmark)
 			}
@@ -330,11 +436,11 @@ func (e *Engine) RunLive(ctx context.Context) (<-chan portfolio.PortfolioManager
 			if isDaily {
 				// Convert DGS3MO yield to cumulative risk-free value.
 				if e.riskFreeResolved {
-					rfDF, rfFetchErr := e.FetchAt(stepCtx, []asset.Asset{e.riskFreeAssetDGS}, e.currentDate, []data.Metric{data.MetricClose})
+					rfDF, rfFetchErr := e.fetchAt(stepCtx, []asset.Asset{e.riskFreeAssetDGS}, e.currentDate, []data.Metric{data.MetricClose})
 					if rfFetchErr == nil {
 						yield := rfDF.Value(e.riskFreeAssetDGS, data.MetricClose)
 						if !math.IsNaN(yield) && yield > 0 {
-							e.riskFreeCumulative = portfolio.YieldToCumulative(yield, e.riskFreeCumulative)
+							e.riskFreeCumulative = portfolio.YieldToCumulative(yield, e.riskFreeCumulative, e.marketCalendar().TradingDaysPerYear())
 						} else if e.riskFreeCumulative == 0 {
 							e.riskFreeCumulative = 100.0
 						}
@@ -357,14 +463,26 @@ func (e *Engine) RunLive(ctx context.Context) (<-chan portfolio.PortfolioManager
 
 				acct.SetRiskFreeValue(e.riskFreeCumulative)
 
-				if len(priceAssets) > 0 {
+				if len(priceAssets) > 0 && e.stream != nil && e.stream.covers(priceAssets) {
+					// Every asset has a real-time mark; record equity from
+					// the stream rather than waiting on the batch database.
+					streamDF, streamErr := e.stream.frame(priceAssets, e.currentDate)
+					if streamErr != nil {
+						zerolog.Ctx(stepCtx).Error().Err(streamErr).Msg("stream mark failed")
+					} else {
+						acct.SetPrices(streamDF)
+						acct.SettleFutures(e.currentDate)
+						acct.ExpireOptions(e.currentDate)
+						acct.UpdatePrices(streamDF)
+					}
+				} else if len(priceAssets) > 0 {
 					var (
 						priceDF  *data.DataFrame
 						fetchErr error
 					)
 
 					for attempt := range 18 {
-						priceDF, fetchErr = e.FetchAt(stepCtx, priceAssets, e.currentDate, priceMetrics)
+						priceDF, fetchErr = e.fetchAt(stepCtx, priceAssets, e.currentDate, priceMetrics)
 						if fetchErr == nil {
 							break
 						}
@@ -384,6 +502,9 @@ func (e *Engine) RunLive(ctx context.Context) (<-chan portfolio.PortfolioManager
 					if fetchErr != nil {
 						zerolog.Ctx(stepCtx).Error().Err(fetchErr).Msg("price fetch failed after retries")
 					} else {
+						acct.SetPrices(priceDF)
+						acct.SettleFutures(e.currentDate)
+						acct.ExpireOptions(e.currentDate)
 						acct.UpdatePrices(priceDF)
 					}
 				} else {
@@ -395,6 +516,13 @@ func (e *Engine) RunLive(ctx context.Context) (<-chan portfolio.PortfolioManager
 				}
 			}
 
+			// Persist the step so a restart can resume from here.
+			if e.liveStateDir != "" {
+				if err := e.writeCheckpoint(acct, lastSyncTime); err != nil {
+					zerolog.Ctx(stepCtx).Error().Err(err).Msg("checkpoint failed")
+				}
+			}
+
 			// j. Non-blocking send of updated portfolio.
 			select {
 			case portfolioCh <- acct:
@@ -0,0 +1,187 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/portfolio"
+	"github.com/rs/zerolog"
+)
+
+// CheckpointFileName is the name of the checkpoint file RunLive writes
+// inside the live state directory.
+const CheckpointFileName = "checkpoint.db"
+
+// sqliteWriter is implemented by accounts that can serialize themselves
+// to a SQLite file (portfolio.Account).
+type sqliteWriter interface {
+	ToSQLite(path string) error
+}
+
+// liveResume carries the state recovered from a checkpoint for the live
+// loop: when the checkpoint was written and how far broker transactions
+// had been synced.
+type liveResume struct {
+	checkpointAt time.Time
+	lastSync     time.Time
+}
+
+// loadCheckpoint restores the account from the resume checkpoint and
+// installs it as the engine's pre-configured account.
+func (e *Engine) loadCheckpoint() (liveResume, error) {
+	acct, err := portfolio.FromSQLite(e.resumePath)
+	if err != nil {
+		return liveResume{}, fmt.Errorf("engine: loading checkpoint %s: %w", e.resumePath, err)
+	}
+
+	var resume liveResume
+
+	if ts := acct.GetMetadata(portfolio.MetaLiveCheckpointAt); ts != "" {
+		if resume.checkpointAt, err = time.Parse(time.RFC3339Nano, ts); err != nil {
+			return liveResume{}, fmt.Errorf("engine: parsing checkpoint time %q: %w", ts, err)
+		}
+	}
+
+	if ts := acct.GetMetadata(portfolio.MetaLiveLastSync); ts != "" {
+		if resume.lastSync, err = time.Parse(time.RFC3339Nano, ts); err != nil {
+			return liveResume{}, fmt.Errorf("engine: parsing last sync time %q: %w", ts, err)
+		}
+	}
+
+	if resume.lastSync.IsZero() {
+		resume.lastSync = resume.checkpointAt
+	}
+
+	e.account = acct
+
+	return resume, nil
+}
+
+// replayMissedFills asks the broker for fills executed since the
+// checkpoint was written and applies those that match orders the
+// checkpoint recorded as pending. Brokers that cannot report historical
+// fills are skipped; their fills arrive on the channel if at all.
+func (e *Engine) replayMissedFills(ctx context.Context, acct portfolio.PortfolioManager, since time.Time) error {
+	replayer, ok := e.broker.(broker.FillReplayer)
+	if !ok {
+		zerolog.Ctx(ctx).Warn().Msg("broker cannot replay fills; fills executed while stopped may be missing")
+		return nil
+	}
+
+	fills, err := replayer.FillsSince(ctx, since)
+	if err != nil {
+		return fmt.Errorf("engine: replaying fills since %v: %w", since, err)
+	}
+
+	acct.ReplayFills(fills)
+
+	zerolog.Ctx(ctx).Info().Int("fills", len(fills)).Time("since", since).Msg("replayed broker fills")
+
+	return nil
+}
+
+// writeCheckpoint atomically writes the account to the live state
+// directory. The account is serialized to a temporary file in the same
+// directory and renamed over the previous checkpoint, so a crash mid-write
+// leaves the prior checkpoint intact.
+func (e *Engine) writeCheckpoint(acct portfolio.PortfolioManager, lastSync time.Time) error {
+	writer, ok := acct.(sqliteWriter)
+	if !ok {
+		return fmt.Errorf("engine: account %T cannot be checkpointed", acct)
+	}
+
+	acct.SetMetadata(portfolio.MetaLiveCheckpointAt, time.Now().Format(time.RFC3339Nano))
+	acct.SetMetadata(portfolio.MetaLiveLastSync, lastSync.Format(time.RFC3339Nano))
+
+	tmp, err := os.CreateTemp(e.liveStateDir, CheckpointFileName+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("engine: creating checkpoint temp file: %w", err)
+	}
+
+	tmpPath := tmp.Name()
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("engine: closing checkpoint temp file: %w", err)
+	}
+
+	if err := writer.ToSQLite(tmpPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("engine: writing checkpoint: %w", err)
+	}
+
+	// Flush the new file before the rename and the directory entry after
+	// it, so a power loss cannot leave a renamed but empty checkpoint or
+	// lose the rename itself.
+	if err := syncPath(tmpPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("engine: syncing checkpoint: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, filepath.Join(e.liveStateDir, CheckpointFileName)); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("engine: installing checkpoint: %w", err)
+	}
+
+	if err := syncPath(e.liveStateDir); err != nil {
+		return fmt.Errorf("engine: syncing live state directory: %w", err)
+	}
+
+	return nil
+}
+
+// syncPath flushes a file or directory to stable storage.
+func syncPath(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// prepareLiveStateDir creates the live state directory if needed.
+func (e *Engine) prepareLiveStateDir() error {
+	if e.liveStateDir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(e.liveStateDir, 0o755); err != nil {
+		return fmt.Errorf("engine: creating live state directory: %w", err)
+	}
+
+	info, err := os.Stat(e.liveStateDir)
+	if err != nil {
+		return fmt.Errorf("engine: live state directory: %w", err)
+	}
+
+	if !info.IsDir() {
+		return errors.New("engine: live state path is not a directory")
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/engine"
+	"github.com/penny-vault/pvbt/portfolio"
+)
+
+var _ = Describe("live checkpoints", func() {
+	var stateDir string
+
+	BeforeEach(func() {
+		stateDir = GinkgoT().TempDir()
+	})
+
+	It("writes the checkpoint atomically and restores it for resume", func() {
+		spy := asset.Asset{Ticker: "SPY", CompositeFigi: "FIGI-SPY"}
+
+		acct := portfolio.New(portfolio.WithCash(50_000, time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)))
+		acct.Record(portfolio.Transaction{
+			Date:   time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC),
+			Asset:  spy,
+			Type:   asset.BuyTransaction,
+			Qty:    10,
+			Price:  600,
+			Amount: -6_000,
+		})
+		acct.SetMetadata(portfolio.MetaStrategyName, "checkpointed")
+
+		writer := engine.New(&liveStrategy{}, engine.WithLiveStateDir(stateDir))
+		lastSync := time.Date(2026, 1, 6, 21, 0, 0, 0, time.UTC)
+		Expect(engine.WriteCheckpointForTest(writer, acct, lastSync)).To(Succeed())
+
+		// Writing again replaces the checkpoint in place without leaving
+		// temporary files behind.
+		Expect(engine.WriteCheckpointForTest(writer, acct, lastSync)).To(Succeed())
+
+		entries, err := os.ReadDir(stateDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Name()).To(Equal(engine.CheckpointFileName))
+
+		reader := engine.New(&liveStrategy{},
+			engine.WithResumeCheckpoint(filepath.Join(stateDir, engine.CheckpointFileName)))
+
+		restored, checkpointAt, restoredSync, err := engine.LoadCheckpointForTest(reader)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checkpointAt).NotTo(BeZero())
+		Expect(restoredSync.Equal(lastSync)).To(BeTrue())
+		Expect(restored.Cash()).To(Equal(44_000.0))
+		Expect(restored.Position(spy)).To(Equal(10.0))
+		Expect(restored.GetMetadata(portfolio.MetaStrategyName)).To(Equal("checkpointed"))
+	})
+
+	It("fails to resume from a missing checkpoint", func() {
+		reader := engine.New(&liveStrategy{},
+			engine.WithResumeCheckpoint(filepath.Join(stateDir, "missing.db")))
+
+		_, _, _, err := engine.LoadCheckpointForTest(reader)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,498 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/portfolio"
+	"github.com/penny-vault/pvbt/universe"
+	"github.com/rs/zerolog"
+)
+
+// streamMetrics lists the real-time metrics the engine subscribes to.
+var streamMetrics = []data.Metric{data.Price, data.Bid, data.Ask}
+
+// DefaultStreamQuoteMaxAge is how old a streamed quote may be before the
+// live loop treats it as missing, unless WithStreamQuoteMaxAge sets
+// another age.
+const DefaultStreamQuoteMaxAge = 5 * time.Minute
+
+// streamRetryDelay is how long the stream manager waits before reopening
+// a subscription the provider closed, and between failed attempts.
+const streamRetryDelay = 5 * time.Second
+
+// liveQuote holds the most recent streamed values for one asset and the
+// time each was stamped. Fields that have not been received yet are NaN.
+type liveQuote struct {
+	price float64
+	bid   float64
+	ask   float64
+
+	priceAt time.Time
+	bidAt   time.Time
+	askAt   time.Time
+}
+
+// emptyQuote returns a quote with no values.
+func emptyQuote() liveQuote {
+	return liveQuote{price: math.NaN(), bid: math.NaN(), ask: math.NaN()}
+}
+
+// fresh returns the quote with every value stamped before cutoff
+// replaced by NaN.
+func (q liveQuote) fresh(cutoff time.Time) liveQuote {
+	if q.priceAt.Before(cutoff) {
+		q.price = math.NaN()
+	}
+
+	if q.bidAt.Before(cutoff) {
+		q.bid = math.NaN()
+	}
+
+	if q.askAt.Before(cutoff) {
+		q.ask = math.NaN()
+	}
+
+	return q
+}
+
+// mark returns the value used to mark the asset: the last trade when
+// one has been seen, otherwise the bid/ask midpoint. NaN when neither
+// is available.
+func (q liveQuote) mark() float64 {
+	if !math.IsNaN(q.price) {
+		return q.price
+	}
+
+	if !math.IsNaN(q.bid) && !math.IsNaN(q.ask) {
+		return (q.bid + q.ask) / 2
+	}
+
+	return math.NaN()
+}
+
+// streamManager owns the engine's StreamProvider subscription during
+// RunLive. It keeps one subscription open for the current set of
+// assets, replaces it whenever that set changes, and reopens it when the
+// provider closes it. Incoming DataPoints are folded into a per-asset
+// quote table read by the live loop and the simulated broker; quotes
+// older than maxAge are treated as missing.
+type streamManager struct {
+	provider   data.StreamProvider
+	maxAge     time.Duration
+	retryDelay time.Duration
+	now        func() time.Time
+
+	mu         sync.RWMutex
+	quotes     map[string]liveQuote // keyed by CompositeFigi
+	subscribed map[string]asset.Asset
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+// newStreamManager creates a streamManager for the given provider whose
+// quotes expire after maxAge; zero means DefaultStreamQuoteMaxAge. No
+// subscription is opened until sync is called.
+func newStreamManager(provider data.StreamProvider, maxAge time.Duration) *streamManager {
+	if maxAge <= 0 {
+		maxAge = DefaultStreamQuoteMaxAge
+	}
+
+	return &streamManager{
+		provider:   provider,
+		maxAge:     maxAge,
+		retryDelay: streamRetryDelay,
+		now:        time.Now,
+		quotes:     make(map[string]liveQuote),
+		subscribed: make(map[string]asset.Asset),
+	}
+}
+
+// sync ensures the active subscription covers exactly the given assets.
+// When the set is unchanged it is a no-op; otherwise the previous
+// subscription is cancelled and a new one is opened. Quotes for assets
+// that are no longer subscribed are discarded.
+func (sm *streamManager) sync(ctx context.Context, assets []asset.Asset) error {
+	want := make(map[string]asset.Asset, len(assets))
+
+	for _, ast := range assets {
+		if ast.CompositeFigi == "" {
+			continue
+		}
+
+		want[ast.CompositeFigi] = ast
+	}
+
+	sm.mu.RLock()
+	unchanged := len(want) == len(sm.subscribed) && sm.cancel != nil
+
+	if unchanged {
+		for figi := range want {
+			if _, ok := sm.subscribed[figi]; !ok {
+				unchanged = false
+				break
+			}
+		}
+	}
+	sm.mu.RUnlock()
+
+	if unchanged {
+		return nil
+	}
+
+	sm.stop()
+
+	if len(want) == 0 {
+		return nil
+	}
+
+	members := make([]asset.Asset, 0, len(want))
+	for _, ast := range want {
+		members = append(members, ast)
+	}
+
+	sort.Slice(members, func(i, j int) bool { return members[i].CompositeFigi < members[j].CompositeFigi })
+
+	subCtx, cancel := context.WithCancel(ctx)
+
+	req := data.DataRequest{
+		Assets:  members,
+		Metrics: streamMetrics,
+		Start:   time.Now(),
+	}
+
+	points, err := sm.provider.Subscribe(subCtx, req)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("engine: stream subscribe: %w", err)
+	}
+
+	done := make(chan struct{})
+
+	sm.mu.Lock()
+	sm.subscribed = want
+	sm.cancel = cancel
+	sm.done = done
+
+	for figi := range sm.quotes {
+		if _, ok := want[figi]; !ok {
+			delete(sm.quotes, figi)
+		}
+	}
+	sm.mu.Unlock()
+
+	go sm.consume(subCtx, req, points, done)
+
+	zerolog.Ctx(ctx).Debug().Int("assets", len(members)).Msg("stream subscription updated")
+
+	return nil
+}
+
+// consume reads DataPoints until the subscription is cancelled. When the
+// provider closes the channel first, for example after a dropped
+// connection, it resubscribes to the same request after retryDelay and
+// keeps retrying until it succeeds or the subscription is cancelled.
+func (sm *streamManager) consume(ctx context.Context, req data.DataRequest, points <-chan data.DataPoint, done chan struct{}) {
+	defer close(done)
+
+	log := zerolog.Ctx(ctx)
+
+	for {
+		for point := range points {
+			sm.record(point)
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Warn().Msg("stream closed by provider; resubscribing")
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(sm.retryDelay):
+			}
+
+			req.Start = time.Now()
+
+			next, err := sm.provider.Subscribe(ctx, req)
+			if err == nil {
+				points = next
+				break
+			}
+
+			log.Error().Err(err).Msg("stream resubscribe failed")
+		}
+	}
+}
+
+// record folds a single DataPoint into the quote table. Metrics other
+// than Price, Bid and Ask, and points for unsubscribed assets, are
+// ignored.
+func (sm *streamManager) record(point data.DataPoint) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if _, ok := sm.subscribed[point.Asset.CompositeFigi]; !ok {
+		return
+	}
+
+	quote, ok := sm.quotes[point.Asset.CompositeFigi]
+	if !ok {
+		quote = emptyQuote()
+	}
+
+	// Points without a timestamp are stamped on arrival.
+	at := point.Time
+	if at.IsZero() {
+		at = sm.now()
+	}
+
+	switch point.Metric {
+	case data.Price:
+		quote.price, quote.priceAt = point.Value, at
+	case data.Bid:
+		quote.bid, quote.bidAt = point.Value, at
+	case data.Ask:
+		quote.ask, quote.askAt = point.Value, at
+	default:
+		return
+	}
+
+	sm.quotes[point.Asset.CompositeFigi] = quote
+}
+
+// stop cancels the active subscription, if any, and waits for the
+// consumer goroutine to drain.
+func (sm *streamManager) stop() {
+	sm.mu.Lock()
+	cancel := sm.cancel
+	done := sm.done
+	sm.cancel = nil
+	sm.done = nil
+	sm.subscribed = make(map[string]asset.Asset)
+	sm.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+
+	if done != nil {
+		<-done
+	}
+}
+
+// quote returns the current quote for an asset with values older than
+// maxAge removed. The caller must hold sm.mu.
+func (sm *streamManager) quote(ast asset.Asset) liveQuote {
+	quote, ok := sm.quotes[ast.CompositeFigi]
+	if !ok {
+		return emptyQuote()
+	}
+
+	return quote.fresh(sm.now().Add(-sm.maxAge))
+}
+
+// covers reports whether every asset has a streamed mark no older than
+// maxAge.
+func (sm *streamManager) covers(assets []asset.Asset) bool {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	for _, ast := range assets {
+		if math.IsNaN(sm.quote(ast).mark()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// frame builds a single-row DataFrame stamped at the given time from
+// the current quotes. The mark is written to MetricOpen, MetricClose,
+// AdjClose, MetricHigh and MetricLow so that the account's mark-to-market
+// and the fill models can consume it unchanged; Price, Bid and Ask carry
+// the raw streamed values. Assets without a quote, or whose quote is older
+// than maxAge, are NaN.
+func (sm *streamManager) frame(assets []asset.Asset, at time.Time) (*data.DataFrame, error) {
+	metrics := []data.Metric{
+		data.MetricOpen, data.MetricClose, data.AdjClose, data.MetricHigh, data.MetricLow,
+		data.Price, data.Bid, data.Ask,
+	}
+
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	cols := make([][]float64, 0, len(assets)*len(metrics))
+
+	for _, ast := range assets {
+		quote := sm.quote(ast)
+		mark := quote.mark()
+		cols = append(cols,
+			[]float64{mark}, []float64{mark}, []float64{mark}, []float64{mark}, []float64{mark},
+			[]float64{quote.price}, []float64{quote.bid}, []float64{quote.ask},
+		)
+	}
+
+	return data.NewDataFrame([]time.Time{at}, assets, metrics, data.Tick, cols)
+}
+
+// findStreamProvider returns the first registered provider that
+// implements StreamProvider, or nil if none do.
+func (e *Engine) findStreamProvider() data.StreamProvider {
+	for _, provider := range e.providers {
+		if sp, ok := provider.(data.StreamProvider); ok {
+			return sp
+		}
+	}
+
+	return nil
+}
+
+// liveStreamAssets returns the assets the live loop should be subscribed
+// to at the given time: current holdings, the benchmark, and the members
+// of every universe (static or dynamic) declared on the strategy.
+func (e *Engine) liveStreamAssets(holdings map[asset.Asset]float64, now time.Time) []asset.Asset {
+	result := make([]asset.Asset, 0, len(holdings)+1)
+	for ast := range holdings {
+		result = appendUniqueAsset(result, ast)
+	}
+
+	result = appendUniqueAsset(result, e.benchmark)
+
+	for _, member := range strategyUniverseMembers(e.strategy, now) {
+		result = appendUniqueAsset(result, member)
+	}
+
+	return result
+}
+
+// strategyUniverseMembers walks the exported fields of a strategy and
+// returns the members, at time t, of every universe.Universe field.
+// Unlike collectStrategyAssets it includes dynamic universes, since the
+// live loop re-evaluates membership on every firing.
+func strategyUniverseMembers(strategy any, t time.Time) []asset.Asset {
+	val := reflect.ValueOf(strategy)
+	if val.Kind() == reflect.Pointer {
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var result []asset.Asset
+
+	targetType := val.Type()
+	for fieldIdx := 0; fieldIdx < targetType.NumField(); fieldIdx++ {
+		field := targetType.Field(fieldIdx)
+		if !field.IsExported() || !field.Type.Implements(universeType) {
+			continue
+		}
+
+		fieldValue := val.Field(fieldIdx)
+		if fieldValue.IsNil() {
+			continue
+		}
+
+		result = append(result, fieldValue.Interface().(universe.Universe).Assets(t)...)
+	}
+
+	return result
+}
+
+// streamPriceSource implements broker.PriceProvider for the simulated
+// broker in live mode. When every requested asset has a streamed mark
+// the fill is priced from the stream; otherwise it falls back to the
+// engine's batch-backed Prices.
+type streamPriceSource struct {
+	engine *Engine
+	stream *streamManager
+}
+
+// Prices implements broker.PriceProvider.
+func (s streamPriceSource) Prices(ctx context.Context, assets ...asset.Asset) (*data.DataFrame, error) {
+	if s.stream.covers(assets) {
+		return s.stream.frame(assets, s.engine.Now())
+	}
+
+	return s.engine.Prices(ctx, assets...)
+}
+
+// syncLiveStream refreshes the stream subscription from the account's
+// holdings and the strategy's universes. Failures are logged; the live
+// loop keeps running on batch prices until the next firing retries.
+func (e *Engine) syncLiveStream(ctx context.Context, acct portfolio.PortfolioManager) {
+	if e.stream == nil {
+		return
+	}
+
+	if err := e.stream.sync(ctx, e.liveStreamAssets(acct.Holdings(), e.Now())); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("stream resubscribe failed")
+	}
+}
+
+// setLiveMarks stores current marks for held assets on the account
+// without recording an equity point. Streamed quotes are used when they
+// cover every holding; otherwise it falls back to setMarginPrices.
+func (e *Engine) setLiveMarks(ctx context.Context, acct portfolio.PortfolioManager) error {
+	if e.stream != nil {
+		holdings := acct.Holdings()
+
+		heldAssets := make([]asset.Asset, 0, len(holdings))
+		for held := range holdings {
+			heldAssets = append(heldAssets, held)
+		}
+
+		if len(heldAssets) > 0 && e.stream.covers(heldAssets) {
+			priceDF, err := e.stream.frame(heldAssets, e.currentDate)
+			if err != nil {
+				return fmt.Errorf("engine: stream marks on %v: %w", e.currentDate, err)
+			}
+
+			acct.SetPrices(priceDF)
+
+			return nil
+		}
+	}
+
+	return e.setMarginPrices(ctx, acct, e.currentDate)
+}
+
+// livePriceProvider returns the price source for the simulated broker in
+// live mode: the stream-backed source when a stream is open, otherwise
+// the engine itself.
+func (e *Engine) livePriceProvider() broker.PriceProvider {
+	if e.stream == nil {
+		return e
+	}
+
+	return streamPriceSource{engine: e, stream: e.stream}
+}
@@ -0,0 +1,253 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine_test
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/engine"
+)
+
+// fakeStreamProvider records each subscription and exposes the channel
+// so tests can push DataPoints into it.
+type fakeStreamProvider struct {
+	mu       sync.Mutex
+	requests []data.DataRequest
+	channels []chan data.DataPoint
+	contexts []context.Context
+	closers  []func()
+}
+
+func (f *fakeStreamProvider) Provides() []data.Metric {
+	return []data.Metric{data.Price, data.Bid, data.Ask}
+}
+
+func (f *fakeStreamProvider) Close() error { return nil }
+
+func (f *fakeStreamProvider) Subscribe(ctx context.Context, req data.DataRequest) (<-chan data.DataPoint, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ch := make(chan data.DataPoint, 16)
+
+	var once sync.Once
+
+	closeCh := func() { once.Do(func() { close(ch) }) }
+
+	f.requests = append(f.requests, req)
+	f.channels = append(f.channels, ch)
+	f.contexts = append(f.contexts, ctx)
+	f.closers = append(f.closers, closeCh)
+
+	go func() {
+		<-ctx.Done()
+		closeCh()
+	}()
+
+	return ch, nil
+}
+
+// drop closes the latest channel the way a provider does when its
+// connection fails.
+func (f *fakeStreamProvider) drop() {
+	f.mu.Lock()
+	closeCh := f.closers[len(f.closers)-1]
+	f.mu.Unlock()
+
+	closeCh()
+}
+
+func (f *fakeStreamProvider) subscriptions() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.requests)
+}
+
+func (f *fakeStreamProvider) latest() (data.DataRequest, chan data.DataPoint, context.Context) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	last := len(f.requests) - 1
+
+	return f.requests[last], f.channels[last], f.contexts[last]
+}
+
+var _ = Describe("streamManager", func() {
+	var (
+		aapl     asset.Asset
+		msft     asset.Asset
+		provider *fakeStreamProvider
+		manager  *engine.StreamManagerForTest
+		ctx      context.Context
+		cancel   context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		aapl = asset.Asset{CompositeFigi: "FIGI-AAPL", Ticker: "AAPL"}
+		msft = asset.Asset{CompositeFigi: "FIGI-MSFT", Ticker: "MSFT"}
+		provider = &fakeStreamProvider{}
+		manager = engine.NewStreamManagerForTest(provider, time.Minute)
+		ctx, cancel = context.WithCancel(context.Background())
+	})
+
+	AfterEach(func() {
+		engine.StreamStopForTest(manager)
+		cancel()
+	})
+
+	It("subscribes to Price, Bid and Ask for the requested assets", func() {
+		Expect(engine.StreamSyncForTest(manager, ctx, []asset.Asset{aapl})).To(Succeed())
+		Expect(provider.subscriptions()).To(Equal(1))
+
+		req, _, _ := provider.latest()
+		Expect(req.Assets).To(ConsistOf(aapl))
+		Expect(req.Metrics).To(ConsistOf(data.Price, data.Bid, data.Ask))
+	})
+
+	It("does not resubscribe when membership is unchanged", func() {
+		Expect(engine.StreamSyncForTest(manager, ctx, []asset.Asset{aapl, msft})).To(Succeed())
+		Expect(engine.StreamSyncForTest(manager, ctx, []asset.Asset{msft, aapl})).To(Succeed())
+		Expect(provider.subscriptions()).To(Equal(1))
+	})
+
+	It("cancels the old subscription and resubscribes when membership changes", func() {
+		Expect(engine.StreamSyncForTest(manager, ctx, []asset.Asset{aapl})).To(Succeed())
+		_, _, firstCtx := provider.latest()
+
+		Expect(engine.StreamSyncForTest(manager, ctx, []asset.Asset{aapl, msft})).To(Succeed())
+		Expect(provider.subscriptions()).To(Equal(2))
+		Expect(firstCtx.Err()).To(HaveOccurred())
+
+		req, _, _ := provider.latest()
+		Expect(req.Assets).To(ConsistOf(aapl, msft))
+	})
+
+	It("marks assets from the last trade and falls back to the bid/ask midpoint", func() {
+		Expect(engine.StreamSyncForTest(manager, ctx, []asset.Asset{aapl, msft})).To(Succeed())
+		_, ch, _ := provider.latest()
+
+		now := time.Now()
+		ch <- data.DataPoint{Time: now, Asset: aapl, Metric: data.Price, Value: 190.5}
+		ch <- data.DataPoint{Time: now, Asset: msft, Metric: data.Bid, Value: 400}
+		ch <- data.DataPoint{Time: now, Asset: msft, Metric: data.Ask, Value: 402}
+
+		Eventually(func() bool {
+			return engine.StreamCoversForTest(manager, []asset.Asset{aapl, msft})
+		}).Should(BeTrue())
+
+		df, err := engine.StreamFrameForTest(manager, []asset.Asset{aapl, msft}, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(df.Value(aapl, data.MetricClose)).To(Equal(190.5))
+		Expect(df.Value(msft, data.MetricClose)).To(Equal(401.0))
+		Expect(df.Value(msft, data.Bid)).To(Equal(400.0))
+		Expect(math.IsNaN(df.Value(aapl, data.Bid))).To(BeTrue())
+	})
+
+	It("reports missing coverage until every asset has a mark", func() {
+		Expect(engine.StreamSyncForTest(manager, ctx, []asset.Asset{aapl, msft})).To(Succeed())
+		_, ch, _ := provider.latest()
+
+		ch <- data.DataPoint{Time: time.Now(), Asset: aapl, Metric: data.Price, Value: 190.5}
+
+		Eventually(func() bool {
+			return engine.StreamCoversForTest(manager, []asset.Asset{aapl})
+		}).Should(BeTrue())
+		Expect(engine.StreamCoversForTest(manager, []asset.Asset{aapl, msft})).To(BeFalse())
+	})
+
+	It("treats quotes older than the maximum age as missing", func() {
+		Expect(engine.StreamSyncForTest(manager, ctx, []asset.Asset{aapl, msft})).To(Succeed())
+		_, ch, _ := provider.latest()
+
+		now := time.Now()
+		ch <- data.DataPoint{Time: now.Add(-2 * time.Minute), Asset: aapl, Metric: data.Price, Value: 190.5}
+		ch <- data.DataPoint{Time: now, Asset: msft, Metric: data.Price, Value: 401}
+
+		Eventually(func() bool {
+			return engine.StreamCoversForTest(manager, []asset.Asset{msft})
+		}).Should(BeTrue())
+		Expect(engine.StreamCoversForTest(manager, []asset.Asset{aapl})).To(BeFalse())
+
+		df, err := engine.StreamFrameForTest(manager, []asset.Asset{aapl, msft}, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(math.IsNaN(df.Value(aapl, data.MetricClose))).To(BeTrue())
+		Expect(df.Value(msft, data.MetricClose)).To(Equal(401.0))
+	})
+
+	It("resubscribes when the provider closes the stream", func() {
+		Expect(engine.StreamSyncForTest(manager, ctx, []asset.Asset{aapl})).To(Succeed())
+		provider.drop()
+
+		Eventually(provider.subscriptions).Should(Equal(2))
+
+		req, ch, _ := provider.latest()
+		Expect(req.Assets).To(ConsistOf(aapl))
+
+		ch <- data.DataPoint{Time: time.Now(), Asset: aapl, Metric: data.Price, Value: 191}
+
+		Eventually(func() bool {
+			return engine.StreamCoversForTest(manager, []asset.Asset{aapl})
+		}).Should(BeTrue())
+
+		// The unchanged membership keeps the reopened subscription.
+		Expect(engine.StreamSyncForTest(manager, ctx, []asset.Asset{aapl})).To(Succeed())
+		Expect(provider.subscriptions()).To(Equal(2))
+	})
+})
+
+var _ = Describe("RunLive with a StreamProvider", func() {
+	It("accepts a stream-only provider and subscribes to the benchmark", func() {
+		spy := asset.Asset{CompositeFigi: "FIGI-SPY", Ticker: "SPY"}
+		testAssets := []asset.Asset{spy}
+
+		dataStart := time.Now().AddDate(0, 0, -30)
+		metrics := []data.Metric{data.MetricClose, data.AdjClose, data.Dividend}
+		df := makeDailyTestData(dataStart, 60, testAssets, metrics)
+		batchProvider := data.NewTestProvider(metrics, df)
+		streamProvider := &fakeStreamProvider{}
+
+		eng := engine.New(&liveStrategy{},
+			engine.WithDataProvider(batchProvider, streamProvider),
+			engine.WithAssetProvider(&mockAssetProvider{assets: testAssets}),
+			engine.WithInitialDeposit(100_000.0),
+			engine.WithBenchmarkTicker("SPY"),
+		)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		ch, err := eng.RunLive(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		for range ch {
+		}
+
+		Expect(streamProvider.subscriptions()).To(Equal(1))
+
+		req, _, subCtx := streamProvider.latest()
+		Expect(req.Assets).To(ConsistOf(spy))
+		Expect(subCtx.Err()).To(HaveOccurred())
+	})
+})
@@ -95,15 +95,15 @@ func (eng *Engine) setMarginPrices(ctx context.Context, acct portfolio.Portfolio
 	return nil
 }
 
-// autoLiquidate trims gross notional proportionally across long and
-// short positions to restore margin compliance.
+// autoLiquidate trims gross notional proportionally across long, short,
+// and futures positions to restore margin compliance.
 func (eng *Engine) autoLiquidate(ctx context.Context, acct portfolio.PortfolioManager, date time.Time) error {
 	deficiency := acct.MarginDeficiency()
 	if deficiency == 0 {
 		return nil
 	}
 
-	gross := acct.LongMarketValue() + acct.ShortMarketValue()
+	gross := acct.LongMarketValue() + acct.ShortMarketValue() + acct.FuturesNotional()
 	if gross <= 0 {
 		return nil
 	}
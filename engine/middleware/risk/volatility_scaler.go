@@ -149,9 +149,11 @@ func (vs *volatilityScaler) Process(ctx context.Context, batch *portfolio.Batch)
 }
 
 // computeAnnualizedVol computes annualized realized volatility from daily
-// close prices: stddev(daily log returns) * sqrt(252). The price series is
-// trimmed to the last lookback+1 rows so vol covers exactly lookback trading
-// days even though the fetch window is wider (calendar days).
+// close prices: stddev(daily log returns) * sqrt(N), where N is the trading
+// days per year of the frame's market calendar (252 for NYSE, 365 for
+// crypto). The price series is trimmed to the last lookback+1 rows so vol
+// covers exactly lookback trading days even though the fetch window is
+// wider (calendar days).
 // Returns NaN if insufficient data (need at least 2 prices).
 func computeAnnualizedVol(priceFrame *data.DataFrame, ast asset.Asset, lookback int) float64 {
 	if priceFrame == nil {
@@ -199,5 +201,5 @@ func computeAnnualizedVol(priceFrame *data.DataFrame, ast asset.Asset, lookback
 	stdDev := math.Sqrt(variance)
 
 	// Annualize.
-	return stdDev * math.Sqrt(252.0)
+	return stdDev * math.Sqrt(data.Daily.PeriodsPerYearOn(priceFrame.Calendar()))
 }
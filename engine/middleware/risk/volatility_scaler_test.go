@@ -17,6 +17,7 @@ package risk_test
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"time"
 
@@ -28,6 +29,7 @@ import (
 	"github.com/penny-vault/pvbt/data"
 	"github.com/penny-vault/pvbt/engine/middleware/risk"
 	"github.com/penny-vault/pvbt/portfolio"
+	"github.com/penny-vault/pvbt/tradecron"
 )
 
 // mockDataSource is a test double for risk.DataSource that returns
@@ -36,6 +38,8 @@ type mockDataSource struct {
 	// pricesByAsset maps CompositeFigi to a slice of daily close prices.
 	pricesByAsset map[string][]float64
 	currentDate   time.Time
+	// calendar, when set, is attached to the returned frames.
+	calendar tradecron.Calendar
 }
 
 func (m *mockDataSource) Fetch(_ context.Context, assets []asset.Asset, _ data.Period, _ []data.Metric) (*data.DataFrame, error) {
@@ -78,7 +82,14 @@ func (m *mockDataSource) Fetch(_ context.Context, assets []asset.Asset, _ data.P
 		columns[assetIdx] = col
 	}
 
-	return data.NewDataFrame(times, assets, metrics, data.Daily, columns)
+	df, err := data.NewDataFrame(times, assets, metrics, data.Daily, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	df.SetCalendar(m.calendar)
+
+	return df, nil
 }
 
 func (m *mockDataSource) FetchAt(_ context.Context, _ []asset.Asset, _ time.Time, _ []data.Metric) (*data.DataFrame, error) {
@@ -301,6 +312,51 @@ var _ = Describe("VolatilityScaler", func() {
 			Expect(annotation).To(ContainSubstring("vol="))
 		})
 
+		It("annualizes volatility over the frame's market calendar", func() {
+			positions := map[asset.Asset]struct {
+				price float64
+				qty   float64
+			}{
+				highVolAsset: {price: 100, qty: 50},
+				lowVolAsset:  {price: 100, qty: 50},
+			}
+
+			acct := buildAccountWithPositions(0, positions)
+			batch := portfolio.NewBatch(ts, acct)
+
+			ds := &mockDataSource{
+				pricesByAsset: map[string][]float64{
+					highVolAsset.CompositeFigi: highVolPrices,
+					lowVolAsset.CompositeFigi:  lowVolPrices,
+				},
+				currentDate: ts,
+				calendar:    tradecron.Crypto,
+			}
+
+			mw := risk.VolatilityScaler(ds, 20)
+			Expect(mw.Process(ctx, batch)).To(Succeed())
+
+			returns := make([]float64, len(highVolPrices)-1)
+			mean := 0.0
+
+			for idx := range returns {
+				returns[idx] = math.Log(highVolPrices[idx+1] / highVolPrices[idx])
+				mean += returns[idx]
+			}
+
+			mean /= float64(len(returns))
+
+			variance := 0.0
+			for _, ret := range returns {
+				variance += (ret - mean) * (ret - mean)
+			}
+
+			stdDev := math.Sqrt(variance / float64(len(returns)-1))
+
+			Expect(batch.Annotations["risk:volatility-scaler"]).To(
+				ContainSubstring(fmt.Sprintf("HIGH: vol=%.1f%%", stdDev*math.Sqrt(365)*100)))
+		})
+
 		It("handles an empty portfolio gracefully", func() {
 			acct := portfolio.New(portfolio.WithCash(10000, time.Time{}))
 			batch := portfolio.NewBatch(ts, acct)
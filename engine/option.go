@@ -16,21 +16,68 @@
 package engine
 
 import (
+	"slices"
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
 	"github.com/penny-vault/pvbt/broker"
 	"github.com/penny-vault/pvbt/data"
 	"github.com/penny-vault/pvbt/portfolio"
+	"github.com/penny-vault/pvbt/tradecron"
 )
 
 // Option configures the engine.
 type Option func(*Engine)
 
 // WithDataProvider registers one or more data providers with the engine.
+// When several providers serve the same metric, the most recently
+// registered takes precedence and the others fill its gaps; see
+// WithProviderPrecedence to set the order explicitly.
 func WithDataProvider(providers ...data.DataProvider) Option {
 	return func(e *Engine) {
 		e.providers = append(e.providers, providers...)
 	}
 }
 
+// WithProviderPrecedence orders data providers into fallback chains,
+// highest precedence first. For every metric, the listed providers that
+// serve it are consulted in this order, followed by any other registered
+// provider serving it (most recently registered first). Cells a provider
+// is missing or returns as NaN are filled from the next provider in the
+// chain. Providers not yet registered are registered as by
+// WithDataProvider.
+func WithProviderPrecedence(providers ...data.DataProvider) Option {
+	return func(e *Engine) {
+		e.registerProviders(providers)
+		e.providerPrecedence = providers
+	}
+}
+
+// WithMetricPrecedence sets the provider chain for a single metric,
+// highest precedence first, overriding WithProviderPrecedence. Only the
+// listed providers are consulted for the metric. Providers not yet
+// registered are registered as by WithDataProvider.
+func WithMetricPrecedence(metric data.Metric, providers ...data.DataProvider) Option {
+	return func(e *Engine) {
+		e.registerProviders(providers)
+
+		if e.metricPrecedence == nil {
+			e.metricPrecedence = make(map[data.Metric][]data.DataProvider)
+		}
+
+		e.metricPrecedence[metric] = providers
+	}
+}
+
+// registerProviders appends the providers that are not already registered.
+func (e *Engine) registerProviders(providers []data.DataProvider) {
+	for _, provider := range providers {
+		if !slices.Contains(e.providers, provider) {
+			e.providers = append(e.providers, provider)
+		}
+	}
+}
+
 // WithAssetProvider sets the asset provider for ticker resolution.
 func WithAssetProvider(p data.AssetProvider) Option {
 	return func(e *Engine) {
@@ -46,6 +93,17 @@ func WithCacheMaxBytes(n int64) Option {
 	}
 }
 
+// WithDiskCache adds a persistent cache tier below the in-memory column
+// cache. Column chunks fetched from providers that implement
+// data.CacheKeyed are written to the cache and read back by later runs.
+// The same DiskCache can be passed to every engine of a study so that
+// concurrent workers fetch each chunk from the provider once.
+func WithDiskCache(cache *DiskCache) Option {
+	return func(e *Engine) {
+		e.diskCache = cache
+	}
+}
+
 // WithInitialDeposit sets the starting cash balance for the portfolio.
 // Mutually exclusive with WithPortfolioSnapshot.
 func WithInitialDeposit(amount float64) Option {
@@ -54,6 +112,50 @@ func WithInitialDeposit(amount float64) Option {
 	}
 }
 
+// WithCashFlows schedules external deposits and withdrawals during a
+// backtest, in addition to the initial deposit. Each flow is posted as a
+// DepositTransaction or WithdrawalTransaction on the parent account
+// before the strategy runs that day, so the strategy can invest
+// contributions or raise cash for withdrawals. Ignored by RunLive.
+func WithCashFlows(flows ...CashFlow) Option {
+	return func(e *Engine) {
+		e.cashFlows = append(e.cashFlows, flows...)
+	}
+}
+
+// WithDividendReinvestment reinvests cash dividends in the paying asset
+// as soon as they are credited, in backtests and live trading alike. It
+// is applied to the engine's account, including one supplied with
+// WithAccount or restored from a checkpoint.
+func WithDividendReinvestment(drip portfolio.DividendReinvestment) Option {
+	return func(e *Engine) {
+		e.dividendReinvestment = &drip
+	}
+}
+
+// WithFXSeries sets the exchange-rate series used to convert prices and
+// cash in cur to US dollars, replacing the FRED series the engine uses by
+// default. The engine fetches rates for every currency an asset in the
+// registry is quoted in, plus any configured here.
+func WithFXSeries(cur asset.Currency, series FXSeries) Option {
+	return func(e *Engine) {
+		if e.fxSeries == nil {
+			e.fxSeries = make(map[asset.Currency]FXSeries)
+		}
+
+		e.fxSeries[cur] = series
+	}
+}
+
+// WithCurrencyHedge hedges the account's foreign-currency exposure with
+// monthly-rolled forwards. It is applied to the engine's account,
+// including one supplied with WithAccount or restored from a checkpoint.
+func WithCurrencyHedge(hedge portfolio.CurrencyHedge) Option {
+	return func(e *Engine) {
+		e.currencyHedge = &hedge
+	}
+}
+
 // WithBroker sets the broker used for order execution. If not set,
 // the engine defaults to a SimulatedBroker.
 func WithBroker(b broker.Broker) Option {
@@ -123,6 +225,18 @@ func WithBenchmarkTicker(ticker string) Option {
 	}
 }
 
+// WithCalendar sets the market calendar strategy schedules, cash flows,
+// warmup, and daily equity recording follow, e.g. tradecron.Crypto for a
+// 24/7, 365-day market. The calendar's trading days per year also set
+// the daily accrual of the risk-free rate and short borrow fees. Without
+// it, the engine uses the calendar of the PrimaryExchange the strategy's
+// assets list on (see tradecron.Lookup), falling back to tradecron.XNYS.
+func WithCalendar(cal tradecron.Calendar) Option {
+	return func(e *Engine) {
+		e.calendar = cal
+	}
+}
+
 // WithAccount sets a pre-configured portfolio Account for the engine
 // to use. When set, this takes priority over WithInitialDeposit,
 // WithPortfolioSnapshot, and WithBroker.
@@ -149,6 +263,16 @@ func WithFillModel(base broker.BaseModel, adjusters ...broker.Adjuster) Option {
 	}
 }
 
+// WithCommissionModel charges commissions and fees on every fill made by
+// the SimulatedBroker. Each fee is recorded as a FeeTransaction linked to
+// the order. If WithBroker is used, the commission model is ignored; a
+// real broker reports its own fees.
+func WithCommissionModel(model broker.CommissionModel) Option {
+	return func(e *Engine) {
+		e.commissionModel = model
+	}
+}
+
 // WithMiddlewareConfig sets the middleware configuration. The engine
 // constructs risk and tax middleware from this config during initialization.
 // When set, config-driven middleware replaces any strategy-declared middleware.
@@ -177,3 +301,59 @@ func WithUserParams(names ...string) Option {
 		e.MarkUserParams(names...)
 	}
 }
+
+// WithLiveStateDir enables crash-safe live sessions. After every step,
+// RunLive atomically writes the account to CheckpointFileName inside dir,
+// creating the directory if needed. Ignored by Backtest.
+func WithLiveStateDir(dir string) Option {
+	return func(e *Engine) {
+		e.liveStateDir = dir
+	}
+}
+
+// WithStreamQuoteMaxAge sets how old a streamed quote may be before
+// RunLive treats it as missing and falls back to batch prices. The
+// default is DefaultStreamQuoteMaxAge. Ignored by Backtest.
+func WithStreamQuoteMaxAge(age time.Duration) Option {
+	return func(e *Engine) {
+		e.streamMaxAge = age
+	}
+}
+
+// WithResumeCheckpoint restores a live session from a checkpoint written
+// by WithLiveStateDir. RunLive rebuilds the account from the file,
+// replays broker fills executed since the checkpoint for brokers that
+// implement broker.FillReplayer, and resumes syncing broker transactions
+// from where the checkpoint left off. Takes precedence over
+// WithInitialDeposit and WithPortfolioSnapshot.
+func WithResumeCheckpoint(path string) Option {
+	return func(e *Engine) {
+		e.resumePath = path
+	}
+}
+
+// WithDataQualityPolicy audits the daily prices every data provider
+// returns with data.Audit using cfg, and applies policy whenever Fetch or
+// FetchAt hands the strategy a frame that includes flagged cells. Cells a
+// higher-precedence provider replaced do not count. Providers that
+// already implement data.AnomalyReporter are consulted as they are;
+// others are wrapped in a data.AuditingProvider. Chunks the disk cache
+// serves for an audited provider are audited as they are read. The
+// engine's own reads, such as fill prices, are not checked.
+func WithDataQualityPolicy(policy DataQualityPolicy, cfg data.AuditConfig) Option {
+	return func(e *Engine) {
+		e.dataQuality = policy
+		e.auditConfig = cfg
+	}
+}
+
+// WithBrokerReconciliation enables broker reconciliation in RunLive. At
+// startup and before each strategy step the engine compares the account's
+// holdings and cash with the broker's Positions and Balance, logs a drift
+// report, and applies cfg.Policy. Ignored by Backtest and when the broker
+// is a SimulatedBroker, which keeps no positions or balance of its own.
+func WithBrokerReconciliation(cfg ReconcileConfig) Option {
+	return func(e *Engine) {
+		e.reconcile = &cfg
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine_test
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/engine"
+	"github.com/penny-vault/pvbt/portfolio"
+)
+
+// recordingBatchProvider is a TestProvider that records every request
+// it is asked to fetch.
+type recordingBatchProvider struct {
+	*data.TestProvider
+	mu       sync.Mutex
+	requests []data.DataRequest
+}
+
+func (p *recordingBatchProvider) Fetch(ctx context.Context, req data.DataRequest) (*data.DataFrame, error) {
+	p.mu.Lock()
+	p.requests = append(p.requests, req)
+	p.mu.Unlock()
+
+	return p.TestProvider.Fetch(ctx, req)
+}
+
+var _ = Describe("Provider chains", func() {
+	var (
+		aapl    asset.Asset
+		msft    asset.Asset
+		metrics []data.Metric
+		vendor  *data.TestProvider
+		patch   *data.TestProvider
+		start   time.Time
+		end     time.Time
+	)
+
+	day := func(d int) time.Time {
+		return time.Date(2024, 1, d, 16, 0, 0, 0, time.UTC)
+	}
+
+	BeforeEach(func() {
+		aapl = asset.Asset{CompositeFigi: "FIGI-AAPL", Ticker: "AAPL"}
+		msft = asset.Asset{CompositeFigi: "FIGI-MSFT", Ticker: "MSFT"}
+		metrics = []data.Metric{data.MetricClose}
+
+		// The vendor covers both assets every day from Jan 1 to Jan 20,
+		// with a hole in AAPL on Jan 10.
+		vendorTimes := make([]time.Time, 20)
+		aaplCloses := make([]float64, 20)
+		msftCloses := make([]float64, 20)
+
+		for idx := range vendorTimes {
+			vendorTimes[idx] = day(idx + 1)
+			aaplCloses[idx] = 100 + float64(idx)
+			msftCloses[idx] = 300 + float64(idx)
+		}
+
+		aaplCloses[9] = math.NaN()
+
+		vendorDF, err := data.NewDataFrame(vendorTimes, []asset.Asset{aapl, msft}, metrics, data.Daily,
+			[][]float64{aaplCloses, msftCloses})
+		Expect(err).NotTo(HaveOccurred())
+
+		// The patch corrects AAPL on Jan 5 and Jan 6 only.
+		patchDF, err := data.NewDataFrame([]time.Time{day(5), day(6)}, []asset.Asset{aapl}, metrics, data.Daily,
+			[][]float64{{1005, 1006}})
+		Expect(err).NotTo(HaveOccurred())
+
+		vendor = data.NewTestProvider(metrics, vendorDF)
+		patch = data.NewTestProvider(metrics, patchDF)
+
+		start = time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)
+		end = time.Date(2024, 1, 19, 23, 0, 0, 0, time.UTC)
+	})
+
+	run := func(opts ...engine.Option) (*engine.Engine, *data.DataFrame) {
+		strategy := &fetchStrategy{
+			lookback:  portfolio.Days(30),
+			metrics:   metrics,
+			assets:    []asset.Asset{aapl, msft},
+			firstOnly: true,
+		}
+
+		opts = append(opts,
+			engine.WithAssetProvider(&mockAssetProvider{assets: []asset.Asset{aapl, msft}}),
+			engine.WithInitialDeposit(100_000.0),
+		)
+
+		eng := engine.New(strategy, opts...)
+
+		_, err := eng.Backtest(context.Background(), start, end)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strategy.fetchErr).NotTo(HaveOccurred())
+
+		return eng, strategy.fetched
+	}
+
+	valueAt := func(df *data.DataFrame, a asset.Asset, at time.Time) float64 {
+		col := df.Column(a, data.MetricClose)
+		for idx, t := range df.Times() {
+			if t.Equal(at) {
+				return col[idx]
+			}
+		}
+
+		Fail("no row at " + at.String())
+
+		return math.NaN()
+	}
+
+	It("fills cells missing from the preferred provider from the next one", func() {
+		eng, df := run(engine.WithProviderPrecedence(patch, vendor))
+
+		Expect(valueAt(df, aapl, day(5))).To(Equal(1005.0))
+		Expect(valueAt(df, aapl, day(6))).To(Equal(1006.0))
+		Expect(valueAt(df, aapl, day(4))).To(Equal(103.0))
+		Expect(valueAt(df, aapl, day(15))).To(Equal(114.0))
+		Expect(valueAt(df, msft, day(5))).To(Equal(304.0))
+
+		Expect(eng.Provenance(aapl, data.MetricClose, day(5))).To(BeIdenticalTo(patch))
+		Expect(eng.Provenance(aapl, data.MetricClose, day(4))).To(BeIdenticalTo(vendor))
+		Expect(eng.Provenance(msft, data.MetricClose, day(5))).To(BeIdenticalTo(vendor))
+	})
+
+	It("leaves a cell NaN when no provider in the chain has it", func() {
+		_, df := run(engine.WithProviderPrecedence(patch, vendor))
+
+		Expect(math.IsNaN(valueAt(df, aapl, day(10)))).To(BeTrue())
+	})
+
+	It("prefers the most recently registered provider by default", func() {
+		eng, df := run(engine.WithDataProvider(vendor), engine.WithDataProvider(patch))
+
+		Expect(valueAt(df, aapl, day(5))).To(Equal(1005.0))
+		Expect(valueAt(df, aapl, day(4))).To(Equal(103.0))
+		Expect(eng.Provenance(aapl, data.MetricClose, day(6))).To(BeIdenticalTo(patch))
+	})
+
+	It("restricts a metric to the providers named by WithMetricPrecedence", func() {
+		eng, df := run(
+			engine.WithProviderPrecedence(patch, vendor),
+			engine.WithMetricPrecedence(data.MetricClose, vendor),
+		)
+
+		Expect(valueAt(df, aapl, day(5))).To(Equal(104.0))
+		Expect(eng.Provenance(aapl, data.MetricClose, day(5))).To(BeIdenticalTo(vendor))
+	})
+
+	It("asks the next provider only for the assets and dates still missing", func() {
+		// The preferred provider covers both assets every day of 2024
+		// except for a NaN in AAPL on Jan 10.
+		times := make([]time.Time, 366)
+		aaplCloses := make([]float64, 366)
+		msftCloses := make([]float64, 366)
+
+		for idx := range times {
+			times[idx] = day(1).AddDate(0, 0, idx)
+			aaplCloses[idx] = 100 + float64(idx)
+			msftCloses[idx] = 300 + float64(idx)
+		}
+
+		aaplCloses[9] = math.NaN()
+
+		fullDF, err := data.NewDataFrame(times, []asset.Asset{aapl, msft}, metrics, data.Daily,
+			[][]float64{aaplCloses, msftCloses})
+		Expect(err).NotTo(HaveOccurred())
+
+		preferred := data.NewTestProvider(metrics, fullDF)
+		fallback := &recordingBatchProvider{TestProvider: data.NewTestProvider(metrics, fullDF)}
+
+		start = time.Date(2024, 2, 20, 0, 0, 0, 0, time.UTC)
+		end = time.Date(2024, 2, 20, 23, 0, 0, 0, time.UTC)
+
+		run(engine.WithProviderPrecedence(preferred, fallback))
+
+		Expect(fallback.requests).To(HaveLen(1))
+		Expect(fallback.requests[0].Assets).To(ConsistOf(aapl))
+		Expect(fallback.requests[0].Start).To(BeTemporally("==", day(10)))
+		Expect(fallback.requests[0].End).To(BeTemporally("==", day(10)))
+	})
+
+	It("reports no provenance for data that was never fetched", func() {
+		eng, _ := run(engine.WithProviderPrecedence(patch, vendor))
+
+		Expect(eng.Provenance(aapl, data.MetricClose, time.Date(2019, 1, 2, 16, 0, 0, 0, time.UTC))).To(BeNil())
+	})
+})
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"time"
 
 	"github.com/penny-vault/pvbt/asset"
@@ -31,6 +32,14 @@ import (
 
 const fillChannelSize = 1024
 
+// workingOrder tracks an order held for a later bar by a fill model that
+// does not fill on the bar of submission.
+type workingOrder struct {
+	order     broker.Order
+	submitted time.Time
+	seq       int
+}
+
 // partialRemainder tracks an order that was partially filled and is
 // waiting to be retried on the next bar.
 type partialRemainder struct {
@@ -39,7 +48,9 @@ type partialRemainder struct {
 }
 
 // SimulatedBroker fills all orders at the close price for backtesting.
-// The engine sets a PriceProvider and date before each Compute step.
+// The engine sets a PriceProvider and date before each Compute step. A
+// fill pipeline whose base model is a broker.NextBarModel holds orders
+// until EvaluatePending runs on the following bar.
 type SimulatedBroker struct {
 	prices            broker.PriceProvider
 	date              time.Time
@@ -50,9 +61,17 @@ type SimulatedBroker struct {
 	initialMarginRate float64
 	maxLeverage       float64
 	borrowRate        float64
+	tradingDays       float64
 	lastPrices        map[asset.Asset]float64
 	fillPipeline      *broker.Pipeline
+	commission        broker.CommissionModel
 	partialRemainders map[string]partialRemainder
+	working           map[string]workingOrder
+	workingSeq        int
+	algos             map[string]*algoOrder
+	intraday          IntradayProvider
+	clock             func() time.Time
+	reports           []broker.ExecutionReport
 }
 
 // NewSimulatedBroker creates a SimulatedBroker with no price provider set.
@@ -64,6 +83,9 @@ func NewSimulatedBroker() *SimulatedBroker {
 		lastPrices:        make(map[asset.Asset]float64),
 		fillPipeline:      broker.NewPipeline(broker.FillAtClose(), nil),
 		partialRemainders: make(map[string]partialRemainder),
+		working:           make(map[string]workingOrder),
+		algos:             make(map[string]*algoOrder),
+		tradingDays:       252,
 	}
 }
 
@@ -88,6 +110,14 @@ func (b *SimulatedBroker) SetBorrowRate(rate float64) {
 	b.borrowRate = rate
 }
 
+// SetTradingDaysPerYear sets the number of trading days per year used to
+// convert the annualized borrow rate to a daily fee. The default is 252.
+func (b *SimulatedBroker) SetTradingDaysPerYear(days float64) {
+	if days > 0 {
+		b.tradingDays = days
+	}
+}
+
 // SetMaxLeverage sets the gross-leverage cap (LMV+SMV)/Equity used to
 // reject orders that would push the account above the cap. Values <= 0
 // disable the broker-level check; the account-level default still
@@ -101,6 +131,29 @@ func (b *SimulatedBroker) SetFillPipeline(pp *broker.Pipeline) {
 	b.fillPipeline = pp
 }
 
+// SetCommissionModel sets the model used to charge commissions and fees
+// on every fill. A nil model charges nothing.
+func (b *SimulatedBroker) SetCommissionModel(model broker.CommissionModel) {
+	b.commission = model
+}
+
+// executedFill builds the Fill for an executed order, attaching any
+// commissions and fees charged by the commission model.
+func (b *SimulatedBroker) executedFill(order broker.Order, price, qty float64) broker.Fill {
+	fill := broker.Fill{
+		OrderID:  order.ID,
+		Price:    price,
+		Qty:      qty,
+		FilledAt: b.date,
+	}
+
+	if b.commission != nil {
+		fill.Fees = b.commission.Fees(order, fill)
+	}
+
+	return fill
+}
+
 // SetDataFetcher propagates a DataFetcher to the fill pipeline's models.
 func (b *SimulatedBroker) SetDataFetcher(df broker.DataFetcher) {
 	b.fillPipeline.SetDataFetcher(df)
@@ -121,6 +174,10 @@ func (b *SimulatedBroker) Fills() <-chan broker.Fill {
 // channel is full it is replaced with one twice the size, preserving fill
 // order; consumers re-fetch the channel via Fills() on every drain.
 func (b *SimulatedBroker) deliverFill(fill broker.Fill) {
+	if algo, ok := b.algos[fill.OrderID]; ok && fill.Err == nil {
+		algo.record(fill)
+	}
+
 	select {
 	case b.fills <- fill:
 		return
@@ -148,6 +205,10 @@ func (b *SimulatedBroker) deliverFill(fill broker.Fill) {
 // and the originating strategy can react to it, instead of the order silently
 // vanishing or the simulation aborting.
 func (b *SimulatedBroker) failOrder(order broker.Order, reason error) {
+	if algo, ok := b.algos[order.ID]; ok {
+		algo.stopped = true
+	}
+
 	b.deliverFill(broker.Fill{
 		OrderID:  order.ID,
 		FilledAt: b.date,
@@ -155,6 +216,33 @@ func (b *SimulatedBroker) failOrder(order broker.Order, reason error) {
 	})
 }
 
+// checkFuturesMargin rejects a futures order that increases the number of
+// open contracts when the account's equity does not cover the initial
+// margin of its futures positions after the fill.
+func (b *SimulatedBroker) checkFuturesMargin(order broker.Order, qty float64) error {
+	currentPos := b.portfolio.Position(order.Asset)
+
+	postQty := currentPos + qty
+	if order.Side == broker.Sell {
+		postQty = currentPos - qty
+	}
+
+	added := math.Abs(postQty) - math.Abs(currentPos)
+	if added <= 0 {
+		return nil
+	}
+
+	initial, _ := b.portfolio.FuturesMargin()
+	required := initial + added*order.Asset.Contract.InitialMargin*b.portfolio.FXRate(order.Asset.QuoteCurrency())
+
+	if equity := b.portfolio.Equity(); equity < required {
+		return fmt.Errorf("order rejected: insufficient margin for %s: equity %.2f below initial margin %.2f",
+			order.Asset.Ticker, equity, required)
+	}
+
+	return nil
+}
+
 func (b *SimulatedBroker) Submit(ctx context.Context, order broker.Order) error {
 	if order.GroupRole == broker.RoleStopLoss || order.GroupRole == broker.RoleTakeProfit {
 		b.pending[order.ID] = order
@@ -170,6 +258,27 @@ func (b *SimulatedBroker) Submit(ctx context.Context, order broker.Order) error
 		return fmt.Errorf("simulated broker: no price provider set")
 	}
 
+	// Execution algorithms work the order as child orders over the
+	// minute bars that follow; EvaluatePending fills them.
+	if order.ExecAlgo != broker.ExecImmediate {
+		return b.submitAlgo(ctx, order)
+	}
+
+	// Trailing stops rest at the broker; EvaluatePending triggers and
+	// ratchets them on each following bar.
+	if order.OrderType.IsTrailing() {
+		return b.submitTrailing(ctx, order)
+	}
+
+	// Next-bar models must not see the bar the order was computed on;
+	// hold the order until EvaluatePending runs on the following bar.
+	if b.fillPipeline.FillsOnNextBar() {
+		b.workingSeq++
+		b.working[order.ID] = workingOrder{order: order, submitted: b.date, seq: b.workingSeq}
+
+		return nil
+	}
+
 	df, err := b.prices.Prices(ctx, order.Asset)
 	if err != nil {
 		// No price to fill against (e.g. an asset whose intraday data
@@ -189,8 +298,51 @@ func (b *SimulatedBroker) Submit(ctx context.Context, order broker.Order) error
 		return fmt.Errorf("simulated broker: fetching price for %s: %w", order.Asset.Ticker, err)
 	}
 
+	if err := b.fillOrder(ctx, order, df); err != nil {
+		b.failOrder(order, err)
+	}
+
+	return nil
+}
+
+// submitTrailing rests a trailing stop order. An order without a stop
+// price starts its trail from the current close.
+func (b *SimulatedBroker) submitTrailing(ctx context.Context, order broker.Order) error {
+	if order.StopPrice == 0 {
+		df, err := b.prices.Prices(ctx, order.Asset)
+		if err != nil {
+			b.failOrder(order, fmt.Errorf("no price available to start trailing stop for %s: %w", order.Asset.Ticker, err))
+
+			return nil
+		}
+
+		closePrice := df.Value(order.Asset, data.MetricClose)
+		if math.IsNaN(closePrice) || closePrice == 0 {
+			b.failOrder(order, fmt.Errorf("no price available to start trailing stop for %s", order.Asset.Ticker))
+
+			return nil
+		}
+
+		order.StopPrice = broker.TrailStop(order, closePrice)
+	}
+
+	b.pending[order.ID] = order
+
+	return nil
+}
+
+// fillOrder runs the fill pipeline for order against df, applies the
+// margin checks, and delivers the resulting fill or failure. It returns
+// an error wrapping broker.ErrPriceNotReached, without resolving the
+// order, when the base model reports that the order's limit or stop
+// price was not reached on this bar.
+func (b *SimulatedBroker) fillOrder(ctx context.Context, order broker.Order, df *data.DataFrame) error {
 	// Phase 1: Base model determines the price.
 	baseResult, baseErr := b.fillPipeline.FillBase(ctx, order, df)
+	if errors.Is(baseErr, broker.ErrPriceNotReached) {
+		return baseErr
+	}
+
 	if baseErr != nil {
 		zerolog.Ctx(ctx).Warn().
 			Err(baseErr).
@@ -201,10 +353,15 @@ func (b *SimulatedBroker) Submit(ctx context.Context, order broker.Order) error
 		return nil
 	}
 
-	// Convert dollar-amount orders between base and adjusters.
+	// Convert dollar-amount orders between base and adjusters. A futures
+	// or option contract's notional is its price times its multiplier.
+	// Crypto always trades in fractional units.
 	qty := baseResult.Quantity
 	if qty == 0 && order.Amount > 0 {
-		qty = math.Floor(order.Amount / baseResult.Price)
+		qty = order.Amount / (baseResult.Price * order.Asset.ContractMultiplier())
+		if !order.Fractional && !order.Asset.IsCrypto() {
+			qty = math.Floor(qty)
+		}
 	}
 
 	if qty == 0 {
@@ -227,9 +384,25 @@ func (b *SimulatedBroker) Submit(ctx context.Context, order broker.Order) error
 		return nil
 	}
 
+	// Contracts with a tick size trade in whole ticks.
+	result.Price = order.Asset.Contract.RoundToTick(result.Price)
+
+	// Futures are margined per contract rather than by notional.
+	if b.portfolio != nil && order.Asset.IsFuture() {
+		if err := b.checkFuturesMargin(order, result.Quantity); err != nil {
+			zerolog.Ctx(ctx).Warn().
+				Str("asset", order.Asset.Ticker).
+				Err(err).
+				Msg("order rejected: insufficient futures margin")
+			b.failOrder(order, err)
+
+			return nil
+		}
+	}
+
 	// Margin checks: initial margin on short-opening sells, and the
 	// gross-leverage cap on any order that increases gross notional.
-	if b.portfolio != nil {
+	if b.portfolio != nil && !order.Asset.IsFuture() {
 		currentPos := b.portfolio.Position(order.Asset)
 
 		signedDelta := result.Quantity
@@ -244,7 +417,12 @@ func (b *SimulatedBroker) Submit(ctx context.Context, order broker.Order) error
 		postLong := math.Max(postQty, 0)
 		postShort := math.Max(-postQty, 0)
 
-		newShortValue := b.portfolio.ShortMarketValue() + (postShort-preShort)*result.Price
+		// Market values are in the base currency; fill prices are in
+		// the asset's quote currency and per unit of an option's
+		// underlying.
+		basePrice := result.Price * order.Asset.ContractMultiplier() * b.portfolio.FXRate(order.Asset.QuoteCurrency())
+
+		newShortValue := b.portfolio.ShortMarketValue() + (postShort-preShort)*basePrice
 		equity := b.portfolio.Equity()
 
 		if order.Side == broker.Sell && postQty < 0 && newShortValue > 0 {
@@ -268,7 +446,7 @@ func (b *SimulatedBroker) Submit(ctx context.Context, order broker.Order) error
 		if b.maxLeverage > 0 {
 			preLongValue := b.portfolio.LongMarketValue()
 			preGross := preLongValue + b.portfolio.ShortMarketValue()
-			newLongValue := preLongValue + (postLong-preLong)*result.Price
+			newLongValue := preLongValue + (postLong-preLong)*basePrice
 			postGross := newLongValue + newShortValue
 
 			// Only reject orders that increase gross notional; allow
@@ -295,12 +473,7 @@ func (b *SimulatedBroker) Submit(ctx context.Context, order broker.Order) error
 		}
 	}
 
-	b.deliverFill(broker.Fill{
-		OrderID:  order.ID,
-		Price:    result.Price,
-		Qty:      result.Quantity,
-		FilledAt: b.date,
-	})
+	b.deliverFill(b.executedFill(order, result.Price, result.Quantity))
 
 	// Handle partial fills: queue remainder for next bar.
 	if result.Partial {
@@ -320,7 +493,20 @@ func (b *SimulatedBroker) Submit(ctx context.Context, order broker.Order) error
 	return nil
 }
 
-func (b *SimulatedBroker) Cancel(_ context.Context, orderID string) error {
+func (b *SimulatedBroker) Cancel(ctx context.Context, orderID string) error {
+	if algo, ok := b.algos[orderID]; ok {
+		algo.stopped = true
+		b.completeAlgo(ctx, algo)
+
+		return nil
+	}
+
+	if _, ok := b.working[orderID]; ok {
+		delete(b.working, orderID)
+
+		return nil
+	}
+
 	order, ok := b.pending[orderID]
 	if !ok {
 		return fmt.Errorf("simulated broker: order %s not found", orderID)
@@ -370,6 +556,11 @@ func (b *SimulatedBroker) EvaluatePending() {
 		return
 	}
 
+	// Orders held by a next-bar fill model fill before this bar's
+	// brackets are evaluated, so an entry and its exits never share a bar.
+	b.evaluateWorkingOrders()
+	b.evaluateAlgoOrders()
+
 	if len(b.pending) == 0 {
 		// No bracket orders, but still process partial remainders.
 		b.evaluatePartialRemainders()
@@ -497,15 +688,24 @@ func (b *SimulatedBroker) EvaluatePending() {
 			}
 		}
 
+		// A trailing stop that did not trigger follows this bar's extreme.
+		if hasStop && !stopTriggered && !tpTriggered && stopOrder.OrderType.IsTrailing() {
+			ratchetHigh, ratchetLow := high, low
+			if highUnavailable {
+				ratchetHigh = closePrice
+			}
+
+			if lowUnavailable {
+				ratchetLow = closePrice
+			}
+
+			b.pending[stopOrder.ID] = ratchetTrailingStop(stopOrder, ratchetHigh, ratchetLow)
+		}
+
 		switch {
 		case stopTriggered:
 			// Stop loss wins (pessimistic) — even if TP also triggered.
-			b.deliverFill(broker.Fill{
-				OrderID:  stopOrder.ID,
-				Price:    stopFillPrice,
-				Qty:      stopOrder.Qty,
-				FilledAt: b.date,
-			})
+			b.deliverFill(b.executedFill(stopOrder, stopFillPrice, stopOrder.Qty))
 
 			delete(b.pending, stopOrder.ID)
 
@@ -516,12 +716,7 @@ func (b *SimulatedBroker) EvaluatePending() {
 			delete(b.groups, groupID)
 
 		case tpTriggered:
-			b.deliverFill(broker.Fill{
-				OrderID:  tpOrder.ID,
-				Price:    tpFillPrice,
-				Qty:      tpOrder.Qty,
-				FilledAt: b.date,
-			})
+			b.deliverFill(b.executedFill(tpOrder, tpFillPrice, tpOrder.Qty))
 
 			delete(b.pending, tpOrder.ID)
 
@@ -533,10 +728,239 @@ func (b *SimulatedBroker) EvaluatePending() {
 		}
 	}
 
+	b.evaluateTrailingOrders(df)
+
 	// Process partial fill remainders from prior bars.
 	b.evaluatePartialRemainders()
 }
 
+// evaluateTrailingOrders triggers standalone trailing stop orders against
+// the current bar and ratchets the ones that do not trigger. The trigger
+// is checked against the stop carried in from the prior bar before the
+// stop follows this bar's high (for sells) or low (for buys), so a bar
+// never both raises a stop and hits it. A stop-limit that triggers
+// without filling rests from then on as a Limit order at its trail
+// limit.
+func (b *SimulatedBroker) evaluateTrailingOrders(df *data.DataFrame) {
+	for orderID, order := range b.pending {
+		triggeredLimit := order.OrderType == broker.Limit
+		if order.GroupID != "" || !(order.OrderType.IsTrailing() || triggeredLimit) {
+			continue
+		}
+
+		bar := barPrices(df, order.Asset)
+		if math.IsNaN(bar.high) || math.IsNaN(bar.low) {
+			continue
+		}
+
+		if triggeredLimit {
+			if price, filled := limitFill(order.Side, order.LimitPrice, bar); filled {
+				b.deliverFill(b.executedFill(order, price, order.Qty))
+				delete(b.pending, orderID)
+			}
+
+			continue
+		}
+
+		price, triggered, filled := trailingFill(order, bar)
+
+		switch {
+		case filled:
+			b.deliverFill(b.executedFill(order, price, order.Qty))
+			delete(b.pending, orderID)
+		case triggered:
+			// The stop-limit gapped past its limit. It stops trailing
+			// and waits for the price to come back to the limit.
+			order.OrderType = broker.Limit
+			order.LimitPrice = broker.TrailLimit(order, order.StopPrice)
+			b.pending[orderID] = order
+		default:
+			b.pending[orderID] = ratchetTrailingStop(order, bar.high, bar.low)
+		}
+	}
+}
+
+// ohlcBar holds one asset's prices from a bar.
+type ohlcBar struct {
+	open, high, low, close float64
+}
+
+// barPrices reads an asset's bar from df. Missing or zero prices are NaN;
+// a missing high or low falls back to the close.
+func barPrices(df *data.DataFrame, held asset.Asset) ohlcBar {
+	price := func(metric data.Metric) float64 {
+		value := df.Value(held, metric)
+		if value == 0 {
+			return math.NaN()
+		}
+
+		return value
+	}
+
+	bar := ohlcBar{
+		open:  price(data.MetricOpen),
+		high:  price(data.MetricHigh),
+		low:   price(data.MetricLow),
+		close: price(data.MetricClose),
+	}
+
+	if math.IsNaN(bar.high) {
+		bar.high = bar.close
+	}
+
+	if math.IsNaN(bar.low) {
+		bar.low = bar.close
+	}
+
+	return bar
+}
+
+// trailingFill evaluates a trailing stop order against a bar. It reports
+// whether the stop triggered and, if the order filled, the fill price. A
+// bar that opens beyond the stop fills a trailing stop at the open. A
+// trailing stop-limit fills at the stop when the bar trades through it,
+// at the open when the bar gaps past the stop but not the limit, at the
+// limit when the bar gaps past the limit and comes back, and otherwise
+// triggers without filling.
+func trailingFill(order broker.Order, bar ohlcBar) (price float64, triggered, filled bool) {
+	stop := order.StopPrice
+	sell := order.Side == broker.Sell
+
+	// beyond reports whether price is at or past level in the direction
+	// the order triggers: down for sells, up for buys.
+	beyond := func(price, level float64) bool {
+		if sell {
+			return price <= level
+		}
+
+		return price >= level
+	}
+
+	if (sell && !beyond(bar.low, stop)) || (!sell && !beyond(bar.high, stop)) {
+		return 0, false, false
+	}
+
+	gapped := !math.IsNaN(bar.open) && beyond(bar.open, stop)
+
+	if order.OrderType == broker.TrailingStop {
+		if gapped {
+			return bar.open, true, true
+		}
+
+		return stop, true, true
+	}
+
+	if !gapped {
+		return stop, true, true
+	}
+
+	price, filled = limitFill(order.Side, broker.TrailLimit(order, stop), bar)
+
+	return price, true, filled
+}
+
+// limitFill evaluates a limit order against a bar. It fills at the open
+// when the bar opens at or better than the limit, at the limit when the
+// bar trades through it, and otherwise not at all.
+func limitFill(side broker.Side, limit float64, bar ohlcBar) (float64, bool) {
+	sell := side == broker.Sell
+
+	switch {
+	case !math.IsNaN(bar.open) && (sell && bar.open >= limit || !sell && bar.open <= limit):
+		return bar.open, true
+	case sell && bar.high >= limit, !sell && bar.low <= limit:
+		return limit, true
+	default:
+		return 0, false
+	}
+}
+
+// ratchetTrailingStop moves a trailing order's stop after a bar: up to
+// trail the bar's high for sells, down to trail its low for buys. The
+// stop never moves against the position.
+func ratchetTrailingStop(order broker.Order, high, low float64) broker.Order {
+	if order.Side == broker.Sell {
+		if !math.IsNaN(high) && high != 0 {
+			order.StopPrice = math.Max(order.StopPrice, broker.TrailStop(order, high))
+		}
+
+		return order
+	}
+
+	if !math.IsNaN(low) && low != 0 {
+		order.StopPrice = math.Min(order.StopPrice, broker.TrailStop(order, low))
+	}
+
+	return order
+}
+
+// evaluateWorkingOrders fills orders held by a next-bar fill model
+// against the current bar. Orders submitted on the current bar wait for
+// the next one. An order whose limit or stop price is not reached stays
+// working when it is GTC, or GTD and not yet expired; otherwise it fails.
+func (b *SimulatedBroker) evaluateWorkingOrders() {
+	if b.prices == nil || len(b.working) == 0 {
+		return
+	}
+
+	ctx := context.Background()
+
+	due := make([]workingOrder, 0, len(b.working))
+	assetSet := make(map[string]asset.Asset)
+
+	for _, wo := range b.working {
+		if !wo.submitted.Before(b.date) {
+			continue
+		}
+
+		due = append(due, wo)
+		assetSet[wo.order.Asset.CompositeFigi] = wo.order.Asset
+	}
+
+	if len(due) == 0 {
+		return
+	}
+
+	// Fill in submission order, the order Submit would have used.
+	sort.Slice(due, func(i, j int) bool { return due[i].seq < due[j].seq })
+
+	assets := make([]asset.Asset, 0, len(assetSet))
+	for _, held := range assetSet {
+		assets = append(assets, held)
+	}
+
+	df, err := b.prices.Prices(ctx, assets...)
+	if err != nil {
+		return
+	}
+
+	for _, wo := range due {
+		notReached := b.fillOrder(ctx, wo.order, df)
+		if notReached != nil && stillWorking(wo.order, b.date) {
+			continue
+		}
+
+		delete(b.working, wo.order.ID)
+
+		if notReached != nil {
+			b.failOrder(wo.order, notReached)
+		}
+	}
+}
+
+// stillWorking reports whether an order whose price was not reached
+// remains open on date under its time in force.
+func stillWorking(order broker.Order, date time.Time) bool {
+	switch order.TimeInForce {
+	case broker.GTC:
+		return true
+	case broker.GTD:
+		return !date.After(order.GTDDate)
+	default:
+		return false
+	}
+}
+
 // evaluatePartialRemainders retries partial fill remainders from prior bars.
 // After two bars without a full fill, the remainder is cancelled.
 func (b *SimulatedBroker) evaluatePartialRemainders() {
@@ -581,12 +1005,7 @@ func (b *SimulatedBroker) evaluatePartialRemainders() {
 		}
 
 		if result.Quantity > 0 {
-			b.deliverFill(broker.Fill{
-				OrderID:  orderID,
-				Price:    result.Price,
-				Qty:      result.Quantity,
-				FilledAt: b.date,
-			})
+			b.deliverFill(b.executedFill(pr.order, result.Price, result.Quantity))
 		}
 
 		if !result.Partial {
@@ -613,11 +1032,19 @@ func (b *SimulatedBroker) Replace(_ context.Context, _ string, _ broker.Order) e
 }
 
 func (b *SimulatedBroker) Orders(_ context.Context) ([]broker.Order, error) {
-	orders := make([]broker.Order, 0, len(b.pending))
+	orders := make([]broker.Order, 0, len(b.pending)+len(b.working))
 	for _, order := range b.pending {
 		orders = append(orders, order)
 	}
 
+	for _, wo := range b.working {
+		orders = append(orders, wo.order)
+	}
+
+	for _, algo := range b.algos {
+		orders = append(orders, algo.order)
+	}
+
 	return orders, nil
 }
 
@@ -727,7 +1154,7 @@ func (b *SimulatedBroker) Transactions(ctx context.Context, _ time.Time) ([]brok
 
 		if qty < 0 && b.borrowRate > 0 {
 			if !math.IsNaN(closePrice) && closePrice != 0 {
-				dailyFee := math.Abs(qty) * closePrice * (b.borrowRate / 252.0)
+				dailyFee := math.Abs(qty) * closePrice * (b.borrowRate / b.tradingDays)
 				txns = append(txns, broker.Transaction{
 					ID:            fmt.Sprintf("sim-fee-%s-%s", ast.CompositeFigi, b.date.Format("2006-01-02")),
 					Date:          b.date,
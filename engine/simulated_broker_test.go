@@ -38,12 +38,16 @@ type mockPortfolio struct {
 	longMarketValue  float64
 }
 
-func (m *mockPortfolio) Position(a asset.Asset) float64      { return m.positions[a] }
-func (m *mockPortfolio) Equity() float64                     { return m.equity }
-func (m *mockPortfolio) ShortMarketValue() float64           { return m.shortMarketValue }
-func (m *mockPortfolio) Cash() float64                       { return 0 }
-func (m *mockPortfolio) Value() float64                      { return 0 }
-func (m *mockPortfolio) PositionValue(_ asset.Asset) float64 { return 0 }
+func (m *mockPortfolio) Position(a asset.Asset) float64           { return m.positions[a] }
+func (m *mockPortfolio) Equity() float64                          { return m.equity }
+func (m *mockPortfolio) ShortMarketValue() float64                { return m.shortMarketValue }
+func (m *mockPortfolio) Cash() float64                            { return 0 }
+func (m *mockPortfolio) CashBalances() map[asset.Currency]float64 { return nil }
+func (m *mockPortfolio) FXRate(asset.Currency) float64            { return 1 }
+func (m *mockPortfolio) FuturesNotional() float64                 { return 0 }
+func (m *mockPortfolio) FuturesMargin() (float64, float64)        { return 0, 0 }
+func (m *mockPortfolio) Value() float64                           { return 0 }
+func (m *mockPortfolio) PositionValue(_ asset.Asset) float64      { return 0 }
 func (m *mockPortfolio) Holdings() map[asset.Asset]float64 {
 	result := make(map[asset.Asset]float64, len(m.positions))
 	for ast, qty := range m.positions {
@@ -425,6 +429,41 @@ var _ = Describe("SimulatedBroker", func() {
 			Expect(fill.Qty).To(Equal(10.0))
 		})
 
+		It("margins futures per contract rather than by notional", func() {
+			es := asset.NewFuture("ESM25", asset.Contract{
+				Multiplier: 50, TickSize: 0.25, InitialMargin: 12_000,
+			})
+			simBroker := engine.NewSimulatedBroker()
+			simBroker.SetPriceProvider(&mockPriceProvider{
+				prices: map[asset.Asset]float64{es: 5_000.1},
+				date:   date,
+			}, date)
+			simBroker.SetMaxLeverage(1.0)
+			simBroker.SetPortfolio(&mockPortfolio{
+				positions: map[asset.Asset]float64{},
+				equity:    30_000,
+			})
+
+			// Two contracts carry 500,000 of notional against 30,000 of
+			// equity but need only 24,000 of initial margin.
+			Expect(simBroker.Submit(context.Background(), broker.Order{
+				Asset: es, Side: broker.Buy, Qty: 2, OrderType: broker.Market,
+			})).To(Succeed())
+
+			var fill broker.Fill
+			Eventually(simBroker.Fills()).Should(Receive(&fill))
+			Expect(fill.Err).NotTo(HaveOccurred())
+			Expect(fill.Qty).To(Equal(2.0))
+			Expect(fill.Price).To(Equal(5_000.0))
+
+			Expect(simBroker.Submit(context.Background(), broker.Order{
+				Asset: es, Side: broker.Sell, Qty: 3, OrderType: broker.Market,
+			})).To(Succeed())
+
+			Eventually(simBroker.Fills()).Should(Receive(&fill))
+			Expect(fill.Err).To(MatchError(ContainSubstring("insufficient margin")))
+		})
+
 		It("reports a failed fill and produces no error when asset has no price", func() {
 			unknown := asset.Asset{CompositeFigi: "FIGI-UNKNOWN", Ticker: "UNKNOWN"}
 			simBroker := engine.NewSimulatedBroker()
@@ -939,6 +978,418 @@ var _ = Describe("SimulatedBroker", func() {
 			// Price should include slippage: 200 + 200*0.01 = 202
 			Expect(ff.Price).To(Equal(202.0))
 		})
+
+		It("fills dollar-amount crypto orders in fractional units", func() {
+			btc := asset.Asset{CompositeFigi: "BTC-USD", Ticker: "BTC-USD", AssetType: asset.AssetTypeCrypto}
+
+			simBroker := engine.NewSimulatedBroker()
+			simBroker.SetPriceProvider(&mockPriceProvider{
+				prices: map[asset.Asset]float64{btc: 64_000.0},
+				date:   date,
+			}, date)
+
+			Expect(simBroker.Submit(context.Background(), broker.Order{
+				Asset:     btc,
+				Side:      broker.Buy,
+				Amount:    1_000,
+				OrderType: broker.Market,
+			})).To(Succeed())
+
+			var ff broker.Fill
+			Eventually(simBroker.Fills()).Should(Receive(&ff))
+			Expect(ff.Qty).To(BeNumerically("~", 1_000.0/64_000.0, 1e-12))
+		})
+	})
+
+	Context("Next-bar fill models", func() {
+		var nextDate time.Time
+
+		ohlcBar := func(at time.Time, open, high, low, closePrice float64) *data.DataFrame {
+			df, err := data.NewDataFrame([]time.Time{at}, []asset.Asset{aapl},
+				[]data.Metric{data.MetricOpen, data.MetricHigh, data.MetricLow, data.MetricClose},
+				data.Daily, [][]float64{{open}, {high}, {low}, {closePrice}})
+			Expect(err).NotTo(HaveOccurred())
+
+			return df
+		}
+
+		BeforeEach(func() {
+			nextDate = date.AddDate(0, 0, 1)
+		})
+
+		It("holds a NextOpen order until the next bar and fills at its open", func() {
+			simBroker := engine.NewSimulatedBroker()
+			simBroker.SetFillPipeline(broker.NewPipeline(broker.NextOpen(), nil))
+			simBroker.SetPriceProvider(&mockDFPriceProvider{df: ohlcBar(date, 148, 151, 147, 150)}, date)
+
+			Expect(simBroker.Submit(context.Background(), broker.Order{
+				ID: "buy-1", Asset: aapl, Side: broker.Buy, Qty: 10, OrderType: broker.Market,
+			})).To(Succeed())
+			Expect(drainFills(simBroker, 1)).To(BeEmpty())
+
+			orders, err := simBroker.Orders(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orders).To(HaveLen(1))
+
+			// Evaluating on the submission bar leaves the order working.
+			simBroker.EvaluatePending()
+			Expect(drainFills(simBroker, 1)).To(BeEmpty())
+
+			simBroker.SetPriceProvider(&mockDFPriceProvider{df: ohlcBar(nextDate, 152, 155, 151, 154)}, nextDate)
+			simBroker.EvaluatePending()
+
+			fills := drainFills(simBroker, 2)
+			Expect(fills).To(HaveLen(1))
+			Expect(fills[0].OrderID).To(Equal("buy-1"))
+			Expect(fills[0].Price).To(Equal(152.0))
+			Expect(fills[0].Qty).To(Equal(10.0))
+			Expect(fills[0].FilledAt).To(Equal(nextDate))
+
+			orders, err = simBroker.Orders(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orders).To(BeEmpty())
+		})
+
+		It("sizes dollar-amount orders at the next open", func() {
+			simBroker := engine.NewSimulatedBroker()
+			simBroker.SetFillPipeline(broker.NewPipeline(broker.NextOpen(), nil))
+			simBroker.SetPriceProvider(&mockDFPriceProvider{df: ohlcBar(date, 100, 100, 100, 100)}, date)
+
+			Expect(simBroker.Submit(context.Background(), broker.Order{
+				ID: "buy-1", Asset: aapl, Side: broker.Buy, Amount: 1000, OrderType: broker.Market,
+			})).To(Succeed())
+
+			simBroker.SetPriceProvider(&mockDFPriceProvider{df: ohlcBar(nextDate, 200, 200, 200, 200)}, nextDate)
+			simBroker.EvaluatePending()
+
+			fills := drainFills(simBroker, 1)
+			Expect(fills).To(HaveLen(1))
+			Expect(fills[0].Qty).To(Equal(5.0))
+		})
+
+		It("keeps a GTC limit working until the path touches it", func() {
+			simBroker := engine.NewSimulatedBroker()
+			simBroker.SetFillPipeline(broker.NewPipeline(broker.OHLCPath(broker.OpenHighLowClose), nil))
+			simBroker.SetPriceProvider(&mockDFPriceProvider{df: ohlcBar(date, 100, 101, 99, 100)}, date)
+
+			Expect(simBroker.Submit(context.Background(), broker.Order{
+				ID: "buy-1", Asset: aapl, Side: broker.Buy, Qty: 10,
+				OrderType: broker.Limit, LimitPrice: 95, TimeInForce: broker.GTC,
+			})).To(Succeed())
+
+			simBroker.SetPriceProvider(&mockDFPriceProvider{df: ohlcBar(nextDate, 100, 102, 97, 98)}, nextDate)
+			simBroker.EvaluatePending()
+			Expect(drainFills(simBroker, 1)).To(BeEmpty())
+
+			thirdDate := nextDate.AddDate(0, 0, 1)
+			simBroker.SetPriceProvider(&mockDFPriceProvider{df: ohlcBar(thirdDate, 97, 98, 93, 96)}, thirdDate)
+			simBroker.EvaluatePending()
+
+			fills := drainFills(simBroker, 1)
+			Expect(fills).To(HaveLen(1))
+			Expect(fills[0].Price).To(Equal(95.0))
+			Expect(fills[0].FilledAt).To(Equal(thirdDate))
+		})
+
+		It("fails a day limit the next bar does not touch", func() {
+			simBroker := engine.NewSimulatedBroker()
+			simBroker.SetFillPipeline(broker.NewPipeline(broker.OHLCPath(broker.OpenHighLowClose), nil))
+			simBroker.SetPriceProvider(&mockDFPriceProvider{df: ohlcBar(date, 100, 101, 99, 100)}, date)
+
+			Expect(simBroker.Submit(context.Background(), broker.Order{
+				ID: "buy-1", Asset: aapl, Side: broker.Buy, Qty: 10,
+				OrderType: broker.Limit, LimitPrice: 95,
+			})).To(Succeed())
+
+			simBroker.SetPriceProvider(&mockDFPriceProvider{df: ohlcBar(nextDate, 100, 102, 97, 98)}, nextDate)
+			simBroker.EvaluatePending()
+
+			fills := drainFills(simBroker, 1)
+			Expect(fills).To(HaveLen(1))
+			Expect(fills[0].Qty).To(BeZero())
+			Expect(fills[0].Err).To(MatchError(broker.ErrPriceNotReached))
+		})
+
+		It("cancels a working order", func() {
+			simBroker := engine.NewSimulatedBroker()
+			simBroker.SetFillPipeline(broker.NewPipeline(broker.NextOpen(), nil))
+			simBroker.SetPriceProvider(&mockDFPriceProvider{df: ohlcBar(date, 100, 100, 100, 100)}, date)
+
+			Expect(simBroker.Submit(context.Background(), broker.Order{
+				ID: "buy-1", Asset: aapl, Side: broker.Buy, Qty: 10, OrderType: broker.Market,
+			})).To(Succeed())
+			Expect(simBroker.Cancel(context.Background(), "buy-1")).To(Succeed())
+
+			simBroker.SetPriceProvider(&mockDFPriceProvider{df: ohlcBar(nextDate, 101, 101, 101, 101)}, nextDate)
+			simBroker.EvaluatePending()
+			Expect(drainFills(simBroker, 1)).To(BeEmpty())
+		})
+	})
+
+	Context("Trailing stops", func() {
+		bar := func(open, high, low, closePrice float64) broker.PriceProvider {
+			df, err := data.NewDataFrame([]time.Time{date}, []asset.Asset{aapl},
+				[]data.Metric{data.MetricOpen, data.MetricHigh, data.MetricLow, data.MetricClose},
+				data.Daily, [][]float64{{open}, {high}, {low}, {closePrice}})
+			Expect(err).NotTo(HaveOccurred())
+
+			return &mockDFPriceProvider{df: df}
+		}
+
+		stopOf := func(simBroker *engine.SimulatedBroker, orderID string) float64 {
+			orders, err := simBroker.Orders(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, order := range orders {
+				if order.ID == orderID {
+					return order.StopPrice
+				}
+			}
+
+			Fail("order " + orderID + " is not pending")
+
+			return 0
+		}
+
+		It("starts the trail from the close and ratchets it up with the high", func() {
+			simBroker := engine.NewSimulatedBroker()
+			simBroker.SetPriceProvider(bar(99, 101, 98, 100), date)
+
+			Expect(simBroker.Submit(context.Background(), broker.Order{
+				ID: "ts-1", Asset: aapl, Side: broker.Sell, Qty: 10,
+				OrderType: broker.TrailingStop, TrailAmount: 5,
+			})).To(Succeed())
+			Expect(drainFills(simBroker, 1)).To(BeEmpty())
+			Expect(stopOf(simBroker, "ts-1")).To(Equal(95.0))
+
+			simBroker.SetPriceProvider(bar(101, 110, 100, 108), date)
+			simBroker.EvaluatePending()
+			Expect(drainFills(simBroker, 1)).To(BeEmpty())
+			Expect(stopOf(simBroker, "ts-1")).To(Equal(105.0))
+
+			// A lower high never loosens the stop.
+			simBroker.SetPriceProvider(bar(108, 109, 106, 107), date)
+			simBroker.EvaluatePending()
+			Expect(stopOf(simBroker, "ts-1")).To(Equal(105.0))
+		})
+
+		It("trails a percentage of the high", func() {
+			simBroker := engine.NewSimulatedBroker()
+			simBroker.SetPriceProvider(bar(100, 200, 190, 200), date)
+
+			Expect(simBroker.Submit(context.Background(), broker.Order{
+				ID: "ts-1", Asset: aapl, Side: broker.Sell, Qty: 10,
+				OrderType: broker.TrailingStop, TrailPercent: 10,
+			})).To(Succeed())
+			Expect(stopOf(simBroker, "ts-1")).To(Equal(180.0))
+
+			simBroker.SetPriceProvider(bar(200, 250, 195, 240), date)
+			simBroker.EvaluatePending()
+			Expect(stopOf(simBroker, "ts-1")).To(Equal(225.0))
+		})
+
+		It("fills at the stop when the low reaches it", func() {
+			simBroker := engine.NewSimulatedBroker()
+			simBroker.SetPriceProvider(bar(101, 103, 100, 102), date)
+
+			Expect(simBroker.Submit(context.Background(), broker.Order{
+				ID: "ts-1", Asset: aapl, Side: broker.Sell, Qty: 10,
+				OrderType: broker.TrailingStop, TrailAmount: 5, StopPrice: 95,
+			})).To(Succeed())
+
+			// The bar's high of 106 would raise the stop to 101, but the
+			// trigger is checked against the stop carried in from before.
+			simBroker.SetPriceProvider(bar(100, 106, 94, 97), date)
+			simBroker.EvaluatePending()
+
+			fills := drainFills(simBroker, 2)
+			Expect(fills).To(HaveLen(1))
+			Expect(fills[0].OrderID).To(Equal("ts-1"))
+			Expect(fills[0].Price).To(Equal(95.0))
+			Expect(fills[0].Qty).To(Equal(10.0))
+
+			orders, err := simBroker.Orders(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orders).To(BeEmpty())
+		})
+
+		It("fills at the open when the bar gaps through the stop", func() {
+			simBroker := engine.NewSimulatedBroker()
+			simBroker.SetPriceProvider(bar(101, 103, 100, 102), date)
+
+			Expect(simBroker.Submit(context.Background(), broker.Order{
+				ID: "ts-1", Asset: aapl, Side: broker.Sell, Qty: 10,
+				OrderType: broker.TrailingStop, TrailAmount: 5, StopPrice: 95,
+			})).To(Succeed())
+
+			simBroker.SetPriceProvider(bar(90, 92, 88, 91), date)
+			simBroker.EvaluatePending()
+
+			fills := drainFills(simBroker, 2)
+			Expect(fills).To(HaveLen(1))
+			Expect(fills[0].Price).To(Equal(90.0))
+		})
+
+		It("trails a buy stop down with the low", func() {
+			simBroker := engine.NewSimulatedBroker()
+			simBroker.SetPriceProvider(bar(100, 101, 99, 100), date)
+
+			Expect(simBroker.Submit(context.Background(), broker.Order{
+				ID: "ts-1", Asset: aapl, Side: broker.Buy, Qty: 10,
+				OrderType: broker.TrailingStop, TrailAmount: 5,
+			})).To(Succeed())
+			Expect(stopOf(simBroker, "ts-1")).To(Equal(105.0))
+
+			simBroker.SetPriceProvider(bar(99, 100, 90, 92), date)
+			simBroker.EvaluatePending()
+			Expect(stopOf(simBroker, "ts-1")).To(Equal(95.0))
+
+			simBroker.SetPriceProvider(bar(93, 96, 92, 95), date)
+			simBroker.EvaluatePending()
+
+			fills := drainFills(simBroker, 2)
+			Expect(fills).To(HaveLen(1))
+			Expect(fills[0].Price).To(Equal(95.0))
+		})
+
+		It("leaves a gapped trailing stop-limit working until the price returns to the limit", func() {
+			simBroker := engine.NewSimulatedBroker()
+			simBroker.SetPriceProvider(bar(101, 103, 100, 102), date)
+
+			Expect(simBroker.Submit(context.Background(), broker.Order{
+				ID: "tsl-1", Asset: aapl, Side: broker.Sell, Qty: 10,
+				OrderType: broker.TrailingStopLimit, TrailAmount: 5, StopPrice: 95, LimitOffset: 1,
+			})).To(Succeed())
+
+			// Opens below the 94 limit and never trades back up to it.
+			simBroker.SetPriceProvider(bar(90, 93, 88, 92), date)
+			simBroker.EvaluatePending()
+			Expect(drainFills(simBroker, 1)).To(BeEmpty())
+			Expect(stopOf(simBroker, "tsl-1")).To(Equal(95.0))
+
+			simBroker.SetPriceProvider(bar(92, 96, 91, 95), date)
+			simBroker.EvaluatePending()
+
+			fills := drainFills(simBroker, 2)
+			Expect(fills).To(HaveLen(1))
+			Expect(fills[0].Price).To(Equal(94.0))
+		})
+
+		It("fills a gapped trailing stop-limit as a limit order when the price recovers past the stop", func() {
+			simBroker := engine.NewSimulatedBroker()
+			simBroker.SetPriceProvider(bar(101, 103, 100, 102), date)
+
+			Expect(simBroker.Submit(context.Background(), broker.Order{
+				ID: "tsl-1", Asset: aapl, Side: broker.Sell, Qty: 10,
+				OrderType: broker.TrailingStopLimit, TrailAmount: 5, StopPrice: 95, LimitOffset: 1,
+			})).To(Succeed())
+
+			simBroker.SetPriceProvider(bar(90, 93, 88, 92), date)
+			simBroker.EvaluatePending()
+			Expect(drainFills(simBroker, 1)).To(BeEmpty())
+
+			orders, err := simBroker.Orders(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orders).To(HaveLen(1))
+			Expect(orders[0].OrderType).To(Equal(broker.Limit))
+			Expect(orders[0].LimitPrice).To(Equal(94.0))
+
+			// The bar never reaches the old stop, so a trailing order
+			// would ratchet instead of filling.
+			simBroker.SetPriceProvider(bar(97, 102, 96, 101), date)
+			simBroker.EvaluatePending()
+
+			fills := drainFills(simBroker, 2)
+			Expect(fills).To(HaveLen(1))
+			Expect(fills[0].Price).To(Equal(97.0))
+		})
+
+		It("ratchets a trailing bracket stop loss", func() {
+			simBroker := engine.NewSimulatedBroker()
+			simBroker.SetPriceProvider(&mockHLPriceProvider{
+				high:  map[asset.Asset]float64{aapl: 160.0},
+				low:   map[asset.Asset]float64{aapl: 148.0},
+				close: map[asset.Asset]float64{aapl: 158.0},
+				date:  date,
+			}, date)
+
+			stopOrder := broker.Order{
+				ID: "sl-1", Asset: aapl, Side: broker.Sell, Qty: 100,
+				OrderType: broker.TrailingStop, TrailAmount: 10, StopPrice: 140.0,
+				GroupID: "grp-t", GroupRole: broker.RoleStopLoss,
+			}
+			tpOrder := broker.Order{
+				ID: "tp-1", Asset: aapl, Side: broker.Sell, Qty: 100,
+				OrderType: broker.Limit, LimitPrice: 175.0,
+				GroupID: "grp-t", GroupRole: broker.RoleTakeProfit,
+			}
+
+			Expect(simBroker.SubmitGroup(context.Background(), []broker.Order{stopOrder, tpOrder}, broker.GroupOCO)).To(Succeed())
+			simBroker.EvaluatePending()
+			Expect(drainFills(simBroker, 1)).To(BeEmpty())
+			Expect(stopOf(simBroker, "sl-1")).To(Equal(150.0))
+
+			simBroker.SetPriceProvider(&mockHLPriceProvider{
+				high:  map[asset.Asset]float64{aapl: 155.0},
+				low:   map[asset.Asset]float64{aapl: 149.0},
+				close: map[asset.Asset]float64{aapl: 151.0},
+				date:  date,
+			}, date)
+			simBroker.EvaluatePending()
+
+			fills := drainFills(simBroker, 2)
+			Expect(fills).To(HaveLen(1))
+			Expect(fills[0].OrderID).To(Equal("sl-1"))
+			Expect(fills[0].Price).To(Equal(150.0))
+		})
+	})
+
+	Context("Commission model", func() {
+		It("attaches the model's fees to each fill", func() {
+			simBroker := engine.NewSimulatedBroker()
+			simBroker.SetPriceProvider(&mockPriceProvider{
+				prices: map[asset.Asset]float64{aapl: 150.0},
+				date:   date,
+			}, date)
+			simBroker.SetCommissionModel(broker.Commissions(broker.FlatCommission(1.00), broker.RegulatoryFees()))
+
+			err := simBroker.Submit(context.Background(), broker.Order{
+				ID:        "sell-1",
+				Asset:     aapl,
+				Side:      broker.Sell,
+				Qty:       100,
+				OrderType: broker.Market,
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+
+			var ff broker.Fill
+			Eventually(simBroker.Fills()).Should(Receive(&ff))
+			Expect(ff.OrderID).To(Equal("sell-1"))
+			Expect(ff.Fees).To(HaveLen(3))
+			Expect(ff.Fees[0].Amount).To(Equal(1.00))
+		})
+
+		It("charges nothing when no model is configured", func() {
+			simBroker := engine.NewSimulatedBroker()
+			simBroker.SetPriceProvider(&mockPriceProvider{
+				prices: map[asset.Asset]float64{aapl: 150.0},
+				date:   date,
+			}, date)
+
+			err := simBroker.Submit(context.Background(), broker.Order{
+				Asset:     aapl,
+				Side:      broker.Buy,
+				Qty:       10,
+				OrderType: broker.Market,
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+
+			var ff broker.Fill
+			Eventually(simBroker.Fills()).Should(Receive(&ff))
+			Expect(ff.Fees).To(BeEmpty())
+		})
 	})
 
 	Context("Broker lifecycle", func() {
@@ -52,7 +52,7 @@ func (e *Engine) adjustEndForExpectedEOD(ctx context.Context, end, now time.Time
 	today := time.Date(nowLocal.Year(), nowLocal.Month(), nowLocal.Day(), 0, 0, 0, 0, nyc)
 	closeToday := time.Date(today.Year(), today.Month(), today.Day(), marketCloseHour, 0, 0, 0, nyc)
 
-	marketStatus := tradecron.NewMarketStatus(&tradecron.RegularHours)
+	marketStatus := tradecron.NewMarketStatus(&tradecron.RegularHours, tradecron.WithCalendar(e.marketCalendar()))
 
 	var expectedLast time.Time
 	if marketStatus.IsMarketDay(today) && !nowLocal.Before(closeToday) {
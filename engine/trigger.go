@@ -0,0 +1,300 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/portfolio"
+	"github.com/rs/zerolog"
+)
+
+// Trigger is a data-driven condition the engine checks on every bar in
+// addition to the strategy's schedule. Register triggers with
+// Engine.AddTrigger during Setup. A trigger fires when its condition
+// becomes true and must turn false again before it can fire a second time,
+// so a VIX spike that lasts a week fires once.
+type Trigger interface {
+	// Name identifies the trigger in annotations and logs.
+	Name() string
+
+	// Check reports whether the condition holds at the engine's current
+	// date, along with a description of the observed values.
+	Check(ctx context.Context, eng *Engine, port portfolio.Portfolio) (bool, string, error)
+}
+
+// TriggerHandler is an optional interface strategies may implement to
+// respond to triggers. When one or more triggers fire on a bar, the engine
+// calls OnTrigger with a fresh batch; strategies without it get an extra
+// Compute instead. Either way the batch carries a
+// portfolio.TriggerAnnotation entry per firing.
+type TriggerHandler interface {
+	OnTrigger(ctx context.Context, eng *Engine, port portfolio.Portfolio, batch *portfolio.Batch, fired []TriggerEvent) error
+}
+
+// TriggerEvent describes a trigger that fired.
+type TriggerEvent struct {
+	Name   string
+	Detail string
+	Time   time.Time
+}
+
+// registeredTrigger tracks whether a trigger's condition held on the
+// previous bar so it fires only on the transition.
+type registeredTrigger struct {
+	trigger Trigger
+	active  bool
+}
+
+// AddTrigger registers a trigger evaluated on every bar of the backtest or
+// live session. Call it from Strategy.Setup.
+func (e *Engine) AddTrigger(trigger Trigger) {
+	e.triggers = append(e.triggers, &registeredTrigger{trigger: trigger})
+}
+
+// firedTriggers checks every registered trigger and returns the ones whose
+// condition became true on this bar.
+func (e *Engine) firedTriggers(ctx context.Context, port portfolio.Portfolio, now time.Time) ([]TriggerEvent, error) {
+	var fired []TriggerEvent
+
+	for _, registered := range e.triggers {
+		holds, detail, err := registered.trigger.Check(ctx, e, port)
+		if err != nil {
+			return nil, fmt.Errorf("engine: trigger %q: %w", registered.trigger.Name(), err)
+		}
+
+		if holds && !registered.active {
+			fired = append(fired, TriggerEvent{Name: registered.trigger.Name(), Detail: detail, Time: now})
+
+			zerolog.Ctx(ctx).Info().
+				Str("trigger", registered.trigger.Name()).
+				Str("detail", detail).
+				Msg("trigger fired")
+		}
+
+		registered.active = holds
+	}
+
+	return fired, nil
+}
+
+// computeTriggered annotates batch with each fired trigger and runs the
+// strategy's OnTrigger, or Compute when it has no TriggerHandler.
+func (e *Engine) computeTriggered(ctx context.Context, port portfolio.Portfolio, batch *portfolio.Batch, fired []TriggerEvent) error {
+	for _, event := range fired {
+		batch.Annotate(portfolio.TriggerAnnotation+event.Name, event.Detail)
+	}
+
+	if handler, ok := e.strategy.(TriggerHandler); ok {
+		return handler.OnTrigger(ctx, e, port, batch, fired)
+	}
+
+	return e.strategy.Compute(ctx, e, port, batch)
+}
+
+// NewTrigger returns a Trigger named name that fires when check reports
+// true.
+func NewTrigger(name string, check func(ctx context.Context, eng *Engine, port portfolio.Portfolio) (bool, error)) Trigger {
+	return funcTrigger{name: name, check: check}
+}
+
+type funcTrigger struct {
+	name  string
+	check func(ctx context.Context, eng *Engine, port portfolio.Portfolio) (bool, error)
+}
+
+func (t funcTrigger) Name() string { return t.name }
+
+func (t funcTrigger) Check(ctx context.Context, eng *Engine, port portfolio.Portfolio) (bool, string, error) {
+	holds, err := t.check(ctx, eng, port)
+
+	return holds, t.name, err
+}
+
+// MetricAbove returns a Trigger that fires when ast's metric rises above
+// threshold, e.g. MetricAbove(vix, data.MetricClose, 30).
+func MetricAbove(ast asset.Asset, metric data.Metric, threshold float64) Trigger {
+	return metricThreshold{asset: ast, metric: metric, threshold: threshold, above: true}
+}
+
+// MetricBelow returns a Trigger that fires when ast's metric falls below
+// threshold.
+func MetricBelow(ast asset.Asset, metric data.Metric, threshold float64) Trigger {
+	return metricThreshold{asset: ast, metric: metric, threshold: threshold}
+}
+
+type metricThreshold struct {
+	asset     asset.Asset
+	metric    data.Metric
+	threshold float64
+	above     bool
+}
+
+func (t metricThreshold) comparison() string {
+	if t.above {
+		return ">"
+	}
+
+	return "<"
+}
+
+func (t metricThreshold) Name() string {
+	return fmt.Sprintf("%s %s %s %g", t.asset.Ticker, t.metric, t.comparison(), t.threshold)
+}
+
+func (t metricThreshold) Check(ctx context.Context, eng *Engine, _ portfolio.Portfolio) (bool, string, error) {
+	df, err := eng.FetchAt(ctx, []asset.Asset{t.asset}, eng.CurrentDate(), []data.Metric{t.metric})
+	if err != nil {
+		return false, "", err
+	}
+
+	value := df.Value(t.asset, t.metric)
+	if math.IsNaN(value) {
+		return false, "", nil
+	}
+
+	holds := value < t.threshold
+	if t.above {
+		holds = value > t.threshold
+	}
+
+	return holds, fmt.Sprintf("%s %s %.2f %s %g", t.asset.Ticker, t.metric, value, t.comparison(), t.threshold), nil
+}
+
+// DropFromHigh returns a Trigger that fires when ast closes at least pct
+// (e.g. 0.05 for 5%) below its highest close over the last days calendar
+// days, today included.
+func DropFromHigh(ast asset.Asset, days int, pct float64) Trigger {
+	return dropFromHigh{asset: ast, days: days, pct: pct}
+}
+
+type dropFromHigh struct {
+	asset asset.Asset
+	days  int
+	pct   float64
+}
+
+func (t dropFromHigh) Name() string {
+	return fmt.Sprintf("%s %.1f%% below %d-day high", t.asset.Ticker, t.pct*100, t.days)
+}
+
+func (t dropFromHigh) Check(ctx context.Context, eng *Engine, _ portfolio.Portfolio) (bool, string, error) {
+	df, err := eng.Fetch(ctx, []asset.Asset{t.asset}, portfolio.Days(t.days), []data.Metric{data.MetricClose})
+	if err != nil {
+		return false, "", err
+	}
+
+	closes := df.Column(t.asset, data.MetricClose)
+	if len(closes) == 0 {
+		return false, "", nil
+	}
+
+	current := closes[len(closes)-1]
+	high := math.Inf(-1)
+
+	for _, price := range closes {
+		if !math.IsNaN(price) && price > high {
+			high = price
+		}
+	}
+
+	if math.IsNaN(current) || math.IsInf(high, -1) || high <= 0 {
+		return false, "", nil
+	}
+
+	drop := 1 - current/high
+
+	return drop >= t.pct, fmt.Sprintf("%s close %.2f is %.1f%% below %d-day high %.2f",
+		t.asset.Ticker, current, drop*100, t.days, high), nil
+}
+
+// DrawdownExceeds returns a Trigger that fires when the portfolio's value
+// falls more than pct (e.g. 0.10 for 10%) below its peak.
+func DrawdownExceeds(pct float64) Trigger {
+	return drawdownExceeds{pct: pct}
+}
+
+type drawdownExceeds struct {
+	pct float64
+}
+
+func (t drawdownExceeds) Name() string {
+	return fmt.Sprintf("drawdown > %.1f%%", t.pct*100)
+}
+
+func (t drawdownExceeds) Check(_ context.Context, _ *Engine, port portfolio.Portfolio) (bool, string, error) {
+	current := port.Value()
+	peak := current
+
+	if perf := port.PerfData(); perf != nil && len(perf.AssetList()) > 0 {
+		for _, value := range perf.Column(perf.AssetList()[0], data.PortfolioEquity) {
+			if value > peak {
+				peak = value
+			}
+		}
+	}
+
+	if peak <= 0 {
+		return false, "", nil
+	}
+
+	drawdown := 1 - current/peak
+
+	return drawdown > t.pct, fmt.Sprintf("portfolio %.1f%% below peak %.2f", drawdown*100, peak), nil
+}
+
+// runLiveTriggers checks the registered triggers during a live firing and
+// executes the strategy's response. Failures are logged so one bad bar
+// does not end the session.
+func (e *Engine) runLiveTriggers(ctx context.Context, acct portfolio.PortfolioManager) {
+	fired, err := e.firedTriggers(ctx, acct, e.currentDate)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("trigger check failed")
+		return
+	}
+
+	if len(fired) == 0 {
+		return
+	}
+
+	if sb, ok := e.broker.(*SimulatedBroker); ok {
+		sb.SetPriceProvider(e.livePriceProvider(), e.currentDate)
+	}
+
+	if err := acct.CancelOpenOrders(ctx); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("cancel open orders failed")
+		return
+	}
+
+	batch := acct.NewBatch(e.currentDate)
+	if err := e.computeTriggered(ctx, acct, batch, fired); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("strategy trigger failed")
+		return
+	}
+
+	if err := acct.ExecuteBatch(ctx, batch); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("execute trigger batch failed")
+		return
+	}
+
+	e.dispatchLiveEvents(ctx, acct)
+	e.syncLiveStream(ctx, acct)
+}
@@ -0,0 +1,248 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/engine"
+	"github.com/penny-vault/pvbt/portfolio"
+)
+
+// triggerStrategy rebalances at month end and registers triggers in
+// Setup. It records the dates of every Compute call.
+type triggerStrategy struct {
+	triggers     []engine.Trigger
+	computeDates []time.Time
+}
+
+func (s *triggerStrategy) Name() string { return "trigger" }
+
+func (s *triggerStrategy) Setup(eng *engine.Engine) {
+	for _, trigger := range s.triggers {
+		eng.AddTrigger(trigger)
+	}
+}
+
+func (s *triggerStrategy) Describe() engine.StrategyDescription {
+	return engine.StrategyDescription{Schedule: "@monthend"}
+}
+
+func (s *triggerStrategy) Compute(_ context.Context, eng *engine.Engine, _ portfolio.Portfolio, _ *portfolio.Batch) error {
+	s.computeDates = append(s.computeDates, eng.CurrentDate())
+
+	return nil
+}
+
+// triggerHandlerStrategy responds to triggers by moving to cash.
+type triggerHandlerStrategy struct {
+	triggerStrategy
+	target asset.Asset
+	fired  [][]engine.TriggerEvent
+}
+
+func (s *triggerHandlerStrategy) Compute(ctx context.Context, eng *engine.Engine, _ portfolio.Portfolio, batch *portfolio.Batch) error {
+	s.computeDates = append(s.computeDates, eng.CurrentDate())
+
+	return batch.RebalanceTo(ctx, portfolio.Allocation{
+		Date:          eng.CurrentDate(),
+		Members:       map[asset.Asset]float64{s.target: 1.0},
+		Justification: "month end",
+	})
+}
+
+func (s *triggerHandlerStrategy) OnTrigger(ctx context.Context, _ *engine.Engine, _ portfolio.Portfolio, batch *portfolio.Batch, fired []engine.TriggerEvent) error {
+	s.fired = append(s.fired, fired)
+
+	return batch.RebalanceTo(ctx, portfolio.Allocation{
+		Date:          batch.Timestamp,
+		Members:       map[asset.Asset]float64{},
+		Justification: "trigger",
+	})
+}
+
+var _ = Describe("Triggers", func() {
+	var (
+		testStock     asset.Asset
+		assetProvider *mockAssetProvider
+		allMetrics    []data.Metric
+		btStart       time.Time
+		btEnd         time.Time
+	)
+
+	BeforeEach(func() {
+		testStock = asset.Asset{CompositeFigi: "FIGI-TRIG", Ticker: "TRIG"}
+		assetProvider = &mockAssetProvider{assets: []asset.Asset{testStock}}
+		allMetrics = []data.Metric{data.MetricClose, data.AdjClose, data.Dividend, data.MetricHigh, data.MetricLow, data.SplitFactor, data.Volume}
+		btStart = time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		btEnd = time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	})
+
+	// makeTriggerData returns daily bars from December 2023 through
+	// February 2024 closing at 100 except on the given days.
+	makeTriggerData := func(closes map[time.Time]float64) *data.DataFrame {
+		dataStart := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+		nDays := 91
+
+		times := make([]time.Time, nDays)
+		vals := make([]float64, nDays*len(allMetrics))
+
+		for dayIdx := range times {
+			day := dataStart.AddDate(0, 0, dayIdx)
+			times[dayIdx] = time.Date(day.Year(), day.Month(), day.Day(), 16, 0, 0, 0, time.UTC)
+
+			price := 100.0
+			if override, ok := closes[day]; ok {
+				price = override
+			}
+
+			vals[0*nDays+dayIdx] = price
+			vals[1*nDays+dayIdx] = price
+			vals[2*nDays+dayIdx] = 0.0
+			vals[3*nDays+dayIdx] = price + 1.0
+			vals[4*nDays+dayIdx] = price - 1.0
+			vals[5*nDays+dayIdx] = 1.0
+			vals[6*nDays+dayIdx] = 1_000_000.0
+		}
+
+		testDF, err := data.NewDataFrame(times, []asset.Asset{testStock}, allMetrics, data.Daily, data.SlabToColumns(vals, len(allMetrics), nDays))
+		Expect(err).NotTo(HaveOccurred())
+
+		return testDF
+	}
+
+	day := func(month time.Month, dayOfMonth int) time.Time {
+		return time.Date(2024, month, dayOfMonth, 0, 0, 0, 0, time.UTC)
+	}
+
+	dates := func(times []time.Time) []string {
+		formatted := make([]string, len(times))
+		for idx, tt := range times {
+			formatted[idx] = tt.Format("2006-01-02")
+		}
+
+		return formatted
+	}
+
+	It("runs an extra Compute once each time the condition becomes true", func() {
+		provider := data.NewTestProvider(allMetrics, makeTriggerData(map[time.Time]float64{
+			day(time.January, 10): 120,
+			day(time.January, 11): 121,
+			day(time.January, 12): 122,
+			day(time.January, 17): 125,
+		}))
+
+		strategy := &triggerStrategy{
+			triggers: []engine.Trigger{engine.MetricAbove(testStock, data.MetricClose, 110)},
+		}
+
+		acct := portfolio.New(portfolio.WithCash(10_000, time.Time{}))
+		eng := engine.New(strategy,
+			engine.WithDataProvider(provider),
+			engine.WithAssetProvider(assetProvider),
+			engine.WithAccount(acct),
+		)
+
+		_, err := eng.Backtest(context.Background(), btStart, btEnd)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(dates(strategy.computeDates)).To(Equal([]string{
+			"2024-01-10", "2024-01-17", "2024-01-31", "2024-02-29",
+		}))
+
+		var triggered []string
+
+		for _, annotation := range acct.Annotations() {
+			if strings.HasPrefix(annotation.Key, portfolio.TriggerAnnotation) {
+				triggered = append(triggered, annotation.Timestamp.Format("2006-01-02")+" "+annotation.Key)
+			}
+		}
+
+		Expect(triggered).To(Equal([]string{
+			"2024-01-10 trigger.TRIG Close > 110",
+			"2024-01-17 trigger.TRIG Close > 110",
+		}))
+	})
+
+	It("calls OnTrigger and executes its orders when the strategy implements TriggerHandler", func() {
+		provider := data.NewTestProvider(allMetrics, makeTriggerData(map[time.Time]float64{
+			day(time.February, 6): 90,
+			day(time.February, 7): 80,
+		}))
+
+		strategy := &triggerHandlerStrategy{target: testStock}
+		strategy.triggers = []engine.Trigger{engine.DropFromHigh(testStock, 20, 0.15)}
+
+		acct := portfolio.New(portfolio.WithCash(10_000, time.Time{}))
+		eng := engine.New(strategy,
+			engine.WithDataProvider(provider),
+			engine.WithAssetProvider(assetProvider),
+			engine.WithAccount(acct),
+		)
+
+		fund, err := eng.Backtest(context.Background(), btStart, btEnd)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(dates(strategy.computeDates)).To(Equal([]string{"2024-01-31", "2024-02-29"}))
+		Expect(strategy.fired).To(HaveLen(1))
+		Expect(strategy.fired[0]).To(HaveLen(1))
+		Expect(strategy.fired[0][0].Name).To(Equal("TRIG 15.0% below 20-day high"))
+		Expect(dates([]time.Time{strategy.fired[0][0].Time})).To(Equal([]string{"2024-02-07"}))
+
+		var sells []string
+
+		for _, txn := range fund.Transactions() {
+			if txn.Type == asset.SellTransaction {
+				sells = append(sells, txn.Date.Format("2006-01-02")+" "+txn.Justification)
+			}
+		}
+
+		Expect(sells).To(Equal([]string{"2024-02-07 trigger"}))
+	})
+
+	It("fires custom triggers built with NewTrigger", func() {
+		provider := data.NewTestProvider(allMetrics, makeTriggerData(nil))
+
+		strategy := &triggerStrategy{
+			triggers: []engine.Trigger{
+				engine.NewTrigger("mid-month", func(_ context.Context, eng *engine.Engine, _ portfolio.Portfolio) (bool, error) {
+					return eng.CurrentDate().Day() >= 15, nil
+				}),
+			},
+		}
+
+		acct := portfolio.New(portfolio.WithCash(10_000, time.Time{}))
+		eng := engine.New(strategy,
+			engine.WithDataProvider(provider),
+			engine.WithAssetProvider(assetProvider),
+			engine.WithAccount(acct),
+		)
+
+		_, err := eng.Backtest(context.Background(), btStart, btEnd)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(dates(strategy.computeDates)).To(Equal([]string{
+			"2024-01-15", "2024-01-31", "2024-02-15", "2024-02-29",
+		}))
+	})
+})
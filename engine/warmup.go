@@ -31,8 +31,9 @@ import (
 
 // walkBackTradingDays finds the trading date that is `days` trading days
 // before `from`. It uses a forward-walk approach since TradeCron only
-// supports Next(). Returns an error if days is negative.
-func walkBackTradingDays(from time.Time, days int) (time.Time, error) {
+// supports Next(). Trading days follow the NYSE calendar unless opts
+// select another. Returns an error if days is negative.
+func walkBackTradingDays(from time.Time, days int, opts ...tradecron.Option) (time.Time, error) {
 	if days < 0 {
 		return time.Time{}, fmt.Errorf("walkBackTradingDays: days must be non-negative, got %d", days)
 	}
@@ -41,7 +42,7 @@ func walkBackTradingDays(from time.Time, days int) (time.Time, error) {
 		return from, nil
 	}
 
-	daily, err := tradecron.New("@close * * *", tradecron.RegularHours)
+	daily, err := tradecron.New("@close * * *", tradecron.RegularHours, opts...)
 	if err != nil {
 		return time.Time{}, fmt.Errorf("walkBackTradingDays: creating daily schedule: %w", err)
 	}
@@ -130,7 +131,7 @@ func (e *Engine) validateWarmup(ctx context.Context, start, end time.Time) (time
 	}
 
 	// Check warmup data availability.
-	warmupStart, err := walkBackTradingDays(firstTradeDate, e.warmup)
+	warmupStart, err := walkBackTradingDays(firstTradeDate, e.warmup, tradecron.WithCalendar(e.marketCalendar()))
 	if err != nil {
 		return time.Time{}, fmt.Errorf("engine: computing warmup start: %w", err)
 	}
@@ -150,14 +151,14 @@ func (e *Engine) validateWarmup(ctx context.Context, start, end time.Time) (time
 	}
 
 	// Permissive mode: scan forward to find a valid start date.
-	daily, dailyErr := tradecron.New("@close * * *", tradecron.RegularHours)
+	daily, dailyErr := e.newSchedule("@close * * *")
 	if dailyErr != nil {
 		return time.Time{}, fmt.Errorf("engine: creating daily schedule for permissive scan: %w", dailyErr)
 	}
 
 	candidate := daily.Next(firstTradeDate.Add(time.Nanosecond))
 	for !candidate.After(end) {
-		candidateWarmupStart, walkErr := walkBackTradingDays(candidate, e.warmup)
+		candidateWarmupStart, walkErr := walkBackTradingDays(candidate, e.warmup, tradecron.WithCalendar(e.marketCalendar()))
 		if walkErr != nil {
 			return time.Time{}, fmt.Errorf("engine: computing warmup start for candidate %s: %w",
 				candidate.Format("2006-01-02"), walkErr)
@@ -19,6 +19,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"math"
 	"slices"
 	"time"
@@ -78,8 +79,10 @@ type Account struct {
 	metrics                  []MetricRow
 	registeredMetrics        []PerformanceMetric
 	annotations              []Annotation
+	skippedTrades            []SkippedTrade
 	middleware               []Middleware
 	pendingOrders            map[string]broker.Order
+	orderFills               map[string]orderFill          // orderID -> filled so far
 	pendingGroups            map[string]*broker.OrderGroup // groupID -> group
 	brokerHasGroups          bool                          // cached GroupSubmitter check
 	deferredExits            map[string]OrderGroupSpec     // groupID -> bracket spec
@@ -103,6 +106,29 @@ type Account struct {
 	// next scheduled trade date. Set by SetPrediction at the end of a
 	// backtest; nil when no prediction has been recorded.
 	prediction *Prediction
+	// dividendReinvestment is the account's DRIP plan; nil when dividends
+	// are kept as cash.
+	dividendReinvestment *DividendReinvestment
+	// fxRates holds the base-currency value of one unit of each foreign
+	// currency, set by SetFXRates.
+	fxRates map[asset.Currency]float64
+	// foreignCash holds cash balances in currencies other than the base
+	// currency; cash holds the base-currency balance.
+	foreignCash map[asset.Currency]float64
+	// holdForeignCash keeps foreign-currency cash flows in their currency
+	// instead of converting them to the base currency.
+	holdForeignCash bool
+	// currencyHedge is the account's currency hedge configuration; nil
+	// when foreign-currency exposure is unhedged.
+	currencyHedge *CurrencyHedge
+	// fxForwards holds the open currency hedge contracts by currency.
+	fxForwards map[asset.Currency]fxForward
+	// futuresMarks holds the price each futures position was last
+	// settled at, in the contract's quote currency.
+	futuresMarks map[asset.Asset]float64
+	// observer is notified of fills and corporate actions; nil when no
+	// one is listening.
+	observer AccountObserver
 }
 
 // New creates an Account with the given options.
@@ -115,6 +141,7 @@ func New(opts ...Option) *Account {
 		recentBuys:       make(map[asset.Asset][]recentBuy),
 		metadata:         make(map[string]string),
 		pendingOrders:    make(map[string]broker.Order),
+		orderFills:       make(map[string]orderFill),
 		pendingGroups:    make(map[string]*broker.OrderGroup),
 		deferredExits:    make(map[string]OrderGroupSpec),
 		substitutions:    make(map[asset.Asset]Substitution),
@@ -311,6 +338,7 @@ func (a *Account) Order(ctx context.Context, ast asset.Asset, side Side, qty flo
 	var (
 		hasLimit, hasStop bool
 		justification     string
+		trailing          *trailingStopModifier
 	)
 
 	for _, mod := range mods {
@@ -321,6 +349,9 @@ func (a *Account) Order(ctx context.Context, ast asset.Asset, side Side, qty flo
 		case stopModifier:
 			order.StopPrice = modifier.price
 			hasStop = true
+		case trailingStopModifier:
+			captured := modifier
+			trailing = &captured
 		case dayOrderModifier:
 			order.TimeInForce = broker.Day
 		case goodTilCancelModifier:
@@ -356,6 +387,10 @@ func (a *Account) Order(ctx context.Context, ast asset.Asset, side Side, qty flo
 		order.OrderType = broker.Stop
 	}
 
+	if trailing != nil {
+		trailing.apply(&order)
+	}
+
 	return a.submitAndRecord(ctx, ast, order, justification)
 }
 
@@ -372,6 +407,8 @@ func (a *Account) submitAndRecord(ctx context.Context, ast asset.Asset, order br
 		order.ID = fmt.Sprintf("order-%s-%d", ast.CompositeFigi, len(a.transactions))
 	}
 
+	order = a.localizeOrder(order)
+
 	order.Justification = justification
 	a.pendingOrders[order.ID] = order
 
@@ -386,20 +423,24 @@ func (a *Account) submitAndRecord(ctx context.Context, ast asset.Asset, order br
 	return nil
 }
 
-// Cash returns the current cash balance.
+// Cash returns the current cash balance in the base currency, including
+// cash held in foreign currencies converted at the current exchange rates.
 func (a *Account) Cash() float64 {
-	return a.cash
+	return a.cash + a.foreignCashValue()
 }
 
-// Value returns the total portfolio value: cash plus all holdings marked
-// to current prices. If no prices have been set yet, returns cash only.
+// Value returns the total portfolio value in the base currency: cash plus
+// all holdings marked to current prices and exchange rates, plus the
+// unrealized profit and loss of any currency hedge. Futures positions
+// contribute their gain or loss since the last settlement rather than
+// their notional. If no prices have been set yet, returns cash only.
 func (a *Account) Value() float64 {
-	total := a.cash
+	total := a.Cash() + a.hedgeValue()
 	if a.prices != nil {
 		for ast, qty := range a.holdings {
-			v := a.prices.Value(ast, data.MetricClose)
+			v := a.marketValue(ast, qty)
 			if !math.IsNaN(v) {
-				total += qty * v
+				total += v
 			}
 		}
 	}
@@ -413,19 +454,21 @@ func (a *Account) Position(ast asset.Asset) float64 {
 }
 
 // PositionValue returns the current market value of the position in a
-// specific asset (quantity * current price), or 0 if no prices or no position.
+// specific asset (quantity * current price) in the base currency, or 0 if
+// no prices or no position. For a futures position it is the gain or loss
+// since the last settlement.
 func (a *Account) PositionValue(ast asset.Asset) float64 {
 	qty := a.holdings[ast]
 	if qty == 0 || a.prices == nil {
 		return 0
 	}
 
-	v := a.prices.Value(ast, data.MetricClose)
+	v := a.marketValue(ast, qty)
 	if math.IsNaN(v) {
 		return 0
 	}
 
-	return qty * v
+	return v
 }
 
 // Holdings returns a map of all current positions keyed by asset with the
@@ -542,7 +585,7 @@ func (a *Account) TaxMetrics() (TaxMetrics, error) {
 }
 
 func (a *Account) TradeMetrics() (TradeMetrics, error) {
-	return tradeMetricsFrom(a)
+	return tradeMetricsFrom(a, a.skippedTrades, time.Time{}, time.Time{})
 }
 
 func (a *Account) WithdrawalMetrics() (WithdrawalMetrics, error) {
@@ -674,7 +717,9 @@ func taxMetricsFrom(src metricQuerier) (TaxMetrics, error) {
 	return taxMetrics, errors.Join(errs...)
 }
 
-func tradeMetricsFrom(src metricQuerier) (TradeMetrics, error) {
+// tradeMetricsFrom aggregates the trade metrics of src. Rebalance trades
+// skipped within [start, end] are counted from skipped.
+func tradeMetricsFrom(src metricQuerier, skipped []SkippedTrade, start, end time.Time) (TradeMetrics, error) {
 	var errs []error
 
 	tradeMetrics := TradeMetrics{}
@@ -771,6 +816,8 @@ func tradeMetricsFrom(src metricQuerier) (TradeMetrics, error) {
 		errs = append(errs, err)
 	}
 
+	tradeMetrics.SkippedTrades, tradeMetrics.SkippedNotional = skippedRebalanceTrades(skipped, start, end)
+
 	return tradeMetrics, errors.Join(errs...)
 }
 
@@ -843,6 +890,27 @@ func (a *Account) Record(txn Transaction) {
 		txn.Qualified = a.isDividendQualified(txn.Asset, txn.Date)
 	}
 
+	if txn.Currency == "" && txn.Type != asset.FXTransaction {
+		txn.Currency = currencyCode(txn.Asset.QuoteCurrency())
+	}
+
+	// Stamp the exchange rate so tax lots and realized gains can be
+	// measured in the base currency.
+	if txn.Currency != "" && txn.FXRate == 0 {
+		if rate := a.FXRate(txn.Currency); !math.IsNaN(rate) {
+			txn.FXRate = rate
+		}
+	}
+
+	// Futures trades move no cash: the open position is settled to the
+	// trade price, and the traded contracts start from that price.
+	isFuturesTrade := txn.Asset.IsFuture() &&
+		(txn.Type == asset.BuyTransaction || txn.Type == asset.SellTransaction)
+	if isFuturesTrade {
+		a.settleFuture(txn.Date, txn.Asset, txn.Price)
+		txn.Amount = 0
+	}
+
 	// Prune expired wash sale tracking entries.
 	a.pruneWashSaleTracking(txn.Date)
 
@@ -852,7 +920,7 @@ func (a *Account) Record(txn Transaction) {
 		a.seenTransactions[txn.ID] = struct{}{}
 	}
 
-	a.cash += txn.Amount
+	a.credit(txn.Currency, txn.Amount)
 
 	switch txn.Type {
 	case asset.BuyTransaction:
@@ -901,7 +969,7 @@ func (a *Account) Record(txn Transaction) {
 						EntryPrice: tdLots[tdLotIdx].Price,
 						ExitPrice:  txn.Price,
 						Qty:        matched,
-						PnL:        (tdLots[tdLotIdx].Price - txn.Price) * matched,
+						PnL:        (tdLots[tdLotIdx].BasePrice() - txn.Price*txn.baseRate()) * matched * txn.Asset.ContractMultiplier(),
 						HoldDays:   txn.Date.Sub(tdLots[tdLotIdx].Date).Hours() / 24.0,
 						MFE:        mfe,
 						MAE:        mae,
@@ -919,7 +987,7 @@ func (a *Account) Record(txn Transaction) {
 			a.consumeShortLots(txn.Asset, coverQty, method)
 
 			// Wash sale check: covering a short at a loss.
-			lossPerShare := txn.Price - avgShortEntry
+			lossPerShare := txn.Price*txn.baseRate() - avgShortEntry
 			if lossPerShare > 0 {
 				a.recentLossSales[txn.Asset] = append(a.recentLossSales[txn.Asset], recentLossSale{
 					date:         txn.Date,
@@ -940,10 +1008,11 @@ func (a *Account) Record(txn Transaction) {
 		if longQty > 0 {
 			lotID := fmt.Sprintf("lot-%d-%d", txn.Date.UnixNano(), len(a.taxLots[txn.Asset]))
 			newLot := TaxLot{
-				ID:    lotID,
-				Date:  txn.Date,
-				Qty:   longQty,
-				Price: txn.Price,
+				ID:     lotID,
+				Date:   txn.Date,
+				Qty:    longQty,
+				Price:  txn.Price,
+				FXRate: txn.FXRate,
 			}
 
 			a.taxLots[txn.Asset] = append(a.taxLots[txn.Asset], newLot)
@@ -1020,7 +1089,7 @@ func (a *Account) Record(txn Transaction) {
 						EntryPrice: tdLots[tdLotIdx].Price,
 						ExitPrice:  txn.Price,
 						Qty:        matched,
-						PnL:        (txn.Price - tdLots[tdLotIdx].Price) * matched,
+						PnL:        (txn.Price*txn.baseRate() - tdLots[tdLotIdx].BasePrice()) * matched * txn.Asset.ContractMultiplier(),
 						HoldDays:   txn.Date.Sub(tdLots[tdLotIdx].Date).Hours() / 24.0,
 						MFE:        mfe,
 						MAE:        mae,
@@ -1035,7 +1104,7 @@ func (a *Account) Record(txn Transaction) {
 			a.consumeLots(txn.Asset, closeLongQty, method)
 
 			// Check for wash sale on the long close.
-			lossPerShare := consumed.avgCostBasis - txn.Price
+			lossPerShare := consumed.avgCostBasis - txn.Price*txn.baseRate()
 			if lossPerShare > 0 {
 				disallowedQty := a.checkWashSaleOnSell(txn.Asset, txn.Date, closeLongQty, lossPerShare, consumed.latestBuyDate)
 
@@ -1061,10 +1130,11 @@ func (a *Account) Record(txn Transaction) {
 		if shortQty > 0 {
 			lotID := fmt.Sprintf("short-%d-%d", txn.Date.UnixNano(), len(a.shortLots[txn.Asset]))
 			a.shortLots[txn.Asset] = append(a.shortLots[txn.Asset], TaxLot{
-				ID:    lotID,
-				Date:  txn.Date,
-				Qty:   shortQty,
-				Price: txn.Price,
+				ID:     lotID,
+				Date:   txn.Date,
+				Qty:    shortQty,
+				Price:  txn.Price,
+				FXRate: txn.FXRate,
 			})
 
 			// Initialize excursion tracking for the short position.
@@ -1081,6 +1151,58 @@ func (a *Account) Record(txn Transaction) {
 		}
 
 		// Cleanup: remove tracking when fully flat.
+		if a.holdings[txn.Asset] == 0 {
+			delete(a.holdings, txn.Asset)
+			delete(a.taxLots, txn.Asset)
+			delete(a.shortLots, txn.Asset)
+			delete(a.excursions, txn.Asset)
+		}
+	case asset.JournalTransaction:
+		// A journal that names an asset moves shares in or out of the
+		// account without a trade, e.g. a broker reconciliation
+		// adjustment. Qty is signed. Like a buy, a positive journal
+		// first covers short lots and adds a long lot at Price for the
+		// rest; like a sell, a negative journal first removes shares
+		// from the oldest long lots and opens a short lot for the rest.
+		// No trade details are generated.
+		if txn.Asset == (asset.Asset{}) || txn.Qty == 0 {
+			break
+		}
+
+		a.holdings[txn.Asset] += txn.Qty
+
+		if txn.Qty > 0 {
+			coverQty := math.Min(txn.Qty, lotQty(a.shortLots[txn.Asset]))
+			if coverQty > 0 {
+				a.consumeShortLots(txn.Asset, coverQty, LotFIFO)
+			}
+
+			if longQty := txn.Qty - coverQty; longQty > 0 {
+				a.taxLots[txn.Asset] = append(a.taxLots[txn.Asset], TaxLot{
+					ID:     fmt.Sprintf("journal-%d-%d", txn.Date.UnixNano(), len(a.taxLots[txn.Asset])),
+					Date:   txn.Date,
+					Qty:    longQty,
+					Price:  txn.Price,
+					FXRate: txn.FXRate,
+				})
+			}
+		} else {
+			closeLongQty := math.Min(-txn.Qty, lotQty(a.taxLots[txn.Asset]))
+			if closeLongQty > 0 {
+				a.consumeLots(txn.Asset, closeLongQty, LotFIFO)
+			}
+
+			if shortQty := -txn.Qty - closeLongQty; shortQty > 0 {
+				a.shortLots[txn.Asset] = append(a.shortLots[txn.Asset], TaxLot{
+					ID:     fmt.Sprintf("journal-short-%d-%d", txn.Date.UnixNano(), len(a.shortLots[txn.Asset])),
+					Date:   txn.Date,
+					Qty:    shortQty,
+					Price:  txn.Price,
+					FXRate: txn.FXRate,
+				})
+			}
+		}
+
 		if a.holdings[txn.Asset] == 0 {
 			delete(a.holdings, txn.Asset)
 			delete(a.taxLots, txn.Asset)
@@ -1088,10 +1210,18 @@ func (a *Account) Record(txn Transaction) {
 			delete(a.excursions, txn.Asset)
 		}
 	}
+
+	if isFuturesTrade && a.holdings[txn.Asset] == 0 {
+		delete(a.futuresMarks, txn.Asset)
+	}
+
+	a.convertForeignCashFlow(txn)
 }
 
 // SyncTransactions applies broker-reported transactions to the account,
-// skipping any that have already been recorded (by ID).
+// skipping any that have already been recorded (by ID). Dividends covered
+// by the account's DividendReinvestment plan are reinvested as they are
+// recorded.
 func (a *Account) SyncTransactions(txns []broker.Transaction) error {
 	for _, bt := range txns {
 		if _, seen := a.seenTransactions[bt.ID]; seen {
@@ -1106,11 +1236,12 @@ func (a *Account) SyncTransactions(txns []broker.Transaction) error {
 			}
 
 			a.seenTransactions[bt.ID] = struct{}{}
+			a.notifyCorporateAction(bt)
 
 			continue
 		}
 
-		a.Record(Transaction{
+		txn := Transaction{
 			ID:            bt.ID,
 			Date:          bt.Date,
 			Asset:         bt.Asset,
@@ -1119,7 +1250,15 @@ func (a *Account) SyncTransactions(txns []broker.Transaction) error {
 			Price:         bt.Price,
 			Amount:        bt.Amount,
 			Justification: bt.Justification,
-		})
+		}
+
+		a.Record(txn)
+
+		a.notifyCorporateAction(bt)
+
+		if txn.Type == asset.DividendTransaction {
+			a.reinvestDividend(txn)
+		}
 	}
 
 	return nil
@@ -1417,13 +1556,18 @@ func (a *Account) lotExists(ast asset.Asset, lotID string) bool {
 	return false
 }
 
-// adjustLotBasis adds the given per-share adjustment to the cost basis
-// of the specified lot.
+// adjustLotBasis adds the given per-share adjustment, in the base
+// currency, to the cost basis of the specified lot.
 func (a *Account) adjustLotBasis(ast asset.Asset, lotID string, perShareAdjustment float64) {
 	lots := a.taxLots[ast]
 	for idx := range lots {
 		if lots[idx].ID == lotID {
+			if lots[idx].FXRate != 0 {
+				perShareAdjustment /= lots[idx].FXRate
+			}
+
 			lots[idx].Price += perShareAdjustment
+
 			return
 		}
 	}
@@ -1451,10 +1595,11 @@ func (a *Account) splitLot(ast asset.Asset, lotID string, headQty float64) (head
 		// Create a new lot for the remaining shares with a derived ID.
 		tailID = fmt.Sprintf("%s-split", original.ID)
 		tail := TaxLot{
-			ID:    tailID,
-			Date:  original.Date,
-			Qty:   tailQty,
-			Price: original.Price,
+			ID:     tailID,
+			Date:   original.Date,
+			Qty:    tailQty,
+			Price:  original.Price,
+			FXRate: original.FXRate,
 		}
 
 		// Insert the tail immediately after the head so the slice keeps
@@ -1474,8 +1619,8 @@ type consumedLotInfo struct {
 	latestBuyDate time.Time
 }
 
-// computeConsumedLotInfo computes the weighted average cost basis and the
-// date range of lots that would be consumed by a sell of the given quantity
+// computeConsumedLotInfo computes the weighted average cost basis, in the
+// base currency, and the date range of lots that would be consumed by a sell of the given quantity
 // using the specified lot selection method. This does NOT modify the lots.
 func (a *Account) computeConsumedLotInfo(ast asset.Asset, qty float64, method LotSelection) consumedLotInfo {
 	lots := a.taxLots[ast]
@@ -1492,7 +1637,7 @@ func (a *Account) computeConsumedLotInfo(ast asset.Asset, qty float64, method Lo
 	var latest time.Time
 
 	accumulate := func(lot TaxLot, consumed float64) {
-		totalCost += consumed * lot.Price
+		totalCost += consumed * lot.BasePrice()
 		totalQty += consumed
 
 		if latest.IsZero() || lot.Date.After(latest) {
@@ -1702,8 +1847,9 @@ func (a *Account) consumeShortLots(ast asset.Asset, qty float64, method LotSelec
 	}
 }
 
-// avgShortEntryPrice computes the weighted average entry price of short
-// lots that would be consumed for the given qty and method.
+// avgShortEntryPrice computes the weighted average entry price, in the
+// base currency, of short lots that would be consumed for the given qty
+// and method.
 func (a *Account) avgShortEntryPrice(ast asset.Asset, qty float64, method LotSelection) float64 {
 	lots := make([]TaxLot, len(a.shortLots[ast]))
 	copy(lots, a.shortLots[ast])
@@ -1720,7 +1866,7 @@ func (a *Account) avgShortEntryPrice(ast asset.Asset, qty float64, method LotSel
 				matched = remaining
 			}
 
-			totalCost += matched * lots[idx].Price
+			totalCost += matched * lots[idx].BasePrice()
 			totalQty += matched
 			remaining -= matched
 		}
@@ -1747,7 +1893,7 @@ func (a *Account) avgShortEntryPrice(ast asset.Asset, qty float64, method LotSel
 				matched = remaining
 			}
 
-			totalCost += matched * lots[idx].Price
+			totalCost += matched * lots[idx].BasePrice()
 			totalQty += matched
 			remaining -= matched
 		}
@@ -1774,9 +1920,9 @@ func (a *Account) UpdatePrices(priceData *data.DataFrame) {
 	// We compute it first so we can both stamp perfData and fill the side-car.
 	stepMV := make(map[asset.Asset]float64, len(a.holdings))
 
-	total := a.cash
+	total := a.Cash() + a.hedgeValue()
 	for ast, qty := range a.holdings {
-		mv := priceData.Value(ast, data.MetricClose)
+		mv := a.marketValue(ast, qty)
 		if math.IsNaN(mv) {
 			// Fall back to the last-known price if today's close is NaN. Note: this
 			// recovers priorPrice = lastMV / lastQty, which embeds the pre-split
@@ -1802,7 +1948,7 @@ func (a *Account) UpdatePrices(priceData *data.DataFrame) {
 			continue
 		}
 
-		stepMV[ast] = qty * mv
+		stepMV[ast] = mv
 		total += stepMV[ast]
 	}
 
@@ -1867,7 +2013,8 @@ func (a *Account) UpdatePrices(priceData *data.DataFrame) {
 	}
 
 	// $CASH row every step, including zero balance.
-	a.appendPositionRow(cashSentinel, a.cash, a.cash, histLen)
+	cash := a.Cash()
+	a.appendPositionRow(cashSentinel, cash, cash, histLen)
 
 	// Invalidate lazily-computed DataFrames so they are recomputed on next access.
 	a.dfCache = nil
@@ -2046,6 +2193,12 @@ func (a *Account) Annotations() []Annotation {
 	return a.annotations
 }
 
+// SkippedTrades returns the rebalance trades that executed batches left
+// out, in the order they were recorded.
+func (a *Account) SkippedTrades() []SkippedTrade {
+	return a.skippedTrades
+}
+
 // Use appends one or more middleware to the processing chain.
 func (a *Account) Use(middleware ...Middleware) {
 	a.middleware = append(a.middleware, middleware...)
@@ -2094,6 +2247,8 @@ func (a *Account) ExecuteBatch(ctx context.Context, batch *Batch) error {
 		for key, value := range batch.Annotations {
 			a.Annotate(batch.Timestamp, key, value)
 		}
+
+		a.skippedTrades = append(a.skippedTrades, batch.SkippedTrades...)
 	}
 
 	// 3. Only submit if there are new orders and a broker is set.
@@ -2111,6 +2266,7 @@ func (a *Account) ExecuteBatch(ctx context.Context, batch *Batch) error {
 		}
 
 		order.BatchID = batchID
+		*order = a.localizeOrder(*order)
 
 		a.pendingOrders[order.ID] = *order
 
@@ -2230,6 +2386,13 @@ type deferredExitInfo struct {
 	batchID int
 }
 
+// orderFill is the quantity an order has filled so far and what it cost,
+// as the sum of price times quantity over its fills.
+type orderFill struct {
+	qty  float64
+	cost float64
+}
+
 // drainFillsFromChannel reads all available fills from the broker's
 // fill channel (non-blocking) and records each as a transaction. After
 // draining, it submits deferred bracket exit orders for any entry fills.
@@ -2246,98 +2409,171 @@ func (a *Account) drainFillsFromChannel() {
 	for {
 		select {
 		case fill := <-fillCh:
-			order, ok := a.pendingOrders[fill.OrderID]
-			if !ok {
-				log.Warn().Str("orderID", fill.OrderID).Msg("received fill for unknown order")
-				continue
-			}
+			pendingExits = a.applyFill(fill, pendingExits)
+		default:
+			goto phase2
+		}
+	}
+
+phase2:
+	// Phase 2: Submit deferred bracket exit orders.
+	for _, exitInfo := range pendingExits {
+		a.submitBracketExits(exitInfo)
+	}
+}
+
+// applyFill records a single fill against its pending order: it books the
+// trade transaction, keeps partially filled orders pending, and handles
+// bracket and OCO group bookkeeping. Bracket entries that fill are appended
+// to pendingExits so the caller can submit their exit legs once all fills
+// in the current pass have been applied.
+func (a *Account) applyFill(fill broker.Fill, pendingExits []deferredExitInfo) []deferredExitInfo {
+	order, ok := a.pendingOrders[fill.OrderID]
+	if !ok {
+		log.Warn().Str("orderID", fill.OrderID).Msg("received fill for unknown order")
+		return pendingExits
+	}
+
+	// Record the outcome on the batch currently executing so a
+	// strategy's Reconcile can see what filled and what failed.
+	if a.currentBatch != nil && order.BatchID == a.currentBatchID {
+		a.currentBatch.Outcomes = append(a.currentBatch.Outcomes, OrderOutcome{
+			Order:  order,
+			Filled: fill.Err == nil,
+			Qty:    fill.Qty,
+			Price:  fill.Price,
+			Err:    fill.Err,
+		})
+	}
+
+	// A failed fill did not execute: drop the order without recording
+	// a transaction or running group/exit handling.
+	if fill.Err != nil {
+		delete(a.pendingOrders, fill.OrderID)
+		delete(a.orderFills, fill.OrderID)
+		a.notifyFill(order, fill)
+
+		return pendingExits
+	}
+
+	var (
+		txType asset.TransactionType
+		amount float64
+	)
+
+	switch order.Side {
+	case broker.Buy:
+		txType = asset.BuyTransaction
+		amount = -(fill.Price * fill.Qty * order.Asset.ContractMultiplier())
+	case broker.Sell:
+		txType = asset.SellTransaction
+		amount = fill.Price * fill.Qty * order.Asset.ContractMultiplier()
+	}
+
+	a.Record(Transaction{
+		Date:          fill.FilledAt,
+		Asset:         order.Asset,
+		Type:          txType,
+		Qty:           fill.Qty,
+		Price:         fill.Price,
+		Amount:        amount,
+		Justification: order.Justification,
+		LotSelection:  LotSelection(order.LotSelection),
+		BatchID:       order.BatchID,
+		OrderID:       order.ID,
+	})
+
+	for _, fee := range fill.Fees {
+		a.Record(Transaction{
+			Date:          fill.FilledAt,
+			Asset:         order.Asset,
+			Type:          asset.FeeTransaction,
+			Amount:        -fee.Amount,
+			Justification: fee.Description,
+			BatchID:       order.BatchID,
+			OrderID:       order.ID,
+		})
+	}
+
+	// Partial fill: keep the order pending with the remaining
+	// quantity so later fills for the same order ID are still
+	// recognized rather than dropped as unknown.
+	if order.Qty-fill.Qty > 1e-9 {
+		order.Qty -= fill.Qty
+		a.pendingOrders[fill.OrderID] = order
 
-			// Record the outcome on the batch currently executing so a
-			// strategy's Reconcile can see what filled and what failed.
-			if a.currentBatch != nil && order.BatchID == a.currentBatchID {
-				a.currentBatch.Outcomes = append(a.currentBatch.Outcomes, OrderOutcome{
-					Order:  order,
-					Filled: fill.Err == nil,
-					Qty:    fill.Qty,
-					Price:  fill.Price,
-					Err:    fill.Err,
+		progress := a.orderFills[fill.OrderID]
+		progress.qty += fill.Qty
+		progress.cost += fill.Price * fill.Qty
+		a.orderFills[fill.OrderID] = progress
+	} else {
+		delete(a.pendingOrders, fill.OrderID)
+		delete(a.orderFills, fill.OrderID)
+	}
+
+	// Group handling for the filled order.
+	if order.GroupID != "" {
+		switch order.GroupRole {
+		case broker.RoleEntry:
+			// Bracket entry filled: collect deferred exits for Phase 2.
+			if spec, found := a.deferredExits[order.GroupID]; found {
+				pendingExits = append(pendingExits, deferredExitInfo{
+					groupID:   order.GroupID,
+					spec:      spec,
+					entrySide: order.Side,
+					fillPrice: fill.Price,
+					asset:     order.Asset,
+					qty:       fill.Qty,
+					batchID:   order.BatchID,
 				})
+				delete(a.deferredExits, order.GroupID)
 			}
 
-			// A failed fill did not execute: drop the order without recording
-			// a transaction or running group/exit handling.
-			if fill.Err != nil {
-				delete(a.pendingOrders, fill.OrderID)
-				continue
-			}
+		case broker.RoleStopLoss, broker.RoleTakeProfit:
+			// OCO leg filled: cancel siblings.
+			a.cancelOCOSiblings(order.GroupID, fill.OrderID)
+		}
+	}
 
-			var (
-				txType asset.TransactionType
-				amount float64
-			)
-
-			switch order.Side {
-			case broker.Buy:
-				txType = asset.BuyTransaction
-				amount = -(fill.Price * fill.Qty)
-			case broker.Sell:
-				txType = asset.SellTransaction
-				amount = fill.Price * fill.Qty
-			}
+	a.notifyFill(order, fill)
 
-			a.Record(Transaction{
-				Date:          fill.FilledAt,
-				Asset:         order.Asset,
-				Type:          txType,
-				Qty:           fill.Qty,
-				Price:         fill.Price,
-				Amount:        amount,
-				Justification: order.Justification,
-				LotSelection:  LotSelection(order.LotSelection),
-				BatchID:       order.BatchID,
-			})
+	return pendingExits
+}
 
-			// Partial fill: keep the order pending with the remaining
-			// quantity so later fills for the same order ID are still
-			// recognized rather than dropped as unknown.
-			if order.Qty-fill.Qty > 1e-9 {
-				order.Qty -= fill.Qty
-				a.pendingOrders[fill.OrderID] = order
-			} else {
-				delete(a.pendingOrders, fill.OrderID)
-			}
+// ReplayFills applies fills that were not delivered on the broker's fill
+// channel, such as fills that occurred while a live session was down.
+// Brokers report replayed fills as the order's cumulative quantity and
+// average price, so only the part beyond what the account already
+// recorded for the order is applied, at the price that makes up the
+// difference in cost. Fills for orders that are not pending, or that add
+// nothing, are ignored, so replaying a fill that was already applied is
+// harmless. The increment is capped at the order's remaining quantity.
+func (a *Account) ReplayFills(fills []broker.Fill) {
+	var pendingExits []deferredExitInfo
 
-			// Group handling for the filled order.
-			if order.GroupID != "" {
-				switch order.GroupRole {
-				case broker.RoleEntry:
-					// Bracket entry filled: collect deferred exits for Phase 2.
-					if spec, found := a.deferredExits[order.GroupID]; found {
-						pendingExits = append(pendingExits, deferredExitInfo{
-							groupID:   order.GroupID,
-							spec:      spec,
-							entrySide: order.Side,
-							fillPrice: fill.Price,
-							asset:     order.Asset,
-							qty:       fill.Qty,
-							batchID:   order.BatchID,
-						})
-						delete(a.deferredExits, order.GroupID)
-					}
+	for _, fill := range fills {
+		order, ok := a.pendingOrders[fill.OrderID]
+		if !ok {
+			continue
+		}
 
-				case broker.RoleStopLoss, broker.RoleTakeProfit:
-					// OCO leg filled: cancel siblings.
-					a.cancelOCOSiblings(order.GroupID, fill.OrderID)
-				}
-			}
+		progress := a.orderFills[fill.OrderID]
 
-		default:
-			goto phase2
+		newQty := fill.Qty - progress.qty
+		if newQty <= 1e-9 {
+			continue
 		}
+
+		fill.Price = (fill.Price*fill.Qty - progress.cost) / newQty
+		fill.Qty = newQty
+
+		if order.Qty > 0 && fill.Qty > order.Qty {
+			fill.Qty = order.Qty
+		}
+
+		pendingExits = a.applyFill(fill, pendingExits)
 	}
 
-phase2:
-	// Phase 2: Submit deferred bracket exit orders.
 	for _, exitInfo := range pendingExits {
 		a.submitBracketExits(exitInfo)
 	}
@@ -2405,6 +2641,15 @@ func (a *Account) submitBracketExits(info deferredExitInfo) {
 		BatchID:     info.batchID,
 	}
 
+	// A trailing stop loss starts the trail's distance from the fill
+	// price; the broker ratchets it from there.
+	if trail := info.spec.StopLoss.Trail; !trail.isZero() {
+		stopLossOrder.OrderType = broker.TrailingStop
+		stopLossOrder.TrailAmount = trail.Amount
+		stopLossOrder.TrailPercent = trail.Percent
+		stopLossOrder.StopPrice = broker.TrailStop(stopLossOrder, info.fillPrice)
+	}
+
 	takeProfitOrder := broker.Order{
 		ID:          exitGroupID + "-tp",
 		Asset:       info.asset,
@@ -2473,11 +2718,18 @@ func (a *Account) CancelOpenOrders(ctx context.Context) error {
 	var errs []error
 
 	// Separate bracket exit orders (stop-loss/take-profit) from regular orders.
-	// Bracket exits persist across bars until triggered by EvaluatePending.
+	// Bracket exits and good-til-cancelled trailing stops persist across bars
+	// until triggered by EvaluatePending.
 	survivingOrders := make(map[string]broker.Order)
 	survivingGroups := make(map[string]*broker.OrderGroup)
 
 	for orderID, order := range a.pendingOrders {
+		if order.OrderType.IsTrailing() && order.TimeInForce == broker.GTC && order.GroupID == "" {
+			survivingOrders[orderID] = order
+
+			continue
+		}
+
 		if order.GroupRole == broker.RoleStopLoss || order.GroupRole == broker.RoleTakeProfit {
 			survivingOrders[orderID] = order
 
@@ -2632,8 +2884,10 @@ func (acct *Account) Clone() PortfolioManager {
 		metrics:           acct.metrics,
 		registeredMetrics: acct.registeredMetrics,
 		annotations:       annotations,
+		skippedTrades:     slices.Clone(acct.skippedTrades),
 		middleware:        acct.middleware,
 		pendingOrders:     pendingOrders,
+		orderFills:        maps.Clone(acct.orderFills),
 		pendingGroups:     pendingGroups,
 		brokerHasGroups:   acct.brokerHasGroups,
 		deferredExits:     deferredExits,
@@ -2652,6 +2906,14 @@ func (acct *Account) Clone() PortfolioManager {
 		maxLeverage:              acct.maxLeverage,
 		grossMaintenanceLeverage: acct.grossMaintenanceLeverage,
 		borrowRate:               acct.borrowRate,
+
+		dividendReinvestment: acct.dividendReinvestment,
+		fxRates:              maps.Clone(acct.fxRates),
+		foreignCash:          maps.Clone(acct.foreignCash),
+		holdForeignCash:      acct.holdForeignCash,
+		currencyHedge:        acct.currencyHedge,
+		fxForwards:           maps.Clone(acct.fxForwards),
+		futuresMarks:         maps.Clone(acct.futuresMarks),
 	}
 
 	if acct.perfData != nil {
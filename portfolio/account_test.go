@@ -798,6 +798,91 @@ var _ = Describe("Account", func() {
 		})
 	})
 
+	Describe("Record journal adjustments", func() {
+		It("adjusts holdings without moving cash when the journal carries an asset", func() {
+			a := portfolio.New(portfolio.WithCash(10_000, time.Time{}))
+			a.Record(portfolio.Transaction{
+				Date:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+				Asset:  spy,
+				Type:   asset.BuyTransaction,
+				Qty:    10,
+				Price:  300.0,
+				Amount: -3_000.0,
+			})
+
+			a.Record(portfolio.Transaction{
+				Date:  time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+				Asset: spy,
+				Type:  asset.JournalTransaction,
+				Qty:   2,
+				Price: 305.0,
+			})
+			Expect(a.Position(spy)).To(Equal(12.0))
+			Expect(a.Cash()).To(Equal(7_000.0))
+
+			a.Record(portfolio.Transaction{
+				Date:  time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC),
+				Asset: spy,
+				Type:  asset.JournalTransaction,
+				Qty:   -12,
+			})
+			Expect(a.Position(spy)).To(Equal(0.0))
+			Expect(a.Holdings()).NotTo(HaveKey(spy))
+			Expect(a.Cash()).To(Equal(7_000.0))
+		})
+
+		It("covers short lots first and opens short lots when the position goes negative", func() {
+			a := portfolio.New(portfolio.WithCash(10_000, time.Time{}))
+			a.Record(portfolio.Transaction{
+				Date:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+				Asset:  spy,
+				Type:   asset.SellTransaction,
+				Qty:    10,
+				Price:  300.0,
+				Amount: 3_000.0,
+			})
+
+			shortQty := func() float64 {
+				total := 0.0
+				a.ShortLots(func(ast asset.Asset, lots []portfolio.TaxLot) {
+					if ast == spy {
+						for _, lot := range lots {
+							total += lot.Qty
+						}
+					}
+				})
+
+				return total
+			}
+
+			// Journaling 15 shares in covers the 10 short and leaves 5 long.
+			a.Record(portfolio.Transaction{
+				Date:  time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+				Asset: spy,
+				Type:  asset.JournalTransaction,
+				Qty:   15,
+				Price: 305.0,
+			})
+			Expect(a.Position(spy)).To(Equal(5.0))
+			Expect(shortQty()).To(Equal(0.0))
+			Expect(a.TaxLots()[spy]).To(HaveLen(1))
+			Expect(a.TaxLots()[spy][0].Qty).To(Equal(5.0))
+
+			// Journaling 8 shares out removes the 5 long and opens 3 short.
+			a.Record(portfolio.Transaction{
+				Date:  time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC),
+				Asset: spy,
+				Type:  asset.JournalTransaction,
+				Qty:   -8,
+				Price: 310.0,
+			})
+			Expect(a.Position(spy)).To(Equal(-3.0))
+			Expect(a.TaxLots()[spy]).To(BeEmpty())
+			Expect(shortQty()).To(Equal(3.0))
+			Expect(a.Cash()).To(Equal(13_000.0))
+		})
+	})
+
 	Describe("WithCash(0, time.Time{})", func() {
 		It("records no deposit transaction when cash is 0", func() {
 			a := portfolio.New(portfolio.WithCash(0, time.Time{}))
@@ -1824,6 +1909,44 @@ var _ = Describe("Window", func() {
 			Expect(takeProfit.TimeInForce).To(Equal(broker.GTC))
 		})
 
+		It("submits a trailing stop loss that starts the trail from the fill price", func() {
+			mgb := newMockGroupBroker()
+			mgb.submitFn = func(ord broker.Order) error {
+				mgb.fillCh <- broker.Fill{OrderID: ord.ID, Price: 100.0, Qty: ord.Qty, FilledAt: ts}
+				return nil
+			}
+
+			acct := portfolio.New(
+				portfolio.WithCash(50_000, ts),
+				portfolio.WithBroker(mgb),
+			)
+
+			df := buildDF(ts, []asset.Asset{testAsset}, []float64{100.0}, []float64{100.0})
+			acct.UpdatePrices(df)
+
+			batch := acct.NewBatch(ts)
+			Expect(batch.Order(context.Background(), testAsset, portfolio.Buy, 10,
+				portfolio.WithBracket(
+					portfolio.TrailingStopLoss(portfolio.TrailPercent(8)),
+					portfolio.TakeProfitPercent(10),
+				))).To(Succeed())
+			Expect(acct.ExecuteBatch(context.Background(), batch)).To(Succeed())
+
+			Expect(mgb.submittedGroups).To(HaveLen(1))
+
+			var stopLoss broker.Order
+			for _, ord := range mgb.submittedGroups[0].orders {
+				if ord.GroupRole == broker.RoleStopLoss {
+					stopLoss = ord
+				}
+			}
+
+			Expect(stopLoss.OrderType).To(Equal(broker.TrailingStop))
+			Expect(stopLoss.TrailPercent).To(Equal(8.0))
+			Expect(stopLoss.StopPrice).To(BeNumerically("~", 92.0, 0.001))
+			Expect(stopLoss.Side).To(Equal(broker.Sell))
+		})
+
 		It("cancels OCO sibling on fill without calling broker.Cancel for GroupSubmitter broker", func() {
 			mgb := newMockGroupBroker()
 
@@ -2162,6 +2285,54 @@ var _ = Describe("batch history", func() {
 		Expect(tradeTxn.BatchID).To(Equal(1))
 	})
 
+	It("records fill fees as fee transactions linked to the order", func() {
+		ctx := context.Background()
+		ts := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+		spy := asset.Asset{CompositeFigi: "SPY", Ticker: "SPY"}
+
+		mb := newMockBroker()
+		mb.fillsByAsset = map[asset.Asset][]broker.Fill{
+			spy: {{
+				Price:    100.0,
+				Qty:      10,
+				FilledAt: ts,
+				Fees: []broker.Fee{
+					{Amount: 1.00, Description: "commission"},
+					{Amount: 0.05, Description: "exchange fee"},
+				},
+			}},
+		}
+
+		acct := portfolio.New(portfolio.WithCash(100_000, ts), portfolio.WithBroker(mb))
+		acct.UpdatePrices(buildDF(ts, []asset.Asset{spy}, []float64{100.0}, []float64{100.0}))
+
+		batch := acct.NewBatch(ts)
+		Expect(batch.Order(ctx, spy, portfolio.Buy, 10)).To(Succeed())
+		Expect(acct.ExecuteBatch(ctx, batch)).To(Succeed())
+
+		var (
+			tradeTxn portfolio.Transaction
+			feeTxns  []portfolio.Transaction
+		)
+
+		for _, txn := range acct.Transactions() {
+			switch txn.Type {
+			case asset.BuyTransaction:
+				tradeTxn = txn
+			case asset.FeeTransaction:
+				feeTxns = append(feeTxns, txn)
+			}
+		}
+
+		Expect(tradeTxn.OrderID).NotTo(BeEmpty())
+		Expect(feeTxns).To(HaveLen(2))
+		Expect(feeTxns[0].OrderID).To(Equal(tradeTxn.OrderID))
+		Expect(feeTxns[0].Amount).To(Equal(-1.00))
+		Expect(feeTxns[0].Justification).To(Equal("commission"))
+		Expect(feeTxns[1].Amount).To(Equal(-0.05))
+		Expect(acct.Cash()).To(BeNumerically("~", 100_000-1_000-1.05, 1e-9))
+	})
+
 	It("preserves batches and currentBatchID on Clone", func() {
 		ctx := context.Background()
 		ts := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
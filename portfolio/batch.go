@@ -19,6 +19,8 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/penny-vault/pvbt/asset"
@@ -26,6 +28,13 @@ import (
 	"github.com/penny-vault/pvbt/data"
 )
 
+// TriggerAnnotation prefixes the batch annotation the engine records for
+// each data-driven trigger that caused the batch. The full key is the
+// prefix followed by the trigger's name; the value describes the condition
+// that was met. Middleware can look for these keys (or call
+// Batch.Triggers) to react to a firing before the next scheduled run.
+const TriggerAnnotation = "trigger."
+
 // OrderGroupSpec describes an order group before submission.
 type OrderGroupSpec struct {
 	GroupID    string
@@ -50,6 +59,10 @@ type Batch struct {
 	// Annotations holds key-value metadata accumulated by calls to Annotate.
 	Annotations map[string]string
 
+	// SkippedTrades holds the rebalance trades RebalanceWithin left out
+	// because they fell inside a band or below the minimum trade size.
+	SkippedTrades []SkippedTrade
+
 	// Outcomes records how each submitted order resolved -- filled or
 	// failed. Account.ExecuteBatch appends to it as the broker's fills
 	// drain. A strategy's Reconcile reads it (via Outcomes / FailedOrders)
@@ -146,6 +159,23 @@ func (b *Batch) Annotate(key, value string) {
 	b.Annotations[key] = value
 }
 
+// Triggers returns the sorted names of the triggers that caused the batch,
+// read from its TriggerAnnotation entries. It returns nil for a batch run
+// on the strategy's schedule.
+func (b *Batch) Triggers() []string {
+	var names []string
+
+	for key := range b.Annotations {
+		if name, ok := strings.CutPrefix(key, TriggerAnnotation); ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
 // Order accumulates a broker.Order in the batch without executing it.
 // The side, asset, and quantity are recorded along with any modifiers
 // (Limit, Stop, time-in-force, WithJustification, etc.).
@@ -241,14 +271,16 @@ func (b *Batch) Order(_ context.Context, ast asset.Asset, side Side, qty float64
 }
 
 // applyOrderModifiers applies the order modifiers to order, setting limit and
-// stop prices, time-in-force, justification, and lot selection, and inferring
-// the order type from the presence of limit/stop prices. Bracket and OCO
-// modifiers are not applied to the order directly; they are returned so the
-// caller can perform the group expansion. Either return value is nil when the
-// corresponding modifier is absent.
+// stop prices, trailing stops, time-in-force, justification, and lot
+// selection, and inferring the order type from the presence of limit/stop
+// prices. Bracket and OCO modifiers are not applied to the order directly;
+// they are returned so the caller can perform the group expansion. Either
+// return value is nil when the corresponding modifier is absent.
 func applyOrderModifiers(order *broker.Order, mods []OrderModifier) (*bracketModifier, *ocoModifier) {
 	var hasLimit, hasStop bool
 
+	var trailing *trailingStopModifier
+
 	var bracket *bracketModifier
 
 	var oco *ocoModifier
@@ -261,6 +293,9 @@ func applyOrderModifiers(order *broker.Order, mods []OrderModifier) (*bracketMod
 		case stopModifier:
 			order.StopPrice = modifier.price
 			hasStop = true
+		case trailingStopModifier:
+			captured := modifier
+			trailing = &captured
 		case dayOrderModifier:
 			order.TimeInForce = broker.Day
 		case goodTilCancelModifier:
@@ -280,6 +315,8 @@ func applyOrderModifiers(order *broker.Order, mods []OrderModifier) (*bracketMod
 			order.Justification = modifier.reason
 		case lotSelectionModifier:
 			order.LotSelection = int(modifier.method)
+		case execAlgoModifier:
+			modifier.apply(order)
 		case bracketModifier:
 			captured := modifier
 			bracket = &captured
@@ -297,6 +334,11 @@ func applyOrderModifiers(order *broker.Order, mods []OrderModifier) (*bracketMod
 		order.OrderType = broker.Stop
 	}
 
+	// A trailing stop takes precedence over fixed limit and stop prices.
+	if trailing != nil {
+		trailing.apply(order)
+	}
+
 	return bracket, oco
 }
 
@@ -336,6 +378,26 @@ func (b *Batch) Allocate(_ context.Context, ast asset.Asset, weight float64, mod
 		return fmt.Errorf("portfolio: Allocate does not support bracket or OCO modifiers")
 	}
 
+	// Execution algorithms work the order in share slices, so size it in
+	// shares up front.
+	if order.ExecAlgo != broker.ExecImmediate {
+		price := b.priceOf(ast)
+		if price <= 0 {
+			return fmt.Errorf("portfolio: Allocate with an execution algorithm needs a price for %s", ast.Ticker)
+		}
+
+		order.Qty = order.Amount / price
+		if !order.Fractional {
+			order.Qty = math.Floor(order.Qty)
+		}
+
+		order.Amount = 0
+
+		if order.Qty == 0 {
+			return nil
+		}
+	}
+
 	b.Orders = append(b.Orders, order)
 
 	return nil
@@ -380,118 +442,165 @@ func (b *Batch) Liquidate(_ context.Context, ast asset.Asset, mods ...OrderModif
 // buy/sell amounts.
 func (b *Batch) RebalanceTo(_ context.Context, allocs ...Allocation) error {
 	for _, alloc := range allocs {
-		// Filter out $CASH entries -- cash is the implicit remainder.
-		filtered := make(map[asset.Asset]float64, len(alloc.Members))
-		for memberAsset, weight := range alloc.Members {
-			if memberAsset.Ticker != "$CASH" {
-				filtered[memberAsset] = weight
-			}
-		}
+		b.rebalance(alloc, rebalanceConfig{})
+	}
 
-		alloc = Allocation{
-			Date:          alloc.Date,
-			Members:       filtered,
-			Justification: alloc.Justification,
-		}
+	return nil
+}
+
+// RebalanceWithin is RebalanceTo with tolerances: positions whose weight
+// has drifted less than an AbsoluteBand or RelativeBand are left alone,
+// trades smaller than MinTradeNotional are dropped, TradeToBand trades
+// only back to the edge of the band, and CashBuffer holds part of the
+// portfolio in cash. Each trade it skips is recorded in SkippedTrades,
+// annotated under RebalanceSkipAnnotation plus the ticker, and counted in
+// TradeMetrics. Assets absent from the allocation are liquidated as in
+// RebalanceTo.
+func (b *Batch) RebalanceWithin(_ context.Context, alloc Allocation, opts ...RebalanceOption) error {
+	var cfg rebalanceConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.cashBuffer < 0 || cfg.cashBuffer >= 1 {
+		return fmt.Errorf("portfolio: RebalanceWithin: cash buffer %g must be at least 0 and less than 1", cfg.cashBuffer)
+	}
 
-		totalValue := b.ProjectedValue()
+	b.rebalance(alloc, cfg)
+
+	return nil
+}
 
-		type pendingOrder struct {
-			asset  asset.Asset
-			side   Side
-			qty    float64 // share count for full liquidations
-			amount float64 // dollar amount for partial adjustments
+// rebalance appends the orders that move the batch's projected holdings to
+// alloc under the tolerances in cfg. A zero cfg trades every member to its
+// exact target weight.
+func (b *Batch) rebalance(alloc Allocation, cfg rebalanceConfig) {
+	// Filter out $CASH entries -- cash is the implicit remainder.
+	filtered := make(map[asset.Asset]float64, len(alloc.Members))
+	for memberAsset, weight := range alloc.Members {
+		if memberAsset.Ticker != "$CASH" {
+			filtered[memberAsset] = weight
 		}
+	}
 
-		var sells []pendingOrder
+	alloc = Allocation{
+		Date:          alloc.Date,
+		Members:       filtered,
+		Justification: alloc.Justification,
+	}
 
-		var coverBuys []pendingOrder
+	totalValue := b.ProjectedValue()
 
-		// Liquidate all positions not in the target allocation.
-		// Long positions are sold; short positions are covered (bought back).
-		// Projected holdings (not actual) so positions already sold by
-		// earlier orders in this batch are not sold a second time.
-		for ast, qty := range b.ProjectedHoldings() {
-			if _, ok := alloc.Members[ast]; !ok && qty != 0 {
-				if qty > 0 {
-					sells = append(sells, pendingOrder{asset: ast, side: Sell, qty: qty})
-				} else {
-					coverBuys = append(coverBuys, pendingOrder{asset: ast, side: Buy, qty: math.Abs(qty)})
-				}
+	// Members inside their band are absent from targets and not traded.
+	targets := b.bandTargets(alloc, totalValue, cfg)
+
+	type pendingOrder struct {
+		asset  asset.Asset
+		side   Side
+		qty    float64 // share count for full liquidations
+		amount float64 // dollar amount for partial adjustments
+	}
+
+	var sells []pendingOrder
+
+	var coverBuys []pendingOrder
+
+	// Liquidate all positions not in the target allocation.
+	// Long positions are sold; short positions are covered (bought back).
+	// Projected holdings (not actual) so positions already sold by
+	// earlier orders in this batch are not sold a second time.
+	for ast, qty := range b.ProjectedHoldings() {
+		if _, ok := alloc.Members[ast]; !ok && qty != 0 {
+			if qty > 0 {
+				sells = append(sells, pendingOrder{asset: ast, side: Sell, qty: qty})
+			} else {
+				coverBuys = append(coverBuys, pendingOrder{asset: ast, side: Buy, qty: math.Abs(qty)})
 			}
 		}
+	}
 
-		// Sell overweight positions.
-		for ast, weight := range alloc.Members {
-			targetDollars := weight * totalValue
-			currentDollars := b.projectedPositionValue(ast)
-			diff := targetDollars - currentDollars
+	// Sell overweight positions.
+	trimmed := make(map[asset.Asset]bool)
 
-			if diff < 0 {
-				sells = append(sells, pendingOrder{asset: ast, side: Sell, amount: -diff})
-			}
+	for ast, weight := range targets {
+		targetDollars := weight * totalValue
+		currentDollars := b.projectedPositionValue(ast)
+		diff := targetDollars - currentDollars
+
+		if diff < 0 && !b.belowMinimum(ast, -diff, cfg) {
+			sells = append(sells, pendingOrder{asset: ast, side: Sell, amount: -diff})
+			trimmed[ast] = true
 		}
+	}
 
-		for _, sell := range sells {
-			order := broker.Order{
-				Asset:         sell.asset,
-				Side:          broker.Sell,
-				Qty:           sell.qty,
-				Amount:        sell.amount,
-				OrderType:     broker.Market,
-				TimeInForce:   broker.Day,
-				Justification: alloc.Justification,
-			}
-			b.Orders = append(b.Orders, order)
-		}
-
-		// Cover short positions not in the target allocation.
-		for _, cover := range coverBuys {
-			order := broker.Order{
-				Asset:         cover.asset,
-				Side:          broker.Buy,
-				Qty:           cover.qty,
-				OrderType:     broker.Market,
-				TimeInForce:   broker.Day,
-				Justification: alloc.Justification,
-			}
-			b.Orders = append(b.Orders, order)
+	for _, sell := range sells {
+		order := broker.Order{
+			Asset:         sell.asset,
+			Side:          broker.Sell,
+			Qty:           sell.qty,
+			Amount:        sell.amount,
+			OrderType:     broker.Market,
+			TimeInForce:   broker.Day,
+			Justification: alloc.Justification,
+		}
+		b.Orders = append(b.Orders, order)
+	}
+
+	// Cover short positions not in the target allocation.
+	for _, cover := range coverBuys {
+		order := broker.Order{
+			Asset:         cover.asset,
+			Side:          broker.Buy,
+			Qty:           cover.qty,
+			OrderType:     broker.Market,
+			TimeInForce:   broker.Day,
+			Justification: alloc.Justification,
 		}
+		b.Orders = append(b.Orders, order)
+	}
 
-		// Recompute value after projected sells/covers to use actual available cash.
-		postSellValue := b.ProjectedValue()
+	// Recompute value after projected sells/covers to use actual available cash.
+	postSellValue := b.ProjectedValue()
 
-		var buys []pendingOrder
+	var buys []pendingOrder
 
-		for ast, weight := range alloc.Members {
-			targetDollars := weight * postSellValue
-			currentDollars := b.projectedPositionValue(ast)
-			diff := targetDollars - currentDollars
+	for ast, weight := range targets {
+		targetDollars := weight * postSellValue
+		currentDollars := b.projectedPositionValue(ast)
+		diff := targetDollars - currentDollars
 
-			if diff > 0 {
-				buys = append(buys, pendingOrder{asset: ast, side: Buy, amount: diff})
-			}
+		if diff <= 0 {
+			continue
 		}
 
-		for _, buy := range buys {
-			order := broker.Order{
-				Asset:         buy.asset,
-				Side:          broker.Buy,
-				Amount:        buy.amount,
-				OrderType:     broker.Market,
-				TimeInForce:   broker.Day,
-				Justification: alloc.Justification,
-			}
-			b.Orders = append(b.Orders, order)
+		// A small residual buy on a position just trimmed is share
+		// rounding, not a trade worth recording as skipped.
+		if trimmed[ast] && diff < cfg.minTradeNotional {
+			continue
+		}
+
+		if !b.belowMinimum(ast, diff, cfg) {
+			buys = append(buys, pendingOrder{asset: ast, side: Buy, amount: diff})
 		}
 	}
 
-	return nil
+	for _, buy := range buys {
+		order := broker.Order{
+			Asset:         buy.asset,
+			Side:          broker.Buy,
+			Amount:        buy.amount,
+			OrderType:     broker.Market,
+			TimeInForce:   broker.Day,
+			Justification: alloc.Justification,
+		}
+		b.Orders = append(b.Orders, order)
+	}
 }
 
 // ProjectedHoldings returns what holdings would be if all batch orders
 // executed at last known prices. Dollar-amount orders are converted to share
-// quantities using math.Floor(amount / price). Assets with unknown prices
+// quantities using math.Floor(amount / price), or amount / price for
+// fractional orders and crypto assets. Assets with unknown prices
 // (priceOf returns 0) that only appear in buy orders are not added to the
 // projected holdings.
 //
@@ -522,7 +631,10 @@ func (b *Batch) ProjectedHoldings() map[asset.Asset]float64 {
 		if order.Qty != 0 {
 			qty = order.Qty
 		} else if price > 0 && order.Amount > 0 {
-			qty = math.Floor(order.Amount / price)
+			qty = order.Amount / price
+			if !order.Fractional && !order.Asset.IsCrypto() {
+				qty = math.Floor(qty)
+			}
 		}
 
 		// Map the order asset to its logical original if a substitution is active.
@@ -552,12 +664,25 @@ func (b *Batch) ProjectedValue() float64 {
 	total := b.projectedCash()
 
 	for ast, qty := range b.ProjectedHoldings() {
+		if ast.IsFuture() {
+			continue
+		}
+
 		price := b.priceOf(ast)
 		if price > 0 {
 			total += qty * price
 		}
 	}
 
+	// A futures position is worth only its gain or loss since the last
+	// settlement, which trading the contract settles into cash rather
+	// than changes.
+	for ast := range b.portfolio.Holdings() {
+		if ast.IsFuture() {
+			total += b.portfolio.PositionValue(ast)
+		}
+	}
+
 	total += b.pendingUnpricedValue()
 
 	return total
@@ -655,10 +780,15 @@ func (b *Batch) projectedPositionValue(ast asset.Asset) float64 {
 
 // projectedCash returns the cash balance after all batch orders execute.
 // Sell orders (by qty or dollar amount) add cash; buy orders subtract cash.
+// Futures orders move no cash.
 func (b *Batch) projectedCash() float64 {
 	cash := b.portfolio.Cash()
 
 	for _, order := range b.unexecutedOrders() {
+		if order.Asset.IsFuture() {
+			continue
+		}
+
 		price := b.priceOf(order.Asset)
 
 		var dollarAmount float64
@@ -680,12 +810,15 @@ func (b *Batch) projectedCash() float64 {
 	return cash
 }
 
-// priceOf derives the per-share price for an asset. For held assets the price
-// is computed from PositionValue / Position. For assets not currently held, it
-// falls back to the portfolio's most-recent price DataFrame.
+// priceOf derives the per-share price for an asset, including an option's
+// contract multiplier. For held assets the price is computed from
+// PositionValue / Position. For assets not currently held, and for futures,
+// whose PositionValue is only the gain or loss since the last settlement,
+// it falls back to the portfolio's most-recent price DataFrame, so a
+// futures contract is priced at its notional value.
 func (b *Batch) priceOf(ast asset.Asset) float64 {
 	qty := b.portfolio.Position(ast)
-	if qty != 0 {
+	if qty != 0 && !ast.IsFuture() {
 		return b.portfolio.PositionValue(ast) / qty
 	}
 
@@ -695,7 +828,7 @@ func (b *Batch) priceOf(ast asset.Asset) float64 {
 		return 0
 	}
 
-	v := prices.Value(ast, data.MetricClose)
+	v := prices.Value(ast, data.MetricClose) * ast.ContractMultiplier() * b.portfolio.FXRate(ast.QuoteCurrency())
 	if math.IsNaN(v) {
 		return 0
 	}
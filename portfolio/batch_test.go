@@ -108,6 +108,28 @@ var _ = Describe("Batch", func() {
 		})
 	})
 
+	Describe("Triggers", func() {
+		It("returns the sorted names of trigger annotations", func() {
+			acct := buildPricedAccount(10_000, []asset.Asset{spy}, []float64{100})
+			batch := portfolio.NewBatch(ts, acct)
+
+			batch.Annotate("signal", "0.75")
+			batch.Annotate(portfolio.TriggerAnnotation+"VIX Close > 30", "VIX Close 31.20 > 30")
+			batch.Annotate(portfolio.TriggerAnnotation+"drawdown > 10.0%", "portfolio 10.4% below peak 11000.00")
+
+			Expect(batch.Triggers()).To(Equal([]string{"VIX Close > 30", "drawdown > 10.0%"}))
+		})
+
+		It("returns nil for a scheduled batch", func() {
+			acct := buildPricedAccount(10_000, []asset.Asset{spy}, []float64{100})
+			batch := portfolio.NewBatch(ts, acct)
+
+			batch.Annotate("signal", "0.75")
+
+			Expect(batch.Triggers()).To(BeNil())
+		})
+	})
+
 	Describe("Order", func() {
 		It("accumulates a buy order without executing it", func() {
 			acct := buildPricedAccount(10_000, []asset.Asset{spy}, []float64{100})
@@ -181,6 +203,53 @@ var _ = Describe("Batch", func() {
 			Expect(batch.Orders[0].OrderType).To(Equal(broker.StopLimit))
 		})
 
+		It("applies TrailingStop modifier", func() {
+			acct := buildPricedAccount(10_000, []asset.Asset{spy}, []float64{100})
+			batch := portfolio.NewBatch(ts, acct)
+
+			Expect(batch.Order(context.Background(), spy, portfolio.Sell, 5,
+				portfolio.TrailingStop(portfolio.TrailAmount(2.5)))).To(Succeed())
+
+			Expect(batch.Orders[0].OrderType).To(Equal(broker.TrailingStop))
+			Expect(batch.Orders[0].TrailAmount).To(Equal(2.5))
+			Expect(batch.Orders[0].TrailPercent).To(BeZero())
+		})
+
+		It("applies TrailingStopLimit modifier over a fixed stop", func() {
+			acct := buildPricedAccount(10_000, []asset.Asset{spy}, []float64{100})
+			batch := portfolio.NewBatch(ts, acct)
+
+			Expect(batch.Order(context.Background(), spy, portfolio.Sell, 5, portfolio.Stop(90.0),
+				portfolio.TrailingStopLimit(portfolio.TrailPercent(5), 0.5))).To(Succeed())
+
+			Expect(batch.Orders[0].OrderType).To(Equal(broker.TrailingStopLimit))
+			Expect(batch.Orders[0].TrailPercent).To(Equal(5.0))
+			Expect(batch.Orders[0].LimitOffset).To(Equal(0.5))
+		})
+
+		It("applies ExecTWAP modifier", func() {
+			acct := buildPricedAccount(10_000, []asset.Asset{spy}, []float64{100})
+			batch := portfolio.NewBatch(ts, acct)
+
+			Expect(batch.Order(context.Background(), spy, portfolio.Buy, 50,
+				portfolio.ExecTWAP(30*time.Minute, 6))).To(Succeed())
+
+			Expect(batch.Orders[0].OrderType).To(Equal(broker.Market))
+			Expect(batch.Orders[0].ExecAlgo).To(Equal(broker.ExecTWAP))
+			Expect(batch.Orders[0].ExecDuration).To(Equal(30 * time.Minute))
+			Expect(batch.Orders[0].ExecSlices).To(Equal(6))
+		})
+
+		It("applies ExecPOV modifier", func() {
+			acct := buildPricedAccount(10_000, []asset.Asset{spy}, []float64{100})
+			batch := portfolio.NewBatch(ts, acct)
+
+			Expect(batch.Order(context.Background(), spy, portfolio.Buy, 50, portfolio.ExecPOV(10))).To(Succeed())
+
+			Expect(batch.Orders[0].ExecAlgo).To(Equal(broker.ExecPOV))
+			Expect(batch.Orders[0].ExecParticipation).To(Equal(10.0))
+		})
+
 		It("applies GoodTilCancel modifier", func() {
 			acct := buildPricedAccount(10_000, []asset.Asset{spy}, []float64{100})
 			batch := portfolio.NewBatch(ts, acct)
@@ -790,6 +859,116 @@ var _ = Describe("Batch", func() {
 		})
 	})
 
+	Describe("RebalanceWithin", func() {
+		// buildDriftedAccount holds 60 SPY @ 10 and 20 AAPL @ 20 with no
+		// cash: weights of 60% and 40%.
+		buildDriftedAccount := func() *portfolio.Account {
+			acct := buildPricedAccount(1_000, []asset.Asset{spy, aapl}, []float64{10, 20})
+			acct.Record(portfolio.Transaction{
+				Date: ts, Asset: spy, Type: asset.BuyTransaction, Qty: 60, Price: 10, Amount: -600,
+			})
+			acct.Record(portfolio.Transaction{
+				Date: ts, Asset: aapl, Type: asset.BuyTransaction, Qty: 20, Price: 20, Amount: -400,
+			})
+
+			return acct
+		}
+
+		evenSplit := func() portfolio.Allocation {
+			return portfolio.Allocation{Date: ts, Members: map[asset.Asset]float64{spy: 0.5, aapl: 0.5}}
+		}
+
+		It("skips positions inside the band and annotates them", func() {
+			batch := portfolio.NewBatch(ts, buildDriftedAccount())
+
+			Expect(batch.RebalanceWithin(context.Background(), evenSplit(),
+				portfolio.AbsoluteBand(0.15))).To(Succeed())
+
+			Expect(batch.Orders).To(BeEmpty())
+			Expect(batch.SkippedTrades).To(HaveLen(2))
+			Expect(batch.Annotations).To(HaveKeyWithValue(portfolio.RebalanceSkipAnnotation+"SPY",
+				HavePrefix("$100.00: drift 10.00%")))
+			Expect(batch.Annotations).To(HaveKey(portfolio.RebalanceSkipAnnotation + "AAPL"))
+		})
+
+		It("uses the narrower of the absolute and relative bands", func() {
+			batch := portfolio.NewBatch(ts, buildDriftedAccount())
+
+			// The relative band is 0.5 * 0.1 = 5%, narrower than 15%.
+			Expect(batch.RebalanceWithin(context.Background(), evenSplit(),
+				portfolio.AbsoluteBand(0.15), portfolio.RelativeBand(0.1))).To(Succeed())
+
+			Expect(ordersForAsset(ordersWithSide(batch.Orders, broker.Sell), spy)).To(HaveLen(1))
+			Expect(ordersForAsset(ordersWithSide(batch.Orders, broker.Buy), aapl)).To(HaveLen(1))
+		})
+
+		It("trades only back to the edge of the band", func() {
+			batch := portfolio.NewBatch(ts, buildDriftedAccount())
+
+			Expect(batch.RebalanceWithin(context.Background(), evenSplit(),
+				portfolio.AbsoluteBand(0.05), portfolio.TradeToBand())).To(Succeed())
+
+			sells := ordersForAsset(ordersWithSide(batch.Orders, broker.Sell), spy)
+			Expect(sells).To(HaveLen(1))
+			Expect(sells[0].Amount).To(BeNumerically("~", 50.0, 0.01))
+
+			buys := ordersForAsset(ordersWithSide(batch.Orders, broker.Buy), aapl)
+			Expect(buys).To(HaveLen(1))
+			Expect(buys[0].Amount).To(BeNumerically("~", 50.0, 0.01))
+		})
+
+		It("skips trades below the minimum notional", func() {
+			batch := portfolio.NewBatch(ts, buildDriftedAccount())
+
+			Expect(batch.RebalanceWithin(context.Background(), evenSplit(),
+				portfolio.MinTradeNotional(150))).To(Succeed())
+
+			Expect(batch.Orders).To(BeEmpty())
+			Expect(batch.Annotations).To(HaveKeyWithValue(portfolio.RebalanceSkipAnnotation+"AAPL",
+				"$100.00: below $150.00 minimum trade"))
+		})
+
+		It("holds a cash buffer by scaling the target weights", func() {
+			batch := portfolio.NewBatch(ts, buildDriftedAccount())
+
+			Expect(batch.RebalanceWithin(context.Background(), evenSplit(),
+				portfolio.CashBuffer(0.1))).To(Succeed())
+
+			sells := ordersForAsset(ordersWithSide(batch.Orders, broker.Sell), spy)
+			Expect(sells).To(HaveLen(1))
+			Expect(sells[0].Amount).To(BeNumerically("~", 150.0, 0.01))
+
+			buys := ordersForAsset(ordersWithSide(batch.Orders, broker.Buy), aapl)
+			Expect(buys).To(HaveLen(1))
+			Expect(buys[0].Amount).To(BeNumerically("~", 50.0, 0.01))
+		})
+
+		It("rejects a cash buffer of the whole portfolio", func() {
+			batch := portfolio.NewBatch(ts, buildDriftedAccount())
+
+			Expect(batch.RebalanceWithin(context.Background(), evenSplit(),
+				portfolio.CashBuffer(1))).To(MatchError(ContainSubstring("cash buffer")))
+		})
+
+		It("counts skipped trades in TradeMetrics", func() {
+			acct := buildDriftedAccount()
+			batch := portfolio.NewBatch(ts, acct)
+
+			Expect(batch.RebalanceWithin(context.Background(), evenSplit(),
+				portfolio.AbsoluteBand(0.15))).To(Succeed())
+
+			Expect(acct.ExecuteBatch(context.Background(), batch)).To(Succeed())
+
+			// Annotations alone, even under the skip prefix, are not counted.
+			acct.Annotate(ts, "momentum", "1.5")
+			acct.Annotate(ts, portfolio.RebalanceSkipAnnotation+"QQQ", "$500.00: manual note")
+
+			metrics, _ := acct.TradeMetrics()
+			Expect(metrics.SkippedTrades).To(Equal(2.0))
+			Expect(metrics.SkippedNotional).To(BeNumerically("~", 200.0, 0.01))
+		})
+	})
+
 	Describe("Allocate", func() {
 		It("appends a buy sized off projected value for an unheld asset", func() {
 			// 10k cash, no positions. Target SPY weight 0.5 => $5000 buy.
@@ -927,6 +1106,17 @@ var _ = Describe("Batch", func() {
 			Expect(batch.Orders[0].OrderType).To(Equal(broker.Limit))
 			Expect(batch.Orders[0].LimitPrice).To(Equal(99.0))
 		})
+
+		It("sizes an order with an execution algorithm in whole shares", func() {
+			acct := buildPricedAccount(10_000, []asset.Asset{spy}, []float64{100})
+			batch := portfolio.NewBatch(ts, acct)
+
+			Expect(batch.Allocate(context.Background(), spy, 0.555, portfolio.ExecVWAP(time.Hour, 12))).To(Succeed())
+
+			Expect(batch.Orders[0].ExecAlgo).To(Equal(broker.ExecVWAP))
+			Expect(batch.Orders[0].Qty).To(Equal(55.0))
+			Expect(batch.Orders[0].Amount).To(BeZero())
+		})
 	})
 
 	Describe("Liquidate", func() {
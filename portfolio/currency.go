@@ -0,0 +1,369 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/rs/zerolog/log"
+)
+
+// Justifications recorded on the FXTransactions the account creates.
+const (
+	CurrencyConversionJustification = "currency conversion"
+	CurrencyHedgeJustification      = "currency hedge settlement"
+)
+
+// CurrencyHedge configures a rolling currency hedge on foreign-currency
+// exposure. The account sells Ratio of each hedged currency's exposure
+// forward at the spot rate, holds the contract until the first rate
+// update of the next calendar month, settles its profit or loss in the
+// base currency as an FXTransaction, and opens a new contract sized to
+// the exposure at that time. Unrealized hedge profit and loss is
+// included in Value. Forward points (the interest-rate differential) are
+// not modeled, so a full hedge removes currency returns entirely.
+type CurrencyHedge struct {
+	// Ratio is the fraction of each currency's exposure to hedge: 1
+	// hedges it fully, 0.5 hedges half.
+	Ratio float64
+
+	// Currencies limits the hedge to these currencies. An empty list
+	// hedges every foreign currency the account is exposed to.
+	Currencies []asset.Currency
+}
+
+// covers reports whether exposure in cur is hedged.
+func (hedge CurrencyHedge) covers(cur asset.Currency) bool {
+	return len(hedge.Currencies) == 0 || slices.Contains(hedge.Currencies, cur)
+}
+
+// fxForward is an open currency hedge contract.
+type fxForward struct {
+	Notional float64   // foreign-currency units sold forward; negative when bought
+	Rate     float64   // contract rate in base currency per foreign unit
+	Opened   time.Time // date the contract was opened
+}
+
+// WithForeignCash keeps the proceeds of foreign-currency trades,
+// dividends, and fees in that currency. By default the account converts
+// every foreign-currency cash flow to the base currency at the spot rate
+// as it is recorded. With foreign cash held, a purchase can drive a
+// currency's balance negative, which is a loan in that currency; use
+// ConvertCash to move money between currencies.
+func WithForeignCash() Option {
+	return func(a *Account) {
+		a.holdForeignCash = true
+	}
+}
+
+// WithCurrencyHedge enables a rolling hedge of the account's
+// foreign-currency exposure.
+func WithCurrencyHedge(hedge CurrencyHedge) Option {
+	return func(a *Account) {
+		a.SetCurrencyHedge(hedge)
+	}
+}
+
+// SetCurrencyHedge enables a rolling currency hedge, replacing any hedge
+// configuration already set. Open contracts are kept and settled on
+// their normal schedule.
+func (a *Account) SetCurrencyHedge(hedge CurrencyHedge) {
+	a.currencyHedge = &hedge
+}
+
+// FXRate returns the base-currency value of one unit of cur as of the
+// last SetFXRates call. It is 1 for the base currency and NaN when no
+// rate for cur has been set. A minor unit such as GBX is valued from the
+// rate of its major currency.
+func (a *Account) FXRate(cur asset.Currency) float64 {
+	if cur == "" || cur == asset.BaseCurrency {
+		return 1
+	}
+
+	if major, units := cur.Major(); units != 1 {
+		return a.FXRate(major) / units
+	}
+
+	rate, ok := a.fxRates[cur]
+	if !ok {
+		return math.NaN()
+	}
+
+	return rate
+}
+
+// CashBalance returns the cash held in cur, in units of cur.
+func (a *Account) CashBalance(cur asset.Currency) float64 {
+	if cur == "" || cur == asset.BaseCurrency {
+		return a.cash
+	}
+
+	return a.foreignCash[cur]
+}
+
+// CashBalances returns the cash held in each currency, in units of that
+// currency. The base currency is always present.
+func (a *Account) CashBalances() map[asset.Currency]float64 {
+	balances := make(map[asset.Currency]float64, len(a.foreignCash)+1)
+	balances[asset.BaseCurrency] = a.cash
+
+	for cur, amount := range a.foreignCash {
+		balances[cur] = amount
+	}
+
+	return balances
+}
+
+// SetFXRates stores the base-currency value of one unit of each currency
+// in rates as of date, merging them into the rates already known. Rates
+// that are missing, NaN, or not positive are ignored. It then settles and
+// reopens currency hedge contracts that are due.
+func (a *Account) SetFXRates(date time.Time, rates map[asset.Currency]float64) {
+	for cur, rate := range rates {
+		if cur == asset.BaseCurrency || math.IsNaN(rate) || rate <= 0 {
+			continue
+		}
+
+		if a.fxRates == nil {
+			a.fxRates = make(map[asset.Currency]float64)
+		}
+
+		a.fxRates[cur] = rate
+	}
+
+	a.rollCurrencyHedges(date)
+}
+
+// ConvertCash exchanges amount units of from for to at the current rates,
+// recording one FXTransaction for each leg.
+func (a *Account) ConvertCash(date time.Time, from, to asset.Currency, amount float64) error {
+	fromRate := a.FXRate(from)
+	toRate := a.FXRate(to)
+
+	if math.IsNaN(fromRate) || math.IsNaN(toRate) {
+		return fmt.Errorf("convert cash: no exchange rate between %s and %s", from, to)
+	}
+
+	a.recordConversion(date, from, -amount, to, amount*fromRate/toRate)
+
+	return nil
+}
+
+// recordConversion records the two legs of a currency conversion.
+func (a *Account) recordConversion(date time.Time, from asset.Currency, fromAmount float64, to asset.Currency, toAmount float64) {
+	a.Record(Transaction{
+		Date:          date,
+		Type:          asset.FXTransaction,
+		Amount:        fromAmount,
+		Currency:      currencyCode(from),
+		Justification: CurrencyConversionJustification,
+	})
+
+	a.Record(Transaction{
+		Date:          date,
+		Type:          asset.FXTransaction,
+		Amount:        toAmount,
+		Currency:      currencyCode(to),
+		Justification: CurrencyConversionJustification,
+	})
+}
+
+// currencyCode normalizes the base currency to the empty code used on
+// transactions.
+func currencyCode(cur asset.Currency) asset.Currency {
+	if cur == asset.BaseCurrency {
+		return ""
+	}
+
+	return cur
+}
+
+// credit adds amount to the cash balance held in cur. Amounts in a minor
+// unit such as GBX are held in its major currency.
+func (a *Account) credit(cur asset.Currency, amount float64) {
+	major, units := cur.Major()
+	cur, amount = major, amount/units
+
+	if cur == "" || cur == asset.BaseCurrency {
+		a.cash += amount
+		return
+	}
+
+	if a.foreignCash == nil {
+		a.foreignCash = make(map[asset.Currency]float64)
+	}
+
+	a.foreignCash[cur] += amount
+}
+
+// foreignCashValue returns the base-currency value of all cash held in
+// foreign currencies. Balances without a rate are left out.
+func (a *Account) foreignCashValue() float64 {
+	var total float64
+
+	for cur, amount := range a.foreignCash {
+		if rate := a.FXRate(cur); !math.IsNaN(rate) {
+			total += amount * rate
+		}
+	}
+
+	return total
+}
+
+// convertForeignCashFlow converts the cash impact of a foreign-currency
+// transaction to the base currency unless the account holds foreign
+// cash. Without a rate the amount stays in the foreign currency.
+func (a *Account) convertForeignCashFlow(txn Transaction) {
+	if a.holdForeignCash || txn.Currency == "" || txn.Type == asset.FXTransaction || txn.Amount == 0 {
+		return
+	}
+
+	rate := a.FXRate(txn.Currency)
+	if math.IsNaN(rate) {
+		log.Warn().
+			Str("currency", string(txn.Currency)).
+			Float64("amount", txn.Amount).
+			Msg("portfolio: no exchange rate; keeping foreign-currency cash")
+
+		return
+	}
+
+	a.recordConversion(txn.Date, txn.Currency, -txn.Amount, asset.BaseCurrency, txn.Amount*rate)
+}
+
+// priceInBase returns the current close of ast converted to the base
+// currency, or NaN when the price or the exchange rate is unknown.
+func (a *Account) priceInBase(ast asset.Asset) float64 {
+	if a.prices == nil {
+		return math.NaN()
+	}
+
+	return a.prices.Value(ast, data.MetricClose) * a.FXRate(ast.QuoteCurrency())
+}
+
+// localizeOrder converts the dollar Amount of an order for a foreign-currency
+// asset into the asset's quote currency, which is what the broker sizes
+// the order in. Orders without a known rate are returned unchanged.
+func (a *Account) localizeOrder(order broker.Order) broker.Order {
+	if order.Amount == 0 {
+		return order
+	}
+
+	rate := a.FXRate(order.Asset.QuoteCurrency())
+	if math.IsNaN(rate) || rate == 1 {
+		return order
+	}
+
+	order.Amount /= rate
+
+	return order
+}
+
+// currencyExposure returns the account's exposure to cur in units of cur:
+// the market value of holdings quoted in cur plus cash held in it.
+func (a *Account) currencyExposure(cur asset.Currency) float64 {
+	exposure := a.foreignCash[cur]
+
+	if a.prices == nil {
+		return exposure
+	}
+
+	for ast, qty := range a.holdings {
+		// A futures position's only currency exposure is its unsettled
+		// gain or loss, which settles into cash daily.
+		major, units := ast.QuoteCurrency().Major()
+		if major != cur || ast.IsFuture() {
+			continue
+		}
+
+		if price := a.prices.Value(ast, data.MetricClose); !math.IsNaN(price) {
+			exposure += qty * ast.ContractMultiplier() * price / units
+		}
+	}
+
+	return exposure
+}
+
+// hedgeValue returns the unrealized profit and loss of the open currency
+// hedge contracts in the base currency.
+func (a *Account) hedgeValue() float64 {
+	var total float64
+
+	for cur, fwd := range a.fxForwards {
+		if rate := a.FXRate(cur); !math.IsNaN(rate) {
+			total += fwd.Notional * (fwd.Rate - rate)
+		}
+	}
+
+	return total
+}
+
+// rollCurrencyHedges settles contracts opened in an earlier month than
+// date and opens contracts for hedged currencies without one.
+func (a *Account) rollCurrencyHedges(date time.Time) {
+	for _, cur := range slices.Sorted(maps.Keys(a.fxForwards)) {
+		fwd := a.fxForwards[cur]
+		if fwd.Opened.Year() == date.Year() && fwd.Opened.Month() == date.Month() {
+			continue
+		}
+
+		rate := a.FXRate(cur)
+		if math.IsNaN(rate) {
+			continue
+		}
+
+		delete(a.fxForwards, cur)
+
+		pnl := fwd.Notional * (fwd.Rate - rate)
+		if pnl != 0 {
+			a.Record(Transaction{
+				Date:          date,
+				Type:          asset.FXTransaction,
+				Amount:        pnl,
+				Justification: fmt.Sprintf("%s %s", CurrencyHedgeJustification, cur),
+			})
+		}
+	}
+
+	hedge := a.currencyHedge
+	if hedge == nil || hedge.Ratio == 0 {
+		return
+	}
+
+	for _, cur := range slices.Sorted(maps.Keys(a.fxRates)) {
+		if _, open := a.fxForwards[cur]; open || !hedge.covers(cur) {
+			continue
+		}
+
+		notional := hedge.Ratio * a.currencyExposure(cur)
+		if notional == 0 {
+			continue
+		}
+
+		if a.fxForwards == nil {
+			a.fxForwards = make(map[asset.Currency]fxForward)
+		}
+
+		a.fxForwards[cur] = fxForward{Notional: notional, Rate: a.fxRates[cur], Opened: date}
+	}
+}
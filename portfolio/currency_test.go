@@ -0,0 +1,284 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio_test
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/portfolio"
+)
+
+var _ = Describe("Multi-currency accounts", func() {
+	var (
+		sap  asset.Asset
+		spy  asset.Asset
+		date time.Time
+		mb   *mockBroker
+	)
+
+	BeforeEach(func() {
+		sap = asset.Asset{CompositeFigi: "SAP", Ticker: "SAP", Currency: asset.CurrencyEUR}
+		spy = asset.Asset{CompositeFigi: "SPY", Ticker: "SPY"}
+		date = time.Date(2024, 6, 3, 16, 0, 0, 0, time.UTC)
+
+		// Fill dollar-amount orders at 100 a share in the asset's currency.
+		mb = newMockBroker()
+		mb.submitFn = func(order broker.Order) error {
+			qty := order.Qty
+			if qty == 0 {
+				qty = math.Floor(order.Amount / 100)
+			}
+
+			mb.fillCh <- broker.Fill{OrderID: order.ID, Price: 100, Qty: qty, FilledAt: date}
+
+			return nil
+		}
+	})
+
+	// buySAP records a purchase of 10 SAP shares at EUR 100.
+	buySAP := func(acct *portfolio.Account) {
+		acct.Record(portfolio.Transaction{
+			Date: date, Asset: sap, Type: asset.BuyTransaction, Qty: 10, Price: 100, Amount: -1_000,
+		})
+	}
+
+	markPrices := func(acct *portfolio.Account, when time.Time, sapPrice float64) {
+		acct.UpdatePrices(buildDF(when, []asset.Asset{sap, spy}, []float64{sapPrice, 50}, []float64{sapPrice, 50}))
+	}
+
+	It("reports the base currency at par and unknown currencies as NaN", func() {
+		acct := portfolio.New(portfolio.WithCash(10_000, date))
+
+		Expect(acct.FXRate(asset.CurrencyUSD)).To(Equal(1.0))
+		Expect(acct.FXRate("")).To(Equal(1.0))
+		Expect(math.IsNaN(acct.FXRate(asset.CurrencyEUR))).To(BeTrue())
+
+		acct.SetFXRates(date, map[asset.Currency]float64{asset.CurrencyEUR: 1.1, asset.CurrencyGBP: math.NaN()})
+		Expect(acct.FXRate(asset.CurrencyEUR)).To(Equal(1.1))
+		Expect(math.IsNaN(acct.FXRate(asset.CurrencyGBP))).To(BeTrue())
+	})
+
+	It("settles foreign-currency trades in the base currency by default", func() {
+		acct := portfolio.New(portfolio.WithCash(10_000, date))
+		acct.SetFXRates(date, map[asset.Currency]float64{asset.CurrencyEUR: 1.1})
+
+		buySAP(acct)
+
+		txns := acct.Transactions()
+		Expect(txns).To(HaveLen(4))
+		Expect(txns[1].Currency).To(Equal(asset.CurrencyEUR))
+		Expect(txns[2].Type).To(Equal(asset.FXTransaction))
+		Expect(txns[2].Currency).To(Equal(asset.CurrencyEUR))
+		Expect(txns[2].Amount).To(Equal(1_000.0))
+		Expect(txns[3].Type).To(Equal(asset.FXTransaction))
+		Expect(txns[3].Currency).To(BeEmpty())
+		Expect(txns[3].Amount).To(BeNumerically("~", -1_100, 1e-9))
+
+		Expect(acct.CashBalance(asset.CurrencyEUR)).To(Equal(0.0))
+		Expect(acct.Cash()).To(BeNumerically("~", 8_900, 1e-9))
+	})
+
+	It("measures tax lots and realized gains in the base currency", func() {
+		acct := portfolio.New(portfolio.WithCash(10_000, date))
+		acct.SetFXRates(date, map[asset.Currency]float64{asset.CurrencyEUR: 1.1})
+
+		buySAP(acct)
+
+		lots := acct.TaxLots()[sap]
+		Expect(lots).To(HaveLen(1))
+		Expect(lots[0].Price).To(Equal(100.0))
+		Expect(lots[0].FXRate).To(Equal(1.1))
+		Expect(lots[0].BasePrice()).To(BeNumerically("~", 110, 1e-9))
+
+		// Sold at the purchase price in euros after the euro rose: the
+		// whole gain is currency.
+		later := date.AddDate(0, 1, 0)
+		acct.SetFXRates(later, map[asset.Currency]float64{asset.CurrencyEUR: 1.2})
+		acct.Record(portfolio.Transaction{
+			Date: later, Asset: sap, Type: asset.SellTransaction, Qty: 10, Price: 100, Amount: 1_000,
+		})
+
+		details := acct.TradeDetails()
+		Expect(details).To(HaveLen(1))
+		Expect(details[0].PnL).To(BeNumerically("~", 100, 1e-9))
+
+		_, stcg := acct.RealizedGainsYTD()
+		Expect(stcg).To(BeNumerically("~", 100, 1e-9))
+	})
+
+	It("values foreign holdings at the current exchange rate", func() {
+		acct := portfolio.New(portfolio.WithCash(10_000, date))
+		acct.SetFXRates(date, map[asset.Currency]float64{asset.CurrencyEUR: 1.1})
+		buySAP(acct)
+
+		markPrices(acct, date, 100)
+		Expect(acct.Value()).To(BeNumerically("~", 10_000, 1e-9))
+		Expect(acct.PositionValue(sap)).To(BeNumerically("~", 1_100, 1e-9))
+
+		// The euro strengthens while SAP's euro price is unchanged: the
+		// gain is pure currency profit.
+		next := date.AddDate(0, 0, 1)
+		acct.SetFXRates(next, map[asset.Currency]float64{asset.CurrencyEUR: 1.2})
+		markPrices(acct, next, 100)
+
+		Expect(acct.Value()).To(BeNumerically("~", 10_100, 1e-9))
+		equity := acct.PerfData().Column(perfAsset, data.PortfolioEquity)
+		Expect(equity).To(HaveLen(2))
+		Expect(equity[1]).To(BeNumerically("~", 10_100, 1e-9))
+	})
+
+	It("values pence-quoted holdings and cash in pounds", func() {
+		vod := asset.Asset{CompositeFigi: "VOD", Ticker: "VOD", Currency: asset.CurrencyGBX}
+
+		acct := portfolio.New(portfolio.WithCash(10_000, date), portfolio.WithForeignCash())
+		acct.SetFXRates(date, map[asset.Currency]float64{asset.CurrencyGBP: 1.25})
+		Expect(acct.FXRate(asset.CurrencyGBX)).To(Equal(0.0125))
+
+		acct.Record(portfolio.Transaction{
+			Date: date, Asset: vod, Type: asset.BuyTransaction, Qty: 1_000, Price: 70, Amount: -70_000,
+		})
+
+		Expect(acct.CashBalances()).To(Equal(map[asset.Currency]float64{
+			asset.CurrencyUSD: 10_000,
+			asset.CurrencyGBP: -700,
+		}))
+
+		acct.UpdatePrices(buildDF(date, []asset.Asset{vod}, []float64{72}, []float64{72}))
+		Expect(acct.PositionValue(vod)).To(BeNumerically("~", 900, 1e-9))
+		Expect(acct.Value()).To(BeNumerically("~", 10_025, 1e-9))
+	})
+
+	It("keeps foreign cash when configured", func() {
+		acct := portfolio.New(portfolio.WithCash(10_000, date), portfolio.WithForeignCash())
+		acct.SetFXRates(date, map[asset.Currency]float64{asset.CurrencyEUR: 1.1})
+		buySAP(acct)
+
+		Expect(acct.Transactions()).To(HaveLen(2))
+		Expect(acct.CashBalances()).To(Equal(map[asset.Currency]float64{
+			asset.CurrencyUSD: 10_000,
+			asset.CurrencyEUR: -1_000,
+		}))
+		Expect(acct.Cash()).To(BeNumerically("~", 8_900, 1e-9))
+
+		Expect(acct.ConvertCash(date, asset.CurrencyUSD, asset.CurrencyEUR, 2_200)).To(Succeed())
+		Expect(acct.CashBalance(asset.CurrencyUSD)).To(BeNumerically("~", 7_800, 1e-9))
+		Expect(acct.CashBalance(asset.CurrencyEUR)).To(BeNumerically("~", 1_000, 1e-9))
+
+		Expect(acct.ConvertCash(date, asset.CurrencyUSD, asset.CurrencyGBP, 100)).
+			To(MatchError(ContainSubstring("no exchange rate")))
+	})
+
+	It("sizes dollar-amount orders in the asset's currency", func() {
+		acct := portfolio.New(portfolio.WithCash(10_000, date), portfolio.WithBroker(mb))
+		acct.SetFXRates(date, map[asset.Currency]float64{asset.CurrencyEUR: 1.25})
+		markPrices(acct, date, 100)
+
+		batch := acct.NewBatch(date)
+		Expect(batch.Allocate(context.Background(), sap, 0.5)).To(Succeed())
+		Expect(acct.ExecuteBatch(context.Background(), batch)).To(Succeed())
+
+		Expect(mb.submitted).To(HaveLen(1))
+		Expect(mb.submitted[0].Amount).To(BeNumerically("~", 4_000, 1e-9))
+		Expect(acct.Position(sap)).To(Equal(40.0))
+		Expect(acct.Cash()).To(BeNumerically("~", 5_000, 1e-9))
+	})
+
+	It("hedges currency exposure with monthly forwards", func() {
+		acct := portfolio.New(portfolio.WithCash(10_000, date),
+			portfolio.WithCurrencyHedge(portfolio.CurrencyHedge{Ratio: 1}))
+		acct.SetFXRates(date, map[asset.Currency]float64{asset.CurrencyEUR: 1.1})
+		buySAP(acct)
+		markPrices(acct, date, 100)
+
+		// The hedge opens on the next rate update, once there is exposure.
+		next := date.AddDate(0, 0, 1)
+		acct.SetFXRates(next, map[asset.Currency]float64{asset.CurrencyEUR: 1.1})
+		markPrices(acct, next, 100)
+
+		later := date.AddDate(0, 0, 10)
+		acct.SetFXRates(later, map[asset.Currency]float64{asset.CurrencyEUR: 1.3})
+		markPrices(acct, later, 100)
+		Expect(acct.Value()).To(BeNumerically("~", 10_000, 1e-9))
+
+		// The first update in July settles June's contract.
+		july := time.Date(2024, 7, 1, 16, 0, 0, 0, time.UTC)
+		acct.SetFXRates(july, map[asset.Currency]float64{asset.CurrencyEUR: 1.3})
+
+		txns := acct.Transactions()
+		settlement := txns[len(txns)-1]
+		Expect(settlement.Type).To(Equal(asset.FXTransaction))
+		Expect(settlement.Justification).To(Equal("currency hedge settlement EUR"))
+		Expect(settlement.Amount).To(BeNumerically("~", -200, 1e-9))
+
+		markPrices(acct, july, 100)
+		Expect(acct.Value()).To(BeNumerically("~", 10_000, 1e-9))
+		Expect(acct.Cash()).To(BeNumerically("~", 8_700, 1e-9))
+	})
+
+	It("hedges only part of the exposure when the ratio is below one", func() {
+		acct := portfolio.New(portfolio.WithCash(10_000, date),
+			portfolio.WithCurrencyHedge(portfolio.CurrencyHedge{Ratio: 0.5}))
+		acct.SetFXRates(date, map[asset.Currency]float64{asset.CurrencyEUR: 1.1})
+		buySAP(acct)
+		markPrices(acct, date, 100)
+
+		next := date.AddDate(0, 0, 1)
+		acct.SetFXRates(next, map[asset.Currency]float64{asset.CurrencyEUR: 1.1})
+
+		later := date.AddDate(0, 0, 10)
+		acct.SetFXRates(later, map[asset.Currency]float64{asset.CurrencyEUR: 1.3})
+		markPrices(acct, later, 100)
+
+		Expect(acct.Value()).To(BeNumerically("~", 10_100, 1e-9))
+	})
+
+	It("round-trips foreign cash, currencies, and open hedges through SQLite", func() {
+		acct := portfolio.New(portfolio.WithCash(10_000, date),
+			portfolio.WithCurrencyHedge(portfolio.CurrencyHedge{Ratio: 1}))
+		acct.SetFXRates(date, map[asset.Currency]float64{asset.CurrencyEUR: 1.1})
+		buySAP(acct)
+		Expect(acct.ConvertCash(date, asset.CurrencyUSD, asset.CurrencyEUR, 1_100)).To(Succeed())
+		markPrices(acct, date, 100)
+		acct.SetFXRates(date.AddDate(0, 0, 1), map[asset.Currency]float64{asset.CurrencyEUR: 1.1})
+
+		path := filepath.Join(GinkgoT().TempDir(), "fx.db")
+		Expect(acct.ToSQLite(path)).To(Succeed())
+
+		restored, err := portfolio.FromSQLite(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restored.Position(sap)).To(Equal(10.0))
+		Expect(restored.CashBalances()).To(Equal(acct.CashBalances()))
+		Expect(restored.Transactions()[1].Currency).To(Equal(asset.CurrencyEUR))
+		Expect(restored.Transactions()[1].FXRate).To(Equal(1.1))
+		Expect(restored.TaxLots()[sap]).To(HaveLen(1))
+		Expect(restored.TaxLots()[sap][0].FXRate).To(Equal(1.1))
+
+		// The restored hedge covers EUR 2,000 and settles against the
+		// contract rate.
+		restored.SetFXRates(time.Date(2024, 7, 1, 16, 0, 0, 0, time.UTC), map[asset.Currency]float64{asset.CurrencyEUR: 1.2})
+		txns := restored.Transactions()
+		Expect(txns[len(txns)-1].Amount).To(BeNumerically("~", -200, 1e-9))
+	})
+})
@@ -0,0 +1,109 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/rs/zerolog/log"
+)
+
+// DividendReinvestmentJustification is the Justification recorded on the
+// buy transactions placed by dividend reinvestment.
+const DividendReinvestmentJustification = "dividend reinvestment"
+
+// DividendReinvestment configures a dividend reinvestment plan (DRIP).
+// When a cash dividend on a long position is synced from the broker, the
+// account immediately submits a market buy of the paying asset for the
+// dividend amount. The buy goes through the broker like any other order,
+// so the fill model and commissions apply and the shares open a new tax
+// lot. In backtests the simulated broker credits dividends on the
+// ex-date, so they are reinvested at that day's fill price; live brokers
+// report dividends when they are paid.
+type DividendReinvestment struct {
+	// Fractional buys fractional shares so the entire dividend is
+	// reinvested. When false, whole shares are bought and the remainder
+	// stays in cash.
+	Fractional bool
+
+	// Assets limits reinvestment to dividends paid by these assets. An
+	// empty list reinvests dividends from every holding.
+	Assets []asset.Asset
+}
+
+// covers reports whether dividends paid by ast are reinvested.
+func (drip DividendReinvestment) covers(ast asset.Asset) bool {
+	return len(drip.Assets) == 0 || slices.Contains(drip.Assets, ast)
+}
+
+// WithDividendReinvestment enables a dividend reinvestment plan for the
+// account.
+func WithDividendReinvestment(drip DividendReinvestment) Option {
+	return func(a *Account) {
+		a.SetDividendReinvestment(drip)
+	}
+}
+
+// SetDividendReinvestment enables a dividend reinvestment plan, replacing
+// any plan already configured.
+func (a *Account) SetDividendReinvestment(drip DividendReinvestment) {
+	a.dividendReinvestment = &drip
+}
+
+// reinvestDividend buys the paying asset with a dividend just recorded,
+// when the account's reinvestment plan covers it. Short dividend
+// obligations and dividends on assets no longer held are left as cash. A
+// reinvestment the broker rejects is logged and the dividend stays in
+// cash.
+func (a *Account) reinvestDividend(dividend Transaction) {
+	drip := a.dividendReinvestment
+	if drip == nil || a.broker == nil || dividend.Amount <= 0 || !drip.covers(dividend.Asset) {
+		return
+	}
+
+	if a.holdings[dividend.Asset] <= 0 {
+		return
+	}
+
+	// Dividends are paid in the asset's quote currency; order amounts are
+	// in the base currency.
+	amount := dividend.Amount * a.FXRate(dividend.Asset.QuoteCurrency())
+	if math.IsNaN(amount) {
+		return
+	}
+
+	order := broker.Order{
+		ID:          fmt.Sprintf("drip-%s-%s", dividend.Asset.CompositeFigi, dividend.Date.Format("2006-01-02")),
+		Asset:       dividend.Asset,
+		Side:        broker.Buy,
+		Amount:      amount,
+		OrderType:   broker.Market,
+		TimeInForce: broker.Day,
+		Fractional:  drip.Fractional,
+	}
+
+	if err := a.submitAndRecord(context.Background(), dividend.Asset, order, DividendReinvestmentJustification); err != nil {
+		log.Warn().Err(err).
+			Str("ticker", dividend.Asset.Ticker).
+			Float64("amount", dividend.Amount).
+			Msg("dividend reinvestment order rejected; dividend kept as cash")
+	}
+}
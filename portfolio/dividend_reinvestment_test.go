@@ -0,0 +1,163 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/portfolio"
+)
+
+var _ = Describe("DividendReinvestment", func() {
+	var (
+		spy     asset.Asset
+		qqq     asset.Asset
+		date    time.Time
+		divDate time.Time
+		mb      *mockBroker
+	)
+
+	BeforeEach(func() {
+		spy = asset.Asset{CompositeFigi: "SPY", Ticker: "SPY"}
+		qqq = asset.Asset{CompositeFigi: "QQQ", Ticker: "QQQ"}
+		date = time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+		divDate = time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+		// Fill dollar-amount orders at $100 a share, fractionally when asked.
+		mb = newMockBroker()
+		mb.submitFn = func(order broker.Order) error {
+			qty := order.Amount / 100
+			if !order.Fractional {
+				qty = float64(int(qty))
+			}
+
+			mb.fillCh <- broker.Fill{OrderID: order.ID, Price: 100, Qty: qty, FilledAt: divDate}
+
+			return nil
+		}
+	})
+
+	heldAccount := func(opts ...portfolio.Option) *portfolio.Account {
+		opts = append([]portfolio.Option{portfolio.WithCash(50_000, date), portfolio.WithBroker(mb)}, opts...)
+		acct := portfolio.New(opts...)
+
+		for _, ast := range []asset.Asset{spy, qqq} {
+			acct.Record(portfolio.Transaction{
+				Date: date, Asset: ast, Type: asset.BuyTransaction, Qty: 100, Price: 100, Amount: -10_000,
+			})
+		}
+
+		return acct
+	}
+
+	dividend := func(ast asset.Asset, amount float64) broker.Transaction {
+		return broker.Transaction{
+			ID:     "div-" + ast.Ticker,
+			Date:   divDate,
+			Asset:  ast,
+			Type:   asset.DividendTransaction,
+			Qty:    100,
+			Price:  amount / 100,
+			Amount: amount,
+		}
+	}
+
+	It("buys fractional shares of the paying asset with the whole dividend", func() {
+		acct := heldAccount(portfolio.WithDividendReinvestment(portfolio.DividendReinvestment{Fractional: true}))
+
+		Expect(acct.SyncTransactions([]broker.Transaction{dividend(spy, 150)})).To(Succeed())
+
+		Expect(mb.submitted).To(HaveLen(1))
+		Expect(mb.submitted[0].Side).To(Equal(broker.Buy))
+		Expect(mb.submitted[0].Amount).To(Equal(150.0))
+		Expect(mb.submitted[0].Fractional).To(BeTrue())
+
+		Expect(acct.Position(spy)).To(BeNumerically("~", 101.5, 1e-9))
+		Expect(acct.Cash()).To(BeNumerically("~", 30_000, 1e-9))
+
+		lots := acct.UnrealizedLots(spy)
+		Expect(lots).To(HaveLen(2))
+		Expect(lots[1].Qty).To(BeNumerically("~", 1.5, 1e-9))
+		Expect(lots[1].Date).To(Equal(divDate))
+
+		txns := acct.Transactions()
+		Expect(txns[len(txns)-1].Justification).To(Equal(portfolio.DividendReinvestmentJustification))
+	})
+
+	It("buys whole shares and keeps the remainder as cash", func() {
+		acct := heldAccount(portfolio.WithDividendReinvestment(portfolio.DividendReinvestment{}))
+
+		Expect(acct.SyncTransactions([]broker.Transaction{dividend(spy, 250)})).To(Succeed())
+
+		Expect(acct.Position(spy)).To(Equal(102.0))
+		Expect(acct.Cash()).To(BeNumerically("~", 30_050, 1e-9))
+	})
+
+	It("reinvests only the listed assets", func() {
+		acct := heldAccount(portfolio.WithDividendReinvestment(portfolio.DividendReinvestment{
+			Fractional: true,
+			Assets:     []asset.Asset{qqq},
+		}))
+
+		Expect(acct.SyncTransactions([]broker.Transaction{dividend(spy, 150), dividend(qqq, 80)})).To(Succeed())
+
+		Expect(mb.submitted).To(HaveLen(1))
+		Expect(mb.submitted[0].Asset).To(Equal(qqq))
+		Expect(acct.Position(spy)).To(Equal(100.0))
+		Expect(acct.Position(qqq)).To(BeNumerically("~", 100.8, 1e-9))
+	})
+
+	It("does not reinvest without a plan, for short obligations, or twice for the same dividend", func() {
+		plain := heldAccount()
+		Expect(plain.SyncTransactions([]broker.Transaction{dividend(spy, 150)})).To(Succeed())
+		Expect(mb.submitted).To(BeEmpty())
+
+		acct := heldAccount(portfolio.WithDividendReinvestment(portfolio.DividendReinvestment{Fractional: true}))
+		Expect(acct.SyncTransactions([]broker.Transaction{dividend(spy, -150)})).To(Succeed())
+		Expect(mb.submitted).To(BeEmpty())
+
+		Expect(acct.SyncTransactions([]broker.Transaction{dividend(qqq, 100)})).To(Succeed())
+		Expect(acct.SyncTransactions([]broker.Transaction{dividend(qqq, 100)})).To(Succeed())
+		Expect(mb.submitted).To(HaveLen(1))
+	})
+
+	It("carries the plan into clones", func() {
+		acct := heldAccount(portfolio.WithDividendReinvestment(portfolio.DividendReinvestment{Fractional: true}))
+		clone := acct.Clone().(*portfolio.Account)
+
+		Expect(clone.SyncTransactions([]broker.Transaction{dividend(spy, 150)})).To(Succeed())
+
+		Expect(mb.submitted).To(HaveLen(1))
+		Expect(clone.Position(spy)).To(BeNumerically("~", 101.5, 1e-9))
+		Expect(acct.Position(spy)).To(Equal(100.0))
+	})
+
+	It("keeps the dividend as cash when the broker rejects the order", func() {
+		mb.submitErr = errors.New("market closed")
+		acct := heldAccount(portfolio.WithDividendReinvestment(portfolio.DividendReinvestment{Fractional: true}))
+
+		Expect(acct.SyncTransactions([]broker.Transaction{dividend(spy, 150)})).To(Succeed())
+
+		Expect(acct.Position(spy)).To(Equal(100.0))
+		Expect(acct.Cash()).To(BeNumerically("~", 30_150, 1e-9))
+	})
+})
@@ -204,6 +204,14 @@
 //   - [asset.FeeTransaction]: fee or commission charged.
 //   - [asset.DepositTransaction]: cash added to the portfolio.
 //   - [asset.WithdrawalTransaction]: cash removed from the portfolio.
+//   - [asset.FXTransaction]: one leg of a currency conversion, or the
+//     settlement of a currency hedge.
+//   - [asset.VariationMarginTransaction]: the daily cash settlement of a
+//     futures position.
+//
+// Option exercise, assignment, and expiry are recorded as buys and sells
+// justified by [OptionExerciseJustification],
+// [OptionAssignmentJustification], and [OptionExpiryJustification].
 //
 // The Qualified flag on a [Transaction] indicates whether a dividend meets
 // the IRS 60-day holding period requirement for preferential tax rates.
@@ -212,7 +220,17 @@
 //
 // The Amount field represents the total cash impact of the transaction.
 // Positive values are cash inflows (sells, dividends, deposits); negative
-// values are cash outflows (buys, fees, withdrawals).
+// values are cash outflows (buys, fees, withdrawals). The Currency field
+// names the currency Amount is in; it is empty for US dollars.
+//
+// # Multiple Currencies
+//
+// The account keeps its books in US dollars. Assets whose Currency is set
+// are priced and traded in that currency and valued in dollars at the
+// rates passed to [Account.SetFXRates]. Foreign-currency cash flows are
+// converted to dollars as they are recorded unless the account was built
+// with [WithForeignCash]; [WithCurrencyHedge] hedges the currency exposure
+// with monthly rolling forwards.
 //
 // # Risk Controls
 //
@@ -330,7 +348,7 @@
 //   - [Portfolio.TradeMetrics]: WinRate, AverageWin, AverageLoss,
 //     ProfitFactor, AverageHoldingPeriod, Turnover, NPositivePeriods,
 //     GainLossRatio, AverageMFE, AverageMAE, MedianMFE, MedianMAE,
-//     EdgeRatio, TradeCaptureRatio.
+//     EdgeRatio, TradeCaptureRatio, SkippedTrades, SkippedNotional.
 //   - [Portfolio.WithdrawalMetrics]: SafeWithdrawalRate,
 //     PerpetualWithdrawalRate, DynamicWithdrawalRate.
 //
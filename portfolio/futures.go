@@ -0,0 +1,177 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
+)
+
+// FuturesExpiryJustification is recorded on the trade that closes a
+// futures position at its final settlement price on the last trading day.
+const FuturesExpiryJustification = "futures expiry"
+
+// SettleFutures settles every futures position to the current close,
+// recording the gain or loss since the previous settlement as a
+// VariationMarginTransaction. Positions in contracts whose last trading
+// day is on or before date are then closed at that price. Positions
+// without a close price are left unsettled until the next call.
+func (a *Account) SettleFutures(date time.Time) {
+	if a.prices == nil {
+		return
+	}
+
+	var futures []asset.Asset
+
+	for ast := range a.holdings {
+		if ast.IsFuture() {
+			futures = append(futures, ast)
+		}
+	}
+
+	slices.SortFunc(futures, func(left, right asset.Asset) int {
+		return strings.Compare(left.Ticker, right.Ticker)
+	})
+
+	for _, ast := range futures {
+		price := a.prices.Value(ast, data.MetricClose)
+		if math.IsNaN(price) {
+			continue
+		}
+
+		a.settleFuture(date, ast, price)
+
+		if !ast.Contract.ExpiresBy(date) {
+			continue
+		}
+
+		qty := a.holdings[ast]
+
+		txnType := asset.SellTransaction
+		if qty < 0 {
+			txnType = asset.BuyTransaction
+		}
+
+		a.Record(Transaction{
+			Date:          date,
+			Asset:         ast,
+			Type:          txnType,
+			Qty:           math.Abs(qty),
+			Price:         price,
+			Justification: FuturesExpiryJustification,
+		})
+	}
+}
+
+// settleFuture records the variation margin on the position in ast from
+// its last settlement price to price and makes price the new settlement
+// price.
+func (a *Account) settleFuture(date time.Time, ast asset.Asset, price float64) {
+	qty := a.holdings[ast]
+
+	if mark, ok := a.futuresMarks[ast]; ok && qty != 0 && price != mark {
+		a.Record(Transaction{
+			Date:   date,
+			Asset:  ast,
+			Type:   asset.VariationMarginTransaction,
+			Qty:    qty,
+			Price:  price,
+			Amount: qty * ast.ContractMultiplier() * (price - mark),
+		})
+	}
+
+	if a.futuresMarks == nil {
+		a.futuresMarks = make(map[asset.Asset]float64)
+	}
+
+	a.futuresMarks[ast] = price
+}
+
+// futuresPnL returns the gain or loss on qty contracts of ast since their
+// last settlement, in the base currency. It is zero when the price or
+// exchange rate is unknown.
+func (a *Account) futuresPnL(ast asset.Asset, qty float64) float64 {
+	mark, ok := a.futuresMarks[ast]
+	if !ok {
+		return 0
+	}
+
+	price := a.priceInBase(ast)
+	rate := a.FXRate(ast.QuoteCurrency())
+
+	if math.IsNaN(price) || math.IsNaN(rate) {
+		return 0
+	}
+
+	return qty * ast.ContractMultiplier() * (price - mark*rate)
+}
+
+// marketValue returns what qty units of ast contribute to the account's
+// value in the base currency: quantity times price (and an option's
+// multiplier) for securities, and the unsettled gain or loss for futures,
+// whose notional is never paid. It is NaN when a security's price is
+// unknown.
+func (a *Account) marketValue(ast asset.Asset, qty float64) float64 {
+	if ast.IsFuture() {
+		return a.futuresPnL(ast, qty)
+	}
+
+	return qty * ast.ContractMultiplier() * a.priceInBase(ast)
+}
+
+// FuturesNotional returns the total absolute notional value of the
+// account's futures positions in the base currency: contracts times
+// multiplier times price.
+func (a *Account) FuturesNotional() float64 {
+	var total float64
+
+	for ast, qty := range a.holdings {
+		if !ast.IsFuture() {
+			continue
+		}
+
+		if price := a.priceInBase(ast); !math.IsNaN(price) {
+			total += math.Abs(qty) * ast.ContractMultiplier() * price
+		}
+	}
+
+	return total
+}
+
+// FuturesMargin returns the initial and maintenance margin required by
+// the account's futures positions in the base currency.
+func (a *Account) FuturesMargin() (initial, maintenance float64) {
+	for ast, qty := range a.holdings {
+		if !ast.IsFuture() {
+			continue
+		}
+
+		rate := a.FXRate(ast.QuoteCurrency())
+		if math.IsNaN(rate) {
+			continue
+		}
+
+		initial += math.Abs(qty) * ast.Contract.InitialMargin * rate
+		maintenance += math.Abs(qty) * ast.Contract.MaintenanceRequirement() * rate
+	}
+
+	return initial, maintenance
+}
@@ -0,0 +1,189 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio_test
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/portfolio"
+)
+
+var _ = Describe("Futures", func() {
+	var (
+		es   asset.Asset
+		date time.Time
+	)
+
+	BeforeEach(func() {
+		es = asset.NewFuture("ESM24", asset.Contract{
+			Root:              "ES",
+			Multiplier:        50,
+			TickSize:          0.25,
+			Expiry:            time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC),
+			InitialMargin:     12_000,
+			MaintenanceMargin: 11_000,
+		})
+		date = time.Date(2024, 6, 3, 16, 0, 0, 0, time.UTC)
+	})
+
+	// settle marks es at price on when the way the engine does at the close.
+	settle := func(acct *portfolio.Account, when time.Time, price float64) {
+		df := buildDF(when, []asset.Asset{es}, []float64{price}, []float64{price})
+		acct.SetPrices(df)
+		acct.SettleFutures(when)
+		acct.UpdatePrices(df)
+	}
+
+	buyES := func(acct *portfolio.Account, qty, price float64) {
+		acct.Record(portfolio.Transaction{
+			Date: date, Asset: es, Type: asset.BuyTransaction, Qty: qty, Price: price,
+		})
+	}
+
+	It("opens positions without debiting notional", func() {
+		acct := portfolio.New(portfolio.WithCash(100_000, date))
+		buyES(acct, 2, 5_000)
+
+		Expect(acct.Position(es)).To(Equal(2.0))
+		Expect(acct.Cash()).To(Equal(100_000.0))
+		Expect(acct.Transactions()[1].Amount).To(Equal(0.0))
+
+		settle(acct, date, 5_000)
+		Expect(acct.Value()).To(Equal(100_000.0))
+		Expect(acct.FuturesNotional()).To(Equal(500_000.0))
+	})
+
+	It("settles daily gains and losses as variation margin", func() {
+		acct := portfolio.New(portfolio.WithCash(100_000, date))
+		buyES(acct, 2, 5_000)
+		settle(acct, date, 5_000)
+
+		next := date.AddDate(0, 0, 1)
+		settle(acct, next, 5_010)
+
+		txns := acct.Transactions()
+		Expect(txns[len(txns)-1].Type).To(Equal(asset.VariationMarginTransaction))
+		Expect(txns[len(txns)-1].Amount).To(Equal(1_000.0))
+		Expect(acct.Cash()).To(Equal(101_000.0))
+		Expect(acct.Value()).To(Equal(101_000.0))
+
+		settle(acct, next.AddDate(0, 0, 1), 4_990)
+		Expect(acct.Cash()).To(Equal(99_000.0))
+	})
+
+	It("settles to the fill price when a position changes", func() {
+		acct := portfolio.New(portfolio.WithCash(100_000, date))
+		buyES(acct, 2, 5_000)
+		acct.Record(portfolio.Transaction{
+			Date: date, Asset: es, Type: asset.SellTransaction, Qty: 2, Price: 5_020,
+		})
+
+		Expect(acct.Position(es)).To(Equal(0.0))
+		Expect(acct.Cash()).To(Equal(102_000.0))
+
+		details := acct.TradeDetails()
+		Expect(details).To(HaveLen(1))
+		Expect(details[0].PnL).To(Equal(2_000.0))
+	})
+
+	It("rebalances a held position by its notional exposure", func() {
+		acct := portfolio.New(portfolio.WithCash(100_000, date))
+		buyES(acct, 2, 5_000)
+		settle(acct, date, 5_000)
+
+		// Mark without settling: the position carries a $1,000 gain.
+		acct.UpdatePrices(buildDF(date, []asset.Asset{es}, []float64{5_010}, []float64{5_010}))
+		Expect(acct.Value()).To(Equal(101_000.0))
+
+		// Target 2x exposure: $202,000 of a $501,000 position.
+		batch := acct.NewBatch(date)
+		Expect(batch.RebalanceTo(context.Background(), portfolio.Allocation{
+			Date:    date,
+			Members: map[asset.Asset]float64{es: 2},
+		})).To(Succeed())
+
+		Expect(batch.Orders).To(HaveLen(1))
+		Expect(batch.Orders[0].Side).To(Equal(broker.Sell))
+		Expect(batch.Orders[0].Amount).To(BeNumerically("~", 299_000, 1e-6))
+
+		// One contract is sold and no cash changes hands.
+		Expect(batch.ProjectedHoldings()[es]).To(Equal(1.0))
+		Expect(batch.ProjectedValue()).To(BeNumerically("~", 101_000, 1e-6))
+	})
+
+	It("closes positions on the last trading day", func() {
+		acct := portfolio.New(portfolio.WithCash(100_000, date))
+		buyES(acct, 1, 5_000)
+		settle(acct, date, 5_000)
+
+		expiry := time.Date(2024, 6, 21, 16, 0, 0, 0, time.UTC)
+		settle(acct, expiry, 5_100)
+
+		Expect(acct.Position(es)).To(Equal(0.0))
+		Expect(acct.Cash()).To(Equal(105_000.0))
+
+		txns := acct.Transactions()
+		Expect(txns[len(txns)-1].Type).To(Equal(asset.SellTransaction))
+		Expect(txns[len(txns)-1].Justification).To(Equal(portfolio.FuturesExpiryJustification))
+	})
+
+	It("requires per-contract margin and flags maintenance breaches", func() {
+		acct := portfolio.New(portfolio.WithCash(30_000, date))
+		buyES(acct, 2, 5_000)
+		settle(acct, date, 5_000)
+
+		initial, maintenance := acct.FuturesMargin()
+		Expect(initial).To(Equal(24_000.0))
+		Expect(maintenance).To(Equal(22_000.0))
+		Expect(acct.MarginDeficiency()).To(Equal(0.0))
+		Expect(acct.BuyingPower()).To(Equal(6_000.0))
+
+		// A 100-point drop costs 10,000, leaving 20,000 against 22,000
+		// maintenance.
+		settle(acct, date.AddDate(0, 0, 1), 4_900)
+		Expect(acct.Equity()).To(Equal(20_000.0))
+		Expect(acct.MarginDeficiency()).To(BeNumerically(">", 0))
+	})
+
+	It("round-trips contract specs and settlement marks through SQLite", func() {
+		acct := portfolio.New(portfolio.WithCash(100_000, date))
+		buyES(acct, 2, 5_000)
+		settle(acct, date, 5_010)
+
+		path := filepath.Join(GinkgoT().TempDir(), "futures.db")
+		Expect(acct.ToSQLite(path)).To(Succeed())
+
+		restored, err := portfolio.FromSQLite(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restored.Position(es)).To(Equal(2.0))
+		Expect(restored.Cash()).To(Equal(acct.Cash()))
+
+		for held := range restored.Holdings() {
+			Expect(held.Contract).To(Equal(es.Contract))
+		}
+
+		// Settlement resumes from the saved mark.
+		settle(restored, date.AddDate(0, 0, 1), 5_020)
+		Expect(restored.Cash()).To(Equal(acct.Cash() + 1_000))
+	})
+})
@@ -42,10 +42,20 @@ func sortLotsByDateAsc(lots []TaxLot) {
 // sortLotsByPriceDesc sorts lots in descending price order (highest cost first).
 func sortLotsByPriceDesc(lots []TaxLot) {
 	sort.SliceStable(lots, func(i, j int) bool {
-		return lots[i].Price > lots[j].Price
+		return lots[i].BasePrice() > lots[j].BasePrice()
 	})
 }
 
+// lotQty returns the total quantity held across lots.
+func lotQty(lots []TaxLot) float64 {
+	total := 0.0
+	for _, lot := range lots {
+		total += lot.Qty
+	}
+
+	return total
+}
+
 // lotsInConsumptionOrder returns a copy of lots ordered the way the given
 // lot-selection method consumes them: FIFO front-first, LIFO back-first,
 // HighestCost by descending price. Callers use it to attribute per-lot
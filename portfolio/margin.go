@@ -15,11 +15,7 @@
 
 package portfolio
 
-import (
-	"math"
-
-	"github.com/penny-vault/pvbt/data"
-)
+import "math"
 
 const (
 	defaultInitialMarginRate        = 0.50
@@ -29,7 +25,8 @@ const (
 )
 
 // ShortMarketValue returns the total absolute market value of all short
-// positions (negative holdings). Returns 0 if prices have not been set.
+// positions (negative holdings). Futures are excluded; see
+// FuturesNotional. Returns 0 if prices have not been set.
 func (a *Account) ShortMarketValue() float64 {
 	if a.prices == nil {
 		return 0
@@ -38,10 +35,10 @@ func (a *Account) ShortMarketValue() float64 {
 	var total float64
 
 	for ast, qty := range a.holdings {
-		if qty < 0 {
-			price := a.prices.Value(ast, data.MetricClose)
+		if qty < 0 && !ast.IsFuture() {
+			price := a.priceInBase(ast)
 			if !math.IsNaN(price) {
-				total += math.Abs(qty) * price
+				total += math.Abs(qty) * ast.ContractMultiplier() * price
 			}
 		}
 	}
@@ -50,7 +47,8 @@ func (a *Account) ShortMarketValue() float64 {
 }
 
 // LongMarketValue returns the total market value of all long positions
-// (positive holdings). Returns 0 if prices have not been set.
+// (positive holdings). Futures are excluded; see FuturesNotional. Returns
+// 0 if prices have not been set.
 func (a *Account) LongMarketValue() float64 {
 	if a.prices == nil {
 		return 0
@@ -59,10 +57,10 @@ func (a *Account) LongMarketValue() float64 {
 	var total float64
 
 	for ast, qty := range a.holdings {
-		if qty > 0 {
-			price := a.prices.Value(ast, data.MetricClose)
+		if qty > 0 && !ast.IsFuture() {
+			price := a.priceInBase(ast)
 			if !math.IsNaN(price) {
-				total += qty * price
+				total += qty * ast.ContractMultiplier() * price
 			}
 		}
 	}
@@ -70,9 +68,19 @@ func (a *Account) LongMarketValue() float64 {
 	return total
 }
 
-// Equity returns cash plus long market value minus short market value.
+// Equity returns cash plus long market value minus short market value,
+// including the unrealized profit and loss of any currency hedge and the
+// unsettled profit and loss of futures positions.
 func (a *Account) Equity() float64 {
-	return a.cash + a.LongMarketValue() - a.ShortMarketValue()
+	equity := a.Cash() + a.hedgeValue() + a.LongMarketValue() - a.ShortMarketValue()
+
+	for ast, qty := range a.holdings {
+		if ast.IsFuture() {
+			equity += a.futuresPnL(ast, qty)
+		}
+	}
+
+	return equity
 }
 
 // MarginRatio returns equity divided by short market value. Returns NaN
@@ -87,11 +95,13 @@ func (a *Account) MarginRatio() float64 {
 }
 
 // MarginDeficiency returns the dollar amount of position notional that
-// must be unwound to restore margin compliance. It is the worst of two
+// must be unwound to restore margin compliance. It is the worst of three
 // breaches: the short-side maintenance margin shortfall (notional of
-// shorts to cover so SMV*maintenanceRate <= equity), and the gross
+// shorts to cover so SMV*maintenanceRate <= equity), the gross
 // maintenance leverage shortfall (notional to close so
-// (LMV+SMV)/equity <= GrossMaintenanceLeverage). MaxLeverage is
+// (LMV+SMV)/equity <= GrossMaintenanceLeverage), and the futures
+// maintenance margin shortfall (futures notional to close so the
+// remaining contracts' maintenance margin <= equity). MaxLeverage is
 // intentionally not consulted here: it gates new orders at submission
 // time, but adverse drift past that cap does not by itself force
 // liquidation. Returns 0 if the account is healthy.
@@ -124,11 +134,16 @@ func (a *Account) MarginDeficiency() float64 {
 		}
 	}
 
-	if shortDeficit > leverageDeficit {
-		return shortDeficit
+	var futuresDeficit float64
+
+	if _, maintenance := a.FuturesMargin(); maintenance > equity {
+		futuresDeficit = a.FuturesNotional()
+		if equity > 0 {
+			futuresDeficit *= (maintenance - equity) / maintenance
+		}
 	}
 
-	return leverageDeficit
+	return max(shortDeficit, leverageDeficit, futuresDeficit)
 }
 
 // GrossLeverage returns (LongMarketValue + ShortMarketValue) / Equity.
@@ -214,9 +229,11 @@ func (a *Account) LeverageHeadroom() float64 {
 }
 
 // BuyingPower returns cash minus the initial margin reserved for
-// short positions.
+// short and futures positions.
 func (a *Account) BuyingPower() float64 {
-	return a.cash - a.ShortMarketValue()*a.initialMarginRate()
+	futuresInitial, _ := a.FuturesMargin()
+
+	return a.Cash() - a.ShortMarketValue()*a.initialMarginRate() - futuresInitial
 }
 
 // initialMarginRate returns the configured initial margin rate, or the
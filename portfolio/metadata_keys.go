@@ -47,4 +47,12 @@ const (
 
 	// MetaRunInitialCash is the initial cash balance as a decimal string.
 	MetaRunInitialCash = "run.initial_cash"
+
+	// MetaLiveCheckpointAt is the wall-clock time, in RFC 3339 format, at
+	// which a live session last wrote its checkpoint.
+	MetaLiveCheckpointAt = "live.checkpoint_at"
+
+	// MetaLiveLastSync is the time, in RFC 3339 format, up to which broker
+	// transactions had been synced when the checkpoint was written.
+	MetaLiveLastSync = "live.last_sync"
 )
@@ -180,14 +180,16 @@ func annualizationFactor(times []time.Time) float64 {
 }
 
 // YieldToCumulative converts an annualized yield percentage to the next
-// value in a cumulative price-equivalent series. For example, a yield of
-// 5.25 (meaning 5.25% annual) produces a daily return of
+// value in a cumulative price-equivalent series. periodsPerYear is the
+// number of trading days in the market calendar's year, so a yield of 5.25
+// (meaning 5.25% annual) on a 252-day calendar produces a daily return of
 // (1 + 0.0525)^(1/252) - 1, and the cumulative series grows by that factor.
+// A 24/7 calendar passes 365. Non-positive periodsPerYear falls back to 252.
 //
 // Pass prevCumulative=0 on the first call; it returns 100.0 as the
 // starting value. On subsequent calls, it returns
 // prevCumulative * (1 + dailyReturn).
-func YieldToCumulative(annualYieldPct, prevCumulative float64) float64 {
+func YieldToCumulative(annualYieldPct, prevCumulative, periodsPerYear float64) float64 {
 	if prevCumulative == 0 {
 		return 100.0
 	}
@@ -196,7 +198,11 @@ func YieldToCumulative(annualYieldPct, prevCumulative float64) float64 {
 		return prevCumulative
 	}
 
-	dailyReturn := math.Pow(1+annualYieldPct/100, 1.0/252) - 1
+	if periodsPerYear <= 0 {
+		periodsPerYear = 252
+	}
+
+	dailyReturn := math.Pow(1+annualYieldPct/100, 1.0/periodsPerYear) - 1
 
 	return prevCumulative * (1 + dailyReturn)
 }
@@ -226,7 +232,7 @@ func roundTrips(details []TradeDetail, txns []Transaction) ([]roundTrip, float64
 
 	for _, txn := range txns {
 		if txn.Type == asset.SellTransaction {
-			totalSellValue += txn.Price * txn.Qty
+			totalSellValue += txn.Price * txn.Qty * txn.Asset.ContractMultiplier()
 		}
 	}
 
@@ -277,7 +283,9 @@ type gainEvent struct {
 // short lots -- realizing entry-minus-cover gains that are always short-term
 // per IRS short-sale rules -- and any excess opens long lots. It also
 // mirrors Account.ApplySplit: a SplitTransaction rescales open lot
-// quantities and prices by the split factor recorded in txn.Price.
+// quantities and prices by the split factor recorded in txn.Price. Prices
+// and dividends are converted to the base currency at each transaction's
+// FXRate.
 func replayGainEvents(txns []Transaction, start, end time.Time) (events []gainEvent, qualDiv, nonQualDiv float64) {
 	type lot struct {
 		date  time.Time
@@ -338,13 +346,13 @@ func replayGainEvents(txns []Transaction, start, end time.Time) (events []gainEv
 			remaining := txn.Qty
 			shortLots[key], remaining = consume(shortLots[key], remaining, func(matched lot, matchedQty float64) {
 				if attribute {
-					event.stcg += (matched.price - txn.Price) * matchedQty
+					event.stcg += (matched.price - txn.Price*txn.baseRate()) * matchedQty * txn.Asset.ContractMultiplier()
 				}
 			})
 
 			// Phase 2: open a long lot for the remainder.
 			if remaining > 0 {
-				longLots[key] = append(longLots[key], lot{date: txn.Date, qty: remaining, price: txn.Price})
+				longLots[key] = append(longLots[key], lot{date: txn.Date, qty: remaining, price: txn.Price * txn.baseRate()})
 			}
 
 			if attribute && (event.ltcg != 0 || event.stcg != 0) {
@@ -361,7 +369,7 @@ func replayGainEvents(txns []Transaction, start, end time.Time) (events []gainEv
 					return
 				}
 
-				gain := (txn.Price - matched.price) * matchedQty
+				gain := (txn.Price*txn.baseRate() - matched.price) * matchedQty * txn.Asset.ContractMultiplier()
 
 				holdingDays := txn.Date.Sub(matched.date).Hours() / 24
 				if holdingDays > 365 {
@@ -373,7 +381,7 @@ func replayGainEvents(txns []Transaction, start, end time.Time) (events []gainEv
 
 			// Phase 2: open short lots for the remainder.
 			if remaining > 0 {
-				shortLots[key] = append(shortLots[key], lot{date: txn.Date, qty: remaining, price: txn.Price})
+				shortLots[key] = append(shortLots[key], lot{date: txn.Date, qty: remaining, price: txn.Price * txn.baseRate()})
 			}
 
 			if attribute && (event.ltcg != 0 || event.stcg != 0) {
@@ -399,9 +407,9 @@ func replayGainEvents(txns []Transaction, start, end time.Time) (events []gainEv
 			}
 
 			if txn.Qualified {
-				qualDiv += txn.Amount
+				qualDiv += txn.Amount * txn.baseRate()
 			} else {
-				nonQualDiv += txn.Amount
+				nonQualDiv += txn.Amount * txn.baseRate()
 			}
 		}
 	}
@@ -66,3 +66,18 @@ var _ = Describe("Benchmark metrics with NaN in returns", func() {
 		})
 	})
 })
+
+var _ = Describe("YieldToCumulative", func() {
+	It("compounds the annual yield over the calendar's trading days", func() {
+		Expect(portfolio.YieldToCumulative(5, 0, 252)).To(Equal(100.0))
+
+		for _, days := range []float64{252, 365} {
+			cumulative := 100.0
+			for range int(days) {
+				cumulative = portfolio.YieldToCumulative(5, cumulative, days)
+			}
+
+			Expect(cumulative).To(BeNumerically("~", 105, 1e-9))
+		}
+	})
+})
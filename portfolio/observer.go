@@ -0,0 +1,63 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio
+
+import (
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+)
+
+// AccountObserver is notified of broker events after the account has
+// applied them. The engine installs one to deliver fills and corporate
+// actions to a strategy's event callbacks. Observers must not call back
+// into the account: they run while it is draining fills or syncing
+// transactions.
+type AccountObserver interface {
+	// OrderFilled is called for every fill drained from the broker,
+	// including failed fills (fill.Err is set), with the order it
+	// belongs to.
+	OrderFilled(order broker.Order, fill broker.Fill)
+
+	// CorporateActionApplied is called for every dividend and split
+	// synced from the broker.
+	CorporateActionApplied(action broker.Transaction)
+}
+
+// SetObserver installs observer to be notified of fills and corporate
+// actions, replacing any observer already set. Passing nil removes it.
+// Clones do not inherit the observer.
+func (a *Account) SetObserver(observer AccountObserver) {
+	a.observer = observer
+}
+
+// notifyFill reports a drained fill to the observer, if any.
+func (a *Account) notifyFill(order broker.Order, fill broker.Fill) {
+	if a.observer != nil {
+		a.observer.OrderFilled(order, fill)
+	}
+}
+
+// notifyCorporateAction reports a synced dividend or split to the
+// observer, if any.
+func (a *Account) notifyCorporateAction(action broker.Transaction) {
+	if a.observer == nil {
+		return
+	}
+
+	if action.Type == asset.DividendTransaction || action.Type == asset.SplitTransaction {
+		a.observer.CorporateActionApplied(action)
+	}
+}
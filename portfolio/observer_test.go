@@ -0,0 +1,98 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/portfolio"
+)
+
+// recordingObserver collects the events an account reports.
+type recordingObserver struct {
+	orders  []broker.Order
+	fills   []broker.Fill
+	actions []broker.Transaction
+}
+
+func (r *recordingObserver) OrderFilled(order broker.Order, fill broker.Fill) {
+	r.orders = append(r.orders, order)
+	r.fills = append(r.fills, fill)
+}
+
+func (r *recordingObserver) CorporateActionApplied(action broker.Transaction) {
+	r.actions = append(r.actions, action)
+}
+
+var _ = Describe("AccountObserver", func() {
+	var (
+		testAsset asset.Asset
+		mb        *mockBroker
+		acct      *portfolio.Account
+		observer  *recordingObserver
+		date      time.Time
+	)
+
+	BeforeEach(func() {
+		testAsset = asset.Asset{CompositeFigi: "TEST001", Ticker: "TEST"}
+		date = time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+		mb = newMockBroker()
+		mb.defaultFill = &broker.Fill{Price: 100.0, FilledAt: date}
+		acct = portfolio.New(portfolio.WithCash(10_000, time.Time{}), portfolio.WithBroker(mb))
+		observer = &recordingObserver{}
+		acct.SetObserver(observer)
+	})
+
+	It("reports each fill with its order after recording it", func() {
+		batch := acct.NewBatch(date)
+		batch.Order(context.Background(), testAsset, portfolio.Buy, 10)
+		Expect(acct.ExecuteBatch(context.Background(), batch)).To(Succeed())
+
+		Expect(observer.fills).To(HaveLen(1))
+		Expect(observer.fills[0].Qty).To(Equal(10.0))
+		Expect(observer.orders[0].Asset).To(Equal(testAsset))
+		Expect(observer.orders[0].Side).To(Equal(broker.Buy))
+		Expect(acct.Position(testAsset)).To(Equal(10.0))
+	})
+
+	It("reports dividends and splits but not other broker transactions", func() {
+		err := acct.SyncTransactions([]broker.Transaction{
+			{ID: "div-1", Date: date, Asset: testAsset, Type: asset.DividendTransaction, Price: 0.5, Amount: 5},
+			{ID: "fee-1", Date: date, Type: asset.FeeTransaction, Amount: -1},
+			{ID: "split-1", Date: date, Asset: testAsset, Type: asset.SplitTransaction, Price: 2},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(observer.actions).To(HaveLen(2))
+		Expect(observer.actions[0].ID).To(Equal("div-1"))
+		Expect(observer.actions[1].ID).To(Equal("split-1"))
+	})
+
+	It("is not inherited by clones", func() {
+		clone := acct.Clone()
+		batch := clone.NewBatch(date)
+		batch.Order(context.Background(), testAsset, portfolio.Buy, 10)
+		Expect(clone.ExecuteBatch(context.Background(), batch)).To(Succeed())
+
+		Expect(observer.fills).To(BeEmpty())
+	})
+})
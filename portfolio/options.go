@@ -0,0 +1,272 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/data"
+)
+
+// Justifications recorded on the transactions an option expiry produces.
+const (
+	// OptionExpiryJustification marks the closing of an option position
+	// at expiry: at its premium when exercised or assigned, at its
+	// intrinsic value when cash-settled, and at zero otherwise.
+	OptionExpiryJustification = "option expiry"
+
+	// OptionExerciseJustification marks the underlying trade produced by
+	// exercising a long option.
+	OptionExerciseJustification = "option exercise"
+
+	// OptionAssignmentJustification marks the underlying trade produced by
+	// assignment on a short option.
+	OptionAssignmentJustification = "option assignment"
+)
+
+// ExpireOptions settles every option position whose expiry is on or
+// before date against the underlying's current close. In-the-money long
+// options are exercised and in-the-money short options are assigned,
+// trading multiplier shares of the underlying per contract at the strike.
+// The premium paid or received is folded into that trade, raising or
+// lowering the basis of the shares bought or the proceeds of the shares
+// sold, and the option is closed at its premium so it realizes nothing on
+// its own. Cash-settled contracts, such as index options, trade no
+// shares: the option is closed at its intrinsic value in cash.
+// Out-of-the-money options are closed at no value. Positions whose
+// underlying has no close price are left open until the next call.
+func (a *Account) ExpireOptions(date time.Time) {
+	if a.prices == nil {
+		return
+	}
+
+	var options []asset.Asset
+
+	for ast := range a.holdings {
+		if ast.IsOption() && ast.Contract.ExpiresBy(date) {
+			options = append(options, ast)
+		}
+	}
+
+	slices.SortFunc(options, func(left, right asset.Asset) int {
+		return strings.Compare(left.Ticker, right.Ticker)
+	})
+
+	for _, ast := range options {
+		spec := ast.Contract
+		underlying := a.underlyingOf(ast)
+
+		spot := a.prices.Value(underlying, data.MetricClose)
+		if math.IsNaN(spot) {
+			continue
+		}
+
+		qty := a.holdings[ast]
+		long := qty > 0
+		shares := math.Abs(qty) * ast.ContractMultiplier()
+		intrinsic := spec.Intrinsic(spot)
+
+		closing := Transaction{
+			Date:          date,
+			Asset:         ast,
+			Type:          asset.SellTransaction,
+			Qty:           math.Abs(qty),
+			Justification: OptionExpiryJustification,
+		}
+
+		if !long {
+			closing.Type = asset.BuyTransaction
+		}
+
+		switch {
+		case intrinsic > 0 && spec.CashSettled:
+			closing.Price = intrinsic
+			closing.Amount = shares * intrinsic
+
+			if !long {
+				closing.Amount = -closing.Amount
+			}
+		case intrinsic > 0:
+			premium := a.optionPremium(ast, long)
+
+			// A long call or a short put ends long the underlying.
+			buysUnderlying := (spec.Right == asset.Call) == long
+
+			justification := OptionExerciseJustification
+			if !long {
+				justification = OptionAssignmentJustification
+			}
+
+			// Buying through a long option or selling through a short
+			// one adds the premium; the other two subtract it.
+			price := spec.Strike - premium
+			if buysUnderlying == long {
+				price = spec.Strike + premium
+			}
+
+			txn := Transaction{
+				Date:          date,
+				Asset:         underlying,
+				Qty:           shares,
+				Price:         price,
+				Justification: justification,
+			}
+
+			if buysUnderlying {
+				txn.Type = asset.BuyTransaction
+				txn.Amount = -shares * spec.Strike
+			} else {
+				txn.Type = asset.SellTransaction
+				txn.Amount = shares * spec.Strike
+			}
+
+			a.Record(txn)
+
+			closing.Price = premium
+		}
+
+		a.Record(closing)
+	}
+}
+
+// optionPremium returns the average premium per share of the open lots
+// of an option position: the long lots when long is true, the short lots
+// otherwise.
+func (a *Account) optionPremium(option asset.Asset, long bool) float64 {
+	lots := a.taxLots[option]
+	if !long {
+		lots = a.shortLots[option]
+	}
+
+	var qty, cost float64
+
+	for _, lot := range lots {
+		qty += lot.Qty
+		cost += lot.Qty * lot.Price
+	}
+
+	if qty == 0 {
+		return 0
+	}
+
+	return cost / qty
+}
+
+// underlyingOf returns the asset an option is written on, preferring the
+// account's own holding of it so exercise and assignment add to that
+// position rather than opening a second one keyed on a bare asset.
+func (a *Account) underlyingOf(option asset.Asset) asset.Asset {
+	underlying := option.Contract.UnderlyingAsset()
+
+	for ast := range a.holdings {
+		if ast.CompositeFigi == underlying.CompositeFigi && !ast.IsOption() {
+			return ast
+		}
+	}
+
+	return underlying
+}
+
+// CoveredCall appends an order selling call contracts against the
+// projected long position in the call's underlying: one contract per
+// multiplier shares not already covered by short calls on the same
+// underlying. It is a no-op when every whole lot is covered and returns an
+// error when ast is not a call option. Bracket and OCO modifiers are not
+// supported.
+func (b *Batch) CoveredCall(_ context.Context, ast asset.Asset, mods ...OrderModifier) error {
+	if !ast.IsOption() || ast.Contract.Right != asset.Call {
+		return fmt.Errorf("portfolio: CoveredCall requires a call option, got %s", ast.Ticker)
+	}
+
+	return b.optionOverlay("CoveredCall", ast, Sell, mods)
+}
+
+// ProtectivePut appends an order buying put contracts against the
+// projected long position in the put's underlying: one contract per
+// multiplier shares not already protected by long puts on the same
+// underlying. It is a no-op when every whole lot is protected and returns
+// an error when ast is not a put option. Bracket and OCO modifiers are not
+// supported.
+func (b *Batch) ProtectivePut(_ context.Context, ast asset.Asset, mods ...OrderModifier) error {
+	if !ast.IsOption() || ast.Contract.Right != asset.Put {
+		return fmt.Errorf("portfolio: ProtectivePut requires a put option, got %s", ast.Ticker)
+	}
+
+	return b.optionOverlay("ProtectivePut", ast, Buy, mods)
+}
+
+// optionOverlay sizes and appends an order for option contracts that
+// cover the projected shares of the option's underlying. Selling writes
+// calls (covered by short calls already held); buying adds puts (covered
+// by long puts already held).
+func (b *Batch) optionOverlay(name string, option asset.Asset, side Side, mods []OrderModifier) error {
+	spec := option.Contract
+
+	var shares, coveredContracts float64
+
+	for held, qty := range b.ProjectedHoldings() {
+		switch {
+		case held.IsOption():
+			if held.Contract.UnderlyingFigi != spec.UnderlyingFigi || held.Contract.Right != spec.Right {
+				continue
+			}
+
+			contracts := qty
+			if side == Sell {
+				contracts = -qty
+			}
+
+			if contracts > 0 {
+				coveredContracts += contracts * held.ContractMultiplier() / option.ContractMultiplier()
+			}
+		case held.CompositeFigi == spec.UnderlyingFigi:
+			shares += qty
+		}
+	}
+
+	contracts := math.Floor(shares/option.ContractMultiplier()) - math.Ceil(coveredContracts)
+	if contracts <= 0 {
+		return nil
+	}
+
+	order := broker.Order{
+		Asset:       option,
+		Qty:         contracts,
+		OrderType:   broker.Market,
+		TimeInForce: broker.Day,
+		Side:        broker.Buy,
+	}
+
+	if side == Sell {
+		order.Side = broker.Sell
+	}
+
+	bracket, oco := applyOrderModifiers(&order, mods)
+	if bracket != nil || oco != nil {
+		return fmt.Errorf("portfolio: %s does not support bracket or OCO modifiers", name)
+	}
+
+	b.Orders = append(b.Orders, order)
+
+	return nil
+}
@@ -0,0 +1,255 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio_test
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/portfolio"
+)
+
+var _ = Describe("Options", func() {
+	var (
+		spy    asset.Asset
+		call   asset.Asset
+		put    asset.Asset
+		date   time.Time
+		expiry time.Time
+	)
+
+	BeforeEach(func() {
+		spy = asset.Asset{CompositeFigi: "SPY", Ticker: "SPY"}
+		date = time.Date(2024, 6, 3, 16, 0, 0, 0, time.UTC)
+		expiry = time.Date(2024, 6, 21, 16, 0, 0, 0, time.UTC)
+		call = asset.NewOption(spy, asset.Call, 500, expiry, 0)
+		put = asset.NewOption(spy, asset.Put, 500, expiry, 0)
+	})
+
+	trade := func(acct *portfolio.Account, ast asset.Asset, txnType asset.TransactionType, qty, price float64) {
+		amount := qty * price * ast.ContractMultiplier()
+		if txnType == asset.BuyTransaction {
+			amount = -amount
+		}
+
+		acct.Record(portfolio.Transaction{
+			Date: date, Asset: ast, Type: txnType, Qty: qty, Price: price, Amount: amount,
+		})
+	}
+
+	// expire marks the underlying and options at the expiry close and
+	// settles expiring options the way the engine does.
+	expire := func(acct *portfolio.Account, spot float64) {
+		df := buildDF(expiry, []asset.Asset{spy, call, put},
+			[]float64{spot, max(spot-500, 0), max(500-spot, 0)},
+			[]float64{spot, max(spot-500, 0), max(500-spot, 0)})
+		acct.SetPrices(df)
+		acct.ExpireOptions(expiry)
+		acct.UpdatePrices(df)
+	}
+
+	It("values option positions by the contract multiplier", func() {
+		acct := portfolio.New(portfolio.WithCash(10_000, date))
+		trade(acct, call, asset.BuyTransaction, 2, 5)
+		Expect(acct.Cash()).To(Equal(9_000.0))
+
+		acct.UpdatePrices(buildDF(date, []asset.Asset{spy, call}, []float64{505, 6}, []float64{505, 6}))
+		Expect(acct.PositionValue(call)).To(Equal(1_200.0))
+		Expect(acct.Value()).To(Equal(10_200.0))
+	})
+
+	It("exercises in-the-money long calls at expiry", func() {
+		acct := portfolio.New(portfolio.WithCash(200_000, date))
+		trade(acct, call, asset.BuyTransaction, 2, 5)
+
+		expire(acct, 510)
+
+		Expect(acct.Position(call)).To(Equal(0.0))
+		Expect(acct.Position(spy)).To(Equal(200.0))
+		Expect(acct.Cash()).To(Equal(200_000.0 - 1_000 - 100_000))
+		Expect(acct.Value()).To(Equal(200_000.0 - 1_000 + 2_000))
+
+		txns := acct.Transactions()
+		Expect(txns[len(txns)-2].Justification).To(Equal(portfolio.OptionExerciseJustification))
+		Expect(txns[len(txns)-1].Justification).To(Equal(portfolio.OptionExpiryJustification))
+	})
+
+	It("adds the premium of an exercised call to the basis of the shares bought", func() {
+		acct := portfolio.New(portfolio.WithCash(200_000, date))
+		trade(acct, call, asset.BuyTransaction, 2, 5)
+
+		expire(acct, 510)
+
+		lots := acct.UnrealizedLots(spy)
+		Expect(lots).To(HaveLen(1))
+		Expect(lots[0].Price).To(Equal(505.0))
+
+		details := acct.TradeDetails()
+		Expect(details).To(HaveLen(1))
+		Expect(details[0].Asset).To(Equal(call))
+		Expect(details[0].PnL).To(Equal(0.0))
+	})
+
+	It("assigns in-the-money short puts at expiry", func() {
+		acct := portfolio.New(portfolio.WithCash(100_000, date))
+		trade(acct, put, asset.SellTransaction, 1, 4)
+
+		expire(acct, 490)
+
+		Expect(acct.Position(put)).To(Equal(0.0))
+		Expect(acct.Position(spy)).To(Equal(100.0))
+		Expect(acct.Cash()).To(Equal(100_000.0 + 400 - 50_000))
+
+		txns := acct.Transactions()
+		Expect(txns[len(txns)-2].Type).To(Equal(asset.BuyTransaction))
+		Expect(txns[len(txns)-2].Justification).To(Equal(portfolio.OptionAssignmentJustification))
+	})
+
+	It("subtracts the premium of an assigned put from the basis of the shares bought", func() {
+		acct := portfolio.New(portfolio.WithCash(100_000, date))
+		trade(acct, put, asset.SellTransaction, 1, 4)
+
+		expire(acct, 490)
+
+		lots := acct.UnrealizedLots(spy)
+		Expect(lots).To(HaveLen(1))
+		Expect(lots[0].Price).To(Equal(496.0))
+	})
+
+	It("delivers shares held against assigned covered calls", func() {
+		acct := portfolio.New(portfolio.WithCash(100_000, date))
+		trade(acct, spy, asset.BuyTransaction, 100, 480)
+		trade(acct, call, asset.SellTransaction, 1, 3)
+
+		expire(acct, 520)
+
+		Expect(acct.Position(spy)).To(Equal(0.0))
+		Expect(acct.Position(call)).To(Equal(0.0))
+		Expect(acct.Cash()).To(Equal(100_000.0 - 48_000 + 300 + 50_000))
+
+		// The call premium is part of the proceeds of the shares sold.
+		txns := acct.Transactions()
+		Expect(txns[len(txns)-2].Asset).To(Equal(spy))
+		Expect(txns[len(txns)-2].Price).To(Equal(503.0))
+		Expect(txns[len(txns)-2].Amount).To(Equal(50_000.0))
+		Expect(txns[len(txns)-1].Price).To(Equal(3.0))
+		Expect(txns[len(txns)-1].Amount).To(Equal(0.0))
+	})
+
+	It("settles in-the-money index options in cash", func() {
+		spx := asset.Asset{CompositeFigi: "SPX", Ticker: "SPX"}
+		long := asset.NewIndexOption(spx, asset.Call, 5_000, expiry, 0)
+		short := asset.NewIndexOption(spx, asset.Put, 5_100, expiry, 0)
+
+		acct := portfolio.New(portfolio.WithCash(100_000, date))
+		trade(acct, long, asset.BuyTransaction, 1, 20)
+		trade(acct, short, asset.SellTransaction, 1, 30)
+
+		df := buildDF(expiry, []asset.Asset{spx, long, short},
+			[]float64{5_050, 50, 50}, []float64{5_050, 50, 50})
+		acct.SetPrices(df)
+		acct.ExpireOptions(expiry)
+		acct.UpdatePrices(df)
+
+		Expect(acct.Position(long)).To(Equal(0.0))
+		Expect(acct.Position(short)).To(Equal(0.0))
+		Expect(acct.Position(spx)).To(Equal(0.0))
+		Expect(acct.Cash()).To(Equal(100_000.0 - 2_000 + 3_000 + 5_000 - 5_000))
+	})
+
+	It("lets out-of-the-money options expire worthless", func() {
+		acct := portfolio.New(portfolio.WithCash(10_000, date))
+		trade(acct, put, asset.BuyTransaction, 1, 2)
+
+		expire(acct, 510)
+
+		Expect(acct.Position(put)).To(Equal(0.0))
+		Expect(acct.Position(spy)).To(Equal(0.0))
+		Expect(acct.Cash()).To(Equal(9_800.0))
+
+		details := acct.TradeDetails()
+		Expect(details).To(HaveLen(1))
+		Expect(details[0].PnL).To(Equal(-200.0))
+	})
+
+	It("sizes covered calls and protective puts from projected shares", func() {
+		acct := portfolio.New(portfolio.WithCash(200_000, date))
+		trade(acct, spy, asset.BuyTransaction, 250, 500)
+		trade(acct, call, asset.SellTransaction, 1, 3)
+		acct.UpdatePrices(buildDF(date, []asset.Asset{spy, call, put}, []float64{500, 3, 4}, []float64{500, 3, 4}))
+
+		batch := acct.NewBatch(date)
+		Expect(batch.CoveredCall(context.Background(), call)).To(Succeed())
+		Expect(batch.ProtectivePut(context.Background(), put)).To(Succeed())
+
+		Expect(batch.Orders).To(HaveLen(2))
+		Expect(batch.Orders[0].Asset).To(Equal(call))
+		Expect(batch.Orders[0].Side).To(Equal(broker.Sell))
+		Expect(batch.Orders[0].Qty).To(Equal(1.0))
+		Expect(batch.Orders[1].Asset).To(Equal(put))
+		Expect(batch.Orders[1].Side).To(Equal(broker.Buy))
+		Expect(batch.Orders[1].Qty).To(Equal(2.0))
+
+		// Every whole lot is now covered.
+		Expect(batch.CoveredCall(context.Background(), call)).To(Succeed())
+		Expect(batch.Orders).To(HaveLen(2))
+
+		Expect(batch.CoveredCall(context.Background(), put)).To(MatchError(ContainSubstring("requires a call option")))
+	})
+
+	It("round-trips option contracts through SQLite", func() {
+		acct := portfolio.New(portfolio.WithCash(10_000, date))
+		trade(acct, call, asset.BuyTransaction, 2, 5)
+
+		path := filepath.Join(GinkgoT().TempDir(), "options.db")
+		Expect(acct.ToSQLite(path)).To(Succeed())
+
+		restored, err := portfolio.FromSQLite(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		for held := range restored.Holdings() {
+			Expect(held.AssetType).To(Equal(asset.AssetTypeOption))
+			Expect(held.Contract).To(Equal(call.Contract))
+		}
+
+		expire(restored, 510)
+		Expect(restored.Position(spy)).To(Equal(200.0))
+	})
+
+	It("round-trips cash settlement through SQLite", func() {
+		spx := asset.Asset{CompositeFigi: "SPX", Ticker: "SPX"}
+		index := asset.NewIndexOption(spx, asset.Call, 5_000, expiry, 0)
+
+		acct := portfolio.New(portfolio.WithCash(10_000, date))
+		trade(acct, index, asset.BuyTransaction, 1, 20)
+
+		path := filepath.Join(GinkgoT().TempDir(), "index.db")
+		Expect(acct.ToSQLite(path)).To(Succeed())
+
+		restored, err := portfolio.FromSQLite(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		for held := range restored.Holdings() {
+			Expect(held.Contract.CashSettled).To(BeTrue())
+		}
+	})
+})
@@ -53,6 +53,63 @@ func (stopModifier) orderModifier() {}
 // Stop triggers a market order when the price reaches a threshold (stop loss).
 func Stop(price float64) OrderModifier { return stopModifier{price: price} }
 
+// Trail is the distance a trailing stop keeps from the best price reached
+// since the order was placed. Either Amount or Percent is set, but not
+// both.
+type Trail struct {
+	Amount  float64
+	Percent float64
+}
+
+// TrailAmount creates a Trail that follows the price by a dollar amount.
+func TrailAmount(amount float64) Trail {
+	return Trail{Amount: amount}
+}
+
+// TrailPercent creates a Trail that follows the price by a percentage of
+// it. For example, TrailPercent(5.0) keeps the stop 5% from the best
+// price.
+func TrailPercent(pct float64) Trail {
+	return Trail{Percent: pct}
+}
+
+func (t Trail) isZero() bool { return t.Amount == 0 && t.Percent == 0 }
+
+type trailingStopModifier struct {
+	trail       Trail
+	limitOffset float64
+	limit       bool
+}
+
+func (trailingStopModifier) orderModifier() {}
+
+// TrailingStop turns the order into a trailing stop: a stop that follows
+// the price as it moves in the position's favor and triggers a market
+// order when the price reverses by the trail. A sell trails below the
+// highest price since submission; a buy trails above the lowest.
+func TrailingStop(trail Trail) OrderModifier {
+	return trailingStopModifier{trail: trail}
+}
+
+// TrailingStopLimit is TrailingStop except that the triggered order is a
+// limit order priced limitOffset dollars beyond the stop (below it for
+// sells, above it for buys).
+func TrailingStopLimit(trail Trail, limitOffset float64) OrderModifier {
+	return trailingStopModifier{trail: trail, limitOffset: limitOffset, limit: true}
+}
+
+// apply sets the trailing order type and distance on order.
+func (ts trailingStopModifier) apply(order *broker.Order) {
+	order.OrderType = broker.TrailingStop
+	if ts.limit {
+		order.OrderType = broker.TrailingStopLimit
+		order.LimitOffset = ts.limitOffset
+	}
+
+	order.TrailAmount = ts.trail.Amount
+	order.TrailPercent = ts.trail.Percent
+}
+
 // --- Time in force modifiers ---
 
 type dayOrderModifier struct{}
@@ -125,13 +182,54 @@ func WithLotSelection(method LotSelection) OrderModifier {
 	return lotSelectionModifier{method: method}
 }
 
+// --- Execution algorithm modifiers ---
+
+type execAlgoModifier struct {
+	algo          broker.ExecAlgo
+	duration      time.Duration
+	slices        int
+	participation float64
+}
+
+func (execAlgoModifier) orderModifier() {}
+
+// ExecTWAP works the order as slices equal-sized child orders spaced
+// evenly over duration, starting with the first minute bar after the order
+// is placed.
+func ExecTWAP(duration time.Duration, slices int) OrderModifier {
+	return execAlgoModifier{algo: broker.ExecTWAP, duration: duration, slices: slices}
+}
+
+// ExecVWAP works the order as slices child orders over duration, each
+// sized by the share of the window's volume traded in its slice.
+func ExecVWAP(duration time.Duration, slices int) OrderModifier {
+	return execAlgoModifier{algo: broker.ExecVWAP, duration: duration, slices: slices}
+}
+
+// ExecPOV works the order by trading pct percent of each minute's volume
+// until it is filled. For example, ExecPOV(10) takes 10% of the volume.
+func ExecPOV(pct float64) OrderModifier {
+	return execAlgoModifier{algo: broker.ExecPOV, participation: pct}
+}
+
+// apply sets the execution algorithm and its parameters on order.
+func (ea execAlgoModifier) apply(order *broker.Order) {
+	order.ExecAlgo = ea.algo
+	order.ExecDuration = ea.duration
+	order.ExecSlices = ea.slices
+	order.ExecParticipation = ea.participation
+}
+
 // --- Bracket and OCO modifiers ---
 
 // ExitTarget describes a single exit condition for a bracket order. Either
-// AbsolutePrice or PercentOffset is set, but not both.
+// AbsolutePrice or PercentOffset is set, but not both. A stop-loss target
+// may instead set Trail, making the stop a trailing stop that starts the
+// trail's distance from the entry fill price.
 type ExitTarget struct {
 	AbsolutePrice float64
 	PercentOffset float64
+	Trail         Trail
 }
 
 // StopLossPrice creates an ExitTarget that triggers a stop at the given
@@ -147,6 +245,13 @@ func StopLossPercent(pct float64) ExitTarget {
 	return ExitTarget{PercentOffset: -pct / 100.0}
 }
 
+// TrailingStopLoss creates an ExitTarget that places a trailing stop,
+// starting trail away from the entry fill price and following the price
+// from there.
+func TrailingStopLoss(trail Trail) ExitTarget {
+	return ExitTarget{Trail: trail}
+}
+
 // TakeProfitPrice creates an ExitTarget that closes the position at the
 // given absolute price.
 func TakeProfitPrice(price float64) ExitTarget {
@@ -37,9 +37,20 @@ var (
 // but no mutation methods. Orders are placed through a Batch, which the
 // engine submits via PortfolioManager.ExecuteBatch.
 type Portfolio interface {
-	// Cash returns the current cash balance available in the portfolio.
+	// Cash returns the current cash balance available in the portfolio,
+	// in the base currency. Cash held in foreign currencies is converted
+	// at the current exchange rates.
 	Cash() float64
 
+	// CashBalances returns the cash held in each currency, in units of
+	// that currency. The base currency is always present.
+	CashBalances() map[asset.Currency]float64
+
+	// FXRate returns the base-currency value of one unit of the given
+	// currency as of the latest rate update. It is 1 for the base
+	// currency and NaN when no rate is known.
+	FXRate(cur asset.Currency) float64
+
 	// Value returns the total portfolio value: cash plus all holdings
 	// marked to current prices.
 	Value() float64
@@ -165,6 +176,15 @@ type Portfolio interface {
 	// account is already over the cap.
 	LeverageHeadroom() float64
 
+	// FuturesNotional returns the total absolute notional value
+	// (contracts * multiplier * price) of all futures positions. Futures
+	// are not part of LongMarketValue or ShortMarketValue.
+	FuturesNotional() float64
+
+	// FuturesMargin returns the initial and maintenance margin required
+	// by the account's futures positions.
+	FuturesMargin() (initial, maintenance float64)
+
 	// Benchmark returns the asset used as the performance benchmark
 	// for this portfolio.
 	Benchmark() asset.Asset
@@ -252,6 +272,11 @@ type PortfolioManager interface {
 	// channel and records them as transactions.
 	DrainFills(ctx context.Context) error
 
+	// ReplayFills applies fills reported by the broker outside the fill
+	// channel, such as those executed while a live session was down.
+	// Fills for orders that are no longer pending are ignored.
+	ReplayFills(fills []broker.Fill)
+
 	// CancelOpenOrders cancels all open or submitted orders and
 	// removes them from the pending-orders tracker.
 	CancelOpenOrders(ctx context.Context) error
@@ -267,6 +292,33 @@ type PortfolioManager interface {
 	// HasMaxLeverage reports whether a non-default cap is configured.
 	HasMaxLeverage() bool
 
+	// SetFXRates updates the exchange rates used to value foreign-currency
+	// holdings and cash, and settles and reopens currency hedge contracts
+	// that are due. The engine calls this at each step before
+	// housekeeping.
+	SetFXRates(date time.Time, rates map[asset.Currency]float64)
+
+	// SetCurrencyHedge enables a rolling hedge of foreign-currency
+	// exposure.
+	SetCurrencyHedge(hedge CurrencyHedge)
+
+	// SettleFutures settles futures positions to the current close
+	// through variation margin and closes positions in contracts that
+	// expire on or before date. The engine calls this at the end of
+	// each step, before recording the day's equity.
+	SettleFutures(date time.Time)
+
+	// ExpireOptions exercises, assigns, or closes option positions that
+	// expire on or before date, against the underlying's current close.
+	// The engine calls this at the end of each step, after
+	// SettleFutures.
+	ExpireOptions(date time.Time)
+
+	// SetDividendReinvestment enables a dividend reinvestment plan: cash
+	// dividends synced from the broker are immediately used to buy more
+	// of the paying asset.
+	SetDividendReinvestment(drip DividendReinvestment)
+
 	// SetGrossMaintenanceLeverage applies a gross-leverage liquidation
 	// threshold. Used by the engine to install a strategy- or
 	// CLI-supplied value. Values <= 0 are ignored.
@@ -314,6 +366,10 @@ type PortfolioManager interface {
 	// is independent: mutations to one do not affect the other.
 	Clone() PortfolioManager
 
+	// SetObserver installs an observer notified of every fill and every
+	// dividend or split the account applies. Passing nil removes it.
+	SetObserver(observer AccountObserver)
+
 	// SetPrediction stores the outcome of a prediction run so it can be
 	// queried via Prediction and persisted alongside the rest of the
 	// portfolio state. Passing nil clears any stored prediction.
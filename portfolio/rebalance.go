@@ -0,0 +1,193 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
+)
+
+// RebalanceSkipAnnotation prefixes the batch annotation recorded for each
+// rebalance trade that RebalanceWithin skipped. The full key is the prefix
+// followed by the asset's ticker; the value starts with the skipped dollar
+// amount ("$1234.56: ...") followed by the reason.
+const RebalanceSkipAnnotation = "rebalance.skipped."
+
+// SkippedTrade is a rebalance trade that RebalanceWithin did not place.
+type SkippedTrade struct {
+	Timestamp time.Time
+	Asset     asset.Asset
+	Amount    float64 // dollar value of the trade that was skipped
+	Reason    string
+}
+
+// rebalanceConfig holds the tolerances applied by RebalanceWithin.
+type rebalanceConfig struct {
+	absoluteBand     float64
+	relativeBand     float64
+	minTradeNotional float64
+	cashBuffer       float64
+	tradeToBand      bool
+}
+
+// RebalanceOption configures how RebalanceWithin trades toward its target
+// weights.
+type RebalanceOption func(*rebalanceConfig)
+
+// AbsoluteBand skips a position whose weight is within drift of its
+// target, measured in weight units: AbsoluteBand(0.05) leaves a 40%
+// target alone anywhere from 35% to 45%.
+func AbsoluteBand(drift float64) RebalanceOption {
+	return func(cfg *rebalanceConfig) {
+		cfg.absoluteBand = drift
+	}
+}
+
+// RelativeBand skips a position whose weight is within fraction of its
+// target weight: RelativeBand(0.25) leaves a 40% target alone anywhere
+// from 30% to 50%. When both bands are set the narrower one applies, so
+// AbsoluteBand(0.05) with RelativeBand(0.25) is the "5/25" rule.
+func RelativeBand(fraction float64) RebalanceOption {
+	return func(cfg *rebalanceConfig) {
+		cfg.relativeBand = fraction
+	}
+}
+
+// MinTradeNotional skips any rebalance trade smaller than dollars.
+// Liquidations of assets dropped from the allocation are always traded.
+func MinTradeNotional(dollars float64) RebalanceOption {
+	return func(cfg *rebalanceConfig) {
+		cfg.minTradeNotional = dollars
+	}
+}
+
+// TradeToBand trades a position that has drifted outside its band only
+// back to the nearest edge of the band rather than all the way to its
+// target weight. It has no effect without AbsoluteBand or RelativeBand.
+func TradeToBand() RebalanceOption {
+	return func(cfg *rebalanceConfig) {
+		cfg.tradeToBand = true
+	}
+}
+
+// CashBuffer keeps fraction of the portfolio's value in cash by scaling
+// every target weight by 1 - fraction. CashBuffer(0.02) invests 98% of
+// the portfolio according to the allocation.
+func CashBuffer(fraction float64) RebalanceOption {
+	return func(cfg *rebalanceConfig) {
+		cfg.cashBuffer = fraction
+	}
+}
+
+// tolerance returns the drift allowed around targetWeight, or -1 when no
+// band is configured.
+func (cfg rebalanceConfig) tolerance(targetWeight float64) float64 {
+	tol := -1.0
+
+	if cfg.absoluteBand > 0 {
+		tol = cfg.absoluteBand
+	}
+
+	if cfg.relativeBand > 0 {
+		relative := cfg.relativeBand * math.Abs(targetWeight)
+		if tol < 0 || relative < tol {
+			tol = relative
+		}
+	}
+
+	return tol
+}
+
+// bandTargets returns the weight each member of alloc should be traded
+// to. Members whose drift from target is inside their band are left out
+// of the result and recorded as skipped. totalValue is the portfolio
+// value the current weights are measured against.
+func (b *Batch) bandTargets(alloc Allocation, totalValue float64, cfg rebalanceConfig) map[asset.Asset]float64 {
+	targets := make(map[asset.Asset]float64, len(alloc.Members))
+
+	for ast, weight := range alloc.Members {
+		target := weight * (1 - cfg.cashBuffer)
+
+		tol := cfg.tolerance(target)
+		if tol < 0 || totalValue <= 0 {
+			targets[ast] = target
+			continue
+		}
+
+		current := b.projectedPositionValue(ast)
+		drift := current/totalValue - target
+
+		if math.Abs(drift) <= tol {
+			b.recordSkip(ast, math.Abs(drift*totalValue),
+				fmt.Sprintf("drift %.2f%% inside %.2f%% band", drift*100, tol*100))
+
+			continue
+		}
+
+		if cfg.tradeToBand {
+			target += math.Copysign(tol, drift)
+		}
+
+		targets[ast] = target
+	}
+
+	return targets
+}
+
+// belowMinimum reports whether a trade of amount dollars in ast is too
+// small to place, recording the skip when it is.
+func (b *Batch) belowMinimum(ast asset.Asset, amount float64, cfg rebalanceConfig) bool {
+	if cfg.minTradeNotional <= 0 || amount >= cfg.minTradeNotional {
+		return false
+	}
+
+	b.recordSkip(ast, amount, fmt.Sprintf("below $%.2f minimum trade", cfg.minTradeNotional))
+
+	return true
+}
+
+// recordSkip records a rebalance trade that was not placed, both as a
+// SkippedTrade and as a human-readable batch annotation.
+func (b *Batch) recordSkip(ast asset.Asset, amount float64, reason string) {
+	b.SkippedTrades = append(b.SkippedTrades, SkippedTrade{
+		Timestamp: b.Timestamp,
+		Asset:     ast,
+		Amount:    amount,
+		Reason:    reason,
+	})
+
+	b.Annotate(RebalanceSkipAnnotation+ast.Ticker, fmt.Sprintf("$%.2f: %s", amount, reason))
+}
+
+// skippedRebalanceTrades counts the skipped trades recorded within
+// [start, end] and sums their dollar amounts. A zero start or end leaves
+// that side of the range open.
+func skippedRebalanceTrades(skipped []SkippedTrade, start, end time.Time) (count, notional float64) {
+	for _, trade := range skipped {
+		if (!start.IsZero() && trade.Timestamp.Before(start)) ||
+			(!end.IsZero() && trade.Timestamp.After(end)) {
+			continue
+		}
+
+		count++
+		notional += trade.Amount
+	}
+
+	return count, notional
+}
@@ -23,12 +23,24 @@ import (
 )
 
 // TaxLot tracks the purchase date, quantity, and price of a position for
-// tax gain/loss calculations.
+// tax gain/loss calculations. Price is in the asset's quote currency;
+// FXRate is the base-currency value of one unit of that currency on the
+// lot's date, and is zero for base-currency lots.
 type TaxLot struct {
-	ID    string
-	Date  time.Time
-	Qty   float64
-	Price float64
+	ID     string
+	Date   time.Time
+	Qty    float64
+	Price  float64
+	FXRate float64
+}
+
+// BasePrice returns the lot's price in the base currency.
+func (lot TaxLot) BasePrice() float64 {
+	if lot.FXRate == 0 {
+		return lot.Price
+	}
+
+	return lot.Price * lot.FXRate
 }
 
 // PortfolioSnapshot exposes the state needed to reconstruct a portfolio
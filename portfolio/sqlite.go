@@ -22,8 +22,11 @@ import (
 	"io/fs"
 	"math"
 	"os"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/penny-vault/pvbt/asset"
@@ -32,7 +35,40 @@ import (
 	_ "modernc.org/sqlite"
 )
 
-const schemaVersion = "7"
+// schemaVersion must be bumped with every change to createSchema.
+// Databases written with a version in migratableVersions are upgraded
+// by migrateSchema when read.
+const schemaVersion = "18"
+
+// migratableVersions lists the older schema versions FromSQLite can
+// upgrade.
+var migratableVersions = []string{"7", "8", "9", "10", "11", "12", "13", "14", "15", "16", "17"}
+
+// addedColumns lists the columns added to existing tables since schema
+// version 7. A database may come from any migratable version, so
+// migrateSchema adds each one only if the table lacks it.
+var addedColumns = []struct {
+	table      string
+	column     string
+	definition string
+}{
+	{"transactions", "order_id", "TEXT"},
+	{"pending_orders", "trail_amount", "REAL NOT NULL DEFAULT 0"},
+	{"pending_orders", "trail_percent", "REAL NOT NULL DEFAULT 0"},
+	{"pending_orders", "limit_offset", "REAL NOT NULL DEFAULT 0"},
+	{"transactions", "currency", "TEXT NOT NULL DEFAULT ''"},
+	{"holdings", "currency", "TEXT NOT NULL DEFAULT ''"},
+	{"tax_lots", "currency", "TEXT NOT NULL DEFAULT ''"},
+	{"contracts", "underlying", "TEXT NOT NULL DEFAULT ''"},
+	{"contracts", "underlying_figi", "TEXT NOT NULL DEFAULT ''"},
+	{"contracts", "strike", "REAL NOT NULL DEFAULT 0"},
+	{"contracts", "option_right", "TEXT NOT NULL DEFAULT ''"},
+	{"pending_orders", "filled_qty", "REAL NOT NULL DEFAULT 0"},
+	{"pending_orders", "filled_cost", "REAL NOT NULL DEFAULT 0"},
+	{"transactions", "fx_rate", "REAL NOT NULL DEFAULT 0"},
+	{"tax_lots", "fx_rate", "REAL NOT NULL DEFAULT 0"},
+	{"contracts", "cash_settled", "INTEGER NOT NULL DEFAULT 0"},
+}
 
 const dateFormat = "2006-01-02"
 
@@ -65,7 +101,10 @@ CREATE TABLE transactions (
     price         REAL,
     amount        REAL,
     qualified     INTEGER,
-    justification TEXT
+    justification TEXT,
+    order_id      TEXT,
+    currency      TEXT NOT NULL DEFAULT '',
+    fx_rate       REAL NOT NULL DEFAULT 0
 );
 
 CREATE TABLE holdings (
@@ -73,7 +112,8 @@ CREATE TABLE holdings (
     asset_figi   TEXT NOT NULL,
     quantity     REAL NOT NULL,
     avg_cost     REAL NOT NULL,
-    market_value REAL NOT NULL
+    market_value REAL NOT NULL,
+    currency     TEXT NOT NULL DEFAULT ''
 );
 
 CREATE TABLE tax_lots (
@@ -82,7 +122,9 @@ CREATE TABLE tax_lots (
     date         TEXT NOT NULL,
     quantity     REAL NOT NULL,
     price        REAL NOT NULL,
-    id           TEXT NOT NULL DEFAULT ''
+    id           TEXT NOT NULL DEFAULT '',
+    currency     TEXT NOT NULL DEFAULT '',
+    fx_rate      REAL NOT NULL DEFAULT 0
 );
 
 CREATE TABLE metrics (
@@ -105,8 +147,17 @@ CREATE TABLE annotations (
 );
 
 CREATE INDEX idx_annotations_timestamp ON annotations(timestamp);
+
 CREATE INDEX idx_annotations_batch ON annotations(batch_id);
 
+CREATE TABLE skipped_trades (
+    timestamp    INTEGER NOT NULL,
+    asset_ticker TEXT NOT NULL,
+    asset_figi   TEXT NOT NULL,
+    amount       REAL NOT NULL,
+    reason       TEXT NOT NULL
+);
+
 CREATE TABLE positions_daily (
     date         TEXT NOT NULL,
     ticker       TEXT NOT NULL,
@@ -144,6 +195,80 @@ CREATE TABLE predicted_annotations (
     key   TEXT NOT NULL,
     value TEXT NOT NULL
 );
+
+CREATE TABLE pending_orders (
+    id            TEXT PRIMARY KEY,
+    batch_id      INTEGER NOT NULL DEFAULT 0,
+    ticker        TEXT NOT NULL,
+    figi          TEXT NOT NULL,
+    side          INTEGER NOT NULL,
+    quantity      REAL NOT NULL,
+    amount        REAL NOT NULL,
+    order_type    INTEGER NOT NULL,
+    time_in_force INTEGER NOT NULL,
+    limit_price   REAL NOT NULL,
+    stop_price    REAL NOT NULL,
+    lot_selection INTEGER NOT NULL,
+    group_id      TEXT NOT NULL DEFAULT '',
+    group_role    INTEGER NOT NULL DEFAULT 0,
+    justification TEXT,
+    trail_amount  REAL NOT NULL DEFAULT 0,
+    trail_percent REAL NOT NULL DEFAULT 0,
+    limit_offset  REAL NOT NULL DEFAULT 0,
+    filled_qty    REAL NOT NULL DEFAULT 0,
+    filled_cost   REAL NOT NULL DEFAULT 0
+);
+
+CREATE TABLE deferred_exits (
+    group_id           TEXT PRIMARY KEY,
+    group_type         INTEGER NOT NULL,
+    entry_index        INTEGER NOT NULL,
+    stop_price         REAL NOT NULL,
+    stop_percent       REAL NOT NULL,
+    stop_trail_amount  REAL NOT NULL,
+    stop_trail_percent REAL NOT NULL,
+    take_price         REAL NOT NULL,
+    take_percent       REAL NOT NULL,
+    take_trail_amount  REAL NOT NULL,
+    take_trail_percent REAL NOT NULL
+);
+
+CREATE TABLE seen_transactions (
+    id TEXT PRIMARY KEY
+);
+
+CREATE TABLE foreign_cash (
+    currency TEXT PRIMARY KEY,
+    amount   REAL NOT NULL
+);
+
+CREATE TABLE fx_forwards (
+    currency TEXT PRIMARY KEY,
+    notional REAL NOT NULL,
+    rate     REAL NOT NULL,
+    opened   TEXT NOT NULL
+);
+
+CREATE TABLE contracts (
+    figi               TEXT PRIMARY KEY,
+    asset_type         TEXT NOT NULL,
+    root               TEXT NOT NULL,
+    underlying         TEXT NOT NULL,
+    underlying_figi    TEXT NOT NULL,
+    strike             REAL NOT NULL,
+    option_right       TEXT NOT NULL,
+    multiplier         REAL NOT NULL,
+    tick_size          REAL NOT NULL,
+    expiry             TEXT NOT NULL,
+    initial_margin     REAL NOT NULL,
+    maintenance_margin REAL NOT NULL,
+    cash_settled       INTEGER NOT NULL
+);
+
+CREATE TABLE futures_marks (
+    figi  TEXT PRIMARY KEY,
+    price REAL NOT NULL
+);
 `
 
 // transactionTypeToString maps a TransactionType to its lowercase string
@@ -168,6 +293,10 @@ func transactionTypeToString(txnType asset.TransactionType) string {
 		return "interest"
 	case asset.JournalTransaction:
 		return "journal"
+	case asset.FXTransaction:
+		return "fx"
+	case asset.VariationMarginTransaction:
+		return "variation_margin"
 	default:
 		return fmt.Sprintf("unknown(%d)", int(txnType))
 	}
@@ -194,6 +323,10 @@ func stringToTransactionType(str string) (asset.TransactionType, error) {
 		return asset.InterestTransaction, nil
 	case "journal":
 		return asset.JournalTransaction, nil
+	case "fx":
+		return asset.FXTransaction, nil
+	case "variation_margin":
+		return asset.VariationMarginTransaction, nil
 	default:
 		return 0, fmt.Errorf("unknown transaction type: %q", str)
 	}
@@ -276,11 +409,40 @@ func (a *Account) ToSQLite(path string) error {
 		return err
 	}
 
+	if err := a.writeSkippedTrades(dbTx); err != nil {
+		return err
+	}
+
 	// Write prediction.
 	if err := a.writePrediction(dbTx); err != nil {
 		return err
 	}
 
+	// Write open orders and synced broker transaction IDs so a live
+	// session restored from this file can match late fills and skip
+	// already-applied broker activity.
+	if err := a.writePendingOrders(dbTx); err != nil {
+		return err
+	}
+
+	if err := a.writeDeferredExits(dbTx); err != nil {
+		return err
+	}
+
+	if err := a.writeSeenTransactions(dbTx); err != nil {
+		return err
+	}
+
+	// Write foreign-currency cash and open currency hedges.
+	if err := a.writeCurrencyState(dbTx); err != nil {
+		return err
+	}
+
+	// Write contract specifications and futures settlement prices.
+	if err := a.writeContracts(dbTx); err != nil {
+		return err
+	}
+
 	if err := dbTx.Commit(); err != nil {
 		return fmt.Errorf("commit: %w", err)
 	}
@@ -432,7 +594,7 @@ func (a *Account) writeTransactions(tx *sql.Tx) error {
 		return nil
 	}
 
-	stmt, err := tx.Prepare("INSERT INTO transactions (batch_id, date, type, ticker, figi, quantity, price, amount, qualified, justification) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	stmt, err := tx.Prepare("INSERT INTO transactions (batch_id, date, type, ticker, figi, quantity, price, amount, qualified, justification, order_id, currency, fx_rate) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
 		return fmt.Errorf("prepare transactions: %w", err)
 	}
@@ -454,6 +616,9 @@ func (a *Account) writeTransactions(tx *sql.Tx) error {
 			txn.Qty, txn.Price, txn.Amount,
 			qualified,
 			sql.NullString{String: txn.Justification, Valid: txn.Justification != ""},
+			sql.NullString{String: txn.OrderID, Valid: txn.OrderID != ""},
+			string(txn.Currency),
+			txn.FXRate,
 		); err != nil {
 			return fmt.Errorf("insert transaction: %w", err)
 		}
@@ -487,7 +652,7 @@ func (a *Account) writeHoldings(tx *sql.Tx) error {
 		return nil
 	}
 
-	stmt, err := tx.Prepare("INSERT INTO holdings (asset_ticker, asset_figi, quantity, avg_cost, market_value) VALUES (?, ?, ?, ?, ?)")
+	stmt, err := tx.Prepare("INSERT INTO holdings (asset_ticker, asset_figi, quantity, avg_cost, market_value, currency) VALUES (?, ?, ?, ?, ?, ?)")
 	if err != nil {
 		return fmt.Errorf("prepare holdings: %w", err)
 	}
@@ -513,14 +678,11 @@ func (a *Account) writeHoldings(tx *sql.Tx) error {
 		// Compute market value from latest prices.
 		var marketValue float64
 
-		if a.prices != nil {
-			v := a.prices.Value(ast, data.MetricClose)
-			if !math.IsNaN(v) {
-				marketValue = qty * v
-			}
+		if v := a.marketValue(ast, qty); !math.IsNaN(v) {
+			marketValue = v
 		}
 
-		if _, err := stmt.Exec(ast.Ticker, ast.CompositeFigi, qty, avgCost, marketValue); err != nil {
+		if _, err := stmt.Exec(ast.Ticker, ast.CompositeFigi, qty, avgCost, marketValue, string(ast.Currency)); err != nil {
 			return fmt.Errorf("insert holding: %w", err)
 		}
 	}
@@ -533,7 +695,7 @@ func (a *Account) writeTaxLots(tx *sql.Tx) error {
 		return nil
 	}
 
-	stmt, err := tx.Prepare("INSERT INTO tax_lots (asset_ticker, asset_figi, date, quantity, price, id) VALUES (?, ?, ?, ?, ?, ?)")
+	stmt, err := tx.Prepare("INSERT INTO tax_lots (asset_ticker, asset_figi, date, quantity, price, id, currency, fx_rate) VALUES (?, ?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
 		return fmt.Errorf("prepare tax_lots: %w", err)
 	}
@@ -542,7 +704,7 @@ func (a *Account) writeTaxLots(tx *sql.Tx) error {
 	for ast, lots := range a.taxLots {
 		for _, lot := range lots {
 			d := lot.Date.Format(dateFormat)
-			if _, err := stmt.Exec(ast.Ticker, ast.CompositeFigi, d, lot.Qty, lot.Price, lot.ID); err != nil {
+			if _, err := stmt.Exec(ast.Ticker, ast.CompositeFigi, d, lot.Qty, lot.Price, lot.ID, string(ast.Currency), lot.FXRate); err != nil {
 				return fmt.Errorf("insert tax_lot: %w", err)
 			}
 		}
@@ -592,6 +754,27 @@ func (a *Account) writeAnnotations(tx *sql.Tx) error {
 	return nil
 }
 
+func (a *Account) writeSkippedTrades(tx *sql.Tx) error {
+	if len(a.skippedTrades) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.Prepare("INSERT INTO skipped_trades (timestamp, asset_ticker, asset_figi, amount, reason) VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("prepare skipped_trades: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, trade := range a.skippedTrades {
+		if _, err := stmt.Exec(trade.Timestamp.UnixNano(), trade.Asset.Ticker, trade.Asset.CompositeFigi,
+			trade.Amount, trade.Reason); err != nil {
+			return fmt.Errorf("insert skipped trade: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (a *Account) writePrediction(tx *sql.Tx) error {
 	if a.prediction == nil {
 		return nil
@@ -796,8 +979,34 @@ func (a *Account) readAnnotations(db *sql.DB) error {
 	return rows.Err()
 }
 
+func (a *Account) readSkippedTrades(db *sql.DB) error {
+	rows, err := db.Query("SELECT timestamp, asset_ticker, asset_figi, amount, reason FROM skipped_trades ORDER BY rowid")
+	if err != nil {
+		return fmt.Errorf("query skipped_trades: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			nanos int64
+			trade SkippedTrade
+		)
+
+		if err := rows.Scan(&nanos, &trade.Asset.Ticker, &trade.Asset.CompositeFigi, &trade.Amount, &trade.Reason); err != nil {
+			return fmt.Errorf("scan skipped trade: %w", err)
+		}
+
+		trade.Timestamp = time.Unix(0, nanos).UTC()
+		a.skippedTrades = append(a.skippedTrades, trade)
+	}
+
+	return rows.Err()
+}
+
 // FromSQLite restores an Account from a SQLite database at the given path.
-// The database must have been created by ToSQLite with schema_version "7".
+// Databases written with an older schema version listed in
+// migratableVersions are read from an upgraded temporary copy, so the
+// file itself, such as a checkpoint being resumed, is left unchanged.
 // Fields that require a live broker or price DataFrame (broker, prices,
 // registeredMetrics) are not restored.
 func FromSQLite(path string) (*Account, error) {
@@ -823,6 +1032,7 @@ func FromSQLite(path string) (*Account, error) {
 		recentBuys:       make(map[asset.Asset][]recentBuy),
 		metadata:         make(map[string]string),
 		pendingOrders:    make(map[string]broker.Order),
+		orderFills:       make(map[string]orderFill),
 		pendingGroups:    make(map[string]*broker.OrderGroup),
 		deferredExits:    make(map[string]OrderGroupSpec),
 		substitutions:    make(map[asset.Asset]Substitution),
@@ -839,7 +1049,17 @@ func FromSQLite(path string) (*Account, error) {
 
 	// Verify schema version.
 	if ver := acct.metadata["schema_version"]; ver != schemaVersion {
-		return nil, fmt.Errorf("unsupported schema version: %q (expected %q)", ver, schemaVersion)
+		if !slices.Contains(migratableVersions, ver) {
+			return nil, fmt.Errorf("unsupported schema version: %q (expected %q)", ver, schemaVersion)
+		}
+
+		migrated, cleanup, err := migratedCopy(database)
+		if err != nil {
+			return nil, fmt.Errorf("migrate schema version %q to %q: %w", ver, schemaVersion, err)
+		}
+		defer cleanup()
+
+		database = migrated
 	}
 
 	// Restore cash from metadata.
@@ -902,14 +1122,130 @@ func FromSQLite(path string) (*Account, error) {
 		return nil, err
 	}
 
+	if err := acct.readSkippedTrades(database); err != nil {
+		return nil, err
+	}
+
 	// Read prediction.
 	if err := acct.readPrediction(database); err != nil {
 		return nil, err
 	}
 
+	// Read open orders and synced broker transaction IDs.
+	if err := acct.readPendingOrders(database); err != nil {
+		return nil, err
+	}
+
+	if err := acct.readDeferredExits(database); err != nil {
+		return nil, err
+	}
+
+	if err := acct.readSeenTransactions(database); err != nil {
+		return nil, err
+	}
+
+	// Read foreign-currency cash and open currency hedges.
+	if err := acct.readCurrencyState(database); err != nil {
+		return nil, err
+	}
+
+	// Reattach contract specifications and futures settlement prices.
+	if err := acct.readContracts(database); err != nil {
+		return nil, err
+	}
+
 	return acct, nil
 }
 
+// migratedCopy copies db into a temporary file and upgrades the copy
+// with migrateSchema. The returned cleanup closes the copy and removes
+// the file.
+func migratedCopy(db *sql.DB) (*sql.DB, func(), error) {
+	dir, err := os.MkdirTemp("", "pvbt-migrate-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("create temp dir: %w", err)
+	}
+
+	copyPath := filepath.Join(dir, "account.db")
+
+	if _, err := db.Exec("VACUUM INTO ?", copyPath); err != nil {
+		os.RemoveAll(dir)
+		return nil, nil, fmt.Errorf("copy database: %w", err)
+	}
+
+	copyDB, err := sql.Open("sqlite", copyPath)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, nil, fmt.Errorf("open copy: %w", err)
+	}
+
+	cleanup := func() {
+		copyDB.Close()
+		os.RemoveAll(dir)
+	}
+
+	if err := migrateSchema(copyDB); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	return copyDB, cleanup, nil
+}
+
+// migrateSchema upgrades a database written with an older schema
+// version: it creates the tables and indexes it lacks, adds missing
+// columns from addedColumns, and records the current version. The
+// upgrade runs in one transaction, so a failure leaves the file as it
+// was.
+func migrateSchema(db *sql.DB) error {
+	dbTx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if rollbackErr := dbTx.Rollback(); rollbackErr != nil {
+			_ = rollbackErr
+		}
+	}()
+
+	missingOnly := strings.NewReplacer(
+		"CREATE TABLE ", "CREATE TABLE IF NOT EXISTS ",
+		"CREATE INDEX ", "CREATE INDEX IF NOT EXISTS ",
+	).Replace(createSchema)
+
+	if _, err := dbTx.Exec(missingOnly); err != nil {
+		return fmt.Errorf("create missing tables: %w", err)
+	}
+
+	for _, added := range addedColumns {
+		var count int
+		if err := dbTx.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?",
+			added.table, added.column).Scan(&count); err != nil {
+			return fmt.Errorf("inspect %s.%s: %w", added.table, added.column, err)
+		}
+
+		if count > 0 {
+			continue
+		}
+
+		if _, err := dbTx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s",
+			added.table, added.column, added.definition)); err != nil {
+			return fmt.Errorf("add %s.%s: %w", added.table, added.column, err)
+		}
+	}
+
+	if _, err := dbTx.Exec("UPDATE metadata SET value = ? WHERE key = 'schema_version'", schemaVersion); err != nil {
+		return fmt.Errorf("update schema_version: %w", err)
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
 func (a *Account) readMetadata(db *sql.DB) error {
 	rows, err := db.Query("SELECT key, value FROM metadata")
 	if err != nil {
@@ -1037,7 +1373,7 @@ func (a *Account) readPerfData(db *sql.DB) error {
 }
 
 func (a *Account) readTransactions(db *sql.DB) error {
-	rows, err := db.Query("SELECT batch_id, date, type, ticker, figi, quantity, price, amount, qualified, justification FROM transactions ORDER BY date, batch_id")
+	rows, err := db.Query("SELECT batch_id, date, type, ticker, figi, quantity, price, amount, qualified, justification, order_id, currency, fx_rate FROM transactions ORDER BY date, batch_id")
 	if err != nil {
 		return fmt.Errorf("query transactions: %w", err)
 	}
@@ -1051,9 +1387,12 @@ func (a *Account) readTransactions(db *sql.DB) error {
 			qty, price, amount sql.NullFloat64
 			qualified          sql.NullInt64
 			justification      sql.NullString
+			orderID            sql.NullString
+			currency           string
+			fxRate             float64
 		)
 
-		if err := rows.Scan(&batchID, &dateStr, &typStr, &ticker, &figi, &qty, &price, &amount, &qualified, &justification); err != nil {
+		if err := rows.Scan(&batchID, &dateStr, &typStr, &ticker, &figi, &qty, &price, &amount, &qualified, &justification, &orderID, &currency, &fxRate); err != nil {
 			return fmt.Errorf("scan transaction: %w", err)
 		}
 
@@ -1075,12 +1414,18 @@ func (a *Account) readTransactions(db *sql.DB) error {
 			Price:     price.Float64,
 			Amount:    amount.Float64,
 			Qualified: qualified.Valid && qualified.Int64 == 1,
+			OrderID:   orderID.String,
+			Currency:  asset.Currency(currency),
+			FXRate:    fxRate,
 		}
 
+		// A transaction naming an asset is denominated in the asset's
+		// quote currency.
 		if ticker.Valid || figi.Valid {
 			txn.Asset = asset.Asset{
 				Ticker:        ticker.String,
 				CompositeFigi: figi.String,
+				Currency:      txn.Currency,
 			}
 		}
 
@@ -1094,6 +1439,440 @@ func (a *Account) readTransactions(db *sql.DB) error {
 	return rows.Err()
 }
 
+func (a *Account) writePendingOrders(tx *sql.Tx) error {
+	if len(a.pendingOrders) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO pending_orders (id, batch_id, ticker, figi, side, quantity, amount,
+		order_type, time_in_force, limit_price, stop_price, lot_selection, group_id, group_role, justification,
+		trail_amount, trail_percent, limit_offset, filled_qty, filled_cost)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("prepare pending_orders: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, order := range a.pendingOrders {
+		progress := a.orderFills[order.ID]
+
+		if _, err := stmt.Exec(
+			order.ID, order.BatchID,
+			order.Asset.Ticker, order.Asset.CompositeFigi,
+			int(order.Side), order.Qty, order.Amount,
+			int(order.OrderType), int(order.TimeInForce),
+			order.LimitPrice, order.StopPrice, order.LotSelection,
+			order.GroupID, int(order.GroupRole),
+			sql.NullString{String: order.Justification, Valid: order.Justification != ""},
+			order.TrailAmount, order.TrailPercent, order.LimitOffset,
+			progress.qty, progress.cost,
+		); err != nil {
+			return fmt.Errorf("insert pending order: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeDeferredExits stores the stop-loss and take-profit specs of
+// bracket orders whose entry has not filled yet, so a restored account
+// still submits the exits when the entry fills.
+func (a *Account) writeDeferredExits(tx *sql.Tx) error {
+	if len(a.deferredExits) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO deferred_exits (group_id, group_type, entry_index,
+		stop_price, stop_percent, stop_trail_amount, stop_trail_percent,
+		take_price, take_percent, take_trail_amount, take_trail_percent)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("prepare deferred_exits: %w", err)
+	}
+	defer stmt.Close()
+
+	for groupID, spec := range a.deferredExits {
+		if _, err := stmt.Exec(groupID, int(spec.Type), spec.EntryIndex,
+			spec.StopLoss.AbsolutePrice, spec.StopLoss.PercentOffset,
+			spec.StopLoss.Trail.Amount, spec.StopLoss.Trail.Percent,
+			spec.TakeProfit.AbsolutePrice, spec.TakeProfit.PercentOffset,
+			spec.TakeProfit.Trail.Amount, spec.TakeProfit.Trail.Percent,
+		); err != nil {
+			return fmt.Errorf("insert deferred exit: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (a *Account) writeSeenTransactions(tx *sql.Tx) error {
+	if len(a.seenTransactions) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.Prepare("INSERT INTO seen_transactions (id) VALUES (?)")
+	if err != nil {
+		return fmt.Errorf("prepare seen_transactions: %w", err)
+	}
+	defer stmt.Close()
+
+	for id := range a.seenTransactions {
+		if _, err := stmt.Exec(id); err != nil {
+			return fmt.Errorf("insert seen transaction: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (a *Account) readPendingOrders(db *sql.DB) error {
+	rows, err := db.Query(`SELECT id, batch_id, ticker, figi, side, quantity, amount, order_type, time_in_force,
+		limit_price, stop_price, lot_selection, group_id, group_role, justification,
+		trail_amount, trail_percent, limit_offset, filled_qty, filled_cost FROM pending_orders`)
+	if err != nil {
+		return fmt.Errorf("query pending_orders: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			order                                   broker.Order
+			ticker, figi                            string
+			side, orderType, timeInForce, groupRole int
+			justification                           sql.NullString
+			progress                                orderFill
+		)
+
+		if err := rows.Scan(&order.ID, &order.BatchID, &ticker, &figi, &side, &order.Qty, &order.Amount,
+			&orderType, &timeInForce, &order.LimitPrice, &order.StopPrice, &order.LotSelection,
+			&order.GroupID, &groupRole, &justification,
+			&order.TrailAmount, &order.TrailPercent, &order.LimitOffset,
+			&progress.qty, &progress.cost); err != nil {
+			return fmt.Errorf("scan pending order: %w", err)
+		}
+
+		order.Asset = asset.Asset{Ticker: ticker, CompositeFigi: figi}
+		order.Side = broker.Side(side)
+		order.OrderType = broker.OrderType(orderType)
+		order.TimeInForce = broker.TimeInForce(timeInForce)
+		order.GroupRole = broker.GroupRole(groupRole)
+		order.Status = broker.OrderSubmitted
+		order.Justification = justification.String
+
+		a.pendingOrders[order.ID] = order
+
+		if progress.qty > 0 {
+			a.orderFills[order.ID] = progress
+		}
+	}
+
+	return rows.Err()
+}
+
+func (a *Account) readDeferredExits(db *sql.DB) error {
+	rows, err := db.Query(`SELECT group_id, group_type, entry_index,
+		stop_price, stop_percent, stop_trail_amount, stop_trail_percent,
+		take_price, take_percent, take_trail_amount, take_trail_percent FROM deferred_exits`)
+	if err != nil {
+		return fmt.Errorf("query deferred_exits: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			spec      OrderGroupSpec
+			groupType int
+		)
+
+		if err := rows.Scan(&spec.GroupID, &groupType, &spec.EntryIndex,
+			&spec.StopLoss.AbsolutePrice, &spec.StopLoss.PercentOffset,
+			&spec.StopLoss.Trail.Amount, &spec.StopLoss.Trail.Percent,
+			&spec.TakeProfit.AbsolutePrice, &spec.TakeProfit.PercentOffset,
+			&spec.TakeProfit.Trail.Amount, &spec.TakeProfit.Trail.Percent); err != nil {
+			return fmt.Errorf("scan deferred exit: %w", err)
+		}
+
+		spec.Type = broker.GroupType(groupType)
+		a.deferredExits[spec.GroupID] = spec
+	}
+
+	return rows.Err()
+}
+
+func (a *Account) readSeenTransactions(db *sql.DB) error {
+	rows, err := db.Query("SELECT id FROM seen_transactions")
+	if err != nil {
+		return fmt.Errorf("query seen_transactions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return fmt.Errorf("scan seen transaction: %w", err)
+		}
+
+		a.seenTransactions[id] = struct{}{}
+	}
+
+	return rows.Err()
+}
+
+func (a *Account) writeCurrencyState(tx *sql.Tx) error {
+	cashStmt, err := tx.Prepare("INSERT INTO foreign_cash (currency, amount) VALUES (?, ?)")
+	if err != nil {
+		return fmt.Errorf("prepare foreign_cash: %w", err)
+	}
+	defer cashStmt.Close()
+
+	for cur, amount := range a.foreignCash {
+		if _, err := cashStmt.Exec(string(cur), amount); err != nil {
+			return fmt.Errorf("insert foreign cash: %w", err)
+		}
+	}
+
+	fwdStmt, err := tx.Prepare("INSERT INTO fx_forwards (currency, notional, rate, opened) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("prepare fx_forwards: %w", err)
+	}
+	defer fwdStmt.Close()
+
+	for cur, fwd := range a.fxForwards {
+		if _, err := fwdStmt.Exec(string(cur), fwd.Notional, fwd.Rate, fwd.Opened.Format(dateFormat)); err != nil {
+			return fmt.Errorf("insert fx forward: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (a *Account) readCurrencyState(db *sql.DB) error {
+	cashRows, err := db.Query("SELECT currency, amount FROM foreign_cash")
+	if err != nil {
+		return fmt.Errorf("query foreign_cash: %w", err)
+	}
+	defer cashRows.Close()
+
+	for cashRows.Next() {
+		var (
+			cur    string
+			amount float64
+		)
+
+		if err := cashRows.Scan(&cur, &amount); err != nil {
+			return fmt.Errorf("scan foreign cash: %w", err)
+		}
+
+		a.credit(asset.Currency(cur), amount)
+	}
+
+	if err := cashRows.Err(); err != nil {
+		return err
+	}
+
+	fwdRows, err := db.Query("SELECT currency, notional, rate, opened FROM fx_forwards")
+	if err != nil {
+		return fmt.Errorf("query fx_forwards: %w", err)
+	}
+	defer fwdRows.Close()
+
+	for fwdRows.Next() {
+		var (
+			cur, openedStr string
+			fwd            fxForward
+		)
+
+		if err := fwdRows.Scan(&cur, &fwd.Notional, &fwd.Rate, &openedStr); err != nil {
+			return fmt.Errorf("scan fx forward: %w", err)
+		}
+
+		fwd.Opened, err = time.Parse(dateFormat, openedStr)
+		if err != nil {
+			return fmt.Errorf("parse fx forward opened date: %w", err)
+		}
+
+		if a.fxForwards == nil {
+			a.fxForwards = make(map[asset.Currency]fxForward)
+		}
+
+		a.fxForwards[asset.Currency(cur)] = fwd
+	}
+
+	return fwdRows.Err()
+}
+
+func (a *Account) writeContracts(tx *sql.Tx) error {
+	contracts := make(map[string]asset.Asset)
+
+	collect := func(ast asset.Asset) {
+		if ast.Contract != (asset.Contract{}) {
+			contracts[ast.CompositeFigi] = ast
+		}
+	}
+
+	for _, txn := range a.transactions {
+		collect(txn.Asset)
+	}
+
+	for ast := range a.holdings {
+		collect(ast)
+	}
+
+	for _, order := range a.pendingOrders {
+		collect(order.Asset)
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO contracts (figi, asset_type, root, underlying, underlying_figi, strike,
+		option_right, multiplier, tick_size, expiry, initial_margin, maintenance_margin, cash_settled)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("prepare contracts: %w", err)
+	}
+	defer stmt.Close()
+
+	for figi, ast := range contracts {
+		spec := ast.Contract
+
+		var expiry string
+		if !spec.Expiry.IsZero() {
+			expiry = spec.Expiry.Format(dateFormat)
+		}
+
+		if _, err := stmt.Exec(figi, string(ast.AssetType), spec.Root, spec.Underlying, spec.UnderlyingFigi, spec.Strike,
+			string(spec.Right), spec.Multiplier, spec.TickSize, expiry, spec.InitialMargin, spec.MaintenanceMargin,
+			spec.CashSettled); err != nil {
+			return fmt.Errorf("insert contract: %w", err)
+		}
+	}
+
+	markStmt, err := tx.Prepare("INSERT INTO futures_marks (figi, price) VALUES (?, ?)")
+	if err != nil {
+		return fmt.Errorf("prepare futures_marks: %w", err)
+	}
+	defer markStmt.Close()
+
+	for ast, price := range a.futuresMarks {
+		if _, err := markStmt.Exec(ast.CompositeFigi, price); err != nil {
+			return fmt.Errorf("insert futures mark: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// readContracts restores the asset type and contract specification of
+// every derivative asset read from the other tables, then the futures
+// settlement prices. It must run after the tables holding assets are
+// read.
+func (a *Account) readContracts(db *sql.DB) error {
+	rows, err := db.Query(`SELECT figi, asset_type, root, underlying, underlying_figi, strike, option_right,
+		multiplier, tick_size, expiry, initial_margin, maintenance_margin, cash_settled FROM contracts`)
+	if err != nil {
+		return fmt.Errorf("query contracts: %w", err)
+	}
+	defer rows.Close()
+
+	type contractRow struct {
+		assetType asset.AssetType
+		spec      asset.Contract
+	}
+
+	contracts := make(map[string]contractRow)
+
+	for rows.Next() {
+		var (
+			figi, assetType, right, expiry string
+			row                            contractRow
+		)
+
+		if err := rows.Scan(&figi, &assetType, &row.spec.Root, &row.spec.Underlying, &row.spec.UnderlyingFigi,
+			&row.spec.Strike, &right, &row.spec.Multiplier, &row.spec.TickSize, &expiry,
+			&row.spec.InitialMargin, &row.spec.MaintenanceMargin, &row.spec.CashSettled); err != nil {
+			return fmt.Errorf("scan contract: %w", err)
+		}
+
+		if expiry != "" {
+			row.spec.Expiry, err = time.Parse(dateFormat, expiry)
+			if err != nil {
+				return fmt.Errorf("parse contract expiry: %w", err)
+			}
+		}
+
+		row.spec.Right = asset.OptionRight(right)
+		row.assetType = asset.AssetType(assetType)
+		contracts[figi] = row
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	restore := func(ast asset.Asset) asset.Asset {
+		if row, ok := contracts[ast.CompositeFigi]; ok {
+			ast.AssetType = row.assetType
+			ast.Contract = row.spec
+		}
+
+		return ast
+	}
+
+	if len(contracts) > 0 {
+		for idx := range a.transactions {
+			a.transactions[idx].Asset = restore(a.transactions[idx].Asset)
+		}
+
+		holdings := make(map[asset.Asset]float64, len(a.holdings))
+		for ast, qty := range a.holdings {
+			holdings[restore(ast)] = qty
+		}
+
+		a.holdings = holdings
+
+		taxLots := make(map[asset.Asset][]TaxLot, len(a.taxLots))
+		for ast, lots := range a.taxLots {
+			taxLots[restore(ast)] = lots
+		}
+
+		a.taxLots = taxLots
+
+		for id, order := range a.pendingOrders {
+			order.Asset = restore(order.Asset)
+			a.pendingOrders[id] = order
+		}
+	}
+
+	markRows, err := db.Query("SELECT figi, price FROM futures_marks")
+	if err != nil {
+		return fmt.Errorf("query futures_marks: %w", err)
+	}
+	defer markRows.Close()
+
+	for markRows.Next() {
+		var (
+			figi  string
+			price float64
+		)
+
+		if err := markRows.Scan(&figi, &price); err != nil {
+			return fmt.Errorf("scan futures mark: %w", err)
+		}
+
+		for ast := range a.holdings {
+			if ast.CompositeFigi != figi {
+				continue
+			}
+
+			if a.futuresMarks == nil {
+				a.futuresMarks = make(map[asset.Asset]float64)
+			}
+
+			a.futuresMarks[ast] = price
+		}
+	}
+
+	return markRows.Err()
+}
+
 func (a *Account) readBatches(db *sql.DB) error {
 	rows, err := db.Query("SELECT batch_id, timestamp FROM batches ORDER BY batch_id")
 	if err != nil {
@@ -1118,7 +1897,7 @@ func (a *Account) readBatches(db *sql.DB) error {
 }
 
 func (a *Account) readHoldings(db *sql.DB) error {
-	rows, err := db.Query("SELECT asset_ticker, asset_figi, quantity FROM holdings")
+	rows, err := db.Query("SELECT asset_ticker, asset_figi, quantity, currency FROM holdings")
 	if err != nil {
 		return fmt.Errorf("query holdings: %w", err)
 	}
@@ -1126,15 +1905,15 @@ func (a *Account) readHoldings(db *sql.DB) error {
 
 	for rows.Next() {
 		var (
-			ticker, figi string
-			qty          float64
+			ticker, figi, currency string
+			qty                    float64
 		)
 
-		if err := rows.Scan(&ticker, &figi, &qty); err != nil {
+		if err := rows.Scan(&ticker, &figi, &qty, &currency); err != nil {
 			return fmt.Errorf("scan holding: %w", err)
 		}
 
-		ast := asset.Asset{Ticker: ticker, CompositeFigi: figi}
+		ast := asset.Asset{Ticker: ticker, CompositeFigi: figi, Currency: asset.Currency(currency)}
 		a.holdings[ast] = qty
 	}
 
@@ -1142,7 +1921,7 @@ func (a *Account) readHoldings(db *sql.DB) error {
 }
 
 func (a *Account) readTaxLots(db *sql.DB) error {
-	rows, err := db.Query("SELECT asset_ticker, asset_figi, date, quantity, price, id FROM tax_lots ORDER BY date")
+	rows, err := db.Query("SELECT asset_ticker, asset_figi, date, quantity, price, id, currency, fx_rate FROM tax_lots ORDER BY date")
 	if err != nil {
 		return fmt.Errorf("query tax_lots: %w", err)
 	}
@@ -1150,11 +1929,11 @@ func (a *Account) readTaxLots(db *sql.DB) error {
 
 	for rows.Next() {
 		var (
-			ticker, figi, dateStr, lotID string
-			qty, price                   float64
+			ticker, figi, dateStr, lotID, currency string
+			qty, price, fxRate                     float64
 		)
 
-		if err := rows.Scan(&ticker, &figi, &dateStr, &qty, &price, &lotID); err != nil {
+		if err := rows.Scan(&ticker, &figi, &dateStr, &qty, &price, &lotID, &currency, &fxRate); err != nil {
 			return fmt.Errorf("scan tax_lot: %w", err)
 		}
 
@@ -1163,12 +1942,13 @@ func (a *Account) readTaxLots(db *sql.DB) error {
 			return fmt.Errorf("parse tax_lot date: %w", err)
 		}
 
-		ast := asset.Asset{Ticker: ticker, CompositeFigi: figi}
+		ast := asset.Asset{Ticker: ticker, CompositeFigi: figi, Currency: asset.Currency(currency)}
 		a.taxLots[ast] = append(a.taxLots[ast], TaxLot{
-			ID:    lotID,
-			Date:  parsedTime,
-			Qty:   qty,
-			Price: price,
+			ID:     lotID,
+			Date:   parsedTime,
+			Qty:    qty,
+			Price:  price,
+			FXRate: fxRate,
 		})
 	}
 
@@ -1,6 +1,7 @@
 package portfolio_test
 
 import (
+	"context"
 	"database/sql"
 	"math"
 	"os"
@@ -11,6 +12,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
 	"github.com/penny-vault/pvbt/data"
 	"github.com/penny-vault/pvbt/portfolio"
 	_ "modernc.org/sqlite"
@@ -266,6 +268,186 @@ var _ = Describe("SQLite", func() {
 			Expect(txns[2].Justification).To(BeEmpty())
 		})
 
+		It("round-trips pending orders and synced broker transaction IDs", func() {
+			spy := asset.Asset{Ticker: "SPY", CompositeFigi: "BBG000BHTMY2"}
+
+			acct := portfolio.New(portfolio.WithCash(10_000, time.Time{}))
+			acct.SetPendingOrder(broker.Order{
+				ID:            "order-1",
+				Asset:         spy,
+				Side:          broker.Buy,
+				Qty:           10,
+				OrderType:     broker.Limit,
+				LimitPrice:    495,
+				TimeInForce:   broker.GTC,
+				BatchID:       3,
+				Justification: "entry",
+			})
+
+			divTxn := broker.Transaction{
+				ID:     "div-1",
+				Date:   time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+				Asset:  spy,
+				Type:   asset.DividendTransaction,
+				Amount: 12.5,
+			}
+			Expect(acct.SyncTransactions([]broker.Transaction{divTxn})).To(Succeed())
+
+			path := filepath.Join(tmpDir, "pending.db")
+			Expect(acct.ToSQLite(path)).To(Succeed())
+
+			restored, err := portfolio.FromSQLite(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			// A re-synced transaction is deduplicated against the restored IDs.
+			Expect(restored.SyncTransactions([]broker.Transaction{divTxn})).To(Succeed())
+			Expect(restored.Cash()).To(Equal(10_012.5))
+
+			// The restored pending order matches a replayed fill; the
+			// cumulative quantity is capped at the order's remaining size.
+			restored.ReplayFills([]broker.Fill{
+				{OrderID: "order-1", Price: 494, Qty: 12, FilledAt: time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC)},
+				{OrderID: "unknown", Price: 1, Qty: 1},
+			})
+
+			Expect(restored.Position(spy)).To(Equal(10.0))
+			Expect(restored.Cash()).To(BeNumerically("~", 10_012.5-4_940, 1e-9))
+
+			txns := restored.Transactions()
+			last := txns[len(txns)-1]
+			Expect(last.Type).To(Equal(asset.BuyTransaction))
+			Expect(last.BatchID).To(Equal(3))
+			Expect(last.Justification).To(Equal("entry"))
+		})
+
+		It("applies only the increment of cumulative fills replayed after a restore", func() {
+			spy := asset.Asset{Ticker: "SPY", CompositeFigi: "BBG000BHTMY2"}
+			filledAt := time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC)
+
+			acct := portfolio.New(portfolio.WithCash(10_000, time.Time{}))
+			acct.SetPendingOrder(broker.Order{
+				ID: "order-1", Asset: spy, Side: broker.Buy, Qty: 100,
+				OrderType: broker.Market, TimeInForce: broker.GTC,
+			})
+
+			// 60 of 100 shares filled before the checkpoint.
+			acct.ReplayFills([]broker.Fill{{OrderID: "order-1", Price: 10, Qty: 60, FilledAt: filledAt}})
+			Expect(acct.Position(spy)).To(Equal(60.0))
+
+			path := filepath.Join(tmpDir, "partial.db")
+			Expect(acct.ToSQLite(path)).To(Succeed())
+
+			restored, err := portfolio.FromSQLite(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			// The broker still reports the same cumulative 60 shares.
+			restored.ReplayFills([]broker.Fill{{OrderID: "order-1", Price: 10, Qty: 60, FilledAt: filledAt}})
+			Expect(restored.Position(spy)).To(Equal(60.0))
+			Expect(restored.Cash()).To(BeNumerically("~", 9_400, 1e-9))
+
+			// Cumulative 80 at an average of 10.50: 20 more shares at 12.
+			restored.ReplayFills([]broker.Fill{{OrderID: "order-1", Price: 10.5, Qty: 80, FilledAt: filledAt}})
+			Expect(restored.Position(spy)).To(Equal(80.0))
+			Expect(restored.Cash()).To(BeNumerically("~", 9_160, 1e-9))
+
+			txns := restored.Transactions()
+			Expect(txns[len(txns)-1].Qty).To(Equal(20.0))
+			Expect(txns[len(txns)-1].Price).To(BeNumerically("~", 12, 1e-9))
+		})
+
+		It("submits deferred bracket exits when a restored entry fills", func() {
+			spy := asset.Asset{Ticker: "SPY", CompositeFigi: "BBG000BHTMY2"}
+			date := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+			mb := newMockBroker()
+			mb.submitFn = func(_ broker.Order) error { return nil }
+
+			acct := portfolio.New(portfolio.WithCash(10_000, date), portfolio.WithBroker(mb))
+			acct.UpdatePrices(buildDF(date, []asset.Asset{spy}, []float64{100}, []float64{100}))
+
+			batch := acct.NewBatch(date)
+			Expect(batch.Order(context.Background(), spy, portfolio.Buy, 10,
+				portfolio.WithBracket(
+					portfolio.TrailingStopLoss(portfolio.TrailAmount(2)),
+					portfolio.TakeProfitPrice(115),
+				))).To(Succeed())
+			Expect(acct.ExecuteBatch(context.Background(), batch)).To(Succeed())
+
+			entryIDs := acct.PendingOrderIDs()
+			Expect(entryIDs).To(HaveLen(1))
+
+			path := filepath.Join(tmpDir, "deferred-exits.db")
+			Expect(acct.ToSQLite(path)).To(Succeed())
+
+			restored, err := portfolio.FromSQLite(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			resumed := newMockBroker()
+			resumed.submitFn = func(_ broker.Order) error { return nil }
+			restored.SetBroker(resumed)
+
+			restored.ReplayFills([]broker.Fill{{OrderID: entryIDs[0], Price: 100, Qty: 10, FilledAt: date}})
+			Expect(restored.Position(spy)).To(Equal(10.0))
+
+			Expect(resumed.submitted).To(HaveLen(2))
+			Expect(resumed.submitted[0].OrderType).To(Equal(broker.TrailingStop))
+			Expect(resumed.submitted[0].TrailAmount).To(Equal(2.0))
+			Expect(resumed.submitted[0].StopPrice).To(BeNumerically("~", 98, 1e-9))
+			Expect(resumed.submitted[1].OrderType).To(Equal(broker.Limit))
+			Expect(resumed.submitted[1].LimitPrice).To(Equal(115.0))
+		})
+
+		It("round-trips skipped rebalance trades", func() {
+			spy := asset.Asset{Ticker: "SPY", CompositeFigi: "BBG000BHTMY2"}
+			date := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+			acct := portfolio.New(portfolio.WithCash(10_000, date))
+			acct.UpdatePrices(buildDF(date, []asset.Asset{spy}, []float64{100}, []float64{100}))
+
+			// A 1% target is a $100 trade, below the $150 minimum.
+			batch := acct.NewBatch(date)
+			Expect(batch.RebalanceWithin(context.Background(),
+				portfolio.Allocation{Date: date, Members: map[asset.Asset]float64{spy: 0.01}},
+				portfolio.MinTradeNotional(150))).To(Succeed())
+			Expect(acct.ExecuteBatch(context.Background(), batch)).To(Succeed())
+
+			path := filepath.Join(tmpDir, "skipped.db")
+			Expect(acct.ToSQLite(path)).To(Succeed())
+
+			restored, err := portfolio.FromSQLite(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restored.SkippedTrades()).To(Equal(acct.SkippedTrades()))
+
+			metrics, _ := restored.TradeMetrics()
+			Expect(metrics.SkippedTrades).To(Equal(1.0))
+			Expect(metrics.SkippedNotional).To(BeNumerically("~", 100.0, 0.01))
+		})
+
+		It("round-trips the order a transaction belongs to", func() {
+			spy := asset.Asset{Ticker: "SPY", CompositeFigi: "BBG000BHTMY2"}
+			date := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+			acct := portfolio.New(portfolio.WithCash(10_000, date))
+			acct.Record(portfolio.Transaction{
+				Date:          date,
+				Asset:         spy,
+				Type:          asset.FeeTransaction,
+				Amount:        -1.0,
+				Justification: "commission",
+				OrderID:       "order-7",
+			})
+
+			path := filepath.Join(tmpDir, "order-id.db")
+			Expect(acct.ToSQLite(path)).To(Succeed())
+
+			restored, err := portfolio.FromSQLite(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			txns := restored.Transactions()
+			Expect(txns[len(txns)-1].OrderID).To(Equal("order-7"))
+			Expect(txns[0].OrderID).To(BeEmpty())
+		})
+
 		It("round-trips perfData frequency", func() {
 			spy := asset.Asset{Ticker: "SPY", CompositeFigi: "BBG000BHTMY2"}
 
@@ -469,6 +651,65 @@ var _ = Describe("SQLite", func() {
 		})
 	})
 
+	Describe("schema migration", func() {
+		It("upgrades a version 7 database written before later columns and tables existed", func() {
+			spy := asset.Asset{Ticker: "SPY", CompositeFigi: "BBG000BHTMY2"}
+			date := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+			acct := portfolio.New(portfolio.WithCash(10_000, date))
+			acct.Record(portfolio.Transaction{Date: date, Asset: spy, Type: asset.BuyTransaction, Qty: 10, Price: 500, Amount: -5_000})
+
+			dbPath := filepath.Join(tmpDir, "v7.db")
+			Expect(acct.ToSQLite(dbPath)).To(Succeed())
+
+			// Rewrite the file into the shape version 7 had.
+			db, err := sql.Open("sqlite", dbPath)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, stmt := range []string{
+				`ALTER TABLE transactions DROP COLUMN order_id`,
+				`ALTER TABLE transactions DROP COLUMN currency`,
+				`ALTER TABLE holdings DROP COLUMN currency`,
+				`ALTER TABLE tax_lots DROP COLUMN currency`,
+				`ALTER TABLE transactions DROP COLUMN fx_rate`,
+				`ALTER TABLE tax_lots DROP COLUMN fx_rate`,
+				`DROP TABLE pending_orders`,
+				`DROP TABLE seen_transactions`,
+				`DROP TABLE foreign_cash`,
+				`DROP TABLE fx_forwards`,
+				`DROP TABLE contracts`,
+				`DROP TABLE futures_marks`,
+				`DROP TABLE deferred_exits`,
+				`DROP TABLE skipped_trades`,
+				`UPDATE metadata SET value = '7' WHERE key = 'schema_version'`,
+			} {
+				_, err = db.Exec(stmt)
+				Expect(err).NotTo(HaveOccurred(), stmt)
+			}
+
+			db.Close()
+
+			restored, err := portfolio.FromSQLite(dbPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restored.Position(spy)).To(Equal(10.0))
+			Expect(restored.Cash()).To(Equal(5_000.0))
+			Expect(restored.UnrealizedLots(spy)).To(HaveLen(1))
+
+			// The upgrade ran on a copy; the file still carries version 7.
+			db, err = sql.Open("sqlite", dbPath)
+			Expect(err).NotTo(HaveOccurred())
+			defer db.Close()
+
+			var schemaVer string
+			Expect(db.QueryRow(`SELECT value FROM metadata WHERE key='schema_version'`).Scan(&schemaVer)).To(Succeed())
+			Expect(schemaVer).To(Equal("7"))
+
+			var tables int
+			Expect(db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name='pending_orders'`).Scan(&tables)).To(Succeed())
+			Expect(tables).To(BeZero())
+		})
+	})
+
 	Describe("positions_daily", func() {
 		It("writes one row per (date, ticker) with $CASH included and bumps schema to 6", func() {
 			spy := asset.Asset{Ticker: "SPY", CompositeFigi: "BBG000BHTMY2"}
@@ -495,7 +736,7 @@ var _ = Describe("SQLite", func() {
 
 			var schemaVer string
 			Expect(db.QueryRow(`SELECT value FROM metadata WHERE key='schema_version'`).Scan(&schemaVer)).To(Succeed())
-			Expect(schemaVer).To(Equal("7"))
+			Expect(schemaVer).To(Equal("18"))
 
 			var total int
 			Expect(db.QueryRow(`SELECT COUNT(*) FROM positions_daily`).Scan(&total)).To(Succeed())
@@ -37,4 +37,6 @@ type TradeMetrics struct {
 	ShortWinRate         float64 // win rate for short trades only
 	LongProfitFactor     float64 // profit factor for long trades only
 	ShortProfitFactor    float64 // profit factor for short trades only
+	SkippedTrades        float64 // rebalance trades skipped by RebalanceWithin bands or minimums
+	SkippedNotional      float64 // dollar value of the skipped rebalance trades
 }
@@ -76,4 +76,29 @@ type Transaction struct {
 	// (deposits, withdrawals, stock splits, manual Record calls).
 	// Batch IDs start at 1 and increment monotonically within an Account.
 	BatchID int
+
+	// OrderID is the broker order that produced this transaction. Set on
+	// trades recorded from fills and on the commissions and fees charged
+	// for them, so fees can be traced back to the order. Empty otherwise.
+	OrderID string
+
+	// Currency is the currency Amount is denominated in. Record fills it
+	// in from the asset's quote currency for trades, dividends, and fees
+	// on foreign-currency assets. Empty means the base currency.
+	Currency asset.Currency
+
+	// FXRate is the base-currency value of one unit of Currency on Date.
+	// Record fills it in from the account's exchange rates when Currency
+	// is set. Zero for base-currency transactions.
+	FXRate float64
+}
+
+// baseRate returns the multiplier that converts the transaction's price
+// and amount to the base currency.
+func (txn Transaction) baseRate() float64 {
+	if txn.FXRate == 0 {
+		return 1
+	}
+
+	return txn.FXRate
 }
@@ -107,7 +107,7 @@ func (vp *viewedPortfolio) TaxMetrics() (TaxMetrics, error) {
 }
 
 func (vp *viewedPortfolio) TradeMetrics() (TradeMetrics, error) {
-	return tradeMetricsFrom(vp)
+	return tradeMetricsFrom(vp, vp.acct.SkippedTrades(), vp.start, vp.end)
 }
 
 func (vp *viewedPortfolio) WithdrawalMetrics() (WithdrawalMetrics, error) {
@@ -143,11 +143,19 @@ func (vp *viewedPortfolio) MaxLeverage() float64                  { return vp.ac
 func (vp *viewedPortfolio) GrossMaintenanceLeverage() float64 {
 	return vp.acct.GrossMaintenanceLeverage()
 }
-func (vp *viewedPortfolio) LeverageHeadroom() float64     { return vp.acct.LeverageHeadroom() }
-func (vp *viewedPortfolio) Benchmark() asset.Asset        { return vp.acct.Benchmark() }
-func (vp *viewedPortfolio) Prediction() *Prediction       { return vp.acct.Prediction() }
-func (vp *viewedPortfolio) SetMetadata(key, value string) { vp.acct.SetMetadata(key, value) }
-func (vp *viewedPortfolio) GetMetadata(key string) string { return vp.acct.GetMetadata(key) }
+func (vp *viewedPortfolio) LeverageHeadroom() float64         { return vp.acct.LeverageHeadroom() }
+func (vp *viewedPortfolio) Benchmark() asset.Asset            { return vp.acct.Benchmark() }
+func (vp *viewedPortfolio) Prediction() *Prediction           { return vp.acct.Prediction() }
+func (vp *viewedPortfolio) SetMetadata(key, value string)     { vp.acct.SetMetadata(key, value) }
+func (vp *viewedPortfolio) GetMetadata(key string) string     { return vp.acct.GetMetadata(key) }
+func (vp *viewedPortfolio) FXRate(cur asset.Currency) float64 { return vp.acct.FXRate(cur) }
+func (vp *viewedPortfolio) CashBalances() map[asset.Currency]float64 {
+	return vp.acct.CashBalances()
+}
+func (vp *viewedPortfolio) FuturesNotional() float64 { return vp.acct.FuturesNotional() }
+func (vp *viewedPortfolio) FuturesMargin() (initial, maintenance float64) {
+	return vp.acct.FuturesMargin()
+}
 
 // Prices returns the windowed price DataFrame.
 func (vp *viewedPortfolio) Prices() *data.DataFrame {
@@ -0,0 +1,145 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signal
+
+import (
+	"math"
+
+	"github.com/penny-vault/pvbt/asset"
+)
+
+// Greeks holds a Black-Scholes option price and its sensitivities, all
+// per unit of the underlying. Theta is per calendar day, and Vega and Rho
+// are per percentage point of volatility and interest rate.
+type Greeks struct {
+	Price float64
+	Delta float64
+	Gamma float64
+	Theta float64
+	Vega  float64
+	Rho   float64
+}
+
+// BlackScholes prices a European option on a non-dividend-paying
+// underlying. years is the time to expiry in years, and rate and vol are
+// the continuously compounded risk-free rate and annualized volatility as
+// decimals. At or after expiry the price is the intrinsic value and the
+// sensitivities other than delta are zero. Every field is NaN when an
+// input is invalid.
+func BlackScholes(right asset.OptionRight, spot, strike, years, rate, vol float64) Greeks {
+	nan := math.NaN()
+	invalid := Greeks{Price: nan, Delta: nan, Gamma: nan, Theta: nan, Vega: nan, Rho: nan}
+
+	if spot <= 0 || strike <= 0 || vol < 0 || math.IsNaN(years+rate+vol) {
+		return invalid
+	}
+
+	if right != asset.Call && right != asset.Put {
+		return invalid
+	}
+
+	if years <= 0 || vol == 0 {
+		spec := asset.Contract{Right: right, Strike: strike}
+		greeks := Greeks{Price: spec.Intrinsic(spot)}
+
+		if greeks.Price > 0 {
+			greeks.Delta = 1
+			if right == asset.Put {
+				greeks.Delta = -1
+			}
+		}
+
+		return greeks
+	}
+
+	sqrtT := math.Sqrt(years)
+	d1 := (math.Log(spot/strike) + (rate+vol*vol/2)*years) / (vol * sqrtT)
+	d2 := d1 - vol*sqrtT
+	discount := math.Exp(-rate * years)
+	density := normPDF(d1)
+
+	greeks := Greeks{
+		Gamma: density / (spot * vol * sqrtT),
+		Vega:  spot * density * sqrtT / 100,
+	}
+
+	decay := -spot * density * vol / (2 * sqrtT)
+
+	if right == asset.Call {
+		greeks.Price = spot*normCDF(d1) - strike*discount*normCDF(d2)
+		greeks.Delta = normCDF(d1)
+		greeks.Theta = (decay - rate*strike*discount*normCDF(d2)) / 365
+		greeks.Rho = strike * years * discount * normCDF(d2) / 100
+	} else {
+		greeks.Price = strike*discount*normCDF(-d2) - spot*normCDF(-d1)
+		greeks.Delta = normCDF(d1) - 1
+		greeks.Theta = (decay + rate*strike*discount*normCDF(-d2)) / 365
+		greeks.Rho = -strike * years * discount * normCDF(-d2) / 100
+	}
+
+	return greeks
+}
+
+// BlackScholesImpliedVol returns the volatility at which BlackScholes
+// prices the option at price, found by bisection between 0.01% and 500%.
+// It is NaN when price lies outside the no-arbitrage bounds or the
+// search does not converge.
+func BlackScholesImpliedVol(right asset.OptionRight, price, spot, strike, years, rate float64) float64 {
+	const (
+		lowVol     = 1e-4
+		highVol    = 5.0
+		tolerance  = 1e-8
+		iterations = 200
+	)
+
+	if price <= 0 || years <= 0 || math.IsNaN(price) {
+		return math.NaN()
+	}
+
+	low := BlackScholes(right, spot, strike, years, rate, lowVol).Price
+	high := BlackScholes(right, spot, strike, years, rate, highVol).Price
+
+	if math.IsNaN(low) || price < low || price > high {
+		return math.NaN()
+	}
+
+	lower, upper := lowVol, highVol
+
+	for range iterations {
+		mid := (lower + upper) / 2
+		if BlackScholes(right, spot, strike, years, rate, mid).Price < price {
+			lower = mid
+		} else {
+			upper = mid
+		}
+
+		if upper-lower < tolerance {
+			return (lower + upper) / 2
+		}
+	}
+
+	return math.NaN()
+}
+
+// normCDF is the standard normal cumulative distribution function.
+func normCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// normPDF is the standard normal probability density function.
+func normPDF(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
@@ -0,0 +1,83 @@
+package signal_test
+
+import (
+	"context"
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/signal"
+	"github.com/penny-vault/pvbt/universe"
+)
+
+var _ = Describe("BlackScholes", func() {
+	It("matches reference prices and greeks", func() {
+		call := signal.BlackScholes(asset.Call, 100, 100, 1, 0.05, 0.2)
+		Expect(call.Price).To(BeNumerically("~", 10.4506, 1e-4))
+		Expect(call.Delta).To(BeNumerically("~", 0.6368, 1e-4))
+		Expect(call.Gamma).To(BeNumerically("~", 0.018762, 1e-6))
+		Expect(call.Vega).To(BeNumerically("~", 0.37524, 1e-5))
+		Expect(call.Theta).To(BeNumerically("~", -6.4140/365, 1e-6))
+		Expect(call.Rho).To(BeNumerically("~", 0.53232, 1e-5))
+
+		put := signal.BlackScholes(asset.Put, 100, 100, 1, 0.05, 0.2)
+		Expect(put.Price).To(BeNumerically("~", 5.5735, 1e-4))
+		Expect(put.Delta).To(BeNumerically("~", -0.3632, 1e-4))
+		Expect(put.Gamma).To(Equal(call.Gamma))
+
+		// Put-call parity.
+		Expect(call.Price - put.Price).To(BeNumerically("~", 100-100*math.Exp(-0.05), 1e-9))
+	})
+
+	It("returns intrinsic value at expiry", func() {
+		greeks := signal.BlackScholes(asset.Put, 90, 100, 0, 0.05, 0.2)
+		Expect(greeks.Price).To(Equal(10.0))
+		Expect(greeks.Delta).To(Equal(-1.0))
+		Expect(greeks.Gamma).To(Equal(0.0))
+	})
+
+	It("returns NaN for invalid inputs", func() {
+		Expect(math.IsNaN(signal.BlackScholes(asset.Call, -1, 100, 1, 0.05, 0.2).Price)).To(BeTrue())
+		Expect(math.IsNaN(signal.BlackScholes("", 100, 100, 1, 0.05, 0.2).Price)).To(BeTrue())
+	})
+
+	It("inverts prices to implied volatility", func() {
+		for _, vol := range []float64{0.05, 0.2, 0.8} {
+			price := signal.BlackScholes(asset.Put, 100, 110, 0.5, 0.03, vol).Price
+			Expect(signal.BlackScholesImpliedVol(asset.Put, price, 100, 110, 0.5, 0.03)).To(BeNumerically("~", vol, 1e-6))
+		}
+
+		// Below intrinsic value there is no implied volatility.
+		Expect(math.IsNaN(signal.BlackScholesImpliedVol(asset.Call, 1, 120, 100, 1, 0.05))).To(BeTrue())
+	})
+})
+
+var _ = Describe("OptionGreeks", func() {
+	It("computes implied volatility and greeks from option and underlying closes", func() {
+		now := time.Date(2024, 6, 20, 16, 0, 0, 0, time.UTC)
+		underlying := asset.Asset{CompositeFigi: "FIGI-XYZ", Ticker: "XYZ"}
+		option := asset.NewOption(underlying, asset.Call, 100, time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC), 0)
+		price := signal.BlackScholes(asset.Call, 100, 100, 1, 0.05, 0.2).Price
+
+		optionDF, err := data.NewDataFrame([]time.Time{now}, []asset.Asset{option}, []data.Metric{data.MetricClose}, data.Daily, [][]float64{{price}})
+		Expect(err).NotTo(HaveOccurred())
+
+		underlyingDF, err := data.NewDataFrame([]time.Time{now}, []asset.Asset{underlying}, []data.Metric{data.MetricClose}, data.Daily, [][]float64{{100}})
+		Expect(err).NotTo(HaveOccurred())
+
+		options := universe.NewStaticWithSource([]asset.Asset{option}, &mockDataSource{currentDate: now, fetchResult: optionDF})
+		underlyings := universe.NewStaticWithSource([]asset.Asset{underlying}, &mockDataSource{currentDate: now, fetchResult: underlyingDF})
+
+		result := signal.OptionGreeks(context.Background(), options, underlyings, 0.05)
+		Expect(result.Err()).NotTo(HaveOccurred())
+		Expect(result.Value(option, signal.ImpliedVolSignal)).To(BeNumerically("~", 0.2, 1e-6))
+		Expect(result.Value(option, signal.DeltaSignal)).To(BeNumerically("~", 0.6368, 1e-4))
+
+		iv := signal.ImpliedVolatility(context.Background(), options, underlyings, 0.05)
+		Expect(iv.MetricList()).To(Equal([]data.Metric{signal.ImpliedVolSignal}))
+	})
+})
@@ -50,6 +50,11 @@
 //   - [HurstDFA](ctx, u, period): Hurst exponent via Detrended Fluctuation Analysis (0 to 1).
 //   - [PairsResidual](ctx, u, period, refUniverse): Z-score of OLS regression residuals vs reference assets.
 //   - [PairsRatio](ctx, u, period, refUniverse): Z-score of price ratio vs reference assets.
+//   - [ImpliedVolatility](ctx, options, underlyings, rate): Black-Scholes implied volatility of each option.
+//   - [OptionGreeks](ctx, options, underlyings, rate): Implied volatility and Black-Scholes delta, gamma, theta, vega, and rho.
+//
+// [BlackScholes] and [BlackScholesImpliedVol] expose the underlying pricing
+// functions for use outside a universe.
 //
 // # Custom Signals
 //
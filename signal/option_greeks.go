@@ -0,0 +1,137 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signal
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/universe"
+)
+
+const (
+	// ImpliedVolSignal is the metric name for Black-Scholes implied volatility.
+	ImpliedVolSignal data.Metric = "ImpliedVol"
+	// DeltaSignal is the metric name for option delta.
+	DeltaSignal data.Metric = "Delta"
+	// GammaSignal is the metric name for option gamma.
+	GammaSignal data.Metric = "Gamma"
+	// ThetaSignal is the metric name for option theta per calendar day.
+	ThetaSignal data.Metric = "Theta"
+	// VegaSignal is the metric name for option vega per volatility point.
+	VegaSignal data.Metric = "Vega"
+	// RhoSignal is the metric name for option rho per rate point.
+	RhoSignal data.Metric = "Rho"
+)
+
+// ImpliedVolatility computes the Black-Scholes implied volatility of each
+// option in the options universe from its current close, the close of its
+// underlying in the underlyings universe, and riskFreeRate (a continuously
+// compounded annual rate as a decimal). Time to expiry is measured in
+// calendar days over 365. Options whose price cannot be inverted are NaN.
+//
+// Returns a single-row DataFrame with the ImpliedVolSignal metric.
+func ImpliedVolatility(ctx context.Context, options, underlyings universe.Universe, riskFreeRate float64) *data.DataFrame {
+	return optionSignal(ctx, "ImpliedVolatility", options, underlyings, riskFreeRate,
+		[]data.Metric{ImpliedVolSignal}, func(vol float64, _ Greeks) []float64 {
+			return []float64{vol}
+		})
+}
+
+// OptionGreeks computes Black-Scholes greeks for each option in the
+// options universe, at the volatility implied by its current close (see
+// ImpliedVolatility).
+//
+// Returns a single-row DataFrame with six metrics: ImpliedVolSignal,
+// DeltaSignal, GammaSignal, ThetaSignal, VegaSignal, and RhoSignal.
+func OptionGreeks(ctx context.Context, options, underlyings universe.Universe, riskFreeRate float64) *data.DataFrame {
+	return optionSignal(ctx, "OptionGreeks", options, underlyings, riskFreeRate,
+		[]data.Metric{ImpliedVolSignal, DeltaSignal, GammaSignal, ThetaSignal, VegaSignal, RhoSignal},
+		func(vol float64, greeks Greeks) []float64 {
+			return []float64{vol, greeks.Delta, greeks.Gamma, greeks.Theta, greeks.Vega, greeks.Rho}
+		})
+}
+
+// optionSignal prices each option against its underlying and lays out the
+// values extract picks from the implied volatility and greeks.
+func optionSignal(ctx context.Context, name string, options, underlyings universe.Universe, riskFreeRate float64,
+	metrics []data.Metric, extract func(vol float64, greeks Greeks) []float64,
+) *data.DataFrame {
+	optionDF, err := options.At(ctx, data.MetricClose)
+	if err != nil {
+		return data.WithErr(fmt.Errorf("%s: option fetch: %w", name, err))
+	}
+
+	underlyingDF, err := underlyings.At(ctx, data.MetricClose)
+	if err != nil {
+		return data.WithErr(fmt.Errorf("%s: underlying fetch: %w", name, err))
+	}
+
+	if optionDF.Len() == 0 {
+		return data.WithErr(fmt.Errorf("%s: no option prices", name))
+	}
+
+	now := options.CurrentDate()
+	assets := optionDF.AssetList()
+	cols := make([][]float64, len(assets)*len(metrics))
+
+	for assetIdx, option := range assets {
+		values := make([]float64, len(metrics))
+		for idx := range values {
+			values[idx] = math.NaN()
+		}
+
+		if option.IsOption() {
+			spec := option.Contract
+			price := optionDF.Value(option, data.MetricClose)
+			spot := underlyingDF.Value(spec.UnderlyingAsset(), data.MetricClose)
+			years := yearsToExpiry(now, spec.Expiry)
+
+			vol := BlackScholesImpliedVol(spec.Right, price, spot, spec.Strike, years, riskFreeRate)
+			if !math.IsNaN(vol) {
+				values = extract(vol, BlackScholes(spec.Right, spot, spec.Strike, years, riskFreeRate, vol))
+			}
+		}
+
+		for metricIdx, value := range values {
+			cols[assetIdx*len(metrics)+metricIdx] = []float64{value}
+		}
+	}
+
+	times := optionDF.Times()
+
+	result, err := data.NewDataFrame(times[len(times)-1:], assets, metrics, optionDF.Frequency(), cols)
+	if err != nil {
+		return data.WithErr(fmt.Errorf("%s: %w", name, err))
+	}
+
+	return result
+}
+
+// yearsToExpiry returns the calendar time from now to 16:00 on the expiry
+// date in now's time zone, in years of 365 days.
+func yearsToExpiry(now, expiry time.Time) float64 {
+	if expiry.IsZero() {
+		return math.NaN()
+	}
+
+	expiryClose := time.Date(expiry.Year(), expiry.Month(), expiry.Day(), 16, 0, 0, 0, now.Location())
+
+	return expiryClose.Sub(now).Hours() / 24 / 365
+}
@@ -43,14 +43,18 @@ type fakePortfolio struct {
 }
 
 // Portfolio interface methods.
-func (fp *fakePortfolio) Cash() float64                         { return 0 }
-func (fp *fakePortfolio) Value() float64                        { return fp.portfolioVal }
-func (fp *fakePortfolio) Position(_ asset.Asset) float64        { return 0 }
-func (fp *fakePortfolio) PositionValue(_ asset.Asset) float64   { return 0 }
-func (fp *fakePortfolio) Holdings() map[asset.Asset]float64     { return nil }
-func (fp *fakePortfolio) Transactions() []portfolio.Transaction { return nil }
-func (fp *fakePortfolio) Prices() *data.DataFrame               { return nil }
-func (fp *fakePortfolio) PerfData() *data.DataFrame             { return fp.perfData }
+func (fp *fakePortfolio) Cash() float64                            { return 0 }
+func (fp *fakePortfolio) CashBalances() map[asset.Currency]float64 { return nil }
+func (fp *fakePortfolio) FXRate(asset.Currency) float64            { return 1 }
+func (fp *fakePortfolio) FuturesNotional() float64                 { return 0 }
+func (fp *fakePortfolio) FuturesMargin() (float64, float64)        { return 0, 0 }
+func (fp *fakePortfolio) Value() float64                           { return fp.portfolioVal }
+func (fp *fakePortfolio) Position(_ asset.Asset) float64           { return 0 }
+func (fp *fakePortfolio) PositionValue(_ asset.Asset) float64      { return 0 }
+func (fp *fakePortfolio) Holdings() map[asset.Asset]float64        { return nil }
+func (fp *fakePortfolio) Transactions() []portfolio.Transaction    { return nil }
+func (fp *fakePortfolio) Prices() *data.DataFrame                  { return nil }
+func (fp *fakePortfolio) PerfData() *data.DataFrame                { return fp.perfData }
 func (fp *fakePortfolio) PerformanceMetric(_ portfolio.PerformanceMetric) portfolio.PerformanceMetricQuery {
 	return portfolio.PerformanceMetricQuery{}
 }
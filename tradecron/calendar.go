@@ -0,0 +1,149 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tradecron
+
+import "time"
+
+// Calendar describes when a market trades: the time zone its sessions are
+// defined in, its regular session, which days it trades on, and which of
+// those days close early. Schedules and market status checks consult the
+// calendar they were built with; XNYS is the default. Lookup finds the
+// calendar for an exchange.
+type Calendar interface {
+	// Name identifies the calendar, usually by the exchange's MIC, e.g.
+	// "XNYS".
+	Name() string
+
+	// Location returns the time zone session times are expressed in.
+	Location() *time.Location
+
+	// Session returns the calendar's regular trading hours.
+	Session() MarketHours
+
+	// IsTradingDay reports whether the market trades on date's calendar
+	// day, evaluated in Location.
+	IsTradingDay(date time.Time) bool
+
+	// IsHoliday reports whether date's calendar day is a full-day closure.
+	// Regular non-trading days such as weekends are not holidays.
+	IsHoliday(date time.Time) bool
+
+	// EarlyClose returns the HHMM close time of a shortened session on
+	// date's calendar day, or 0 when the session closes normally.
+	EarlyClose(date time.Time) int
+
+	// TradingDaysPerYear returns the number of trading days in a typical
+	// year, used to convert between annual and daily rates.
+	TradingDaysPerYear() float64
+}
+
+var (
+	// XNYS is the New York Stock Exchange calendar: America/New_York,
+	// RegularHours, weekdays only, with holidays and early closes taken
+	// from SetMarketHolidays, or generated by NYSEHolidays until a provider
+	// supplies them. It also serves NASDAQ and the other US equity venues.
+	XNYS Calendar = nyseCalendar{}
+
+	// NYSE is an alias for XNYS.
+	NYSE = XNYS
+
+	// Crypto is a 24/7 calendar for markets that never close: UTC,
+	// AllHours, every day a trading day, and no holidays or early closes.
+	Crypto Calendar = cryptoCalendar{}
+)
+
+// Option configures a TradeCron or MarketStatus.
+type Option func(*MarketStatus)
+
+// WithCalendar evaluates the schedule or market status against cal
+// instead of the default NYSE calendar.
+func WithCalendar(cal Calendar) Option {
+	return func(ms *MarketStatus) {
+		ms.calendar = cal
+		ms.tz = cal.Location()
+	}
+}
+
+type nyseCalendar struct{}
+
+func (nyseCalendar) Name() string { return "XNYS" }
+
+func (nyseCalendar) Location() *time.Location { return mustLoadNewYork() }
+
+func (nyseCalendar) Session() MarketHours { return RegularHours }
+
+func (nyseCalendar) TradingDaysPerYear() float64 { return 252 }
+
+func (cal nyseCalendar) IsTradingDay(date time.Time) bool {
+	date = date.In(cal.Location())
+
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return false
+	}
+
+	return !cal.IsHoliday(date)
+}
+
+func (cal nyseCalendar) IsHoliday(date time.Time) bool {
+	holidayLocker.RLock()
+	defer holidayLocker.RUnlock()
+
+	if holidays == nil {
+		return nyseRules.IsHoliday(date)
+	}
+
+	marketClose, isHoliday := holidays[startOfDay(date, cal.Location()).Unix()]
+	if marketClose != 0 {
+		// Non-zero means early close, not a full holiday.
+		return false
+	}
+
+	return isHoliday
+}
+
+func (cal nyseCalendar) EarlyClose(date time.Time) int {
+	holidayLocker.RLock()
+	defer holidayLocker.RUnlock()
+
+	if holidays == nil {
+		return nyseRules.EarlyClose(date)
+	}
+
+	return holidays[startOfDay(date, cal.Location()).Unix()]
+}
+
+type cryptoCalendar struct{}
+
+func (cryptoCalendar) Name() string { return "CRYPTO" }
+
+func (cryptoCalendar) Location() *time.Location { return time.UTC }
+
+func (cryptoCalendar) Session() MarketHours { return AllHours }
+
+func (cryptoCalendar) TradingDaysPerYear() float64 { return 365 }
+
+func (cryptoCalendar) IsTradingDay(time.Time) bool { return true }
+
+func (cryptoCalendar) IsHoliday(time.Time) bool { return false }
+
+func (cryptoCalendar) EarlyClose(time.Time) int { return 0 }
+
+// startOfDay returns midnight of date's calendar day in loc.
+func startOfDay(date time.Time, loc *time.Location) time.Time {
+	date = date.In(loc)
+
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,277 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tradecron
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// directivePattern splits a directive into its name, an @monthday day of
+// month, a trading-day offset, and an interval, e.g. "@monthend-2/3".
+var directivePattern = regexp.MustCompile(`^(@[a-z]+)(\d*)([+-]\d+)?(?:/(\d+))?$`)
+
+// weekEpoch is the Monday that week intervals are counted from.
+var weekEpoch = time.Date(1970, time.January, 5, 0, 0, 0, 0, time.UTC)
+
+// directive is a parsed schedule directive.
+type directive struct {
+	name     string
+	day      int
+	offset   int
+	interval int
+}
+
+// parseDirective parses a directive token. Only date directives accept a
+// day, offset, or interval; @monthday requires a day from 1 to 31.
+func parseDirective(token string) (directive, error) {
+	match := directivePattern.FindStringSubmatch(token)
+	if match == nil {
+		return directive{}, ErrUnknownModifier
+	}
+
+	parsed := directive{name: match[1]}
+
+	if match[2] != "" {
+		if parsed.name != AtMonthDay {
+			return directive{}, ErrUnknownModifier
+		}
+
+		parsed.day, _ = strconv.Atoi(match[2])
+		if parsed.day < 1 || parsed.day > 31 {
+			return directive{}, ErrFieldOutOfBounds
+		}
+	} else if parsed.name == AtMonthDay {
+		return directive{}, ErrMalformedTimeSpec
+	}
+
+	if match[3] != "" {
+		parsed.offset, _ = strconv.Atoi(match[3])
+	}
+
+	if match[4] != "" {
+		parsed.interval, _ = strconv.Atoi(match[4])
+		if parsed.interval < 1 {
+			return directive{}, ErrFieldOutOfBounds
+		}
+	}
+
+	if (parsed.offset != 0 || parsed.interval != 0) && !isDateDirective(parsed.name) {
+		return directive{}, ErrUnknownModifier
+	}
+
+	return parsed, nil
+}
+
+// isDateDirective reports whether name selects trading days rather than a
+// time of day.
+func isDateDirective(name string) bool {
+	switch name {
+	case AtWeekBegin, AtWeekEnd, AtMonthBegin, AtMonthEnd, AtQuarterBegin, AtQuarterEnd, AtOpex, AtMonthDay:
+		return true
+	default:
+		return false
+	}
+}
+
+// usesRules reports whether the schedule's date directive is evaluated by
+// nextByRule rather than the period-specific cases in Next.
+func (tc *TradeCron) usesRules() bool {
+	return tc.DateOffset != 0 || tc.Interval > 1 || tc.DateFlag == AtOpex || tc.DateFlag == AtMonthDay
+}
+
+// nextByRule returns the first firing after forDate for a date directive
+// with an offset or interval, @opex, or @monthday. It walks the week,
+// month, or quarter periods from just before forDate, finds each selected
+// period's anchor trading day, shifts it by the offset in trading days,
+// and fires on the first resulting day with a scheduled time after
+// forDate.
+func (tc *TradeCron) nextByRule(forDate time.Time) time.Time {
+	ms := tc.marketStatus
+	fromDay := startOfDay(forDate, ms.tz)
+
+	// Start far enough back that a positive offset from an earlier
+	// period can still land on or after forDate.
+	offset := tc.DateOffset
+	if offset < 0 {
+		offset = -offset
+	}
+
+	period := tc.periodIndex(fromDay) - 1 - offset
+
+	for iters := 0; iters < 5000; iters++ {
+		if tc.Interval <= 1 || floorMod(period, tc.Interval) == 0 {
+			day := ms.shiftTradingDays(tc.anchorDay(period), tc.DateOffset)
+			if !day.Before(fromDay) {
+				if fire, ok := tc.fireOn(day, forDate); ok {
+					return fire
+				}
+			}
+		}
+
+		period++
+	}
+
+	log.Panic().Str("TimeSpec", tc.TimeSpec).Str("Schedule", tc.ScheduleString).Msg("tradecron schedule appears to be in an infinite loop")
+
+	return time.Time{}
+}
+
+// fireOn returns the first scheduled time on day that is after forDate and
+// inside market hours, snapping @close to an early close.
+func (tc *TradeCron) fireOn(day, forDate time.Time) (time.Time, bool) {
+	ms := tc.marketStatus
+
+	checkDate := day.Add(-time.Nanosecond)
+	if forDate.After(checkDate) {
+		checkDate = forDate
+	}
+
+	for {
+		checkDate = tc.Schedule.Next(checkDate)
+		if checkDate.IsZero() || !startOfDay(checkDate, ms.tz).Equal(day) {
+			return time.Time{}, false
+		}
+
+		if ms.IsMarketOpen(checkDate) {
+			return checkDate, true
+		}
+
+		if tc.TimeFlag == AtClose {
+			if earlyClose := ms.EarlyClose(checkDate); earlyClose != 0 {
+				snapped := time.Date(day.Year(), day.Month(), day.Day(), earlyClose/100, earlyClose%100, 0, 0, ms.tz)
+				if snapped.After(forDate) {
+					return snapped, true
+				}
+			}
+		}
+	}
+}
+
+// periodIndex numbers the week (Monday to Sunday), month, or quarter that
+// contains day so that consecutive periods have consecutive indexes.
+func (tc *TradeCron) periodIndex(day time.Time) int {
+	switch tc.DateFlag {
+	case AtWeekBegin, AtWeekEnd:
+		days := int(date(day.Year(), day.Month(), day.Day()).Sub(weekEpoch).Hours() / 24)
+
+		return floorDiv(days, 7)
+	case AtQuarterBegin, AtQuarterEnd:
+		return day.Year()*4 + int(day.Month()-1)/3
+	default:
+		return day.Year()*12 + int(day.Month()-1)
+	}
+}
+
+// periodBounds returns the first and last calendar days of a period
+// numbered by periodIndex.
+func (tc *TradeCron) periodBounds(period int) (time.Time, time.Time) {
+	tz := tc.marketStatus.tz
+
+	switch tc.DateFlag {
+	case AtWeekBegin, AtWeekEnd:
+		first := weekEpoch.AddDate(0, 0, 7*period)
+		first = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, tz)
+
+		return first, first.AddDate(0, 0, 6)
+	case AtQuarterBegin, AtQuarterEnd:
+		first := time.Date(floorDiv(period, 4), time.Month(floorMod(period, 4)*3+1), 1, 0, 0, 0, 0, tz)
+
+		return first, first.AddDate(0, 3, -1)
+	default:
+		first := time.Date(floorDiv(period, 12), time.Month(floorMod(period, 12)+1), 1, 0, 0, 0, 0, tz)
+
+		return first, first.AddDate(0, 1, -1)
+	}
+}
+
+// anchorDay returns the trading day a period's directive points at before
+// any offset is applied.
+func (tc *TradeCron) anchorDay(period int) time.Time {
+	ms := tc.marketStatus
+	first, last := tc.periodBounds(period)
+
+	switch tc.DateFlag {
+	case AtWeekEnd, AtMonthEnd, AtQuarterEnd:
+		return ms.tradingDayOnOrBefore(last)
+	case AtOpex:
+		// Monthly options expire on the third Friday, or the trading day
+		// before it when the exchange is closed that Friday.
+		friday := nthWeekday(first.Year(), first.Month(), time.Friday, 3)
+
+		return ms.tradingDayOnOrBefore(time.Date(friday.Year(), friday.Month(), friday.Day(), 0, 0, 0, 0, ms.tz))
+	case AtMonthDay:
+		day := min(tc.MonthDay, last.Day())
+
+		return ms.tradingDayOnOrAfter(time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, ms.tz))
+	default:
+		return ms.tradingDayOnOrAfter(first)
+	}
+}
+
+// tradingDayOnOrAfter returns the first trading day on or after day.
+func (ms *MarketStatus) tradingDayOnOrAfter(day time.Time) time.Time {
+	for !ms.IsMarketDay(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return day
+}
+
+// tradingDayOnOrBefore returns the last trading day on or before day.
+func (ms *MarketStatus) tradingDayOnOrBefore(day time.Time) time.Time {
+	for !ms.IsMarketDay(day) {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	return day
+}
+
+// shiftTradingDays moves day forward (positive n) or back (negative n) by
+// n trading days.
+func (ms *MarketStatus) shiftTradingDays(day time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+
+	for ; n > 0; n-- {
+		day = day.AddDate(0, 0, step)
+		for !ms.IsMarketDay(day) {
+			day = day.AddDate(0, 0, step)
+		}
+	}
+
+	return day
+}
+
+// floorDiv divides rounding toward negative infinity.
+func floorDiv(a, b int) int {
+	quotient := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		quotient--
+	}
+
+	return quotient
+}
+
+// floorMod returns the non-negative remainder of a divided by b.
+func floorMod(a, b int) int {
+	return a - floorDiv(a, b)*b
+}
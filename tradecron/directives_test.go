@@ -0,0 +1,161 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tradecron_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/tradecron"
+)
+
+var _ = Describe("Offset, expiration, and interval directives", func() {
+	var nyc *time.Location
+
+	BeforeEach(func() {
+		var err error
+		nyc, err = time.LoadLocation("America/New_York")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Use the built-in NYSE holidays.
+		tradecron.ResetMarketHolidays()
+	})
+
+	AfterEach(func() {
+		tradecron.SetMarketHolidays(nil)
+	})
+
+	// firings returns the first n firings of spec after from.
+	firings := func(spec string, from time.Time, n int) []time.Time {
+		tc, err := tradecron.New(spec, tradecron.RegularHours)
+		Expect(err).NotTo(HaveOccurred())
+
+		var got []time.Time
+		for len(got) < n {
+			from = tc.Next(from)
+			got = append(got, from)
+		}
+
+		return got
+	}
+
+	It("fires @monthbegin+N on the month's (N+1)th trading day", func() {
+		// January 1, 2024 was a holiday, so the month began on the 2nd.
+		Expect(firings("@monthbegin+2", time.Date(2024, time.January, 1, 0, 0, 0, 0, nyc), 2)).To(Equal([]time.Time{
+			time.Date(2024, time.January, 4, 9, 30, 0, 0, nyc),
+			time.Date(2024, time.February, 5, 9, 30, 0, 0, nyc),
+		}))
+	})
+
+	It("fires @monthend-N that many trading days before the month's last", func() {
+		// March 2024 ended on Thursday the 28th because of Good Friday.
+		Expect(firings("@monthend-2", time.Date(2024, time.January, 1, 0, 0, 0, 0, nyc), 3)).To(Equal([]time.Time{
+			time.Date(2024, time.January, 29, 16, 0, 0, 0, nyc),
+			time.Date(2024, time.February, 27, 16, 0, 0, 0, nyc),
+			time.Date(2024, time.March, 26, 16, 0, 0, 0, nyc),
+		}))
+	})
+
+	It("honors an explicit time directive with an offset", func() {
+		Expect(firings("@open @monthend-1", time.Date(2024, time.January, 1, 0, 0, 0, 0, nyc), 1)).To(Equal([]time.Time{
+			time.Date(2024, time.January, 30, 9, 30, 0, 0, nyc),
+		}))
+	})
+
+	It("fires @opex at the close on the third Friday", func() {
+		Expect(firings("@opex", time.Date(2024, time.March, 1, 0, 0, 0, 0, nyc), 2)).To(Equal([]time.Time{
+			time.Date(2024, time.March, 15, 16, 0, 0, 0, nyc),
+			time.Date(2024, time.April, 19, 16, 0, 0, 0, nyc),
+		}))
+	})
+
+	It("moves @opex to Thursday when the third Friday is a holiday", func() {
+		// April 18, 2025 was Good Friday.
+		Expect(firings("@opex", time.Date(2025, time.April, 1, 0, 0, 0, 0, nyc), 1)).To(Equal([]time.Time{
+			time.Date(2025, time.April, 17, 16, 0, 0, 0, nyc),
+		}))
+	})
+
+	It("fires @monthdayN on the first trading day on or after day N", func() {
+		// March 16, 2024 was a Saturday.
+		Expect(firings("@monthday16", time.Date(2024, time.March, 1, 0, 0, 0, 0, nyc), 2)).To(Equal([]time.Time{
+			time.Date(2024, time.March, 18, 9, 30, 0, 0, nyc),
+			time.Date(2024, time.April, 16, 9, 30, 0, 0, nyc),
+		}))
+	})
+
+	It("fires @weekbegin/2 in every second week", func() {
+		// The week of February 19, 2024 began on Tuesday after Presidents Day.
+		Expect(firings("@weekbegin/2", time.Date(2024, time.January, 1, 0, 0, 0, 0, nyc), 4)).To(Equal([]time.Time{
+			time.Date(2024, time.January, 8, 9, 30, 0, 0, nyc),
+			time.Date(2024, time.January, 22, 9, 30, 0, 0, nyc),
+			time.Date(2024, time.February, 5, 9, 30, 0, 0, nyc),
+			time.Date(2024, time.February, 20, 9, 30, 0, 0, nyc),
+		}))
+	})
+
+	It("fires @monthend/3 in the first month of each quarter", func() {
+		Expect(firings("@monthend/3", time.Date(2024, time.February, 1, 0, 0, 0, 0, nyc), 2)).To(Equal([]time.Time{
+			time.Date(2024, time.April, 30, 16, 0, 0, 0, nyc),
+			time.Date(2024, time.July, 31, 16, 0, 0, 0, nyc),
+		}))
+	})
+
+	It("combines an offset with an interval", func() {
+		Expect(firings("@quarterend-1/2", time.Date(2024, time.January, 1, 0, 0, 0, 0, nyc), 2)).To(Equal([]time.Time{
+			time.Date(2024, time.March, 27, 16, 0, 0, 0, nyc),
+			time.Date(2024, time.September, 27, 16, 0, 0, 0, nyc),
+		}))
+	})
+
+	It("snaps @close to an early close", func() {
+		// Nine trading days after the November 15, 2024 expiration was the
+		// day after Thanksgiving, which closed at 13:00.
+		Expect(firings("@opex+9", time.Date(2024, time.November, 20, 0, 0, 0, 0, nyc), 1)).To(Equal([]time.Time{
+			time.Date(2024, time.November, 29, 13, 0, 0, 0, nyc),
+		}))
+	})
+
+	It("reports only the selected day as a trade day", func() {
+		tc, err := tradecron.New("@monthend-2", tradecron.RegularHours)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(tc.IsTradeDay(time.Date(2024, time.January, 29, 12, 0, 0, 0, nyc))).To(BeTrue())
+		Expect(tc.IsTradeDay(time.Date(2024, time.January, 31, 12, 0, 0, 0, nyc))).To(BeFalse())
+	})
+
+	It("counts calendar days on a 24/7 calendar", func() {
+		tc, err := tradecron.New("@monthend-1", tradecron.AllHours, tradecron.WithCalendar(tradecron.Crypto))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(tc.Next(time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC))).To(Equal(time.Date(2024, time.August, 30, 23, 59, 0, 0, time.UTC)))
+	})
+
+	DescribeTable("rejects malformed directives",
+		func(spec string, expected error) {
+			_, err := tradecron.New(spec, tradecron.RegularHours)
+			Expect(err).To(MatchError(expected))
+		},
+		Entry("@monthday without a day", "@monthday", tradecron.ErrMalformedTimeSpec),
+		Entry("@monthday past the 31st", "@monthday32", tradecron.ErrFieldOutOfBounds),
+		Entry("a zero interval", "@monthend/0", tradecron.ErrFieldOutOfBounds),
+		Entry("an offset on a time directive", "@open+1 @monthend", tradecron.ErrUnknownModifier),
+		Entry("a day on another directive", "@monthend3", tradecron.ErrUnknownModifier),
+		Entry("two date directives", "@opex @monthend-1", tradecron.ErrConflictingModifiers),
+	)
+})
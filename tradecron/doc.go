@@ -20,14 +20,42 @@
 //   - @monthend -- last trading day of the month
 //   - @quarterbegin -- first trading day of the quarter
 //   - @quarterend -- last trading day of the quarter
+//   - @opex -- monthly options expiration, the third Friday or the trading
+//     day before it when that Friday is a holiday
+//   - @monthdayN -- first trading day on or after day N of the month
+//
+// A date directive takes an optional offset in trading days and an
+// interval: @monthbegin+2 is the third trading day of the month,
+// @monthend-2 is two trading days before the last, and @weekbegin/2 fires
+// in every second week. Intervals count months and quarters from January
+// and weeks from Monday, January 5, 1970.
 //
 // Directives may be combined with standard cron fields (minute, hour,
 // day-of-month, month, day-of-week).
 //
+// # Calendars
+//
+// A Calendar supplies the time zone, regular session, trading days, and
+// early closes a schedule is evaluated against. XNYS (alias NYSE) is the
+// default and takes its holidays from SetMarketHolidays, falling back to
+// the rule-based NYSEHolidays when no provider has supplied any;
+// DiffHolidays compares the two. XLON, XTSE, XTKS, and XETR cover London,
+// Toronto, Tokyo, and Xetra with holidays generated from each exchange's
+// rules, and Crypto trades every day around the clock in UTC. Lookup maps an exchange name or MIC to its calendar. Pass
+// WithCalendar to New or NewMarketStatus to select one, and use the
+// calendar's Session as the hours:
+//
+//	tc, err := tradecron.New("@close * * *", tradecron.Crypto.Session(),
+//		tradecron.WithCalendar(tradecron.Crypto))
+//
+// TradingDaysPerYear reports the calendar's year length (252 for NYSE, 365
+// for Crypto) for converting between annual and daily rates.
+//
 // # Time Zones
 //
 // Market-aware directives (@monthend, @weekbegin, @open, @close, etc.)
-// produce timestamps in America/New_York. Plain cron expressions like
+// produce timestamps in the calendar's time zone, America/New_York for
+// NYSE. Plain cron expressions like
 // "0 16 * * 1-5" produce UTC timestamps. Data providers must use matching
 // time zones -- if the schedule produces Eastern timestamps but the data
 // uses UTC, the engine's time-range filtering will silently return empty
@@ -45,6 +73,8 @@
 //	@weekend              last trading day of week
 //	@quarterbegin         first trading day of quarter
 //	@quarterend           last trading day of quarter
+//	@monthend-2           two trading days before the last of the month
+//	@opex                 monthly options expiration at close
 //	*/5 * * * *           every 5 minutes during trading hours
 //
 // # Sessions
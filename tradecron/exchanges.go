@@ -0,0 +1,416 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tradecron
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// XLON is the London Stock Exchange calendar: Europe/London, 08:00 to
+	// 16:30, with English bank holidays and 12:30 closes on Christmas Eve
+	// and New Year's Eve.
+	XLON Calendar = &ruleCalendar{
+		name:        "XLON",
+		zone:        "Europe/London",
+		session:     MarketHours{Open: 800, Close: 1630},
+		tradingDays: 253,
+		rules:       londonHolidays,
+	}
+
+	// XTSE is the Toronto Stock Exchange calendar: America/Toronto, 09:30
+	// to 16:00, with Canadian statutory holidays and a 13:00 close on
+	// Christmas Eve.
+	XTSE Calendar = &ruleCalendar{
+		name:        "XTSE",
+		zone:        "America/Toronto",
+		session:     MarketHours{Open: 930, Close: 1600},
+		tradingDays: 250,
+		rules:       torontoHolidays,
+	}
+
+	// XTKS is the Tokyo Stock Exchange calendar: Asia/Tokyo, 09:00 to
+	// 15:30, with Japanese national holidays (including substitute and
+	// citizens' holidays) and the year-end closure from December 31 to
+	// January 3. The midday break is not modeled.
+	XTKS Calendar = &ruleCalendar{
+		name:        "XTKS",
+		zone:        "Asia/Tokyo",
+		session:     MarketHours{Open: 900, Close: 1530},
+		tradingDays: 245,
+		rules:       tokyoHolidays,
+	}
+
+	// XETR is the Deutsche Börse Xetra calendar: Europe/Berlin, 09:00 to
+	// 17:30, closed on New Year's Day, Good Friday, Easter Monday, Labour
+	// Day, and December 24 to 26 and 31.
+	XETR Calendar = &ruleCalendar{
+		name:        "XETR",
+		zone:        "Europe/Berlin",
+		session:     MarketHours{Open: 900, Close: 1730},
+		tradingDays: 252,
+		rules:       xetraHolidays,
+	}
+)
+
+// Lookup returns the calendar for an exchange, identified by its MIC
+// (e.g. "XLON") or a common name (e.g. "LSE", "NASDAQ"). US equity venues
+// all map to XNYS. The match is case-insensitive.
+func Lookup(exchange string) (Calendar, bool) {
+	switch strings.ToUpper(strings.TrimSpace(exchange)) {
+	case "XNYS", "NYSE", "XNAS", "NASDAQ", "NMFQS", "BATS", "ARCX", "NYSE ARCA", "NYSE MKT", "XASE", "AMEX":
+		return XNYS, true
+	case "XLON", "LSE":
+		return XLON, true
+	case "XTSE", "TSX":
+		return XTSE, true
+	case "XTKS", "TSE", "JPX":
+		return XTKS, true
+	case "XETR", "XETRA":
+		return XETR, true
+	case "CRYPTO":
+		return Crypto, true
+	default:
+		return nil, false
+	}
+}
+
+// ruleCalendar is a weekday calendar whose holidays and early closes are
+// generated per year from rules and cached.
+type ruleCalendar struct {
+	name        string
+	zone        string
+	session     MarketHours
+	tradingDays float64
+	rules       func(year int) []MarketHoliday
+
+	locOnce sync.Once
+	loc     *time.Location
+
+	mu    sync.Mutex
+	years map[int]map[int64]int
+}
+
+func (cal *ruleCalendar) Name() string { return cal.name }
+
+func (cal *ruleCalendar) Session() MarketHours { return cal.session }
+
+func (cal *ruleCalendar) TradingDaysPerYear() float64 { return cal.tradingDays }
+
+func (cal *ruleCalendar) Location() *time.Location {
+	cal.locOnce.Do(func() {
+		var err error
+
+		cal.loc, err = time.LoadLocation(cal.zone)
+		if err != nil {
+			panic("tradecron: could not load " + cal.zone + " timezone: " + err.Error())
+		}
+	})
+
+	return cal.loc
+}
+
+func (cal *ruleCalendar) IsTradingDay(date time.Time) bool {
+	date = date.In(cal.Location())
+
+	return !isWeekend(date) && !cal.IsHoliday(date)
+}
+
+func (cal *ruleCalendar) IsHoliday(date time.Time) bool {
+	marketClose, isHoliday := cal.lookup(date)
+
+	return isHoliday && marketClose == 0
+}
+
+func (cal *ruleCalendar) EarlyClose(date time.Time) int {
+	marketClose, _ := cal.lookup(date)
+
+	return marketClose
+}
+
+// lookup returns the early close time of date's calendar day and whether
+// the day has any holiday entry, generating the year's entries on first use.
+func (cal *ruleCalendar) lookup(date time.Time) (int, bool) {
+	day := startOfDay(date, cal.Location())
+
+	cal.mu.Lock()
+	defer cal.mu.Unlock()
+
+	if cal.years == nil {
+		cal.years = make(map[int]map[int64]int)
+	}
+
+	entries, ok := cal.years[day.Year()]
+	if !ok {
+		entries = make(map[int64]int)
+
+		for _, holiday := range cal.rules(day.Year()) {
+			key := time.Date(holiday.Date.Year(), holiday.Date.Month(), holiday.Date.Day(), 0, 0, 0, 0, cal.Location())
+			if holiday.EarlyClose {
+				entries[key.Unix()] = holiday.CloseTime
+			} else {
+				entries[key.Unix()] = 0
+			}
+		}
+
+		cal.years[day.Year()] = entries
+	}
+
+	marketClose, isHoliday := entries[day.Unix()]
+
+	return marketClose, isHoliday
+}
+
+// closures converts full-day closures to MarketHoliday entries.
+func closures(days ...time.Time) []MarketHoliday {
+	holidays := make([]MarketHoliday, 0, len(days))
+	for _, day := range days {
+		holidays = append(holidays, MarketHoliday{Date: day})
+	}
+
+	return holidays
+}
+
+// earlyCloseOnWeekday returns a MarketHoliday closing early at closeTime on
+// day, or nothing when day falls on a weekend.
+func earlyCloseOnWeekday(day time.Time, closeTime int) []MarketHoliday {
+	if isWeekend(day) {
+		return nil
+	}
+
+	return []MarketHoliday{{Date: day, EarlyClose: true, CloseTime: closeTime}}
+}
+
+// londonOneOffClosures are bank holidays declared for a single year.
+var londonOneOffClosures = map[int][]time.Time{
+	1999: {date(1999, time.December, 31)},
+	2002: {date(2002, time.June, 3)},
+	2011: {date(2011, time.April, 29)},
+	2012: {date(2012, time.June, 5)},
+	2022: {date(2022, time.June, 3), date(2022, time.September, 19)},
+	2023: {date(2023, time.May, 8)},
+}
+
+// londonSpringHoliday overrides the last-Monday-of-May spring bank holiday
+// in jubilee years.
+var londonSpringHoliday = map[int]time.Time{
+	2002: date(2002, time.June, 4),
+	2012: date(2012, time.June, 4),
+	2022: date(2022, time.June, 2),
+}
+
+// londonHolidays returns the London Stock Exchange closures and early
+// closes of year.
+func londonHolidays(year int) []MarketHoliday {
+	easter := easterSunday(year)
+
+	earlyMay := nthWeekday(year, time.May, time.Monday, 1)
+	if year == 2020 {
+		earlyMay = date(2020, time.May, 8)
+	}
+
+	spring, ok := londonSpringHoliday[year]
+	if !ok {
+		spring = nthWeekday(year, time.May, time.Monday, -1)
+	}
+
+	days := []time.Time{
+		mondayIfWeekend(date(year, time.January, 1)),
+		easter.AddDate(0, 0, -2),
+		easter.AddDate(0, 0, 1),
+		earlyMay,
+		spring,
+		nthWeekday(year, time.August, time.Monday, -1),
+	}
+	days = append(days, christmasAndBoxingDay(year)...)
+	days = append(days, londonOneOffClosures[year]...)
+
+	// Closures come last so a one-off closure replaces an early close.
+	holidays := earlyCloseOnWeekday(date(year, time.December, 24), 1230)
+	holidays = append(holidays, earlyCloseOnWeekday(date(year, time.December, 31), 1230)...)
+
+	return append(holidays, closures(days...)...)
+}
+
+// torontoHolidays returns the Toronto Stock Exchange closures and early
+// closes of year.
+func torontoHolidays(year int) []MarketHoliday {
+	days := []time.Time{
+		mondayIfWeekend(date(year, time.January, 1)),
+		easterSunday(year).AddDate(0, 0, -2),
+		weekdayOnOrBefore(date(year, time.May, 24), time.Monday),
+		mondayIfWeekend(date(year, time.July, 1)),
+		nthWeekday(year, time.August, time.Monday, 1),
+		nthWeekday(year, time.September, time.Monday, 1),
+		nthWeekday(year, time.October, time.Monday, 2),
+	}
+
+	if year >= 2008 {
+		days = append(days, nthWeekday(year, time.February, time.Monday, 3))
+	}
+
+	days = append(days, christmasAndBoxingDay(year)...)
+
+	holidays := closures(days...)
+	holidays = append(holidays, earlyCloseOnWeekday(date(year, time.December, 24), 1300)...)
+
+	return holidays
+}
+
+// xetraHolidays returns the Xetra closures of year. Xetra does not move
+// holidays that fall on a weekend.
+func xetraHolidays(year int) []MarketHoliday {
+	easter := easterSunday(year)
+
+	return closures(
+		date(year, time.January, 1),
+		easter.AddDate(0, 0, -2),
+		easter.AddDate(0, 0, 1),
+		date(year, time.May, 1),
+		date(year, time.December, 24),
+		date(year, time.December, 25),
+		date(year, time.December, 26),
+		date(year, time.December, 31),
+	)
+}
+
+// tokyoOlympicMoves relocates the Marine, Mountain, and Sports Day holidays
+// for the 2020 Tokyo Olympics, held in 2020 and postponed to 2021.
+var tokyoOlympicMoves = map[int][]time.Time{
+	2020: {date(2020, time.July, 23), date(2020, time.July, 24), date(2020, time.August, 10)},
+	2021: {date(2021, time.July, 22), date(2021, time.July, 23), date(2021, time.August, 9)},
+}
+
+// tokyoHolidays returns the Tokyo Stock Exchange closures of year: the
+// exchange's year-end closure plus Japanese national holidays, with a
+// holiday on Sunday substituted by the next non-holiday and a weekday
+// sandwiched between two holidays made a citizens' holiday.
+func tokyoHolidays(year int) []MarketHoliday {
+	national := japanNationalHolidays(year)
+
+	isNational := make(map[time.Time]bool, len(national))
+	for _, day := range national {
+		isNational[day] = true
+	}
+
+	days := append([]time.Time(nil), national...)
+
+	for _, day := range national {
+		if day.Weekday() != time.Sunday {
+			continue
+		}
+
+		substitute := day.AddDate(0, 0, 1)
+		for isNational[substitute] {
+			substitute = substitute.AddDate(0, 0, 1)
+		}
+
+		days = append(days, substitute)
+	}
+
+	for _, day := range national {
+		between := day.AddDate(0, 0, 1)
+		if !isNational[between] && isNational[between.AddDate(0, 0, 1)] && between.Weekday() != time.Sunday {
+			days = append(days, between)
+		}
+	}
+
+	days = append(days,
+		date(year, time.January, 2),
+		date(year, time.January, 3),
+		date(year, time.December, 31),
+	)
+
+	return closures(days...)
+}
+
+// japanNationalHolidays returns the statutory national holidays of year
+// from 2000 onward, before substitute and citizens' holidays are added.
+func japanNationalHolidays(year int) []time.Time {
+	days := []time.Time{
+		date(year, time.January, 1),
+		nthWeekday(year, time.January, time.Monday, 2),
+		date(year, time.February, 11),
+		date(year, time.March, equinoxDay(year, 20.8431)),
+		date(year, time.April, 29),
+		date(year, time.May, 3),
+		date(year, time.May, 4),
+		date(year, time.May, 5),
+		date(year, time.September, equinoxDay(year, 23.2488)),
+		date(year, time.November, 3),
+		date(year, time.November, 23),
+	}
+
+	switch {
+	case year >= 2020:
+		days = append(days, date(year, time.February, 23))
+	case year <= 2018:
+		days = append(days, date(year, time.December, 23))
+	}
+
+	if moved, ok := tokyoOlympicMoves[year]; ok {
+		days = append(days, moved...)
+	} else {
+		days = append(days,
+			marineDay(year),
+			nthWeekday(year, time.October, time.Monday, 2),
+		)
+
+		if year >= 2016 {
+			days = append(days, date(year, time.August, 11))
+		}
+	}
+
+	if year >= 2003 {
+		days = append(days, nthWeekday(year, time.September, time.Monday, 3))
+	} else {
+		days = append(days, date(year, time.September, 15))
+	}
+
+	if year == 2019 {
+		// Imperial succession: enthronement and ceremony days.
+		days = append(days,
+			date(2019, time.April, 30),
+			date(2019, time.May, 1),
+			date(2019, time.May, 2),
+			date(2019, time.October, 22),
+		)
+	}
+
+	return days
+}
+
+// marineDay returns Japan's Marine Day: July 20 until 2002, then the third
+// Monday of July.
+func marineDay(year int) time.Time {
+	if year < 2003 {
+		return date(year, time.July, 20)
+	}
+
+	return nthWeekday(year, time.July, time.Monday, 3)
+}
+
+// equinoxDay approximates the day of the March (base 20.8431) or
+// September (base 23.2488) equinox in Japan Standard Time, valid for
+// 1980 through 2099.
+func equinoxDay(year int, base float64) int {
+	elapsed := year - 1980
+
+	return int(math.Floor(base+0.242194*float64(elapsed))) - elapsed/4
+}
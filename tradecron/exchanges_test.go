@@ -0,0 +1,106 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tradecron_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/tradecron"
+)
+
+// closedDays returns the weekdays of year on which cal does not trade.
+func closedDays(cal tradecron.Calendar, year int) []string {
+	var closed []string
+
+	for day := time.Date(year, time.January, 1, 12, 0, 0, 0, cal.Location()); day.Year() == year; day = day.AddDate(0, 0, 1) {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday && !cal.IsTradingDay(day) {
+			closed = append(closed, day.Format("01-02"))
+		}
+	}
+
+	return closed
+}
+
+var _ = Describe("Exchange calendars", func() {
+	DescribeTable("closes on the exchange's 2024 holidays",
+		func(cal tradecron.Calendar, expected []string) {
+			Expect(closedDays(cal, 2024)).To(Equal(expected))
+		},
+		Entry("XLON", tradecron.XLON, []string{"01-01", "03-29", "04-01", "05-06", "05-27", "08-26", "12-25", "12-26"}),
+		Entry("XTSE", tradecron.XTSE, []string{"01-01", "02-19", "03-29", "05-20", "07-01", "08-05", "09-02", "10-14", "12-25", "12-26"}),
+		Entry("XTKS", tradecron.XTKS, []string{
+			"01-01", "01-02", "01-03", "01-08", "02-12", "02-23", "03-20", "04-29", "05-03", "05-06",
+			"07-15", "08-12", "09-16", "09-23", "10-14", "11-04", "12-31",
+		}),
+		Entry("XETR", tradecron.XETR, []string{"01-01", "03-29", "04-01", "05-01", "12-24", "12-25", "12-26", "12-31"}),
+	)
+
+	It("substitutes weekend holidays", func() {
+		// Christmas 2021 fell on a Saturday and Canada Day 2023 on a Saturday.
+		Expect(closedDays(tradecron.XLON, 2021)).To(ContainElements("12-27", "12-28"))
+		Expect(closedDays(tradecron.XTSE, 2023)).To(ContainElement("07-03"))
+
+		// Japan's September 2015 citizens' holiday fell between two holidays.
+		Expect(closedDays(tradecron.XTKS, 2015)).To(ContainElements("09-21", "09-22", "09-23"))
+	})
+
+	It("reports early closes in the exchange's time zone", func() {
+		london := tradecron.XLON.Location()
+		Expect(tradecron.XLON.EarlyClose(time.Date(2024, time.December, 24, 9, 0, 0, 0, london))).To(Equal(1230))
+		Expect(tradecron.XTSE.EarlyClose(time.Date(2024, time.December, 24, 9, 0, 0, 0, tradecron.XTSE.Location()))).To(Equal(1300))
+		Expect(tradecron.XETR.EarlyClose(time.Date(2024, time.December, 23, 9, 0, 0, 0, tradecron.XETR.Location()))).To(Equal(0))
+	})
+
+	It("resolves @monthend per exchange", func() {
+		london := tradecron.XLON.Location()
+		tc, err := tradecron.New("@close @monthend", tradecron.XLON.Session(), tradecron.WithCalendar(tradecron.XLON))
+		Expect(err).NotTo(HaveOccurred())
+
+		// March 29, 2024 is Good Friday.
+		got := tc.Next(time.Date(2024, time.March, 1, 0, 0, 0, 0, london))
+		Expect(got).To(Equal(time.Date(2024, time.March, 28, 16, 30, 0, 0, london)))
+
+		tokyo := tradecron.XTKS.Location()
+		tc, err = tradecron.New("@close @monthend", tradecron.XTKS.Session(), tradecron.WithCalendar(tradecron.XTKS))
+		Expect(err).NotTo(HaveOccurred())
+
+		got = tc.Next(time.Date(2024, time.December, 1, 0, 0, 0, 0, tokyo))
+		Expect(got).To(Equal(time.Date(2024, time.December, 30, 15, 30, 0, 0, tokyo)))
+	})
+
+	DescribeTable("looks up calendars by exchange",
+		func(exchange string, expected tradecron.Calendar) {
+			cal, ok := tradecron.Lookup(exchange)
+			Expect(ok).To(BeTrue())
+			Expect(cal.Name()).To(Equal(expected.Name()))
+		},
+		Entry("NYSE", "NYSE", tradecron.XNYS),
+		Entry("NASDAQ", "NASDAQ", tradecron.XNYS),
+		Entry("LSE", "LSE", tradecron.XLON),
+		Entry("XTSE", "xtse", tradecron.XTSE),
+		Entry("TSE", "TSE", tradecron.XTKS),
+		Entry("XETRA", "XETRA", tradecron.XETR),
+		Entry("CRYPTO", "CRYPTO", tradecron.Crypto),
+	)
+
+	It("does not know unlisted exchanges", func() {
+		_, ok := tradecron.Lookup("FRED")
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,100 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tradecron
+
+import "time"
+
+// date returns midnight UTC of the given calendar day. Holiday rules work
+// on calendar days only; callers attach the exchange's time zone.
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// isWeekend reports whether day falls on a Saturday or Sunday.
+func isWeekend(day time.Time) bool {
+	return day.Weekday() == time.Saturday || day.Weekday() == time.Sunday
+}
+
+// easterSunday returns Western (Gregorian) Easter Sunday of year, using
+// the anonymous Gregorian algorithm.
+func easterSunday(year int) time.Time {
+	golden := year % 19
+	century := year / 100
+	yearOfCentury := year % 100
+	leapCentury := century / 4
+	centuryRemainder := century % 4
+	correction := (century + 8) / 25
+	moon := (century - correction + 1) / 3
+	epact := (19*golden + century - leapCentury - moon + 15) % 30
+	leapYears := yearOfCentury / 4
+	yearRemainder := yearOfCentury % 4
+	weekday := (32 + 2*centuryRemainder + 2*leapYears - epact - yearRemainder) % 7
+	offset := (golden + 11*epact + 22*weekday) / 451
+	month := (epact + weekday - 7*offset + 114) / 31
+	day := (epact+weekday-7*offset+114)%31 + 1
+
+	return date(year, time.Month(month), day)
+}
+
+// nthWeekday returns the nth occurrence (1-based) of weekday in month, or
+// the last occurrence when n is -1.
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	if n < 0 {
+		last := date(year, month+1, 0)
+		back := (int(last.Weekday()) - int(weekday) + 7) % 7
+
+		return last.AddDate(0, 0, -back)
+	}
+
+	first := date(year, month, 1)
+	ahead := (int(weekday) - int(first.Weekday()) + 7) % 7
+
+	return first.AddDate(0, 0, ahead+7*(n-1))
+}
+
+// weekdayOnOrBefore returns the latest weekday (e.g. Monday) on or before day.
+func weekdayOnOrBefore(day time.Time, weekday time.Weekday) time.Time {
+	back := (int(day.Weekday()) - int(weekday) + 7) % 7
+
+	return day.AddDate(0, 0, -back)
+}
+
+// mondayIfWeekend moves a Saturday or Sunday holiday to the following
+// Monday.
+func mondayIfWeekend(day time.Time) time.Time {
+	switch day.Weekday() {
+	case time.Saturday:
+		return day.AddDate(0, 0, 2)
+	case time.Sunday:
+		return day.AddDate(0, 0, 1)
+	default:
+		return day
+	}
+}
+
+// christmasAndBoxingDay returns the observed Christmas and Boxing Day
+// holidays of year under the Commonwealth substitution rule: a holiday on
+// a weekend moves to the next weekday not already taken by the other.
+func christmasAndBoxingDay(year int) []time.Time {
+	christmas := mondayIfWeekend(date(year, time.December, 25))
+
+	boxing := date(year, time.December, 26)
+	for isWeekend(boxing) || boxing.Equal(christmas) {
+		boxing = boxing.AddDate(0, 0, 1)
+	}
+
+	return []time.Time{christmas, boxing}
+}
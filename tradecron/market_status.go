@@ -29,6 +29,7 @@ var (
 )
 
 // HolidaysInitialized returns true if SetMarketHolidays has been called.
+// Until then the XNYS calendar uses holidays generated by NYSEHolidays.
 func HolidaysInitialized() bool {
 	holidayLocker.RLock()
 	defer holidayLocker.RUnlock()
@@ -36,15 +37,6 @@ func HolidaysInitialized() bool {
 	return holidays != nil
 }
 
-// requireHolidays panics if SetMarketHolidays has not been called. This
-// catches misconfigured commands that forget to load the holiday calendar,
-// which would silently treat every day as a trading day.
-func requireHolidays() {
-	if holidays == nil {
-		panic("tradecron: market holidays not initialized -- call SetMarketHolidays before using market calendar functions")
-	}
-}
-
 // MarketHoliday describes a single market holiday or early-close day.
 type MarketHoliday struct {
 	Date       time.Time
@@ -55,10 +47,12 @@ type MarketHoliday struct {
 // MarketStatus tracks market hours and holidays for determining trading availability.
 type MarketStatus struct {
 	marketHours *MarketHours
+	calendar    Calendar
 	tz          *time.Location
 }
 
-// SetMarketHolidays replaces the global holiday calendar with the provided data.
+// SetMarketHolidays replaces the XNYS holiday calendar with the provided
+// data. Other exchange calendars generate their holidays from rules.
 func SetMarketHolidays(items []MarketHoliday) {
 	nyc := mustLoadNewYork()
 
@@ -76,41 +70,23 @@ func SetMarketHolidays(items []MarketHoliday) {
 	}
 }
 
-// EarlyClose returns close time of an early close market day, e.g. 1300
-func (ms *MarketStatus) EarlyClose(checkTime time.Time) int {
-	holidayLocker.RLock()
-	defer holidayLocker.RUnlock()
-
-	requireHolidays()
-
-	checkTime = checkTime.In(ms.tz)
+// ResetMarketHolidays discards holidays loaded by SetMarketHolidays so the
+// XNYS calendar goes back to the holidays generated by NYSEHolidays.
+func ResetMarketHolidays() {
+	holidayLocker.Lock()
+	defer holidayLocker.Unlock()
 
-	d := time.Date(checkTime.Year(), checkTime.Month(), checkTime.Day(), 0, 0, 0, 0, ms.tz)
-	if marketClose, ok := holidays[d.Unix()]; ok {
-		return marketClose
-	}
+	holidays = nil
+}
 
-	return 0
+// EarlyClose returns close time of an early close market day, e.g. 1300
+func (ms *MarketStatus) EarlyClose(checkTime time.Time) int {
+	return ms.calendar.EarlyClose(checkTime)
 }
 
 // IsMarketHoliday returns true if the specified date is a market holiday
 func (ms *MarketStatus) IsMarketHoliday(checkTime time.Time) bool {
-	holidayLocker.RLock()
-	defer holidayLocker.RUnlock()
-
-	requireHolidays()
-
-	checkTime = checkTime.In(ms.tz)
-
-	d := time.Date(checkTime.Year(), checkTime.Month(), checkTime.Day(), 0, 0, 0, 0, ms.tz)
-
-	marketHoliday, isHoliday := holidays[d.Unix()]
-	if marketHoliday != 0 {
-		// Non-zero means early close, not a full holiday.
-		return false
-	}
-
-	return isHoliday
+	return ms.calendar.IsHoliday(checkTime)
 }
 
 // IsMarketOpen returns true if the specified time is during market hours
@@ -145,23 +121,28 @@ func (ms *MarketStatus) IsMarketOpen(checkTime time.Time) bool {
 // (i.e. not a market holiday or weekend). The date is evaluated in the
 // market's timezone regardless of the input's location.
 func (ms *MarketStatus) IsMarketDay(checkTime time.Time) bool {
-	checkTime = checkTime.In(ms.tz)
+	return ms.calendar.IsTradingDay(checkTime.In(ms.tz))
+}
 
-	if checkTime.Weekday() == time.Saturday || checkTime.Weekday() == time.Sunday {
-		return false
+// NewMarketStatus creates a new MarketStatus for the given market hours,
+// evaluated against the NYSE calendar unless WithCalendar says otherwise.
+func NewMarketStatus(hours *MarketHours, opts ...Option) *MarketStatus {
+	ms := &MarketStatus{
+		marketHours: hours,
+		calendar:    NYSE,
+		tz:          NYSE.Location(),
 	}
 
-	isHoliday := ms.IsMarketHoliday(checkTime)
+	for _, opt := range opts {
+		opt(ms)
+	}
 
-	return !isHoliday
+	return ms
 }
 
-// NewMarketStatus creates a new MarketStatus for the given market hours.
-func NewMarketStatus(hours *MarketHours) *MarketStatus {
-	return &MarketStatus{
-		marketHours: hours,
-		tz:          mustLoadNewYork(),
-	}
+// Calendar returns the market calendar the status is evaluated against.
+func (ms *MarketStatus) Calendar() Calendar {
+	return ms.calendar
 }
 
 // NextFirstTradingDayOfMonth returns the first trading day of the next month
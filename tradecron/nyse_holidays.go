@@ -0,0 +1,198 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tradecron
+
+import (
+	"sort"
+	"time"
+)
+
+// nyseRules is the XNYS calendar with holidays generated by NYSEHolidays.
+// XNYS falls back to it until SetMarketHolidays supplies provider data.
+var nyseRules = &ruleCalendar{
+	name:        "XNYS",
+	zone:        "America/New_York",
+	session:     RegularHours,
+	tradingDays: 252,
+	rules:       NYSEHolidays,
+}
+
+// nyseOneOffClosures are unscheduled full-day closures: presidential
+// funerals, weather, and the September 11 attacks.
+var nyseOneOffClosures = map[int][]time.Time{
+	1972: {date(1972, time.December, 28)},
+	1973: {date(1973, time.January, 25)},
+	1977: {date(1977, time.July, 14)},
+	1985: {date(1985, time.September, 27)},
+	1994: {date(1994, time.April, 27)},
+	2001: {
+		date(2001, time.September, 11),
+		date(2001, time.September, 12),
+		date(2001, time.September, 13),
+		date(2001, time.September, 14),
+	},
+	2004: {date(2004, time.June, 11)},
+	2007: {date(2007, time.January, 2)},
+	2012: {date(2012, time.October, 29), date(2012, time.October, 30)},
+	2018: {date(2018, time.December, 5)},
+	2025: {date(2025, time.January, 9)},
+}
+
+// NYSEHolidays returns the New York Stock Exchange full-day closures and
+// 13:00 early closes of year, sorted by date. Dates are midnight UTC of
+// the calendar day.
+//
+// The rules follow the holiday schedule in effect since the 1971 Uniform
+// Monday Holiday Act: New Year's Day, Martin Luther King Jr. Day (from
+// 1998), Washington's Birthday, Good Friday, Memorial Day, Juneteenth
+// (from 2022), Independence Day, Labor Day, Thanksgiving, and Christmas,
+// plus presidential Election Days through 1980 and known one-off
+// closures. A holiday on Sunday is observed on Monday and one on Saturday
+// on the preceding Friday, except New Year's Day, which is not observed
+// when it falls on a Saturday. The market closes at 13:00 on July 3, the
+// day after Thanksgiving, and Christmas Eve when those are otherwise
+// trading days.
+func NYSEHolidays(year int) []MarketHoliday {
+	days := []time.Time{
+		nthWeekday(year, time.February, time.Monday, 3),
+		easterSunday(year).AddDate(0, 0, -2),
+		nthWeekday(year, time.May, time.Monday, -1),
+		observedUS(date(year, time.July, 4)),
+		nthWeekday(year, time.September, time.Monday, 1),
+		nthWeekday(year, time.November, time.Thursday, 4),
+		observedUS(date(year, time.December, 25)),
+	}
+
+	newYear := date(year, time.January, 1)
+	switch newYear.Weekday() {
+	case time.Saturday:
+		// The exchange stays open on the preceding Friday, December 31.
+	case time.Sunday:
+		days = append(days, newYear.AddDate(0, 0, 1))
+	default:
+		days = append(days, newYear)
+	}
+
+	if year >= 1998 {
+		days = append(days, nthWeekday(year, time.January, time.Monday, 3))
+	}
+
+	if year >= 2022 {
+		days = append(days, observedUS(date(year, time.June, 19)))
+	}
+
+	if year <= 1980 && year%4 == 0 {
+		// Election Day: the Tuesday after the first Monday in November.
+		days = append(days, nthWeekday(year, time.November, time.Monday, 1).AddDate(0, 0, 1))
+	}
+
+	days = append(days, nyseOneOffClosures[year]...)
+
+	closed := make(map[time.Time]bool, len(days))
+	for _, day := range days {
+		closed[day] = true
+	}
+
+	holidays := closures(days...)
+
+	for _, day := range []time.Time{
+		date(year, time.July, 3),
+		nthWeekday(year, time.November, time.Thursday, 4).AddDate(0, 0, 1),
+		date(year, time.December, 24),
+	} {
+		if !closed[day] {
+			holidays = append(holidays, earlyCloseOnWeekday(day, 1300)...)
+		}
+	}
+
+	sort.Slice(holidays, func(i, j int) bool { return holidays[i].Date.Before(holidays[j].Date) })
+
+	return holidays
+}
+
+// observedUS moves a Saturday holiday to the preceding Friday and a Sunday
+// holiday to the following Monday.
+func observedUS(day time.Time) time.Time {
+	switch day.Weekday() {
+	case time.Saturday:
+		return day.AddDate(0, 0, -1)
+	case time.Sunday:
+		return day.AddDate(0, 0, 1)
+	default:
+		return day
+	}
+}
+
+// HolidayDiff is a day on which two holiday lists disagree. Generated and
+// Provided are nil when that list has no entry for the day.
+type HolidayDiff struct {
+	Date      time.Time
+	Generated *MarketHoliday
+	Provided  *MarketHoliday
+}
+
+// DiffHolidays compares holidays generated by rules (e.g. NYSEHolidays)
+// with holidays supplied by a provider and returns the days on which they
+// disagree, sorted by date. Days match when both lists close the market
+// all day or both close it early at the same time. Only calendar days are
+// compared; each entry's time of day and time zone are ignored.
+func DiffHolidays(generated, provided []MarketHoliday) []HolidayDiff {
+	byDay := func(items []MarketHoliday) map[time.Time]MarketHoliday {
+		indexed := make(map[time.Time]MarketHoliday, len(items))
+		for _, holiday := range items {
+			indexed[date(holiday.Date.Year(), holiday.Date.Month(), holiday.Date.Day())] = holiday
+		}
+
+		return indexed
+	}
+
+	generatedByDay := byDay(generated)
+	providedByDay := byDay(provided)
+
+	var diffs []HolidayDiff
+
+	for day, gen := range generatedByDay {
+		prov, ok := providedByDay[day]
+		if !ok {
+			diffs = append(diffs, HolidayDiff{Date: day, Generated: &gen})
+			continue
+		}
+
+		if holidayCloseTime(gen) != holidayCloseTime(prov) {
+			diffs = append(diffs, HolidayDiff{Date: day, Generated: &gen, Provided: &prov})
+		}
+	}
+
+	for day, prov := range providedByDay {
+		if _, ok := generatedByDay[day]; !ok {
+			diffs = append(diffs, HolidayDiff{Date: day, Provided: &prov})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Date.Before(diffs[j].Date) })
+
+	return diffs
+}
+
+// holidayCloseTime returns the HHMM close time of an early close, or 0 for
+// a full-day closure.
+func holidayCloseTime(holiday MarketHoliday) int {
+	if !holiday.EarlyClose {
+		return 0
+	}
+
+	return holiday.CloseTime
+}
@@ -0,0 +1,163 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tradecron_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/tradecron"
+)
+
+// holidayDays splits generated holidays into full closures and early
+// closes, formatted as MM-DD and MM-DD@HHMM.
+func holidayDays(items []tradecron.MarketHoliday) (closed, early []string) {
+	for _, holiday := range items {
+		if holiday.EarlyClose {
+			early = append(early, fmt.Sprintf("%s@%04d", holiday.Date.Format("01-02"), holiday.CloseTime))
+		} else {
+			closed = append(closed, holiday.Date.Format("01-02"))
+		}
+	}
+
+	return closed, early
+}
+
+var _ = Describe("NYSE holiday rules", func() {
+	DescribeTable("generates the year's closures and early closes",
+		func(year int, expectedClosed, expectedEarly []string) {
+			closed, early := holidayDays(tradecron.NYSEHolidays(year))
+			Expect(closed).To(Equal(expectedClosed))
+			Expect(early).To(Equal(expectedEarly))
+		},
+		Entry("2024", 2024,
+			[]string{"01-01", "01-15", "02-19", "03-29", "05-27", "06-19", "07-04", "09-02", "11-28", "12-25"},
+			[]string{"07-03@1300", "11-29@1300", "12-24@1300"}),
+		Entry("2021, with Independence Day on a Sunday and Christmas on a Saturday", 2021,
+			[]string{"01-01", "01-18", "02-15", "04-02", "05-31", "07-05", "09-06", "11-25", "12-24"},
+			[]string{"11-26@1300"}),
+		Entry("2022, with New Year's Day on a Saturday left unobserved", 2022,
+			[]string{"01-17", "02-21", "04-15", "05-30", "06-20", "07-04", "09-05", "11-24", "12-26"},
+			[]string{"11-25@1300"}),
+		Entry("2026, with Independence Day on a Saturday", 2026,
+			[]string{"01-01", "01-19", "02-16", "04-03", "05-25", "06-19", "07-03", "09-07", "11-26", "12-25"},
+			[]string{"11-27@1300", "12-24@1300"}),
+	)
+
+	It("applies holidays only from the year they were established", func() {
+		closed, _ := holidayDays(tradecron.NYSEHolidays(1997))
+		Expect(closed).NotTo(ContainElement("01-20"))
+
+		closed, _ = holidayDays(tradecron.NYSEHolidays(2021))
+		Expect(closed).NotTo(ContainElement("06-18"))
+
+		closed, _ = holidayDays(tradecron.NYSEHolidays(1976))
+		Expect(closed).To(ContainElement("11-02"))
+	})
+
+	It("includes one-off closures", func() {
+		closed, _ := holidayDays(tradecron.NYSEHolidays(2001))
+		Expect(closed).To(ContainElements("09-11", "09-12", "09-13", "09-14"))
+
+		closed, _ = holidayDays(tradecron.NYSEHolidays(2012))
+		Expect(closed).To(ContainElements("10-29", "10-30"))
+
+		closed, _ = holidayDays(tradecron.NYSEHolidays(2025))
+		Expect(closed).To(ContainElement("01-09"))
+	})
+
+	Context("when no provider has supplied holidays", func() {
+		BeforeEach(func() {
+			tradecron.ResetMarketHolidays()
+		})
+
+		AfterEach(func() {
+			tradecron.SetMarketHolidays(nil)
+		})
+
+		It("uses the generated holidays for XNYS", func() {
+			Expect(tradecron.HolidaysInitialized()).To(BeFalse())
+
+			nyc, err := time.LoadLocation("America/New_York")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tradecron.XNYS.IsHoliday(time.Date(2024, 3, 29, 12, 0, 0, 0, nyc))).To(BeTrue())
+			Expect(tradecron.XNYS.EarlyClose(time.Date(2024, 11, 29, 12, 0, 0, 0, nyc))).To(Equal(1300))
+			Expect(tradecron.XNYS.IsTradingDay(time.Date(2024, 3, 28, 12, 0, 0, 0, nyc))).To(BeTrue())
+		})
+
+		It("evaluates @monthend schedules without a provider", func() {
+			nyc, err := time.LoadLocation("America/New_York")
+			Expect(err).NotTo(HaveOccurred())
+
+			tc, err := tradecron.New("@close @monthend", tradecron.RegularHours)
+			Expect(err).NotTo(HaveOccurred())
+
+			// March 29, 2024 was Good Friday, so March closed out on the 28th.
+			next := tc.Next(time.Date(2024, 3, 1, 0, 0, 0, 0, nyc))
+			Expect(next).To(Equal(time.Date(2024, 3, 28, 16, 0, 0, 0, nyc)))
+		})
+
+		It("prefers provider holidays once they are set", func() {
+			nyc, err := time.LoadLocation("America/New_York")
+			Expect(err).NotTo(HaveOccurred())
+
+			tradecron.SetMarketHolidays(nil)
+			Expect(tradecron.XNYS.IsHoliday(time.Date(2024, 3, 29, 12, 0, 0, 0, nyc))).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("DiffHolidays", func() {
+	It("reports days missing from either list and mismatched close times", func() {
+		generated := []tradecron.MarketHoliday{
+			{Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{Date: time.Date(2024, 7, 3, 0, 0, 0, 0, time.UTC), EarlyClose: true, CloseTime: 1300},
+			{Date: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)},
+		}
+
+		nyc, err := time.LoadLocation("America/New_York")
+		Expect(err).NotTo(HaveOccurred())
+
+		provided := []tradecron.MarketHoliday{
+			{Date: time.Date(2024, 1, 1, 0, 0, 0, 0, nyc)},
+			{Date: time.Date(2024, 7, 3, 0, 0, 0, 0, nyc), EarlyClose: true, CloseTime: 1400},
+			{Date: time.Date(2024, 11, 28, 0, 0, 0, 0, nyc)},
+		}
+
+		diffs := tradecron.DiffHolidays(generated, provided)
+		Expect(diffs).To(HaveLen(3))
+
+		Expect(diffs[0].Date.Format("2006-01-02")).To(Equal("2024-07-03"))
+		Expect(diffs[0].Generated.CloseTime).To(Equal(1300))
+		Expect(diffs[0].Provided.CloseTime).To(Equal(1400))
+
+		Expect(diffs[1].Date.Format("2006-01-02")).To(Equal("2024-11-28"))
+		Expect(diffs[1].Generated).To(BeNil())
+		Expect(diffs[1].Provided).NotTo(BeNil())
+
+		Expect(diffs[2].Date.Format("2006-01-02")).To(Equal("2024-12-25"))
+		Expect(diffs[2].Generated).NotTo(BeNil())
+		Expect(diffs[2].Provided).To(BeNil())
+	})
+
+	It("finds no differences between the generated holidays and themselves", func() {
+		Expect(tradecron.DiffHolidays(tradecron.NYSEHolidays(2024), tradecron.NYSEHolidays(2024))).To(BeEmpty())
+	})
+})
@@ -33,12 +33,14 @@ const (
 	AtMonthEnd     = "@monthend"
 	AtQuarterBegin = "@quarterbegin"
 	AtQuarterEnd   = "@quarterend"
+	AtOpex         = "@opex"
+	AtMonthDay     = "@monthday"
 )
 
 // MarketHours defines the opening and closing times for a trading session.
 type MarketHours struct {
-	Open  int // Market open time as HHMM in Eastern, e.g. 930 for 9:30 AM.
-	Close int // Market close time as HHMM in Eastern, e.g. 1600 for 4:00 PM.
+	Open  int // Market open time as HHMM in the calendar's time zone, e.g. 930 for 9:30 AM.
+	Close int // Market close time as HHMM in the calendar's time zone, e.g. 1600 for 4:00 PM.
 }
 
 // TradeCron enables market-aware scheduling built on top of cron expressions.
@@ -48,6 +50,9 @@ type TradeCron struct {
 	TimeSpec       string
 	TimeFlag       string
 	DateFlag       string
+	DateOffset     int // trading days to shift the DateFlag's day by, e.g. -2 for @monthend-2
+	Interval       int // fire only in every Interval-th week, month, or quarter; 0 or 1 for all
+	MonthDay       int // day of month for @monthday
 	marketStatus   *MarketStatus
 }
 
@@ -91,6 +96,22 @@ func (mh MarketHours) coversFullDay() bool {
 //	@monthend     - Run at market close or timespec on last trading day of month
 //	@quarterbegin - Run at market open or timespec on first trading day of quarter
 //	@quarterend   - Run at market close or timespec on last trading day of quarter
+//	@opex         - Run at market close or timespec on monthly options expiration:
+//	                the third Friday, or the trading day before it if that is a holiday
+//	@monthdayN    - Run at market open or timespec on the first trading day on or
+//	                after day N of the month, e.g. @monthday16
+//
+// Date modifiers accept a trading-day offset and an interval:
+//
+//	@monthbegin+2 - third trading day of the month
+//	@monthend-2   - two trading days before the last trading day of the month
+//	@opex-1       - trading day before options expiration
+//	@weekbegin/2  - first trading day of every second week
+//	@monthend/3   - last trading day of January, April, July, and October
+//
+// Intervals count weeks from Monday, January 5, 1970, and months and
+// quarters from January, so every schedule with the same interval selects
+// the same periods.
 //
 // Examples:
 //   - every 5 minutes: */5 * * * *
@@ -98,7 +119,10 @@ func (mh MarketHours) coversFullDay() bool {
 //   - 15 minutes after market open: 15 @open * * *
 //   - market open on first trading day of week: @weekbegin
 //   - market open on last trading day of month: @open @monthend
-func New(cronSpec string, hours MarketHours) (*TradeCron, error) {
+//
+// Schedules follow the NYSE calendar unless WithCalendar selects another;
+// hours are interpreted in the calendar's time zone.
+func New(cronSpec string, hours MarketHours, opts ...Option) (*TradeCron, error) {
 	specParser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
 
 	scheduleStr := strings.TrimSpace(cronSpec)
@@ -131,8 +155,23 @@ func New(cronSpec string, hours MarketHours) (*TradeCron, error) {
 		err      error
 	)
 
+	var dateDirective directive
+
 	for _, token := range specialTokens {
-		switch token {
+		parsed, err := parseDirective(token)
+		if err != nil {
+			return nil, err
+		}
+
+		if isDateDirective(parsed.name) {
+			if dateFlag != "" {
+				return nil, ErrConflictingModifiers
+			}
+
+			dateDirective = parsed
+		}
+
+		switch parsed.name {
 		case AtOpen:
 			if timeFlag != "" {
 				return nil, ErrConflictingModifiers
@@ -154,41 +193,21 @@ func New(cronSpec string, hours MarketHours) (*TradeCron, error) {
 
 			timeFlag = AtClose
 		case AtWeekBegin:
-			if dateFlag != "" {
-				return nil, ErrConflictingModifiers
-			}
-
 			dateFlag = AtWeekBegin
 		case AtWeekEnd:
-			if dateFlag != "" {
-				return nil, ErrConflictingModifiers
-			}
-
 			dateFlag = AtWeekEnd
 		case AtMonthBegin:
-			if dateFlag != "" {
-				return nil, ErrConflictingModifiers
-			}
-
 			dateFlag = AtMonthBegin
 		case AtMonthEnd:
-			if dateFlag != "" {
-				return nil, ErrConflictingModifiers
-			}
-
 			dateFlag = AtMonthEnd
 		case AtQuarterBegin:
-			if dateFlag != "" {
-				return nil, ErrConflictingModifiers
-			}
-
 			dateFlag = AtQuarterBegin
 		case AtQuarterEnd:
-			if dateFlag != "" {
-				return nil, ErrConflictingModifiers
-			}
-
 			dateFlag = AtQuarterEnd
+		case AtOpex:
+			dateFlag = AtOpex
+		case AtMonthDay:
+			dateFlag = AtMonthDay
 		default:
 			return nil, ErrUnknownModifier
 		}
@@ -197,14 +216,14 @@ func New(cronSpec string, hours MarketHours) (*TradeCron, error) {
 	// Default time for date-only modifiers: @monthend → @close, @monthbegin → @open.
 	if timeFlag == "" && dateFlag != "" {
 		switch dateFlag {
-		case AtMonthEnd, AtWeekEnd, AtQuarterEnd:
+		case AtMonthEnd, AtWeekEnd, AtQuarterEnd, AtOpex:
 			timeFlag = AtClose
 
 			var parseErr error
 			if timeSpec, parseErr = parseTimeRelativeTo(timeSpecTokens, hours.Close/100, hours.Close%100); parseErr != nil {
 				return nil, parseErr
 			}
-		case AtMonthBegin, AtWeekBegin, AtQuarterBegin:
+		case AtMonthBegin, AtWeekBegin, AtQuarterBegin, AtMonthDay:
 			timeFlag = AtOpen
 
 			var parseErr error
@@ -229,13 +248,21 @@ func New(cronSpec string, hours MarketHours) (*TradeCron, error) {
 		ScheduleString: cronSpec,
 		TimeSpec:       timeSpec,
 		DateFlag:       dateFlag,
+		DateOffset:     dateDirective.offset,
+		Interval:       dateDirective.interval,
+		MonthDay:       dateDirective.day,
 		TimeFlag:       timeFlag,
-		marketStatus:   NewMarketStatus(&hours),
+		marketStatus:   NewMarketStatus(&hours, opts...),
 	}
 
 	return tradeCron, nil
 }
 
+// Calendar returns the market calendar the schedule is evaluated against.
+func (tc *TradeCron) Calendar() Calendar {
+	return tc.marketStatus.calendar
+}
+
 // IsTradeDay evaluates the given date against the schedule and returns true if the date falls
 // on a trading day according to the schedule. The time portion of the schedule is ignored when
 // evaluating this function.
@@ -258,6 +285,10 @@ func (tc *TradeCron) Next(forDate time.Time) time.Time {
 	// market time, not the caller's wall clock.
 	forDate = forDate.In(tc.marketStatus.tz)
 
+	if tc.usesRules() {
+		return tc.nextByRule(forDate)
+	}
+
 	var checkDate time.Time
 
 	nextDate := tc.Schedule.Next(forDate)
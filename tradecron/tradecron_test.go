@@ -59,6 +59,42 @@ var _ = Describe("TradeCron", func() {
 		})
 	})
 
+	Describe("Crypto calendar", func() {
+		BeforeEach(func() {
+			tradecron.SetMarketHolidays([]tradecron.MarketHoliday{
+				{Date: time.Date(2024, time.December, 25, 0, 0, 0, 0, nyc)},
+			})
+		})
+
+		It("fires every calendar day in UTC", func() {
+			cal := tradecron.Crypto
+			tc, err := tradecron.New("@close * * *", cal.Session(), tradecron.WithCalendar(cal))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tc.Calendar()).To(Equal(cal))
+
+			// Friday Dec 20, 2024 after the close -- the weekend trades.
+			got := tc.Next(time.Date(2024, time.December, 20, 23, 59, 0, 0, time.UTC))
+			Expect(got).To(Equal(time.Date(2024, time.December, 21, 23, 59, 0, 0, time.UTC)))
+
+			// NYSE holidays do not apply.
+			Expect(tc.IsTradeDay(time.Date(2024, time.December, 25, 12, 0, 0, 0, time.UTC))).To(BeTrue())
+		})
+
+		It("ends the month on its last calendar day", func() {
+			tc, err := tradecron.New("@monthend", tradecron.AllHours, tradecron.WithCalendar(tradecron.Crypto))
+			Expect(err).NotTo(HaveOccurred())
+
+			// Aug 31, 2024 is a Saturday.
+			got := tc.Next(time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC))
+			Expect(got).To(Equal(time.Date(2024, time.August, 31, 23, 59, 0, 0, time.UTC)))
+		})
+
+		It("reports a 365-day year", func() {
+			Expect(tradecron.Crypto.TradingDaysPerYear()).To(Equal(365.0))
+			Expect(tradecron.NYSE.TradingDaysPerYear()).To(Equal(252.0))
+		})
+	})
+
 	Describe("@daily", func() {
 		BeforeEach(func() {
 			tradecron.SetMarketHolidays(nil)
@@ -89,6 +89,15 @@
 // on the order date, so positions migrate via a normal rebalance when the
 // simulation crosses a cutoff.
 //
+// From a chain of futures contracts: use eng.ContinuousFutures from Setup to
+// follow consecutive contracts as one instrument. The universe holds each
+// contract until a set number of weekdays before its expiry, Window stitches
+// the contracts' histories together with the roll gaps removed by back or
+// ratio adjustment, and Roll adds the orders that move held contracts into
+// the active one.
+//
+//	s.ES = eng.ContinuousFutures(universe.BackAdjusted, 5, "ESH24", "ESM24", "ESU24")
+//
 // # Getting Data
 //
 // Strategies retrieve market data through the universe rather than querying a
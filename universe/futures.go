@@ -0,0 +1,371 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package universe
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/portfolio"
+)
+
+// FuturesRollJustification is recorded on the orders ContinuousFutures.Roll
+// places.
+const FuturesRollJustification = "futures roll"
+
+// FuturesAdjustment selects how a continuous futures series removes the
+// price gap between consecutive contracts at each roll.
+type FuturesAdjustment int
+
+const (
+	// Unadjusted splices raw contract prices, leaving the roll gaps in
+	// the series.
+	Unadjusted FuturesAdjustment = iota
+
+	// BackAdjusted adds the price difference between the new and old
+	// contract at each roll to every earlier price, preserving point
+	// moves. Old prices can turn negative over long histories.
+	BackAdjusted
+
+	// RatioAdjusted multiplies every earlier price by the ratio of the new
+	// to the old contract's price at each roll, preserving percentage
+	// moves.
+	RatioAdjusted
+)
+
+// adjustedMetrics are the price metrics a roll adjustment applies to.
+var adjustedMetrics = []data.Metric{data.MetricOpen, data.MetricHigh, data.MetricLow, data.MetricClose, data.AdjClose}
+
+// compile-time check
+var _ Universe = (*ContinuousFutures)(nil)
+
+// ContinuousFutures is a single-asset universe that follows a chain of
+// futures contracts, holding each until rollDays weekdays before its
+// expiry and then moving to the next. Assets returns the active contract,
+// and Window stitches the contracts' histories into one series labeled
+// with the active contract, adjusted at each roll as configured. Roll
+// appends the orders that move held contracts into the active one.
+type ContinuousFutures struct {
+	contracts  []asset.Asset // sorted by expiry
+	rollDays   int
+	adjustment FuturesAdjustment
+	ds         DataSource
+}
+
+// NewContinuousFutures creates a continuous futures universe over the
+// contracts named by tickers, in any order. Each contract must carry an
+// expiry in its asset.Contract specification; contracts are ordered by
+// it once resolved.
+//
+// The universe has no data source until it is wired via
+// engine.ContinuousFutures(), which also resolves each ticker through the
+// engine's asset registry.
+func NewContinuousFutures(adjustment FuturesAdjustment, rollDays int, tickers ...string) *ContinuousFutures {
+	contracts := make([]asset.Asset, len(tickers))
+	for idx, ticker := range tickers {
+		contracts[idx] = asset.Asset{Ticker: ticker}
+	}
+
+	return &ContinuousFutures{
+		contracts:  contracts,
+		rollDays:   rollDays,
+		adjustment: adjustment,
+	}
+}
+
+// SetDataSource wires the universe to a data source.
+func (u *ContinuousFutures) SetDataSource(ds DataSource) { u.ds = ds }
+
+// Resolve replaces each bare ticker with a fully-resolved asset and orders
+// the contracts by expiry. Contracts without an expiry sort last.
+func (u *ContinuousFutures) Resolve(lookup func(ticker string) asset.Asset) {
+	for idx := range u.contracts {
+		u.contracts[idx] = lookup(u.contracts[idx].Ticker)
+	}
+
+	slices.SortStableFunc(u.contracts, func(left, right asset.Asset) int {
+		switch {
+		case left.Contract.Expiry.IsZero() && right.Contract.Expiry.IsZero():
+			return 0
+		case left.Contract.Expiry.IsZero():
+			return 1
+		case right.Contract.Expiry.IsZero():
+			return -1
+		default:
+			return left.Contract.Expiry.Compare(right.Contract.Expiry)
+		}
+	})
+}
+
+// Contracts returns the chain's contracts in expiry order.
+func (u *ContinuousFutures) Contracts() []asset.Asset {
+	return slices.Clone(u.contracts)
+}
+
+// RollDate returns the date trading moves out of contract: rollDays
+// weekdays before its expiry. Exchange holidays are not skipped. It is the
+// zero time for a contract without an expiry.
+func (u *ContinuousFutures) RollDate(contract asset.Asset) time.Time {
+	expiry := contract.Contract.Expiry
+	if expiry.IsZero() {
+		return time.Time{}
+	}
+
+	day := time.Date(expiry.Year(), expiry.Month(), expiry.Day(), 0, 0, 0, 0, expiry.Location())
+
+	for remaining := u.rollDays; remaining > 0; {
+		day = day.AddDate(0, 0, -1)
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			remaining--
+		}
+	}
+
+	return day
+}
+
+// activeIndex returns the index of the first contract whose roll date is
+// after asOf, or the last contract once every roll date has passed.
+func (u *ContinuousFutures) activeIndex(asOf time.Time) int {
+	for idx, contract := range u.contracts {
+		roll := u.RollDate(contract)
+		if roll.IsZero() || asOf.Before(roll) {
+			return idx
+		}
+	}
+
+	return len(u.contracts) - 1
+}
+
+// Assets returns the contract active as of asOf. Always a single-element
+// slice.
+func (u *ContinuousFutures) Assets(asOf time.Time) []asset.Asset {
+	if len(u.contracts) == 0 {
+		return nil
+	}
+
+	return []asset.Asset{u.contracts[u.activeIndex(asOf)]}
+}
+
+// continuousPiece is one contract's stretch of a continuous series.
+type continuousPiece struct {
+	contract asset.Asset
+	raw      *data.DataFrame // the contract's full fetched window
+	segment  *data.DataFrame // the stretch the contract was active
+}
+
+// Window fetches each contract over the part of the lookback window it was
+// active, adjusts earlier contracts' prices for the gap at each roll, and
+// stitches the pieces together chronologically. The returned DataFrame has
+// a single asset column labeled with the currently active contract, so
+// signals computed from it route orders to that contract. Each gap is
+// measured on the first bar of the newer contract, using both contracts'
+// closes on that bar.
+func (u *ContinuousFutures) Window(ctx context.Context, lookback portfolio.Period, metrics ...data.Metric) (*data.DataFrame, error) {
+	if u.ds == nil {
+		return nil, fmt.Errorf("universe has no data source; was it created via engine.ContinuousFutures()?")
+	}
+
+	if len(u.contracts) == 0 {
+		return nil, fmt.Errorf("continuous futures: no contracts")
+	}
+
+	now := u.ds.CurrentDate()
+	windowStart := lookback.Before(now)
+	activeIdx := u.activeIndex(now)
+	active := u.contracts[activeIdx]
+
+	fetchMetrics := metrics
+	if !slices.Contains(fetchMetrics, data.MetricClose) {
+		fetchMetrics = append(slices.Clone(metrics), data.MetricClose)
+	}
+
+	var pieces []continuousPiece
+
+	for idx := 0; idx <= activeIdx; idx++ {
+		contract := u.contracts[idx]
+
+		segStart := windowStart
+		if idx > 0 {
+			if prevRoll := u.RollDate(u.contracts[idx-1]); prevRoll.After(segStart) {
+				segStart = prevRoll
+			}
+		}
+
+		segEnd := now.Add(time.Nanosecond)
+		if idx < activeIdx {
+			if roll := u.RollDate(contract); roll.Before(segEnd) {
+				segEnd = roll
+			}
+		}
+
+		if !segStart.Before(segEnd) {
+			continue
+		}
+
+		raw, err := u.ds.Fetch(ctx, []asset.Asset{contract}, lookback, fetchMetrics)
+		if err != nil {
+			return nil, fmt.Errorf("continuous futures fetch %s: %w", contract.Ticker, err)
+		}
+
+		segment := raw.Between(segStart, segEnd.Add(-time.Nanosecond))
+		if segment.Len() == 0 {
+			continue
+		}
+
+		pieces = append(pieces, continuousPiece{contract: contract, raw: raw, segment: segment})
+	}
+
+	if len(pieces) == 0 {
+		return data.WithErr(fmt.Errorf("continuous futures %s: no data in window [%s, %s]",
+			active.Ticker, windowStart.Format(time.DateOnly), now.Format(time.DateOnly))), nil
+	}
+
+	// Walk the rolls newest first, accumulating the adjustment each
+	// earlier piece needs.
+	offset, factor := 0.0, 1.0
+	adjusted := make([]*data.DataFrame, len(pieces))
+
+	for idx := len(pieces) - 1; idx >= 0; idx-- {
+		if idx < len(pieces)-1 {
+			newer := pieces[idx+1]
+			rollBar := newer.segment.Times()[0]
+			newClose := newer.raw.ValueAt(newer.contract, data.MetricClose, rollBar)
+			oldClose := pieces[idx].raw.ValueAt(pieces[idx].contract, data.MetricClose, rollBar)
+
+			if !math.IsNaN(newClose) && !math.IsNaN(oldClose) && oldClose != 0 {
+				offset += newClose - oldClose
+				factor *= newClose / oldClose
+			}
+		}
+
+		piece, err := u.adjustPiece(pieces[idx], active, offset, factor)
+		if err != nil {
+			return nil, err
+		}
+
+		adjusted[idx] = piece
+	}
+
+	merged, err := data.MergeTimes(adjusted...)
+	if err != nil {
+		return nil, err
+	}
+
+	return merged.Metrics(metrics...), nil
+}
+
+// adjustPiece relabels a piece with the active contract and applies the
+// universe's roll adjustment to its price metrics.
+func (u *ContinuousFutures) adjustPiece(piece continuousPiece, active asset.Asset, offset, factor float64) (*data.DataFrame, error) {
+	metrics := piece.segment.MetricList()
+	cols := make([][]float64, len(metrics))
+
+	for metricIdx, metric := range metrics {
+		col := slices.Clone(piece.segment.Column(piece.contract, metric))
+
+		if slices.Contains(adjustedMetrics, metric) {
+			for row := range col {
+				switch u.adjustment {
+				case BackAdjusted:
+					col[row] += offset
+				case RatioAdjusted:
+					col[row] *= factor
+				}
+			}
+		}
+
+		cols[metricIdx] = col
+	}
+
+	df, err := data.NewDataFrame(piece.segment.Times(), []asset.Asset{active}, metrics, piece.segment.Frequency(), cols)
+	if err != nil {
+		return nil, fmt.Errorf("continuous futures relabel %s -> %s: %w", piece.contract.Ticker, active.Ticker, err)
+	}
+
+	return df, nil
+}
+
+// At returns a single-row DataFrame at the current simulation date for the
+// active contract. Prices are the contract's own and need no adjustment.
+func (u *ContinuousFutures) At(ctx context.Context, metrics ...data.Metric) (*data.DataFrame, error) {
+	if u.ds == nil {
+		return nil, fmt.Errorf("universe has no data source; was it created via engine.ContinuousFutures()?")
+	}
+
+	now := u.ds.CurrentDate()
+
+	return u.ds.FetchAt(ctx, u.Assets(now), now, metrics)
+}
+
+// CurrentDate returns the current simulation date from the data source, or
+// the zero time if no data source is set.
+func (u *ContinuousFutures) CurrentDate() time.Time {
+	if u.ds == nil {
+		return time.Time{}
+	}
+
+	return u.ds.CurrentDate()
+}
+
+// Roll appends orders to batch that move each position projected in a
+// contract of the chain other than the active one into the active
+// contract, keeping the number of contracts and the direction. Call it
+// from Compute on every frame: it adds nothing until a held contract
+// reaches its roll date.
+func (u *ContinuousFutures) Roll(ctx context.Context, batch *portfolio.Batch) error {
+	current := u.Assets(batch.Timestamp)
+	if len(current) == 0 {
+		return nil
+	}
+
+	active := current[0]
+	holdings := batch.ProjectedHoldings()
+
+	for held, qty := range holdings {
+		if qty == 0 || held.CompositeFigi == active.CompositeFigi || !u.inChain(held) {
+			continue
+		}
+
+		closeSide, openSide := portfolio.Sell, portfolio.Buy
+		if qty < 0 {
+			closeSide, openSide = portfolio.Buy, portfolio.Sell
+		}
+
+		contracts := math.Abs(qty)
+
+		if err := batch.Order(ctx, held, closeSide, contracts, portfolio.WithJustification(FuturesRollJustification)); err != nil {
+			return fmt.Errorf("roll out of %s: %w", held.Ticker, err)
+		}
+
+		if err := batch.Order(ctx, active, openSide, contracts, portfolio.WithJustification(FuturesRollJustification)); err != nil {
+			return fmt.Errorf("roll into %s: %w", active.Ticker, err)
+		}
+	}
+
+	return nil
+}
+
+// inChain reports whether ast is one of the chain's contracts.
+func (u *ContinuousFutures) inChain(ast asset.Asset) bool {
+	return slices.ContainsFunc(u.contracts, func(contract asset.Asset) bool {
+		return contract.CompositeFigi == ast.CompositeFigi
+	})
+}
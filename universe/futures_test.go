@@ -0,0 +1,158 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package universe_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pvbt/asset"
+	"github.com/penny-vault/pvbt/broker"
+	"github.com/penny-vault/pvbt/data"
+	"github.com/penny-vault/pvbt/portfolio"
+	"github.com/penny-vault/pvbt/universe"
+)
+
+// makeFuturesFrame builds a daily close series for a contract from start
+// through end, taking each day's value from price.
+func makeFuturesFrame(a asset.Asset, start, end time.Time, price func(time.Time) float64) *data.DataFrame {
+	var (
+		times []time.Time
+		col   []float64
+	)
+
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		times = append(times, day)
+		col = append(col, price(day))
+	}
+
+	df, err := data.NewDataFrame(times, []asset.Asset{a}, []data.Metric{data.MetricClose}, data.Daily, [][]float64{col})
+	if err != nil {
+		panic(err)
+	}
+
+	return df
+}
+
+var _ = Describe("ContinuousFutures", func() {
+	var (
+		esh   asset.Asset
+		esm   asset.Asset
+		ds    *spliceMockDataSource
+		march time.Time
+	)
+
+	BeforeEach(func() {
+		esh = asset.NewFuture("ESH24", asset.Contract{
+			Root: "ES", Multiplier: 50, Expiry: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+		})
+		esm = asset.NewFuture("ESM24", asset.Contract{
+			Root: "ES", Multiplier: 50, Expiry: time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC),
+		})
+		march = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+		start := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+
+		ds = &spliceMockDataSource{
+			currentDate: end,
+			frames: map[string]*data.DataFrame{
+				"ESH24": makeFuturesFrame(esh, start, end, func(day time.Time) float64 {
+					if day.Before(march) {
+						return 50
+					}
+
+					return 100
+				}),
+				"ESM24": makeFuturesFrame(esm, start, end, func(time.Time) float64 { return 110 }),
+			},
+		}
+	})
+
+	newUniverse := func(adjustment universe.FuturesAdjustment) *universe.ContinuousFutures {
+		// Listed out of order: Resolve sorts by expiry.
+		u := universe.NewContinuousFutures(adjustment, 2, "ESM24", "ESH24")
+		u.Resolve(func(ticker string) asset.Asset {
+			if ticker == "ESH24" {
+				return esh
+			}
+
+			return esm
+		})
+		u.SetDataSource(ds)
+
+		return u
+	}
+
+	It("rolls the given number of weekdays before expiry", func() {
+		u := newUniverse(universe.Unadjusted)
+
+		Expect(u.Contracts()).To(Equal([]asset.Asset{esh, esm}))
+		Expect(u.RollDate(esh)).To(Equal(time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC)))
+		Expect(u.Assets(time.Date(2024, 3, 12, 16, 0, 0, 0, time.UTC))).To(Equal([]asset.Asset{esh}))
+		Expect(u.Assets(time.Date(2024, 3, 13, 16, 0, 0, 0, time.UTC))).To(Equal([]asset.Asset{esm}))
+	})
+
+	DescribeTable("stitches contract histories under the active contract",
+		func(adjustment universe.FuturesAdjustment, early, late float64) {
+			df, err := newUniverse(adjustment).Window(context.Background(), portfolio.Months(3), data.MetricClose)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(df.AssetList()).To(Equal([]asset.Asset{esm}))
+
+			Expect(df.ValueAt(esm, data.MetricClose, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))).To(BeNumerically("~", early, 1e-9))
+			Expect(df.ValueAt(esm, data.MetricClose, time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC))).To(BeNumerically("~", late, 1e-9))
+			Expect(df.ValueAt(esm, data.MetricClose, time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC))).To(Equal(110.0))
+		},
+		Entry("unadjusted", universe.Unadjusted, 50.0, 100.0),
+		Entry("back-adjusted", universe.BackAdjusted, 60.0, 110.0),
+		Entry("ratio-adjusted", universe.RatioAdjusted, 55.0, 110.0),
+	)
+
+	It("rolls held contracts into the active one", func() {
+		u := newUniverse(universe.BackAdjusted)
+
+		acct := portfolio.New(portfolio.WithCash(100_000, march))
+		acct.Record(portfolio.Transaction{
+			Date: march, Asset: esh, Type: asset.SellTransaction, Qty: 3, Price: 100,
+		})
+
+		before := acct.NewBatch(time.Date(2024, 3, 12, 16, 0, 0, 0, time.UTC))
+		Expect(u.Roll(context.Background(), before)).To(Succeed())
+		Expect(before.Orders).To(BeEmpty())
+
+		batch := acct.NewBatch(time.Date(2024, 3, 13, 16, 0, 0, 0, time.UTC))
+		Expect(u.Roll(context.Background(), batch)).To(Succeed())
+		Expect(batch.Orders).To(HaveLen(2))
+
+		Expect(batch.Orders[0].Asset).To(Equal(esh))
+		Expect(batch.Orders[0].Side).To(Equal(broker.Buy))
+		Expect(batch.Orders[0].Qty).To(Equal(3.0))
+		Expect(batch.Orders[0].Justification).To(Equal(universe.FuturesRollJustification))
+
+		Expect(batch.Orders[1].Asset).To(Equal(esm))
+		Expect(batch.Orders[1].Side).To(Equal(broker.Sell))
+		Expect(batch.Orders[1].Qty).To(Equal(3.0))
+	})
+
+	It("errors without a data source", func() {
+		u := universe.NewContinuousFutures(universe.BackAdjusted, 2, "ESH24")
+		_, err := u.Window(context.Background(), portfolio.Months(3), data.MetricClose)
+		Expect(err).To(MatchError(ContainSubstring("no data source")))
+	})
+})